@@ -0,0 +1,136 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package actions holds the definitions of the actions that juju
+// provides itself, independently of any charm.
+package actions
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"github.com/juju/utils/exec"
+	"gopkg.in/juju/charm.v4"
+)
+
+// JujuRunActionName is the name of the predefined action used by
+// "juju run" to execute arbitrary commands on units and machines.
+const JujuRunActionName = "juju-run"
+
+// PredefinedActionsSpec holds the specs of the actions that can be
+// queued on any unit or machine, whether or not a charm defines them.
+var PredefinedActionsSpec = map[string]charm.ActionSpec{
+	JujuRunActionName: {
+		Description: "run the given commands",
+		Params: map[string]interface{}{
+			"type":        "object",
+			"title":       JujuRunActionName,
+			"description": "run the given commands",
+			"properties": map[string]interface{}{
+				"command": map[string]interface{}{
+					"type":        "string",
+					"description": "the commands to run",
+				},
+				"timeout": map[string]interface{}{
+					"type":        "number",
+					"description": "the timeout for the commands, in nanoseconds",
+				},
+			},
+			"required": []interface{}{"command"},
+		},
+	},
+}
+
+// JujuRunParams returns the parameters of a juju-run action that runs
+// the given commands, killing them if they take longer than timeout. A
+// zero timeout means the commands are never killed.
+func JujuRunParams(commands string, timeout time.Duration) map[string]interface{} {
+	return map[string]interface{}{
+		"command": commands,
+		"timeout": timeout.Nanoseconds(),
+	}
+}
+
+// ParseJujuRunParams extracts the commands and timeout from the
+// parameters of a juju-run action. Numeric parameters may arrive as any
+// of the types produced by the API and the database, so all of them
+// are accepted for the timeout.
+func ParseJujuRunParams(params map[string]interface{}) (string, time.Duration, error) {
+	commands, ok := params["command"].(string)
+	if !ok {
+		return "", 0, fmt.Errorf("command not specified")
+	}
+	var timeout time.Duration
+	switch value := params["timeout"].(type) {
+	case nil:
+	case int:
+		timeout = time.Duration(value)
+	case int64:
+		timeout = time.Duration(value)
+	case float64:
+		timeout = time.Duration(value)
+	default:
+		return "", 0, fmt.Errorf("invalid timeout %v", value)
+	}
+	return commands, timeout, nil
+}
+
+// JujuRunResults converts the response from the commands of a juju-run
+// action into the values recorded as the action's results. Output that
+// is not valid UTF-8 is stored base64 encoded.
+func JujuRunResults(response *exec.ExecResponse) map[string]string {
+	results := map[string]string{
+		"Code": strconv.Itoa(response.Code),
+	}
+	storeOutput(results, "Stdout", response.Stdout)
+	storeOutput(results, "Stderr", response.Stderr)
+	return results
+}
+
+func storeOutput(results map[string]string, key string, output []byte) {
+	if utf8.Valid(output) {
+		results[key] = string(output)
+		return
+	}
+	results[key] = base64.StdEncoding.EncodeToString(output)
+	results[key+"Encoding"] = "base64"
+}
+
+// ParseJujuRunResults converts the results of a completed juju-run
+// action back into the response from its commands.
+func ParseJujuRunResults(results map[string]interface{}) (*exec.ExecResponse, error) {
+	response := &exec.ExecResponse{}
+	if code, ok := results["Code"].(string); ok {
+		var err error
+		if response.Code, err = strconv.Atoi(code); err != nil {
+			return nil, fmt.Errorf("invalid return code %q", code)
+		}
+	}
+	var err error
+	if response.Stdout, err = loadOutput(results, "Stdout"); err != nil {
+		return nil, err
+	}
+	if response.Stderr, err = loadOutput(results, "Stderr"); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func loadOutput(results map[string]interface{}, key string) ([]byte, error) {
+	output, _ := results[key].(string)
+	switch encoding, _ := results[key+"Encoding"].(string); encoding {
+	case "":
+		return []byte(output), nil
+	case "base64":
+		decoded, err := base64.StdEncoding.DecodeString(output)
+		if err != nil {
+			return nil, fmt.Errorf("cannot decode %s: %v", key, err)
+		}
+		return decoded, nil
+	default:
+		return nil, fmt.Errorf("unknown encoding %q for %s", encoding, key)
+	}
+}
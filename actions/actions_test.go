@@ -0,0 +1,106 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package actions_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils/exec"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/actions"
+	"github.com/juju/juju/testing"
+)
+
+type ActionsSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&ActionsSuite{})
+
+func (s *ActionsSuite) TestJujuRunSpecValidatesParams(c *gc.C) {
+	spec, ok := actions.PredefinedActionsSpec[actions.JujuRunActionName]
+	c.Assert(ok, jc.IsTrue)
+
+	_, err := spec.ValidateParams(actions.JujuRunParams("hostname", time.Minute))
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = spec.ValidateParams(map[string]interface{}{"timeout": 10})
+	c.Assert(err, gc.NotNil)
+}
+
+func (s *ActionsSuite) TestParseJujuRunParams(c *gc.C) {
+	for i, test := range []struct {
+		params   map[string]interface{}
+		commands string
+		timeout  time.Duration
+		err      string
+	}{{
+		params:   actions.JujuRunParams("hostname", time.Minute),
+		commands: "hostname",
+		timeout:  time.Minute,
+	}, {
+		params:   map[string]interface{}{"command": "hostname"},
+		commands: "hostname",
+	}, {
+		params:   map[string]interface{}{"command": "hostname", "timeout": float64(time.Second)},
+		commands: "hostname",
+		timeout:  time.Second,
+	}, {
+		params:   map[string]interface{}{"command": "hostname", "timeout": int(time.Second)},
+		commands: "hostname",
+		timeout:  time.Second,
+	}, {
+		params: map[string]interface{}{"timeout": 10},
+		err:    "command not specified",
+	}, {
+		params: map[string]interface{}{"command": "hostname", "timeout": "10s"},
+		err:    "invalid timeout 10s",
+	}} {
+		c.Logf("test %d: %v", i, test.params)
+		commands, timeout, err := actions.ParseJujuRunParams(test.params)
+		if test.err != "" {
+			c.Check(err, gc.ErrorMatches, test.err)
+			continue
+		}
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(commands, gc.Equals, test.commands)
+		c.Check(timeout, gc.Equals, test.timeout)
+	}
+}
+
+func (s *ActionsSuite) TestJujuRunResultsRoundTrip(c *gc.C) {
+	response := &exec.ExecResponse{
+		Code:   42,
+		Stdout: []byte("hello\n"),
+		Stderr: []byte{0xff, 0xfe},
+	}
+	results := actions.JujuRunResults(response)
+	c.Assert(results, jc.DeepEquals, map[string]string{
+		"Code":           "42",
+		"Stdout":         "hello\n",
+		"Stderr":         "//4=",
+		"StderrEncoding": "base64",
+	})
+
+	output := make(map[string]interface{})
+	for key, value := range results {
+		output[key] = value
+	}
+	parsed, err := actions.ParseJujuRunResults(output)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(parsed, jc.DeepEquals, response)
+}
+
+func (s *ActionsSuite) TestParseJujuRunResultsErrors(c *gc.C) {
+	_, err := actions.ParseJujuRunResults(map[string]interface{}{"Code": "x"})
+	c.Assert(err, gc.ErrorMatches, `invalid return code "x"`)
+
+	_, err = actions.ParseJujuRunResults(map[string]interface{}{
+		"Stdout":         "foo",
+		"StdoutEncoding": "rot13",
+	})
+	c.Assert(err, gc.ErrorMatches, `unknown encoding "rot13" for Stdout`)
+}
@@ -61,6 +61,13 @@ const (
 	MongoOplogSize         = "MONGO_OPLOG_SIZE"
 	NumaCtlPreference      = "NUMA_CTL_PREFERENCE"
 	AllowsSecureConnection = "SECURE_STATESERVER_CONNECTION"
+
+	// The following keys hold the limits enforced by the API
+	// server of a state server; see apiserver.ServerConfig.
+	APIMaxConcurrentLogins   = "API_MAX_CONCURRENT_LOGINS"
+	APIMaxConnections        = "API_MAX_CONNECTIONS"
+	APIMaxConnectionRequests = "API_MAX_CONNECTION_REQUESTS"
+	APIRetryDelay            = "API_RETRY_DELAY"
 )
 
 // The Config interface is the sole way that the agent gets access to the
@@ -4,6 +4,8 @@
 package action
 
 import (
+	"time"
+
 	"github.com/juju/errors"
 	"gopkg.in/juju/charm.v4"
 
@@ -76,13 +78,39 @@ func (c *Client) ListCompleted(arg params.Entities) (params.ActionsByReceivers,
 	return results, err
 }
 
-// Cancel attempts to cancel a queued up Action from running.
-func (c *Client) Cancel(arg params.Actions) (params.ActionResults, error) {
+// Cancel attempts to cancel queued up Actions from running.
+func (c *Client) Cancel(arg params.Entities) (params.ActionResults, error) {
 	results := params.ActionResults{}
 	err := c.facade.FacadeCall("Cancel", arg, &results)
 	return results, err
 }
 
+// RunOnAllMachines enqueues the commands as a juju-run action on all
+// the machines, with the specified timeout. It requires version 1 of
+// the Action facade.
+func (c *Client) RunOnAllMachines(commands string, timeout time.Duration) ([]params.ActionResult, error) {
+	if c.BestAPIVersion() < 1 {
+		return nil, errors.NotSupportedf("running commands as actions")
+	}
+	var results params.ActionResults
+	args := params.RunParams{Commands: commands, Timeout: timeout}
+	err := c.facade.FacadeCall("RunOnAllMachines", args, &results)
+	return results.Results, err
+}
+
+// Run enqueues the commands specified as a juju-run action on the
+// machines and units identified through the ids provided in the
+// machines, services and units slices. It requires version 1 of the
+// Action facade.
+func (c *Client) Run(run params.RunParams) ([]params.ActionResult, error) {
+	if c.BestAPIVersion() < 1 {
+		return nil, errors.NotSupportedf("running commands as actions")
+	}
+	var results params.ActionResults
+	err := c.facade.FacadeCall("Run", run, &results)
+	return results.Results, err
+}
+
 // servicesCharmActions is a batched query for the charm.Actions for a slice
 // of services by Entity.
 func (c *Client) servicesCharmActions(arg params.Entities) (params.ServicesCharmActionsResults, error) {
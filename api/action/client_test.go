@@ -5,6 +5,7 @@ package action_test
 
 import (
 	"errors"
+	"time"
 
 	"github.com/juju/names"
 	jc "github.com/juju/testing/checkers"
@@ -114,6 +115,61 @@ func (s *actionSuite) TestServiceCharmActions(c *gc.C) {
 	}
 }
 
+func (s *actionSuite) TestRun(c *gc.C) {
+	args := params.RunParams{
+		Commands: "hostname",
+		Timeout:  time.Minute,
+		Machines: []string{"0"},
+		Units:    []string{"wordpress/0"},
+	}
+	expected := []params.ActionResult{{
+		Action: &params.Action{Receiver: "machine-0", Name: "juju-run"},
+		Status: "pending",
+	}}
+	cleanup := action.PatchClientFacadeCall(s.client,
+		func(req string, paramsIn interface{}, resp interface{}) error {
+			c.Check(req, gc.Equals, "Run")
+			c.Check(paramsIn, jc.DeepEquals, args)
+			resp.(*params.ActionResults).Results = expected
+			return nil
+		},
+	)
+	defer cleanup()
+	results, err := s.client.Run(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, expected)
+}
+
+func (s *actionSuite) TestRunOnAllMachines(c *gc.C) {
+	cleanup := action.PatchClientFacadeCall(s.client,
+		func(req string, paramsIn interface{}, resp interface{}) error {
+			c.Check(req, gc.Equals, "RunOnAllMachines")
+			c.Check(paramsIn, jc.DeepEquals, params.RunParams{Commands: "hostname", Timeout: time.Minute})
+			return errors.New("boom")
+		},
+	)
+	defer cleanup()
+	_, err := s.client.RunOnAllMachines("hostname", time.Minute)
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *actionSuite) TestCancel(c *gc.C) {
+	args := params.Entities{Entities: []params.Entity{{Tag: "action-00000000-0000-0000-0000-000000000000"}}}
+	expected := params.ActionResults{Results: []params.ActionResult{{Status: "cancelled"}}}
+	cleanup := action.PatchClientFacadeCall(s.client,
+		func(req string, paramsIn interface{}, resp interface{}) error {
+			c.Check(req, gc.Equals, "Cancel")
+			c.Check(paramsIn, jc.DeepEquals, args)
+			*(resp.(*params.ActionResults)) = expected
+			return nil
+		},
+	)
+	defer cleanup()
+	results, err := s.client.Cancel(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, expected)
+}
+
 // replace "ServicesCharmActions" facade call with required results and error
 // if desired
 func patchServiceCharmActions(c *gc.C, apiCli *action.Client, patchResults []params.ServiceCharmActionsResult, err string) func() {
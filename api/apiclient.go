@@ -8,6 +8,7 @@ import (
 	"crypto/x509"
 	"fmt"
 	"io"
+	"math/rand"
 	"strings"
 	"time"
 
@@ -69,6 +70,10 @@ type State struct {
 	// certPool holds the cert pool that is used to authenticate the tls
 	// connections to the API.
 	certPool *x509.CertPool
+
+	// batchCalls holds whether the API server accepts
+	// several requests sent in a single message.
+	batchCalls bool
 }
 
 // Info encapsulates information about a server holding juju state and
@@ -125,7 +130,59 @@ func DefaultDialOpts() DialOpts {
 	}
 }
 
+// maxBusyRetryDelay bounds the delay between attempts to connect to
+// an API server that has asked us to try again later but has not
+// suggested a delay.
+const maxBusyRetryDelay = time.Minute
+
+// Open connects to the API server described by info and logs in. If
+// the server turns us away because it is busy, Open backs off and
+// tries again until the dial timeout has elapsed, so that many agents
+// reconnecting at once do not all retry at the same moment.
 func Open(info *Info, opts DialOpts) (*State, error) {
+	deadline := time.Now().Add(opts.Timeout)
+	for attempt := 0; ; attempt++ {
+		st, err := open(info, opts)
+		if err == nil || !params.IsCodeTryAgain(err) {
+			return st, err
+		}
+		delay := busyRetryDelay(err, attempt, opts.RetryDelay)
+		if time.Now().Add(delay).After(deadline) {
+			return nil, err
+		}
+		logger.Infof("API server is busy, retrying in %v", delay)
+		time.Sleep(delay)
+	}
+}
+
+// busyRetryDelay returns how long to wait before the next attempt to
+// connect to an API server that replied with the given try-again
+// error. The server's suggested delay is used if it made one;
+// otherwise the base delay is doubled on each attempt. In both cases
+// the delay is jittered.
+func busyRetryDelay(err error, attempt int, base time.Duration) time.Duration {
+	delay, ok := params.RetryAfter(err)
+	if !ok {
+		if base <= 0 {
+			base = time.Second
+		}
+		delay = maxBusyRetryDelay
+		if attempt < 16 && base<<uint(attempt) < maxBusyRetryDelay {
+			delay = base << uint(attempt)
+		}
+	}
+	return jitter(delay)
+}
+
+// jitter returns a random duration between d/2 and 3d/2.
+var jitter = func(d time.Duration) time.Duration {
+	if d <= 0 {
+		return d
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d)))
+}
+
+func open(info *Info, opts DialOpts) (*State, error) {
 	if len(info.Addrs) == 0 {
 		return nil, fmt.Errorf("no API addresses to connect to")
 	}
@@ -175,6 +232,12 @@ func Open(info *Info, opts DialOpts) (*State, error) {
 	logger.Infof("connection established to %q", conn.RemoteAddr())
 
 	client := rpc.NewConn(jsoncodec.NewWebsocket(conn), nil)
+	// All requests made over this connection share a trace id,
+	// so that the logs of a single client operation can be
+	// followed through the API server into state.
+	traceId := rpc.NewTraceId()
+	logger.Debugf("using API trace id %s", traceId)
+	client.SetTraceId(traceId)
 	client.Start()
 	st := &State{
 		client:     client,
@@ -320,6 +383,20 @@ func (s *State) RPCClient() *rpc.Conn {
 	return s.client
 }
 
+// TraceId returns the trace id sent with every request made
+// over the connection.
+func (s *State) TraceId() string {
+	return s.client.TraceId()
+}
+
+// SetTraceId sets the trace id sent with all subsequent requests
+// made over the connection. Clients that make several connections
+// on behalf of a single operation can use it to give them all
+// the same trace id.
+func (s *State) SetTraceId(traceId string) {
+	s.client.SetTraceId(traceId)
+}
+
 // Addr returns the address used to connect to the API server.
 func (s *State) Addr() string {
 	return s.addr
@@ -8,6 +8,7 @@ import (
 	"io"
 	"net"
 	"strconv"
+	"time"
 
 	"github.com/juju/names"
 	jc "github.com/juju/testing/checkers"
@@ -177,3 +178,56 @@ func (*websocketSuite) TestSetUpWebsocketConfigHandlesEnvironUUID(c *gc.C) {
 	c.Check(conf.Location.String(), gc.Equals, "wss://0.1.2.3:1234/environment/dead-beef-1234/api")
 	c.Check(conf.Origin.String(), gc.Equals, "http://localhost/")
 }
+
+func (s *websocketSuite) TestBusyRetryDelayUsesServerHint(c *gc.C) {
+	s.PatchValue(api.Jitter, func(d time.Duration) time.Duration { return d })
+	err := &params.Error{
+		Code:       params.CodeTryAgain,
+		Message:    "try again",
+		RetryAfter: 7 * time.Second,
+	}
+	c.Assert(api.BusyRetryDelay(err, 0, time.Second), gc.Equals, 7*time.Second)
+	c.Assert(api.BusyRetryDelay(err, 5, time.Second), gc.Equals, 7*time.Second)
+}
+
+func (s *websocketSuite) TestBusyRetryDelayBacksOff(c *gc.C) {
+	s.PatchValue(api.Jitter, func(d time.Duration) time.Duration { return d })
+	err := &params.Error{
+		Code:    params.CodeTryAgain,
+		Message: "try again",
+	}
+	for i, expect := range []time.Duration{
+		2 * time.Second,
+		4 * time.Second,
+		8 * time.Second,
+		16 * time.Second,
+		32 * time.Second,
+		time.Minute,
+		time.Minute,
+	} {
+		c.Check(api.BusyRetryDelay(err, i, 2*time.Second), gc.Equals, expect)
+	}
+	c.Check(api.BusyRetryDelay(err, 100, 2*time.Second), gc.Equals, time.Minute)
+}
+
+func (s *websocketSuite) TestBusyRetryDelayDefaultBase(c *gc.C) {
+	s.PatchValue(api.Jitter, func(d time.Duration) time.Duration { return d })
+	err := &params.Error{
+		Code:    params.CodeTryAgain,
+		Message: "try again",
+	}
+	c.Assert(api.BusyRetryDelay(err, 0, 0), gc.Equals, time.Second)
+	c.Assert(api.BusyRetryDelay(err, 3, 0), gc.Equals, 8*time.Second)
+}
+
+func (s *websocketSuite) TestBusyRetryDelayIsJittered(c *gc.C) {
+	err := &params.Error{
+		Code:    params.CodeTryAgain,
+		Message: "try again",
+	}
+	for i := 0; i < 20; i++ {
+		delay := api.BusyRetryDelay(err, 0, time.Second)
+		c.Assert(delay >= 500*time.Millisecond, jc.IsTrue)
+		c.Assert(delay < 1500*time.Millisecond, jc.IsTrue)
+	}
+}
@@ -58,6 +58,14 @@ type AgentStatus struct {
 	Err     error
 }
 
+// WorkloadStatus holds the status of the software run by a service
+// or a unit, as reported by its charm.
+type WorkloadStatus struct {
+	Status params.Status
+	Info   string
+	Data   map[string]interface{}
+}
+
 // MachineStatus holds status info about a machine.
 type MachineStatus struct {
 	Agent AgentStatus
@@ -82,6 +90,8 @@ type MachineStatus struct {
 	Jobs          []multiwatcher.MachineJob
 	HasVote       bool
 	WantsVote     bool
+	Maintenance   bool
+	Tags          map[string]string
 }
 
 // ServiceStatus holds status info about a service.
@@ -95,11 +105,13 @@ type ServiceStatus struct {
 	CanUpgradeTo  string
 	SubordinateTo []string
 	Units         map[string]UnitStatus
+	Status        WorkloadStatus
 }
 
 // UnitStatus holds status info about a unit.
 type UnitStatus struct {
-	Agent AgentStatus
+	Agent    AgentStatus
+	Workload WorkloadStatus
 
 	// See the comment in MachineStatus regarding these fields.
 	AgentState     params.Status
@@ -225,6 +237,45 @@ func (c *Client) RetryProvisioning(machines ...names.MachineTag) ([]params.Error
 	return results.Results, err
 }
 
+// SetMachineMaintenance puts the given machines into maintenance mode,
+// or takes them out of it. If relocateUnits is true when putting the
+// machines into maintenance, the stateless units of services with more
+// than one unit are moved to other machines.
+func (c *Client) SetMachineMaintenance(maintenance, relocateUnits bool, machines ...names.MachineTag) ([]params.ErrorResult, error) {
+	p := params.SetMachineMaintenance{
+		Entities:      make([]params.Entity, len(machines)),
+		Maintenance:   maintenance,
+		RelocateUnits: relocateUnits,
+	}
+	for i, machine := range machines {
+		p.Entities[i] = params.Entity{Tag: machine.String()}
+	}
+	var results params.ErrorResults
+	err := c.facade.FacadeCall("SetMachineMaintenance", p, &results)
+	return results.Results, err
+}
+
+// HookHistory returns the hooks, actions and commands recorded for the
+// given unit, most recent first.
+func (c *Client) HookHistory(unit names.UnitTag) ([]params.HookRecord, error) {
+	p := params.Entities{
+		Entities: []params.Entity{{Tag: unit.String()}},
+	}
+	var results params.HookHistoryResults
+	err := c.facade.FacadeCall("HookHistory", p, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return result.Records, nil
+}
+
 // PublicAddress returns the public address of the specified
 // machine or unit. For a machine, target is an id not a tag.
 func (c *Client) PublicAddress(target string) (string, error) {
@@ -386,9 +437,13 @@ func (c *Client) ServiceDeploy(charmURL string, serviceName string, numUnits int
 }
 
 // ServiceUpdate updates the service attributes, including charm URL,
-// minimum number of units, settings and constraints.
+// minimum number of units, hook timeout, settings and constraints.
+// Setting a hook timeout requires version 1 of the Client facade.
 // TODO(frankban) deprecate redundant API calls that this supercedes.
 func (c *Client) ServiceUpdate(args params.ServiceUpdate) error {
+	if args.HookTimeout != nil && c.facade.BestAPIVersion() < 1 {
+		return errors.NotSupportedf("setting a service's hook timeout")
+	}
 	return c.facade.FacadeCall("ServiceUpdate", args, nil)
 }
 
@@ -402,6 +457,30 @@ func (c *Client) ServiceSetCharm(serviceName string, charmUrl string, force bool
 	return c.facade.FacadeCall("ServiceSetCharm", args, nil)
 }
 
+// ServiceSetCharmRollout sets the charm for a given service, allowing
+// its existing units to upgrade only batchSize at a time. If waitHealthy
+// is true, each batch must also become idle before the next upgrades.
+func (c *Client) ServiceSetCharmRollout(serviceName string, charmUrl string, force bool, batchSize int, waitHealthy bool) error {
+	args := params.ServiceSetCharmRollout{
+		ServiceName: serviceName,
+		CharmUrl:    charmUrl,
+		Force:       force,
+		BatchSize:   batchSize,
+		WaitHealthy: waitHealthy,
+	}
+	return c.facade.FacadeCall("ServiceSetCharmRollout", args, nil)
+}
+
+// ServiceRollbackCharm sets the charm for a given service back to the
+// one it was using before its most recent charm change.
+func (c *Client) ServiceRollbackCharm(serviceName string, force bool) error {
+	args := params.ServiceRollbackCharm{
+		ServiceName: serviceName,
+		Force:       force,
+	}
+	return c.facade.FacadeCall("ServiceRollbackCharm", args, nil)
+}
+
 // ServiceGetCharmURL returns the charm URL the given service is
 // running at present.
 func (c *Client) ServiceGetCharmURL(serviceName string) (*charm.URL, error) {
@@ -463,6 +542,15 @@ func (c *Client) SetServiceConstraints(service string, constraints constraints.V
 	return c.facade.FacadeCall("SetServiceConstraints", params, nil)
 }
 
+// SetMachineConstraints specifies the constraints for the given machine.
+func (c *Client) SetMachineConstraints(machineId string, constraints constraints.Value) error {
+	params := params.SetMachineConstraints{
+		MachineId:   machineId,
+		Constraints: constraints,
+	}
+	return c.facade.FacadeCall("SetMachineConstraints", params, nil)
+}
+
 // SetEnvironmentConstraints specifies the constraints for the environment.
 func (c *Client) SetEnvironmentConstraints(constraints constraints.Value) error {
 	params := params.SetConstraints{
@@ -515,14 +603,16 @@ func (c *Client) EnvironmentUUID() string {
 	return tag.Id()
 }
 
-// ShareEnvironment allows the given users access to the environment.
-func (c *Client) ShareEnvironment(users []names.UserTag) error {
+// ShareEnvironment allows the given users the
+// given level of access to the environment.
+func (c *Client) ShareEnvironment(users []names.UserTag, access params.EnvironAccess) error {
 	var args params.ModifyEnvironUsers
 	for _, user := range users {
 		if &user != nil {
 			args.Changes = append(args.Changes, params.ModifyEnvironUser{
 				UserTag: user.String(),
 				Action:  params.AddEnvUser,
+				Access:  access,
 			})
 		}
 	}
@@ -555,6 +645,56 @@ func (c *Client) UnshareEnvironment(users []names.UserTag) error {
 	return result.Combine()
 }
 
+// SetEnvironmentAccess changes the level of access the given users,
+// who must already have access to the environment, have to it.
+func (c *Client) SetEnvironmentAccess(users []names.UserTag, access params.EnvironAccess) error {
+	var args params.ModifyEnvironUsers
+	for _, user := range users {
+		args.Changes = append(args.Changes, params.ModifyEnvironUser{
+			UserTag: user.String(),
+			Action:  params.SetEnvUserAccess,
+			Access:  access,
+		})
+	}
+
+	var result params.ErrorResults
+	err := c.facade.FacadeCall("ShareEnvironment", args, &result)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return result.Combine()
+}
+
+// ShareEnvironmentGroups allows the members of the given groups,
+// as defined by the environment's external identity service, the
+// given level of access to the environment.
+func (c *Client) ShareEnvironmentGroups(groups []string, access params.EnvironAccess) error {
+	return c.modifyEnvironGroups(groups, params.AddEnvUser, access)
+}
+
+// UnshareEnvironmentGroups removes access to the environment
+// for the members of the given groups.
+func (c *Client) UnshareEnvironmentGroups(groups []string) error {
+	return c.modifyEnvironGroups(groups, params.RemoveEnvUser, "")
+}
+
+func (c *Client) modifyEnvironGroups(groups []string, action params.EnvironAction, access params.EnvironAccess) error {
+	var args params.ModifyEnvironGroups
+	for _, group := range groups {
+		args.Changes = append(args.Changes, params.ModifyEnvironGroup{
+			Group:  group,
+			Action: action,
+			Access: access,
+		})
+	}
+	var result params.ErrorResults
+	err := c.facade.FacadeCall("ShareEnvironmentGroups", args, &result)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return result.Combine()
+}
+
 // WatchAll holds the id of the newly-created AllWatcher.
 type WatchAll struct {
 	AllWatcherId string
@@ -647,6 +787,9 @@ func (c *Client) FindTools(
 
 // RunOnAllMachines runs the command on all the machines with the specified
 // timeout.
+//
+// The commands are run over SSH from the API server; the action client's
+// RunOnAllMachines should be used instead.
 func (c *Client) RunOnAllMachines(commands string, timeout time.Duration) ([]params.RunResult, error) {
 	var results params.RunResults
 	args := params.RunParams{Commands: commands, Timeout: timeout}
@@ -656,6 +799,9 @@ func (c *Client) RunOnAllMachines(commands string, timeout time.Duration) ([]par
 
 // Run the Commands specified on the machines identified through the ids
 // provided in the machines, services and units slices.
+//
+// The commands are run over SSH from the API server; the action client's
+// Run should be used instead.
 func (c *Client) Run(run params.RunParams) ([]params.RunResult, error) {
 	var results params.RunResults
 	err := c.facade.FacadeCall("Run", run, &results)
@@ -14,6 +14,7 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 
 	"code.google.com/p/go.net/websocket"
 	"github.com/juju/errors"
@@ -134,7 +135,7 @@ func (s *clientSuite) TestShareEnvironmentExistingUser(c *gc.C) {
 	)
 	defer cleanup()
 
-	err := client.ShareEnvironment([]names.UserTag{user.UserTag()})
+	err := client.ShareEnvironment([]names.UserTag{user.UserTag()}, params.AdminEnvironAccess)
 	c.Assert(err, gc.ErrorMatches, "failed to create environment user: env user already exists")
 }
 
@@ -169,14 +170,14 @@ func (s *clientSuite) TestShareEnvironmentThreeUsers(c *gc.C) {
 	)
 	defer cleanup()
 
-	err := client.ShareEnvironment([]names.UserTag{existingUser.UserTag(), localUser.UserTag(), newUserTag})
+	err := client.ShareEnvironment([]names.UserTag{existingUser.UserTag(), localUser.UserTag(), newUserTag}, params.AdminEnvironAccess)
 	c.Assert(err, gc.ErrorMatches, `existing user`)
 }
 
 func (s *clientSuite) TestShareEnvironmentRealAPIServer(c *gc.C) {
 	client := s.APIState.Client()
 	user := names.NewUserTag("foo@ubuntuone")
-	err := client.ShareEnvironment([]names.UserTag{user})
+	err := client.ShareEnvironment([]names.UserTag{user}, params.ReadEnvironAccess)
 	c.Assert(err, jc.ErrorIsNil)
 
 	envUser, err := s.State.EnvironmentUser(user)
@@ -184,12 +185,13 @@ func (s *clientSuite) TestShareEnvironmentRealAPIServer(c *gc.C) {
 	c.Assert(envUser.UserName(), gc.Equals, user.Username())
 	c.Assert(envUser.CreatedBy(), gc.Equals, s.AdminUserTag(c).Username())
 	c.Assert(envUser.LastConnection(), gc.IsNil)
+	c.Assert(envUser.Access(), gc.Equals, state.EnvironReadAccess)
 }
 
 func (s *clientSuite) TestUnshareEnvironmentRealAPIServer(c *gc.C) {
 	client := s.APIState.Client()
 	user := names.NewUserTag("foo@ubuntuone")
-	err := client.ShareEnvironment([]names.UserTag{user})
+	err := client.ShareEnvironment([]names.UserTag{user}, params.AdminEnvironAccess)
 	c.Assert(err, jc.ErrorIsNil)
 
 	envUser, err := s.State.EnvironmentUser(user)
@@ -203,6 +205,83 @@ func (s *clientSuite) TestUnshareEnvironmentRealAPIServer(c *gc.C) {
 	c.Assert(errors.IsNotFound(err), jc.IsTrue)
 }
 
+func (s *clientSuite) TestShareEnvironmentGroups(c *gc.C) {
+	client := s.APIState.Client()
+	var called bool
+	cleanup := api.PatchClientFacadeCall(client,
+		func(request string, paramsIn interface{}, response interface{}) error {
+			called = true
+			c.Assert(request, gc.Equals, "ShareEnvironmentGroups")
+			c.Assert(paramsIn, jc.DeepEquals, params.ModifyEnvironGroups{
+				Changes: []params.ModifyEnvironGroup{{
+					Group:  "devops",
+					Action: params.AddEnvUser,
+					Access: params.WriteEnvironAccess,
+				}, {
+					Group:  "dba",
+					Action: params.AddEnvUser,
+					Access: params.WriteEnvironAccess,
+				}},
+			})
+			result, ok := response.(*params.ErrorResults)
+			c.Assert(ok, jc.IsTrue)
+			err := &params.Error{Message: "environment group already exists"}
+			*result = params.ErrorResults{Results: []params.ErrorResult{{Error: nil}, {Error: err}}}
+			return nil
+		},
+	)
+	defer cleanup()
+
+	err := client.ShareEnvironmentGroups([]string{"devops", "dba"}, params.WriteEnvironAccess)
+	c.Assert(err, gc.ErrorMatches, "environment group already exists")
+	c.Assert(called, jc.IsTrue)
+}
+
+func (s *clientSuite) TestServiceUpdateHookTimeoutOlderServer(c *gc.C) {
+	client := s.APIState.Client()
+	var called bool
+	// The patched facade caller reports version 0 of the Client
+	// facade, which ignores hook timeouts.
+	cleanup := api.PatchClientFacadeCall(client,
+		func(request string, paramsIn interface{}, response interface{}) error {
+			called = true
+			return nil
+		},
+	)
+	defer cleanup()
+
+	timeout := 10 * time.Minute
+	err := client.ServiceUpdate(params.ServiceUpdate{
+		ServiceName: "wordpress",
+		HookTimeout: &timeout,
+	})
+	c.Assert(err, gc.ErrorMatches, "setting a service's hook timeout not supported")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	c.Assert(called, jc.IsFalse)
+
+	// Other updates are still sent.
+	err = client.ServiceUpdate(params.ServiceUpdate{ServiceName: "wordpress"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+}
+
+func (s *clientSuite) TestShareEnvironmentGroupsRealAPIServer(c *gc.C) {
+	client := s.APIState.Client()
+	err := client.ShareEnvironmentGroups([]string{"devops"}, params.ReadEnvironAccess)
+	c.Assert(err, jc.ErrorIsNil)
+
+	envGroup, err := s.State.EnvironmentGroup("devops")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(envGroup.CreatedBy(), gc.Equals, s.AdminUserTag(c).Username())
+	c.Assert(envGroup.Access(), gc.Equals, state.EnvironReadAccess)
+
+	err = client.UnshareEnvironmentGroups([]string{"devops"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.State.EnvironmentGroup("devops")
+	c.Assert(errors.IsNotFound(err), jc.IsTrue)
+}
+
 func (s *clientSuite) TestWatchDebugLogConnected(c *gc.C) {
 	// Shows both the unmarshalling of a real error, and
 	// that the api server is connected.
@@ -16,6 +16,8 @@ var (
 	BestVersion         = bestVersion
 	FacadeVersions      = &facadeVersions
 	NewHTTPClient       = &newHTTPClient
+	BusyRetryDelay      = busyRetryDelay
+	Jitter              = &jitter
 )
 
 // SetServerRoot allows changing the URL to the internal API server
@@ -89,3 +91,15 @@ func (f *resultCaller) BestAPIVersion() int {
 func (f *resultCaller) RawAPICaller() base.APICaller {
 	return nil
 }
+
+// BatchCalls reports whether the API connection
+// will send pipelined calls in a single message.
+func BatchCalls(st *State) bool {
+	return st.batchCalls
+}
+
+// SetBatchCalls sets whether the API connection
+// will send pipelined calls in a single message.
+func SetBatchCalls(st *State, batchCalls bool) {
+	st.batchCalls = batchCalls
+}
@@ -11,14 +11,14 @@ package api
 // New facades should start at 1.
 // Facades that existed before versioning start at 0.
 var facadeVersions = map[string]int{
-	"Action":               0,
+	"Action":               1,
 	"Agent":                1,
 	"AllWatcher":           0,
 	"Annotations":          1,
 	"Backups":              0,
 	"Charms":               1,
 	"CharmRevisionUpdater": 0,
-	"Client":               0,
+	"Client":               1,
 	"Deployer":             0,
 	"DiskFormatter":        1,
 	"DiskManager":          1,
@@ -31,6 +31,7 @@ var facadeVersions = map[string]int{
 	"KeyUpdater":           0,
 	"LeadershipService":    1,
 	"Logger":               0,
+	"MachineActions":       1,
 	"Machiner":             0,
 	"MetricsManager":       0,
 	"Networker":            0,
@@ -39,6 +40,7 @@ var facadeVersions = map[string]int{
 	"Provisioner":          0,
 	"Reboot":               1,
 	"RelationUnitsWatcher": 0,
+	"RPCMetrics":           1,
 	"Rsyslog":              0,
 	"Service":              1,
 	"Storage":              1,
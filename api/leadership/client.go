@@ -44,7 +44,13 @@ func (c *client) ClaimLeadership(serviceId, unitId string) (time.Duration, error
 
 	// We should have our 1 result. If not, we rightfully panic.
 	result := results.Results[0]
-	return time.Duration(result.ClaimDurationInSec) * time.Second, result.Error
+	if result.Error != nil {
+		if params.IsCodeLeadershipClaimDenied(result.Error) {
+			return 0, LeadershipClaimDeniedErr
+		}
+		return 0, result.Error
+	}
+	return time.Duration(result.ClaimDurationInSec) * time.Second, nil
 }
 
 // ReleaseLeadership implements LeadershipManager.
@@ -55,7 +61,10 @@ func (c *client) ReleaseLeadership(serviceId, unitId string) error {
 	}
 
 	// We should have our 1 result. If not, we rightfully panic.
-	return results.Results[0].Error
+	if err := results.Results[0].Error; err != nil {
+		return err
+	}
+	return nil
 }
 
 // BlockUntilLeadershipReleased implements LeadershipManager.
@@ -113,6 +113,27 @@ func (s *clientSuite) TestClaimLeadershipErrorTranslation(c *gc.C) {
 	c.Check(err, gc.ErrorMatches, "error making a leadership claim: "+errMsg)
 }
 
+func (s *clientSuite) TestClaimLeadershipDeniedTranslation(c *gc.C) {
+
+	stub := &stubFacade{
+		FacadeCallFn: func(name string, parameters, response interface{}) error {
+			typedR, ok := response.(*params.ClaimLeadershipBulkResults)
+			c.Assert(ok, gc.Equals, true)
+			typedR.Results = []params.ClaimLeadershipResults{params.ClaimLeadershipResults{
+				Error: &params.Error{
+					Message: "leadership claim denied",
+					Code:    params.CodeLeadershipClaimDenied,
+				},
+			}}
+			return nil
+		},
+	}
+
+	client := NewClient(stub, stub)
+	_, err := client.ClaimLeadership(StubServiceNm, StubUnitNm)
+	c.Check(err, gc.Equals, LeadershipClaimDeniedErr)
+}
+
 func (s *clientSuite) TestReleaseLeadershipTranslation(c *gc.C) {
 
 	numStubCalls := 0
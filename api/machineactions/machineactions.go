@@ -0,0 +1,128 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package machineactions provides the client side of the API used by
+// machine agents to run the actions queued for their machines.
+package machineactions
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/api/watcher"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Action represents a single queued action, by name and parameters.
+type Action struct {
+	name   string
+	params map[string]interface{}
+}
+
+// NewAction returns an Action with the given name and parameters.
+func NewAction(name string, params map[string]interface{}) *Action {
+	return &Action{name: name, params: params}
+}
+
+// Name returns the name of the action.
+func (a *Action) Name() string {
+	return a.name
+}
+
+// Params returns the parameters of the action.
+func (a *Action) Params() map[string]interface{} {
+	return a.params
+}
+
+// State provides access to a machine actions worker's view of the state.
+type State struct {
+	machineTag names.MachineTag
+	facade     base.FacadeCaller
+}
+
+// NewState returns a version of the state that provides functionality
+// required by the machine actions worker.
+func NewState(caller base.APICaller, machineTag names.MachineTag) *State {
+	return &State{
+		facade:     base.NewFacadeCaller(caller, "MachineActions"),
+		machineTag: machineTag,
+	}
+}
+
+// WatchActionNotifications returns a StringsWatcher that reports the
+// ids of the actions queued for the machine.
+func (st *State) WatchActionNotifications() (watcher.StringsWatcher, error) {
+	var results params.StringsWatchResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: st.machineTag.String()}},
+	}
+	err := st.facade.FacadeCall("WatchActionNotifications", args, &results)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	w := watcher.NewStringsWatcher(st.facade.RawAPICaller(), result)
+	return w, nil
+}
+
+// Action returns the pending action with the given tag.
+func (st *State) Action(tag names.ActionTag) (*Action, error) {
+	var results params.ActionsQueryResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: tag.String()}},
+	}
+	err := st.facade.FacadeCall("Actions", args, &results)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &Action{
+		name:   result.Action.Action.Name,
+		params: result.Action.Action.Parameters,
+	}, nil
+}
+
+// ActionBegin marks an action as running.
+func (st *State) ActionBegin(tag names.ActionTag) error {
+	var results params.ErrorResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: tag.String()}},
+	}
+	err := st.facade.FacadeCall("BeginActions", args, &results)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
+// ActionFinish records the status, results and message of a finished
+// action.
+func (st *State) ActionFinish(tag names.ActionTag, status string, results map[string]interface{}, message string) error {
+	var outcome params.ErrorResults
+	args := params.ActionExecutionResults{
+		Results: []params.ActionExecutionResult{{
+			ActionTag: tag.String(),
+			Status:    status,
+			Results:   results,
+			Message:   message,
+		}},
+	}
+	err := st.facade.FacadeCall("FinishActions", args, &outcome)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return outcome.OneError()
+}
@@ -0,0 +1,104 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machineactions_test
+
+import (
+	stdtesting "testing"
+	"time"
+
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/actions"
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/api/machineactions"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
+	coretesting "github.com/juju/juju/testing"
+)
+
+func TestAll(t *stdtesting.T) {
+	coretesting.MgoTestPackage(t)
+}
+
+type machineActionsSuite struct {
+	testing.JujuConnSuite
+
+	machine        *state.Machine
+	st             *api.State
+	machineActions *machineactions.State
+}
+
+var _ = gc.Suite(&machineActionsSuite{})
+
+func (s *machineActionsSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	var err error
+	s.st, s.machine = s.OpenAPIAsNewMachine(c)
+	s.machineActions, err = s.st.MachineActions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.machineActions, gc.NotNil)
+}
+
+func (s *machineActionsSuite) addAction(c *gc.C) *state.Action {
+	action, err := s.machine.AddAction(actions.JujuRunActionName, actions.JujuRunParams("hostname", time.Minute))
+	c.Assert(err, jc.ErrorIsNil)
+	return action
+}
+
+func (s *machineActionsSuite) TestWatchActionNotifications(c *gc.C) {
+	first := s.addAction(c)
+
+	w, err := s.machineActions.WatchActionNotifications()
+	c.Assert(err, jc.ErrorIsNil)
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewStringsWatcherC(c, s.BackingState, w)
+	wc.AssertChange(first.Id())
+	wc.AssertNoChange()
+
+	second := s.addAction(c)
+	wc.AssertChange(second.Id())
+	wc.AssertNoChange()
+}
+
+func (s *machineActionsSuite) TestActionLifecycle(c *gc.C) {
+	queued := s.addAction(c)
+	tag := names.NewActionTag(queued.Id())
+
+	action, err := s.machineActions.Action(tag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(action.Name(), gc.Equals, actions.JujuRunActionName)
+	commands, timeout, err := actions.ParseJujuRunParams(action.Params())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(commands, gc.Equals, "hostname")
+	c.Assert(timeout, gc.Equals, time.Minute)
+
+	err = s.machineActions.ActionBegin(tag)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.machineActions.Action(tag)
+	c.Assert(err, gc.ErrorMatches, "action no longer available")
+
+	err = s.machineActions.ActionFinish(tag, params.ActionCompleted, map[string]interface{}{"Code": "0"}, "")
+	c.Assert(err, jc.ErrorIsNil)
+	completed, err := s.machine.CompletedActions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(completed, gc.HasLen, 1)
+	output, _ := completed[0].Results()
+	c.Assert(output, jc.DeepEquals, map[string]interface{}{"Code": "0"})
+}
+
+func (s *machineActionsSuite) TestActionBeginError(c *gc.C) {
+	machineactions.PatchFacadeCall(s, s.machineActions, func(request string, p, resp interface{}) error {
+		c.Check(request, gc.Equals, "BeginActions")
+		if resp, ok := resp.(*params.ErrorResults); ok {
+			resp.Results = []params.ErrorResult{{Error: &params.Error{Message: "boom"}}}
+		}
+		return nil
+	})
+	err := s.machineActions.ActionBegin(names.NewActionTag("00000000-0000-0000-0000-000000000000"))
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
@@ -0,0 +1,116 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package api
+
+import (
+	"sync"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/rpc"
+)
+
+// Pipeline collects independent API calls so that they can be sent
+// to the API server together, without waiting for each call to
+// complete before making the next. When the server supports it, the
+// calls are sent in a single message; the replies are delivered as
+// the server completes each call.
+//
+// A Pipeline may be used from multiple goroutines.
+type Pipeline struct {
+	st *State
+
+	mu     sync.Mutex
+	queued []*rpc.Call
+	calls  []*PendingCall
+}
+
+// PendingCall represents a call added to a Pipeline.
+type PendingCall struct {
+	pipeline *Pipeline
+	done     chan *rpc.Call
+	once     sync.Once
+	err      error
+}
+
+// NewPipeline returns a new Pipeline that makes calls
+// on the given API connection.
+func (s *State) NewPipeline() *Pipeline {
+	return &Pipeline{st: s}
+}
+
+// APICall adds a call to the pipeline with the same arguments as
+// State.APICall. The call is not sent until Flush or Wait is called
+// on the pipeline or on the returned PendingCall; the response value
+// must not be used until the call has completed.
+func (p *Pipeline) APICall(facade string, version int, id, method string, args, response interface{}) *PendingCall {
+	call := &rpc.Call{
+		Request: rpc.Request{
+			Type:    facade,
+			Version: version,
+			Id:      id,
+			Action:  method,
+		},
+		Params:   args,
+		Response: response,
+		Done:     make(chan *rpc.Call, 1),
+	}
+	pending := &PendingCall{
+		pipeline: p,
+		done:     call.Done,
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.queued = append(p.queued, call)
+	p.calls = append(p.calls, pending)
+	return pending
+}
+
+// Flush sends all the calls added to the pipeline since it was
+// last flushed. It does not wait for them to complete.
+func (p *Pipeline) Flush() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.queued) == 0 {
+		return
+	}
+	if p.st.batchCalls {
+		p.st.client.GoBatch(p.queued)
+	} else {
+		// The server will not understand batched requests,
+		// so send the requests individually. They are still
+		// pipelined because we don't wait for the replies.
+		for _, call := range p.queued {
+			p.st.client.Go(call.Request, call.Params, call.Response, call.Done)
+		}
+	}
+	p.queued = nil
+}
+
+// Wait flushes the pipeline and waits for all the calls made on it
+// to complete. It returns the error from the first call, in the order
+// the calls were added, that failed.
+func (p *Pipeline) Wait() error {
+	p.Flush()
+	p.mu.Lock()
+	calls := p.calls
+	p.mu.Unlock()
+	var firstErr error
+	for _, call := range calls {
+		if err := call.Wait(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// Wait flushes the call's pipeline and waits for the call to
+// complete, returning its error as State.APICall would.
+func (c *PendingCall) Wait() error {
+	c.pipeline.Flush()
+	c.once.Do(func() {
+		call := <-c.done
+		c.err = params.ClientError(call.Error)
+	})
+	return c.err
+}
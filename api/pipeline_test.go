@@ -0,0 +1,72 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package api_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/apiserver/params"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/version"
+)
+
+type pipelineSuite struct {
+	jujutesting.JujuConnSuite
+}
+
+var _ = gc.Suite(&pipelineSuite{})
+
+func (s *pipelineSuite) TestLoginReportsBatchCalls(c *gc.C) {
+	c.Assert(api.BatchCalls(s.APIState), jc.IsTrue)
+}
+
+func (s *pipelineSuite) TestPipeline(c *gc.C) {
+	s.assertPipeline(c, true)
+}
+
+func (s *pipelineSuite) TestPipelineWithoutBatchCalls(c *gc.C) {
+	s.assertPipeline(c, false)
+}
+
+func (s *pipelineSuite) assertPipeline(c *gc.C, batchCalls bool) {
+	api.SetBatchCalls(s.APIState, batchCalls)
+	clientVersion := s.APIState.BestFacadeVersion("Client")
+
+	p := s.APIState.NewPipeline()
+	var versionResult params.AgentVersionResult
+	versionCall := p.APICall("Client", clientVersion, "", "AgentVersion", nil, &versionResult)
+	var badCallResult params.ErrorResult
+	badCall := p.APICall("Client", clientVersion, "", "NoSuchMethod", nil, &badCallResult)
+	var configResult params.EnvironmentGetResults
+	configCall := p.APICall("Client", clientVersion, "", "EnvironmentGet", nil, &configResult)
+
+	err := p.Wait()
+	c.Assert(err, jc.Satisfies, params.IsCodeNotImplemented)
+
+	c.Assert(versionCall.Wait(), jc.ErrorIsNil)
+	c.Assert(versionResult.Version, gc.Equals, version.Current.Number)
+	c.Assert(badCall.Wait(), jc.Satisfies, params.IsCodeNotImplemented)
+	c.Assert(configCall.Wait(), jc.ErrorIsNil)
+	c.Assert(configResult.Config["name"], gc.Equals, "dummyenv")
+}
+
+func (s *pipelineSuite) TestPendingCallWaitFlushes(c *gc.C) {
+	p := s.APIState.NewPipeline()
+	var result params.AgentVersionResult
+	call := p.APICall("Client", s.APIState.BestFacadeVersion("Client"), "", "AgentVersion", nil, &result)
+	c.Assert(call.Wait(), jc.ErrorIsNil)
+	c.Assert(result.Version, gc.Equals, version.Current.Number)
+
+	// Waiting again returns the same result.
+	c.Assert(call.Wait(), jc.ErrorIsNil)
+	c.Assert(p.Wait(), jc.ErrorIsNil)
+}
+
+func (s *pipelineSuite) TestEmptyPipeline(c *gc.C) {
+	p := s.APIState.NewPipeline()
+	p.Flush()
+	c.Assert(p.Wait(), jc.ErrorIsNil)
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/juju/juju/api/watcher"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/storage"
 )
@@ -155,6 +156,48 @@ func (m *Machine) Series() (string, error) {
 	return result.Result, nil
 }
 
+// Constraints returns the exact constraints that apply to the machine.
+func (m *Machine) Constraints() (constraints.Value, error) {
+	var results params.ConstraintsResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: m.tag.String()}},
+	}
+	err := m.st.facade.FacadeCall("Constraints", args, &results)
+	if err != nil {
+		return constraints.Value{}, err
+	}
+	if len(results.Results) != 1 {
+		return constraints.Value{}, fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return constraints.Value{}, result.Error
+	}
+	return result.Constraints, nil
+}
+
+// WatchConstraints returns a NotifyWatcher that notifies of changes
+// to the machine's constraints.
+func (m *Machine) WatchConstraints() (watcher.NotifyWatcher, error) {
+	var results params.NotifyWatchResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: m.tag.String()}},
+	}
+	err := m.st.facade.FacadeCall("WatchConstraints", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != 1 {
+		return nil, fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	w := watcher.NewNotifyWatcher(m.st.facade.RawAPICaller(), result)
+	return w, nil
+}
+
 // DistributionGroup returns a slice of instance.Ids
 // that belong to the same distribution group as this
 // Machine. The provisioner may use this information
@@ -353,6 +353,42 @@ func (s *provisionerSuite) TestSeries(c *gc.C) {
 	c.Assert(series, gc.Equals, "quantal")
 }
 
+func (s *provisionerSuite) TestConstraintsAndWatchConstraints(c *gc.C) {
+	template := state.MachineTemplate{
+		Series:      "quantal",
+		Jobs:        []state.MachineJob{state.JobHostUnits},
+		Constraints: constraints.MustParse("mem=1G"),
+	}
+	container, err := s.State.AddMachineInsideMachine(template, s.machine.Id(), instance.LXC)
+	c.Assert(err, jc.ErrorIsNil)
+	apiMachine, err := s.provisioner.Machine(container.Tag().(names.MachineTag))
+	c.Assert(err, jc.ErrorIsNil)
+
+	cons, err := apiMachine.Constraints()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cons, gc.DeepEquals, template.Constraints)
+
+	w, err := apiMachine.WatchConstraints()
+	c.Assert(err, jc.ErrorIsNil)
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewNotifyWatcherC(c, s.BackingState, w)
+
+	// Initial event.
+	wc.AssertOneChange()
+
+	// Change the constraints and check they are reported.
+	newCons := constraints.MustParse("mem=2G")
+	err = container.SetConstraints(newCons)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+	cons, err = apiMachine.Constraints()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cons, gc.DeepEquals, newCons)
+
+	statetesting.AssertStop(c, w)
+	wc.AssertClosed()
+}
+
 func (s *provisionerSuite) TestDistributionGroup(c *gc.C) {
 	apiMachine, err := s.provisioner.Machine(s.machine.Tag().(names.MachineTag))
 	c.Assert(err, jc.ErrorIsNil)
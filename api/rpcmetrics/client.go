@@ -0,0 +1,39 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package rpcmetrics
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Client allows access to the API server's call statistics.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient creates a new client for accessing the RPCMetrics API.
+func NewClient(st base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(st, "RPCMetrics")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// CallMetrics returns the per-method call statistics
+// recorded by the API server.
+func (c *Client) CallMetrics() (params.RPCMetricsResult, error) {
+	var result params.RPCMetricsResult
+	if err := c.facade.FacadeCall("CallMetrics", nil, &result); err != nil {
+		return params.RPCMetricsResult{}, errors.Trace(err)
+	}
+	return result, nil
+}
+
+// ResetCallMetrics discards the call statistics
+// recorded by the API server.
+func (c *Client) ResetCallMetrics() error {
+	return c.facade.FacadeCall("ResetCallMetrics", nil, nil)
+}
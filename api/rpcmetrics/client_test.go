@@ -0,0 +1,73 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package rpcmetrics_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/rpcmetrics"
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type rpcMetricsSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&rpcMetricsSuite{})
+
+func (s *rpcMetricsSuite) TestCallMetrics(c *gc.C) {
+	expected := params.RPCMetricsResult{
+		LatencyBuckets: []time.Duration{time.Millisecond},
+		Methods: []params.RPCMethodMetrics{{
+			Facade:  "Client",
+			Method:  "FullStatus",
+			Calls:   2,
+			Buckets: []int64{1, 1},
+		}},
+	}
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string, version int, id, request string, a, result interface{}) error {
+			c.Check(objType, gc.Equals, "RPCMetrics")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "CallMetrics")
+			c.Check(a, gc.IsNil)
+			*(result.(*params.RPCMetricsResult)) = expected
+			return nil
+		})
+	client := rpcmetrics.NewClient(apiCaller)
+	result, err := client.CallMetrics()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, expected)
+}
+
+func (s *rpcMetricsSuite) TestCallMetricsError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string, version int, id, request string, a, result interface{}) error {
+			return errors.New("boom")
+		})
+	client := rpcmetrics.NewClient(apiCaller)
+	_, err := client.CallMetrics()
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *rpcMetricsSuite) TestResetCallMetrics(c *gc.C) {
+	var called bool
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string, version int, id, request string, a, result interface{}) error {
+			called = true
+			c.Check(objType, gc.Equals, "RPCMetrics")
+			c.Check(request, gc.Equals, "ResetCallMetrics")
+			return nil
+		})
+	client := rpcmetrics.NewClient(apiCaller)
+	err := client.ResetCallMetrics()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+}
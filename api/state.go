@@ -19,7 +19,9 @@ import (
 	"github.com/juju/juju/api/environment"
 	"github.com/juju/juju/api/firewaller"
 	"github.com/juju/juju/api/keyupdater"
+	"github.com/juju/juju/api/leadership"
 	apilogger "github.com/juju/juju/api/logger"
+	"github.com/juju/juju/api/machineactions"
 	"github.com/juju/juju/api/machiner"
 	"github.com/juju/juju/api/networker"
 	"github.com/juju/juju/api/provisioner"
@@ -89,6 +91,7 @@ func (st *State) loginV1(tag, password, nonce string) error {
 	if err != nil {
 		return err
 	}
+	st.batchCalls = result.LoginResultV1.BatchCalls
 	return nil
 }
 
@@ -213,6 +216,13 @@ func (st *State) Uniter() (*uniter.State, error) {
 	return uniter.NewState(st, unitTag), nil
 }
 
+// LeadershipManager returns a client for the leadership service,
+// which units use to claim and release leadership of their service.
+func (st *State) LeadershipManager() leadership.LeadershipClient {
+	frontend, backend := base.NewClientFacade(st, "LeadershipService")
+	return leadership.NewClient(frontend, backend)
+}
+
 // DiskManager returns a version of the state that provides functionality
 // required by the diskmanager worker.
 func (st *State) DiskManager() (*diskmanager.State, error) {
@@ -260,6 +270,16 @@ func (st *State) Reboot() (*reboot.State, error) {
 	}
 }
 
+// MachineActions returns access to the MachineActions API
+func (st *State) MachineActions() (*machineactions.State, error) {
+	switch tag := st.authTag.(type) {
+	case names.MachineTag:
+		return machineactions.NewState(st, tag), nil
+	default:
+		return nil, errors.Errorf("expected names.MachineTag, got %T", tag)
+	}
+}
+
 // Deployer returns access to the Deployer API
 func (st *State) Deployer() *deployer.State {
 	return deployer.NewState(st)
@@ -37,6 +37,22 @@ func (s *stateSuite) TestCloseMultipleOk(c *gc.C) {
 	c.Assert(s.APIState.Close(), gc.IsNil)
 }
 
+func (s *stateSuite) TestTraceId(c *gc.C) {
+	// Each connection is given its own trace id when opened.
+	traceId := s.APIState.TraceId()
+	c.Assert(traceId, gc.Matches, "[0-9a-f]{16}")
+	apistate, err := api.Open(s.APIInfo(c), api.DialOpts{})
+	c.Assert(err, jc.ErrorIsNil)
+	defer apistate.Close()
+	c.Assert(apistate.TraceId(), gc.Not(gc.Equals), traceId)
+
+	// A connection can be given the trace id of another.
+	apistate.SetTraceId(traceId)
+	c.Assert(apistate.TraceId(), gc.Equals, traceId)
+	_, err = apistate.Client().EnvironmentGet()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 // OpenAPIWithoutLogin connects to the API and returns an api.State without
 // actually calling st.Login already. The returned strings are the "tag" and
 // "password" that we would have used to login.
@@ -120,7 +136,7 @@ func (s *stateSuite) TestAllFacadeVersionsSafeFromMutation(c *gc.C) {
 }
 
 func (s *stateSuite) TestBestFacadeVersion(c *gc.C) {
-	c.Check(s.APIState.BestFacadeVersion("Client"), gc.Equals, 0)
+	c.Check(s.APIState.BestFacadeVersion("Client"), gc.Equals, 1)
 }
 
 func (s *stateSuite) TestAPIHostPortsMovesConnectedValueFirst(c *gc.C) {
@@ -4,8 +4,6 @@
 package uniter
 
 import (
-	"fmt"
-
 	"github.com/juju/errors"
 	"github.com/juju/names"
 
@@ -47,7 +45,10 @@ func (lsa *LeadershipSettingsAccessor) Merge(serviceId string, settings map[stri
 	if err != nil {
 		return errors.Annotate(err, "could not merge settings")
 	}
-	return results.Results[0].Error
+	if err := results.Results[0].Error; err != nil {
+		return err
+	}
+	return nil
 }
 
 // Read retrieves the leadership settings for the given service
@@ -62,7 +63,10 @@ func (lsa *LeadershipSettingsAccessor) Read(serviceId string) (map[string]string
 	if err != nil {
 		return nil, errors.Annotate(err, "could not read leadership settings")
 	}
-	return results.Results[0].Settings, results.Results[0].Error
+	if err := results.Results[0].Error; err != nil {
+		return nil, err
+	}
+	return results.Results[0].Settings, nil
 }
 
 // WatchLeadershipSettings returns a watcher which can be used to wait
@@ -81,7 +85,9 @@ func (lsa *LeadershipSettingsAccessor) WatchLeadershipSettings(serviceId string)
 	); err != nil {
 		return nil, errors.Annotate(err, "could not watch leadership settings")
 	}
-	fmt.Printf("%v", results)
+	if err := results.Results[0].Error; err != nil {
+		return nil, err
+	}
 	return lsa.newNotifyWatcher(results.Results[0]), nil
 }
 
@@ -6,6 +6,7 @@ package uniter
 import (
 	"fmt"
 
+	"github.com/juju/errors"
 	"github.com/juju/names"
 	"gopkg.in/juju/charm.v4"
 
@@ -114,6 +115,25 @@ func (s *Service) CharmURL() (*charm.URL, bool, error) {
 	return nil, false, fmt.Errorf("%q has no charm url set", s.tag)
 }
 
+// SetStatus sets the status of the service. Only the leader unit of
+// the service is allowed to do so.
+func (s *Service) SetStatus(status params.Status, info string, data map[string]interface{}) error {
+	if s.st.BestAPIVersion() < 2 {
+		return errors.NotImplementedf("service.SetStatus() (need V2+)")
+	}
+	var result params.ErrorResults
+	args := params.SetStatus{
+		Entities: []params.EntityStatus{
+			{Tag: s.tag.String(), Status: status, Info: info, Data: data},
+		},
+	}
+	err := s.st.facade.FacadeCall("SetServiceStatus", args, &result)
+	if err != nil {
+		return err
+	}
+	return result.OneError()
+}
+
 // OwnerTag returns the service's owner user tag.
 func (s *Service) OwnerTag() (names.UserTag, error) {
 	if s.st.BestAPIVersion() > 0 {
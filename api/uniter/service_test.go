@@ -4,6 +4,7 @@
 package uniter_test
 
 import (
+	"github.com/juju/errors"
 	"github.com/juju/names"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
@@ -134,6 +135,13 @@ func (s *serviceSuite) TestOwnerTagV1(c *gc.C) {
 	c.Assert(tag, gc.Equals, s.AdminUserTag(c))
 }
 
+func (s *serviceSuite) TestSetStatusV1NotImplemented(c *gc.C) {
+	s.patchNewState(c, uniter.NewStateV1)
+
+	err := s.apiService.SetStatus(params.StatusRunning, "", nil)
+	c.Assert(err, jc.Satisfies, errors.IsNotImplemented)
+}
+
 func (s *serviceSuite) patchNewState(
 	c *gc.C,
 	patchFunc func(_ base.APICaller, _ names.UnitTag) *uniter.State,
@@ -5,6 +5,7 @@ package uniter
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/names"
@@ -67,6 +68,92 @@ func (u *Unit) SetStatus(status params.Status, info string, data map[string]inte
 	return result.OneError()
 }
 
+// SetWorkloadStatus sets the status of the software run by the unit,
+// as reported by its charm.
+func (u *Unit) SetWorkloadStatus(status params.Status, info string, data map[string]interface{}) error {
+	if u.st.BestAPIVersion() < 2 {
+		return errors.NotImplementedf("unit.SetWorkloadStatus() (need V2+)")
+	}
+	var result params.ErrorResults
+	args := params.SetStatus{
+		Entities: []params.EntityStatus{
+			{Tag: u.tag.String(), Status: status, Info: info, Data: data},
+		},
+	}
+	err := u.st.facade.FacadeCall("SetWorkloadStatus", args, &result)
+	if err != nil {
+		return err
+	}
+	return result.OneError()
+}
+
+// WorkloadStatus returns the status of the software run by the unit,
+// as last reported by its charm.
+func (u *Unit) WorkloadStatus() (params.StatusResult, error) {
+	if u.st.BestAPIVersion() < 2 {
+		return params.StatusResult{}, errors.NotImplementedf("unit.WorkloadStatus() (need V2+)")
+	}
+	var results params.StatusResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: u.tag.String()}},
+	}
+	err := u.st.facade.FacadeCall("WorkloadStatus", args, &results)
+	if err != nil {
+		return params.StatusResult{}, err
+	}
+	if len(results.Results) != 1 {
+		return params.StatusResult{}, fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return params.StatusResult{}, result.Error
+	}
+	return result, nil
+}
+
+// HookTimeout returns how long the unit's charm hooks may run before
+// they are killed. Zero means they may run for as long as they like.
+func (u *Unit) HookTimeout() (time.Duration, error) {
+	if u.st.BestAPIVersion() < 2 {
+		return 0, errors.NotImplementedf("unit.HookTimeout() (need V2+)")
+	}
+	var results params.DurationResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: u.tag.String()}},
+	}
+	err := u.st.facade.FacadeCall("HookTimeout", args, &results)
+	if err != nil {
+		return 0, err
+	}
+	if len(results.Results) != 1 {
+		return 0, fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return result.Result, nil
+}
+
+// AddHookRecord adds record to the unit's hook history.
+//
+// NOTE: This differs from state.Unit.AddHookRecord() by requiring V2+
+// of the uniter API.
+func (u *Unit) AddHookRecord(record params.HookRecord) error {
+	if u.st.BestAPIVersion() < 2 {
+		return errors.NotImplementedf("unit.AddHookRecord() (need V2+)")
+	}
+	var result params.ErrorResults
+	args := params.UnitHookRecords{
+		Records: []params.UnitHookRecord{{Tag: u.tag.String(), Record: record}},
+	}
+	err := u.st.facade.FacadeCall("AddHookRecords", args, &result)
+	if err != nil {
+		return err
+	}
+	return result.OneError()
+}
+
 // AddMetrics adds the metrics for the unit.
 func (u *Unit) AddMetrics(metrics []params.Metric) error {
 	var result params.ErrorResults
@@ -459,6 +546,46 @@ func (u *Unit) CharmURL() (*charm.URL, error) {
 	return nil, ErrNoCharmURLSet
 }
 
+// UpgradeCharmURL returns the URL of the charm the unit should be
+// running, and whether it should upgrade to that charm even if it is
+// in an error state. This is the service's charm unless a rolling
+// charm upgrade has not yet allowed the unit to upgrade.
+//
+// NOTE: With V0 or V1 of the uniter API, which do not support rolling
+// upgrades, this returns the service's charm URL.
+func (u *Unit) UpgradeCharmURL() (*charm.URL, bool, error) {
+	if u.st.BestAPIVersion() < 2 {
+		service := &Service{
+			st:  u.st,
+			tag: u.ServiceTag(),
+		}
+		return service.CharmURL()
+	}
+	var results params.StringBoolResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: u.tag.String()}},
+	}
+	err := u.st.facade.FacadeCall("UpgradeCharmURL", args, &results)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(results.Results) != 1 {
+		return nil, false, fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, false, result.Error
+	}
+	if result.Result != "" {
+		curl, err := charm.ParseURL(result.Result)
+		if err != nil {
+			return nil, false, err
+		}
+		return curl, result.Ok, nil
+	}
+	return nil, false, fmt.Errorf("%q has no charm url set", u.ServiceTag())
+}
+
 // SetCharmURL marks the unit as currently using the supplied charm URL.
 // An error will be returned if the unit is dead, or the charm URL not known.
 func (u *Unit) SetCharmURL(curl *charm.URL) error {
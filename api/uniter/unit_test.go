@@ -71,6 +71,85 @@ func (s *unitSuite) TestSetStatus(c *gc.C) {
 	c.Assert(data, gc.HasLen, 0)
 }
 
+func (s *unitSuite) TestSetWorkloadStatus(c *gc.C) {
+	result, err := s.apiUnit.WorkloadStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Status, gc.Equals, params.StatusUnknown)
+
+	err = s.apiUnit.SetWorkloadStatus(params.StatusBlocked, "need a database", map[string]interface{}{
+		"foo": "bar",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	status, info, data, err := s.wordpressUnit.WorkloadStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status, gc.Equals, state.StatusBlocked)
+	c.Assert(info, gc.Equals, "need a database")
+	c.Assert(data, gc.DeepEquals, map[string]interface{}{"foo": "bar"})
+
+	result, err = s.apiUnit.WorkloadStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Status, gc.Equals, params.StatusBlocked)
+	c.Assert(result.Info, gc.Equals, "need a database")
+	c.Assert(result.Data, gc.DeepEquals, map[string]interface{}{"foo": "bar"})
+}
+
+func (s *unitSuite) TestSetWorkloadStatusV1NotImplemented(c *gc.C) {
+	s.patchNewState(c, uniter.NewStateV1)
+
+	err := s.apiUnit.SetWorkloadStatus(params.StatusRunning, "", nil)
+	c.Assert(err, jc.Satisfies, errors.IsNotImplemented)
+	_, err = s.apiUnit.WorkloadStatus()
+	c.Assert(err, jc.Satisfies, errors.IsNotImplemented)
+}
+
+func (s *unitSuite) TestHookTimeout(c *gc.C) {
+	timeout, err := s.apiUnit.HookTimeout()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(timeout, gc.Equals, time.Duration(0))
+
+	err = s.wordpressService.SetHookTimeout(time.Minute)
+	c.Assert(err, jc.ErrorIsNil)
+	timeout, err = s.apiUnit.HookTimeout()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(timeout, gc.Equals, time.Minute)
+}
+
+func (s *unitSuite) TestHookTimeoutV1NotImplemented(c *gc.C) {
+	s.patchNewState(c, uniter.NewStateV1)
+
+	_, err := s.apiUnit.HookTimeout()
+	c.Assert(err, jc.Satisfies, errors.IsNotImplemented)
+}
+
+func (s *unitSuite) TestAddHookRecord(c *gc.C) {
+	started := time.Date(2015, 4, 1, 12, 0, 0, 0, time.UTC)
+	err := s.apiUnit.AddHookRecord(params.HookRecord{
+		Kind:     params.HookRecordHook,
+		Hook:     "install",
+		Started:  started,
+		Duration: time.Second,
+		Result:   "exit status 1",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	records, err := s.wordpressUnit.HookHistory()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(records, gc.HasLen, 1)
+	c.Assert(records[0].Kind, gc.Equals, "hook")
+	c.Assert(records[0].Hook, gc.Equals, "install")
+	c.Assert(records[0].Started.Equal(started), jc.IsTrue)
+	c.Assert(records[0].Duration, gc.Equals, time.Second)
+	c.Assert(records[0].Result, gc.Equals, "exit status 1")
+}
+
+func (s *unitSuite) TestAddHookRecordV1NotImplemented(c *gc.C) {
+	s.patchNewState(c, uniter.NewStateV1)
+
+	err := s.apiUnit.AddHookRecord(params.HookRecord{Kind: params.HookRecordHook, Hook: "install"})
+	c.Assert(err, jc.Satisfies, errors.IsNotImplemented)
+}
+
 func (s *unitSuite) TestEnsureDead(c *gc.C) {
 	c.Assert(s.wordpressUnit.Life(), gc.Equals, state.Alive)
 
@@ -355,6 +434,30 @@ func (s *unitSuite) TestGetSetCharmURL(c *gc.C) {
 	c.Assert(curl.String(), gc.Equals, s.wordpressCharm.String())
 }
 
+func (s *unitSuite) TestUpgradeCharmURL(c *gc.C) {
+	curl, force, err := s.apiUnit.UpgradeCharmURL()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(curl, gc.DeepEquals, s.wordpressCharm.URL())
+	c.Assert(force, jc.IsFalse)
+
+	err = s.wordpressService.SetCharm(s.wordpressCharm, true)
+	c.Assert(err, jc.ErrorIsNil)
+	curl, force, err = s.apiUnit.UpgradeCharmURL()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(curl, gc.DeepEquals, s.wordpressCharm.URL())
+	c.Assert(force, jc.IsTrue)
+}
+
+func (s *unitSuite) TestUpgradeCharmURLV1(c *gc.C) {
+	s.patchNewState(c, uniter.NewStateV1)
+
+	// Without rolling upgrade support, the service's charm is used.
+	curl, force, err := s.apiUnit.UpgradeCharmURL()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(curl, gc.DeepEquals, s.wordpressCharm.URL())
+	c.Assert(force, jc.IsFalse)
+}
+
 func (s *unitSuite) TestConfigSettings(c *gc.C) {
 	// Make sure ConfigSettings returns an error when
 	// no charm URL is set, as its state counterpart does.
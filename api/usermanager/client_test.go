@@ -119,6 +119,7 @@ func (s *usermanagerSuite) TestUserInfo(c *gc.C) {
 			DisplayName: "Foo Bar",
 			CreatedBy:   s.AdminUserTag(c).Name(),
 			DateCreated: user.DateCreated(),
+			Access:      params.AdminEnvironAccess,
 		},
 	}
 
@@ -7,6 +7,7 @@ import (
 	"github.com/juju/loggo"
 	"github.com/juju/names"
 
+	"github.com/juju/juju/actions"
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/state"
@@ -16,6 +17,7 @@ var logger = loggo.GetLogger("juju.apiserver.action")
 
 func init() {
 	common.RegisterStandardFacade("Action", 0, NewActionAPI)
+	common.RegisterStandardFacade("Action", 1, NewActionAPIV1)
 }
 
 // ActionAPI implements the client API for interacting with Actions
@@ -23,6 +25,7 @@ type ActionAPI struct {
 	state      *state.State
 	resources  *common.Resources
 	authorizer common.Authorizer
+	check      *common.BlockChecker
 }
 
 // NewActionAPI returns an initialized ActionAPI
@@ -35,6 +38,7 @@ func NewActionAPI(st *state.State, resources *common.Resources, authorizer commo
 		state:      st,
 		resources:  resources,
 		authorizer: authorizer,
+		check:      common.NewBlockChecker(st),
 	}, nil
 }
 
@@ -96,6 +100,9 @@ func (a *ActionAPI) FindActionTagsByPrefix(arg params.FindTags) (params.FindTags
 // queued Action, or an error if there was a problem queueing up the
 // Action.
 func (a *ActionAPI) Enqueue(arg params.Actions) (params.ActionResults, error) {
+	if !a.authorizer.AuthEnvironAccess(state.EnvironWriteAccess) {
+		return params.ActionResults{}, common.ErrPerm
+	}
 	response := params.ActionResults{Results: make([]params.ActionResult, len(arg.Actions))}
 	for i, action := range arg.Actions {
 		current := &response.Results[i]
@@ -105,6 +112,12 @@ func (a *ActionAPI) Enqueue(arg params.Actions) (params.ActionResults, error) {
 			current.Error = common.ServerError(err)
 			continue
 		}
+		if action.Name == actions.JujuRunActionName {
+			if err := a.checkCanRunOn(receiver); err != nil {
+				current.Error = common.ServerError(err)
+				continue
+			}
+		}
 
 		queued, err := receiver.AddAction(action.Name, action.Parameters)
 		if err != nil {
@@ -145,6 +158,9 @@ func (a *ActionAPI) ListCompleted(arg params.Entities) (params.ActionsByReceiver
 
 // Cancel attempts to cancel queued up Actions from running.
 func (a *ActionAPI) Cancel(arg params.Entities) (params.ActionResults, error) {
+	if !a.authorizer.AuthEnvironAccess(state.EnvironWriteAccess) {
+		return params.ActionResults{}, common.ErrPerm
+	}
 	response := params.ActionResults{Results: make([]params.ActionResult, len(arg.Entities))}
 	for i, entity := range arg.Entities {
 		current := &response.Results[i]
@@ -29,7 +29,7 @@ func TestAll(t *testing.T) {
 type actionSuite struct {
 	jujutesting.JujuConnSuite
 
-	action     *action.ActionAPI
+	action     *action.ActionAPIV1
 	authorizer apiservertesting.FakeAuthorizer
 	resources  *common.Resources
 
@@ -52,7 +52,7 @@ func (s *actionSuite) SetUpTest(c *gc.C) {
 		Tag: s.AdminUserTag(c),
 	}
 	var err error
-	s.action, err = action.NewActionAPI(s.State, nil, s.authorizer)
+	s.action, err = action.NewActionAPIV1(s.State, nil, s.authorizer)
 	c.Assert(err, jc.ErrorIsNil)
 
 	factory := jujuFactory.NewFactory(s.State)
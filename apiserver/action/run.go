@@ -0,0 +1,167 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package action
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"github.com/juju/utils/set"
+
+	"github.com/juju/juju/actions"
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+// getAllUnitTags returns a sequence of valid unit tags from state. If any
+// of the service names or unit names are not found, an error is returned.
+func getAllUnitTags(st *state.State, units, services []string) (result []names.Tag, err error) {
+	unitsSet := set.NewStrings(units...)
+	for _, name := range services {
+		service, err := st.Service(name)
+		if err != nil {
+			return nil, err
+		}
+		units, err := service.AllUnits()
+		if err != nil {
+			return nil, err
+		}
+		for _, unit := range units {
+			unitsSet.Add(unit.Name())
+		}
+	}
+	for _, unitName := range unitsSet.SortedValues() {
+		unit, err := st.Unit(unitName)
+		if err != nil {
+			return nil, err
+		}
+		// We only operate on units that have an assigned machine,
+		// as only those have an agent to run the commands.
+		if _, err := unit.AssignedMachineId(); err != nil {
+			return nil, err
+		}
+		result = append(result, unit.Tag())
+	}
+	return result, nil
+}
+
+// ActionAPIV1 implements version 1 of the client API for interacting
+// with Actions. It is like ActionAPI, except that it can also run
+// commands on machines and units as juju-run actions.
+type ActionAPIV1 struct {
+	*ActionAPI
+}
+
+// NewActionAPIV1 returns an initialized ActionAPIV1.
+func NewActionAPIV1(st *state.State, resources *common.Resources, authorizer common.Authorizer) (*ActionAPIV1, error) {
+	api, err := NewActionAPI(st, resources, authorizer)
+	if err != nil {
+		return nil, err
+	}
+	return &ActionAPIV1{api}, nil
+}
+
+// Run enqueues the commands specified as a juju-run action on the
+// machines and units identified through the list of machines, units
+// and services. The agents responsible for each receiver run the
+// commands and record the results against the returned actions.
+// Running commands on machines, or in units on state server machines,
+// requires admin access to the environment.
+func (a *ActionAPIV1) Run(run params.RunParams) (results params.ActionResults, err error) {
+	if err := a.checkCanRun(); err != nil {
+		return results, errors.Trace(err)
+	}
+	receivers, err := getAllUnitTags(a.state, run.Units, run.Services)
+	if err != nil {
+		return results, errors.Trace(err)
+	}
+	for _, machineId := range run.Machines {
+		if !names.IsValidMachine(machineId) {
+			return results, errors.NotValidf("machine id %q", machineId)
+		}
+		receivers = append(receivers, names.NewMachineTag(machineId))
+	}
+	return a.enqueueJujuRun(receivers, run)
+}
+
+// RunOnAllMachines enqueues the specified commands as a juju-run action
+// on all the machines in the environment. It requires admin access to
+// the environment.
+func (a *ActionAPIV1) RunOnAllMachines(run params.RunParams) (results params.ActionResults, err error) {
+	if err := a.checkCanRun(); err != nil {
+		return results, errors.Trace(err)
+	}
+	if !a.authorizer.AuthEnvironAccess(state.EnvironAdminAccess) {
+		return results, common.ErrPerm
+	}
+	machines, err := a.state.AllMachines()
+	if err != nil {
+		return results, errors.Trace(err)
+	}
+	receivers := make([]names.Tag, len(machines))
+	for i, machine := range machines {
+		receivers[i] = machine.Tag()
+	}
+	return a.enqueueJujuRun(receivers, run)
+}
+
+func (a *ActionAPI) checkCanRun() error {
+	if !a.authorizer.AuthEnvironAccess(state.EnvironWriteAccess) {
+		return common.ErrPerm
+	}
+	return a.check.ChangeAllowed()
+}
+
+// checkCanRunOn returns common.ErrPerm if the authenticated user may
+// not run commands on the receiver. The commands run as root, so only
+// users with admin access to the environment may run them on machines,
+// or in units on state server machines, which hold the environment's
+// secrets.
+func (a *ActionAPI) checkCanRunOn(receiver state.ActionReceiver) error {
+	if a.authorizer.AuthEnvironAccess(state.EnvironAdminAccess) {
+		return nil
+	}
+	unit, ok := receiver.(*state.Unit)
+	if !ok {
+		return common.ErrPerm
+	}
+	machineId, err := unit.AssignedMachineId()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	machine, err := a.state.Machine(machineId)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if machine.IsManager() {
+		return common.ErrPerm
+	}
+	return nil
+}
+
+// enqueueJujuRun enqueues a juju-run action with the run parameters on
+// each of the receivers.
+func (a *ActionAPI) enqueueJujuRun(receivers []names.Tag, run params.RunParams) (params.ActionResults, error) {
+	parameters := actions.JujuRunParams(run.Commands, run.Timeout)
+	arg := params.Actions{Actions: make([]params.Action, len(receivers))}
+	for i, receiver := range receivers {
+		arg.Actions[i] = params.Action{
+			Receiver:   receiver.String(),
+			Name:       actions.JujuRunActionName,
+			Parameters: parameters,
+		}
+	}
+	results, err := a.Enqueue(arg)
+	if err != nil {
+		return results, err
+	}
+	// Make sure callers can tell which receiver an enqueueing
+	// failure relates to.
+	for i, result := range results.Results {
+		if result.Action == nil {
+			results.Results[i].Action = &arg.Actions[i]
+		}
+	}
+	return results, nil
+}
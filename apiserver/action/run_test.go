@@ -0,0 +1,160 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package action_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/actions"
+	"github.com/juju/juju/apiserver/action"
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/state"
+)
+
+func (s *actionSuite) blockAllChanges(c *gc.C) {
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{"block-all-changes": true}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *actionSuite) TestRunMachineAndService(c *gc.C) {
+	results, err := s.action.Run(params.RunParams{
+		Commands: "hostname",
+		Timeout:  time.Minute,
+		Machines: []string{s.machine1.Id()},
+		Services: []string{"wordpress"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+
+	expectedParams := actions.JujuRunParams("hostname", time.Minute)
+	for i, receiver := range []string{s.wordpressUnit.Tag().String(), s.machine1.Tag().String()} {
+		result := results.Results[i]
+		c.Check(result.Error, gc.IsNil)
+		c.Assert(result.Action, gc.NotNil)
+		c.Check(result.Action.Receiver, gc.Equals, receiver)
+		c.Check(result.Action.Name, gc.Equals, actions.JujuRunActionName)
+		c.Check(result.Status, gc.Equals, string(state.ActionPending))
+	}
+
+	pending, err := s.machine1.PendingActions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pending, gc.HasLen, 1)
+	c.Assert(pending[0].Name(), gc.Equals, actions.JujuRunActionName)
+	c.Assert(pending[0].Parameters(), gc.DeepEquals, expectedParams)
+
+	pending, err = s.wordpressUnit.PendingActions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pending, gc.HasLen, 1)
+	c.Assert(pending[0].Name(), gc.Equals, actions.JujuRunActionName)
+}
+
+func (s *actionSuite) TestRunUnknownService(c *gc.C) {
+	_, err := s.action.Run(params.RunParams{
+		Commands: "hostname",
+		Services: []string{"unknown"},
+	})
+	c.Assert(err, gc.ErrorMatches, `service "unknown" not found`)
+}
+
+func (s *actionSuite) TestRunInvalidMachine(c *gc.C) {
+	_, err := s.action.Run(params.RunParams{
+		Commands: "hostname",
+		Machines: []string{"foo"},
+	})
+	c.Assert(err, gc.ErrorMatches, `machine id "foo" not valid`)
+}
+
+func (s *actionSuite) TestRunUnknownMachine(c *gc.C) {
+	results, err := s.action.Run(params.RunParams{
+		Commands: "hostname",
+		Machines: []string{"42"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.ErrorMatches, "id not found")
+	c.Assert(results.Results[0].Action, gc.NotNil)
+	c.Assert(results.Results[0].Action.Receiver, gc.Equals, "machine-42")
+}
+
+func (s *actionSuite) TestRunOnAllMachines(c *gc.C) {
+	results, err := s.action.RunOnAllMachines(params.RunParams{
+		Commands: "hostname",
+		Timeout:  time.Minute,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+	for i, machine := range []*state.Machine{s.machine0, s.machine1} {
+		c.Check(results.Results[i].Error, gc.IsNil)
+		c.Assert(results.Results[i].Action, gc.NotNil)
+		c.Check(results.Results[i].Action.Receiver, gc.Equals, machine.Tag().String())
+
+		pending, err := machine.PendingActions()
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(pending, gc.HasLen, 1)
+	}
+}
+
+func (s *actionSuite) TestBlockRun(c *gc.C) {
+	s.blockAllChanges(c)
+	_, err := s.action.Run(params.RunParams{
+		Commands: "hostname",
+		Machines: []string{s.machine1.Id()},
+	})
+	c.Assert(errors.Cause(err), gc.DeepEquals, common.ErrOperationBlocked)
+}
+
+func (s *actionSuite) TestBlockRunOnAllMachines(c *gc.C) {
+	s.blockAllChanges(c)
+	_, err := s.action.RunOnAllMachines(params.RunParams{Commands: "hostname"})
+	c.Assert(errors.Cause(err), gc.DeepEquals, common.ErrOperationBlocked)
+}
+
+func (s *actionSuite) newActionAPIWithAccess(c *gc.C, access state.EnvironmentAccess) *action.ActionAPIV1 {
+	auth := apiservertesting.FakeAuthorizer{
+		Tag:    names.NewUserTag("bob"),
+		Access: access,
+	}
+	api, err := action.NewActionAPIV1(s.State, nil, auth)
+	c.Assert(err, jc.ErrorIsNil)
+	return api
+}
+
+func (s *actionSuite) TestRunWriteAccess(c *gc.C) {
+	api := s.newActionAPIWithAccess(c, state.EnvironWriteAccess)
+	results, err := api.Run(params.RunParams{
+		Commands: "hostname",
+		Machines: []string{s.machine1.Id()},
+		Services: []string{"mysql", "wordpress"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 3)
+
+	// Only the unit that is not on a state server can run commands.
+	c.Check(results.Results[0].Action.Receiver, gc.Equals, s.mysqlUnit.Tag().String())
+	c.Check(results.Results[0].Error, gc.IsNil)
+	c.Check(results.Results[1].Action.Receiver, gc.Equals, s.wordpressUnit.Tag().String())
+	c.Check(results.Results[1].Error, gc.ErrorMatches, "permission denied")
+	c.Check(results.Results[2].Action.Receiver, gc.Equals, s.machine1.Tag().String())
+	c.Check(results.Results[2].Error, gc.ErrorMatches, "permission denied")
+
+	pending, err := s.machine1.PendingActions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pending, gc.HasLen, 0)
+	pending, err = s.wordpressUnit.PendingActions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pending, gc.HasLen, 0)
+}
+
+func (s *actionSuite) TestRunOnAllMachinesWriteAccess(c *gc.C) {
+	api := s.newActionAPIWithAccess(c, state.EnvironWriteAccess)
+	_, err := api.RunOnAllMachines(params.RunParams{Commands: "hostname"})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/juju/juju/apiserver/authentication"
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/identity"
 	"github.com/juju/juju/rpc"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/presence"
@@ -131,7 +132,7 @@ func (a *admin) doLogin(req params.LoginRequest) (params.LoginResultV1, error) {
 		// Users are not rate limited, all other entities are
 		if !a.srv.limiter.Acquire() {
 			logger.Debugf("rate limiting, try again later")
-			return fail, common.ErrTryAgain
+			return fail, a.srv.tryAgainError()
 		}
 		defer a.srv.limiter.Release()
 	} else {
@@ -188,6 +189,10 @@ func (a *admin) doLogin(req params.LoginRequest) (params.LoginResultV1, error) {
 	var maybeUserInfo *params.AuthUserInfo
 	// Send back user info if user
 	if isUser {
+		a.root.access, err = environAccess(a.root.state, entity)
+		if err != nil {
+			return fail, err
+		}
 		lastConnection := getAndUpdateLastLoginForEntity(entity)
 		maybeUserInfo = &params.AuthUserInfo{
 			Identity:       entity.Tag().String(),
@@ -207,6 +212,9 @@ func (a *admin) doLogin(req params.LoginRequest) (params.LoginResultV1, error) {
 		return fail, err
 	}
 
+	if a.srv.maxConnRequests > 0 {
+		authedApi = newLimitingRoot(authedApi, a.srv.maxConnRequests, a.srv.tryAgainError)
+	}
 	a.root.rpcConn.ServeFinder(authedApi, serverError)
 
 	return params.LoginResultV1{
@@ -214,6 +222,7 @@ func (a *admin) doLogin(req params.LoginRequest) (params.LoginResultV1, error) {
 		EnvironTag: environ.Tag().String(),
 		Facades:    DescribeFacades(),
 		UserInfo:   maybeUserInfo,
+		BatchCalls: true,
 	}, nil
 }
 
@@ -267,6 +276,9 @@ func checkCreds(st *state.State, req params.LoginRequest) (state.Entity, error)
 	if err != nil {
 		return nil, err
 	}
+	if user, ok := tag.(names.UserTag); ok && !user.IsLocal() {
+		return checkExternalUserCreds(st, user, req.Credentials)
+	}
 	entity, err := st.FindEntity(tag)
 	if errors.IsNotFound(err) {
 		// We return the same error when an entity does not exist as for a bad
@@ -300,6 +312,113 @@ func checkCreds(st *state.State, req params.LoginRequest) (state.Entity, error)
 	return entity, nil
 }
 
+// externalUser represents a remote user whose identity has been
+// vouched for by an external identity service. Remote users have no
+// entity of their own in state.
+type externalUser struct {
+	tag    names.UserTag
+	access state.EnvironmentAccess
+}
+
+// Tag implements state.Entity.
+func (u *externalUser) Tag() names.Tag {
+	return u.tag
+}
+
+// checkExternalUserCreds checks that the credentials hold a macaroon
+// discharged for the remote user by the identity service trusted by
+// the environment, and that the user, or one of the groups the identity
+// service says the user belongs to, has been given access to the
+// environment. Users logging in without credentials are sent a
+// macaroon to have discharged.
+func checkExternalUserCreds(st *state.State, user names.UserTag, credentials string) (state.Entity, error) {
+	cfg, err := st.EnvironConfig()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	location, locationOK := cfg.IdentityURL()
+	keyString, keyOK := cfg.IdentityPublicKey()
+	if !locationOK || !keyOK {
+		logger.Debugf("no identity service configured for remote user %q", user.Username())
+		return nil, common.ErrBadCreds
+	}
+	key, err := identity.ParsePublicKey(keyString)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	rootKey, err := st.MacaroonRootKey()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	authenticator := &authentication.ExternalUserAuthenticator{
+		RootKey:         rootKey,
+		EnvironUUID:     st.EnvironUUID(),
+		ServiceLocation: location,
+		ServiceKey:      key,
+	}
+	id, err := authenticator.Authenticate(user, credentials)
+	if err != nil {
+		return nil, err
+	}
+	envUser, err := st.EnvironmentUser(user)
+	if err == nil {
+		return &externalUser{tag: user, access: envUser.Access()}, nil
+	}
+	if !errors.IsNotFound(err) {
+		return nil, errors.Trace(err)
+	}
+	// The user has no access of their own, so they are given
+	// the greatest access granted to any of their groups.
+	var access state.EnvironmentAccess
+	for _, group := range id.Groups {
+		envGroup, err := st.EnvironmentGroup(group)
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if !access.Includes(envGroup.Access()) {
+			access = envGroup.Access()
+		}
+	}
+	if access == "" {
+		logger.Debugf("remote user %q has no access to the environment", user.Username())
+		return nil, common.ErrBadCreds
+	}
+	return &externalUser{tag: user, access: access}, nil
+}
+
+// environAccess returns the level of access the given
+// authenticated user has to the environment.
+func environAccess(st *state.State, entity state.Entity) (state.EnvironmentAccess, error) {
+	if user, ok := entity.(*externalUser); ok {
+		return user.access, nil
+	}
+	user, ok := entity.Tag().(names.UserTag)
+	if !ok {
+		return "", errors.Errorf("entity %q is not a user", entity.Tag())
+	}
+	envUser, err := st.EnvironmentUser(user)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return envUser.Access(), nil
+}
+
+// checkEnvironAccess returns common.ErrPerm if the given authenticated
+// user does not have at least the given level of access to the environment.
+func checkEnvironAccess(st *state.State, entity state.Entity, access state.EnvironmentAccess) error {
+	userAccess, err := environAccess(st, entity)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !userAccess.Includes(access) {
+		return common.ErrPerm
+	}
+	return nil
+}
+
 func getAndUpdateLastLoginForEntity(entity state.Entity) *time.Time {
 	if user, ok := entity.(*state.User); ok {
 		result := user.LastLogin()
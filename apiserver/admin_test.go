@@ -20,6 +20,9 @@ import (
 	"github.com/juju/juju/api"
 	"github.com/juju/juju/apiserver"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/identity"
+	identitytesting "github.com/juju/juju/identity/testing"
 	jujutesting "github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
@@ -534,6 +537,137 @@ func (s *loginSuite) TestNonEnvironUserLoginFails(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "invalid entity name or password")
 }
 
+func (s *loginSuite) TestLoginWithReadAccess(c *gc.C) {
+	info, cleanup := s.setupServerWithValidator(c, nil)
+	defer cleanup()
+	user := s.Factory.MakeUser(c, &factory.UserParams{Password: "dummy-password", NoEnvUser: true})
+	s.Factory.MakeEnvUser(c, &factory.EnvUserParams{
+		User:   user.UserTag().Username(),
+		Access: state.EnvironReadAccess,
+	})
+	info.Password = "dummy-password"
+	info.Tag = user.UserTag()
+	st, err := api.Open(info, fastDialOpts)
+	c.Assert(err, jc.ErrorIsNil)
+	defer st.Close()
+
+	_, err = st.Client().AgentVersion()
+	c.Assert(err, jc.ErrorIsNil)
+	err = st.Client().SetEnvironmentConstraints(constraints.Value{})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *loginSuite) setupIdentityService(c *gc.C) *identitytesting.Service {
+	svc, err := identitytesting.NewService("sso")
+	c.Assert(err, jc.ErrorIsNil)
+	svc.AddUser("bob", "secret", "devops")
+	err = s.State.UpdateEnvironConfig(map[string]interface{}{
+		"identity-url":        "https://sso.invalid",
+		"identity-public-key": svc.PublicKey(),
+	}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	return svc
+}
+
+// externalUserInfo logs in as bob@sso without credentials, has the
+// given identity service discharge the macaroon the server sends
+// back, and returns info for logging in with the result.
+func (s *loginSuite) externalUserInfo(c *gc.C, svc *identitytesting.Service, info *api.Info) *api.Info {
+	info.Tag = names.NewUserTag(svc.UserName("bob"))
+	info.Password = ""
+	_, err := api.Open(info, fastDialOpts)
+	c.Assert(err, jc.Satisfies, params.IsCodeDischargeRequired)
+	m, ok := params.DischargeRequiredMacaroon(err)
+	c.Assert(ok, jc.IsTrue)
+
+	location, caveatId, err := identity.ThirdPartyCaveat(m)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(location, gc.Equals, "https://sso.invalid")
+	d, err := svc.Discharge("bob", caveatId)
+	c.Assert(err, jc.ErrorIsNil)
+	info.Password, err = identity.EncodeCredentials(m, d)
+	c.Assert(err, jc.ErrorIsNil)
+	return info
+}
+
+func (s *loginSuite) TestExternalUserLoginWithoutAccessFails(c *gc.C) {
+	svc := s.setupIdentityService(c)
+	info, cleanup := s.setupServerWithValidator(c, nil)
+	defer cleanup()
+	_, err := api.Open(s.externalUserInfo(c, svc, info), fastDialOpts)
+	c.Assert(err, gc.ErrorMatches, "invalid entity name or password")
+}
+
+func (s *loginSuite) TestExternalUserLoginAsEnvironUser(c *gc.C) {
+	svc := s.setupIdentityService(c)
+	_, err := s.State.AddEnvironmentUser(names.NewUserTag("bob@sso"), s.AdminUserTag(c), state.EnvironAdminAccess)
+	c.Assert(err, jc.ErrorIsNil)
+	info, cleanup := s.setupServerWithValidator(c, nil)
+	defer cleanup()
+	st, err := api.Open(s.externalUserInfo(c, svc, info), fastDialOpts)
+	c.Assert(err, jc.ErrorIsNil)
+	st.Close()
+}
+
+func (s *loginSuite) TestExternalUserLoginAsGroupMember(c *gc.C) {
+	svc := s.setupIdentityService(c)
+	_, err := s.State.AddEnvironmentGroup("devops", s.AdminUserTag(c), state.EnvironWriteAccess)
+	c.Assert(err, jc.ErrorIsNil)
+	info, cleanup := s.setupServerWithValidator(c, nil)
+	defer cleanup()
+	st, err := api.Open(s.externalUserInfo(c, svc, info), fastDialOpts)
+	c.Assert(err, jc.ErrorIsNil)
+	defer st.Close()
+	_, err = st.Client().AgentVersion()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *loginSuite) TestExternalUserLoginWithoutIdentityServiceFails(c *gc.C) {
+	_, err := s.State.AddEnvironmentGroup("devops", s.AdminUserTag(c), state.EnvironWriteAccess)
+	c.Assert(err, jc.ErrorIsNil)
+	info, cleanup := s.setupServerWithValidator(c, nil)
+	defer cleanup()
+	info.Tag = names.NewUserTag("bob@sso")
+	info.Password = ""
+	_, err = api.Open(info, fastDialOpts)
+	c.Assert(err, gc.ErrorMatches, "invalid entity name or password")
+}
+
+func (s *loginSuite) TestExternalUserLoginWithUntrustedDischargeFails(c *gc.C) {
+	s.setupIdentityService(c)
+	_, err := s.State.AddEnvironmentGroup("devops", s.AdminUserTag(c), state.EnvironWriteAccess)
+	c.Assert(err, jc.ErrorIsNil)
+	other, err := identitytesting.NewService("sso")
+	c.Assert(err, jc.ErrorIsNil)
+	other.AddUser("bob", "secret", "devops")
+	info, cleanup := s.setupServerWithValidator(c, nil)
+	defer cleanup()
+	info.Tag = names.NewUserTag("bob@sso")
+	info.Password = ""
+	_, err = api.Open(info, fastDialOpts)
+	m, ok := params.DischargeRequiredMacaroon(err)
+	c.Assert(ok, jc.IsTrue)
+	_, caveatId, err := identity.ThirdPartyCaveat(m)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Only the identity service the environment
+	// trusts can read the caveat to discharge it.
+	_, err = other.Discharge("bob", caveatId)
+	c.Assert(err, gc.ErrorMatches, "caveat id not encrypted for this identity service")
+}
+
+func (s *loginSuite) TestExternalUserLoginWithBadCredentialsFails(c *gc.C) {
+	s.setupIdentityService(c)
+	_, err := s.State.AddEnvironmentGroup("devops", s.AdminUserTag(c), state.EnvironWriteAccess)
+	c.Assert(err, jc.ErrorIsNil)
+	info, cleanup := s.setupServerWithValidator(c, nil)
+	defer cleanup()
+	info.Tag = names.NewUserTag("bob@sso")
+	info.Password = "secret"
+	_, err = api.Open(info, fastDialOpts)
+	c.Assert(err, gc.ErrorMatches, "invalid entity name or password")
+}
+
 func (s *loginV0Suite) TestLoginReportsEnvironTag(c *gc.C) {
 	st, cleanup := s.setupServer(c)
 	defer cleanup()
@@ -69,8 +69,12 @@ func (api *API) Get(args params.Entities) params.AnnotationsGetResults {
 // Set stores annotations for given entities
 func (api *API) Set(args params.AnnotationsSet) params.ErrorResults {
 	setErrors := []params.ErrorResult{}
+	canWrite := api.authorizer.AuthEnvironAccess(state.EnvironWriteAccess)
 	for _, entityAnnotation := range args.Annotations {
-		err := api.setEntityAnnotations(entityAnnotation.EntityTag, entityAnnotation.Annotations)
+		err := common.ErrPerm
+		if canWrite {
+			err = api.setEntityAnnotations(entityAnnotation.EntityTag, entityAnnotation.Annotations)
+		}
 		if err != nil {
 			setErrors = append(setErrors,
 				params.ErrorResult{Error: annotateError(err, entityAnnotation.EntityTag, "setting")})
@@ -25,15 +25,21 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/rpc"
 	"github.com/juju/juju/rpc/jsoncodec"
+	"github.com/juju/juju/rpc/rpcmetrics"
 	"github.com/juju/juju/state"
 )
 
 var logger = loggo.GetLogger("juju.apiserver")
 
 // loginRateLimit defines how many concurrent Login requests we will
-// accept
+// accept if ServerConfig.MaxConcurrentLogins is not set.
 const loginRateLimit = 10
 
+// defaultRetryDelay is the delay suggested to clients that are turned
+// away because the server is busy, if ServerConfig.RetryDelay is not
+// set.
+const defaultRetryDelay = 5 * time.Second
+
 // Server holds the server side of the API.
 type Server struct {
 	tomb              tomb.Tomb
@@ -44,8 +50,12 @@ type Server struct {
 	dataDir           string
 	logDir            string
 	limiter           utils.Limiter
+	connLimiter       utils.Limiter
+	maxConnRequests   int
+	retryDelay        time.Duration
 	validator         LoginValidator
 	adminApiFactories map[int]adminApiFactory
+	metrics           *rpcmetrics.Recorder
 
 	mu          sync.Mutex // protects the fields that follow
 	environUUID string
@@ -65,6 +75,27 @@ type ServerConfig struct {
 	LogDir      string
 	Validator   LoginValidator
 	CertChanged chan params.StateServingInfo
+
+	// MaxConcurrentLogins limits the number of agent logins that
+	// may be in progress at once. User logins are not limited.
+	// If zero, a default limit is used.
+	MaxConcurrentLogins int
+
+	// MaxConnections limits the number of API connections that
+	// may be open at once. If zero, connections are not limited.
+	MaxConnections int
+
+	// MaxConnectionRequests limits the number of requests that a
+	// single connection may have in progress at once. Calls to
+	// watchers and the pinger are not counted, as agents hold
+	// many of those open indefinitely. If zero, requests are not
+	// limited.
+	MaxConnectionRequests int
+
+	// RetryDelay is the delay suggested to clients that are
+	// turned away because one of the above limits has been
+	// reached. If zero, a default delay is used.
+	RetryDelay time.Duration
 }
 
 // changeCertListener wraps a TLS net.Listener.
@@ -180,19 +211,33 @@ func NewServer(s *state.State, lis net.Listener, cfg ServerConfig) (*Server, err
 	if err != nil {
 		return nil, err
 	}
+	maxLogins := cfg.MaxConcurrentLogins
+	if maxLogins <= 0 {
+		maxLogins = loginRateLimit
+	}
+	retryDelay := cfg.RetryDelay
+	if retryDelay <= 0 {
+		retryDelay = defaultRetryDelay
+	}
 	srv := &Server{
-		state:     s,
-		addr:      net.JoinHostPort("localhost", listeningPort),
-		tag:       cfg.Tag,
-		dataDir:   cfg.DataDir,
-		logDir:    cfg.LogDir,
-		limiter:   utils.NewLimiter(loginRateLimit),
-		validator: cfg.Validator,
+		state:           s,
+		addr:            net.JoinHostPort("localhost", listeningPort),
+		tag:             cfg.Tag,
+		dataDir:         cfg.DataDir,
+		logDir:          cfg.LogDir,
+		limiter:         utils.NewLimiter(maxLogins),
+		maxConnRequests: cfg.MaxConnectionRequests,
+		retryDelay:      retryDelay,
+		validator:       cfg.Validator,
+		metrics:         rpcmetrics.NewRecorder(),
 		adminApiFactories: map[int]adminApiFactory{
 			0: newAdminApiV0,
 			1: newAdminApiV1,
 		},
 	}
+	if cfg.MaxConnections > 0 {
+		srv.connLimiter = utils.NewLimiter(cfg.MaxConnections)
+	}
 	// TODO(rog) check that *srvRoot is a valid type for using
 	// as an RPC server.
 	tlsConfig := tls.Config{
@@ -227,20 +272,23 @@ func (srv *Server) Wait() error {
 }
 
 type requestNotifier struct {
-	id    int64
-	start time.Time
+	id      int64
+	start   time.Time
+	metrics *rpcmetrics.Recorder
 
-	mu   sync.Mutex
-	tag_ string
+	mu       sync.Mutex
+	tag_     string
+	traceId_ string
 }
 
 var globalCounter int64
 
-func newRequestNotifier() *requestNotifier {
+func newRequestNotifier(metrics *rpcmetrics.Recorder) *requestNotifier {
 	return &requestNotifier{
-		id:    atomic.AddInt64(&globalCounter, 1),
-		tag_:  "<unknown>",
-		start: time.Now(),
+		id:      atomic.AddInt64(&globalCounter, 1),
+		tag_:    "<unknown>",
+		start:   time.Now(),
+		metrics: metrics,
 	}
 }
 
@@ -257,19 +305,47 @@ func (n *requestNotifier) tag() (tag string) {
 	return
 }
 
+// traceId returns the trace id of the latest request made on the
+// connection, which identifies the client operation it was made for.
+func (n *requestNotifier) traceId() (traceId string) {
+	n.mu.Lock()
+	traceId = n.traceId_
+	n.mu.Unlock()
+	return
+}
+
 func (n *requestNotifier) ServerRequest(hdr *rpc.Header, body interface{}) {
+	n.mu.Lock()
+	n.traceId_ = hdr.TraceId
+	n.mu.Unlock()
 	if hdr.Request.Type == "Pinger" && hdr.Request.Action == "Ping" {
 		return
 	}
+	if logger.EffectiveLogLevel() > loggo.DEBUG {
+		return
+	}
 	// TODO(rog) 2013-10-11 remove secrets from some requests.
-	logger.Debugf("<- [%X] %s %s", n.id, n.tag(), jsoncodec.DumpRequest(hdr, body))
+	logger.Debugf("<- [%X] %s trace %s %s", n.id, n.tag(), hdr.TraceId, jsoncodec.DumpRequest(hdr, body))
 }
 
 func (n *requestNotifier) ServerReply(req rpc.Request, hdr *rpc.Header, body interface{}, timeSpent time.Duration) {
+	switch {
+	case n.metrics == nil:
+	case hdr.ErrorCode == rpc.CodeNotImplemented:
+		// The method named by the request could not be found. Don't
+		// record it under the client-supplied name, or any client
+		// could make the recorded statistics grow without bound.
+		n.metrics.RecordUnknown(timeSpent)
+	default:
+		n.metrics.Record(req, timeSpent, hdr.Error != "")
+	}
 	if req.Type == "Pinger" && req.Action == "Ping" {
 		return
 	}
-	logger.Debugf("-> [%X] %s %s %s %s[%q].%s", n.id, n.tag(), timeSpent, jsoncodec.DumpRequest(hdr, body), req.Type, req.Id, req.Action)
+	if logger.EffectiveLogLevel() > loggo.DEBUG {
+		return
+	}
+	logger.Debugf("-> [%X] %s trace %s %s %s %s[%q].%s", n.id, n.tag(), hdr.TraceId, timeSpent, jsoncodec.DumpRequest(hdr, body), req.Type, req.Id, req.Action)
 }
 
 func (n *requestNotifier) join(req *http.Request) {
@@ -350,6 +426,11 @@ func (srv *Server) run(lis net.Listener) {
 	handleAll(mux, "/environment/:envuuid/images/:kind/:series/:arch/:filename",
 		&imagesDownloadHandler{httpHandler{ssState: srv.state}},
 	)
+	handleAll(mux, "/environment/:envuuid/metrics",
+		&metricsHandler{
+			httpHandler: httpHandler{ssState: srv.state},
+			metrics:     srv.metrics},
+	)
 	// For backwards compatibility we register all the old paths
 	handleAll(mux, "/log",
 		&debugLogHandler{
@@ -377,7 +458,7 @@ func (srv *Server) run(lis net.Listener) {
 }
 
 func (srv *Server) apiHandler(w http.ResponseWriter, req *http.Request) {
-	reqNotifier := newRequestNotifier()
+	reqNotifier := newRequestNotifier(srv.metrics)
 	reqNotifier.join(req)
 	defer reqNotifier.leave()
 	wsServer := websocket.Server{
@@ -411,18 +492,30 @@ func (srv *Server) serveConn(wsConn *websocket.Conn, reqNotifier *requestNotifie
 	if loggo.GetLogger("juju.rpc.jsoncodec").EffectiveLogLevel() <= loggo.TRACE {
 		codec.SetLogging(true)
 	}
-	var notifier rpc.RequestNotifier
-	if logger.EffectiveLogLevel() <= loggo.DEBUG {
-		// Incur request monitoring overhead only if we
-		// know we'll need it.
-		notifier = reqNotifier
+	// The notifier is always installed so that per-method call
+	// metrics are recorded; it only incurs the overhead of
+	// formatting requests when debug logging is enabled.
+	conn := rpc.NewConn(codec, reqNotifier)
+
+	var err error
+	if srv.connLimiter != nil {
+		if srv.connLimiter.Acquire() {
+			defer srv.connLimiter.Release()
+		} else {
+			logger.Debugf("too many API connections, try again later")
+			err = srv.tryAgainError()
+		}
 	}
-	conn := rpc.NewConn(codec, notifier)
-
 	var h *apiHandler
-	st, _, err := validateEnvironUUID(validateArgs{st: srv.state, envUUID: envUUID})
 	if err == nil {
-		h, err = newApiHandler(srv, st, conn, reqNotifier)
+		var st *state.State
+		st, _, err = validateEnvironUUID(validateArgs{st: srv.state, envUUID: envUUID})
+		if err == nil {
+			// Transactions run for the connection are logged
+			// with the trace id of the client's operation.
+			st = st.WithTraceId(reqNotifier.traceId)
+			h, err = newApiHandler(srv, st, conn, reqNotifier)
+		}
 	}
 	if err != nil {
 		conn.Serve(&errRoot{err}, serverError)
@@ -441,6 +534,12 @@ func (srv *Server) serveConn(wsConn *websocket.Conn, reqNotifier *requestNotifie
 	return conn.Close()
 }
 
+// tryAgainError returns the error sent to clients that are
+// turned away because the server is busy.
+func (srv *Server) tryAgainError() error {
+	return &common.TryAgainError{RetryAfter: srv.retryDelay}
+}
+
 func (srv *Server) mongoPinger() error {
 	timer := time.NewTimer(0)
 	session := srv.state.MongoSession()
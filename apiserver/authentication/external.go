@@ -0,0 +1,63 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package authentication
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/identity"
+)
+
+var logger = loggo.GetLogger("juju.apiserver.authentication")
+
+// ExternalUserAuthenticator performs authentication for remote users,
+// whose identity is vouched for by an external identity service rather
+// than checked against a password held in state.
+type ExternalUserAuthenticator struct {
+	// RootKey holds the key used to sign the macaroons
+	// issued to remote users.
+	RootKey []byte
+
+	// EnvironUUID holds the UUID of the environment
+	// that users are logging in to.
+	EnvironUUID string
+
+	// ServiceLocation holds the URL of the trusted identity service.
+	ServiceLocation string
+
+	// ServiceKey holds the public key of the trusted identity service.
+	ServiceKey *identity.PublicKey
+}
+
+// Authenticate checks that the credentials hold a macaroon for the
+// environment, discharged by the identity service for the given user,
+// and returns the user's identity, which holds the groups the user
+// belongs to. If there are no credentials, it returns a
+// *common.DischargeRequiredError holding a new macaroon for the user
+// to have discharged.
+func (a *ExternalUserAuthenticator) Authenticate(user names.UserTag, credentials string) (*identity.Identity, error) {
+	if user.IsLocal() {
+		return nil, common.ErrBadRequest
+	}
+	if credentials == "" {
+		m, err := identity.NewMacaroon(a.RootKey, a.EnvironUUID, a.ServiceLocation, a.ServiceKey)
+		if err != nil {
+			return nil, errors.Annotate(err, "cannot create macaroon")
+		}
+		return nil, &common.DischargeRequiredError{Macaroon: m}
+	}
+	id, err := identity.Verify(a.RootKey, a.EnvironUUID, credentials)
+	if err != nil {
+		logger.Debugf("bad credentials for %q: %v", user.Username(), err)
+		return nil, common.ErrBadCreds
+	}
+	if id.User != user.Username() {
+		logger.Debugf("credentials for %q used by %q", id.User, user.Username())
+		return nil, common.ErrBadCreds
+	}
+	return id, nil
+}
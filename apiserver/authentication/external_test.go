@@ -0,0 +1,129 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package authentication_test
+
+import (
+	"time"
+
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/authentication"
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/identity"
+	identitytesting "github.com/juju/juju/identity/testing"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type externalAuthenticatorSuite struct {
+	coretesting.BaseSuite
+	service       *identitytesting.Service
+	authenticator *authentication.ExternalUserAuthenticator
+}
+
+var _ = gc.Suite(&externalAuthenticatorSuite{})
+
+const testEnvironUUID = "deadbeef-0bad-400d-8000-4b1d0d06f00d"
+
+func (s *externalAuthenticatorSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	service, err := identitytesting.NewService("sso")
+	c.Assert(err, jc.ErrorIsNil)
+	service.AddUser("bob", "secret", "devops", "dba")
+	service.AddUser("alice", "secret")
+	s.service = service
+	s.authenticator = s.newAuthenticator(c, testEnvironUUID, service)
+}
+
+func (s *externalAuthenticatorSuite) newAuthenticator(c *gc.C, envUUID string, service *identitytesting.Service) *authentication.ExternalUserAuthenticator {
+	key, err := identity.ParsePublicKey(service.PublicKey())
+	c.Assert(err, jc.ErrorIsNil)
+	return &authentication.ExternalUserAuthenticator{
+		RootKey:         []byte("0123456789abcdef0123456789abcdef"),
+		EnvironUUID:     envUUID,
+		ServiceLocation: "https://sso.invalid",
+		ServiceKey:      key,
+	}
+}
+
+// credentials logs in as the given user without credentials, and
+// returns the discharge-required macaroon, discharged for the named
+// identity service user, as credentials.
+func (s *externalAuthenticatorSuite) credentials(c *gc.C, a *authentication.ExternalUserAuthenticator, service *identitytesting.Service, name string) string {
+	_, err := a.Authenticate(names.NewUserTag(name+"@sso"), "")
+	c.Assert(err, gc.FitsTypeOf, &common.DischargeRequiredError{})
+	m := err.(*common.DischargeRequiredError).Macaroon
+	location, caveatId, err := identity.ThirdPartyCaveat(m)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(location, gc.Equals, "https://sso.invalid")
+	d, err := service.Discharge(name, caveatId)
+	c.Assert(err, jc.ErrorIsNil)
+	credentials, err := identity.EncodeCredentials(m, d)
+	c.Assert(err, jc.ErrorIsNil)
+	return credentials
+}
+
+func (s *externalAuthenticatorSuite) TestValidLogin(c *gc.C) {
+	credentials := s.credentials(c, s.authenticator, s.service, "bob")
+	id, err := s.authenticator.Authenticate(names.NewUserTag("bob@sso"), credentials)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(id.User, gc.Equals, "bob@sso")
+	c.Assert(id.Groups, jc.DeepEquals, []string{"devops", "dba"})
+}
+
+func (s *externalAuthenticatorSuite) TestInvalidLogins(c *gc.C) {
+	bobCredentials := s.credentials(c, s.authenticator, s.service, "bob")
+	otherEnvCredentials := s.credentials(c, s.newAuthenticator(c, "other-uuid", s.service), s.service, "bob")
+	s.service.SetExpiry(-time.Minute)
+	expiredCredentials := s.credentials(c, s.authenticator, s.service, "bob")
+
+	otherService, err := identitytesting.NewService("sso")
+	c.Assert(err, jc.ErrorIsNil)
+	otherService.AddUser("bob", "secret")
+	otherServer := s.newAuthenticator(c, testEnvironUUID, otherService)
+	otherServer.RootKey = []byte("another root key")
+	otherServerCredentials := s.credentials(c, otherServer, otherService, "bob")
+
+	for i, test := range []struct {
+		about       string
+		user        string
+		credentials string
+		err         string
+	}{{
+		about:       "local user",
+		user:        "bob",
+		credentials: bobCredentials,
+		err:         "invalid request",
+	}, {
+		about:       "password",
+		user:        "bob@sso",
+		credentials: "secret",
+		err:         "invalid entity name or password",
+	}, {
+		about:       "another user's credentials",
+		user:        "alice@sso",
+		credentials: bobCredentials,
+		err:         "invalid entity name or password",
+	}, {
+		about:       "another environment's credentials",
+		user:        "bob@sso",
+		credentials: otherEnvCredentials,
+		err:         "invalid entity name or password",
+	}, {
+		about:       "expired credentials",
+		user:        "bob@sso",
+		credentials: expiredCredentials,
+		err:         "invalid entity name or password",
+	}, {
+		about:       "another server's credentials",
+		user:        "bob@sso",
+		credentials: otherServerCredentials,
+		err:         "invalid entity name or password",
+	}} {
+		c.Logf("test %d: %s", i, test.about)
+		_, err := s.authenticator.Authenticate(names.NewUserTag(test.user), test.credentials)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
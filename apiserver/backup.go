@@ -39,8 +39,9 @@ func (h *backupHandler) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
 	}
 	defer stateWrapper.cleanup()
 
-	if err := stateWrapper.authenticate(req); err != nil {
-		h.authError(resp, h)
+	// Backups hold the environment's secrets, and restoring one
+	// replaces the environment, so only administrators may use them.
+	if !stateWrapper.authenticateAccess(resp, req, h, state.EnvironAdminAccess) {
 		return
 	}
 
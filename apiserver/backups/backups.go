@@ -32,7 +32,9 @@ type API struct {
 
 // NewAPI creates a new instance of the Backups API facade.
 func NewAPI(st *state.State, resources *common.Resources, authorizer common.Authorizer) (*API, error) {
-	if !authorizer.AuthClient() {
+	// Backups hold the environment's secrets, so
+	// only environment administrators may use them.
+	if !authorizer.AuthEnvironAccess(state.EnvironAdminAccess) {
 		return nil, errors.Trace(common.ErrPerm)
 	}
 
@@ -52,8 +52,7 @@ func (h *charmsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	switch r.Method {
 	case "POST":
-		if err := stateWrapper.authenticate(r); err != nil {
-			h.authError(w, h)
+		if !stateWrapper.authenticateAccess(w, r, h, state.EnvironWriteAccess) {
 			return
 		}
 		// Add a local charm to the store provider.
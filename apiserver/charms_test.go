@@ -71,7 +71,7 @@ func (s *authHttpSuite) setupOtherEnvironment(c *gc.C) *state.State {
 	envState := s.Factory.MakeEnvironment(c, nil)
 	s.AddCleanup(func(*gc.C) { envState.Close() })
 	user := s.Factory.MakeUser(c, nil)
-	_, err := envState.AddEnvironmentUser(user.UserTag(), s.userTag)
+	_, err := envState.AddEnvironmentUser(user.UserTag(), s.userTag, state.EnvironAdminAccess)
 	c.Assert(err, jc.ErrorIsNil)
 	s.userTag = user.UserTag()
 	s.password = "password"
@@ -79,6 +79,13 @@ func (s *authHttpSuite) setupOtherEnvironment(c *gc.C) *state.State {
 	return envState
 }
 
+func (s *authHttpSuite) setUserAccess(c *gc.C, access state.EnvironmentAccess) {
+	envUser, err := s.State.EnvironmentUser(s.userTag)
+	c.Assert(err, jc.ErrorIsNil)
+	err = envUser.SetAccess(access)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 func (s *authHttpSuite) authRequest(c *gc.C, method, uri, contentType string, body io.Reader) (*http.Response, error) {
 	return s.sendRequest(c, s.userTag.String(), s.password, method, uri, contentType, body)
 }
@@ -161,6 +168,13 @@ func (s *charmsSuite) TestAuthRequiresUser(c *gc.C) {
 	s.assertErrorResponse(c, resp, http.StatusBadRequest, "expected series=URL argument")
 }
 
+func (s *charmsSuite) TestUploadRequiresWriteAccess(c *gc.C) {
+	s.setUserAccess(c, state.EnvironReadAccess)
+	resp, err := s.authRequest(c, "POST", s.charmsURI(c, ""), "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertErrorResponse(c, resp, http.StatusForbidden, "permission denied")
+}
+
 func (s *charmsSuite) TestUploadRequiresSeries(c *gc.C) {
 	resp, err := s.authRequest(c, "POST", s.charmsURI(c, ""), "", nil)
 	c.Assert(err, jc.ErrorIsNil)
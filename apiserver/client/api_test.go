@@ -197,7 +197,8 @@ var scenarioStatus = &api.Status{
 	},
 	Services: map[string]api.ServiceStatus{
 		"logging": api.ServiceStatus{
-			Charm: "local:quantal/logging-1",
+			Charm:  "local:quantal/logging-1",
+			Status: api.WorkloadStatus{Status: "unknown"},
 			Relations: map[string][]string{
 				"logging-directory": []string{"wordpress"},
 			},
@@ -208,9 +209,11 @@ var scenarioStatus = &api.Status{
 			Relations:     map[string][]string{},
 			SubordinateTo: []string{},
 			Units:         map[string]api.UnitStatus{},
+			Status:        api.WorkloadStatus{Status: "unknown"},
 		},
 		"wordpress": api.ServiceStatus{
-			Charm: "local:quantal/wordpress-3",
+			Charm:  "local:quantal/wordpress-3",
+			Status: api.WorkloadStatus{Status: "unknown"},
 			Relations: map[string][]string{
 				"logging-dir": []string{"logging"},
 			},
@@ -222,6 +225,7 @@ var scenarioStatus = &api.Status{
 						Info:   "blam",
 						Data:   map[string]interface{}{"relation-id": "0"},
 					},
+					Workload:       api.WorkloadStatus{Status: "unknown"},
 					AgentState:     "down",
 					AgentStateInfo: "(error: blam)",
 					Machine:        "1",
@@ -231,6 +235,7 @@ var scenarioStatus = &api.Status{
 								Status: "allocating",
 								Data:   make(map[string]interface{}),
 							},
+							Workload:   api.WorkloadStatus{Status: "unknown"},
 							AgentState: "allocating",
 						},
 					},
@@ -240,6 +245,7 @@ var scenarioStatus = &api.Status{
 						Status: "allocating",
 						Data:   make(map[string]interface{}),
 					},
+					Workload:   api.WorkloadStatus{Status: "unknown"},
 					AgentState: "allocating",
 					Machine:    "2",
 					Subordinates: map[string]api.UnitStatus{
@@ -248,6 +254,7 @@ var scenarioStatus = &api.Status{
 								Status: "allocating",
 								Data:   make(map[string]interface{}),
 							},
+							Workload:   api.WorkloadStatus{Status: "unknown"},
 							AgentState: "allocating",
 						},
 					},
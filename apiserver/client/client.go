@@ -20,6 +20,7 @@ import (
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/highavailability"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/environs/manual"
 	"github.com/juju/juju/instance"
@@ -34,6 +35,8 @@ import (
 
 func init() {
 	common.RegisterStandardFacade("Client", 0, NewClient)
+	// Version 1 accepts a hook timeout in ServiceUpdate.
+	common.RegisterStandardFacade("Client", 1, NewClient)
 }
 
 var (
@@ -79,6 +82,15 @@ func NewClient(st *state.State, resources *common.Resources, authorizer common.A
 		check: common.NewBlockChecker(st)}, nil
 }
 
+// checkAccess returns common.ErrPerm if the authenticated user does
+// not have at least the given level of access to the environment.
+func (c *Client) checkAccess(access state.EnvironmentAccess) error {
+	if !c.api.auth.AuthEnvironAccess(access) {
+		return common.ErrPerm
+	}
+	return nil
+}
+
 func (c *Client) WatchAll() (params.AllWatcherId, error) {
 	w := c.api.state.Watch()
 	return params.AllWatcherId{
@@ -92,6 +104,9 @@ func (c *Client) WatchAll() (params.AllWatcherId, error) {
 // (Deprecated) Use NewServiceSetForClientAPI instead, to preserve values set to
 // an empty string, and use ServiceUnset to unset values.
 func (c *Client) ServiceSet(p params.ServiceSet) error {
+	if err := c.checkAccess(state.EnvironWriteAccess); err != nil {
+		return errors.Trace(err)
+	}
 	if err := c.check.ChangeAllowed(); err != nil {
 		return errors.Trace(err)
 	}
@@ -111,6 +126,9 @@ func (c *Client) ServiceSet(p params.ServiceSet) error {
 // when the GUI handles the new behavior.
 // TODO(mattyw, all): This api call should be move to the new service facade. The client api version will then need bumping.
 func (c *Client) NewServiceSetForClientAPI(p params.ServiceSet) error {
+	if err := c.checkAccess(state.EnvironWriteAccess); err != nil {
+		return errors.Trace(err)
+	}
 	svc, err := c.api.state.Service(p.ServiceName)
 	if err != nil {
 		return err
@@ -121,6 +139,9 @@ func (c *Client) NewServiceSetForClientAPI(p params.ServiceSet) error {
 // ServiceUnset implements the server side of Client.ServiceUnset.
 // TODO(mattyw, all): This api call should be move to the new service facade. The client api version will then need bumping.
 func (c *Client) ServiceUnset(p params.ServiceUnset) error {
+	if err := c.checkAccess(state.EnvironWriteAccess); err != nil {
+		return errors.Trace(err)
+	}
 	if err := c.check.ChangeAllowed(); err != nil {
 		return errors.Trace(err)
 	}
@@ -138,6 +159,9 @@ func (c *Client) ServiceUnset(p params.ServiceUnset) error {
 // ServiceSetYAML implements the server side of Client.ServerSetYAML.
 // TODO(mattyw, all): This api call should be move to the new service facade. The client api version will then need bumping.
 func (c *Client) ServiceSetYAML(p params.ServiceSetYAML) error {
+	if err := c.checkAccess(state.EnvironWriteAccess); err != nil {
+		return errors.Trace(err)
+	}
 	if err := c.check.ChangeAllowed(); err != nil {
 		return errors.Trace(err)
 	}
@@ -169,6 +193,9 @@ func (c *Client) ServiceCharmRelations(p params.ServiceCharmRelations) (params.S
 
 // Resolved implements the server side of Client.Resolved.
 func (c *Client) Resolved(p params.Resolved) error {
+	if err := c.checkAccess(state.EnvironWriteAccess); err != nil {
+		return errors.Trace(err)
+	}
 	if err := c.check.ChangeAllowed(); err != nil {
 		return errors.Trace(err)
 	}
@@ -239,6 +266,9 @@ func (c *Client) PrivateAddress(p params.PrivateAddress) (results params.Private
 // were also explicitly marked by units as open.
 // TODO(mattyw, all): This api call should be move to the new service facade. The client api version will then need bumping.
 func (c *Client) ServiceExpose(args params.ServiceExpose) error {
+	if err := c.checkAccess(state.EnvironWriteAccess); err != nil {
+		return errors.Trace(err)
+	}
 	if err := c.check.ChangeAllowed(); err != nil {
 		return errors.Trace(err)
 	}
@@ -253,6 +283,9 @@ func (c *Client) ServiceExpose(args params.ServiceExpose) error {
 // were also explicitly marked by units as open.
 // TODO(mattyw, all): This api call should be move to the new service facade. The client api version will then need bumping.
 func (c *Client) ServiceUnexpose(args params.ServiceUnexpose) error {
+	if err := c.checkAccess(state.EnvironWriteAccess); err != nil {
+		return errors.Trace(err)
+	}
 	if err := c.check.ChangeAllowed(); err != nil {
 		return errors.Trace(err)
 	}
@@ -282,6 +315,9 @@ func networkTagsToNames(tags []string) ([]string, error) {
 // before calling ServiceDeploy, although for backward compatibility
 // this is not necessary until 1.16 support is removed.
 func (c *Client) ServiceDeploy(args params.ServiceDeploy) error {
+	if err := c.checkAccess(state.EnvironWriteAccess); err != nil {
+		return errors.Trace(err)
+	}
 	if err := c.check.ChangeAllowed(); err != nil {
 		return errors.Trace(err)
 	}
@@ -408,9 +444,12 @@ func validateCharmStorage(args params.ServiceDeploy, ch *state.Charm) error {
 }
 
 // ServiceUpdate updates the service attributes, including charm URL,
-// minimum number of units, settings and constraints.
+// minimum number of units, hook timeout, settings and constraints.
 // All parameters in params.ServiceUpdate except the service name are optional.
 func (c *Client) ServiceUpdate(args params.ServiceUpdate) error {
+	if err := c.checkAccess(state.EnvironWriteAccess); err != nil {
+		return errors.Trace(err)
+	}
 	if !args.ForceCharmUrl {
 		if err := c.check.ChangeAllowed(); err != nil {
 			return errors.Trace(err)
@@ -432,6 +471,12 @@ func (c *Client) ServiceUpdate(args params.ServiceUpdate) error {
 			return err
 		}
 	}
+	// Override the environment's hook timeout for the given service.
+	if args.HookTimeout != nil {
+		if err = service.SetHookTimeout(*args.HookTimeout); err != nil {
+			return err
+		}
+	}
 	// Set up service's settings.
 	if args.SettingsYAML != "" {
 		if err = serviceSetSettingsYAML(service, args.SettingsYAML); err != nil {
@@ -540,6 +585,9 @@ func newServiceSetSettingsStringsForClientAPI(service *state.Service, settings m
 // ServiceSetCharm sets the charm for a given service.
 // TODO(mattyw, all): This api call should be move to the new service facade. The client api version will then need bumping.
 func (c *Client) ServiceSetCharm(args params.ServiceSetCharm) error {
+	if err := c.checkAccess(state.EnvironWriteAccess); err != nil {
+		return errors.Trace(err)
+	}
 	// when forced, don't block
 	if !args.Force {
 		if err := c.check.ChangeAllowed(); err != nil {
@@ -553,6 +601,52 @@ func (c *Client) ServiceSetCharm(args params.ServiceSetCharm) error {
 	return c.serviceSetCharm(service, args.CharmUrl, args.Force)
 }
 
+// ServiceSetCharmRollout sets the charm for a given service, allowing
+// its existing units to upgrade only a batch at a time.
+func (c *Client) ServiceSetCharmRollout(args params.ServiceSetCharmRollout) error {
+	if err := c.checkAccess(state.EnvironWriteAccess); err != nil {
+		return errors.Trace(err)
+	}
+	// when forced, don't block
+	if !args.Force {
+		if err := c.check.ChangeAllowed(); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	service, err := c.api.state.Service(args.ServiceName)
+	if err != nil {
+		return err
+	}
+	curl, err := charm.ParseURL(args.CharmUrl)
+	if err != nil {
+		return err
+	}
+	sch, err := c.api.state.Charm(curl)
+	if err != nil {
+		return err
+	}
+	return service.SetCharmRollout(sch, args.Force, args.BatchSize, args.WaitHealthy)
+}
+
+// ServiceRollbackCharm sets the charm for a given service back to the
+// one it was using before its most recent charm change.
+func (c *Client) ServiceRollbackCharm(args params.ServiceRollbackCharm) error {
+	if err := c.checkAccess(state.EnvironWriteAccess); err != nil {
+		return errors.Trace(err)
+	}
+	// when forced, don't block
+	if !args.Force {
+		if err := c.check.ChangeAllowed(); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	service, err := c.api.state.Service(args.ServiceName)
+	if err != nil {
+		return err
+	}
+	return service.RollbackCharm(args.Force)
+}
+
 // addServiceUnits adds a given number of units to a service.
 func addServiceUnits(state *state.State, args params.AddServiceUnits) ([]*state.Unit, error) {
 	service, err := state.Service(args.ServiceName)
@@ -577,6 +671,9 @@ func addServiceUnits(state *state.State, args params.AddServiceUnits) ([]*state.
 
 // AddServiceUnits adds a given number of units to a service.
 func (c *Client) AddServiceUnits(args params.AddServiceUnits) (params.AddServiceUnitsResults, error) {
+	if err := c.checkAccess(state.EnvironWriteAccess); err != nil {
+		return params.AddServiceUnitsResults{}, errors.Trace(err)
+	}
 	if err := c.check.ChangeAllowed(); err != nil {
 		return params.AddServiceUnitsResults{}, errors.Trace(err)
 	}
@@ -593,6 +690,9 @@ func (c *Client) AddServiceUnits(args params.AddServiceUnits) (params.AddService
 
 // DestroyServiceUnits removes a given set of service units.
 func (c *Client) DestroyServiceUnits(args params.DestroyServiceUnits) error {
+	if err := c.checkAccess(state.EnvironWriteAccess); err != nil {
+		return errors.Trace(err)
+	}
 	if err := c.check.RemoveAllowed(); err != nil {
 		return errors.Trace(err)
 	}
@@ -620,6 +720,9 @@ func (c *Client) DestroyServiceUnits(args params.DestroyServiceUnits) error {
 // ServiceDestroy destroys a given service.
 // TODO(mattyw, all): This api call should be move to the new service facade. The client api version will then need bumping.
 func (c *Client) ServiceDestroy(args params.ServiceDestroy) error {
+	if err := c.checkAccess(state.EnvironWriteAccess); err != nil {
+		return errors.Trace(err)
+	}
 	if err := c.check.RemoveAllowed(); err != nil {
 		return errors.Trace(err)
 	}
@@ -653,6 +756,9 @@ func (c *Client) GetEnvironmentConstraints() (params.GetConstraintsResults, erro
 // SetServiceConstraints sets the constraints for a given service.
 // TODO(mattyw, all): This api call should be move to the new service facade. The client api version will then need bumping.
 func (c *Client) SetServiceConstraints(args params.SetConstraints) error {
+	if err := c.checkAccess(state.EnvironWriteAccess); err != nil {
+		return errors.Trace(err)
+	}
 	if err := c.check.ChangeAllowed(); err != nil {
 		return errors.Trace(err)
 	}
@@ -663,8 +769,28 @@ func (c *Client) SetServiceConstraints(args params.SetConstraints) error {
 	return svc.SetConstraints(args.Constraints)
 }
 
+// SetMachineConstraints sets the constraints for a given machine. The
+// constraints of a provisioned machine can only be changed if it is a
+// container, in which case its resource limits are updated to match.
+func (c *Client) SetMachineConstraints(args params.SetMachineConstraints) error {
+	if err := c.checkAccess(state.EnvironWriteAccess); err != nil {
+		return errors.Trace(err)
+	}
+	if err := c.check.ChangeAllowed(); err != nil {
+		return errors.Trace(err)
+	}
+	machine, err := c.api.state.Machine(args.MachineId)
+	if err != nil {
+		return err
+	}
+	return machine.SetConstraints(args.Constraints)
+}
+
 // SetEnvironmentConstraints sets the constraints for the environment.
 func (c *Client) SetEnvironmentConstraints(args params.SetConstraints) error {
+	if err := c.checkAccess(state.EnvironAdminAccess); err != nil {
+		return errors.Trace(err)
+	}
 	if err := c.check.ChangeAllowed(); err != nil {
 		return errors.Trace(err)
 	}
@@ -673,6 +799,9 @@ func (c *Client) SetEnvironmentConstraints(args params.SetConstraints) error {
 
 // AddRelation adds a relation between the specified endpoints and returns the relation info.
 func (c *Client) AddRelation(args params.AddRelation) (params.AddRelationResults, error) {
+	if err := c.checkAccess(state.EnvironWriteAccess); err != nil {
+		return params.AddRelationResults{}, errors.Trace(err)
+	}
 	if err := c.check.ChangeAllowed(); err != nil {
 		return params.AddRelationResults{}, errors.Trace(err)
 	}
@@ -697,6 +826,9 @@ func (c *Client) AddRelation(args params.AddRelation) (params.AddRelationResults
 
 // DestroyRelation removes the relation between the specified endpoints.
 func (c *Client) DestroyRelation(args params.DestroyRelation) error {
+	if err := c.checkAccess(state.EnvironWriteAccess); err != nil {
+		return errors.Trace(err)
+	}
 	if err := c.check.RemoveAllowed(); err != nil {
 		return errors.Trace(err)
 	}
@@ -718,6 +850,9 @@ func (c *Client) AddMachines(args params.AddMachines) (params.AddMachinesResults
 
 // AddMachinesV2 adds new machines with the supplied parameters.
 func (c *Client) AddMachinesV2(args params.AddMachines) (params.AddMachinesResults, error) {
+	if err := c.checkAccess(state.EnvironWriteAccess); err != nil {
+		return params.AddMachinesResults{}, errors.Trace(err)
+	}
 	results := params.AddMachinesResults{
 		Machines: make([]params.AddMachinesResult, len(args.MachineParams)),
 	}
@@ -871,6 +1006,9 @@ func machineJobFromParams(job multiwatcher.MachineJob) (state.MachineJob, error)
 // ProvisioningScript returns a shell script that, when run,
 // provisions a machine agent on the machine executing the script.
 func (c *Client) ProvisioningScript(args params.ProvisioningScriptParams) (params.ProvisioningScriptResult, error) {
+	if err := c.checkAccess(state.EnvironWriteAccess); err != nil {
+		return params.ProvisioningScriptResult{}, errors.Trace(err)
+	}
 	var result params.ProvisioningScriptResult
 	mcfg, err := MachineConfig(c.api.state, args.MachineId, args.Nonce, args.DataDir)
 	if err != nil {
@@ -899,6 +1037,9 @@ func (c *Client) ProvisioningScript(args params.ProvisioningScriptParams) (param
 
 // DestroyMachines removes a given set of machines.
 func (c *Client) DestroyMachines(args params.DestroyMachines) error {
+	if err := c.checkAccess(state.EnvironWriteAccess); err != nil {
+		return errors.Trace(err)
+	}
 	var errs []string
 	for _, id := range args.MachineNames {
 		machine, err := c.api.state.Machine(id)
@@ -969,6 +1110,9 @@ func (c *Client) EnvironmentInfo() (api.EnvironmentInfo, error) {
 
 // ShareEnvironment allows the given user(s) access to the environment.
 func (c *Client) ShareEnvironment(args params.ModifyEnvironUsers) (result params.ErrorResults, err error) {
+	if err := c.checkAccess(state.EnvironAdminAccess); err != nil {
+		return result, errors.Trace(err)
+	}
 	var createdBy names.UserTag
 	var ok bool
 	if createdBy, ok = c.api.auth.GetAuthTag().(names.UserTag); !ok {
@@ -991,7 +1135,7 @@ func (c *Client) ShareEnvironment(args params.ModifyEnvironUsers) (result params
 		}
 		switch arg.Action {
 		case params.AddEnvUser:
-			_, err := c.api.state.AddEnvironmentUser(user, createdBy)
+			_, err := c.api.state.AddEnvironmentUser(user, createdBy, environAccess(arg.Access))
 			if err != nil {
 				err = errors.Annotate(err, "could not share environment")
 				result.Results[i].Error = common.ServerError(err)
@@ -1002,6 +1146,15 @@ func (c *Client) ShareEnvironment(args params.ModifyEnvironUsers) (result params
 				err = errors.Annotate(err, "could not unshare environment")
 				result.Results[i].Error = common.ServerError(err)
 			}
+		case params.SetEnvUserAccess:
+			envUser, err := c.api.state.EnvironmentUser(user)
+			if err == nil {
+				err = envUser.SetAccess(state.EnvironmentAccess(arg.Access))
+			}
+			if err != nil {
+				err = errors.Annotate(err, "could not set environment access")
+				result.Results[i].Error = common.ServerError(err)
+			}
 		default:
 			result.Results[i].Error = common.ServerError(errors.Errorf("unknown action %q", arg.Action))
 		}
@@ -1009,6 +1162,52 @@ func (c *Client) ShareEnvironment(args params.ModifyEnvironUsers) (result params
 	return result, nil
 }
 
+// ShareEnvironmentGroups grants or revokes access to the environment
+// for the members of groups defined by an external identity service.
+func (c *Client) ShareEnvironmentGroups(args params.ModifyEnvironGroups) (result params.ErrorResults, err error) {
+	if err := c.checkAccess(state.EnvironAdminAccess); err != nil {
+		return result, errors.Trace(err)
+	}
+	createdBy, ok := c.api.auth.GetAuthTag().(names.UserTag)
+	if !ok {
+		return result, errors.Errorf("api connection is not through a user")
+	}
+
+	result = params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Changes)),
+	}
+	for i, arg := range args.Changes {
+		switch arg.Action {
+		case params.AddEnvUser:
+			_, err := c.api.state.AddEnvironmentGroup(arg.Group, createdBy, environAccess(arg.Access))
+			if err != nil {
+				err = errors.Annotate(err, "could not share environment")
+				result.Results[i].Error = common.ServerError(err)
+			}
+		case params.RemoveEnvUser:
+			err := c.api.state.RemoveEnvironmentGroup(arg.Group)
+			if err != nil {
+				err = errors.Annotate(err, "could not unshare environment")
+				result.Results[i].Error = common.ServerError(err)
+			}
+		default:
+			result.Results[i].Error = common.ServerError(errors.Errorf("unknown action %q", arg.Action))
+		}
+	}
+	return result, nil
+}
+
+// environAccess returns the level of access to the environment
+// requested by a client. Clients that do not specify a level of
+// access are given admin access, as all users were before access
+// levels were introduced.
+func environAccess(access params.EnvironAccess) state.EnvironmentAccess {
+	if access == "" {
+		return state.EnvironAdminAccess
+	}
+	return state.EnvironmentAccess(access)
+}
+
 // GetAnnotations returns annotations about a given entity.
 // This API is now deprecated - "Annotations" client should be used instead.
 // TODO(anastasiamac) remove for Juju 2.x
@@ -1056,6 +1255,9 @@ func (c *Client) findEntity(tag names.Tag) (state.GlobalEntity, error) {
 // This API is now deprecated - "Annotations" client should be used instead.
 // TODO(anastasiamac) remove for Juju 2.x
 func (c *Client) SetAnnotations(args params.SetAnnotations) error {
+	if err := c.checkAccess(state.EnvironWriteAccess); err != nil {
+		return errors.Trace(err)
+	}
 	tag, err := c.parseEntityTag(args.Tag)
 	if err != nil {
 		return errors.Trace(err)
@@ -1106,7 +1308,9 @@ func (c *Client) AgentVersion() (params.AgentVersionResult, error) {
 }
 
 // EnvironmentGet implements the server-side part of the
-// get-environment CLI command.
+// get-environment CLI command. Only users with admin access to the
+// environment see the provider's secret attributes, such as its
+// credentials.
 func (c *Client) EnvironmentGet() (params.EnvironmentGetResults, error) {
 	result := params.EnvironmentGetResults{}
 	// Get the existing environment config from the state.
@@ -1114,13 +1318,30 @@ func (c *Client) EnvironmentGet() (params.EnvironmentGetResults, error) {
 	if err != nil {
 		return result, err
 	}
-	result.Config = config.AllAttrs()
+	attrs := config.AllAttrs()
+	if err := c.checkAccess(state.EnvironAdminAccess); err != nil {
+		provider, err := environs.Provider(config.Type())
+		if err != nil {
+			return result, errors.Trace(err)
+		}
+		secretAttrs, err := provider.SecretAttrs(config)
+		if err != nil {
+			return result, errors.Trace(err)
+		}
+		for name := range secretAttrs {
+			delete(attrs, name)
+		}
+	}
+	result.Config = attrs
 	return result, nil
 }
 
 // EnvironmentSet implements the server-side part of the
 // set-environment CLI command.
 func (c *Client) EnvironmentSet(args params.EnvironmentSet) error {
+	if err := c.checkAccess(state.EnvironAdminAccess); err != nil {
+		return errors.Trace(err)
+	}
 	if err := c.check.ChangeAllowed(); err != nil {
 		// if trying to change value for block-changes, we would want to let it go.
 		if v, present := args.Config[config.PreventAllChangesKey]; !present {
@@ -1152,6 +1373,9 @@ func (c *Client) EnvironmentSet(args params.EnvironmentSet) error {
 // EnvironmentUnset implements the server-side part of the
 // set-environment CLI command.
 func (c *Client) EnvironmentUnset(args params.EnvironmentUnset) error {
+	if err := c.checkAccess(state.EnvironAdminAccess); err != nil {
+		return errors.Trace(err)
+	}
 	if err := c.check.ChangeAllowed(); err != nil {
 		return errors.Trace(err)
 	}
@@ -1163,6 +1387,9 @@ func (c *Client) EnvironmentUnset(args params.EnvironmentUnset) error {
 
 // SetEnvironAgentVersion sets the environment agent version.
 func (c *Client) SetEnvironAgentVersion(args params.SetEnvironAgentVersion) error {
+	if err := c.checkAccess(state.EnvironAdminAccess); err != nil {
+		return errors.Trace(err)
+	}
 	if err := c.check.ChangeAllowed(); err != nil {
 		return errors.Trace(err)
 	}
@@ -1172,6 +1399,9 @@ func (c *Client) SetEnvironAgentVersion(args params.SetEnvironAgentVersion) erro
 // AbortCurrentUpgrade aborts and archives the current upgrade
 // synchronisation record, if any.
 func (c *Client) AbortCurrentUpgrade() error {
+	if err := c.checkAccess(state.EnvironAdminAccess); err != nil {
+		return errors.Trace(err)
+	}
 	if err := c.check.ChangeAllowed(); err != nil {
 		return errors.Trace(err)
 	}
@@ -1199,6 +1429,9 @@ func destroyErr(desc string, ids, errs []string) error {
 // the environment, if it does not exist yet. Local charms are not
 // supported, only charm store URLs. See also AddLocalCharm().
 func (c *Client) AddCharm(args params.CharmURL) error {
+	if err := c.checkAccess(state.EnvironWriteAccess); err != nil {
+		return errors.Trace(err)
+	}
 	charmURL, err := charm.ParseURL(args.URL)
 	if err != nil {
 		return err
@@ -1336,6 +1569,9 @@ func charmArchiveStoragePath(curl *charm.URL) (string, error) {
 
 // RetryProvisioning marks a provisioning error as transient on the machines.
 func (c *Client) RetryProvisioning(p params.Entities) (params.ErrorResults, error) {
+	if err := c.checkAccess(state.EnvironWriteAccess); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
 	if err := c.check.ChangeAllowed(); err != nil {
 		return params.ErrorResults{}, errors.Trace(err)
 	}
@@ -1348,6 +1584,79 @@ func (c *Client) RetryProvisioning(p params.Entities) (params.ErrorResults, erro
 	})
 }
 
+// SetMachineMaintenance puts the given machines into maintenance mode,
+// or takes them out of it. No new units or containers are placed on a
+// machine while it is in maintenance.
+func (c *Client) SetMachineMaintenance(args params.SetMachineMaintenance) (params.ErrorResults, error) {
+	if err := c.checkAccess(state.EnvironWriteAccess); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	if err := c.check.ChangeAllowed(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Entities)),
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseMachineTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		machine, err := c.api.state.Machine(tag.Id())
+		if err == nil {
+			err = machine.SetMaintenance(args.Maintenance)
+		}
+		if err == nil && args.Maintenance && args.RelocateUnits {
+			err = relocateUnits(c.api.state, machine)
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// relocateUnits moves the stateless principal units assigned to the
+// given machine, which must be in maintenance, to other machines: each
+// is replaced by a new unit of its service, placed as "juju add-unit"
+// would place it, and is then destroyed. Units with storage are not
+// stateless, and units that are the only unit of their service are
+// not moved, so that the service keeps running.
+func relocateUnits(st *state.State, machine *state.Machine) error {
+	units, err := machine.Units()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, unit := range units {
+		if !unit.IsPrincipal() || unit.Life() != state.Alive || len(unit.StorageInstanceIds()) > 0 {
+			continue
+		}
+		service, err := unit.Service()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		serviceUnits, err := service.AllUnits()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		alive := 0
+		for _, u := range serviceUnits {
+			if u.Life() == state.Alive {
+				alive++
+			}
+		}
+		if alive < 2 {
+			continue
+		}
+		if _, err := jjj.AddUnits(st, service, 1, ""); err != nil {
+			return errors.Annotatef(err, "cannot relocate unit %q", unit.Name())
+		}
+		if err := unit.Destroy(); err != nil {
+			return errors.Annotatef(err, "cannot relocate unit %q", unit.Name())
+		}
+	}
+	return nil
+}
+
 // APIHostPorts returns the API host/port addresses stored in state.
 func (c *Client) APIHostPorts() (result params.APIHostPortsResult, err error) {
 	if result.Servers, err = c.api.state.APIHostPorts(); err != nil {
@@ -1360,6 +1669,9 @@ func (c *Client) APIHostPorts() (result params.APIHostPortsResult, err error) {
 // DEPRECATED: remove when we stop supporting 1.20 and earlier clients.
 // This API is now on the HighAvailability facade.
 func (c *Client) EnsureAvailability(args params.StateServersSpecs) (params.StateServersChangeResults, error) {
+	if err := c.checkAccess(state.EnvironAdminAccess); err != nil {
+		return params.StateServersChangeResults{}, errors.Trace(err)
+	}
 	if err := c.check.ChangeAllowed(); err != nil {
 		return params.StateServersChangeResults{}, errors.Trace(err)
 	}
@@ -1371,3 +1683,48 @@ func (c *Client) EnsureAvailability(args params.StateServersSpecs) (params.State
 	}
 	return results, nil
 }
+
+// HookHistory returns the hooks, actions and commands recorded for each
+// given unit, most recent first.
+func (c *Client) HookHistory(args params.Entities) (params.HookHistoryResults, error) {
+	result := params.HookHistoryResults{
+		Results: make([]params.HookHistoryResult, len(args.Entities)),
+	}
+	for i, entity := range args.Entities {
+		records, err := c.unitHookHistory(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		result.Results[i].Records = records
+	}
+	return result, nil
+}
+
+func (c *Client) unitHookHistory(tag string) ([]params.HookRecord, error) {
+	unitTag, err := names.ParseUnitTag(tag)
+	if err != nil {
+		return nil, err
+	}
+	unit, err := c.api.state.Unit(unitTag.Id())
+	if err != nil {
+		return nil, err
+	}
+	records, err := unit.HookHistory()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]params.HookRecord, len(records))
+	for i, record := range records {
+		result[i] = params.HookRecord{
+			Kind:       record.Kind,
+			Hook:       record.Hook,
+			Relation:   record.Relation,
+			RemoteUnit: record.RemoteUnit,
+			Started:    record.Started,
+			Duration:   record.Duration,
+			Result:     record.Result,
+		}
+	}
+	return result, nil
+}
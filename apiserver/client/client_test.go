@@ -10,6 +10,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/names"
@@ -309,6 +310,163 @@ func (s *serverSuite) TestShareEnvironmentInvalidAction(c *gc.C) {
 	c.Assert(result.Results[0].Error, gc.ErrorMatches, expectedErr)
 }
 
+func (s *serverSuite) TestShareEnvironmentWithAccess(c *gc.C) {
+	user := names.NewUserTag("foobar@ubuntuone")
+	args := params.ModifyEnvironUsers{
+		Changes: []params.ModifyEnvironUser{{
+			UserTag: user.String(),
+			Action:  params.AddEnvUser,
+			Access:  params.ReadEnvironAccess,
+		}}}
+
+	result, err := s.client.ShareEnvironment(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.OneError(), gc.IsNil)
+
+	envUser, err := s.State.EnvironmentUser(user)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(envUser.Access(), gc.Equals, state.EnvironReadAccess)
+}
+
+func (s *serverSuite) TestShareEnvironmentInvalidAccess(c *gc.C) {
+	args := params.ModifyEnvironUsers{
+		Changes: []params.ModifyEnvironUser{{
+			UserTag: names.NewUserTag("foobar@ubuntuone").String(),
+			Action:  params.AddEnvUser,
+			Access:  "superuser",
+		}}}
+
+	result, err := s.client.ShareEnvironment(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.OneError(), gc.ErrorMatches, `could not share environment: invalid environment access "superuser"`)
+}
+
+func (s *serverSuite) TestShareEnvironmentSetAccess(c *gc.C) {
+	user := s.Factory.MakeEnvUser(c, &factory.EnvUserParams{Access: state.EnvironWriteAccess})
+	args := params.ModifyEnvironUsers{
+		Changes: []params.ModifyEnvironUser{{
+			UserTag: user.UserTag().String(),
+			Action:  params.SetEnvUserAccess,
+			Access:  params.ReadEnvironAccess,
+		}, {
+			UserTag: names.NewUserTag("foobar@ubuntuone").String(),
+			Action:  params.SetEnvUserAccess,
+			Access:  params.ReadEnvironAccess,
+		}}}
+
+	result, err := s.client.ShareEnvironment(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 2)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Assert(result.Results[1].Error, gc.ErrorMatches, `could not set environment access: .*not found`)
+
+	envUser, err := s.State.EnvironmentUser(user.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(envUser.Access(), gc.Equals, state.EnvironReadAccess)
+}
+
+func (s *serverSuite) newClientWithAccess(c *gc.C, access state.EnvironmentAccess) *client.Client {
+	user := s.Factory.MakeEnvUser(c, &factory.EnvUserParams{Access: access})
+	auth := testing.FakeAuthorizer{
+		Tag:    user.UserTag(),
+		Access: access,
+	}
+	apiClient, err := client.NewClient(s.State, common.NewResources(), auth)
+	c.Assert(err, jc.ErrorIsNil)
+	return apiClient
+}
+
+func (s *serverSuite) TestReadAccess(c *gc.C) {
+	s.setUpScenario(c)
+	apiClient := s.newClientWithAccess(c, state.EnvironReadAccess)
+
+	_, err := apiClient.FullStatus(params.StatusParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	result, err := apiClient.EnvironmentGet()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Config["name"], gc.Equals, "dummyenv")
+	_, found := result.Config["secret"]
+	c.Assert(found, jc.IsFalse)
+
+	err = apiClient.ServiceExpose(params.ServiceExpose{ServiceName: "wordpress"})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	err = apiClient.ServiceDestroy(params.ServiceDestroy{ServiceName: "wordpress"})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	_, err = apiClient.AddServiceUnits(params.AddServiceUnits{ServiceName: "wordpress", NumUnits: 1})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	err = apiClient.DestroyEnvironment()
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *serverSuite) TestWriteAccess(c *gc.C) {
+	s.setUpScenario(c)
+	apiClient := s.newClientWithAccess(c, state.EnvironWriteAccess)
+
+	err := apiClient.ServiceExpose(params.ServiceExpose{ServiceName: "wordpress"})
+	c.Assert(err, jc.ErrorIsNil)
+	service, err := s.State.Service("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(service.IsExposed(), jc.IsTrue)
+
+	err = apiClient.EnvironmentSet(params.EnvironmentSet{Config: map[string]interface{}{"some-key": "value"}})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	_, err = apiClient.Run(params.RunParams{Commands: "hostname", Machines: []string{"0"}})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	_, err = apiClient.RunOnAllMachines(params.RunParams{Commands: "hostname"})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	_, err = apiClient.ShareEnvironment(params.ModifyEnvironUsers{})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	err = apiClient.DestroyEnvironment()
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *serverSuite) TestShareEnvironmentGroups(c *gc.C) {
+	args := params.ModifyEnvironGroups{
+		Changes: []params.ModifyEnvironGroup{{
+			Group:  "devops",
+			Action: params.AddEnvUser,
+		}}}
+
+	result, err := s.client.ShareEnvironmentGroups(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.OneError(), gc.IsNil)
+	c.Assert(result.Results, gc.HasLen, 1)
+
+	envGroup, err := s.State.EnvironmentGroup("devops")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(envGroup.GroupName(), gc.Equals, "devops")
+	c.Assert(envGroup.CreatedBy(), gc.Equals, dummy.AdminUserTag().Username())
+
+	args.Changes[0].Action = params.RemoveEnvUser
+	result, err = s.client.ShareEnvironmentGroups(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.OneError(), gc.IsNil)
+
+	_, err = s.State.EnvironmentGroup("devops")
+	c.Assert(errors.IsNotFound(err), jc.IsTrue)
+}
+
+func (s *serverSuite) TestShareEnvironmentGroupsErrors(c *gc.C) {
+	args := params.ModifyEnvironGroups{
+		Changes: []params.ModifyEnvironGroup{{
+			Group:  "-devops",
+			Action: params.AddEnvUser,
+		}, {
+			Group:  "dba",
+			Action: params.RemoveEnvUser,
+		}, {
+			Group:  "devops",
+			Action: "dance",
+		}}}
+
+	result, err := s.client.ShareEnvironmentGroups(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 3)
+	c.Assert(result.Results[0].Error, gc.ErrorMatches, `could not share environment: invalid group name "-devops"`)
+	c.Assert(result.Results[1].Error, gc.ErrorMatches, `could not unshare environment: environment group "dba" does not exist`)
+	c.Assert(result.Results[2].Error, gc.ErrorMatches, `unknown action "dance"`)
+}
+
 func (s *serverSuite) TestSetEnvironAgentVersion(c *gc.C) {
 	args := params.SetEnvironAgentVersion{
 		Version: version.MustParse("9.8.7"),
@@ -1834,6 +1992,23 @@ func (s *clientSuite) TestClientServiceUpdateSetMinUnitsError(c *gc.C) {
 	c.Assert(service.MinUnits(), gc.Equals, 0)
 }
 
+func (s *clientSuite) TestClientServiceUpdateSetHookTimeout(c *gc.C) {
+	service := s.AddTestingService(c, "dummy", s.AddTestingCharm(c, "dummy"))
+
+	// Override the hook timeout for the service.
+	timeout := 10 * time.Minute
+	args := params.ServiceUpdate{
+		ServiceName: "dummy",
+		HookTimeout: &timeout,
+	}
+	err := s.APIState.Client().ServiceUpdate(args)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Ensure the hook timeout has been set.
+	c.Assert(service.Refresh(), gc.IsNil)
+	c.Assert(service.HookTimeout(), gc.Equals, timeout)
+}
+
 func (s *clientSuite) TestClientServiceUpdateSetSettingsStrings(c *gc.C) {
 	service := s.AddTestingService(c, "dummy", s.AddTestingCharm(c, "dummy"))
 
@@ -2056,6 +2231,84 @@ func (s *clientSuite) TestBlockServiceSetCharmForce(c *gc.C) {
 	s.assertServiceSetCharmBlocked(c, false, true)
 }
 
+func (s *clientSuite) TestClientServiceSetCharmRollout(c *gc.C) {
+	s.setupServiceSetCharm(c)
+	err := s.APIState.Client().AddCharm(charm.MustParseURL("cs:precise/wordpress-3"))
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.APIState.Client().ServiceSetCharmRollout(
+		"service", "cs:precise/wordpress-3", false, 2, true,
+	)
+	c.Assert(err, jc.ErrorIsNil)
+
+	service, err := s.State.Service("service")
+	c.Assert(err, jc.ErrorIsNil)
+	charm, force, err := service.Charm()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(charm.URL().String(), gc.Equals, "cs:precise/wordpress-3")
+	c.Assert(force, jc.IsFalse)
+	rollout, ok := service.Rollout()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(rollout, jc.DeepEquals, state.RolloutStatus{
+		BatchSize:   2,
+		WaitHealthy: true,
+		Upgrading:   []string{"service/0", "service/1"},
+	})
+}
+
+func (s *clientSuite) TestClientServiceSetCharmRolloutUnknownCharm(c *gc.C) {
+	s.setupServiceSetCharm(c)
+	err := s.APIState.Client().ServiceSetCharmRollout(
+		"service", "cs:precise/wordpress-42", false, 2, false,
+	)
+	c.Assert(err, gc.ErrorMatches, `charm "cs:precise/wordpress-42" not found`)
+}
+
+func (s *clientSuite) TestBlockChangesServiceSetCharmRollout(c *gc.C) {
+	s.setupServiceSetCharm(c)
+	s.blockAllChanges(c)
+	err := s.APIState.Client().ServiceSetCharmRollout(
+		"service", "cs:precise/wordpress-3", false, 2, false,
+	)
+	c.Assert(errors.Cause(err), gc.DeepEquals, common.ErrOperationBlocked)
+}
+
+func (s *clientSuite) TestClientServiceRollbackCharm(c *gc.C) {
+	s.setupServiceSetCharm(c)
+	err := s.APIState.Client().ServiceSetCharm(
+		"service", "cs:precise/wordpress-3", false,
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.APIState.Client().ServiceRollbackCharm("service", false)
+	c.Assert(err, jc.ErrorIsNil)
+
+	service, err := s.State.Service("service")
+	c.Assert(err, jc.ErrorIsNil)
+	charm, force, err := service.Charm()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(charm.URL().String(), gc.Equals, "cs:precise/dummy-1")
+	c.Assert(force, jc.IsFalse)
+	c.Assert(service.PreviousCharmURL().String(), gc.Equals, "cs:precise/wordpress-3")
+}
+
+func (s *clientSuite) TestClientServiceRollbackCharmNoPrevious(c *gc.C) {
+	s.setupServiceSetCharm(c)
+	err := s.APIState.Client().ServiceRollbackCharm("service", false)
+	c.Assert(err, gc.ErrorMatches, `cannot roll back charm for service "service": no previous charm`)
+}
+
+func (s *clientSuite) TestBlockChangesServiceRollbackCharm(c *gc.C) {
+	s.setupServiceSetCharm(c)
+	err := s.APIState.Client().ServiceSetCharm(
+		"service", "cs:precise/wordpress-3", false,
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	s.blockAllChanges(c)
+	err = s.APIState.Client().ServiceRollbackCharm("service", false)
+	c.Assert(errors.Cause(err), gc.DeepEquals, common.ErrOperationBlocked)
+	err = s.APIState.Client().ServiceRollbackCharm("service", true)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 func (s *clientSuite) TestClientServiceSetCharmInvalidService(c *gc.C) {
 	s.makeMockCharmStore()
 	err := s.APIState.Client().ServiceSetCharm(
@@ -2334,6 +2587,32 @@ func (s *clientSuite) TestClientSetServiceConstraints(c *gc.C) {
 	c.Assert(obtained, gc.DeepEquals, cons)
 }
 
+func (s *clientSuite) TestClientSetMachineConstraints(c *gc.C) {
+	host, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	container, err := s.State.AddMachineInsideMachine(state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+	}, host.Id(), instance.LXC)
+	c.Assert(err, jc.ErrorIsNil)
+	err = container.SetProvisioned("juju-machine-0-lxc-0", "fake_nonce", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Update constraints for the provisioned container.
+	cons, err := constraints.Parse("mem=4096", "cpu-cores=2")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.APIState.Client().SetMachineConstraints(container.Id(), cons)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Ensure the constraints have been correctly updated.
+	obtained, err := container.Constraints()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(obtained, gc.DeepEquals, cons)
+
+	err = s.APIState.Client().SetMachineConstraints("42", cons)
+	c.Assert(err, gc.ErrorMatches, `machine 42 not found`)
+}
+
 func (s *clientSuite) setupSetServiceConstraints(c *gc.C) (*state.Service, constraints.Value) {
 	service := s.AddTestingService(c, "dummy", s.AddTestingCharm(c, "dummy"))
 	// Update constraints for the service.
@@ -3453,6 +3732,90 @@ func (s *clientSuite) TestRetryProvisioning(c *gc.C) {
 	c.Assert(data["transient"], jc.IsTrue)
 }
 
+func (s *clientSuite) TestSetMachineMaintenance(c *gc.C) {
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	tag := machine.Tag().(names.MachineTag)
+
+	results, err := s.APIState.Client().SetMachineMaintenance(true, false, tag, names.NewMachineTag("42"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 2)
+	c.Assert(results[0].Error, gc.IsNil)
+	c.Assert(results[1].Error, gc.ErrorMatches, "machine 42 not found")
+	err = machine.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machine.InMaintenance(), jc.IsTrue)
+
+	status, err := s.APIState.Client().Status(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status.Machines[machine.Id()].Maintenance, jc.IsTrue)
+
+	results, err = s.APIState.Client().SetMachineMaintenance(false, false, tag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results[0].Error, gc.IsNil)
+	err = machine.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machine.InMaintenance(), jc.IsFalse)
+}
+
+func (s *clientSuite) TestSetMachineMaintenanceRelocatesUnits(c *gc.C) {
+	wordpress := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	mysql := s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	other, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	addUnit := func(svc *state.Service, m *state.Machine) *state.Unit {
+		unit, err := svc.AddUnit()
+		c.Assert(err, jc.ErrorIsNil)
+		err = unit.AssignToMachine(m)
+		c.Assert(err, jc.ErrorIsNil)
+		return unit
+	}
+	wordpress0 := addUnit(wordpress, machine)
+	addUnit(wordpress, other)
+	mysql0 := addUnit(mysql, machine)
+
+	results, err := s.APIState.Client().SetMachineMaintenance(true, true, machine.Tag().(names.MachineTag))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, gc.IsNil)
+
+	// wordpress/0 has been replaced by a unit on another machine.
+	err = wordpress0.Refresh()
+	if err == nil {
+		c.Assert(wordpress0.Life(), gc.Not(gc.Equals), state.Alive)
+	} else {
+		c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	}
+	units, err := wordpress.AllUnits()
+	c.Assert(err, jc.ErrorIsNil)
+	var alive []string
+	for _, unit := range units {
+		if unit.Life() != state.Alive {
+			continue
+		}
+		alive = append(alive, unit.Name())
+		machineId, err := unit.AssignedMachineId()
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(machineId, gc.Not(gc.Equals), machine.Id())
+	}
+	c.Assert(alive, jc.SameContents, []string{"wordpress/1", "wordpress/2"})
+
+	// mysql/0 is the only unit of its service, so it stays.
+	err = mysql0.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(mysql0.Life(), gc.Equals, state.Alive)
+}
+
+func (s *clientSuite) TestBlockChangesSetMachineMaintenance(c *gc.C) {
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	s.blockAllChanges(c)
+	_, err = s.APIState.Client().SetMachineMaintenance(true, false, machine.Tag().(names.MachineTag))
+	c.Assert(errors.Cause(err), gc.DeepEquals, common.ErrOperationBlocked)
+}
+
 func (s *clientSuite) setupRetryProvisioning(c *gc.C) *state.Machine {
 	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
 	c.Assert(err, jc.ErrorIsNil)
@@ -3839,3 +4202,36 @@ func (s *clientSuite) TestBlockDestroyDestroyRelation(c *gc.C) {
 	endpoints := []string{"wordpress", "mysql"}
 	s.assertDestroyRelation(c, endpoints)
 }
+
+func (s *clientSuite) TestClientHookHistory(c *gc.C) {
+	service := s.AddTestingService(c, "dummy", s.AddTestingCharm(c, "dummy"))
+	unit, err := service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	started := time.Date(2015, 4, 1, 12, 0, 0, 0, time.UTC)
+	for i, hook := range []string{"install", "start"} {
+		err := unit.AddHookRecord(state.HookRecord{
+			Kind:     "hook",
+			Hook:     hook,
+			Started:  started.Add(time.Duration(i) * time.Minute),
+			Duration: time.Second,
+			Result:   "ok",
+		})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	records, err := s.APIState.Client().HookHistory(unit.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(records, gc.HasLen, 2)
+	c.Check(records[0].Kind, gc.Equals, params.HookRecordHook)
+	c.Check(records[0].Hook, gc.Equals, "start")
+	c.Check(records[0].Started.Equal(started.Add(time.Minute)), jc.IsTrue)
+	c.Check(records[0].Duration, gc.Equals, time.Second)
+	c.Check(records[0].Result, gc.Equals, "ok")
+	c.Check(records[1].Hook, gc.Equals, "install")
+}
+
+func (s *clientSuite) TestClientHookHistoryUnknownUnit(c *gc.C) {
+	_, err := s.APIState.Client().HookHistory(names.NewUnitTag("foo/42"))
+	c.Assert(err, gc.ErrorMatches, `unit "foo/42" not found`)
+	c.Assert(err, jc.Satisfies, params.IsCodeNotFound)
+}
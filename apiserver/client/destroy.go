@@ -17,6 +17,9 @@ import (
 // DestroyEnvironment destroys all services and non-manager machine
 // instances in the environment.
 func (c *Client) DestroyEnvironment() error {
+	if err := c.checkAccess(state.EnvironAdminAccess); err != nil {
+		return errors.Trace(err)
+	}
 	if err := c.check.DestroyAllowed(); err != nil {
 		return errors.Trace(err)
 	}
@@ -111,6 +111,14 @@ func (s *permSuite) TestOperationPerm(c *gc.C) {
 		about: "Client.ServiceSetCharm",
 		op:    opClientServiceSetCharm,
 		allow: []names.Tag{userAdmin, userOther},
+	}, {
+		about: "Client.ServiceSetCharmRollout",
+		op:    opClientServiceSetCharmRollout,
+		allow: []names.Tag{userAdmin, userOther},
+	}, {
+		about: "Client.ServiceRollbackCharm",
+		op:    opClientServiceRollbackCharm,
+		allow: []names.Tag{userAdmin, userOther},
 	}, {
 		about: "Client.GetAnnotations",
 		op:    opClientGetAnnotations,
@@ -373,6 +381,22 @@ func opClientServiceSetCharm(c *gc.C, st *api.State, mst *state.State) (func(),
 	return func() {}, err
 }
 
+func opClientServiceSetCharmRollout(c *gc.C, st *api.State, mst *state.State) (func(), error) {
+	err := st.Client().ServiceSetCharmRollout("nosuch", "local:quantal/wordpress", false, 1, false)
+	if params.IsCodeNotFound(err) {
+		err = nil
+	}
+	return func() {}, err
+}
+
+func opClientServiceRollbackCharm(c *gc.C, st *api.State, mst *state.State) (func(), error) {
+	err := st.Client().ServiceRollbackCharm("nosuch", false)
+	if params.IsCodeNotFound(err) {
+		err = nil
+	}
+	return func() {}, err
+}
+
 func opClientAddServiceUnits(c *gc.C, st *api.State, mst *state.State) (func(), error) {
 	_, err := st.Client().AddServiceUnits("nosuch", 1, "")
 	if params.IsCodeNotFound(err) {
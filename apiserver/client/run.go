@@ -83,7 +83,23 @@ func (c *Client) getDataDir() string {
 
 // Run the commands specified on the machines identified through the
 // list of machines, units and services.
+//
+// The commands are run over SSH from the API server. This is only kept
+// for older clients; current clients queue the commands as actions
+// through the Action facade instead.
+//
+// The commands run as root, so running them on machines, or in units on
+// state server machines, which hold the environment's secrets, requires
+// admin access to the environment.
 func (c *Client) Run(run params.RunParams) (results params.RunResults, err error) {
+	if err := c.checkAccess(state.EnvironWriteAccess); err != nil {
+		return params.RunResults{}, errors.Trace(err)
+	}
+	if len(run.Machines) > 0 {
+		if err := c.checkAccess(state.EnvironAdminAccess); err != nil {
+			return params.RunResults{}, errors.Trace(err)
+		}
+	}
 	if err := c.check.ChangeAllowed(); err != nil {
 		return params.RunResults{}, errors.Trace(err)
 	}
@@ -105,6 +121,11 @@ func (c *Client) Run(run params.RunParams) (results params.RunResults, err error
 		if err != nil {
 			return results, err
 		}
+		if machine.IsManager() {
+			if err := c.checkAccess(state.EnvironAdminAccess); err != nil {
+				return params.RunResults{}, errors.Trace(err)
+			}
+		}
 		command := fmt.Sprintf("juju-run %s %s", unit.Name(), quotedCommands)
 		execParam := remoteParamsForMachine(machine, command, run.Timeout)
 		execParam.UnitId = unit.Name()
@@ -123,7 +144,12 @@ func (c *Client) Run(run params.RunParams) (results params.RunResults, err error
 }
 
 // RunOnAllMachines attempts to run the specified command on all the machines.
+// Like Run, it is only kept for older clients, and it requires admin access
+// to the environment.
 func (c *Client) RunOnAllMachines(run params.RunParams) (params.RunResults, error) {
+	if err := c.checkAccess(state.EnvironAdminAccess); err != nil {
+		return params.RunResults{}, errors.Trace(err)
+	}
 	if err := c.check.ChangeAllowed(); err != nil {
 		return params.RunResults{}, errors.Trace(err)
 	}
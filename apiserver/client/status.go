@@ -6,6 +6,7 @@ package client
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/utils/set"
@@ -333,6 +334,7 @@ func makeMachineStatus(machine *state.Machine) (status api.MachineStatus) {
 	status.Jobs = paramsJobsFromJobs(machine.Jobs())
 	status.WantsVote = machine.WantsVote()
 	status.HasVote = machine.HasVote()
+	status.Maintenance = machine.InMaintenance()
 	instid, err := machine.InstanceId()
 	if err == nil {
 		status.InstanceId = instid
@@ -341,6 +343,10 @@ func makeMachineStatus(machine *state.Machine) (status api.MachineStatus) {
 			status.InstanceState = "error"
 		}
 		status.DNSName = network.SelectPublicAddress(machine.Addresses())
+		status.Tags, err = machine.InstanceTags()
+		if err != nil {
+			logger.Debugf("error fetching instance tags for %q: %v", machine.Id(), err)
+		}
 	} else {
 		if errors.IsNotProvisioned(err) {
 			status.InstanceId = "pending"
@@ -461,6 +467,7 @@ func (context *statusContext) processService(service *state.Service) (status api
 	status.Charm = serviceCharmURL.String()
 	status.Exposed = service.IsExposed()
 	status.Life = processLife(service)
+	status.Status = processWorkloadStatus(service.Status)
 
 	latestCharm, ok := context.latestCharms[*serviceCharmURL.WithRevision(-1)]
 	if ok && latestCharm != serviceCharmURL.String() {
@@ -524,6 +531,7 @@ func (context *statusContext) processUnit(unit *state.Unit, serviceCharm string)
 		status.Charm = curl.String()
 	}
 	status.Agent, status.AgentState, status.AgentStateInfo = processAgent(unit)
+	status.Workload = processWorkloadStatus(unit.WorkloadStatus)
 
 	// Until Juju 2.0, we need to continue to display legacy status values.
 	status.Agent.Status = params.TranslateLegacyStatus(status.Agent.Status)
@@ -545,6 +553,22 @@ func (context *statusContext) processUnit(unit *state.Unit, serviceCharm string)
 	return
 }
 
+// processWorkloadStatus returns the status of the software run by a
+// unit or service, as returned by getStatus. Errors are reported in the
+// status info, so that they don't prevent the rest of the status from
+// being shown.
+func processWorkloadStatus(getStatus func() (state.Status, string, map[string]interface{}, error)) (status api.WorkloadStatus) {
+	st, info, data, err := getStatus()
+	if err != nil {
+		status.Info = err.Error()
+		return
+	}
+	status.Status = params.Status(st)
+	status.Info = info
+	status.Data = data
+	return
+}
+
 func (context *statusContext) unitByName(name string) *state.Unit {
 	serviceName := strings.Split(name, "/")[0]
 	return context.units[serviceName][name]
@@ -600,6 +624,9 @@ func processAgent(entity stateAgent) (out api.AgentStatus, compatStatus params.S
 	var st state.Status
 	st, out.Info, out.Data, out.Err = entity.Status()
 	out.Status = params.Status(st)
+	if running, ok := hookRunningTime(out.Data); ok {
+		out.Info = fmt.Sprintf("%s for %v", out.Info, running)
+	}
 	compatStatus = out.Status
 	compatInfo = out.Info
 	out.Data = filterStatusData(out.Data)
@@ -659,6 +686,21 @@ func filterStatusData(status map[string]interface{}) map[string]interface{} {
 	return out
 }
 
+// hookRunningTime returns how long the hook recorded in an agent's
+// StatusData has been running, to the nearest second, and whether the
+// agent reported a running hook at all.
+func hookRunningTime(status map[string]interface{}) (time.Duration, bool) {
+	started, ok := status["hook-started"].(string)
+	if !ok {
+		return 0, false
+	}
+	t, err := time.Parse(time.RFC3339, started)
+	if err != nil {
+		return 0, false
+	}
+	return (time.Since(t) / time.Second) * time.Second, true
+}
+
 func processLife(entity lifer) string {
 	if life := entity.Life(); life != state.Alive {
 		// alive is the usual state so omit it by default.
@@ -4,10 +4,13 @@
 package client_test
 
 import (
+	"time"
+
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/apiserver/client"
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/testing/factory"
@@ -108,3 +111,20 @@ func (s *statusUnitTestSuite) TestProcessMachinesWithEmbeddedContainers(c *gc.C)
 	c.Check(hostContainer, gc.HasLen, 2)
 	c.Check(hostContainer[lxcHost.Id()].Containers, gc.HasLen, 1)
 }
+
+func (s *statusUnitTestSuite) TestRunningHookDuration(c *gc.C) {
+	unit := s.MakeUnit(c, nil)
+	started := time.Now().Add(-90 * time.Second).UTC().Format(time.RFC3339)
+	err := unit.SetStatus(state.StatusInstalling, "running install hook", map[string]interface{}{
+		"hook":         "install",
+		"hook-started": started,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	status, err := s.APIState.Client().Status(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	unitStatus := status.Services[unit.ServiceName()].Units[unit.Name()]
+	c.Check(unitStatus.Agent.Status, gc.Equals, params.StatusInstalling)
+	c.Check(unitStatus.Agent.Info, gc.Matches, `running install hook for 1m3\ds`)
+	c.Check(unitStatus.Agent.Data, gc.HasLen, 0)
+}
@@ -0,0 +1,126 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package common
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+// AuthAndActionFromTagFn returns a function that parses an action tag,
+// retrieves the action and checks that it is queued for an
+// ActionReceiver accessible with canAccess.
+func AuthAndActionFromTagFn(canAccess AuthFunc, getActionByTag func(names.ActionTag) (*state.Action, error)) func(string) (*state.Action, error) {
+	return func(tag string) (*state.Action, error) {
+		actionTag, err := names.ParseActionTag(tag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		action, err := getActionByTag(actionTag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		receiverTag, err := names.ActionReceiverTag(action.Receiver())
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if !canAccess(receiverTag) {
+			return nil, ErrPerm
+		}
+		return action, nil
+	}
+}
+
+// Actions returns the pending Actions identified by the given tags,
+// using actionFn to authenticate and retrieve each of them.
+func Actions(args params.Entities, actionFn func(string) (*state.Action, error)) params.ActionsQueryResults {
+	results := params.ActionsQueryResults{
+		Results: make([]params.ActionsQueryResult, len(args.Entities)),
+	}
+	for i, arg := range args.Entities {
+		action, err := actionFn(arg.Tag)
+		if err != nil {
+			results.Results[i].Error = ServerError(err)
+			continue
+		}
+		if action.Status() != state.ActionPending {
+			results.Results[i].Error = ServerError(ErrActionNotAvailable)
+			continue
+		}
+		results.Results[i].Action.Action = &params.Action{
+			Name:       action.Name(),
+			Parameters: action.Parameters(),
+		}
+	}
+	return results
+}
+
+// BeginActions marks the Actions identified by the given tags as
+// running, using actionFn to authenticate and retrieve each of them.
+func BeginActions(args params.Entities, actionFn func(string) (*state.Action, error)) params.ErrorResults {
+	results := params.ErrorResults{Results: make([]params.ErrorResult, len(args.Entities))}
+	for i, arg := range args.Entities {
+		action, err := actionFn(arg.Tag)
+		if err != nil {
+			results.Results[i].Error = ServerError(err)
+			continue
+		}
+		_, err = action.Begin()
+		if err != nil {
+			results.Results[i].Error = ServerError(err)
+			continue
+		}
+	}
+	return results
+}
+
+// FinishActions saves the results of completed Actions, using actionFn
+// to authenticate and retrieve each of them.
+func FinishActions(args params.ActionExecutionResults, actionFn func(string) (*state.Action, error)) params.ErrorResults {
+	results := params.ErrorResults{Results: make([]params.ErrorResult, len(args.Results))}
+	for i, arg := range args.Results {
+		action, err := actionFn(arg.ActionTag)
+		if err != nil {
+			results.Results[i].Error = ServerError(err)
+			continue
+		}
+		actionResults, err := ParamsActionExecutionResultsToStateActionResults(arg)
+		if err != nil {
+			results.Results[i].Error = ServerError(err)
+			continue
+		}
+		_, err = action.Finish(actionResults)
+		if err != nil {
+			results.Results[i].Error = ServerError(err)
+			continue
+		}
+	}
+	return results
+}
+
+// ParamsActionExecutionResultsToStateActionResults does exactly what
+// the name implies.
+func ParamsActionExecutionResultsToStateActionResults(arg params.ActionExecutionResult) (state.ActionResults, error) {
+	var status state.ActionStatus
+	switch arg.Status {
+	case params.ActionCancelled:
+		status = state.ActionCancelled
+	case params.ActionCompleted:
+		status = state.ActionCompleted
+	case params.ActionFailed:
+		status = state.ActionFailed
+	case params.ActionPending:
+		status = state.ActionPending
+	default:
+		return state.ActionResults{}, errors.Errorf("unrecognized action status '%s'", arg.Status)
+	}
+	return state.ActionResults{
+		Status:  status,
+		Results: arg.Results,
+		Message: arg.Message,
+	}, nil
+}
@@ -0,0 +1,49 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package common_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+type actionSuite struct{}
+
+var _ = gc.Suite(&actionSuite{})
+
+func (*actionSuite) TestParamsActionExecutionResultsToStateActionResults(c *gc.C) {
+	for i, test := range []struct {
+		status   string
+		expected state.ActionStatus
+	}{
+		{params.ActionCancelled, state.ActionCancelled},
+		{params.ActionCompleted, state.ActionCompleted},
+		{params.ActionFailed, state.ActionFailed},
+		{params.ActionPending, state.ActionPending},
+	} {
+		c.Logf("test %d: %s", i, test.status)
+		results, err := common.ParamsActionExecutionResultsToStateActionResults(params.ActionExecutionResult{
+			Status:  test.status,
+			Results: map[string]interface{}{"foo": "bar"},
+			Message: "message",
+		})
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(results, jc.DeepEquals, state.ActionResults{
+			Status:  test.expected,
+			Results: map[string]interface{}{"foo": "bar"},
+			Message: "message",
+		})
+	}
+}
+
+func (*actionSuite) TestParamsActionExecutionResultsToStateActionResultsBadStatus(c *gc.C) {
+	_, err := common.ParamsActionExecutionResultsToStateActionResults(params.ActionExecutionResult{
+		Status: "bloop",
+	})
+	c.Assert(err, gc.ErrorMatches, "unrecognized action status 'bloop'")
+}
@@ -6,12 +6,15 @@ package common
 import (
 	stderrors "errors"
 	"fmt"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/names"
 	"github.com/juju/txn"
+	"gopkg.in/macaroon.v1"
 
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/leadership"
 	"github.com/juju/juju/state"
 )
 
@@ -63,6 +66,28 @@ func IsUnknownEnviromentError(err error) bool {
 	return ok
 }
 
+// TryAgainError is returned when a request is turned away because
+// the server is too busy to handle it. RetryAfter holds the delay
+// the client is asked to wait before trying again.
+type TryAgainError struct {
+	RetryAfter time.Duration
+}
+
+func (e *TryAgainError) Error() string {
+	return fmt.Sprintf("server busy, try again in %v", e.RetryAfter)
+}
+
+// DischargeRequiredError is returned when an externally authenticated
+// user logs in without credentials. Macaroon holds the macaroon the
+// user must have discharged by their identity service to log in.
+type DischargeRequiredError struct {
+	Macaroon *macaroon.Macaroon
+}
+
+func (e *DischargeRequiredError) Error() string {
+	return "macaroon discharge required"
+}
+
 var (
 	ErrBadId              = stderrors.New("id not found")
 	ErrBadCreds           = stderrors.New("invalid entity name or password")
@@ -82,19 +107,20 @@ var (
 )
 
 var singletonErrorCodes = map[error]string{
-	state.ErrCannotEnterScopeYet: params.CodeCannotEnterScopeYet,
-	state.ErrCannotEnterScope:    params.CodeCannotEnterScope,
-	state.ErrUnitHasSubordinates: params.CodeUnitHasSubordinates,
-	state.ErrDead:                params.CodeDead,
-	txn.ErrExcessiveContention:   params.CodeExcessiveContention,
-	ErrBadId:                     params.CodeNotFound,
-	ErrBadCreds:                  params.CodeUnauthorized,
-	ErrPerm:                      params.CodeUnauthorized,
-	ErrNotLoggedIn:               params.CodeUnauthorized,
-	ErrUnknownWatcher:            params.CodeNotFound,
-	ErrStoppedWatcher:            params.CodeStopped,
-	ErrTryAgain:                  params.CodeTryAgain,
-	ErrActionNotAvailable:        params.CodeActionNotAvailable,
+	state.ErrCannotEnterScopeYet:        params.CodeCannotEnterScopeYet,
+	state.ErrCannotEnterScope:           params.CodeCannotEnterScope,
+	state.ErrUnitHasSubordinates:        params.CodeUnitHasSubordinates,
+	state.ErrDead:                       params.CodeDead,
+	txn.ErrExcessiveContention:          params.CodeExcessiveContention,
+	ErrBadId:                            params.CodeNotFound,
+	ErrBadCreds:                         params.CodeUnauthorized,
+	ErrPerm:                             params.CodeUnauthorized,
+	ErrNotLoggedIn:                      params.CodeUnauthorized,
+	ErrUnknownWatcher:                   params.CodeNotFound,
+	ErrStoppedWatcher:                   params.CodeStopped,
+	ErrTryAgain:                         params.CodeTryAgain,
+	ErrActionNotAvailable:               params.CodeActionNotAvailable,
+	leadership.LeadershipClaimDeniedErr: params.CodeLeadershipClaimDenied,
 }
 
 func singletonCode(err error) (string, bool) {
@@ -139,11 +165,45 @@ func ServerError(err error) *params.Error {
 		code = params.CodeUpgradeInProgress
 	case IsUnknownEnviromentError(err):
 		code = params.CodeNotFound
+	case isTryAgainError(err):
+		code = params.CodeTryAgain
+	case isDischargeRequiredError(err):
+		code = params.CodeDischargeRequired
 	default:
 		code = params.ErrCode(err)
 	}
 	return &params.Error{
-		Message: msg,
-		Code:    code,
+		Message:    msg,
+		Code:       code,
+		RetryAfter: retryAfter(err),
+		Macaroon:   dischargeMacaroon(err),
+	}
+}
+
+func isTryAgainError(err error) bool {
+	_, ok := err.(*TryAgainError)
+	return ok
+}
+
+// retryAfter returns the delay that err asks the client
+// to wait before retrying, or zero if it makes no request.
+func retryAfter(err error) time.Duration {
+	if err, ok := err.(*TryAgainError); ok {
+		return err.RetryAfter
+	}
+	return 0
+}
+
+func isDischargeRequiredError(err error) bool {
+	_, ok := err.(*DischargeRequiredError)
+	return ok
+}
+
+// dischargeMacaroon returns the macaroon that err asks the
+// client to have discharged, or nil if it makes no request.
+func dischargeMacaroon(err error) *macaroon.Macaroon {
+	if err, ok := err.(*DischargeRequiredError); ok {
+		return err.Macaroon
 	}
+	return nil
 }
@@ -5,15 +5,18 @@ package common_test
 
 import (
 	stderrors "errors"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/names"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/txn"
 	gc "gopkg.in/check.v1"
+	"gopkg.in/macaroon.v1"
 
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/leadership"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/testing"
 )
@@ -104,6 +107,14 @@ var errorTransformTests = []struct {
 	err:        common.ErrTryAgain,
 	code:       params.CodeTryAgain,
 	helperFunc: params.IsCodeTryAgain,
+}, {
+	err:        &common.TryAgainError{RetryAfter: 3 * time.Second},
+	code:       params.CodeTryAgain,
+	helperFunc: params.IsCodeTryAgain,
+}, {
+	err:        &common.DischargeRequiredError{},
+	code:       params.CodeDischargeRequired,
+	helperFunc: params.IsCodeDischargeRequired,
 }, {
 	err:        state.UpgradeInProgressError,
 	code:       params.CodeUpgradeInProgress,
@@ -112,6 +123,10 @@ var errorTransformTests = []struct {
 	err:        common.ErrOperationBlocked,
 	code:       params.CodeOperationBlocked,
 	helperFunc: params.IsCodeOperationBlocked,
+}, {
+	err:        errors.Wrap(errors.New("lease claim denied"), leadership.LeadershipClaimDeniedErr),
+	code:       params.CodeLeadershipClaimDenied,
+	helperFunc: params.IsCodeLeadershipClaimDenied,
 }, {
 	err:  stderrors.New("an error"),
 	code: "",
@@ -148,6 +163,26 @@ func (s *errorsSuite) TestErrorTransform(c *gc.C) {
 	}
 }
 
+func (s *errorsSuite) TestTryAgainErrorRetryAfter(c *gc.C) {
+	err := common.ServerError(errors.Trace(&common.TryAgainError{RetryAfter: 3 * time.Second}))
+	c.Assert(err.Code, gc.Equals, params.CodeTryAgain)
+	c.Assert(err.RetryAfter, gc.Equals, 3*time.Second)
+
+	err = common.ServerError(common.ErrTryAgain)
+	c.Assert(err.RetryAfter, gc.Equals, time.Duration(0))
+}
+
+func (s *errorsSuite) TestDischargeRequiredErrorMacaroon(c *gc.C) {
+	m, err := macaroon.New([]byte("root key"), "id", "juju")
+	c.Assert(err, jc.ErrorIsNil)
+	perr := common.ServerError(errors.Trace(&common.DischargeRequiredError{Macaroon: m}))
+	c.Assert(perr.Code, gc.Equals, params.CodeDischargeRequired)
+	c.Assert(perr.Macaroon, gc.Equals, m)
+
+	perr = common.ServerError(common.ErrBadCreds)
+	c.Assert(perr.Macaroon, gc.IsNil)
+}
+
 func (s *errorsSuite) TestUnknownEnvironment(c *gc.C) {
 	err := common.UnknownEnvironmentError("dead-beef")
 	c.Check(err, gc.ErrorMatches, `unknown environment: "dead-beef"`)
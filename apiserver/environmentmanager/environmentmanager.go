@@ -185,6 +185,11 @@ func (em *EnvironmentManagerAPI) newEnvironmentConfig(args params.EnvironmentCre
 // environment config specified in the args.
 func (em *EnvironmentManagerAPI) CreateEnvironment(args params.EnvironmentCreateArgs) (params.Environment, error) {
 	result := params.Environment{}
+	// Users with read-only access to the state server
+	// environment may not create environments.
+	if !em.authorizer.AuthEnvironAccess(state.EnvironWriteAccess) {
+		return result, common.ErrPerm
+	}
 	// Get the state server environment first. We need it both for the state
 	// server owner and the ability to get the config.
 	stateServerEnv, err := em.state.StateServerEnvironment()
@@ -107,6 +107,14 @@ func (s *envManagerSuite) TestNonAdminCannotCreateEnvironmentForSomeoneElse(c *g
 	c.Assert(err, gc.ErrorMatches, "permission denied")
 }
 
+func (s *envManagerSuite) TestReadOnlyUserCannotCreateEnvironment(c *gc.C) {
+	owner := names.NewUserTag("external@remote")
+	s.authoriser.Access = state.EnvironReadAccess
+	s.setAPIUser(c, owner)
+	_, err := s.envmanager.CreateEnvironment(s.createArgs(c, owner))
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
 func (s *envManagerSuite) TestCreateEnvironmentValidatesConfig(c *gc.C) {
 	admin := s.AdminUserTag(c)
 	s.setAPIUser(c, admin)
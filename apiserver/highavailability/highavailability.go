@@ -47,6 +47,10 @@ func NewHighAvailabilityAPI(st *state.State, resources *common.Resources, author
 }
 
 func (api *HighAvailabilityAPI) EnsureAvailability(args params.StateServersSpecs) (params.StateServersChangeResults, error) {
+	// Client users must be environment administrators.
+	if api.authorizer.AuthClient() && !api.authorizer.AuthEnvironAccess(state.EnvironAdminAccess) {
+		return params.StateServersChangeResults{}, common.ErrPerm
+	}
 	results := params.StateServersChangeResults{Results: make([]params.StateServersChangeResult, len(args.Specs))}
 	for i, stateServersSpec := range args.Specs {
 		result, err := EnsureAvailabilitySingle(api.state, stateServersSpec)
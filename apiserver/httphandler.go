@@ -72,31 +72,68 @@ func (h *httpHandler) validateEnvironUUID(r *http.Request) (*httpStateWrapper, e
 // authenticate parses HTTP basic authentication and authorizes the
 // request by looking up the provided tag and password against state.
 func (h *httpStateWrapper) authenticate(r *http.Request) error {
+	_, err := h.authenticateUser(r)
+	return err
+}
+
+// authenticateUser is like authenticate but also returns the tag
+// of the authenticated user.
+func (h *httpStateWrapper) authenticateUser(r *http.Request) (names.UserTag, error) {
+	entity, err := h.authenticateEntity(r)
+	if err != nil {
+		return names.UserTag{}, err
+	}
+	return entity.Tag().(names.UserTag), nil
+}
+
+// authenticateEntity is like authenticate but also returns the
+// authenticated user.
+func (h *httpStateWrapper) authenticateEntity(r *http.Request) (state.Entity, error) {
 	parts := strings.Fields(r.Header.Get("Authorization"))
 	if len(parts) != 2 || parts[0] != "Basic" {
 		// Invalid header format or no header provided.
-		return errors.New("invalid request format")
+		return nil, errors.New("invalid request format")
 	}
 	// Challenge is a base64-encoded "tag:pass" string.
 	// See RFC 2617, Section 2.
 	challenge, err := base64.StdEncoding.DecodeString(parts[1])
 	if err != nil {
-		return errors.New("invalid request format")
+		return nil, errors.New("invalid request format")
 	}
 	tagPass := strings.SplitN(string(challenge), ":", 2)
 	if len(tagPass) != 2 {
-		return errors.New("invalid request format")
+		return nil, errors.New("invalid request format")
 	}
 	// Only allow users, not agents.
 	if _, err := names.ParseUserTag(tagPass[0]); err != nil {
-		return common.ErrBadCreds
+		return nil, common.ErrBadCreds
 	}
 	// Ensure the credentials are correct.
-	_, err = checkCreds(h.state, params.LoginRequest{
+	return checkCreds(h.state, params.LoginRequest{
 		AuthTag:     tagPass[0],
 		Credentials: tagPass[1],
 	})
-	return err
+}
+
+// authenticateAccess authenticates the request like authenticate,
+// and then checks that the user has at least the given level of
+// access to the environment. If the request cannot be authenticated
+// it sends an unauthorized error, and if the user has insufficient
+// access it sends a forbidden error; in either case it returns false.
+func (h *httpStateWrapper) authenticateAccess(
+	w http.ResponseWriter, r *http.Request, sender errorSender, access state.EnvironmentAccess,
+) bool {
+	entity, err := h.authenticateEntity(r)
+	if err != nil {
+		w.Header().Set("WWW-Authenticate", `Basic realm="juju"`)
+		sender.sendError(w, http.StatusUnauthorized, "unauthorized")
+		return false
+	}
+	if err := checkEnvironAccess(h.state, entity, access); err != nil {
+		sender.sendError(w, http.StatusForbidden, err.Error())
+		return false
+	}
+	return true
 }
 
 func (h *httpStateWrapper) cleanup() {
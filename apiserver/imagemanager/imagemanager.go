@@ -88,6 +88,9 @@ func (api *ImageManagerAPI) ListImages(arg params.ImageFilterParams) (params.Lis
 
 // DeleteImages deletes the images matching the specified filter.
 func (api *ImageManagerAPI) DeleteImages(arg params.ImageFilterParams) (params.ErrorResults, error) {
+	if !api.authorizer.AuthEnvironAccess(state.EnvironWriteAccess) {
+		return params.ErrorResults{}, common.ErrPerm
+	}
 	if err := api.check.ChangeAllowed(); err != nil {
 		return params.ErrorResults{}, errors.Trace(err)
 	}
@@ -17,6 +17,7 @@ import (
 
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
 )
 
 func init() {
@@ -82,6 +83,8 @@ func (m *stubAuthorizer) AuthEnvironManager() bool { return true }
 func (m *stubAuthorizer) AuthClient() bool         { return true }
 func (m *stubAuthorizer) GetAuthTag() names.Tag    { return names.NewServiceTag(StubUnitNm) }
 
+func (m *stubAuthorizer) AuthEnvironAccess(state.EnvironmentAccess) bool { return true }
+
 func (s *leadershipSuite) TestClaimLeadershipTranslation(c *gc.C) {
 	var ldrMgr stubLeadershipManager
 	ldrMgr.ClaimLeadershipFn = func(sid, uid string) (time.Duration, error) {
@@ -0,0 +1,89 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/juju/juju/rpc"
+	"github.com/juju/juju/rpc/rpcreflect"
+)
+
+// limitingRoot limits the number of requests that may be in
+// progress at once on a single connection. Requests made when
+// the limit has been reached fail with a try-again error.
+type limitingRoot struct {
+	rpc.MethodFinder
+	slots    chan struct{}
+	tryAgain func() error
+}
+
+// newLimitingRoot returns a new limitingRoot that allows at most
+// maxRequests concurrent requests, and rejects requests above
+// that with the error returned by tryAgain.
+func newLimitingRoot(finder rpc.MethodFinder, maxRequests int, tryAgain func() error) *limitingRoot {
+	return &limitingRoot{
+		MethodFinder: finder,
+		slots:        make(chan struct{}, maxRequests),
+		tryAgain:     tryAgain,
+	}
+}
+
+// isUnlimitedFacade reports whether calls on the named facade
+// are exempt from the request limit. Agents keep calls to
+// watchers outstanding for as long as they run, and the pinger
+// must always get through to keep the connection alive.
+func isUnlimitedFacade(rootName string) bool {
+	return rootName == "Pinger" || strings.HasSuffix(rootName, "Watcher")
+}
+
+// FindMethod implements rpc.MethodFinder.
+func (r *limitingRoot) FindMethod(rootName string, version int, methodName string) (rpcreflect.MethodCaller, error) {
+	caller, err := r.MethodFinder.FindMethod(rootName, version, methodName)
+	if err != nil {
+		return nil, err
+	}
+	if isUnlimitedFacade(rootName) {
+		return caller, nil
+	}
+	return &limitedCaller{caller, r}, nil
+}
+
+// Kill implements rpc.Killer by passing the call
+// on to the underlying MethodFinder.
+func (r *limitingRoot) Kill() {
+	if killer, ok := r.MethodFinder.(rpc.Killer); ok {
+		killer.Kill()
+	}
+}
+
+// Cleanup implements rpc.Cleaner by passing the call
+// on to the underlying MethodFinder.
+func (r *limitingRoot) Cleanup() {
+	if cleaner, ok := r.MethodFinder.(rpc.Cleaner); ok {
+		cleaner.Cleanup()
+	}
+}
+
+// limitedCaller wraps a MethodCaller so that each
+// call occupies one of its root's request slots.
+type limitedCaller struct {
+	rpcreflect.MethodCaller
+	root *limitingRoot
+}
+
+// Call implements rpcreflect.MethodCaller.
+func (c *limitedCaller) Call(objId string, arg reflect.Value) (reflect.Value, error) {
+	select {
+	case c.root.slots <- struct{}{}:
+	default:
+		logger.Debugf("too many concurrent requests, try again later")
+		return reflect.Value{}, c.root.tryAgain()
+	}
+	defer func() {
+		<-c.root.slots
+	}()
+	return c.MethodCaller.Call(objId, arg)
+}
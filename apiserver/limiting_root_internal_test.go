@@ -0,0 +1,150 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// This is an internal package test.
+
+package apiserver
+
+import (
+	"errors"
+	"reflect"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/rpc/rpcreflect"
+	"github.com/juju/juju/testing"
+)
+
+type limitingRootSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&limitingRootSuite{})
+
+var errTryAgain = errors.New("try again")
+
+func tryAgain() error {
+	return errTryAgain
+}
+
+// blockingFinder returns callers that block until
+// their release channel is closed.
+type blockingFinder struct {
+	release chan struct{}
+	started chan string
+	killed  bool
+	cleaned bool
+}
+
+func newBlockingFinder() *blockingFinder {
+	return &blockingFinder{
+		release: make(chan struct{}),
+		started: make(chan string, 10),
+	}
+}
+
+func (f *blockingFinder) FindMethod(rootName string, version int, methodName string) (rpcreflect.MethodCaller, error) {
+	if rootName == "Missing" {
+		return nil, errors.New("no such facade")
+	}
+	return &blockingCaller{f, rootName}, nil
+}
+
+func (f *blockingFinder) Kill() {
+	f.killed = true
+}
+
+func (f *blockingFinder) Cleanup() {
+	f.cleaned = true
+}
+
+type blockingCaller struct {
+	finder   *blockingFinder
+	rootName string
+}
+
+func (c *blockingCaller) ParamsType() reflect.Type { return nil }
+func (c *blockingCaller) ResultType() reflect.Type { return nil }
+
+func (c *blockingCaller) Call(objId string, arg reflect.Value) (reflect.Value, error) {
+	c.finder.started <- c.rootName
+	<-c.finder.release
+	return reflect.Value{}, nil
+}
+
+func (s *limitingRootSuite) startCall(c *gc.C, root *limitingRoot, rootName string) <-chan error {
+	caller, err := root.FindMethod(rootName, 0, "Method")
+	c.Assert(err, jc.ErrorIsNil)
+	done := make(chan error, 1)
+	go func() {
+		_, err := caller.Call("", reflect.Value{})
+		done <- err
+	}()
+	return done
+}
+
+func (s *limitingRootSuite) TestLimitEnforced(c *gc.C) {
+	finder := newBlockingFinder()
+	root := newLimitingRoot(finder, 2, tryAgain)
+	done1 := s.startCall(c, root, "Client")
+	done2 := s.startCall(c, root, "Uniter")
+	<-finder.started
+	<-finder.started
+
+	caller, err := root.FindMethod("Client", 0, "Method")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = caller.Call("", reflect.Value{})
+	c.Assert(err, gc.Equals, errTryAgain)
+
+	close(finder.release)
+	c.Assert(<-done1, jc.ErrorIsNil)
+	c.Assert(<-done2, jc.ErrorIsNil)
+}
+
+func (s *limitingRootSuite) TestSlotsReleased(c *gc.C) {
+	finder := newBlockingFinder()
+	close(finder.release)
+	root := newLimitingRoot(finder, 1, tryAgain)
+	for i := 0; i < 3; i++ {
+		caller, err := root.FindMethod("Client", 0, "Method")
+		c.Assert(err, jc.ErrorIsNil)
+		_, err = caller.Call("", reflect.Value{})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+}
+
+func (s *limitingRootSuite) TestWatchersAndPingerUnlimited(c *gc.C) {
+	finder := newBlockingFinder()
+	root := newLimitingRoot(finder, 1, tryAgain)
+	done := s.startCall(c, root, "Client")
+	<-finder.started
+
+	var watchers []<-chan error
+	for _, rootName := range []string{"NotifyWatcher", "StringsWatcher", "Pinger"} {
+		watchers = append(watchers, s.startCall(c, root, rootName))
+		c.Assert(<-finder.started, gc.Equals, rootName)
+	}
+
+	close(finder.release)
+	c.Assert(<-done, jc.ErrorIsNil)
+	for _, done := range watchers {
+		c.Assert(<-done, jc.ErrorIsNil)
+	}
+}
+
+func (s *limitingRootSuite) TestFindMethodError(c *gc.C) {
+	root := newLimitingRoot(newBlockingFinder(), 1, tryAgain)
+	caller, err := root.FindMethod("Missing", 0, "Method")
+	c.Assert(err, gc.ErrorMatches, "no such facade")
+	c.Assert(caller, gc.IsNil)
+}
+
+func (s *limitingRootSuite) TestKillAndCleanup(c *gc.C) {
+	finder := newBlockingFinder()
+	root := newLimitingRoot(finder, 1, tryAgain)
+	root.Kill()
+	root.Cleanup()
+	c.Assert(finder.killed, jc.IsTrue)
+	c.Assert(finder.cleaned, jc.IsTrue)
+}
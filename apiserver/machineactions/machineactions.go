@@ -0,0 +1,96 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package machineactions implements the API facade used by machine
+// agents to run the actions queued for their machines.
+package machineactions
+
+import (
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/watcher"
+)
+
+func init() {
+	common.RegisterStandardFacade("MachineActions", 1, NewMachineActionsAPI)
+}
+
+// MachineActionsAPI implements the API used by machine agents to run
+// the actions queued for their machines.
+type MachineActionsAPI struct {
+	st         *state.State
+	resources  *common.Resources
+	authorizer common.Authorizer
+}
+
+// NewMachineActionsAPI creates a new server-side MachineActions facade.
+func NewMachineActionsAPI(st *state.State, resources *common.Resources, authorizer common.Authorizer) (*MachineActionsAPI, error) {
+	if !authorizer.AuthMachineAgent() {
+		return nil, common.ErrPerm
+	}
+	return &MachineActionsAPI{
+		st:         st,
+		resources:  resources,
+		authorizer: authorizer,
+	}, nil
+}
+
+// WatchActionNotifications returns a StringsWatcher for observing the
+// actions queued for each of the given machines.
+func (api *MachineActionsAPI) WatchActionNotifications(args params.Entities) (params.StringsWatchResults, error) {
+	result := params.StringsWatchResults{
+		Results: make([]params.StringsWatchResult, len(args.Entities)),
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseMachineTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		err = common.ErrPerm
+		if api.authorizer.AuthOwner(tag) {
+			result.Results[i], err = api.watchOneMachineActionNotifications(tag)
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+func (api *MachineActionsAPI) watchOneMachineActionNotifications(tag names.MachineTag) (params.StringsWatchResult, error) {
+	nothing := params.StringsWatchResult{}
+	machine, err := api.st.Machine(tag.Id())
+	if err != nil {
+		return nothing, err
+	}
+	watch := machine.WatchActionNotifications()
+	if changes, ok := <-watch.Changes(); ok {
+		return params.StringsWatchResult{
+			StringsWatcherId: api.resources.Register(watch),
+			Changes:          changes,
+		}, nil
+	}
+	return nothing, watcher.EnsureErr(watch)
+}
+
+// Actions returns the pending Actions identified by the given tags, as
+// long as they are queued for the calling machine.
+func (api *MachineActionsAPI) Actions(args params.Entities) (params.ActionsQueryResults, error) {
+	return common.Actions(args, api.actionFn()), nil
+}
+
+// BeginActions marks the Actions identified by the given tags as running.
+func (api *MachineActionsAPI) BeginActions(args params.Entities) (params.ErrorResults, error) {
+	return common.BeginActions(args, api.actionFn()), nil
+}
+
+// FinishActions saves the results of completed Actions.
+func (api *MachineActionsAPI) FinishActions(args params.ActionExecutionResults) (params.ErrorResults, error) {
+	return common.FinishActions(args, api.actionFn()), nil
+}
+
+func (api *MachineActionsAPI) actionFn() func(string) (*state.Action, error) {
+	return common.AuthAndActionFromTagFn(api.authorizer.AuthOwner, api.st.ActionByTag)
+}
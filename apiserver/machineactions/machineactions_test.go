@@ -0,0 +1,148 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machineactions_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/actions"
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/machineactions"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
+)
+
+type machineActionsSuite struct {
+	jujutesting.JujuConnSuite
+
+	machine0   *state.Machine
+	machine1   *state.Machine
+	authorizer apiservertesting.FakeAuthorizer
+	resources  *common.Resources
+	api        *machineactions.MachineActionsAPI
+}
+
+var _ = gc.Suite(&machineActionsSuite{})
+
+func (s *machineActionsSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+
+	var err error
+	s.machine0, err = s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	s.machine1, err = s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.authorizer = apiservertesting.FakeAuthorizer{
+		Tag: s.machine0.Tag(),
+	}
+	s.resources = common.NewResources()
+	s.AddCleanup(func(_ *gc.C) { s.resources.StopAll() })
+
+	s.api, err = machineactions.NewMachineActionsAPI(s.State, s.resources, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *machineActionsSuite) addAction(c *gc.C, machine *state.Machine) *state.Action {
+	action, err := machine.AddAction(actions.JujuRunActionName, actions.JujuRunParams("hostname", time.Minute))
+	c.Assert(err, jc.ErrorIsNil)
+	return action
+}
+
+func (s *machineActionsSuite) TestNewMachineActionsAPIRequiresMachineAgent(c *gc.C) {
+	authorizer := s.authorizer
+	authorizer.Tag = s.AdminUserTag(c)
+	_, err := machineactions.NewMachineActionsAPI(s.State, s.resources, authorizer)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *machineActionsSuite) TestWatchActionNotifications(c *gc.C) {
+	action := s.addAction(c, s.machine0)
+
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: s.machine0.Tag().String()},
+		{Tag: s.machine1.Tag().String()},
+		{Tag: "unit-mysql-0"},
+	}}
+	result, err := s.api.WatchActionNotifications(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.StringsWatchResults{
+		Results: []params.StringsWatchResult{
+			{StringsWatcherId: "1", Changes: []string{action.Id()}},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	c.Assert(s.resources.Count(), gc.Equals, 1)
+	resource := s.resources.Get("1")
+	defer statetesting.AssertStop(c, resource)
+
+	w := resource.(state.StringsWatcher)
+	wc := statetesting.NewStringsWatcherC(c, s.State, w)
+	wc.AssertNoChange()
+
+	s.addAction(c, s.machine1)
+	wc.AssertNoChange()
+
+	next := s.addAction(c, s.machine0)
+	wc.AssertChange(next.Id())
+	wc.AssertNoChange()
+}
+
+func (s *machineActionsSuite) TestActions(c *gc.C) {
+	mine := s.addAction(c, s.machine0)
+	theirs := s.addAction(c, s.machine1)
+
+	result, err := s.api.Actions(params.Entities{Entities: []params.Entity{
+		{Tag: mine.Tag().String()},
+		{Tag: theirs.Tag().String()},
+		{Tag: "machine-0"},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 3)
+	c.Check(result.Results[0].Error, gc.IsNil)
+	c.Check(result.Results[0].Action.Action, jc.DeepEquals, &params.Action{
+		Name:       actions.JujuRunActionName,
+		Parameters: mine.Parameters(),
+	})
+	c.Check(result.Results[1].Error, jc.DeepEquals, apiservertesting.ErrUnauthorized)
+	c.Check(result.Results[2].Error, gc.NotNil)
+}
+
+func (s *machineActionsSuite) TestBeginAndFinishActions(c *gc.C) {
+	action := s.addAction(c, s.machine0)
+	args := params.Entities{Entities: []params.Entity{{Tag: action.Tag().String()}}}
+
+	result, err := s.api.BeginActions(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.ErrorResults{Results: []params.ErrorResult{{}}})
+
+	running, err := s.machine0.RunningActions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(running, gc.HasLen, 1)
+
+	result, err = s.api.FinishActions(params.ActionExecutionResults{
+		Results: []params.ActionExecutionResult{{
+			ActionTag: action.Tag().String(),
+			Status:    params.ActionCompleted,
+			Results:   map[string]interface{}{"Code": "0", "Stdout": "somehost\n"},
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.ErrorResults{Results: []params.ErrorResult{{}}})
+
+	completed, err := s.machine0.CompletedActions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(completed, gc.HasLen, 1)
+	c.Assert(completed[0].Status(), gc.Equals, state.ActionCompleted)
+	output, _ := completed[0].Results()
+	c.Assert(output, jc.DeepEquals, map[string]interface{}{"Code": "0", "Stdout": "somehost\n"})
+}
@@ -0,0 +1,97 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/common"
+	apihttp "github.com/juju/juju/apiserver/http"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/rpc/rpcmetrics"
+)
+
+// metricsHandler serves the API server's call statistics over HTTPS.
+// By default the statistics are sent as JSON; a "format=text" query
+// selects a line-based text format suitable for metrics scrapers.
+type metricsHandler struct {
+	httpHandler
+	metrics *rpcmetrics.Recorder
+}
+
+func (h *metricsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	stateWrapper, err := h.validateEnvironUUID(r)
+	if err != nil {
+		h.sendError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	defer stateWrapper.cleanup()
+
+	tag, err := stateWrapper.authenticateUser(r)
+	if err != nil {
+		h.authError(w, h)
+		return
+	}
+	if err := checkStateServerAdmin(stateWrapper.state, tag); err != nil {
+		h.sendError(w, http.StatusForbidden, err.Error())
+		return
+	}
+	if r.Method != "GET" {
+		h.sendError(w, http.StatusMethodNotAllowed, fmt.Sprintf("unsupported method: %q", r.Method))
+		return
+	}
+	result := rpcMetricsResult(h.metrics)
+	if r.URL.Query().Get("format") == "text" {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		writeMetricsText(w, result)
+		return
+	}
+	h.sendJSON(w, http.StatusOK, result)
+}
+
+// writeMetricsText writes the given statistics to w, one
+// metric per line, with times expressed in seconds.
+func writeMetricsText(w io.Writer, result params.RPCMetricsResult) {
+	for _, m := range result.Methods {
+		labels := fmt.Sprintf("facade=%q,version=\"%d\",method=%q", m.Facade, m.Version, m.Method)
+		fmt.Fprintf(w, "juju_api_calls_total{%s} %d\n", labels, m.Calls)
+		fmt.Fprintf(w, "juju_api_errors_total{%s} %d\n", labels, m.Errors)
+		fmt.Fprintf(w, "juju_api_call_seconds_sum{%s} %g\n", labels, m.TotalTime.Seconds())
+		fmt.Fprintf(w, "juju_api_call_seconds_max{%s} %g\n", labels, m.MaxTime.Seconds())
+		var cumulative int64
+		for i, count := range m.Buckets {
+			cumulative += count
+			le := "+Inf"
+			if i < len(result.LatencyBuckets) {
+				le = fmt.Sprintf("%g", result.LatencyBuckets[i].Seconds())
+			}
+			fmt.Fprintf(w, "juju_api_call_seconds_bucket{%s,le=%q} %d\n", labels, le, cumulative)
+		}
+	}
+}
+
+// sendJSON sends a JSON-encoded result.
+func (h *metricsHandler) sendJSON(w http.ResponseWriter, statusCode int, result interface{}) {
+	body, err := json.Marshal(result)
+	if err != nil {
+		logger.Errorf("failed to serialize the result (%v): %v", result, err)
+		return
+	}
+	w.Header().Set("Content-Type", apihttp.CTypeJSON)
+	w.WriteHeader(statusCode)
+	w.Write(body)
+}
+
+// sendError sends a JSON-encoded error response.
+func (h *metricsHandler) sendError(w http.ResponseWriter, statusCode int, message string) {
+	logger.Debugf("sending error: %v %v", statusCode, message)
+	err := common.ServerError(errors.New(message))
+	h.sendJSON(w, statusCode, &params.ErrorResult{Error: err})
+}
@@ -0,0 +1,97 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	apihttp "github.com/juju/juju/apiserver/http"
+	"github.com/juju/juju/apiserver/params"
+)
+
+type metricsSuite struct {
+	authHttpSuite
+}
+
+var _ = gc.Suite(&metricsSuite{})
+
+func (s *metricsSuite) metricsURI(c *gc.C, query string) string {
+	uri := s.baseURL(c)
+	uri.Path = fmt.Sprintf("/environment/%s/metrics", s.envUUID)
+	uri.RawQuery = query
+	return uri.String()
+}
+
+func (s *metricsSuite) adminRequest(c *gc.C, method, query string) *http.Response {
+	info := s.APIInfo(c)
+	resp, err := s.sendRequest(c, info.Tag.String(), info.Password, method, s.metricsURI(c, query), "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	return resp
+}
+
+func (s *metricsSuite) TestRequiresAuth(c *gc.C) {
+	resp, err := s.sendRequest(c, "", "", "GET", s.metricsURI(c, ""), "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertErrorResponse(c, resp, http.StatusUnauthorized, "unauthorized")
+}
+
+func (s *metricsSuite) TestRequiresStateServerAdmin(c *gc.C) {
+	resp, err := s.authRequest(c, "GET", s.metricsURI(c, ""), "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertErrorResponse(c, resp, http.StatusForbidden, "permission denied")
+}
+
+func (s *metricsSuite) TestRequiresGET(c *gc.C) {
+	resp := s.adminRequest(c, "PUT", "")
+	s.assertErrorResponse(c, resp, http.StatusMethodNotAllowed, `unsupported method: "PUT"`)
+}
+
+func (s *metricsSuite) TestMetricsJSON(c *gc.C) {
+	// Make sure at least one API call has been recorded.
+	_, err := s.APIState.Client().Status(nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	resp := s.adminRequest(c, "GET", "")
+	body := assertResponse(c, resp, http.StatusOK, apihttp.CTypeJSON)
+	var result params.RPCMetricsResult
+	err = json.Unmarshal(body, &result)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.LatencyBuckets, gc.Not(gc.HasLen), 0)
+
+	var found bool
+	for _, m := range result.Methods {
+		if m.Facade == "Client" && m.Method == "FullStatus" {
+			found = true
+			c.Check(m.Calls >= 1, jc.IsTrue)
+			c.Check(m.Buckets, gc.HasLen, len(result.LatencyBuckets)+1)
+		}
+	}
+	c.Assert(found, jc.IsTrue)
+}
+
+func (s *metricsSuite) TestMetricsText(c *gc.C) {
+	_, err := s.APIState.Client().Status(nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	resp := s.adminRequest(c, "GET", "format=text")
+	body := string(assertResponse(c, resp, http.StatusOK, "text/plain; charset=utf-8"))
+	labels := `facade="Client",version="0",method="FullStatus"`
+	c.Check(body, jc.Contains, "juju_api_calls_total{"+labels+"} ")
+	c.Check(body, jc.Contains, "juju_api_errors_total{"+labels+"} 0\n")
+	c.Check(body, jc.Contains, "juju_api_call_seconds_bucket{"+labels+`,le="+Inf"} `)
+}
+
+func (s *metricsSuite) assertErrorResponse(c *gc.C, resp *http.Response, expCode int, expError string) {
+	body := assertResponse(c, resp, expCode, apihttp.CTypeJSON)
+	var result params.ErrorResult
+	err := json.Unmarshal(body, &result)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Error, gc.NotNil)
+	c.Assert(result.Error, gc.ErrorMatches, expError)
+}
@@ -4,9 +4,12 @@
 package params
 
 import (
+	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/juju/errors"
+	"gopkg.in/macaroon.v1"
 
 	"github.com/juju/juju/rpc"
 )
@@ -15,6 +18,14 @@ import (
 type Error struct {
 	Message string
 	Code    string
+
+	// RetryAfter holds the delay that a client turned away with
+	// CodeTryAgain is asked to wait before retrying, if any.
+	RetryAfter time.Duration `json:",omitempty"`
+
+	// Macaroon holds the macaroon that a client turned away with
+	// CodeDischargeRequired must have discharged to log in.
+	Macaroon *macaroon.Macaroon `json:",omitempty"`
 }
 
 func (e *Error) Error() string {
@@ -25,7 +36,33 @@ func (e *Error) ErrorCode() string {
 	return e.Code
 }
 
-var _ rpc.ErrorCoder = (*Error)(nil)
+// The keys of the structured fields of an error
+// in its details.
+const (
+	retryAfterInfo = "RetryAfter"
+	macaroonInfo   = "Macaroon"
+)
+
+// ErrorInfo implements rpc.ErrorInfoer, so that the
+// structured fields of the error reach the client.
+func (e *Error) ErrorInfo() map[string]interface{} {
+	if e.RetryAfter == 0 && e.Macaroon == nil {
+		return nil
+	}
+	info := make(map[string]interface{})
+	if e.RetryAfter != 0 {
+		info[retryAfterInfo] = e.RetryAfter
+	}
+	if e.Macaroon != nil {
+		info[macaroonInfo] = e.Macaroon
+	}
+	return info
+}
+
+var (
+	_ rpc.ErrorCoder  = (*Error)(nil)
+	_ rpc.ErrorInfoer = (*Error)(nil)
+)
 
 // GoString implements fmt.GoStringer.  It means that a *Error shows its
 // contents correctly when printed with %#v.
@@ -35,24 +72,26 @@ func (e Error) GoString() string {
 
 // The Code constants hold error codes for some kinds of error.
 const (
-	CodeNotFound            = "not found"
-	CodeUnauthorized        = "unauthorized access"
-	CodeCannotEnterScope    = "cannot enter scope"
-	CodeCannotEnterScopeYet = "cannot enter scope yet"
-	CodeExcessiveContention = "excessive contention"
-	CodeUnitHasSubordinates = "unit has subordinates"
-	CodeNotAssigned         = "not assigned"
-	CodeStopped             = "stopped"
-	CodeDead                = "dead"
-	CodeHasAssignedUnits    = "machine has assigned units"
-	CodeNotProvisioned      = "not provisioned"
-	CodeNoAddressSet        = "no address set"
-	CodeTryAgain            = "try again"
-	CodeNotImplemented      = rpc.CodeNotImplemented
-	CodeAlreadyExists       = "already exists"
-	CodeUpgradeInProgress   = "upgrade in progress"
-	CodeActionNotAvailable  = "action no longer available"
-	CodeOperationBlocked    = "operation is blocked"
+	CodeNotFound              = "not found"
+	CodeUnauthorized          = "unauthorized access"
+	CodeCannotEnterScope      = "cannot enter scope"
+	CodeCannotEnterScopeYet   = "cannot enter scope yet"
+	CodeExcessiveContention   = "excessive contention"
+	CodeUnitHasSubordinates   = "unit has subordinates"
+	CodeNotAssigned           = "not assigned"
+	CodeStopped               = "stopped"
+	CodeDead                  = "dead"
+	CodeHasAssignedUnits      = "machine has assigned units"
+	CodeNotProvisioned        = "not provisioned"
+	CodeNoAddressSet          = "no address set"
+	CodeTryAgain              = "try again"
+	CodeNotImplemented        = rpc.CodeNotImplemented
+	CodeAlreadyExists         = "already exists"
+	CodeUpgradeInProgress     = "upgrade in progress"
+	CodeActionNotAvailable    = "action no longer available"
+	CodeOperationBlocked      = "operation is blocked"
+	CodeLeadershipClaimDenied = "leadership claim denied"
+	CodeDischargeRequired     = "macaroon discharge required"
 )
 
 // ErrCode returns the error code associated with
@@ -78,9 +117,41 @@ func ClientError(err error) error {
 	// within the error message. Also, it's best not to make clients
 	// know that we're using the rpc package.
 	return &Error{
-		Message: rerr.Message,
-		Code:    rerr.Code,
+		Message:    rerr.Message,
+		Code:       rerr.Code,
+		RetryAfter: infoDuration(rerr.Info, retryAfterInfo),
+		Macaroon:   infoMacaroon(rerr.Info, macaroonInfo),
+	}
+}
+
+// infoDuration returns the duration held in the given
+// error details, or zero if there is none.
+func infoDuration(info map[string]interface{}, key string) time.Duration {
+	// Numbers in the details are decoded from JSON as float64.
+	if d, ok := info[key].(float64); ok {
+		return time.Duration(d)
+	}
+	return 0
+}
+
+// infoMacaroon returns the macaroon held in the given
+// error details, or nil if there is none.
+func infoMacaroon(info map[string]interface{}, key string) *macaroon.Macaroon {
+	v, ok := info[key]
+	if !ok {
+		return nil
+	}
+	// The macaroon in the details has been decoded from JSON as
+	// a generic map, so encode it again to decode it properly.
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var m macaroon.Macaroon
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil
 	}
+	return &m
 }
 
 func IsCodeActionNotAvailable(err error) bool {
@@ -148,6 +219,20 @@ func IsCodeTryAgain(err error) bool {
 	return ErrCode(err) == CodeTryAgain
 }
 
+// RetryAfter returns the delay suggested by the given CodeTryAgain
+// error. It returns false if err is not a CodeTryAgain error or if
+// it does not suggest a delay.
+func RetryAfter(err error) (time.Duration, bool) {
+	if !IsCodeTryAgain(err) {
+		return 0, false
+	}
+	perr, ok := errors.Cause(err).(*Error)
+	if !ok || perr.RetryAfter <= 0 {
+		return 0, false
+	}
+	return perr.RetryAfter, true
+}
+
 func IsCodeNotImplemented(err error) bool {
 	return ErrCode(err) == CodeNotImplemented
 }
@@ -163,3 +248,25 @@ func IsCodeUpgradeInProgress(err error) bool {
 func IsCodeOperationBlocked(err error) bool {
 	return ErrCode(err) == CodeOperationBlocked
 }
+
+func IsCodeLeadershipClaimDenied(err error) bool {
+	return ErrCode(err) == CodeLeadershipClaimDenied
+}
+
+func IsCodeDischargeRequired(err error) bool {
+	return ErrCode(err) == CodeDischargeRequired
+}
+
+// DischargeRequiredMacaroon returns the macaroon held by the given
+// CodeDischargeRequired error. It returns false if err is not a
+// CodeDischargeRequired error or if it holds no macaroon.
+func DischargeRequiredMacaroon(err error) (*macaroon.Macaroon, bool) {
+	if !IsCodeDischargeRequired(err) {
+		return nil, false
+	}
+	perr, ok := errors.Cause(err).(*Error)
+	if !ok || perr.Macaroon == nil {
+		return nil, false
+	}
+	return perr.Macaroon, true
+}
@@ -4,10 +4,16 @@
 package params_test
 
 import (
+	"encoding/json"
+	"time"
+
 	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
+	"gopkg.in/macaroon.v1"
 
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/rpc"
 )
 
 type errorSuite struct{}
@@ -22,3 +28,93 @@ func (*errorSuite) TestErrCode(c *gc.C) {
 	err = errors.Trace(err)
 	c.Check(params.ErrCode(err), gc.Equals, params.CodeDead)
 }
+
+func (*errorSuite) TestRetryAfter(c *gc.C) {
+	err := &params.Error{
+		Code:       params.CodeTryAgain,
+		Message:    "try again",
+		RetryAfter: 1500 * time.Millisecond,
+	}
+	delay, ok := params.RetryAfter(errors.Trace(err))
+	c.Check(ok, jc.IsTrue)
+	c.Check(delay, gc.Equals, 1500*time.Millisecond)
+}
+
+func (*errorSuite) TestClientErrorRetryAfter(c *gc.C) {
+	err := &params.Error{
+		Code:       params.CodeTryAgain,
+		Message:    "try again",
+		RetryAfter: 2 * time.Second,
+	}
+	// The details of the error reach the client as JSON.
+	data, jerr := json.Marshal(err.ErrorInfo())
+	c.Assert(jerr, jc.ErrorIsNil)
+	var info map[string]interface{}
+	jerr = json.Unmarshal(data, &info)
+	c.Assert(jerr, jc.ErrorIsNil)
+
+	cerr := params.ClientError(&rpc.RequestError{
+		Message: err.Message,
+		Code:    err.Code,
+		Info:    info,
+	})
+	c.Assert(cerr, jc.DeepEquals, err)
+}
+
+func (*errorSuite) TestErrorInfoEmpty(c *gc.C) {
+	err := &params.Error{Code: params.CodeTryAgain, Message: "try again"}
+	c.Assert(err.ErrorInfo(), gc.IsNil)
+}
+
+func (*errorSuite) TestRetryAfterNoDelay(c *gc.C) {
+	for i, err := range []error{
+		&params.Error{Code: params.CodeTryAgain, Message: "try again"},
+		&params.Error{Code: params.CodeDead, Message: "dead", RetryAfter: time.Second},
+		&params.Error{Code: params.CodeTryAgain, Message: "try again after 5s"},
+		errors.New("boom"),
+	} {
+		c.Logf("test %d: %v", i, err)
+		_, ok := params.RetryAfter(err)
+		c.Check(ok, jc.IsFalse)
+	}
+}
+
+func (*errorSuite) TestClientErrorMacaroon(c *gc.C) {
+	m, err := macaroon.New([]byte("root key"), "id", "juju")
+	c.Assert(err, jc.ErrorIsNil)
+	err = m.AddFirstPartyCaveat("environment-uuid some-uuid")
+	c.Assert(err, jc.ErrorIsNil)
+	perr := &params.Error{
+		Code:     params.CodeDischargeRequired,
+		Message:  "discharge required",
+		Macaroon: m,
+	}
+	// The details of the error reach the client as JSON.
+	data, err := json.Marshal(perr.ErrorInfo())
+	c.Assert(err, jc.ErrorIsNil)
+	var info map[string]interface{}
+	err = json.Unmarshal(data, &info)
+	c.Assert(err, jc.ErrorIsNil)
+
+	cerr := params.ClientError(&rpc.RequestError{
+		Message: perr.Message,
+		Code:    perr.Code,
+		Info:    info,
+	})
+	got, ok := params.DischargeRequiredMacaroon(errors.Trace(cerr))
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(got.Signature(), jc.DeepEquals, m.Signature())
+	c.Assert(got.Caveats(), jc.DeepEquals, m.Caveats())
+}
+
+func (*errorSuite) TestDischargeRequiredMacaroonMissing(c *gc.C) {
+	for i, err := range []error{
+		&params.Error{Code: params.CodeDischargeRequired, Message: "discharge required"},
+		&params.Error{Code: params.CodeDead, Message: "dead", Macaroon: &macaroon.Macaroon{}},
+		errors.New("boom"),
+	} {
+		c.Logf("test %d: %v", i, err)
+		_, ok := params.DischargeRequiredMacaroon(err)
+		c.Check(ok, jc.IsFalse)
+	}
+}
@@ -135,6 +135,30 @@ type StringResults struct {
 	Results []StringResult
 }
 
+// DurationResult holds the result of an API call that returns a
+// time.Duration or an error.
+type DurationResult struct {
+	Error  *Error
+	Result time.Duration
+}
+
+// DurationResults holds the bulk operation result of an API call
+// that returns a time.Duration or an error.
+type DurationResults struct {
+	Results []DurationResult
+}
+
+// UnitHookRecord holds a hook record to be added to a unit's hook history.
+type UnitHookRecord struct {
+	Tag    string
+	Record HookRecord
+}
+
+// UnitHookRecords holds the parameters for making an AddHookRecords call.
+type UnitHookRecords struct {
+	Records []UnitHookRecord
+}
+
 // EnvironmentResult holds the result of an API call returning a name and UUID
 // for an environment.
 type EnvironmentResult struct {
@@ -739,6 +763,7 @@ type ProvisioningInfo struct {
 	Networks    []string
 	Jobs        []multiwatcher.MachineJob
 	Volumes     []storage.VolumeParams
+	Tags        map[string]string
 }
 
 // ProvisioningInfoResult holds machine provisioning info or an error.
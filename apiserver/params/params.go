@@ -187,6 +187,17 @@ type DestroyMachines struct {
 	Force        bool
 }
 
+// SetMachineMaintenance holds parameters for the SetMachineMaintenance call.
+type SetMachineMaintenance struct {
+	Entities    []Entity
+	Maintenance bool
+
+	// RelocateUnits, if true when putting machines into maintenance,
+	// causes stateless units of services with more than one unit to
+	// be moved off the machines.
+	RelocateUnits bool
+}
+
 // ServiceDeploy holds the parameters for making the ServiceDeploy call.
 type ServiceDeploy struct {
 	ServiceName   string
@@ -206,11 +217,44 @@ type ServiceUpdate struct {
 	CharmUrl        string
 	ForceCharmUrl   bool
 	MinUnits        *int
+	HookTimeout     *time.Duration
 	SettingsStrings map[string]string
 	SettingsYAML    string // Takes precedence over SettingsStrings if both are present.
 	Constraints     *constraints.Value
 }
 
+// HookRecord describes a single hook, action or set of commands run
+// by a unit agent.
+type HookRecord struct {
+	Kind       string
+	Hook       string
+	Relation   string
+	RemoteUnit string
+	Started    time.Time
+	Duration   time.Duration
+	Result     string
+}
+
+// The kinds of operation recorded in a unit's hook history.
+const (
+	HookRecordHook     = "hook"
+	HookRecordAction   = "action"
+	HookRecordCommands = "commands"
+)
+
+// HookHistoryResult holds the hooks, actions and commands recorded for a
+// unit, most recent first, or an error.
+type HookHistoryResult struct {
+	Error   *Error
+	Records []HookRecord
+}
+
+// HookHistoryResults holds the bulk operation result of an API call
+// that returns unit hook histories.
+type HookHistoryResults struct {
+	Results []HookHistoryResult
+}
+
 // ServiceSetCharm sets the charm for a given service.
 type ServiceSetCharm struct {
 	ServiceName string
@@ -218,6 +262,24 @@ type ServiceSetCharm struct {
 	Force       bool
 }
 
+// ServiceSetCharmRollout holds the parameters for making the
+// ServiceSetCharmRollout call, which upgrades the service's units
+// BatchSize at a time.
+type ServiceSetCharmRollout struct {
+	ServiceName string
+	CharmUrl    string
+	Force       bool
+	BatchSize   int
+	WaitHealthy bool
+}
+
+// ServiceRollbackCharm holds the parameters for making the
+// ServiceRollbackCharm call.
+type ServiceRollbackCharm struct {
+	ServiceName string
+	Force       bool
+}
+
 // ServiceExpose holds the parameters for making the ServiceExpose call.
 type ServiceExpose struct {
 	ServiceName string
@@ -396,6 +458,13 @@ type SetConstraints struct {
 	Constraints constraints.Value
 }
 
+// SetMachineConstraints stores parameters for making the
+// SetMachineConstraints call.
+type SetMachineConstraints struct {
+	MachineId   string
+	Constraints constraints.Value
+}
+
 // ResolveCharms stores charm references for a ResolveCharms call.
 type ResolveCharms struct {
 	References []charm.Reference
@@ -540,14 +609,43 @@ type EnvironAction string
 
 // Actions that can be preformed on an environment.
 const (
-	AddEnvUser    EnvironAction = "add"
-	RemoveEnvUser EnvironAction = "remove"
+	AddEnvUser       EnvironAction = "add"
+	RemoveEnvUser    EnvironAction = "remove"
+	SetEnvUserAccess EnvironAction = "set-access"
+)
+
+// EnvironAccess is a level of access to an environment.
+type EnvironAccess string
+
+// Levels of access to an environment.
+const (
+	ReadEnvironAccess  EnvironAccess = "read"
+	WriteEnvironAccess EnvironAccess = "write"
+	AdminEnvironAccess EnvironAccess = "admin"
 )
 
 // ModifyEnvironUser stores the parameters used for a Client.ShareEnvironment call.
+// If Access is empty when adding a user, the user is given admin access.
 type ModifyEnvironUser struct {
 	UserTag string        `json:"user-tag"`
 	Action  EnvironAction `json:"action"`
+	Access  EnvironAccess `json:"access,omitempty"`
+}
+
+// ModifyEnvironGroups holds the parameters for making
+// Client.ShareEnvironmentGroups calls.
+type ModifyEnvironGroups struct {
+	Changes []ModifyEnvironGroup
+}
+
+// ModifyEnvironGroup represents a change to the access to an
+// environment granted to the members of an external group.
+// If Access is empty when adding a group, its members are
+// given admin access.
+type ModifyEnvironGroup struct {
+	Group  string        `json:"group"`
+	Action EnvironAction `json:"action"`
+	Access EnvironAccess `json:"access,omitempty"`
 }
 
 // SetEnvironAgentVersion contains the arguments for
@@ -683,6 +781,10 @@ type LoginResultV1 struct {
 	// Facades describes all the available API facade versions to the
 	// authenticated client.
 	Facades []FacadeVersions `json:"facades"`
+
+	// BatchCalls reports whether the server accepts several
+	// requests sent together in a single message.
+	BatchCalls bool `json:"batch-calls,omitempty"`
 }
 
 // StateServersSpec contains arguments for
@@ -888,4 +990,7 @@ const (
 	// The unit believes it is correctly offering all the services it has
 	// been asked to offer.
 	StatusRunning Status = "running"
+
+	// The charm has not yet reported the state of its software.
+	StatusUnknown Status = "unknown"
 )
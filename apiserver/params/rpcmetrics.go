@@ -0,0 +1,34 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params
+
+import (
+	"time"
+)
+
+// RPCMethodMetrics holds the call statistics recorded by an API
+// server for a single method of a versioned facade.
+type RPCMethodMetrics struct {
+	Facade    string
+	Version   int
+	Method    string
+	Calls     int64
+	Errors    int64
+	TotalTime time.Duration
+	MaxTime   time.Duration
+
+	// Buckets holds the latency histogram, with one count for each
+	// entry in RPCMetricsResult.LatencyBuckets followed by a count
+	// of the calls slower than the last bucket.
+	Buckets []int64
+}
+
+// RPCMetricsResult holds the call statistics recorded
+// by an API server since it started.
+type RPCMetricsResult struct {
+	// LatencyBuckets holds the upper bounds of the
+	// latency histogram buckets.
+	LatencyBuckets []time.Duration
+	Methods        []RPCMethodMetrics
+}
@@ -15,6 +15,10 @@ type UserInfo struct {
 	DateCreated    time.Time  `json:"date-created"`
 	LastConnection *time.Time `json:"last-connection,omitempty"`
 	Disabled       bool       `json:"disabled"`
+
+	// Access holds the user's level of access to the environment
+	// of the API connection. It is empty if the user has no access.
+	Access EnvironAccess `json:"access,omitempty"`
 }
 
 // UserInfoResult holds the result of a UserInfo call.
@@ -14,6 +14,7 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/container"
+	"github.com/juju/juju/environs/tags"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/multiwatcher"
@@ -352,6 +353,11 @@ func (p *ProvisionerAPI) ProvisioningInfo(args params.Entities) (params.Provisio
 	if err != nil {
 		return result, err
 	}
+	envConfig, err := p.st.EnvironConfig()
+	if err != nil {
+		return result, err
+	}
+	resourceTags := tags.ResourceTags(p.st.EnvironUUID(), envConfig.ResourceTags())
 	for i, entity := range args.Entities {
 		tag, err := names.ParseMachineTag(entity.Tag)
 		if err != nil {
@@ -360,14 +366,14 @@ func (p *ProvisionerAPI) ProvisioningInfo(args params.Entities) (params.Provisio
 		}
 		machine, err := p.getMachine(canAccess, tag)
 		if err == nil {
-			result.Results[i].Result, err = getProvisioningInfo(machine)
+			result.Results[i].Result, err = getProvisioningInfo(machine, resourceTags)
 		}
 		result.Results[i].Error = common.ServerError(err)
 	}
 	return result, nil
 }
 
-func getProvisioningInfo(m *state.Machine) (*params.ProvisioningInfo, error) {
+func getProvisioningInfo(m *state.Machine, resourceTags map[string]string) (*params.ProvisioningInfo, error) {
 	cons, err := m.Constraints()
 	if err != nil {
 		return nil, err
@@ -397,6 +403,7 @@ func getProvisioningInfo(m *state.Machine) (*params.ProvisioningInfo, error) {
 		Networks:    networks,
 		Jobs:        jobs,
 		Volumes:     volumes,
+		Tags:        tags.InstanceTags(resourceTags, m.Id(), m.IsManager(), m.Principals()),
 	}, nil
 }
 
@@ -708,6 +715,41 @@ func (p *ProvisionerAPI) SetInstanceInfo(args params.InstancesInfo) (params.Erro
 	return result, nil
 }
 
+// WatchConstraints starts a NotifyWatcher for the constraints of each
+// given machine entity, so that the resource limits of containers can
+// be updated when their constraints change.
+func (p *ProvisionerAPI) WatchConstraints(args params.Entities) (params.NotifyWatchResults, error) {
+	result := params.NotifyWatchResults{
+		Results: make([]params.NotifyWatchResult, len(args.Entities)),
+	}
+	canAccess, err := p.getAuthFunc()
+	if err != nil {
+		return result, err
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseMachineTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		machine, err := p.getMachine(canAccess, tag)
+		if err == nil {
+			watch := machine.WatchConstraints()
+			// Consume the initial event. Technically, API
+			// calls to Watch 'transmit' the initial event
+			// in the Watch response. But NotifyWatchers
+			// have no state to transmit.
+			if _, ok := <-watch.Changes(); ok {
+				result.Results[i].NotifyWatcherId = p.resources.Register(watch)
+			} else {
+				err = watcher.EnsureErr(watch)
+			}
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
 // WatchMachineErrorRetry returns a NotifyWatcher that notifies when
 // the provisioner should retry provisioning machines with transient errors.
 func (p *ProvisionerAPI) WatchMachineErrorRetry() (params.NotifyWatchResult, error) {
@@ -20,6 +20,7 @@ import (
 	apiservertesting "github.com/juju/juju/apiserver/testing"
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/container"
+	"github.com/juju/juju/environs/tags"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/network"
@@ -752,6 +753,10 @@ func (s *withoutStateServerSuite) TestProvisioningInfo(c *gc.C) {
 				Series:   "quantal",
 				Networks: []string{},
 				Jobs:     []multiwatcher.MachineJob{multiwatcher.JobHostUnits},
+				Tags: map[string]string{
+					tags.JujuEnv:     s.State.EnvironUUID(),
+					tags.JujuMachine: "0",
+				},
 			}},
 			{Result: &params.ProvisioningInfo{
 				Series:      "quantal",
@@ -760,6 +765,10 @@ func (s *withoutStateServerSuite) TestProvisioningInfo(c *gc.C) {
 				Networks:    template.RequestedNetworks,
 				Jobs:        []multiwatcher.MachineJob{multiwatcher.JobHostUnits},
 				Volumes:     []storage.VolumeParams{{Name: "0", Size: 1000}, {Name: "1", Size: 2000}},
+				Tags: map[string]string{
+					tags.JujuEnv:     s.State.EnvironUUID(),
+					tags.JujuMachine: placementMachine.Id(),
+				},
 			}},
 			{Error: apiservertesting.NotFoundError("machine 42")},
 			{Error: apiservertesting.ErrUnauthorized},
@@ -768,6 +777,31 @@ func (s *withoutStateServerSuite) TestProvisioningInfo(c *gc.C) {
 	})
 }
 
+func (s *withoutStateServerSuite) TestProvisioningInfoTags(c *gc.C) {
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{
+		"resource-tags": "owner=finance",
+	}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	unit, err := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress")).AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AssignToMachine(s.machines[0])
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: s.machines[0].Tag().String()},
+	}}
+	result, err := s.provisioner.ProvisioningInfo(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 1)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Assert(result.Results[0].Result.Tags, jc.DeepEquals, map[string]string{
+		"owner":                "finance",
+		tags.JujuEnv:           s.State.EnvironUUID(),
+		tags.JujuMachine:       "0",
+		tags.JujuUnitsDeployed: "wordpress/0",
+	})
+}
+
 func (s *withoutStateServerSuite) TestProvisioningInfoPermissions(c *gc.C) {
 	// Login as a machine agent for machine 0.
 	anAuthorizer := s.authorizer
@@ -793,6 +827,10 @@ func (s *withoutStateServerSuite) TestProvisioningInfoPermissions(c *gc.C) {
 				Series:   "quantal",
 				Networks: []string{},
 				Jobs:     []multiwatcher.MachineJob{multiwatcher.JobHostUnits},
+				Tags: map[string]string{
+					tags.JujuEnv:     s.State.EnvironUUID(),
+					tags.JujuMachine: "0",
+				},
 			}},
 			{Error: apiservertesting.NotFoundError("machine 0/lxc/0")},
 			{Error: apiservertesting.ErrUnauthorized},
@@ -836,6 +874,38 @@ func (s *withoutStateServerSuite) TestConstraints(c *gc.C) {
 	})
 }
 
+func (s *withoutStateServerSuite) TestWatchConstraints(c *gc.C) {
+	c.Assert(s.resources.Count(), gc.Equals, 0)
+
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: s.machines[0].Tag().String()},
+		{Tag: "machine-42"},
+		{Tag: "unit-foo-0"},
+	}}
+	result, err := s.provisioner.WatchConstraints(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.NotifyWatchResults{
+		Results: []params.NotifyWatchResult{
+			{NotifyWatcherId: "1"},
+			{Error: apiservertesting.NotFoundError("machine 42")},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	// Verify the resource was registered and stop it when done.
+	c.Assert(s.resources.Count(), gc.Equals, 1)
+	resource := s.resources.Get("1")
+	defer statetesting.AssertStop(c, resource)
+
+	// Check that the Watch has consumed the initial event ("returned"
+	// in the Watch call), and reports further changes.
+	wc := statetesting.NewNotifyWatcherC(c, s.State, resource.(state.NotifyWatcher))
+	wc.AssertNoChange()
+	err = s.machines[0].SetConstraints(constraints.MustParse("mem=4G"))
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+}
+
 func (s *withoutStateServerSuite) TestRequestedNetworks(c *gc.C) {
 	// Add a machine with some requested networks.
 	template := state.MachineTemplate{
@@ -47,6 +47,10 @@ type apiHandler struct {
 	rpcConn    *rpc.Conn
 	resources  *common.Resources
 	entity     state.Entity
+
+	// access holds the level of access an authenticated
+	// client user has to the environment.
+	access state.EnvironmentAccess
 }
 
 var _ = (*apiHandler)(nil)
@@ -68,6 +72,11 @@ func newApiHandler(srv *Server, st *state.State, rpcConn *rpc.Conn, reqNotifier
 	if err := r.resources.RegisterNamed("logDir", common.StringResource(srv.logDir)); err != nil {
 		return nil, errors.Trace(err)
 	}
+	if srv.metrics != nil {
+		if err := r.resources.RegisterNamed("rpcMetrics", rpcMetricsResource{srv.metrics}); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
 	return r, nil
 }
 
@@ -314,6 +323,13 @@ func (r *apiHandler) AuthClient() bool {
 	return isUser
 }
 
+// AuthEnvironAccess returns whether the authenticated entity is a
+// client user with at least the given level of access to the
+// environment.
+func (r *apiHandler) AuthEnvironAccess(access state.EnvironmentAccess) bool {
+	return r.AuthClient() && r.access.Includes(access)
+}
+
 // GetAuthTag returns the tag of the authenticated entity.
 func (r *apiHandler) GetAuthTag() names.Tag {
 	return r.entity.Tag()
@@ -28,6 +28,7 @@ var _ = gc.Suite(&rootSuite{})
 
 var allowedDiscardedMethods = []string{
 	"AuthClient",
+	"AuthEnvironAccess",
 	"AuthEnvironManager",
 	"AuthMachineAgent",
 	"AuthOwner",
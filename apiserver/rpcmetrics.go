@@ -0,0 +1,89 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/rpc/rpcmetrics"
+	"github.com/juju/juju/state"
+)
+
+func init() {
+	common.RegisterStandardFacade("RPCMetrics", 1, NewRPCMetricsAPI)
+}
+
+// rpcMetricsResource makes the server's metrics recorder
+// available to facades through the connection's resources.
+type rpcMetricsResource struct {
+	*rpcmetrics.Recorder
+}
+
+// Stop implements common.Resource.
+func (rpcMetricsResource) Stop() error {
+	return nil
+}
+
+// RPCMetricsAPI provides access to the call statistics
+// recorded by the API server. The statistics cover every
+// environment the server hosts, so only the owner of the
+// state server environment may use it.
+type RPCMetricsAPI struct {
+	metrics *rpcmetrics.Recorder
+}
+
+// NewRPCMetricsAPI returns a new RPCMetricsAPI.
+func NewRPCMetricsAPI(
+	st *state.State, resources *common.Resources, authorizer common.Authorizer,
+) (
+	*RPCMetricsAPI, error,
+) {
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	if err := checkStateServerAdmin(st, authorizer.GetAuthTag()); err != nil {
+		return nil, err
+	}
+	res, ok := resources.Get("rpcMetrics").(rpcMetricsResource)
+	if !ok {
+		return nil, errors.NotSupportedf("rpc metrics")
+	}
+	return &RPCMetricsAPI{metrics: res.Recorder}, nil
+}
+
+// CallMetrics returns the call statistics recorded since
+// the API server started or the metrics were last reset.
+func (api *RPCMetricsAPI) CallMetrics() params.RPCMetricsResult {
+	return rpcMetricsResult(api.metrics)
+}
+
+// ResetCallMetrics discards all recorded call statistics.
+func (api *RPCMetricsAPI) ResetCallMetrics() {
+	api.metrics.Reset()
+}
+
+// rpcMetricsResult converts the statistics held
+// by the given recorder to their wire format.
+func rpcMetricsResult(metrics *rpcmetrics.Recorder) params.RPCMetricsResult {
+	stats := metrics.Stats()
+	result := params.RPCMetricsResult{
+		LatencyBuckets: rpcmetrics.LatencyBuckets,
+		Methods:        make([]params.RPCMethodMetrics, len(stats)),
+	}
+	for i, s := range stats {
+		result.Methods[i] = params.RPCMethodMetrics{
+			Facade:    s.Facade,
+			Version:   s.Version,
+			Method:    s.Method.Method,
+			Calls:     s.Calls,
+			Errors:    s.Errors,
+			TotalTime: s.TotalTime,
+			MaxTime:   s.MaxTime,
+			Buckets:   s.Buckets,
+		}
+	}
+	return result
+}
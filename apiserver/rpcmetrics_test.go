@@ -0,0 +1,104 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/api/rpcmetrics"
+	"github.com/juju/juju/apiserver/params"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/testing/factory"
+)
+
+type rpcMetricsSuite struct {
+	jujutesting.JujuConnSuite
+}
+
+var _ = gc.Suite(&rpcMetricsSuite{})
+
+func (s *rpcMetricsSuite) TestCallMetrics(c *gc.C) {
+	_, err := s.APIState.Client().Status(nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	client := rpcmetrics.NewClient(s.APIState)
+	result, err := client.CallMetrics()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(findMethod(result, "Client", "FullStatus"), gc.NotNil)
+}
+
+func (s *rpcMetricsSuite) TestResetCallMetrics(c *gc.C) {
+	_, err := s.APIState.Client().Status(nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	client := rpcmetrics.NewClient(s.APIState)
+	err = client.ResetCallMetrics()
+	c.Assert(err, jc.ErrorIsNil)
+	result, err := client.CallMetrics()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(findMethod(result, "Client", "FullStatus"), gc.IsNil)
+}
+
+func (s *rpcMetricsSuite) TestUnknownMethodsRecordedTogether(c *gc.C) {
+	for _, facade := range []string{"NoSuchFacade", "NorThisOne"} {
+		err := s.APIState.APICall(facade, 0, "", "Frobnicate", nil, nil)
+		c.Assert(err, gc.NotNil)
+	}
+
+	client := rpcmetrics.NewClient(s.APIState)
+	result, err := client.CallMetrics()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(findMethod(result, "NoSuchFacade", "Frobnicate"), gc.IsNil)
+	c.Assert(findMethod(result, "NorThisOne", "Frobnicate"), gc.IsNil)
+	unknown := findMethod(result, "<unknown>", "")
+	c.Assert(unknown, gc.NotNil)
+	c.Assert(unknown.Calls, gc.Equals, int64(2))
+	c.Assert(unknown.Errors, gc.Equals, int64(2))
+}
+
+func (s *rpcMetricsSuite) TestRequiresStateServerAdmin(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Password: "secret"})
+	st := s.OpenAPIAs(c, user.UserTag(), "secret")
+	client := rpcmetrics.NewClient(st)
+	_, err := client.CallMetrics()
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *rpcMetricsSuite) TestHostedEnvironmentOwnerDenied(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Password: "secret"})
+	envState := s.Factory.MakeEnvironment(c, &factory.EnvParams{
+		Owner: user.UserTag(),
+	})
+	defer envState.Close()
+
+	info := s.APIInfo(c)
+	info.Tag = user.UserTag()
+	info.Password = "secret"
+	info.EnvironTag = envState.EnvironTag()
+	st, err := api.Open(info, api.DialOpts{})
+	c.Assert(err, jc.ErrorIsNil)
+	defer st.Close()
+
+	client := rpcmetrics.NewClient(st)
+	_, err = client.CallMetrics()
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *rpcMetricsSuite) TestAgentsDenied(c *gc.C) {
+	st, _ := s.OpenAPIAsNewMachine(c)
+	client := rpcmetrics.NewClient(st)
+	_, err := client.CallMetrics()
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func findMethod(result params.RPCMetricsResult, facade, method string) *params.RPCMethodMetrics {
+	for i, m := range result.Methods {
+		if m.Facade == facade && m.Method == method {
+			return &result.Methods[i]
+		}
+	}
+	return nil
+}
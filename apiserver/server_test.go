@@ -96,6 +96,47 @@ func (s *serverSuite) TestStop(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+func (s *serverSuite) TestMaxConnections(c *gc.C) {
+	listener, err := net.Listen("tcp", ":0")
+	c.Assert(err, jc.ErrorIsNil)
+	srv, err := apiserver.NewServer(s.State, listener, apiserver.ServerConfig{
+		Cert:           []byte(coretesting.ServerCert),
+		Key:            []byte(coretesting.ServerKey),
+		Tag:            names.NewMachineTag("0"),
+		MaxConnections: 1,
+		RetryDelay:     3 * time.Second,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	defer srv.Stop()
+
+	info := s.APIInfo(c)
+	info.Addrs = []string{srv.Addr()}
+	st, err := api.Open(info, fastDialOpts)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The zero Timeout in fastDialOpts means that Open gives
+	// up as soon as the server asks it to try again.
+	_, err = api.Open(info, fastDialOpts)
+	c.Assert(err, jc.Satisfies, params.IsCodeTryAgain)
+	delay, ok := params.RetryAfter(err)
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(delay, gc.Equals, 3*time.Second)
+
+	// Once the first connection has gone away,
+	// another one can be made.
+	err = st.Close()
+	c.Assert(err, jc.ErrorIsNil)
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		st, err = api.Open(info, fastDialOpts)
+		if err == nil {
+			st.Close()
+			break
+		}
+		c.Assert(err, jc.Satisfies, params.IsCodeTryAgain)
+	}
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 func (s *serverSuite) TestAPIServerCanListenOnBothIPv4AndIPv6(c *gc.C) {
 	err := s.State.SetAPIHostPorts(nil)
 	c.Assert(err, jc.ErrorIsNil)
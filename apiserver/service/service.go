@@ -48,6 +48,9 @@ func NewAPI(
 
 // SetMetricCredentials sets credentials on the service.
 func (api *API) SetMetricCredentials(args params.ServiceMetricCredentials) (params.ErrorResults, error) {
+	if !api.authorizer.AuthEnvironAccess(state.EnvironWriteAccess) {
+		return params.ErrorResults{}, common.ErrPerm
+	}
 	result := params.ErrorResults{
 		Results: make([]params.ErrorResult, len(args.Creds)),
 	}
@@ -38,7 +38,8 @@ func NewAPI(
 	resources *common.Resources,
 	authorizer common.Authorizer,
 ) (*API, error) {
-	if !authorizer.AuthClient() {
+	// The facade only reports on storage, so read access suffices.
+	if !authorizer.AuthEnvironAccess(state.EnvironReadAccess) {
 		return nil, common.ErrPerm
 	}
 
@@ -4,6 +4,7 @@
 package storage_test
 
 import (
+	"github.com/juju/names"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
@@ -33,6 +34,12 @@ func (s *storageSuite) SetUpTest(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+func (s *storageSuite) TestNewAPIRefusesNonClient(c *gc.C) {
+	authorizer := testing.FakeAuthorizer{Tag: names.NewUnitTag("mysql/0")}
+	_, err := storage.NewAPI(s.State, nil, authorizer)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
 func (s *storageSuite) TestShowStorage(c *gc.C) {
 	// TODO(anastasiamac) update when s.Factory.MakeStorage or similar is available
 	storageTag := "test-storage"
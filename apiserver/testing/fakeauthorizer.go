@@ -5,12 +5,18 @@ package testing
 
 import (
 	"github.com/juju/names"
+
+	"github.com/juju/juju/state"
 )
 
 // FakeAuthorizer implements the common.Authorizer interface.
 type FakeAuthorizer struct {
 	Tag            names.Tag
 	EnvironManager bool
+
+	// Access holds the level of access a client user has to the
+	// environment. If it is empty, the user has admin access.
+	Access state.EnvironmentAccess
 }
 
 func (fa FakeAuthorizer) AuthOwner(tag names.Tag) bool {
@@ -40,6 +46,18 @@ func (fa FakeAuthorizer) AuthClient() bool {
 	return isUser
 }
 
+// AuthEnvironAccess returns whether the authenticated entity is a
+// client user with at least the given level of access.
+func (fa FakeAuthorizer) AuthEnvironAccess(access state.EnvironmentAccess) bool {
+	if !fa.AuthClient() {
+		return false
+	}
+	if fa.Access == "" {
+		return true
+	}
+	return fa.Access.Includes(access)
+}
+
 func (fa FakeAuthorizer) GetAuthTag() names.Tag {
 	return fa.Tag
 }
@@ -76,8 +76,9 @@ func (h *toolsUploadHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 	defer stateWrapper.cleanup()
 
-	if err := stateWrapper.authenticate(r); err != nil {
-		h.authError(w, h)
+	// Uploaded tools may be used to upgrade the environment's
+	// agents, so only administrators may upload them.
+	if !stateWrapper.authenticateAccess(w, r, h, state.EnvironAdminAccess) {
 		return
 	}
 
@@ -80,6 +80,13 @@ func (s *toolsSuite) TestAuthRequiresUser(c *gc.C) {
 	s.assertErrorResponse(c, resp, http.StatusBadRequest, "expected binaryVersion argument")
 }
 
+func (s *toolsSuite) TestUploadRequiresAdminAccess(c *gc.C) {
+	s.setUserAccess(c, state.EnvironWriteAccess)
+	resp, err := s.authRequest(c, "POST", s.toolsURI(c, ""), "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertErrorResponse(c, resp, http.StatusForbidden, "permission denied")
+}
+
 func (s *toolsSuite) TestUploadRequiresVersion(c *gc.C) {
 	resp, err := s.authRequest(c, "POST", s.toolsURI(c, ""), "", nil)
 	c.Assert(err, jc.ErrorIsNil)
@@ -800,118 +800,29 @@ func (u *uniterBaseAPI) Relation(args params.RelationUnits) (params.RelationResu
 // Actions returns the Actions by Tags passed and ensures that the Unit asking
 // for them is the same Unit that has the Actions.
 func (u *uniterBaseAPI) Actions(args params.Entities) (params.ActionsQueryResults, error) {
-	nothing := params.ActionsQueryResults{}
-
 	actionFn, err := u.authAndActionFromTagFn()
 	if err != nil {
-		return nothing, err
+		return params.ActionsQueryResults{}, err
 	}
-
-	results := params.ActionsQueryResults{
-		Results: make([]params.ActionsQueryResult, len(args.Entities)),
-	}
-
-	for i, arg := range args.Entities {
-		action, err := actionFn(arg.Tag)
-		if err != nil {
-			results.Results[i].Error = common.ServerError(err)
-			continue
-		}
-		if action.Status() != state.ActionPending {
-			results.Results[i].Error = common.ServerError(common.ErrActionNotAvailable)
-			continue
-		}
-		results.Results[i].Action.Action = &params.Action{
-			Name:       action.Name(),
-			Parameters: action.Parameters(),
-		}
-	}
-
-	return results, nil
+	return common.Actions(args, actionFn), nil
 }
 
 // BeginActions marks the actions represented by the passed in Tags as running.
 func (u *uniterBaseAPI) BeginActions(args params.Entities) (params.ErrorResults, error) {
-	nothing := params.ErrorResults{}
-
 	actionFn, err := u.authAndActionFromTagFn()
 	if err != nil {
-		return nothing, err
-	}
-
-	results := params.ErrorResults{Results: make([]params.ErrorResult, len(args.Entities))}
-
-	for i, arg := range args.Entities {
-		action, err := actionFn(arg.Tag)
-		if err != nil {
-			results.Results[i].Error = common.ServerError(err)
-			continue
-		}
-
-		_, err = action.Begin()
-		if err != nil {
-			results.Results[i].Error = common.ServerError(err)
-			continue
-		}
+		return params.ErrorResults{}, err
 	}
-
-	return results, nil
+	return common.BeginActions(args, actionFn), nil
 }
 
 // FinishActions saves the result of a completed Action
 func (u *uniterBaseAPI) FinishActions(args params.ActionExecutionResults) (params.ErrorResults, error) {
-	nothing := params.ErrorResults{}
-
 	actionFn, err := u.authAndActionFromTagFn()
 	if err != nil {
-		return nothing, err
-	}
-
-	results := params.ErrorResults{Results: make([]params.ErrorResult, len(args.Results))}
-
-	for i, arg := range args.Results {
-		action, err := actionFn(arg.ActionTag)
-		if err != nil {
-			results.Results[i].Error = common.ServerError(err)
-			continue
-		}
-		actionResults, err := paramsActionExecutionResultsToStateActionResults(arg)
-		if err != nil {
-			results.Results[i].Error = common.ServerError(err)
-			continue
-		}
-
-		_, err = action.Finish(actionResults)
-		if err != nil {
-			results.Results[i].Error = common.ServerError(err)
-			continue
-		}
-	}
-
-	return results, nil
-}
-
-// paramsActionExecutionResultsToStateActionResults does exactly what
-// the name implies.
-func paramsActionExecutionResultsToStateActionResults(arg params.ActionExecutionResult) (state.ActionResults, error) {
-	var status state.ActionStatus
-	switch arg.Status {
-	case params.ActionCancelled:
-		status = state.ActionCancelled
-	case params.ActionCompleted:
-		status = state.ActionCompleted
-	case params.ActionFailed:
-		status = state.ActionFailed
-	case params.ActionPending:
-		status = state.ActionPending
-	default:
-		return state.ActionResults{}, errors.Errorf("unrecognized action status '%s'", arg.Status)
+		return params.ErrorResults{}, err
 	}
-	return state.ActionResults{
-		Status:  status,
-		Results: arg.Results,
-		Message: arg.Message,
-	}, nil
+	return common.FinishActions(args, actionFn), nil
 }
 
 // RelationById returns information about all given relations,
@@ -1531,28 +1442,9 @@ func (u *uniterBaseAPI) authAndActionFromTagFn() (func(string) (*state.Action, e
 		return nil, fmt.Errorf("calling entity is not a unit")
 	}
 
-	return func(tag string) (*state.Action, error) {
-		actionTag, err := names.ParseActionTag(tag)
-		if err != nil {
-			return nil, err
-		}
-		action, err := u.st.ActionByTag(actionTag)
-		if err != nil {
-			return nil, err
-		}
-		receiverTag, err := names.ActionReceiverTag(action.Receiver())
-		if err != nil {
-			return nil, err
-		}
-		if unit != receiverTag {
-			return nil, common.ErrPerm
-		}
-
-		if !canAccess(receiverTag) {
-			return nil, common.ErrPerm
-		}
-		return action, nil
-	}, nil
+	return common.AuthAndActionFromTagFn(func(tag names.Tag) bool {
+		return tag == unit && canAccess(tag)
+	}, u.st.ActionByTag), nil
 }
 
 func convertRelationSettings(settings map[string]interface{}) (params.Settings, error) {
@@ -1618,6 +1510,11 @@ func leadershipSettingsAccessorFactory(
 		}
 		rawSettings := make(map[string]interface{})
 		for k, v := range settings {
+			// An empty value deletes the key.
+			if v == "" {
+				currentSettings.Delete(k)
+				continue
+			}
 			rawSettings[k] = v
 		}
 		currentSettings.Update(rawSettings)
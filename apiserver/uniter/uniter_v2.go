@@ -7,7 +7,15 @@
 package uniter
 
 import (
+	"time"
+
+	"github.com/juju/names"
+	"gopkg.in/juju/charm.v4"
+
 	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/leadership"
+	"github.com/juju/juju/lease"
 	"github.com/juju/juju/state"
 )
 
@@ -36,3 +44,215 @@ func NewUniterAPIV2(st *state.State, resources *common.Resources, authorizer com
 		StorageAPI:  *storageAPI,
 	}, nil
 }
+
+// isLeader reports whether the given unit is the current leader of
+// the given service. It is a variable so that tests can replace it.
+var isLeader = func(serviceId, unitId string) bool {
+	return leadership.NewLeadershipManager(lease.Manager()).Leader(serviceId, unitId)
+}
+
+// SetWorkloadStatus sets the status of the software run by each
+// given unit, as reported by its charm.
+func (u *UniterAPIV2) SetWorkloadStatus(args params.SetStatus) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Entities)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseUnitTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		err = common.ErrPerm
+		if canAccess(tag) {
+			var unit *state.Unit
+			unit, err = u.getUnit(tag)
+			if err == nil {
+				err = unit.SetWorkloadStatus(state.Status(entity.Status), entity.Info, entity.Data)
+			}
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// WorkloadStatus returns the status of the software run by each
+// given unit, as last reported by its charm.
+func (u *UniterAPIV2) WorkloadStatus(args params.Entities) (params.StatusResults, error) {
+	result := params.StatusResults{
+		Results: make([]params.StatusResult, len(args.Entities)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.StatusResults{}, err
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseUnitTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		err = common.ErrPerm
+		if canAccess(tag) {
+			var unit *state.Unit
+			unit, err = u.getUnit(tag)
+			if err == nil {
+				var status state.Status
+				status, result.Results[i].Info, result.Results[i].Data, err = unit.WorkloadStatus()
+				result.Results[i].Id = tag.Id()
+				result.Results[i].Status = params.Status(status)
+			}
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// SetServiceStatus sets the status of each given service. Only the
+// leader unit of a service is allowed to set its status.
+func (u *UniterAPIV2) SetServiceStatus(args params.SetStatus) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Entities)),
+	}
+	canAccess, err := u.accessService()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseServiceTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		err = common.ErrPerm
+		if canAccess(tag) && isLeader(tag.Id(), u.unit.Name()) {
+			var service *state.Service
+			service, err = u.getService(tag)
+			if err == nil {
+				err = service.SetStatus(state.Status(entity.Status), entity.Info, entity.Data)
+			}
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// HookTimeout returns how long the charm hooks of each given unit may
+// run before they are killed: the override set on the unit's service,
+// if any, or the environment's hook-timeout setting.
+func (u *UniterAPIV2) HookTimeout(args params.Entities) (params.DurationResults, error) {
+	result := params.DurationResults{
+		Results: make([]params.DurationResult, len(args.Entities)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.DurationResults{}, err
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseUnitTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		err = common.ErrPerm
+		if canAccess(tag) {
+			var unit *state.Unit
+			unit, err = u.getUnit(tag)
+			if err == nil {
+				result.Results[i].Result, err = u.hookTimeout(unit)
+			}
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+func (u *UniterAPIV2) hookTimeout(unit *state.Unit) (time.Duration, error) {
+	service, err := unit.Service()
+	if err != nil {
+		return 0, err
+	}
+	if timeout := service.HookTimeout(); timeout > 0 {
+		return timeout, nil
+	}
+	config, err := u.st.EnvironConfig()
+	if err != nil {
+		return 0, err
+	}
+	return config.HookTimeout(), nil
+}
+
+// UpgradeCharmURL returns the URL of the charm each given unit should
+// be running, and whether the unit should upgrade to it even if it is
+// in an error state. While a rolling charm upgrade is in progress, this
+// is only the service's charm for units allowed to upgrade so far.
+func (u *UniterAPIV2) UpgradeCharmURL(args params.Entities) (params.StringBoolResults, error) {
+	result := params.StringBoolResults{
+		Results: make([]params.StringBoolResult, len(args.Entities)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.StringBoolResults{}, err
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseUnitTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		err = common.ErrPerm
+		if canAccess(tag) {
+			var unit *state.Unit
+			unit, err = u.getUnit(tag)
+			if err == nil {
+				var curl *charm.URL
+				curl, result.Results[i].Ok, err = unit.UpgradeCharmURL()
+				if curl != nil {
+					result.Results[i].Result = curl.String()
+				}
+			}
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// AddHookRecords adds each given record to the hook history of its unit.
+func (u *UniterAPIV2) AddHookRecords(args params.UnitHookRecords) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Records)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	for i, arg := range args.Records {
+		tag, err := names.ParseUnitTag(arg.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		err = common.ErrPerm
+		if canAccess(tag) {
+			var unit *state.Unit
+			unit, err = u.getUnit(tag)
+			if err == nil {
+				err = unit.AddHookRecord(state.HookRecord{
+					Kind:       arg.Record.Kind,
+					Hook:       arg.Record.Hook,
+					Relation:   arg.Record.Relation,
+					RemoteUnit: arg.Record.RemoteUnit,
+					Started:    arg.Record.Started,
+					Duration:   arg.Record.Duration,
+					Result:     arg.Record.Result,
+				})
+			}
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
@@ -4,10 +4,14 @@
 package uniter_test
 
 import (
+	"time"
+
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
 	"github.com/juju/juju/apiserver/uniter"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/storage"
@@ -58,3 +62,198 @@ func (s *uniterV2Suite) TestStorageInstances(c *gc.C) {
 		{Id: "data/0", Kind: storage.StorageKindBlock, Location: ""},
 	})
 }
+
+func (s *uniterV2Suite) TestSetWorkloadStatus(c *gc.C) {
+	args := params.SetStatus{
+		Entities: []params.EntityStatus{
+			{Tag: "unit-mysql-0", Status: params.StatusBlocked, Info: "not really"},
+			{Tag: "unit-wordpress-0", Status: params.StatusBlocked, Info: "need a database"},
+			{Tag: "unit-foo-42", Status: params.StatusRunning},
+			{Tag: "service-wordpress", Status: params.StatusRunning},
+		}}
+	result, err := s.uniter.SetWorkloadStatus(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{apiservertesting.ErrUnauthorized},
+			{nil},
+			{apiservertesting.ErrUnauthorized},
+			{apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	status, _, _, err := s.mysqlUnit.WorkloadStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status, gc.Equals, state.StatusUnknown)
+	status, info, _, err := s.wordpressUnit.WorkloadStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status, gc.Equals, state.StatusBlocked)
+	c.Assert(info, gc.Equals, "need a database")
+}
+
+func (s *uniterV2Suite) TestWorkloadStatus(c *gc.C) {
+	err := s.wordpressUnit.SetWorkloadStatus(state.StatusBusy, "installing", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: "unit-mysql-0"},
+		{Tag: "unit-wordpress-0"},
+		{Tag: "unit-foo-42"},
+	}}
+	result, err := s.uniter.WorkloadStatus(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.StatusResults{
+		Results: []params.StatusResult{
+			{Error: apiservertesting.ErrUnauthorized},
+			{Id: "wordpress/0", Status: params.StatusBusy, Info: "installing"},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+}
+
+func (s *uniterV2Suite) TestSetServiceStatus(c *gc.C) {
+	s.PatchValue(uniter.IsLeader, func(serviceId, unitId string) bool {
+		return serviceId == "wordpress" && unitId == "wordpress/0"
+	})
+	args := params.SetStatus{
+		Entities: []params.EntityStatus{
+			{Tag: "service-mysql", Status: params.StatusBlocked},
+			{Tag: "service-wordpress", Status: params.StatusWaiting, Info: "waiting for mysql"},
+			{Tag: "unit-wordpress-0", Status: params.StatusRunning},
+		}}
+	result, err := s.uniter.SetServiceStatus(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{apiservertesting.ErrUnauthorized},
+			{nil},
+			{apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	status, _, _, err := s.mysql.Status()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status, gc.Equals, state.StatusUnknown)
+	status, info, _, err := s.wordpress.Status()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status, gc.Equals, state.StatusWaiting)
+	c.Assert(info, gc.Equals, "waiting for mysql")
+}
+
+func (s *uniterV2Suite) TestSetServiceStatusNotLeader(c *gc.C) {
+	s.PatchValue(uniter.IsLeader, func(serviceId, unitId string) bool {
+		return false
+	})
+	args := params.SetStatus{
+		Entities: []params.EntityStatus{
+			{Tag: "service-wordpress", Status: params.StatusRunning},
+		}}
+	result, err := s.uniter.SetServiceStatus(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	status, _, _, err := s.wordpress.Status()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status, gc.Equals, state.StatusUnknown)
+}
+
+func (s *uniterV2Suite) TestHookTimeout(c *gc.C) {
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{"hook-timeout": 600}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: "unit-mysql-0"},
+		{Tag: "unit-wordpress-0"},
+		{Tag: "unit-foo-42"},
+	}}
+	result, err := s.uniter.HookTimeout(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.DurationResults{
+		Results: []params.DurationResult{
+			{Error: apiservertesting.ErrUnauthorized},
+			{Result: 10 * time.Minute},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	// A service setting overrides the environment's.
+	err = s.wordpress.SetHookTimeout(time.Minute)
+	c.Assert(err, jc.ErrorIsNil)
+	result, err = s.uniter.HookTimeout(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results[1], gc.DeepEquals, params.DurationResult{Result: time.Minute})
+}
+
+func (s *uniterV2Suite) TestUpgradeCharmURL(c *gc.C) {
+	err := s.wordpressUnit.SetCharmURL(s.wpCharm.URL())
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: "unit-mysql-0"},
+		{Tag: "unit-wordpress-0"},
+		{Tag: "unit-foo-42"},
+		{Tag: "service-wordpress"},
+	}}
+	result, err := s.uniter.UpgradeCharmURL(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.StringBoolResults{
+		Results: []params.StringBoolResult{
+			{Error: apiservertesting.ErrUnauthorized},
+			{Result: s.wpCharm.String()},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	// The first batch of a rolling upgrade gets the new charm.
+	newCharm := s.Factory.MakeCharm(c, &jujufactory.CharmParams{Name: "wordpress"})
+	err = s.wordpress.SetCharmRollout(newCharm, true, 1, false)
+	c.Assert(err, jc.ErrorIsNil)
+	result, err = s.uniter.UpgradeCharmURL(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results[1], gc.DeepEquals, params.StringBoolResult{
+		Result: newCharm.String(),
+		Ok:     true,
+	})
+}
+
+func (s *uniterV2Suite) TestAddHookRecords(c *gc.C) {
+	record := params.HookRecord{
+		Kind:     params.HookRecordHook,
+		Hook:     "config-changed",
+		Started:  time.Date(2015, 4, 1, 12, 0, 0, 0, time.UTC),
+		Duration: 3 * time.Second,
+		Result:   "ok",
+	}
+	args := params.UnitHookRecords{Records: []params.UnitHookRecord{
+		{Tag: "unit-mysql-0", Record: record},
+		{Tag: "unit-wordpress-0", Record: record},
+		{Tag: "unit-foo-42", Record: record},
+	}}
+	result, err := s.uniter.AddHookRecords(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{apiservertesting.ErrUnauthorized},
+			{nil},
+			{apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	records, err := s.wordpressUnit.HookHistory()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(records, gc.HasLen, 1)
+	c.Assert(records[0].Kind, gc.Equals, "hook")
+	c.Assert(records[0].Hook, gc.Equals, "config-changed")
+	c.Assert(records[0].Started.Equal(record.Started), jc.IsTrue)
+	c.Assert(records[0].Duration, gc.Equals, 3*time.Second)
+	c.Assert(records[0].Result, gc.Equals, "ok")
+
+	records, err = s.mysqlUnit.HookHistory()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(records, gc.HasLen, 0)
+}
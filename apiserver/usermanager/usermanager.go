@@ -167,16 +167,22 @@ func (api *UserManagerAPI) enableUserImpl(args params.Entities, action string, m
 // UserInfo returns information on a user.
 func (api *UserManagerAPI) UserInfo(request params.UserInfoRequest) (params.UserInfoResults, error) {
 	var infoForUser = func(user *state.User) params.UserInfoResult {
-		return params.UserInfoResult{
-			Result: &params.UserInfo{
-				Username:       user.Name(),
-				DisplayName:    user.DisplayName(),
-				CreatedBy:      user.CreatedBy(),
-				DateCreated:    user.DateCreated(),
-				LastConnection: user.LastLogin(),
-				Disabled:       user.IsDisabled(),
-			},
+		info := &params.UserInfo{
+			Username:       user.Name(),
+			DisplayName:    user.DisplayName(),
+			CreatedBy:      user.CreatedBy(),
+			DateCreated:    user.DateCreated(),
+			LastConnection: user.LastLogin(),
+			Disabled:       user.IsDisabled(),
 		}
+		envUser, err := api.state.EnvironmentUser(user.UserTag())
+		if err != nil && !errors.IsNotFound(err) {
+			return params.UserInfoResult{Error: common.ServerError(err)}
+		}
+		if err == nil {
+			info.Access = params.EnvironAccess(envUser.Access())
+		}
+		return params.UserInfoResult{Result: info}
 	}
 
 	var results params.UserInfoResults
@@ -14,6 +14,7 @@ import (
 	apiservertesting "github.com/juju/juju/apiserver/testing"
 	"github.com/juju/juju/apiserver/usermanager"
 	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
 	"github.com/juju/juju/testing/factory"
 )
 
@@ -312,6 +313,7 @@ func (s *userManagerSuite) TestUserInfo(c *gc.C) {
 					CreatedBy:      s.adminName,
 					DateCreated:    userFoo.DateCreated(),
 					LastConnection: userFoo.LastLogin(),
+					Access:         params.AdminEnvironAccess,
 				},
 			}, {
 				Result: &params.UserInfo{
@@ -320,6 +322,7 @@ func (s *userManagerSuite) TestUserInfo(c *gc.C) {
 					CreatedBy:      s.adminName,
 					DateCreated:    userBar.DateCreated(),
 					LastConnection: userBar.LastLogin(),
+					Access:         params.AdminEnvironAccess,
 					Disabled:       true,
 				},
 			}, {
@@ -360,6 +363,7 @@ func (s *userManagerSuite) TestUserInfoAll(c *gc.C) {
 					CreatedBy:      s.adminName,
 					DateCreated:    userBar.DateCreated(),
 					LastConnection: userBar.LastLogin(),
+					Access:         params.AdminEnvironAccess,
 					Disabled:       true,
 				},
 			}, {
@@ -369,6 +373,7 @@ func (s *userManagerSuite) TestUserInfoAll(c *gc.C) {
 					CreatedBy:      s.adminName,
 					DateCreated:    admin.DateCreated(),
 					LastConnection: admin.LastLogin(),
+					Access:         params.AdminEnvironAccess,
 				},
 			}, {
 				Result: &params.UserInfo{
@@ -377,6 +382,7 @@ func (s *userManagerSuite) TestUserInfoAll(c *gc.C) {
 					CreatedBy:      s.adminName,
 					DateCreated:    userFoo.DateCreated(),
 					LastConnection: userFoo.LastLogin(),
+					Access:         params.AdminEnvironAccess,
 				},
 			}},
 	}
@@ -389,6 +395,26 @@ func (s *userManagerSuite) TestUserInfoAll(c *gc.C) {
 	c.Assert(results, jc.DeepEquals, expected)
 }
 
+func (s *userManagerSuite) TestUserInfoAccess(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "foobar", NoEnvUser: true})
+	s.Factory.MakeEnvUser(c, &factory.EnvUserParams{
+		User:   user.UserTag().Username(),
+		Access: state.EnvironReadAccess,
+	})
+	other := s.Factory.MakeUser(c, &factory.UserParams{Name: "barfoo", NoEnvUser: true})
+
+	args := params.UserInfoRequest{
+		Entities: []params.Entity{
+			{Tag: user.Tag().String()},
+			{Tag: other.Tag().String()},
+		}}
+	results, err := s.usermanager.UserInfo(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+	c.Assert(results.Results[0].Result.Access, gc.Equals, params.ReadEnvironAccess)
+	c.Assert(results.Results[1].Result.Access, gc.Equals, params.EnvironAccess(""))
+}
+
 func (s *userManagerSuite) TestSetPassword(c *gc.C) {
 	alex := s.Factory.MakeUser(c, &factory.UserParams{Name: "alex"})
 
@@ -90,3 +90,22 @@ func validateEnvironUUID(args validateArgs) (*state.State, bool, error) {
 	}
 	return result, true, nil
 }
+
+// checkStateServerAdmin returns common.ErrPerm unless the given
+// tag is that of the user that owns the state server environment.
+// That user administers the state server itself, rather than just
+// one of the environments it hosts.
+func checkStateServerAdmin(st *state.State, tag names.Tag) error {
+	user, ok := tag.(names.UserTag)
+	if !ok {
+		return common.ErrPerm
+	}
+	env, err := st.StateServerEnvironment()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if env.Owner() != user {
+		return common.ErrPerm
+	}
+	return nil
+}
@@ -548,3 +548,33 @@ func (S) TestWindowsPathJoin(c *gc.C) {
 	c.Assert(output, gc.NotNil)
 	c.Assert(output, gc.Equals, compareOutput, gc.Commentf("test %q output differs", "windows writefile"))
 }
+
+func (S) TestCentOSRender(c *gc.C) {
+	cfg := cloudinit.New()
+	cfg.SetAptUpdate(true)
+	cfg.SetAptGetWrapper("eatmydata")
+	cfg.AddRunCmd("yum --assumeyes makecache")
+	render, err := cloudinit.NewRenderer("centos7")
+	c.Assert(err, jc.ErrorIsNil)
+	data, err := render.Render(cfg)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, "#cloud-config\nruncmd:\n- yum --assumeyes makecache\n")
+}
+
+func (S) TestCentOSMkdir(c *gc.C) {
+	render, err := cloudinit.NewRenderer("centos7")
+	c.Assert(err, jc.ErrorIsNil)
+	output := render.Mkdir("fake_dir")
+	c.Assert(output, gc.DeepEquals, []string{"mkdir -p 'fake_dir'"})
+}
+
+func (S) TestCentOSWriteFile(c *gc.C) {
+	filePath := path.Join("fake_dir", "test_file")
+	render, err := cloudinit.NewRenderer("centos7")
+	c.Assert(err, jc.ErrorIsNil)
+	output := render.WriteFile(filePath, "fake output", 0644)
+	c.Assert(output, gc.DeepEquals, []string{
+		"install -m 644 /dev/null 'fake_dir/test_file'",
+		`printf '%s\n' 'fake output' > 'fake_dir/test_file'`,
+	})
+}
@@ -40,6 +40,8 @@ func NewRenderer(series string) (Renderer, error) {
 		return &WindowsRenderer{}, nil
 	case version.Ubuntu:
 		return &UbuntuRenderer{}, nil
+	case version.CentOS:
+		return &CentOSRenderer{}, nil
 	default:
 		return nil, errors.Errorf("No renderer could be found for %s", series)
 	}
@@ -0,0 +1,116 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package cloudinit
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/apt"
+	"github.com/juju/utils/proxy"
+
+	"github.com/juju/juju/version"
+)
+
+// PackageManager provides the shell commands used to manage
+// packages with an operating system's native package manager.
+type PackageManager interface {
+	// Update returns a command that refreshes the list of
+	// available packages.
+	Update() string
+
+	// Upgrade returns a command that upgrades all installed packages.
+	Upgrade() string
+
+	// Install returns a command that installs the given packages.
+	Install(packages ...string) string
+
+	// SetProxy returns a command that configures the package manager
+	// to use the given proxy settings. Existing proxy configuration
+	// is left alone.
+	SetProxy(settings proxy.Settings) string
+}
+
+// NewPackageManager returns the PackageManager used by machines
+// running the given series.
+func NewPackageManager(series string) (PackageManager, error) {
+	operatingSystem, err := version.GetOSFromSeries(series)
+	if err != nil {
+		return nil, err
+	}
+
+	switch operatingSystem {
+	case version.Ubuntu:
+		return &AptPackageManager{}, nil
+	case version.CentOS:
+		return &YumPackageManager{}, nil
+	default:
+		return nil, errors.Errorf("No package manager could be found for %s", series)
+	}
+}
+
+// aptGetCommand is the apt-get invocation used for all package
+// operations; it must not prompt, and must keep existing config files.
+const aptGetCommand = "apt-get --option Dpkg::Options::=--force-confold --assume-yes "
+
+// AptPackageManager manages packages on Ubuntu with apt-get.
+// It implements the PackageManager interface.
+type AptPackageManager struct{}
+
+func (*AptPackageManager) Update() string {
+	return aptGetCommand + "update"
+}
+
+func (*AptPackageManager) Upgrade() string {
+	return aptGetCommand + "upgrade"
+}
+
+func (*AptPackageManager) Install(packages ...string) string {
+	return aptGetCommand + "install " + strings.Join(packages, " ")
+}
+
+func (*AptPackageManager) SetProxy(settings proxy.Settings) string {
+	return fmt.Sprintf(
+		`[ -f %s ] || (printf '%%s\n' %s > %s)`,
+		apt.ConfFile,
+		shquote(apt.ProxyContent(settings)),
+		apt.ConfFile)
+}
+
+// yumConfFile is the location of yum's main configuration file.
+const yumConfFile = "/etc/yum.conf"
+
+// YumPackageManager manages packages on CentOS with yum.
+// It implements the PackageManager interface.
+type YumPackageManager struct{}
+
+func (*YumPackageManager) Update() string {
+	return "yum --assumeyes makecache"
+}
+
+func (*YumPackageManager) Upgrade() string {
+	return "yum --assumeyes update"
+}
+
+func (*YumPackageManager) Install(packages ...string) string {
+	return "yum --assumeyes install " + strings.Join(packages, " ")
+}
+
+// SetProxy implements PackageManager. Yum uses a single proxy for all
+// repositories, so the http proxy is preferred over the https one.
+func (*YumPackageManager) SetProxy(settings proxy.Settings) string {
+	url := settings.Http
+	if url == "" {
+		url = settings.Https
+	}
+	if url == "" {
+		return ""
+	}
+	return fmt.Sprintf(
+		`grep -q '^proxy=' %s || (printf '%%s\n' %s >> %s)`,
+		yumConfFile,
+		shquote("proxy="+url),
+		yumConfFile)
+}
@@ -0,0 +1,65 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package cloudinit_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils/proxy"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cloudinit"
+)
+
+type packagingSuite struct{}
+
+var _ = gc.Suite(&packagingSuite{})
+
+func (*packagingSuite) TestNewPackageManager(c *gc.C) {
+	for series, expect := range map[string]cloudinit.PackageManager{
+		"precise": &cloudinit.AptPackageManager{},
+		"trusty":  &cloudinit.AptPackageManager{},
+		"centos7": &cloudinit.YumPackageManager{},
+	} {
+		pm, err := cloudinit.NewPackageManager(series)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(pm, gc.DeepEquals, expect)
+	}
+}
+
+func (*packagingSuite) TestNewPackageManagerUnsupported(c *gc.C) {
+	_, err := cloudinit.NewPackageManager("win8")
+	c.Assert(err, gc.ErrorMatches, "No package manager could be found for win8")
+	_, err = cloudinit.NewPackageManager("no-such-series")
+	c.Assert(err, gc.ErrorMatches, `invalid series "no-such-series"`)
+}
+
+func (*packagingSuite) TestAptCommands(c *gc.C) {
+	apt := &cloudinit.AptPackageManager{}
+	const aptGet = "apt-get --option Dpkg::Options::=--force-confold --assume-yes "
+	c.Assert(apt.Update(), gc.Equals, aptGet+"update")
+	c.Assert(apt.Upgrade(), gc.Equals, aptGet+"upgrade")
+	c.Assert(apt.Install("curl", "git"), gc.Equals, aptGet+"install curl git")
+	c.Assert(apt.SetProxy(proxy.Settings{Http: "http://proxy.invalid:3128"}), gc.Equals,
+		`[ -f /etc/apt/apt.conf.d/42-juju-proxy-settings ] || `+
+			`(printf '%s\n' 'Acquire::http::Proxy "http://proxy.invalid:3128";' > /etc/apt/apt.conf.d/42-juju-proxy-settings)`)
+}
+
+func (*packagingSuite) TestYumCommands(c *gc.C) {
+	yum := &cloudinit.YumPackageManager{}
+	c.Assert(yum.Update(), gc.Equals, "yum --assumeyes makecache")
+	c.Assert(yum.Upgrade(), gc.Equals, "yum --assumeyes update")
+	c.Assert(yum.Install("curl", "git"), gc.Equals, "yum --assumeyes install curl git")
+}
+
+func (*packagingSuite) TestYumSetProxy(c *gc.C) {
+	yum := &cloudinit.YumPackageManager{}
+	c.Assert(yum.SetProxy(proxy.Settings{}), gc.Equals, "")
+	c.Assert(yum.SetProxy(proxy.Settings{Https: "https://proxy.invalid:3128"}), gc.Equals,
+		`grep -q '^proxy=' /etc/yum.conf || (printf '%s\n' 'proxy=https://proxy.invalid:3128' >> /etc/yum.conf)`)
+	c.Assert(yum.SetProxy(proxy.Settings{
+		Http:  "http://proxy.invalid:3128",
+		Https: "https://proxy.invalid:3128",
+	}), gc.Equals,
+		`grep -q '^proxy=' /etc/yum.conf || (printf '%s\n' 'proxy=http://proxy.invalid:3128' >> /etc/yum.conf)`)
+}
@@ -43,6 +43,50 @@ func (w *UbuntuRenderer) Render(conf *Config) ([]byte, error) {
 	return append([]byte("#cloud-config\n"), data...), nil
 }
 
+// CentOSRenderer represents a CentOS specific script render
+// type that is responsible for this particular OS. It implements
+// the Renderer interface
+type CentOSRenderer struct{}
+
+func (w *CentOSRenderer) Mkdir(path string) []string {
+	return []string{fmt.Sprintf(`mkdir -p %s`, utils.ShQuote(path))}
+}
+
+func (w *CentOSRenderer) WriteFile(filename string, contents string, permission int) []string {
+	quotedFilename := utils.ShQuote(filename)
+	quotedContents := utils.ShQuote(contents)
+	return []string{
+		fmt.Sprintf("install -m %o /dev/null %s", permission, quotedFilename),
+		fmt.Sprintf(`printf '%%s\n' %s > %s`, quotedContents, quotedFilename),
+	}
+}
+
+func (w *CentOSRenderer) FromSlash(filepath string) string {
+	return filepath
+}
+
+func (w *CentOSRenderer) PathJoin(filepath ...string) string {
+	return path.Join(filepath...)
+}
+
+// Render implements Renderer. The apt options understood by
+// cloud-init on Ubuntu have no meaning on CentOS, so they are
+// left out; packages are managed with yum through runcmd instead.
+func (w *CentOSRenderer) Render(conf *Config) ([]byte, error) {
+	attrs := make(map[string]interface{})
+	for name, value := range conf.attrs {
+		if strings.HasPrefix(name, "apt_") {
+			continue
+		}
+		attrs[name] = value
+	}
+	data, err := yaml.Marshal(attrs)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte("#cloud-config\n"), data...), nil
+}
+
 // WindowsRenderer represents a Windows specific script render
 // type that is responsible for this particular OS. It implements
 // the Renderer interface
@@ -29,6 +29,7 @@ func NewSuperCommand() cmd.Command {
 		Purpose:     charmsCommandPurpose,
 	})
 	charmscmd.Register(envcmd.Wrap(&ListCommand{}))
+	charmscmd.Register(&TestHookCommand{})
 	return charmscmd
 }
 
@@ -24,6 +24,7 @@ var _ = gc.Suite(&CharmsCommandSuite{})
 var expectedCharmsCommmandNames = []string{
 	"help",
 	"list",
+	"test-hook",
 }
 
 func (s *CharmsCommandSuite) TestHelp(c *gc.C) {
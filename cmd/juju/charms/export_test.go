@@ -5,4 +5,5 @@ package charms
 
 var (
 	GetCharmsListAPI = &getCharmsListAPI
+	FindJujud        = &findJujud
 )
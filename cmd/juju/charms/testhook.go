@@ -0,0 +1,249 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package charms
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+	"github.com/juju/utils/symlink"
+	"gopkg.in/juju/charm.v4"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/juju/names"
+	"github.com/juju/juju/version"
+	"github.com/juju/juju/worker/uniter/runner"
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+)
+
+const TestHookCommandDoc = `
+Run a hook of a local charm without deploying it, against a unit and
+environment described by a YAML fixture file, and report every hook
+tool the hook ran and the changes it made.
+
+The fixture describes the unit the hook runs as; all fields are optional:
+
+    unit: wordpress/0
+    owner: admin
+    public-address: wordpress-0.example.com
+    private-address: 10.0.0.1
+    availability-zone: zone-1
+    config:
+        blog-title: My Title
+    leader: true
+    leader-settings:
+        password: sekrit
+    opened-ports: [80/tcp]
+    relations:
+        - id: 0
+          name: db
+          settings:
+              private-address: 10.0.0.1
+          units:
+              mysql/0:
+                  host: 10.0.0.2
+          previous-settings:
+              mysql/0:
+                  host: 10.0.0.3
+
+Service config not given in the fixture takes the charm's defaults. For
+relation hooks, the relation and remote unit are inferred from the hook
+name and fixture when they are not specified and there is only one
+candidate.
+
+Hook tools are provided by the jujud binary found alongside juju or in
+the PATH. Nothing is changed in any environment; the hook's output is
+logged, so use --show-log to see it.
+
+examples:
+    juju charms test-hook --fixture unit.yaml install
+    juju charms test-hook --fixture unit.yaml -r db:0 --remote-unit mysql/0 db-relation-changed
+`
+
+// TestHookCommand runs a hook of a local charm against an offline
+// context, and reports what the hook did.
+type TestHookCommand struct {
+	cmd.CommandBase
+	out         cmd.Output
+	HookName    string
+	CharmDir    string
+	FixturePath string
+	RelationId  int
+	RemoteUnit  string
+	relation    string
+}
+
+// Info implements Command.Info.
+func (c *TestHookCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "test-hook",
+		Args:    "<hook name>",
+		Purpose: "run a hook of a local charm against a fixture",
+		Doc:     TestHookCommandDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *TestHookCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml": cmd.FormatYaml,
+		"json": cmd.FormatJson,
+	})
+	f.StringVar(&c.CharmDir, "charm-dir", ".", "the directory of the charm to run the hook of")
+	f.StringVar(&c.FixturePath, "fixture", "", "the YAML file describing the unit running the hook")
+	f.StringVar(&c.relation, "r", "", "the id of the relation for a relation hook")
+	f.StringVar(&c.relation, "relation", "", "")
+	f.StringVar(&c.RemoteUnit, "remote-unit", "", "the name of the remote unit for a relation hook")
+}
+
+// Init implements Command.Init.
+func (c *TestHookCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no hook name specified")
+	}
+	c.HookName = args[0]
+	if c.FixturePath == "" {
+		return errors.New("no fixture specified")
+	}
+	c.RelationId = -1
+	if c.relation != "" {
+		trim := c.relation
+		if idx := strings.LastIndex(trim, ":"); idx != -1 {
+			trim = trim[idx+1:]
+		}
+		id, err := strconv.Atoi(trim)
+		if err != nil || id < 0 {
+			return errors.Errorf("invalid relation id %q", c.relation)
+		}
+		c.RelationId = id
+	}
+	return cmd.CheckEmpty(args[1:])
+}
+
+// findJujud returns the path to the jujud binary that provides the hook
+// tools, preferring the one installed alongside juju.
+var findJujud = func() (string, error) {
+	if jujuPath, err := exec.LookPath(os.Args[0]); err == nil {
+		jujudPath := filepath.Join(filepath.Dir(jujuPath), names.Jujud)
+		if _, err := os.Stat(jujudPath); err == nil {
+			return jujudPath, nil
+		}
+	}
+	jujudPath, err := exec.LookPath(names.Jujud)
+	if err != nil {
+		return "", errors.Annotate(err, "cannot find jujud")
+	}
+	return jujudPath, nil
+}
+
+// Run implements Command.Run.
+func (c *TestHookCommand) Run(ctx *cmd.Context) error {
+	charmDir := ctx.AbsPath(c.CharmDir)
+	ch, err := charm.ReadCharmDir(charmDir)
+	if err != nil {
+		return errors.Annotatef(err, "cannot read charm from %q", charmDir)
+	}
+	var fixture runner.OfflineFixture
+	if err := utils.ReadYaml(ctx.AbsPath(c.FixturePath), &fixture); err != nil {
+		return errors.Annotate(err, "cannot read fixture")
+	}
+	if fixture.Unit == "" {
+		fixture.Unit = ch.Meta().Name + "/0"
+	}
+	config, err := ch.Config().ValidateSettings(fixture.Config)
+	if err != nil {
+		return errors.Annotate(err, "invalid fixture config")
+	}
+	settings := ch.Config().DefaultSettings()
+	for name, value := range config {
+		if value != nil {
+			settings[name] = value
+		}
+	}
+	hctx, err := runner.NewOfflineContext(fixture, settings, c.HookName, c.RelationId, c.RemoteUnit)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	tempDir, err := ioutil.TempDir("", "juju-test-hook")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer os.RemoveAll(tempDir)
+	paths, err := newTestHookPaths(tempDir, charmDir)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	hookErr := runner.NewRunner(hctx, paths).RunHook(c.HookName)
+	if runner.IsMissingHookError(hookErr) {
+		return errors.Errorf("charm %q has no %q hook", ch.Meta().Name, c.HookName)
+	}
+	if err := c.out.Write(ctx, hctx.Report()); err != nil {
+		return errors.Trace(err)
+	}
+	if hookErr != nil {
+		return errors.Annotatef(hookErr, "%q hook failed", c.HookName)
+	}
+	return nil
+}
+
+// testHookPaths implements runner.Paths for hooks run by test-hook.
+type testHookPaths struct {
+	toolsDir string
+	charmDir string
+	socket   string
+}
+
+// newTestHookPaths creates, within tempDir, the hook tools for running
+// the hooks of the charm in charmDir, and returns the paths the hooks
+// should run with.
+func newTestHookPaths(tempDir, charmDir string) (*testHookPaths, error) {
+	jujudPath, err := findJujud()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	toolsDir := filepath.Join(tempDir, "tools")
+	if err := os.Mkdir(toolsDir, 0755); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := symlink.New(jujudPath, filepath.Join(toolsDir, names.Jujud)); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := jujuc.EnsureSymlinks(toolsDir); err != nil {
+		return nil, errors.Trace(err)
+	}
+	socket := filepath.Join(tempDir, "agent.socket")
+	if version.Current.OS == version.Windows {
+		socket = fmt.Sprintf(`\\.\pipe\juju-test-hook-%d`, os.Getpid())
+	}
+	return &testHookPaths{
+		toolsDir: toolsDir,
+		charmDir: charmDir,
+		socket:   socket,
+	}, nil
+}
+
+// GetToolsDir is part of the runner.Paths interface.
+func (paths *testHookPaths) GetToolsDir() string {
+	return paths.toolsDir
+}
+
+// GetCharmDir is part of the runner.Paths interface.
+func (paths *testHookPaths) GetCharmDir() string {
+	return paths.charmDir
+}
+
+// GetJujucSocket is part of the runner.Paths interface.
+func (paths *testHookPaths) GetJujucSocket() string {
+	return paths.socket
+}
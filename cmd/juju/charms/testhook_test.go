@@ -0,0 +1,130 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// +build !windows
+
+package charms_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/juju/charms"
+	"github.com/juju/juju/testing"
+)
+
+// This suite provides basic tests for the "charms test-hook" command.
+type TestHookCommandSuite struct {
+	testing.BaseSuite
+	charmDir string
+	fixture  string
+}
+
+var _ = gc.Suite(&TestHookCommandSuite{})
+
+func (s *TestHookCommandSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	jujudPath := filepath.Join(c.MkDir(), "jujud")
+	err := ioutil.WriteFile(jujudPath, nil, 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	s.PatchValue(charms.FindJujud, func() (string, error) {
+		return jujudPath, nil
+	})
+
+	s.charmDir = c.MkDir()
+	s.writeFile(c, "metadata.yaml", `
+name: wordpress
+summary: "blog engine"
+description: "A pretty popular blog engine"
+requires:
+  db:
+    interface: mysql
+`, 0644)
+	s.writeFile(c, "config.yaml", `
+options:
+  blog-title: {default: My Title, description: A descriptive title, type: string}
+`, 0644)
+	err = os.Mkdir(filepath.Join(s.charmDir, "hooks"), 0755)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.fixture = filepath.Join(c.MkDir(), "fixture.yaml")
+	err = ioutil.WriteFile(s.fixture, []byte(`
+unit: wordpress/1
+relations:
+  - id: 3
+    name: db
+    units:
+      mysql/0: {host: 10.0.0.2}
+`), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *TestHookCommandSuite) writeFile(c *gc.C, name, content string, mode os.FileMode) {
+	err := ioutil.WriteFile(filepath.Join(s.charmDir, name), []byte(content), mode)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *TestHookCommandSuite) run(c *gc.C, args ...string) (string, error) {
+	args = append([]string{"--charm-dir", s.charmDir, "--fixture", s.fixture}, args...)
+	context, err := testing.RunCommand(c, &charms.TestHookCommand{}, args...)
+	if context == nil {
+		return "", err
+	}
+	return testing.Stdout(context), err
+}
+
+func (s *TestHookCommandSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		args: []string{},
+		err:  "no hook name specified",
+	}, {
+		args: []string{"install"},
+		err:  "no fixture specified",
+	}, {
+		args: []string{"--fixture", "f.yaml", "-r", "db:x", "db-relation-joined"},
+		err:  `invalid relation id "db:x"`,
+	}, {
+		args: []string{"--fixture", "f.yaml", "install", "start"},
+		err:  `unrecognized args: \["start"\]`,
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		_, err := testing.RunCommand(c, &charms.TestHookCommand{}, test.args...)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *TestHookCommandSuite) TestRunHook(c *gc.C) {
+	s.writeFile(c, "hooks/db-relation-changed", `#!/bin/sh
+echo "$JUJU_UNIT_NAME $JUJU_RELATION_ID $JUJU_REMOTE_UNIT" > env
+`, 0755)
+	out, err := s.run(c, "db-relation-changed")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, gc.Equals, "hook: db-relation-changed\n")
+	env, err := ioutil.ReadFile(filepath.Join(s.charmDir, "env"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(env), gc.Equals, "wordpress/1 db:3 mysql/0\n")
+}
+
+func (s *TestHookCommandSuite) TestRunHookFailure(c *gc.C) {
+	s.writeFile(c, "hooks/install", "#!/bin/sh\nexit 1\n", 0755)
+	out, err := s.run(c, "--format", "json", "install")
+	c.Assert(err, gc.ErrorMatches, `"install" hook failed: exit status 1`)
+	c.Assert(out, gc.Equals, `{"hook":"install"}`+"\n")
+}
+
+func (s *TestHookCommandSuite) TestRunMissingHook(c *gc.C) {
+	_, err := s.run(c, "install")
+	c.Assert(err, gc.ErrorMatches, `charm "wordpress" has no "install" hook`)
+}
+
+func (s *TestHookCommandSuite) TestRunHookBadFixture(c *gc.C) {
+	_, err := s.run(c, "website-relation-joined")
+	c.Assert(err, gc.ErrorMatches, `cannot infer relation for "website-relation-joined": no "website" relation`)
+}
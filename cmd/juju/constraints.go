@@ -31,6 +31,10 @@ default constraints for all new machines provisioned in the environment (unless
 overridden).  You can also set constraints on a specific service by using juju
 set-constraints <service>.
 
+Constraints can also be set on a specific machine. Constraints of machines
+that have already been provisioned can only be changed for containers, whose
+memory and cpu limits are then updated to enforce the new constraints.
+
 Constraints set on a service are combined with environment constraints for
 commands (such as juju deploy) that provision machines for services.  Where
 environment and service constraints overlap, the service constraints take
@@ -40,6 +44,7 @@ Examples:
 
    set-constraints mem=8G                         (all new machines in the environment must have at least 8GB of RAM)
    set-constraints --service wordpress mem=4G     (all new wordpress machines can ignore the 8G constraint above, and require only 4G)
+   set-constraints --machine 1/lxc/0 mem=2G       (limit container 1/lxc/0 to 2GB of RAM)
 
 See Also:
    juju help constraints
@@ -106,10 +111,11 @@ func (c *GetConstraintsCommand) Run(ctx *cmd.Context) error {
 	return c.out.Write(ctx, cons)
 }
 
-// SetConstraintsCommand shows the constraints for a service or environment.
+// SetConstraintsCommand sets the constraints for a service, machine or environment.
 type SetConstraintsCommand struct {
 	envcmd.EnvCommandBase
 	ServiceName string
+	MachineId   string
 	Constraints constraints.Value
 }
 
@@ -117,7 +123,7 @@ func (c *SetConstraintsCommand) Info() *cmd.Info {
 	return &cmd.Info{
 		Name:    "set-constraints",
 		Args:    "[key=[value] ...]",
-		Purpose: "set constraints on the environment or a service",
+		Purpose: "set constraints on the environment, a service or a machine",
 		Doc:     setConstraintsDoc,
 	}
 }
@@ -125,12 +131,19 @@ func (c *SetConstraintsCommand) Info() *cmd.Info {
 func (c *SetConstraintsCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.StringVar(&c.ServiceName, "s", "", "set service constraints")
 	f.StringVar(&c.ServiceName, "service", "", "")
+	f.StringVar(&c.MachineId, "machine", "", "set machine constraints")
 }
 
 func (c *SetConstraintsCommand) Init(args []string) (err error) {
+	if c.ServiceName != "" && c.MachineId != "" {
+		return fmt.Errorf("cannot set both service and machine constraints")
+	}
 	if c.ServiceName != "" && !names.IsValidService(c.ServiceName) {
 		return fmt.Errorf("invalid service name %q", c.ServiceName)
 	}
+	if c.MachineId != "" && !names.IsValidMachine(c.MachineId) {
+		return fmt.Errorf("invalid machine id %q", c.MachineId)
+	}
 	c.Constraints, err = constraints.Parse(args...)
 	return err
 }
@@ -142,10 +155,13 @@ func (c *SetConstraintsCommand) Run(_ *cmd.Context) (err error) {
 	}
 	defer apiclient.Close()
 
-	if c.ServiceName == "" {
-		err = apiclient.SetEnvironmentConstraints(c.Constraints)
-	} else {
+	switch {
+	case c.ServiceName != "":
 		err = apiclient.SetServiceConstraints(c.ServiceName, c.Constraints)
+	case c.MachineId != "":
+		err = apiclient.SetMachineConstraints(c.MachineId, c.Constraints)
+	default:
+		err = apiclient.SetEnvironmentConstraints(c.Constraints)
 	}
 	return block.ProcessBlockedError(err, block.BlockChange)
 }
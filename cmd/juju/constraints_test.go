@@ -14,6 +14,7 @@ import (
 	"github.com/juju/juju/cmd/envcmd"
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
 	coretesting "github.com/juju/juju/testing"
 )
 
@@ -104,6 +105,26 @@ func (s *ConstraintsCommandsSuite) TestBlockSetService(c *gc.C) {
 	assertSetBlocked(c, "-s", "svc", "mem=4G", "cpu-power=250")
 }
 
+func (s *ConstraintsCommandsSuite) TestSetMachine(c *gc.C) {
+	m, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Set constraints.
+	assertSet(c, "--machine", m.Id(), "mem=4G", "cpu-cores=2")
+	cons, err := m.Constraints()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cons, gc.DeepEquals, constraints.Value{
+		CpuCores: uint64p(2),
+		Mem:      uint64p(4096),
+	})
+
+	// Clear constraints.
+	assertSet(c, "--machine", m.Id())
+	cons, err = m.Constraints()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(&cons, jc.Satisfies, constraints.IsEmpty)
+}
+
 func assertSetError(c *gc.C, code int, stderr string, args ...string) {
 	rcode, rstdout, rstderr := runCmdLine(c, envcmd.Wrap(&SetConstraintsCommand{}), args...)
 	c.Assert(rcode, gc.Equals, code)
@@ -116,6 +137,9 @@ func (s *ConstraintsCommandsSuite) TestSetErrors(c *gc.C) {
 	assertSetError(c, 2, `malformed constraint "="`, "=")
 	assertSetError(c, 2, `malformed constraint "="`, "-s", "s", "=")
 	assertSetError(c, 1, `service "missing" not found`, "-s", "missing")
+	assertSetError(c, 2, `invalid machine id "bad/0"`, "--machine", "bad/0")
+	assertSetError(c, 2, `cannot set both service and machine constraints`, "-s", "s", "--machine", "0")
+	assertSetError(c, 1, `machine 42 not found`, "--machine", "42")
 }
 
 func assertGet(c *gc.C, stdout string, args ...string) {
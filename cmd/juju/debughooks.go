@@ -22,13 +22,23 @@ type DebugHooksCommand struct {
 }
 
 const debugHooksDoc = `
-Interactively debug a hook remotely on a service unit.
+Interactively debug a hook or action remotely on a service unit.
+
+Each named hook or action that runs on the unit opens a new window in
+the tmux session, with the environment the hook or action would have
+run in. Within that window, "run-hook" runs the charm's own hook or
+action, so that a failure can be reproduced and inspected without
+waiting for the hook to fire again.
+
+If a named hook or action fails while no session is attached, and a
+session is attached before it finishes, it is run again in the session
+with the same environment, relation and remote unit.
 `
 
 func (c *DebugHooksCommand) Info() *cmd.Info {
 	return &cmd.Info{
 		Name:    "debug-hooks",
-		Args:    "<unit name> [hook names]",
+		Args:    "<unit name> [hook or action names]",
 		Purpose: "launch a tmux session to debug a hook",
 		Doc:     debugHooksDoc,
 	}
@@ -66,6 +76,10 @@ func (c *DebugHooksCommand) validateHooks() error {
 	if err != nil {
 		return err
 	}
+	actions, err := c.serviceActions(service)
+	if err != nil {
+		return err
+	}
 
 	validHooks := make(map[string]bool)
 	for _, hook := range hooks.UnitHooks() {
@@ -77,6 +91,9 @@ func (c *DebugHooksCommand) validateHooks() error {
 			validHooks[hook] = true
 		}
 	}
+	for _, action := range actions {
+		validHooks[action] = true
+	}
 	for _, hook := range c.hooks {
 		if !validHooks[hook] {
 			names := make([]string, 0, len(validHooks))
@@ -85,12 +102,33 @@ func (c *DebugHooksCommand) validateHooks() error {
 			}
 			sort.Strings(names)
 			logger.Infof("unknown hook %s, valid hook names: %v", hook, names)
-			return fmt.Errorf("unit %q does not contain hook %q", c.Target, hook)
+			return fmt.Errorf("unit %q does not contain hook or action %q", c.Target, hook)
 		}
 	}
 	return nil
 }
 
+// serviceActions returns the names of the actions defined by the
+// service's charm.
+func (c *DebugHooksCommand) serviceActions(service string) ([]string, error) {
+	curl, err := c.apiClient.ServiceGetCharmURL(service)
+	if err != nil {
+		return nil, err
+	}
+	info, err := c.apiClient.CharmInfo(curl.String())
+	if err != nil {
+		return nil, err
+	}
+	if info.Actions == nil {
+		return nil, nil
+	}
+	var actions []string
+	for name := range info.Actions.ActionSpecs {
+		actions = append(actions, name)
+	}
+	return actions, nil
+}
+
 // Run ensures c.Target is a unit, and resolves its address,
 // and connects to it via SSH to execute the debug-hooks
 // script.
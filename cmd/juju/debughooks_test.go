@@ -65,7 +65,11 @@ var debugHooksTests = []struct {
 }, {
 	info:  `invalid hook`,
 	args:  []string{"mysql/0", "invalid-hook"},
-	error: `unit "mysql/0" does not contain hook "invalid-hook"`,
+	error: `unit "mysql/0" does not contain hook or action "invalid-hook"`,
+}, {
+	info:   `actions may be debugged by name`,
+	args:   []string{"mysql/0", "snapshot", "start"},
+	result: ".*\n",
 }}
 
 func (s *DebugHooksSuite) TestDebugHooksCommand(c *gc.C) {
@@ -26,6 +26,8 @@ var expectedCommmandNames = []string{
 	"help",
 	"jenv",
 	"set",
+	"set-access",
+	"share",
 	"unset",
 }
 
@@ -24,6 +24,20 @@ func NewUnsetCommand(api UnsetEnvironmentAPI) *UnsetCommand {
 	}
 }
 
+// NewShareCommand returns a ShareCommand with the api provided as specified.
+func NewShareCommand(api ShareEnvironmentAPI) *ShareCommand {
+	return &ShareCommand{
+		api: api,
+	}
+}
+
+// NewSetAccessCommand returns a SetAccessCommand with the api provided as specified.
+func NewSetAccessCommand(api SetAccessAPI) *SetAccessCommand {
+	return &SetAccessCommand{
+		api: api,
+	}
+}
+
 // NewEnsureAvailabilityCommand returns an EnsureAvailabilityCommand with the
 // haClient provided as specified.
 func NewEnsureAvailabilityCommand(haClient EnsureAvailabilityClient) *EnsureAvailabilityCommand {
@@ -0,0 +1,100 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package environment
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+const setAccessHelpDoc = `
+Change the level of access that users the current environment is already
+shared with have to it. The access level is one of:
+
+    read    the users may see the environment's status and its
+            configuration, apart from the provider's credentials and
+            other secrets, but not change anything
+    write   the users may also deploy, change and remove services,
+            units, machines and relations, and run commands in units
+    admin   the users may also see the provider's secrets, change
+            the environment's configuration, run commands on machines
+            and in units on state servers, share the environment with
+            others, and destroy it
+
+Examples:
+  # Stop "bob" from changing anything in the environment.
+  juju environment set-access read bob
+
+  # Let "alice" of the identity service "sso" administer the environment.
+  juju environment set-access admin alice@sso
+`
+
+// SetAccessCommand changes the access users have to an environment.
+type SetAccessCommand struct {
+	envcmd.EnvCommandBase
+	api    SetAccessAPI
+	Access string
+	Users  []names.UserTag
+}
+
+func (c *SetAccessCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "set-access",
+		Args:    "<read|write|admin> <user> ...",
+		Purpose: "change the access users have to the current environment",
+		Doc:     strings.TrimSpace(setAccessHelpDoc),
+	}
+}
+
+func (c *SetAccessCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("no access level specified")
+	}
+	c.Access, args = args[0], args[1:]
+	switch params.EnvironAccess(c.Access) {
+	case params.ReadEnvironAccess, params.WriteEnvironAccess, params.AdminEnvironAccess:
+	default:
+		return fmt.Errorf("invalid access %q: expected read, write or admin", c.Access)
+	}
+	if len(args) == 0 {
+		return fmt.Errorf("no users specified")
+	}
+	for _, arg := range args {
+		if !names.IsValidUser(arg) {
+			return fmt.Errorf("invalid user name %q", arg)
+		}
+		c.Users = append(c.Users, names.NewUserTag(arg))
+	}
+	return nil
+}
+
+// SetAccessAPI defines the client API methods
+// that the set-access command uses.
+type SetAccessAPI interface {
+	Close() error
+	SetEnvironmentAccess(users []names.UserTag, access params.EnvironAccess) error
+}
+
+func (c *SetAccessCommand) getAPI() (SetAccessAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	return c.NewAPIClient()
+}
+
+func (c *SetAccessCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	return client.SetEnvironmentAccess(c.Users, params.EnvironAccess(c.Access))
+}
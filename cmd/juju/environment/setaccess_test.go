@@ -0,0 +1,99 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package environment_test
+
+import (
+	"errors"
+
+	"github.com/juju/cmd"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/environment"
+	"github.com/juju/juju/testing"
+)
+
+type SetAccessSuite struct {
+	testing.FakeJujuHomeSuite
+	fake *fakeSetAccessAPI
+}
+
+var _ = gc.Suite(&SetAccessSuite{})
+
+func (s *SetAccessSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.fake = &fakeSetAccessAPI{}
+}
+
+func (s *SetAccessSuite) run(c *gc.C, args ...string) (*cmd.Context, error) {
+	command := environment.NewSetAccessCommand(s.fake)
+	return testing.RunCommand(c, envcmd.Wrap(command), args...)
+}
+
+func (s *SetAccessSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args   []string
+		users  []names.UserTag
+		access string
+		err    string
+	}{{
+		err: "no access level specified",
+	}, {
+		args: []string{"read"},
+		err:  "no users specified",
+	}, {
+		args:   []string{"read", "bob", "alice@sso"},
+		users:  []names.UserTag{names.NewUserTag("bob"), names.NewUserTag("alice@sso")},
+		access: "read",
+	}, {
+		args: []string{"superuser", "bob"},
+		err:  `invalid access "superuser": expected read, write or admin`,
+	}, {
+		args: []string{"admin", "not/valid"},
+		err:  `invalid user name "not/valid"`,
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		setAccessCmd := &environment.SetAccessCommand{}
+		err := testing.InitCommand(setAccessCmd, test.args)
+		if test.err != "" {
+			c.Check(err, gc.ErrorMatches, test.err)
+			continue
+		}
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(setAccessCmd.Users, jc.DeepEquals, test.users)
+		c.Check(setAccessCmd.Access, gc.Equals, test.access)
+	}
+}
+
+func (s *SetAccessSuite) TestPassesValues(c *gc.C) {
+	_, err := s.run(c, "admin", "bob")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.users, jc.DeepEquals, []names.UserTag{names.NewUserTag("bob")})
+	c.Assert(s.fake.access, gc.Equals, params.AdminEnvironAccess)
+}
+
+func (s *SetAccessSuite) TestError(c *gc.C) {
+	s.fake.err = errors.New("permission denied")
+	_, err := s.run(c, "read", "bob")
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+type fakeSetAccessAPI struct {
+	users  []names.UserTag
+	access params.EnvironAccess
+	err    error
+}
+
+func (f *fakeSetAccessAPI) Close() error {
+	return nil
+}
+
+func (f *fakeSetAccessAPI) SetEnvironmentAccess(users []names.UserTag, access params.EnvironAccess) error {
+	f.users = users
+	f.access = access
+	return f.err
+}
@@ -0,0 +1,129 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package environment
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+const shareEnvHelpDoc = `
+Share the current environment with one or more users.
+
+The --access option sets the level of access the users are given:
+
+    read    the users may see the environment's status and its
+            configuration, apart from the provider's credentials and
+            other secrets, but not change anything
+    write   the users may also deploy, change and remove services,
+            units, machines and relations, and run commands in
+            units (the default)
+    admin   the users may also see the provider's secrets, change
+            the environment's configuration, run commands on machines
+            and in units on state servers, share the environment with
+            others, and destroy it
+
+Users may be local users, as created by "juju user add", or users of
+the environment's external identity service, such as "bob@sso".
+
+With the --group option, the environment is instead shared with groups
+defined by the identity service. Members of the groups are given the
+access when they log in with "juju user login", unless they have been
+given access of their own.
+
+Examples:
+  # Give the local user "bob" write access to the environment.
+  juju environment share bob
+
+  # Let "alice" of the identity service "sso" see the environment's status.
+  juju environment share --access=read alice@sso
+
+  # Give the members of the identity service's "devops" group
+  # admin access to the environment.
+  juju environment share --group --access=admin devops
+`
+
+// ShareCommand shares an environment with other users.
+type ShareCommand struct {
+	envcmd.EnvCommandBase
+	api    ShareEnvironmentAPI
+	Users  []names.UserTag
+	Groups []string
+	Access string
+	Group  bool
+}
+
+func (c *ShareCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "share",
+		Args:    "<user> ... | --group <group> ...",
+		Purpose: "share the current environment with other users",
+		Doc:     strings.TrimSpace(shareEnvHelpDoc),
+	}
+}
+
+func (c *ShareCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.Access, "access", string(params.WriteEnvironAccess), "level of access to give: read, write or admin")
+	f.BoolVar(&c.Group, "group", false, "share with groups of the identity service rather than users")
+}
+
+func (c *ShareCommand) Init(args []string) error {
+	switch params.EnvironAccess(c.Access) {
+	case params.ReadEnvironAccess, params.WriteEnvironAccess, params.AdminEnvironAccess:
+	default:
+		return fmt.Errorf("invalid access %q: expected read, write or admin", c.Access)
+	}
+	if c.Group {
+		if len(args) == 0 {
+			return fmt.Errorf("no groups specified")
+		}
+		c.Groups = args
+		return nil
+	}
+	if len(args) == 0 {
+		return fmt.Errorf("no users specified")
+	}
+	for _, arg := range args {
+		if !names.IsValidUser(arg) {
+			return fmt.Errorf("invalid user name %q", arg)
+		}
+		c.Users = append(c.Users, names.NewUserTag(arg))
+	}
+	return nil
+}
+
+// ShareEnvironmentAPI defines the client API methods
+// that the share command uses.
+type ShareEnvironmentAPI interface {
+	Close() error
+	ShareEnvironment(users []names.UserTag, access params.EnvironAccess) error
+	ShareEnvironmentGroups(groups []string, access params.EnvironAccess) error
+}
+
+func (c *ShareCommand) getAPI() (ShareEnvironmentAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	return c.NewAPIClient()
+}
+
+func (c *ShareCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if c.Group {
+		return client.ShareEnvironmentGroups(c.Groups, params.EnvironAccess(c.Access))
+	}
+	return client.ShareEnvironment(c.Users, params.EnvironAccess(c.Access))
+}
@@ -0,0 +1,127 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package environment_test
+
+import (
+	"errors"
+
+	"github.com/juju/cmd"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/environment"
+	"github.com/juju/juju/testing"
+)
+
+type ShareSuite struct {
+	testing.FakeJujuHomeSuite
+	fake *fakeShareAPI
+}
+
+var _ = gc.Suite(&ShareSuite{})
+
+func (s *ShareSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.fake = &fakeShareAPI{}
+}
+
+func (s *ShareSuite) run(c *gc.C, args ...string) (*cmd.Context, error) {
+	command := environment.NewShareCommand(s.fake)
+	return testing.RunCommand(c, envcmd.Wrap(command), args...)
+}
+
+func (s *ShareSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args   []string
+		users  []names.UserTag
+		groups []string
+		access string
+		err    string
+	}{{
+		err: "no users specified",
+	}, {
+		args:   []string{"bob"},
+		users:  []names.UserTag{names.NewUserTag("bob")},
+		access: "write",
+	}, {
+		args:   []string{"--access", "read", "bob", "alice@sso"},
+		users:  []names.UserTag{names.NewUserTag("bob"), names.NewUserTag("alice@sso")},
+		access: "read",
+	}, {
+		args: []string{"--access", "superuser", "bob"},
+		err:  `invalid access "superuser": expected read, write or admin`,
+	}, {
+		args: []string{"not/valid"},
+		err:  `invalid user name "not/valid"`,
+	}, {
+		args: []string{"--group"},
+		err:  "no groups specified",
+	}, {
+		args:   []string{"--group", "--access", "admin", "devops", "dba"},
+		groups: []string{"devops", "dba"},
+		access: "admin",
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		shareCmd := &environment.ShareCommand{}
+		err := testing.InitCommand(shareCmd, test.args)
+		if test.err != "" {
+			c.Check(err, gc.ErrorMatches, test.err)
+			continue
+		}
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(shareCmd.Users, jc.DeepEquals, test.users)
+		c.Check(shareCmd.Groups, jc.DeepEquals, test.groups)
+		c.Check(shareCmd.Access, gc.Equals, test.access)
+	}
+}
+
+func (s *ShareSuite) TestPassesValues(c *gc.C) {
+	_, err := s.run(c, "--access=read", "bob", "alice@sso")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.users, jc.DeepEquals, []names.UserTag{
+		names.NewUserTag("bob"),
+		names.NewUserTag("alice@sso"),
+	})
+	c.Assert(s.fake.access, gc.Equals, params.ReadEnvironAccess)
+}
+
+func (s *ShareSuite) TestPassesGroups(c *gc.C) {
+	_, err := s.run(c, "--group", "--access=admin", "devops", "dba")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.users, gc.HasLen, 0)
+	c.Assert(s.fake.groups, jc.DeepEquals, []string{"devops", "dba"})
+	c.Assert(s.fake.access, gc.Equals, params.AdminEnvironAccess)
+}
+
+func (s *ShareSuite) TestError(c *gc.C) {
+	s.fake.err = errors.New("permission denied")
+	_, err := s.run(c, "bob")
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+type fakeShareAPI struct {
+	users  []names.UserTag
+	groups []string
+	access params.EnvironAccess
+	err    error
+}
+
+func (f *fakeShareAPI) Close() error {
+	return nil
+}
+
+func (f *fakeShareAPI) ShareEnvironment(users []names.UserTag, access params.EnvironAccess) error {
+	f.users = users
+	f.access = access
+	return f.err
+}
+
+func (f *fakeShareAPI) ShareEnvironmentGroups(groups []string, access params.EnvironAccess) error {
+	f.groups = groups
+	f.access = access
+	return f.err
+}
@@ -76,6 +76,30 @@ func (dummyHookContext) OwnerTag() string {
 	return ""
 }
 
+func (dummyHookContext) UnitStatus() (*jujuc.StatusInfo, error) {
+	return &jujuc.StatusInfo{}, nil
+}
+
+func (dummyHookContext) SetUnitStatus(status jujuc.StatusInfo) error {
+	return nil
+}
+
+func (dummyHookContext) SetServiceStatus(status jujuc.StatusInfo) error {
+	return nil
+}
+
+func (dummyHookContext) IsLeader() (bool, error) {
+	return false, nil
+}
+
+func (dummyHookContext) LeaderSettings() (map[string]string, error) {
+	return nil, nil
+}
+
+func (dummyHookContext) WriteLeaderSettings(settings map[string]string) error {
+	return nil
+}
+
 type HelpToolCommand struct {
 	cmd.CommandBase
 	tool string
@@ -23,6 +23,13 @@ func NewRemoveCommand(api RemoveMachineAPI) *RemoveCommand {
 	}
 }
 
+// NewMaintenanceCommand returns a MaintenanceCommand with the api provided as specified.
+func NewMaintenanceCommand(api MaintenanceAPI) *MaintenanceCommand {
+	return &MaintenanceCommand{
+		api: api,
+	}
+}
+
 func NewDisksFlag(disks *[]storage.Constraints) *disksFlag {
 	return &disksFlag{disks}
 }
@@ -23,6 +23,7 @@ var _ = gc.Suite(&MachineCommandSuite{})
 var expectedCommmandNames = []string{
 	"add",
 	"help",
+	"maintenance",
 	"remove",
 }
 
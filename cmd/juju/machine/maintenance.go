@@ -0,0 +1,131 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machine
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/block"
+)
+
+// MaintenanceCommand puts machines into maintenance mode, or takes
+// them out of it.
+type MaintenanceCommand struct {
+	envcmd.EnvCommandBase
+	api         MaintenanceAPI
+	MachineIds  []string
+	Maintenance bool
+	Relocate    bool
+}
+
+const maintenanceDoc = `
+A machine in maintenance mode keeps running the units and containers it
+already has, but no new units or containers are placed on it, either
+automatically or with --to. This allows a host to be drained before
+hypervisor or kernel maintenance. Machines in maintenance are marked in
+the output of "juju status".
+
+With --relocate, the stateless units on the machines are moved to other
+machines: each is replaced by a new unit of its service, placed as
+"juju add-unit" would place it, and is then removed. Units with storage
+are not stateless, and are not moved. Units that are the only unit of
+their service are not moved either, so that the service keeps running.
+Units in containers on the machines are not moved.
+
+Examples:
+	# Stop placing units and containers on machines 3 and 4
+	$ juju machine maintenance on 3 4
+
+	# Also move the stateless units on machine 3 elsewhere
+	$ juju machine maintenance --relocate on 3
+
+	# Allow placements on machine 3 again
+	$ juju machine maintenance off 3
+`
+
+func (c *MaintenanceCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "maintenance",
+		Args:    "on|off <machine> ...",
+		Purpose: "put machines into, or take them out of, maintenance mode",
+		Doc:     maintenanceDoc,
+	}
+}
+
+func (c *MaintenanceCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.BoolVar(&c.Relocate, "relocate", false, "move stateless units of services with more than one unit to other machines")
+}
+
+func (c *MaintenanceCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("no mode specified")
+	}
+	switch args[0] {
+	case "on":
+		c.Maintenance = true
+	case "off":
+		if c.Relocate {
+			return fmt.Errorf("--relocate can only be used when turning maintenance on")
+		}
+		c.Maintenance = false
+	default:
+		return fmt.Errorf(`invalid mode %q, expected "on" or "off"`, args[0])
+	}
+	args = args[1:]
+	if len(args) == 0 {
+		return fmt.Errorf("no machines specified")
+	}
+	for _, id := range args {
+		if !names.IsValidMachine(id) {
+			return fmt.Errorf("invalid machine id %q", id)
+		}
+	}
+	c.MachineIds = args
+	return nil
+}
+
+type MaintenanceAPI interface {
+	SetMachineMaintenance(maintenance, relocateUnits bool, machines ...names.MachineTag) ([]params.ErrorResult, error)
+	Close() error
+}
+
+func (c *MaintenanceCommand) getMaintenanceAPI() (MaintenanceAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	return c.NewAPIClient()
+}
+
+func (c *MaintenanceCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getMaintenanceAPI()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	tags := make([]names.MachineTag, len(c.MachineIds))
+	for i, id := range c.MachineIds {
+		tags[i] = names.NewMachineTag(id)
+	}
+	results, err := client.SetMachineMaintenance(c.Maintenance, c.Relocate, tags...)
+	if err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	failed := false
+	for i, result := range results {
+		if result.Error != nil {
+			fmt.Fprintf(ctx.Stderr, "cannot change maintenance mode of machine %s: %v\n", c.MachineIds[i], result.Error)
+			failed = true
+		}
+	}
+	if failed {
+		return cmd.ErrSilent
+	}
+	return nil
+}
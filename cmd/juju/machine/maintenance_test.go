@@ -0,0 +1,156 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machine_test
+
+import (
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/machine"
+	"github.com/juju/juju/testing"
+)
+
+type MaintenanceSuite struct {
+	testing.FakeJujuHomeSuite
+	fake *fakeMaintenanceAPI
+}
+
+var _ = gc.Suite(&MaintenanceSuite{})
+
+func (s *MaintenanceSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.fake = &fakeMaintenanceAPI{}
+}
+
+func (s *MaintenanceSuite) run(c *gc.C, args ...string) (*cmd.Context, error) {
+	command := machine.NewMaintenanceCommand(s.fake)
+	return testing.RunCommand(c, envcmd.Wrap(command), args...)
+}
+
+func (s *MaintenanceSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args        []string
+		machines    []string
+		maintenance bool
+		relocate    bool
+		errorString string
+	}{
+		{
+			errorString: "no mode specified",
+		}, {
+			args:        []string{"1"},
+			errorString: `invalid mode "1", expected "on" or "off"`,
+		}, {
+			args:        []string{"on"},
+			errorString: "no machines specified",
+		}, {
+			args:        []string{"on", "1", "2/lxc/1"},
+			machines:    []string{"1", "2/lxc/1"},
+			maintenance: true,
+		}, {
+			args:     []string{"off", "1"},
+			machines: []string{"1"},
+		}, {
+			args:        []string{"--relocate", "on", "1"},
+			machines:    []string{"1"},
+			maintenance: true,
+			relocate:    true,
+		}, {
+			args:        []string{"--relocate", "off", "1"},
+			errorString: "--relocate can only be used when turning maintenance on",
+		}, {
+			args:        []string{"on", "lxc"},
+			errorString: `invalid machine id "lxc"`,
+		},
+	} {
+		c.Logf("test %d", i)
+		maintenanceCmd := &machine.MaintenanceCommand{}
+		err := testing.InitCommand(maintenanceCmd, test.args)
+		if test.errorString == "" {
+			c.Check(err, jc.ErrorIsNil)
+			c.Check(maintenanceCmd.Maintenance, gc.Equals, test.maintenance)
+			c.Check(maintenanceCmd.Relocate, gc.Equals, test.relocate)
+			c.Check(maintenanceCmd.MachineIds, jc.DeepEquals, test.machines)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.errorString)
+		}
+	}
+}
+
+func (s *MaintenanceSuite) TestMaintenanceOn(c *gc.C) {
+	_, err := s.run(c, "on", "1", "2/lxc/1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.maintenance, jc.IsTrue)
+	c.Assert(s.fake.relocate, jc.IsFalse)
+	c.Assert(s.fake.machines, jc.DeepEquals, []names.MachineTag{
+		names.NewMachineTag("1"),
+		names.NewMachineTag("2/lxc/1"),
+	})
+}
+
+func (s *MaintenanceSuite) TestMaintenanceOnRelocate(c *gc.C) {
+	_, err := s.run(c, "--relocate", "on", "1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.maintenance, jc.IsTrue)
+	c.Assert(s.fake.relocate, jc.IsTrue)
+	c.Assert(s.fake.machines, jc.DeepEquals, []names.MachineTag{names.NewMachineTag("1")})
+}
+
+func (s *MaintenanceSuite) TestMaintenanceOff(c *gc.C) {
+	s.fake.maintenance = true
+	_, err := s.run(c, "off", "1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.maintenance, jc.IsFalse)
+	c.Assert(s.fake.machines, jc.DeepEquals, []names.MachineTag{names.NewMachineTag("1")})
+}
+
+func (s *MaintenanceSuite) TestMachineError(c *gc.C) {
+	s.fake.results = []params.ErrorResult{
+		{},
+		{Error: &params.Error{Message: "machine 2 not found"}},
+	}
+	ctx, err := s.run(c, "on", "1", "2")
+	c.Assert(err, gc.Equals, cmd.ErrSilent)
+	c.Assert(testing.Stderr(ctx), gc.Equals, "cannot change maintenance mode of machine 2: machine 2 not found\n")
+}
+
+func (s *MaintenanceSuite) TestBlockedError(c *gc.C) {
+	s.fake.err = &params.Error{Code: params.CodeOperationBlocked}
+	_, err := s.run(c, "on", "1")
+	c.Assert(err, gc.Equals, cmd.ErrSilent)
+	// msg is logged
+	stripped := strings.Replace(c.GetTestLog(), "\n", "", -1)
+	c.Assert(stripped, gc.Matches, ".*To unblock changes.*")
+}
+
+type fakeMaintenanceAPI struct {
+	maintenance bool
+	relocate    bool
+	machines    []names.MachineTag
+	results     []params.ErrorResult
+	err         error
+}
+
+func (f *fakeMaintenanceAPI) Close() error {
+	return nil
+}
+
+func (f *fakeMaintenanceAPI) SetMachineMaintenance(maintenance, relocate bool, machines ...names.MachineTag) ([]params.ErrorResult, error) {
+	f.maintenance = maintenance
+	f.relocate = relocate
+	f.machines = machines
+	if f.err != nil {
+		return nil, f.err
+	}
+	if f.results == nil {
+		return make([]params.ErrorResult, len(machines)), nil
+	}
+	return f.results, nil
+}
@@ -223,6 +223,8 @@ var commandNames = []string{
 	"set-constraints",
 	"set-env", // alias for set-environment
 	"set-environment",
+	"share-environment", // alias for environment share
+	"show-hook-history",
 	"ssh",
 	"stat", // alias for status
 	"status",
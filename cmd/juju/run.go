@@ -12,8 +12,12 @@ import (
 
 	"github.com/juju/cmd"
 	"github.com/juju/names"
+	"github.com/juju/utils/set"
 	"launchpad.net/gnuflag"
 
+	"github.com/juju/juju/actions"
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/api/action"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/envcmd"
 	"github.com/juju/juju/cmd/juju/block"
@@ -22,13 +26,14 @@ import (
 // RunCommand is responsible for running arbitrary commands on remote machines.
 type RunCommand struct {
 	envcmd.EnvCommandBase
-	out      cmd.Output
-	all      bool
-	timeout  time.Duration
-	machines []string
-	services []string
-	units    []string
-	commands string
+	out         cmd.Output
+	all         bool
+	timeout     time.Duration
+	maxParallel int
+	machines    []string
+	services    []string
+	units       []string
+	commands    string
 }
 
 const runDoc = `
@@ -40,8 +45,12 @@ names.  At least one target specifier is needed.
 Multiple values can be set for --machine, --service, and --unit by using
 comma separated values.
 
-If the target is a machine, the command is run as the "ubuntu" user on
-the remote machine.
+The commands are queued as actions in the environment, and run by the
+agents responsible for each target over their existing connection to the
+API server. Results are shown as each target completes.
+
+If the target is a machine, the command is run by the machine agent as
+the "root" user on that machine.
 
 If the target is a service, the command is run on all units for that
 service. For example, if there was a service "mysql" and that service
@@ -57,6 +66,10 @@ the unit.
 in the environment.  If you specify --all you cannot provide additional
 targets.
 
+--max-parallel limits the number of targets the commands run on at any
+one time. Once a target completes, the commands are queued on the next
+one. By default the commands are queued on all targets at once.
+
 `
 
 func (c *RunCommand) Info() *cmd.Info {
@@ -72,6 +85,7 @@ func (c *RunCommand) SetFlags(f *gnuflag.FlagSet) {
 	c.out.AddFlags(f, "smart", cmd.DefaultFormatters)
 	f.BoolVar(&c.all, "all", false, "run the commands on all the machines")
 	f.DurationVar(&c.timeout, "timeout", 5*time.Minute, "how long to wait before the remote command is considered to have failed")
+	f.IntVar(&c.maxParallel, "max-parallel", 0, "maximum number of targets to run the commands on at once (0 means no limit)")
 	f.Var(cmd.NewStringsValue(nil, &c.machines), "machine", "one or more machine ids")
 	f.Var(cmd.NewStringsValue(nil, &c.services), "service", "one or more service names")
 	f.Var(cmd.NewStringsValue(nil, &c.units), "unit", "one or more unit ids")
@@ -83,6 +97,9 @@ func (c *RunCommand) Init(args []string) error {
 	}
 	c.commands, args = args[0], args[1:]
 
+	if c.maxParallel < 0 {
+		return fmt.Errorf("--max-parallel must not be negative")
+	}
 	if c.all {
 		if len(c.machines) != 0 {
 			return fmt.Errorf("You cannot specify --all and individual machines")
@@ -151,10 +168,13 @@ func ConvertRunResults(runResults []params.RunResult) interface{} {
 		// We always want to have a string for stdout, but only show stderr,
 		// code and error if they are there.
 		values := make(map[string]interface{})
-		values["MachineId"] = result.MachineId
+		// Results from units are reported by the unit agent,
+		// which does not know the machine id.
+		if result.MachineId != "" {
+			values["MachineId"] = result.MachineId
+		}
 		if result.UnitId != "" {
 			values["UnitId"] = result.UnitId
-
 		}
 		storeOutput(values, "Stdout", result.Stdout)
 		if len(result.Stderr) > 0 {
@@ -172,6 +192,16 @@ func ConvertRunResults(runResults []params.RunResult) interface{} {
 	return results
 }
 
+// runPollInterval is how often the results of queued commands are
+// checked for.
+var runPollInterval = time.Second
+
+// runGracePeriod is how long after the timeout has passed we keep
+// waiting for an agent to report the results of queued commands. The
+// agents enforce the timeout themselves, but only from the time they
+// start running the commands.
+var runGracePeriod = 30 * time.Second
+
 func (c *RunCommand) Run(ctx *cmd.Context) error {
 	client, err := getRunAPIClient(c)
 	if err != nil {
@@ -179,20 +209,96 @@ func (c *RunCommand) Run(ctx *cmd.Context) error {
 	}
 	defer client.Close()
 
+	if client.BestAPIVersion() < 1 {
+		// The API server cannot queue the commands as
+		// actions, so have it run them over SSH instead.
+		return c.runOverSSH(ctx, client)
+	}
+
+	batches, err := c.batches(client)
+	if err != nil {
+		return err
+	}
+
+	// Results are written as each target completes, unless they
+	// are formatted as JSON, which can only be written as a whole.
+	streaming := c.out.Name() != "json"
 	var runResults []params.RunResult
+	var targetCount int
+	report := func(result params.RunResult) {
+		// If we are just dealing with one target, AND we are using
+		// the smart format, then we pretend we were running it
+		// locally once the results are all in.
+		if !streaming || (targetCount == 1 && c.out.Name() == "smart") {
+			runResults = append(runResults, result)
+			return
+		}
+		c.out.Write(ctx, ConvertRunResults([]params.RunResult{result}))
+	}
+
+	running := make(map[string]time.Time)
+	var order []string
+	for len(batches) > 0 || len(running) > 0 {
+		for len(batches) > 0 && (c.maxParallel == 0 || len(running) < c.maxParallel) {
+			queued, err := c.enqueue(client, batches[0])
+			if err != nil {
+				return block.ProcessBlockedError(err, block.BlockChange)
+			}
+			batches = batches[1:]
+			if targetCount == 0 {
+				targetCount = len(queued) + len(batches)
+			}
+			deadline := time.Now().Add(c.timeout + runGracePeriod)
+			for _, result := range queued {
+				if result.Error != nil {
+					report(actionResultToRunResult(result))
+					continue
+				}
+				running[result.Action.Tag] = deadline
+				order = append(order, result.Action.Tag)
+			}
+		}
+		if len(running) == 0 {
+			continue
+		}
+		time.Sleep(runPollInterval)
+		completed, err := c.completed(client, order, running)
+		if err != nil {
+			return err
+		}
+		for _, result := range completed {
+			report(result)
+		}
+	}
+
+	if targetCount == 1 && c.out.Name() == "smart" && len(runResults) == 1 {
+		return writeSingleResult(ctx, runResults[0])
+	}
+	if !streaming {
+		c.out.Write(ctx, ConvertRunResults(runResults))
+	}
+	return nil
+}
+
+// runOverSSH has the API server run the commands on the targets over
+// SSH, as API servers without version 1 of the Action facade do.
+func (c *RunCommand) runOverSSH(ctx *cmd.Context, client RunClient) error {
+	if c.maxParallel != 0 {
+		return fmt.Errorf("--max-parallel is not supported by this API server")
+	}
+	var runResults []params.RunResult
+	var err error
 	if c.all {
-		runResults, err = client.RunOnAllMachines(c.commands, c.timeout)
+		runResults, err = client.SSHRunOnAllMachines(c.commands, c.timeout)
 	} else {
-		params := params.RunParams{
+		runResults, err = client.SSHRun(params.RunParams{
 			Commands: c.commands,
 			Timeout:  c.timeout,
 			Machines: c.machines,
 			Services: c.services,
 			Units:    c.units,
-		}
-		runResults, err = client.Run(params)
+		})
 	}
-
 	if err != nil {
 		return block.ProcessBlockedError(err, block.BlockChange)
 	}
@@ -200,21 +306,220 @@ func (c *RunCommand) Run(ctx *cmd.Context) error {
 	// If we are just dealing with one result, AND we are using the smart
 	// format, then pretend we were running it locally.
 	if len(runResults) == 1 && c.out.Name() == "smart" {
-		result := runResults[0]
-		ctx.Stdout.Write(result.Stdout)
-		ctx.Stderr.Write(result.Stderr)
-		if result.Error != "" {
-			// Convert the error string back into an error object.
-			return fmt.Errorf("%s", result.Error)
+		return writeSingleResult(ctx, runResults[0])
+	}
+	c.out.Write(ctx, ConvertRunResults(runResults))
+	return nil
+}
+
+// writeSingleResult writes the output of the commands run on a single
+// target as though they had been run locally.
+func writeSingleResult(ctx *cmd.Context, result params.RunResult) error {
+	ctx.Stdout.Write(result.Stdout)
+	ctx.Stderr.Write(result.Stderr)
+	if result.Error != "" {
+		// Convert the error string back into an error object.
+		return fmt.Errorf("%s", result.Error)
+	}
+	if result.Code != 0 {
+		return cmd.NewRcPassthroughError(result.Code)
+	}
+	return nil
+}
+
+// batches returns the run parameters for each call needed to queue
+// the commands on all the targets. Without a limit on the number of
+// targets running at once, a single call queues the commands on all of
+// them; otherwise the targets are expanded to individual machines and
+// units, so that the commands can be queued on each in turn.
+func (c *RunCommand) batches(client RunClient) ([]params.RunParams, error) {
+	run := params.RunParams{
+		Commands: c.commands,
+		Timeout:  c.timeout,
+		Machines: c.machines,
+		Services: c.services,
+		Units:    c.units,
+	}
+	if c.maxParallel == 0 {
+		return []params.RunParams{run}, nil
+	}
+	status, err := client.Status(nil)
+	if err != nil {
+		return nil, err
+	}
+	machines := c.machines
+	units := c.units
+	if c.all {
+		machines = allMachineIds(status.Machines)
+	} else {
+		units, err = expandServiceUnits(status, c.services, c.units)
+		if err != nil {
+			return nil, err
 		}
-		if result.Code != 0 {
-			return cmd.NewRcPassthroughError(result.Code)
+	}
+	var batches []params.RunParams
+	for _, unit := range units {
+		batches = append(batches, params.RunParams{
+			Commands: c.commands,
+			Timeout:  c.timeout,
+			Units:    []string{unit},
+		})
+	}
+	for _, machine := range machines {
+		batches = append(batches, params.RunParams{
+			Commands: c.commands,
+			Timeout:  c.timeout,
+			Machines: []string{machine},
+		})
+	}
+	return batches, nil
+}
+
+// enqueue queues the commands on the targets in the run parameters.
+func (c *RunCommand) enqueue(client RunClient, run params.RunParams) ([]params.ActionResult, error) {
+	if c.all && c.maxParallel == 0 {
+		return client.RunOnAllMachines(run.Commands, run.Timeout)
+	}
+	return client.Run(run)
+}
+
+// completed checks on the running actions, and returns the results of
+// those that have finished or timed out, in the order they were queued.
+// Finished actions are removed from running. Actions that timed out
+// before any agent started them are cancelled, so they don't run
+// after their results have been given up on.
+func (c *RunCommand) completed(client RunClient, order []string, running map[string]time.Time) ([]params.RunResult, error) {
+	var entities []params.Entity
+	for _, tag := range order {
+		if _, ok := running[tag]; ok {
+			entities = append(entities, params.Entity{Tag: tag})
+		}
+	}
+	results, err := client.Actions(params.Entities{Entities: entities})
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != len(entities) {
+		return nil, fmt.Errorf("expected %d results, got %d", len(entities), len(results.Results))
+	}
+	var completed []params.RunResult
+	var timedOut []params.Entity
+	for i, result := range results.Results {
+		tag := entities[i].Tag
+		if result.Error == nil {
+			switch result.Status {
+			case params.ActionPending, params.ActionRunning:
+				if c.timeout == 0 || time.Now().Before(running[tag]) {
+					continue
+				}
+				if result.Status == params.ActionPending {
+					timedOut = append(timedOut, entities[i])
+				}
+				runResult := actionResultToRunResult(result)
+				runResult.Error = fmt.Sprintf("timed out waiting for results after %v", c.timeout)
+				completed = append(completed, runResult)
+				delete(running, tag)
+				continue
+			}
 		}
-		return nil
+		completed = append(completed, actionResultToRunResult(result))
+		delete(running, tag)
 	}
+	if len(timedOut) > 0 {
+		cancelled, err := client.Cancel(params.Entities{Entities: timedOut})
+		if err != nil {
+			return nil, err
+		}
+		for i, result := range cancelled.Results {
+			// The action may have been started since we
+			// checked on it, in which case its agent will
+			// enforce the timeout.
+			if result.Error != nil && i < len(timedOut) {
+				logger.Warningf("cannot cancel %s: %v", timedOut[i].Tag, result.Error)
+			}
+		}
+	}
+	return completed, nil
+}
 
-	c.out.Write(ctx, ConvertRunResults(runResults))
-	return nil
+// actionResultToRunResult converts the result of a juju-run action
+// into the result of running the commands on its receiver.
+func actionResultToRunResult(result params.ActionResult) params.RunResult {
+	var runResult params.RunResult
+	if result.Action != nil {
+		tag, err := names.ParseTag(result.Action.Receiver)
+		if err == nil {
+			switch tag := tag.(type) {
+			case names.MachineTag:
+				runResult.MachineId = tag.Id()
+			case names.UnitTag:
+				runResult.UnitId = tag.Id()
+			}
+		}
+	}
+	if result.Error != nil {
+		runResult.Error = result.Error.Error()
+		return runResult
+	}
+	if len(result.Output) > 0 {
+		response, err := actions.ParseJujuRunResults(result.Output)
+		if err != nil {
+			runResult.Error = err.Error()
+			return runResult
+		}
+		runResult.ExecResponse = *response
+	}
+	if result.Status != params.ActionCompleted {
+		runResult.Error = result.Message
+		if runResult.Error == "" {
+			runResult.Error = fmt.Sprintf("action %s", result.Status)
+		}
+	}
+	return runResult
+}
+
+// allMachineIds returns the ids of all the machines and containers in
+// the status, ordered so that containers follow their host.
+func allMachineIds(machines map[string]api.MachineStatus) []string {
+	var ids []string
+	for _, id := range sortStrings(machineStatusIds(machines)) {
+		ids = append(ids, id)
+		ids = append(ids, allMachineIds(machines[id].Containers)...)
+	}
+	return ids
+}
+
+func machineStatusIds(machines map[string]api.MachineStatus) []string {
+	ids := make([]string, 0, len(machines))
+	for id := range machines {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// expandServiceUnits returns the names of the units given, along with
+// all the units of the services given, including subordinates.
+func expandServiceUnits(status *api.Status, services, units []string) ([]string, error) {
+	unitNames := set.NewStrings(units...)
+	wanted := set.NewStrings(services...)
+	var addUnits func(map[string]api.UnitStatus)
+	addUnits = func(units map[string]api.UnitStatus) {
+		for unitName, unit := range units {
+			if service, err := names.UnitService(unitName); err == nil && wanted.Contains(service) {
+				unitNames.Add(unitName)
+			}
+			addUnits(unit.Subordinates)
+		}
+	}
+	for _, service := range services {
+		if _, ok := status.Services[service]; !ok {
+			return nil, fmt.Errorf("service %q not found", service)
+		}
+	}
+	for _, service := range status.Services {
+		addUnits(service.Units)
+	}
+	return unitNames.SortedValues(), nil
 }
 
 // In order to be able to easily mock out the API side for testing,
@@ -222,11 +527,50 @@ func (c *RunCommand) Run(ctx *cmd.Context) error {
 
 type RunClient interface {
 	Close() error
-	RunOnAllMachines(commands string, timeout time.Duration) ([]params.RunResult, error)
-	Run(run params.RunParams) ([]params.RunResult, error)
+	BestAPIVersion() int
+	RunOnAllMachines(commands string, timeout time.Duration) ([]params.ActionResult, error)
+	Run(run params.RunParams) ([]params.ActionResult, error)
+	Actions(arg params.Entities) (params.ActionResults, error)
+	Cancel(arg params.Entities) (params.ActionResults, error)
+	Status(patterns []string) (*api.Status, error)
+
+	// SSHRunOnAllMachines and SSHRun have the API server run the
+	// commands over SSH; they are used with API servers that
+	// cannot queue them as actions.
+	SSHRunOnAllMachines(commands string, timeout time.Duration) ([]params.RunResult, error)
+	SSHRun(run params.RunParams) ([]params.RunResult, error)
+}
+
+// runAPIClient queues the commands through the action facade, and
+// looks up the targets through the client facade.
+type runAPIClient struct {
+	*action.Client
+	apiClient *api.Client
+}
+
+// Status implements RunClient.
+func (c *runAPIClient) Status(patterns []string) (*api.Status, error) {
+	return c.apiClient.Status(patterns)
+}
+
+// SSHRunOnAllMachines implements RunClient.
+func (c *runAPIClient) SSHRunOnAllMachines(commands string, timeout time.Duration) ([]params.RunResult, error) {
+	return c.apiClient.RunOnAllMachines(commands, timeout)
+}
+
+// SSHRun implements RunClient.
+func (c *runAPIClient) SSHRun(run params.RunParams) ([]params.RunResult, error) {
+	return c.apiClient.Run(run)
 }
 
 // Here we need the signature to be correct for the interface.
 var getRunAPIClient = func(c *RunCommand) (RunClient, error) {
-	return c.NewAPIClient()
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, err
+	}
+	return &runAPIClient{
+		Client:    action.NewClient(root),
+		apiClient: root.Client(),
+	}, nil
 }
@@ -10,10 +10,13 @@ import (
 	"time"
 
 	"github.com/juju/cmd"
+	"github.com/juju/names"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils/exec"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/actions"
+	"github.com/juju/juju/api"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/envcmd"
 	"github.com/juju/juju/testing"
@@ -106,6 +109,10 @@ func (*RunSuite) TestTargetArgParsing(c *gc.C) {
 		machines: []string{"0"},
 		services: []string{"mysql"},
 		units:    []string{"wordpress/0", "wordpress/1"},
+	}, {
+		message:  "negative max-parallel",
+		args:     []string{"--max-parallel=-1", "--all", "sudo reboot"},
+		errMatch: "--max-parallel must not be negative",
 	}} {
 		c.Log(fmt.Sprintf("%v: %s", i, test.message))
 		runCmd := &RunCommand{}
@@ -245,16 +252,15 @@ func (s *RunSuite) TestRunForMachineAndUnit(c *gc.C) {
 		machineId: "0",
 	}
 	unitResponse := mockResponse{
-		stdout:    "bumblebee",
-		machineId: "1",
-		unitId:    "unit/0",
+		stdout: "bumblebee",
+		unitId: "unit/0",
 	}
 	mock.setResponse("0", machineResponse)
 	mock.setResponse("unit/0", unitResponse)
 
 	unformatted := ConvertRunResults([]params.RunResult{
-		makeRunResult(machineResponse),
 		makeRunResult(unitResponse),
+		makeRunResult(machineResponse),
 	})
 
 	jsonFormatted, err := cmd.FormatJson(unformatted)
@@ -375,22 +381,139 @@ func (s *RunSuite) TestSingleResponse(c *gc.C) {
 	}
 }
 
+func (s *RunSuite) TestMaxParallel(c *gc.C) {
+	mock := s.setupMockAPI()
+	mock.setMachinesAlive("0", "1", "2")
+	for _, id := range []string{"0", "1", "2"} {
+		mock.setResponse(id, mockResponse{stdout: id, machineId: id})
+	}
+
+	context, err := testing.RunCommand(c, &RunCommand{}, "--format=yaml", "--max-parallel=2", "--all", "hostname")
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(mock.maxRunning, gc.Equals, 2)
+	c.Check(mock.enqueued, jc.DeepEquals, []string{"0", "1", "2"})
+	c.Check(testing.Stdout(context), gc.Equals, ""+
+		"- MachineId: \"0\"\n"+
+		"  Stdout: \"0\"\n"+
+		"- MachineId: \"1\"\n"+
+		"  Stdout: \"1\"\n"+
+		"- MachineId: \"2\"\n"+
+		"  Stdout: \"2\"\n",
+	)
+}
+
+func (s *RunSuite) TestMaxParallelExpandsServices(c *gc.C) {
+	mock := s.setupMockAPI()
+	mock.services = map[string][]string{
+		"wordpress": {"wordpress/0", "wordpress/1"},
+		"mysql":     {"mysql/0"},
+	}
+	for _, id := range []string{"wordpress/0", "wordpress/1", "mysql/0"} {
+		mock.setResponse(id, mockResponse{unitId: id})
+	}
+
+	_, err := testing.RunCommand(c, &RunCommand{}, "--format=json", "--max-parallel=1", "--service=wordpress", "--machine=0", "hostname")
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(mock.maxRunning, gc.Equals, 1)
+	c.Check(mock.enqueued, jc.DeepEquals, []string{"wordpress/0", "wordpress/1", "0"})
+}
+
+func (s *RunSuite) TestMaxParallelUnknownService(c *gc.C) {
+	s.setupMockAPI()
+	_, err := testing.RunCommand(c, &RunCommand{}, "--max-parallel=1", "--service=foo", "hostname")
+	c.Assert(err, gc.ErrorMatches, `service "foo" not found`)
+}
+
+func (s *RunSuite) TestTimesOutWaitingForResults(c *gc.C) {
+	mock := s.setupMockAPI()
+	mock.setMachinesAlive("0")
+	mock.setPending("0")
+
+	_, err := testing.RunCommand(c, &RunCommand{}, "--timeout=10ms", "--all", "hostname")
+	c.Assert(err, gc.ErrorMatches, "timed out waiting for results after 10ms")
+	c.Assert(mock.cancelled, jc.DeepEquals, []string{"0"})
+}
+
+func (s *RunSuite) TestOlderServerRunsOverSSH(c *gc.C) {
+	mock := s.setupMockAPI()
+	mock.apiVersion = 0
+	mock.sshResults = []params.RunResult{
+		makeRunResult(mockResponse{stdout: "0", machineId: "0"}),
+		makeRunResult(mockResponse{stdout: "1", machineId: "1"}),
+	}
+
+	context, err := testing.RunCommand(c, &RunCommand{}, "--format=yaml", "--machine=0,1", "hostname")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(mock.enqueued, gc.HasLen, 0)
+	c.Check(mock.sshRun, jc.DeepEquals, []params.RunParams{{
+		Commands: "hostname",
+		Timeout:  5 * time.Minute,
+		Machines: []string{"0", "1"},
+	}})
+	c.Check(testing.Stdout(context), gc.Equals, ""+
+		"- MachineId: \"0\"\n"+
+		"  Stdout: \"0\"\n"+
+		"- MachineId: \"1\"\n"+
+		"  Stdout: \"1\"\n",
+	)
+}
+
+func (s *RunSuite) TestOlderServerAllMachines(c *gc.C) {
+	mock := s.setupMockAPI()
+	mock.apiVersion = 0
+	mock.sshResults = []params.RunResult{
+		makeRunResult(mockResponse{stdout: "hello\n", machineId: "0"}),
+	}
+
+	context, err := testing.RunCommand(c, &RunCommand{}, "--all", "hostname")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(mock.sshRunAll, jc.DeepEquals, []string{"hostname"})
+	c.Check(testing.Stdout(context), gc.Equals, "hello\n")
+}
+
+func (s *RunSuite) TestOlderServerMaxParallel(c *gc.C) {
+	mock := s.setupMockAPI()
+	mock.apiVersion = 0
+	_, err := testing.RunCommand(c, &RunCommand{}, "--max-parallel=2", "--all", "hostname")
+	c.Assert(err, gc.ErrorMatches, "--max-parallel is not supported by this API server")
+}
+
 func (s *RunSuite) setupMockAPI() *mockRunAPI {
-	mock := &mockRunAPI{}
+	mock := &mockRunAPI{apiVersion: 1}
 	s.PatchValue(&getRunAPIClient, func(_ *RunCommand) (RunClient, error) {
 		return mock, nil
 	})
+	s.PatchValue(&runPollInterval, time.Millisecond)
+	s.PatchValue(&runGracePeriod, time.Duration(0))
 	return mock
 }
 
 type mockRunAPI struct {
-	stdout string
-	stderr string
-	code   int
 	// machines, services, units
 	machines  map[string]bool
-	responses map[string]params.RunResult
+	services  map[string][]string
+	responses map[string]mockResponse
+	pending   map[string]bool
 	block     bool
+
+	// apiVersion holds the version of the Action facade.
+	apiVersion int
+	// sshResults holds the results of running commands over SSH.
+	sshResults []params.RunResult
+	// sshRun and sshRunAll record the commands run over SSH.
+	sshRun    []params.RunParams
+	sshRunAll []string
+	// cancelled records the receiver ids of cancelled actions.
+	cancelled []string
+
+	// receivers holds the receiver id of each queued action, by tag.
+	receivers map[string]string
+	// enqueued records the receiver ids in the order queued.
+	enqueued   []string
+	running    int
+	maxRunning int
 }
 
 type mockResponse struct {
@@ -428,23 +551,86 @@ func makeRunResult(mock mockResponse) params.RunResult {
 
 func (m *mockRunAPI) setResponse(id string, mock mockResponse) {
 	if m.responses == nil {
-		m.responses = make(map[string]params.RunResult)
+		m.responses = make(map[string]mockResponse)
+	}
+	m.responses[id] = mock
+}
+
+func (m *mockRunAPI) setPending(ids ...string) {
+	if m.pending == nil {
+		m.pending = make(map[string]bool)
+	}
+	for _, id := range ids {
+		m.pending[id] = true
 	}
-	m.responses[id] = makeRunResult(mock)
 }
 
 func (*mockRunAPI) Close() error {
 	return nil
 }
 
-func (m *mockRunAPI) RunOnAllMachines(commands string, timeout time.Duration) ([]params.RunResult, error) {
-	var result []params.RunResult
+func (m *mockRunAPI) BestAPIVersion() int {
+	return m.apiVersion
+}
+
+func (m *mockRunAPI) SSHRunOnAllMachines(commands string, timeout time.Duration) ([]params.RunResult, error) {
+	m.sshRunAll = append(m.sshRunAll, commands)
+	return m.sshResults, nil
+}
 
-	if m.block {
-		return result, &params.Error{
-			Code:    params.CodeOperationBlocked,
-			Message: "The operation has been blocked.",
+func (m *mockRunAPI) SSHRun(run params.RunParams) ([]params.RunResult, error) {
+	m.sshRun = append(m.sshRun, run)
+	return m.sshResults, nil
+}
+
+func (m *mockRunAPI) Cancel(arg params.Entities) (params.ActionResults, error) {
+	results := params.ActionResults{Results: make([]params.ActionResult, len(arg.Entities))}
+	for i, entity := range arg.Entities {
+		id, ok := m.receivers[entity.Tag]
+		if !ok {
+			results.Results[i].Error = &params.Error{Message: "id not found", Code: params.CodeNotFound}
+			continue
 		}
+		m.cancelled = append(m.cancelled, id)
+		results.Results[i].Status = params.ActionCancelled
+	}
+	return results, nil
+}
+
+func (m *mockRunAPI) blockedError() error {
+	return &params.Error{
+		Code:    params.CodeOperationBlocked,
+		Message: "The operation has been blocked.",
+	}
+}
+
+// enqueue returns a queued juju-run action for the receiver.
+func (m *mockRunAPI) enqueue(receiver names.Tag) params.ActionResult {
+	if m.receivers == nil {
+		m.receivers = make(map[string]string)
+	}
+	tag := names.NewActionTag(fmt.Sprintf("00000000-0000-0000-0000-%012d", len(m.enqueued)))
+	m.receivers[tag.String()] = receiver.Id()
+	m.enqueued = append(m.enqueued, receiver.Id())
+	m.running++
+	if m.running > m.maxRunning {
+		m.maxRunning = m.running
+	}
+	return params.ActionResult{
+		Action: &params.Action{
+			Tag:      tag.String(),
+			Receiver: receiver.String(),
+			Name:     actions.JujuRunActionName,
+		},
+		Status: params.ActionPending,
+	}
+}
+
+func (m *mockRunAPI) RunOnAllMachines(commands string, timeout time.Duration) ([]params.ActionResult, error) {
+	var result []params.ActionResult
+
+	if m.block {
+		return result, m.blockedError()
 	}
 	sortedMachineIds := make([]string, 0, len(m.machines))
 	for machineId := range m.machines {
@@ -453,40 +639,88 @@ func (m *mockRunAPI) RunOnAllMachines(commands string, timeout time.Duration) ([
 	sort.Strings(sortedMachineIds)
 
 	for _, machineId := range sortedMachineIds {
-		response, found := m.responses[machineId]
-		if !found {
-			// Consider this a timeout
-			response = params.RunResult{MachineId: machineId, Error: "command timed out"}
-		}
-		result = append(result, response)
+		result = append(result, m.enqueue(names.NewMachineTag(machineId)))
 	}
 
 	return result, nil
 }
 
-func (m *mockRunAPI) Run(runParams params.RunParams) ([]params.RunResult, error) {
-	var result []params.RunResult
+func (m *mockRunAPI) Run(runParams params.RunParams) ([]params.ActionResult, error) {
+	var result []params.ActionResult
 
 	if m.block {
-		return result, &params.Error{
-			Code:    params.CodeOperationBlocked,
-			Message: "The operation has been blocked.",
-		}
-	}
-	// Just add in ids that match in order.
-	for _, id := range runParams.Machines {
-		response, found := m.responses[id]
-		if found {
-			result = append(result, response)
-		}
+		return result, m.blockedError()
 	}
 	// mock ignores services
 	for _, id := range runParams.Units {
+		result = append(result, m.enqueue(names.NewUnitTag(id)))
+	}
+	for _, id := range runParams.Machines {
+		result = append(result, m.enqueue(names.NewMachineTag(id)))
+	}
+
+	return result, nil
+}
+
+func (m *mockRunAPI) Actions(arg params.Entities) (params.ActionResults, error) {
+	results := params.ActionResults{Results: make([]params.ActionResult, len(arg.Entities))}
+	for i, entity := range arg.Entities {
+		current := &results.Results[i]
+		id, ok := m.receivers[entity.Tag]
+		if !ok {
+			current.Error = &params.Error{Message: "id not found", Code: params.CodeNotFound}
+			continue
+		}
+		var receiver names.Tag = names.NewUnitTag(id)
+		if names.IsValidMachine(id) {
+			receiver = names.NewMachineTag(id)
+		}
+		current.Action = &params.Action{
+			Tag:      entity.Tag,
+			Receiver: receiver.String(),
+			Name:     actions.JujuRunActionName,
+		}
+		if m.pending[id] {
+			current.Status = params.ActionPending
+			continue
+		}
+		m.running--
 		response, found := m.responses[id]
-		if found {
-			result = append(result, response)
+		if !found || response.error != "" {
+			current.Status = params.ActionFailed
+			current.Message = response.error
+			if !found {
+				current.Message = "command timed out"
+			}
+			continue
+		}
+		current.Status = params.ActionCompleted
+		current.Output = make(map[string]interface{})
+		for key, value := range actions.JujuRunResults(&exec.ExecResponse{
+			Stdout: []byte(response.stdout),
+			Stderr: []byte(response.stderr),
+			Code:   response.code,
+		}) {
+			current.Output[key] = value
 		}
 	}
+	return results, nil
+}
 
-	return result, nil
+func (m *mockRunAPI) Status(patterns []string) (*api.Status, error) {
+	status := &api.Status{
+		Machines: make(map[string]api.MachineStatus),
+		Services: make(map[string]api.ServiceStatus),
+	}
+	for id := range m.machines {
+		status.Machines[id] = api.MachineStatus{Id: id}
+	}
+	for service, units := range m.services {
+		serviceStatus := api.ServiceStatus{Units: make(map[string]api.UnitStatus)}
+		for _, unit := range units {
+			serviceStatus.Units[unit] = api.UnitStatus{}
+		}
+		status.Services[service] = serviceStatus
+	}
+	return status, nil
 }
@@ -9,12 +9,14 @@ import (
 	"io/ioutil"
 	"os"
 	"strings"
+	"time"
 	"unicode/utf8"
 
 	"github.com/juju/cmd"
 	"github.com/juju/utils/keyvalues"
 	"launchpad.net/gnuflag"
 
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/envcmd"
 	"github.com/juju/juju/cmd/juju/block"
 )
@@ -25,6 +27,8 @@ type SetCommand struct {
 	ServiceName     string
 	SettingsStrings map[string]string
 	SettingsYAML    cmd.FileVar
+	HookTimeout     *time.Duration
+	hookTimeout     string
 }
 
 const setDoc = `
@@ -38,6 +42,11 @@ size of this value is 5M.
 
 Option values may be any UTF-8 encoded string. UTF-8 is accepted on the command
 line and in configuration files.
+
+The --hook-timeout option sets how long the service's hooks may run before
+they are killed and the unit is put into an error state; it overrides the
+environment's hook-timeout setting. A timeout of 0 restores the environment
+default.
 `
 
 const maxValueSize = 5242880
@@ -53,6 +62,7 @@ func (c *SetCommand) Info() *cmd.Info {
 
 func (c *SetCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.Var(&c.SettingsYAML, "config", "path to yaml-formatted service config")
+	f.StringVar(&c.hookTimeout, "hook-timeout", "", "how long the service's hooks may run, e.g. 10m")
 }
 
 func (c *SetCommand) Init(args []string) error {
@@ -63,6 +73,16 @@ func (c *SetCommand) Init(args []string) error {
 		return errors.New("cannot specify --config when using key=value arguments")
 	}
 	c.ServiceName = args[0]
+	if c.hookTimeout != "" {
+		timeout, err := time.ParseDuration(c.hookTimeout)
+		if err != nil {
+			return fmt.Errorf("invalid hook timeout: %v", err)
+		}
+		if timeout < 0 {
+			return errors.New("hook timeout must not be negative")
+		}
+		c.HookTimeout = &timeout
+	}
 	settings, err := keyvalues.Parse(args[1:], true)
 	if err != nil {
 		return err
@@ -79,6 +99,15 @@ func (c *SetCommand) Run(ctx *cmd.Context) error {
 	}
 	defer api.Close()
 
+	if c.HookTimeout != nil {
+		err := api.ServiceUpdate(params.ServiceUpdate{
+			ServiceName: c.ServiceName,
+			HookTimeout: c.HookTimeout,
+		})
+		if err != nil {
+			return block.ProcessBlockedError(err, block.BlockChange)
+		}
+	}
 	if c.SettingsYAML.Path != "" {
 		b, err := c.SettingsYAML.Read(ctx)
 		if err != nil {
@@ -8,6 +8,7 @@ import (
 	"io/ioutil"
 	"os"
 	"strings"
+	"time"
 	"unicode/utf8"
 
 	"github.com/juju/cmd"
@@ -123,6 +124,34 @@ func (s *SetSuite) TestSetConfig(c *gc.C) {
 	})
 }
 
+func (s *SetSuite) TestSetHookTimeout(c *gc.C) {
+	ctx := coretesting.ContextForDir(c, s.dir)
+	code := cmd.Main(envcmd.Wrap(&SetCommand{}), ctx, []string{"dummy-service", "--hook-timeout", "10m"})
+	c.Check(code, gc.Equals, 0)
+	err := s.svc.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.svc.HookTimeout(), gc.Equals, 10*time.Minute)
+
+	assertSetSuccess(c, s.dir, s.svc, []string{
+		"--hook-timeout", "0",
+		"username=hello",
+	}, charm.Settings{
+		"username": "hello",
+	})
+	err = s.svc.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.svc.HookTimeout(), gc.Equals, time.Duration(0))
+}
+
+func (s *SetSuite) TestSetHookTimeoutFail(c *gc.C) {
+	assertSetFail(c, s.dir, []string{
+		"--hook-timeout", "soon",
+	}, "error: invalid hook timeout: .*\n")
+	assertSetFail(c, s.dir, []string{
+		"--hook-timeout", "-1m",
+	}, "error: hook timeout must not be negative\n")
+}
+
 func (s *SetSuite) TestBlockSetConfig(c *gc.C) {
 	// Block operation
 	s.AssertConfigParameterUpdated(c, "block-all-changes", true)
@@ -0,0 +1,125 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+// ShowHookHistoryCommand shows the hooks, actions and commands recently
+// run by a unit.
+type ShowHookHistoryCommand struct {
+	envcmd.EnvCommandBase
+	UnitName string
+	out      cmd.Output
+}
+
+const showHookHistoryDoc = `
+Show the hooks, actions and juju run commands most recently run by a unit,
+most recent first, with the relation and remote unit each ran for, when it
+started, how long it took, and its result. A result of "ok" means that it
+succeeded; otherwise the result describes how it failed, for example
+"exit status 1".
+
+Only the most recent 100 records are kept for each unit. Hooks that the
+charm does not implement are not recorded.
+`
+
+func (c *ShowHookHistoryCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "show-hook-history",
+		Args:    "<unit>",
+		Purpose: "show the hooks, actions and commands recently run by a unit",
+		Doc:     showHookHistoryDoc,
+	}
+}
+
+func (c *ShowHookHistoryCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatHookHistoryTabular,
+	})
+}
+
+func (c *ShowHookHistoryCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no unit specified")
+	}
+	c.UnitName = args[0]
+	if !names.IsValidUnit(c.UnitName) {
+		return errors.Errorf("invalid unit name %q", c.UnitName)
+	}
+	return cmd.CheckEmpty(args[1:])
+}
+
+// hookRecord holds a hook record as displayed by show-hook-history.
+type hookRecord struct {
+	Kind       string `yaml:"kind" json:"kind"`
+	Name       string `yaml:"name" json:"name"`
+	Relation   string `yaml:"relation,omitempty" json:"relation,omitempty"`
+	RemoteUnit string `yaml:"remote-unit,omitempty" json:"remote-unit,omitempty"`
+	Started    string `yaml:"started" json:"started"`
+	Duration   string `yaml:"duration" json:"duration"`
+	Result     string `yaml:"result" json:"result"`
+}
+
+func formatHookRecords(records []params.HookRecord) []hookRecord {
+	out := make([]hookRecord, len(records))
+	for i, record := range records {
+		out[i] = hookRecord{
+			Kind:       record.Kind,
+			Name:       record.Hook,
+			Relation:   record.Relation,
+			RemoteUnit: record.RemoteUnit,
+			Started:    record.Started.UTC().Format(time.RFC3339),
+			Duration:   record.Duration.String(),
+			Result:     record.Result,
+		}
+	}
+	return out
+}
+
+// formatHookHistoryTabular returns the hook records in columns, one
+// record per line.
+func formatHookHistoryTabular(value interface{}) ([]byte, error) {
+	records, ok := value.([]hookRecord)
+	if !ok {
+		return nil, errors.Errorf("expected value of type %T, got %T", records, value)
+	}
+	var out bytes.Buffer
+	tw := tabwriter.NewWriter(&out, 0, 1, 1, ' ', 0)
+	fmt.Fprintln(tw, "STARTED\tKIND\tNAME\tRELATION\tREMOTE-UNIT\tDURATION\tRESULT")
+	for _, r := range records {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", r.Started, r.Kind, r.Name, r.Relation, r.RemoteUnit, r.Duration, r.Result)
+	}
+	tw.Flush()
+	return out.Bytes(), nil
+}
+
+// Run shows the hook history of the unit.
+func (c *ShowHookHistoryCommand) Run(ctx *cmd.Context) error {
+	client, err := c.NewAPIClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	records, err := client.HookHistory(names.NewUnitTag(c.UnitName))
+	if err != nil {
+		return err
+	}
+	return c.out.Write(ctx, formatHookRecords(records))
+}
@@ -0,0 +1,114 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type ShowHookHistorySuite struct {
+	testing.JujuConnSuite
+	unit *state.Unit
+}
+
+var _ = gc.Suite(&ShowHookHistorySuite{})
+
+func (s *ShowHookHistorySuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	svc := s.AddTestingService(c, "dummy", s.AddTestingCharm(c, "dummy"))
+	unit, err := svc.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	s.unit = unit
+
+	started := time.Date(2015, 4, 1, 12, 0, 0, 0, time.UTC)
+	for _, record := range []state.HookRecord{{
+		Kind:     "hook",
+		Hook:     "install",
+		Started:  started,
+		Duration: 90 * time.Second,
+		Result:   "ok",
+	}, {
+		Kind:       "hook",
+		Hook:       "db-relation-changed",
+		Relation:   "db:0",
+		RemoteUnit: "mysql/0",
+		Started:    started.Add(2 * time.Minute),
+		Duration:   2 * time.Second,
+		Result:     "exit status 1",
+	}, {
+		Kind:     "action",
+		Hook:     "snapshot",
+		Started:  started.Add(3 * time.Minute),
+		Duration: 5 * time.Second,
+		Result:   "ok",
+	}} {
+		err := s.unit.AddHookRecord(record)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+}
+
+func runShowHookHistory(c *gc.C, args ...string) (string, error) {
+	ctx, err := coretesting.RunCommand(c, envcmd.Wrap(&ShowHookHistoryCommand{}), args...)
+	if err != nil {
+		return "", err
+	}
+	return coretesting.Stdout(ctx), nil
+}
+
+func (s *ShowHookHistorySuite) TestInit(c *gc.C) {
+	_, err := runShowHookHistory(c)
+	c.Assert(err, gc.ErrorMatches, "no unit specified")
+	_, err = runShowHookHistory(c, "dummy")
+	c.Assert(err, gc.ErrorMatches, `invalid unit name "dummy"`)
+	_, err = runShowHookHistory(c, "dummy/0", "dummy/1")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["dummy/1"\]`)
+}
+
+func (s *ShowHookHistorySuite) TestTabular(c *gc.C) {
+	out, err := runShowHookHistory(c, "dummy/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, gc.Equals, ""+
+		"STARTED              KIND   NAME                RELATION REMOTE-UNIT DURATION RESULT\n"+
+		"2015-04-01T12:03:00Z action snapshot                                 5s       ok\n"+
+		"2015-04-01T12:02:00Z hook   db-relation-changed db:0     mysql/0     2s       exit status 1\n"+
+		"2015-04-01T12:00:00Z hook   install                                  1m30s    ok\n",
+	)
+}
+
+func (s *ShowHookHistorySuite) TestYaml(c *gc.C) {
+	out, err := runShowHookHistory(c, "dummy/0", "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, gc.Equals, ""+
+		"- kind: action\n"+
+		"  name: snapshot\n"+
+		"  started: 2015-04-01T12:03:00Z\n"+
+		"  duration: 5s\n"+
+		"  result: ok\n"+
+		"- kind: hook\n"+
+		"  name: db-relation-changed\n"+
+		"  relation: db:0\n"+
+		"  remote-unit: mysql/0\n"+
+		"  started: 2015-04-01T12:02:00Z\n"+
+		"  duration: 2s\n"+
+		"  result: exit status 1\n"+
+		"- kind: hook\n"+
+		"  name: install\n"+
+		"  started: 2015-04-01T12:00:00Z\n"+
+		"  duration: 1m30s\n"+
+		"  result: ok\n",
+	)
+}
+
+func (s *ShowHookHistorySuite) TestUnknownUnit(c *gc.C) {
+	_, err := runShowHookHistory(c, "dummy/42")
+	c.Assert(err, gc.ErrorMatches, `unit "dummy/42" not found`)
+}
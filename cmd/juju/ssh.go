@@ -14,8 +14,10 @@ import (
 	"github.com/juju/cmd"
 	"github.com/juju/names"
 	"github.com/juju/utils"
+	"gopkg.in/juju/charm.v4"
 	"launchpad.net/gnuflag"
 
+	"github.com/juju/juju/api"
 	"github.com/juju/juju/cmd/envcmd"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/utils/ssh"
@@ -204,6 +206,8 @@ type sshAPIClient interface {
 	PublicAddress(target string) (string, error)
 	PrivateAddress(target string) (string, error)
 	ServiceCharmRelations(service string) ([]string, error)
+	ServiceGetCharmURL(service string) (*charm.URL, error)
+	CharmInfo(charmURL string) (*api.CharmInfo, error)
 	Close() error
 }
 
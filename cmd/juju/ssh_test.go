@@ -156,9 +156,10 @@ func (s *SSHSuite) TestSSHWillWorkInUpgrade(c *gc.C) {
 	for i := 0; i < t.NumMethod(); i++ {
 		name := t.Method(i).Name
 
-		// Close isn't an API method and ServiceCharmRelations is not
-		// relevant to "juju ssh".
-		if name == "Close" || name == "ServiceCharmRelations" {
+		// Close isn't an API method, and the charm queries used by
+		// "juju debug-hooks" are not relevant to "juju ssh".
+		switch name {
+		case "Close", "ServiceCharmRelations", "ServiceGetCharmURL", "CharmInfo":
 			continue
 		}
 		c.Logf("checking %q", name)
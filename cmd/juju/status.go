@@ -149,6 +149,8 @@ type machineStatus struct {
 	Containers     map[string]machineStatus `json:"containers,omitempty" yaml:"containers,omitempty"`
 	Hardware       string                   `json:"hardware,omitempty" yaml:"hardware,omitempty"`
 	HAStatus       string                   `json:"state-server-member-status,omitempty" yaml:"state-server-member-status,omitempty"`
+	Maintenance    bool                     `json:"maintenance,omitempty" yaml:"maintenance,omitempty"`
+	Tags           map[string]string        `json:"tags,omitempty" yaml:"tags,omitempty"`
 }
 
 // A goyaml bug means we can't declare these types
@@ -183,6 +185,8 @@ type serviceStatus struct {
 	Networks      map[string][]string   `json:"networks,omitempty" yaml:"networks,omitempty"`
 	SubordinateTo []string              `json:"subordinate-to,omitempty" yaml:"subordinate-to,omitempty"`
 	Units         map[string]unitStatus `json:"units,omitempty" yaml:"units,omitempty"`
+	Status        string                `json:"service-status,omitempty" yaml:"service-status,omitempty"`
+	StatusInfo    string                `json:"service-status-info,omitempty" yaml:"service-status-info,omitempty"`
 }
 
 type serviceStatusNoMarshal serviceStatus
@@ -208,6 +212,8 @@ type unitStatus struct {
 	Charm          string                `json:"upgrading-from,omitempty" yaml:"upgrading-from,omitempty"`
 	AgentState     params.Status         `json:"agent-state,omitempty" yaml:"agent-state,omitempty"`
 	AgentStateInfo string                `json:"agent-state-info,omitempty" yaml:"agent-state-info,omitempty"`
+	WorkloadStatus string                `json:"workload-status,omitempty" yaml:"workload-status,omitempty"`
+	WorkloadInfo   string                `json:"workload-status-info,omitempty" yaml:"workload-status-info,omitempty"`
 	AgentVersion   string                `json:"agent-version,omitempty" yaml:"agent-version,omitempty"`
 	Life           string                `json:"life,omitempty" yaml:"life,omitempty"`
 	Machine        string                `json:"machine,omitempty" yaml:"machine,omitempty"`
@@ -334,6 +340,8 @@ func (sf *statusFormatter) formatMachine(machine api.MachineStatus) machineStatu
 			Id:             machine.Id,
 			Containers:     make(map[string]machineStatus),
 			Hardware:       machine.Hardware,
+			Maintenance:    machine.Maintenance,
+			Tags:           machine.Tags,
 		}
 	}
 
@@ -371,9 +379,21 @@ func (sf *statusFormatter) formatService(name string, service api.ServiceStatus)
 	for k, m := range service.Units {
 		out.Units[k] = sf.formatUnit(m, name)
 	}
+	out.Status, out.StatusInfo = formatWorkloadStatus(service.Status)
 	return out
 }
 
+// formatWorkloadStatus returns the status and status info to show for
+// the software run by a service or unit. No status is shown until the
+// charm has reported one, so charms that never use status-set don't
+// clutter the output; any error reading it is still shown as info.
+func formatWorkloadStatus(status api.WorkloadStatus) (string, string) {
+	if status.Status == "" || status.Status == params.StatusUnknown {
+		return "", status.Info
+	}
+	return string(status.Status), status.Info
+}
+
 func (sf *statusFormatter) formatUnit(unit api.UnitStatus, serviceName string) unitStatus {
 	out := unitStatus{
 		Err:            unit.Err,
@@ -387,6 +407,7 @@ func (sf *statusFormatter) formatUnit(unit api.UnitStatus, serviceName string) u
 		Charm:          unit.Charm,
 		Subordinates:   make(map[string]unitStatus),
 	}
+	out.WorkloadStatus, out.WorkloadInfo = formatWorkloadStatus(unit.Workload)
 	for k, m := range unit.Subordinates {
 		out.Subordinates[k] = sf.formatUnit(m, serviceName)
 	}
@@ -2103,6 +2103,45 @@ func (a *fakeApiClient) Close() error {
 	return nil
 }
 
+func (s *StatusSuite) TestStatusMachineTags(c *gc.C) {
+	ctx := s.newContext(c)
+	defer s.resetContext(c, ctx)
+	steps := []stepper{
+		addMachine{machineId: "0", job: state.JobManageEnviron},
+		startAliveMachine{"0"},
+		addMachine{machineId: "1", job: state.JobHostUnits},
+		startAliveMachine{"1"},
+		addMachine{machineId: "2", job: state.JobHostUnits},
+	}
+	ctx.run(c, steps)
+	m, err := s.State.Machine("0")
+	c.Assert(err, jc.ErrorIsNil)
+	err = m.SetInstanceTags(map[string]string{
+		"owner":           "finance",
+		"juju-machine-id": "0",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	code, stdout, stderr := runStatus(c, "--format", "yaml")
+	c.Assert(code, gc.Equals, 0)
+	c.Assert(string(stderr), gc.Equals, "")
+	var out struct {
+		Machines map[string]struct {
+			Tags map[string]string
+		}
+	}
+	err = goyaml.Unmarshal(stdout, &out)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out.Machines["0"].Tags, jc.DeepEquals, map[string]string{
+		"owner":           "finance",
+		"juju-machine-id": "0",
+	})
+	// Machine 1's instance has not been tagged by the provider,
+	// and machine 2 has no instance, so neither has tags.
+	c.Assert(out.Machines["1"].Tags, gc.HasLen, 0)
+	c.Assert(out.Machines["2"].Tags, gc.HasLen, 0)
+}
+
 // Check that the client works with an older server which doesn't
 // return the top level Relations field nor the unit and machine level
 // Agent field (they were introduced at the same time).
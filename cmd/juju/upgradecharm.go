@@ -25,6 +25,9 @@ type UpgradeCharmCommand struct {
 	RepoPath    string // defaults to JUJU_REPOSITORY
 	SwitchURL   string
 	Revision    int // defaults to -1 (latest)
+	Rollback    bool
+	BatchSize   int // defaults to 0 (all units at once)
+	WaitHealthy bool
 }
 
 const upgradeCharmDoc = `
@@ -60,6 +63,19 @@ The new charm may add new relations and configuration settings.
 number with --switch, give it in the charm URL, for instance "cs:wordpress-5"
 would specify revision number 5 of the wordpress charm.
 
+The --batch-size flag upgrades the service's existing units a few at a time
+rather than all at once. Each batch of units is allowed to upgrade only once
+the previous batches have done so; with --wait-healthy, those units must also
+have finished running hooks without error. The upgrade pauses while any
+upgraded unit is in an error state, and carries on once the error is resolved.
+Units added while the upgrade is in progress use the new charm straight away.
+
+The --rollback flag sets the service's charm back to the one it was using
+before its most recent upgrade. Units will deploy the previous charm again and
+run the upgrade-charm hook, and the service will use the previous charm's
+config settings. Rolling back twice returns the service to the newer charm.
+--rollback cannot be combined with --switch or --revision.
+
 Use of the --force flag is not generally recommended; units upgraded while in an
 error state will not have upgrade-charm hooks executed, and may cause unexpected
 behavior.
@@ -79,6 +95,9 @@ func (c *UpgradeCharmCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.StringVar(&c.RepoPath, "repository", os.Getenv("JUJU_REPOSITORY"), "local charm repository path")
 	f.StringVar(&c.SwitchURL, "switch", "", "crossgrade to a different charm")
 	f.IntVar(&c.Revision, "revision", -1, "explicit revision of current charm")
+	f.BoolVar(&c.Rollback, "rollback", false, "revert to the charm used before the last upgrade")
+	f.IntVar(&c.BatchSize, "batch-size", 0, "number of units to upgrade at a time")
+	f.BoolVar(&c.WaitHealthy, "wait-healthy", false, "wait for each batch of units to become idle before upgrading the next")
 }
 
 func (c *UpgradeCharmCommand) Init(args []string) error {
@@ -96,6 +115,18 @@ func (c *UpgradeCharmCommand) Init(args []string) error {
 	if c.SwitchURL != "" && c.Revision != -1 {
 		return fmt.Errorf("--switch and --revision are mutually exclusive")
 	}
+	if c.Rollback && (c.SwitchURL != "" || c.Revision != -1) {
+		return fmt.Errorf("--rollback cannot be used with --switch or --revision")
+	}
+	if c.BatchSize < 0 {
+		return fmt.Errorf("--batch-size must not be negative")
+	}
+	if c.WaitHealthy && c.BatchSize == 0 {
+		return fmt.Errorf("--wait-healthy requires --batch-size")
+	}
+	if c.Rollback && c.BatchSize > 0 {
+		return fmt.Errorf("--rollback cannot be used with --batch-size")
+	}
 	return nil
 }
 
@@ -107,6 +138,10 @@ func (c *UpgradeCharmCommand) Run(ctx *cmd.Context) error {
 		return err
 	}
 	defer client.Close()
+	if c.Rollback {
+		err := client.ServiceRollbackCharm(c.ServiceName, c.Force)
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
 	oldURL, err := client.ServiceGetCharmURL(c.ServiceName)
 	if err != nil {
 		return err
@@ -165,5 +200,9 @@ func (c *UpgradeCharmCommand) Run(ctx *cmd.Context) error {
 		return block.ProcessBlockedError(err, block.BlockChange)
 	}
 
+	if c.BatchSize > 0 {
+		err = client.ServiceSetCharmRollout(c.ServiceName, addedURL.String(), c.Force, c.BatchSize, c.WaitHealthy)
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
 	return block.ProcessBlockedError(client.ServiceSetCharm(c.ServiceName, addedURL.String(), c.Force), block.BlockChange)
 }
@@ -92,6 +92,30 @@ func (s *UpgradeCharmErrorsSuite) TestSwitchAndRevisionFails(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "--switch and --revision are mutually exclusive")
 }
 
+func (s *UpgradeCharmErrorsSuite) TestRollbackWithSwitchOrRevisionFails(c *gc.C) {
+	s.deployService(c)
+	err := runUpgradeCharm(c, "riak", "--rollback", "--switch=riak")
+	c.Assert(err, gc.ErrorMatches, "--rollback cannot be used with --switch or --revision")
+	err = runUpgradeCharm(c, "riak", "--rollback", "--revision=2")
+	c.Assert(err, gc.ErrorMatches, "--rollback cannot be used with --switch or --revision")
+}
+
+func (s *UpgradeCharmErrorsSuite) TestInvalidBatchSize(c *gc.C) {
+	s.deployService(c)
+	err := runUpgradeCharm(c, "riak", "--batch-size=-1")
+	c.Assert(err, gc.ErrorMatches, "--batch-size must not be negative")
+	err = runUpgradeCharm(c, "riak", "--wait-healthy")
+	c.Assert(err, gc.ErrorMatches, "--wait-healthy requires --batch-size")
+	err = runUpgradeCharm(c, "riak", "--rollback", "--batch-size=1")
+	c.Assert(err, gc.ErrorMatches, "--rollback cannot be used with --batch-size")
+}
+
+func (s *UpgradeCharmErrorsSuite) TestRollbackWithoutPreviousCharm(c *gc.C) {
+	s.deployService(c)
+	err := runUpgradeCharm(c, "riak", "--rollback")
+	c.Assert(err, gc.ErrorMatches, `cannot roll back charm for service "riak": no previous charm`)
+}
+
 func (s *UpgradeCharmErrorsSuite) TestInvalidRevision(c *gc.C) {
 	s.deployService(c)
 	err := runUpgradeCharm(c, "riak", "--revision=blah")
@@ -222,6 +246,51 @@ func (s *UpgradeCharmSuccessSuite) TestBlockForcedUpgrade(c *gc.C) {
 	s.assertLocalRevision(c, 7, s.path)
 }
 
+func (s *UpgradeCharmSuccessSuite) TestRollingUpgrade(c *gc.C) {
+	for i := 0; i < 2; i++ {
+		_, err := s.riak.AddUnit()
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	err := runUpgradeCharm(c, "riak", "--batch-size=1", "--wait-healthy")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertUpgraded(c, 8, false)
+	rollout, ok := s.riak.Rollout()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(rollout, jc.DeepEquals, state.RolloutStatus{
+		BatchSize:   1,
+		WaitHealthy: true,
+		Upgrading:   []string{"riak/0"},
+	})
+}
+
+func (s *UpgradeCharmSuccessSuite) TestRollback(c *gc.C) {
+	err := runUpgradeCharm(c, "riak")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertUpgraded(c, 8, false)
+
+	err = runUpgradeCharm(c, "riak", "--rollback")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertUpgraded(c, 7, false)
+	c.Assert(s.riak.PreviousCharmURL().Revision, gc.Equals, 8)
+}
+
+func (s *UpgradeCharmSuccessSuite) TestBlockRollback(c *gc.C) {
+	err := runUpgradeCharm(c, "riak")
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Block operation
+	s.AssertConfigParameterUpdated(c, "block-all-changes", true)
+	err = runUpgradeCharm(c, "riak", "--rollback")
+	c.Assert(err, gc.ErrorMatches, cmd.ErrSilent.Error())
+	// msg is logged
+	stripped := strings.Replace(c.GetTestLog(), "\n", "", -1)
+	c.Check(stripped, gc.Matches, ".*To unblock changes.*")
+
+	err = runUpgradeCharm(c, "riak", "--rollback", "--force")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertUpgraded(c, 7, true)
+}
+
 var myriakMeta = []byte(`
 name: myriak
 summary: "K/V storage engine"
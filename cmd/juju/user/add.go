@@ -13,6 +13,7 @@ import (
 	"github.com/juju/names"
 	"launchpad.net/gnuflag"
 
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/juju/block"
 	"github.com/juju/juju/environs/configstore"
 )
@@ -84,7 +85,7 @@ type AddUserAPI interface {
 
 // ShareEnvironmentAPI defines the client API methods that the add command uses.
 type ShareEnvironmentAPI interface {
-	ShareEnvironment(users []names.UserTag) error
+	ShareEnvironment(users []names.UserTag, access params.EnvironAccess) error
 	Close() error
 }
 
@@ -132,7 +133,7 @@ func (c *AddCommand) Run(ctx *cmd.Context) error {
 	// it makes no sense at all to create a user and not have that user
 	// able to log in and use the one and only environment.
 	// So we share the existing environment with the user here and now.
-	err = shareClient.ShareEnvironment([]names.UserTag{tag})
+	err = shareClient.ShareEnvironment([]names.UserTag{tag}, params.AdminEnvironAccess)
 	if err != nil {
 		return err
 	}
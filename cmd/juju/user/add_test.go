@@ -125,6 +125,8 @@ func (s *UserAddCommandSuite) TestAddUserJustUsername(c *gc.C) {
 	c.Assert(s.mockAPI.username, gc.Equals, "foobar")
 	c.Assert(s.mockAPI.displayname, gc.Equals, "")
 	c.Assert(s.mockAPI.password, gc.Equals, "sekrit")
+	c.Assert(s.mockAPI.sharedUsers, jc.DeepEquals, []names.UserTag{names.NewLocalUserTag("foobar")})
+	c.Assert(s.mockAPI.sharedAccess, gc.Equals, params.AdminEnvironAccess)
 	expected := `
 password:
 type password again:
@@ -204,6 +206,7 @@ type mockAddUserAPI struct {
 
 	shareFailMsg string
 	sharedUsers  []names.UserTag
+	sharedAccess params.EnvironAccess
 	blocked      bool
 }
 
@@ -224,11 +227,12 @@ func (m *mockAddUserAPI) AddUser(username, displayname, password string) (names.
 	return names.UserTag{}, errors.New(m.failMessage)
 }
 
-func (m *mockAddUserAPI) ShareEnvironment(users []names.UserTag) error {
+func (m *mockAddUserAPI) ShareEnvironment(users []names.UserTag, access params.EnvironAccess) error {
 	if m.shareFailMsg != "" {
 		return errors.New(m.shareFailMsg)
 	}
 	m.sharedUsers = users
+	m.sharedAccess = access
 	return nil
 }
 
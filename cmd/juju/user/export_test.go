@@ -18,6 +18,10 @@ var (
 	GetConnectionCredentials = &getConnectionCredentials
 	// disable and enable
 	GetDisableUserAPI = &getDisableUserAPI
+	// login
+	OpenLoginAPI         = &openLoginAPI
+	GetLoginInfoWriter   = &getLoginInfoWriter
+	ObtainLoginDischarge = &obtainLoginDischarge
 
 	UserFriendlyDuration = userFriendlyDuration
 )
@@ -25,6 +25,7 @@ Examples:
   	display-name: Foo Bar
   	date-created : 1981-02-27 16:10:05 +0000 UTC
 	last-connection: 2014-01-01 00:00:00 +0000 UTC
+	access: write
 
   	# Show information on a user with the given username
   	$ juju user info jsmith
@@ -79,6 +80,7 @@ type UserInfo struct {
 	DateCreated    string `yaml:"date-created" json:"date-created"`
 	LastConnection string `yaml:"last-connection" json:"last-connection"`
 	Disabled       bool   `yaml:"disabled,omitempty" json:"disabled,omitempty"`
+	Access         string `yaml:"access,omitempty" json:"access,omitempty"`
 }
 
 // Info implements Command.Info.
@@ -147,6 +149,7 @@ func (c *InfoCommandBase) apiUsersToUserInfoSlice(users []params.UserInfo) []Use
 			Username:    info.Username,
 			DisplayName: info.DisplayName,
 			Disabled:    info.Disabled,
+			Access:      string(info.Access),
 		}
 		if c.exactTime {
 			outInfo.DateCreated = info.DateCreated.String()
@@ -57,6 +57,7 @@ func (*fakeUserInfoAPI) UserInfo(usernames []string, all usermanager.IncludeDisa
 	case "foobar":
 		info.Username = "foobar"
 		info.DisplayName = "Foo Bar"
+		info.Access = params.ReadEnvironAccess
 	default:
 		return nil, common.ErrPerm
 	}
@@ -90,6 +91,7 @@ func (s *UserInfoCommandSuite) TestUserInfoWithUsername(c *gc.C) {
 display-name: Foo Bar
 date-created: 1981-02-27
 last-connection: 2014-01-01
+access: read
 `)
 }
 
@@ -110,7 +112,7 @@ func (*UserInfoCommandSuite) TestUserInfoFormatJsonWithUsername(c *gc.C) {
 	context, err := testing.RunCommand(c, newUserInfoCommand(), "foobar", "--format", "json")
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(testing.Stdout(context), gc.Equals, `
-{"user-name":"foobar","display-name":"Foo Bar","date-created":"1981-02-27","last-connection":"2014-01-01"}
+{"user-name":"foobar","display-name":"Foo Bar","date-created":"1981-02-27","last-connection":"2014-01-01","access":"read"}
 `[1:])
 }
 
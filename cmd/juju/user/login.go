@@ -0,0 +1,152 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package user
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/environs/configstore"
+	"github.com/juju/juju/identity"
+)
+
+const userLoginDoc = `
+Log in to the environment as a user authenticated by the external
+identity service trusted by the environment, rather than by juju.
+
+You will be prompted for your identity service password, which is
+sent only to the identity service. The credentials it issues are saved
+for the environment, and used by subsequent commands until they expire,
+when you will need to log in again.
+
+The environment must be configured with the identity-url and
+identity-public-key settings of the identity service, and you, or a
+group you belong to, must have been given access to the environment
+with "juju environment share".
+
+Examples:
+  # Log in as bob, a user of the identity service "sso".
+  juju user login bob@sso
+
+See Also:
+  juju environment share
+`
+
+// LoginCommand logs in to the environment as an externally
+// authenticated user.
+type LoginCommand struct {
+	UserCommandBase
+	User names.UserTag
+}
+
+// Info implements Command.Info.
+func (c *LoginCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "login",
+		Args:    "<username>@<domain>",
+		Purpose: "log in as a user of an external identity service",
+		Doc:     userLoginDoc,
+	}
+}
+
+// Init implements Command.Init.
+func (c *LoginCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no username supplied")
+	}
+	username := args[0]
+	if !names.IsValidUser(username) {
+		return errors.NotValidf("user name %q", username)
+	}
+	c.User = names.NewUserTag(username)
+	if c.User.IsLocal() {
+		return errors.Errorf("%q is a local user; only users of an identity service can log in", username)
+	}
+	return cmd.CheckEmpty(args[1:])
+}
+
+func (c *LoginCommand) openAPI(password string) (io.Closer, error) {
+	endpoint, err := c.ConnectionEndpoint(false)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	info := &api.Info{
+		Addrs:      endpoint.Addresses,
+		CACert:     endpoint.CACert,
+		EnvironTag: names.NewEnvironTag(endpoint.EnvironUUID),
+		Tag:        c.User,
+		Password:   password,
+	}
+	return api.Open(info, api.DefaultDialOpts())
+}
+
+func (c *LoginCommand) getEnvironInfoWriter() (EnvironInfoCredsWriter, error) {
+	return c.ConnectionWriter()
+}
+
+var (
+	openLoginAPI         = (*LoginCommand).openAPI
+	getLoginInfoWriter   = (*LoginCommand).getEnvironInfoWriter
+	obtainLoginDischarge = identity.ObtainDischarge
+)
+
+// Run implements Command.Run.
+func (c *LoginCommand) Run(ctx *cmd.Context) error {
+	// Logging in without credentials gets us a macaroon,
+	// which the identity service must discharge.
+	st, err := openLoginAPI(c, "")
+	if err == nil {
+		st.Close()
+		return errors.New("environment did not ask for credentials")
+	}
+	m, ok := params.DischargeRequiredMacaroon(err)
+	if !ok {
+		return errors.Trace(err)
+	}
+	location, caveatId, err := identity.ThirdPartyCaveat(m)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	fmt.Fprintf(ctx.Stdout, "password for %s at %s:\n", c.User.Name(), location)
+	password, err := readPassword()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	discharge, err := obtainLoginDischarge(location, caveatId, c.User.Name(), password)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	credentials, err := identity.EncodeCredentials(m, discharge)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	// Check that the credentials work before saving them.
+	st, err = openLoginAPI(c, credentials)
+	if err != nil {
+		return errors.Annotate(err, "cannot log in")
+	}
+	st.Close()
+
+	writer, err := getLoginInfoWriter(c)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	writer.SetAPICredentials(configstore.APICredentials{
+		User:     c.User.Username(),
+		Password: credentials,
+	})
+	if err := writer.Write(); err != nil {
+		return errors.Annotate(err, "cannot save credentials")
+	}
+	ctx.Infof("You are now logged in as %s.", c.User.Username())
+	return nil
+}
@@ -0,0 +1,181 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package user_test
+
+import (
+	"io"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/macaroon.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/user"
+	"github.com/juju/juju/environs/configstore"
+	"github.com/juju/juju/identity"
+	identitytesting "github.com/juju/juju/identity/testing"
+	"github.com/juju/juju/testing"
+)
+
+type LoginCommandSuite struct {
+	BaseSuite
+	service         *identitytesting.Service
+	macaroon        *macaroon.Macaroon
+	passwords       []string
+	credentials     []string
+	loginErr        error
+	mockEnvironInfo *mockEnvironInfo
+}
+
+var _ = gc.Suite(&LoginCommandSuite{})
+
+func (s *LoginCommandSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	var err error
+	s.service, err = identitytesting.NewService("sso")
+	c.Assert(err, jc.ErrorIsNil)
+	s.service.AddUser("bob", "sekrit", "devops")
+	key, err := identity.ParsePublicKey(s.service.PublicKey())
+	c.Assert(err, jc.ErrorIsNil)
+	s.macaroon, err = identity.NewMacaroon([]byte("root key"), "env-uuid", "https://sso.invalid", key)
+	c.Assert(err, jc.ErrorIsNil)
+	s.credentials = nil
+	s.passwords = nil
+	s.loginErr = nil
+	s.mockEnvironInfo = &mockEnvironInfo{}
+
+	s.PatchValue(user.OpenLoginAPI, func(c *user.LoginCommand, password string) (io.Closer, error) {
+		if password == "" {
+			return nil, &params.Error{
+				Code:     params.CodeDischargeRequired,
+				Message:  "macaroon discharge required",
+				Macaroon: s.macaroon,
+			}
+		}
+		s.credentials = append(s.credentials, password)
+		if s.loginErr != nil {
+			return nil, s.loginErr
+		}
+		return fakeCloser{}, nil
+	})
+	s.PatchValue(user.ObtainLoginDischarge, func(location, caveatId, name, password string) (*macaroon.Macaroon, error) {
+		c.Check(location, gc.Equals, "https://sso.invalid")
+		s.passwords = append(s.passwords, password)
+		if password != "sekrit" {
+			return nil, errors.New("identity service refused discharge: invalid user name or password")
+		}
+		return s.service.Discharge(name, caveatId)
+	})
+	s.PatchValue(user.GetLoginInfoWriter, func(c *user.LoginCommand) (user.EnvironInfoCredsWriter, error) {
+		return s.mockEnvironInfo, nil
+	})
+}
+
+type fakeCloser struct{}
+
+func (fakeCloser) Close() error {
+	return nil
+}
+
+func newUserLoginCommand() cmd.Command {
+	return envcmd.Wrap(&user.LoginCommand{})
+}
+
+func (s *LoginCommandSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args        []string
+		user        string
+		errorString string
+	}{{
+		errorString: "no username supplied",
+	}, {
+		args: []string{"bob@sso"},
+		user: "bob@sso",
+	}, {
+		args:        []string{"bob"},
+		errorString: `"bob" is a local user; only users of an identity service can log in`,
+	}, {
+		args:        []string{"bob@local"},
+		errorString: `"bob@local" is a local user; only users of an identity service can log in`,
+	}, {
+		args:        []string{"not/valid"},
+		errorString: `user name "not/valid" not valid`,
+	}, {
+		args:        []string{"bob@sso", "extra"},
+		errorString: `unrecognized args: \["extra"\]`,
+	}} {
+		c.Logf("test %d", i)
+		loginCmd := &user.LoginCommand{}
+		err := testing.InitCommand(loginCmd, test.args)
+		if test.errorString == "" {
+			c.Check(err, jc.ErrorIsNil)
+			c.Check(loginCmd.User.Username(), gc.Equals, test.user)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.errorString)
+		}
+	}
+}
+
+func (s *LoginCommandSuite) TestLogin(c *gc.C) {
+	context, err := testing.RunCommand(c, newUserLoginCommand(), "bob@sso")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(context), gc.Equals, "password for bob at https://sso.invalid:\n")
+	c.Assert(testing.Stderr(context), gc.Equals, "You are now logged in as bob@sso.\n")
+	c.Assert(s.passwords, jc.DeepEquals, []string{"sekrit"})
+
+	// The credentials checked are those saved, and they
+	// hold the identity the identity service vouches for.
+	c.Assert(s.credentials, gc.HasLen, 1)
+	c.Assert(s.mockEnvironInfo.creds, jc.DeepEquals, configstore.APICredentials{
+		User:     "bob@sso",
+		Password: s.credentials[0],
+	})
+	id, err := identity.Verify([]byte("root key"), "env-uuid", s.credentials[0])
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(id, jc.DeepEquals, &identity.Identity{
+		User:   "bob@sso",
+		Groups: []string{"devops"},
+	})
+}
+
+func (s *LoginCommandSuite) TestLoginBadPassword(c *gc.C) {
+	s.PatchValue(user.ReadPassword, func() (string, error) {
+		return "wrong", nil
+	})
+	_, err := testing.RunCommand(c, newUserLoginCommand(), "bob@sso")
+	c.Assert(err, gc.ErrorMatches, "identity service refused discharge: invalid user name or password")
+	c.Assert(s.credentials, gc.HasLen, 0)
+	c.Assert(s.mockEnvironInfo.creds, jc.DeepEquals, configstore.APICredentials{})
+}
+
+func (s *LoginCommandSuite) TestLoginRefused(c *gc.C) {
+	s.loginErr = &params.Error{
+		Code:    params.CodeUnauthorized,
+		Message: "invalid entity name or password",
+	}
+	_, err := testing.RunCommand(c, newUserLoginCommand(), "bob@sso")
+	c.Assert(err, gc.ErrorMatches, "cannot log in: invalid entity name or password")
+	c.Assert(s.mockEnvironInfo.creds, jc.DeepEquals, configstore.APICredentials{})
+}
+
+func (s *LoginCommandSuite) TestLoginWithoutIdentityService(c *gc.C) {
+	s.PatchValue(user.OpenLoginAPI, func(c *user.LoginCommand, password string) (io.Closer, error) {
+		return nil, &params.Error{
+			Code:    params.CodeUnauthorized,
+			Message: "invalid entity name or password",
+		}
+	})
+	_, err := testing.RunCommand(c, newUserLoginCommand(), "bob@sso")
+	c.Assert(err, gc.ErrorMatches, "invalid entity name or password")
+	c.Assert(s.passwords, gc.HasLen, 0)
+}
+
+func (s *LoginCommandSuite) TestLoginWriteFails(c *gc.C) {
+	s.mockEnvironInfo.failMessage = "failed to write"
+	_, err := testing.RunCommand(c, newUserLoginCommand(), "bob@sso")
+	c.Assert(err, gc.ErrorMatches, "cannot save credentials: failed to write")
+}
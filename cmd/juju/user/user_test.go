@@ -29,6 +29,7 @@ var expectedUserCommmandNames = []string{
 	"help",
 	"info",
 	"list",
+	"login",
 }
 
 func (s *UserCommandSuite) TestHelp(c *gc.C) {
@@ -34,6 +34,17 @@ var (
 		Total: 1 * time.Minute,
 		Delay: 5 * time.Second,
 	}
+
+	// agentDialOpts holds the options used by agents to connect
+	// to the API. The timeout bounds both the dial and the time
+	// spent backing off, with jitter, when the API server is too
+	// busy to accept the agent, so that many agents reconnecting
+	// at once are spread out rather than retrying in lockstep.
+	agentDialOpts = api.DialOpts{
+		DialAddressInterval: 50 * time.Millisecond,
+		Timeout:             time.Minute,
+		RetryDelay:          2 * time.Second,
+	}
 )
 
 // AgentConf handles command-line flags shared by all agents.
@@ -148,13 +159,12 @@ type configChanger func(c *agent.Config)
 // the given tag. The given changeConfig function is
 // called if the password changes to set the password.
 func OpenAPIState(agentConfig agent.Config, a Agent) (_ *api.State, _ *apiagent.Entity, resultErr error) {
-	// We let the API dial fail immediately because the
-	// runner's loop outside the caller of openAPIState will
-	// keep on retrying. If we block for ages here,
-	// then the worker that's calling this cannot
-	// be interrupted.
+	// We only let the API dial block for a limited time because
+	// the runner's loop outside the caller of openAPIState will
+	// keep on retrying. If we block for ages here, then the
+	// worker that's calling this cannot be interrupted.
 	info := agentConfig.APIInfo()
-	st, err := apiOpen(info, api.DialOpts{})
+	st, err := apiOpen(info, agentDialOpts)
 	usedOldPassword := false
 	if params.IsCodeUnauthorized(err) {
 		// We've perhaps used the wrong password, so
@@ -163,13 +173,13 @@ func OpenAPIState(agentConfig agent.Config, a Agent) (_ *api.State, _ *apiagent.
 		info = &infoCopy
 		info.Password = agentConfig.OldPassword()
 		usedOldPassword = true
-		st, err = apiOpen(info, api.DialOpts{})
+		st, err = apiOpen(info, agentDialOpts)
 	}
 	// The provisioner may take some time to record the agent's
 	// machine instance ID, so wait until it does so.
 	if params.IsCodeNotProvisioned(err) {
 		for a := checkProvisionedStrategy.Start(); a.Next(); {
-			st, err = apiOpen(info, api.DialOpts{})
+			st, err = apiOpen(info, agentDialOpts)
 			if !params.IsCodeNotProvisioned(err) {
 				break
 			}
@@ -226,7 +236,7 @@ func OpenAPIState(agentConfig agent.Config, a Agent) (_ *api.State, _ *apiagent.
 
 		st.Close()
 		info.Password = newPassword
-		st, err = apiOpen(info, api.DialOpts{})
+		st, err = apiOpen(info, agentDialOpts)
 		if err != nil {
 			return nil, nil, err
 		}
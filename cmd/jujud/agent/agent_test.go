@@ -103,6 +103,21 @@ func (s *apiOpenSuite) TestOpenAPIStateWaitsProvisionedGivesUp(c *gc.C) {
 	c.Assert(called, gc.Equals, checkProvisionedStrategy.Min+1)
 }
 
+func (s *apiOpenSuite) TestOpenAPIStateBacksOffWhenBusy(c *gc.C) {
+	var dialOpts []api.DialOpts
+	s.PatchValue(&apiOpen, func(info *api.Info, opts api.DialOpts) (*api.State, error) {
+		dialOpts = append(dialOpts, opts)
+		return nil, &params.Error{Code: params.CodeTryAgain}
+	})
+	_, _, err := OpenAPIState(fakeAPIOpenConfig{}, nil)
+	c.Assert(err, jc.Satisfies, params.IsCodeTryAgain)
+	// A non-zero timeout lets api.Open back off and retry
+	// when the API server is busy, rather than failing at once.
+	c.Assert(dialOpts, gc.HasLen, 1)
+	c.Assert(dialOpts[0], gc.Equals, agentDialOpts)
+	c.Assert(dialOpts[0].Timeout, jc.GreaterThan, time.Duration(0))
+}
+
 type acCreator func() (cmd.Command, *AgentConf)
 
 // CheckAgentCommand is a utility function for verifying that common agent
@@ -38,6 +38,7 @@ import (
 	"github.com/juju/juju/container"
 	"github.com/juju/juju/container/kvm"
 	"github.com/juju/juju/container/lxc"
+	"github.com/juju/juju/container/lxd"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/instance"
@@ -61,6 +62,7 @@ import (
 	"github.com/juju/juju/worker/authenticationworker"
 	"github.com/juju/juju/worker/certupdater"
 	"github.com/juju/juju/worker/charmrevisionworker"
+	"github.com/juju/juju/worker/charmrollout"
 	"github.com/juju/juju/worker/cleaner"
 	"github.com/juju/juju/worker/deployer"
 	"github.com/juju/juju/worker/diskmanager"
@@ -68,6 +70,7 @@ import (
 	"github.com/juju/juju/worker/instancepoller"
 	"github.com/juju/juju/worker/localstorage"
 	workerlogger "github.com/juju/juju/worker/logger"
+	"github.com/juju/juju/worker/machineactions"
 	"github.com/juju/juju/worker/machiner"
 	"github.com/juju/juju/worker/metricworker"
 	"github.com/juju/juju/worker/minunitsworker"
@@ -614,6 +617,17 @@ func (a *MachineAgent) postUpgradeAPIWorker(
 		}
 		return rebootworker.NewReboot(reboot, agentConfig, lock)
 	})
+	runner.StartWorker("machineactions", func() (worker.Worker, error) {
+		facade, err := st.MachineActions()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		lock, err := cmdutil.HookExecutionLock(cmdutil.DataDir)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return machineactions.NewWorker(facade, lock), nil
+	})
 	runner.StartWorker("apiaddressupdater", func() (worker.Worker, error) {
 		return apiaddressupdater.NewAPIAddressUpdater(st.Machiner(), a.apiAddressSetter), nil
 	})
@@ -773,6 +787,10 @@ func (a *MachineAgent) setupContainerSupport(runner worker.Runner, st *api.State
 	if err == nil && supportsKvm {
 		supportedContainers = append(supportedContainers, instance.KVM)
 	}
+
+	if lxd.IsLXDSupported() {
+		supportedContainers = append(supportedContainers, instance.LXD)
+	}
 	return a.updateSupportedContainers(runner, st, entity.Tag(), supportedContainers, agentConfig)
 }
 
@@ -922,6 +940,9 @@ func (a *MachineAgent) StateWorker() (worker.Worker, error) {
 			a.startWorkerAfterUpgrade(singularRunner, "minunitsworker", func() (worker.Worker, error) {
 				return minunitsworker.NewMinUnitsWorker(st), nil
 			})
+			a.startWorkerAfterUpgrade(singularRunner, "charmrollout", func() (worker.Worker, error) {
+				return charmrollout.NewCharmRollout(st), nil
+			})
 		case state.JobManageStateDeprecated:
 			// Legacy environments may set this, but we ignore it.
 		default:
@@ -967,7 +988,7 @@ func (a *MachineAgent) newApiserverWorker(st *state.State, certChanged chan para
 	if err != nil {
 		return nil, err
 	}
-	return apiserver.NewServer(st, listener, apiserver.ServerConfig{
+	serverConfig := apiserver.ServerConfig{
 		Cert:        cert,
 		Key:         key,
 		Tag:         tag,
@@ -975,7 +996,41 @@ func (a *MachineAgent) newApiserverWorker(st *state.State, certChanged chan para
 		LogDir:      logDir,
 		Validator:   a.limitLogins,
 		CertChanged: certChanged,
-	})
+	}
+	if err := setAPIServerLimits(&serverConfig, agentConfig); err != nil {
+		listener.Close()
+		return nil, &cmdutil.FatalError{err.Error()}
+	}
+	return apiserver.NewServer(st, listener, serverConfig)
+}
+
+// setAPIServerLimits sets the limits of the API server from the
+// agent configuration. Limits that are not configured are left
+// zero, so that the server uses its defaults.
+func setAPIServerLimits(serverConfig *apiserver.ServerConfig, agentConfig agent.Config) error {
+	for key, limit := range map[string]*int{
+		agent.APIMaxConcurrentLogins:   &serverConfig.MaxConcurrentLogins,
+		agent.APIMaxConnections:        &serverConfig.MaxConnections,
+		agent.APIMaxConnectionRequests: &serverConfig.MaxConnectionRequests,
+	} {
+		value := agentConfig.Value(key)
+		if value == "" {
+			continue
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return errors.Errorf("invalid %s: %q", key, value)
+		}
+		*limit = n
+	}
+	if value := agentConfig.Value(agent.APIRetryDelay); value != "" {
+		delay, err := time.ParseDuration(value)
+		if err != nil || delay < 0 {
+			return errors.Errorf("invalid %s: %q", agent.APIRetryDelay, value)
+		}
+		serverConfig.RetryDelay = delay
+	}
+	return nil
 }
 
 // limitLogins is called by the API server for each login attempt.
@@ -34,6 +34,7 @@ import (
 	apimetricsmanager "github.com/juju/juju/api/metricsmanager"
 	apinetworker "github.com/juju/juju/api/networker"
 	apirsyslog "github.com/juju/juju/api/rsyslog"
+	"github.com/juju/juju/apiserver"
 	charmtesting "github.com/juju/juju/apiserver/charmrevisionupdater/testing"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cert"
@@ -49,6 +50,7 @@ import (
 	"github.com/juju/juju/mongo"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/provider/dummy"
+	"github.com/juju/juju/service"
 	"github.com/juju/juju/service/upstart"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/watcher"
@@ -130,6 +132,9 @@ func (s *commonMachineSuite) SetUpTest(c *gc.C) {
 	fakeCmd(filepath.Join(testpath, "stop"))
 
 	s.AgentSuite.PatchValue(&upstart.InitDir, c.MkDir())
+	s.AgentSuite.PatchValue(&service.DiscoverInitSystem, func() string {
+		return service.InitSystemUpstart
+	})
 
 	s.singularRecord = &singularRunnerRecord{startedWorkers: make(set.Strings)}
 	s.AgentSuite.PatchValue(&newSingularRunner, s.singularRecord.newSingularRunner)
@@ -493,6 +498,7 @@ func (s *MachineSuite) TestManageEnviron(c *gc.C) {
 
 	c.Assert(s.singularRecord.started(), jc.DeepEquals, []string{
 		"charm-revision-updater",
+		"charmrollout",
 		"cleaner",
 		"environ-provisioner",
 		"firewaller",
@@ -1562,10 +1568,47 @@ func (s *shouldWriteProxyFilesSuite) TestAll(c *gc.C) {
 	}
 }
 
+func (s *MachineSuite) TestSetAPIServerLimits(c *gc.C) {
+	var serverConfig apiserver.ServerConfig
+	err := setAPIServerLimits(&serverConfig, &mockAgentConfig{values: map[string]string{
+		agent.APIMaxConcurrentLogins:   "20",
+		agent.APIMaxConnections:        "1000",
+		agent.APIMaxConnectionRequests: "50",
+		agent.APIRetryDelay:            "10s",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(serverConfig, jc.DeepEquals, apiserver.ServerConfig{
+		MaxConcurrentLogins:   20,
+		MaxConnections:        1000,
+		MaxConnectionRequests: 50,
+		RetryDelay:            10 * time.Second,
+	})
+}
+
+func (s *MachineSuite) TestSetAPIServerLimitsDefaults(c *gc.C) {
+	var serverConfig apiserver.ServerConfig
+	err := setAPIServerLimits(&serverConfig, &mockAgentConfig{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(serverConfig, jc.DeepEquals, apiserver.ServerConfig{})
+}
+
+func (s *MachineSuite) TestSetAPIServerLimitsInvalid(c *gc.C) {
+	for key, value := range map[string]string{
+		agent.APIMaxConnections:        "lots",
+		agent.APIMaxConnectionRequests: "-1",
+		agent.APIRetryDelay:            "5",
+	} {
+		var serverConfig apiserver.ServerConfig
+		err := setAPIServerLimits(&serverConfig, &mockAgentConfig{values: map[string]string{key: value}})
+		c.Check(err, gc.ErrorMatches, fmt.Sprintf("invalid %s: %q", key, value))
+	}
+}
+
 type mockAgentConfig struct {
 	agent.Config
 	providerType string
 	tag          names.Tag
+	values       map[string]string
 }
 
 func (m *mockAgentConfig) Tag() names.Tag {
@@ -1576,7 +1619,7 @@ func (m *mockAgentConfig) Value(key string) string {
 	if key == agent.ProviderType {
 		return m.providerType
 	}
-	return ""
+	return m.values[key]
 }
 
 type singularRunnerRecord struct {
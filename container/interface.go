@@ -4,6 +4,7 @@
 package container
 
 import (
+	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/environs/cloudinit"
 	"github.com/juju/juju/instance"
 )
@@ -41,6 +42,15 @@ type Manager interface {
 	IsInitialized() bool
 }
 
+// LimitsUpdater is implemented by managers that can change the resource
+// limits of the containers they have started.
+type LimitsUpdater interface {
+	// UpdateContainerLimits changes the resource limits of the container
+	// identified by instance id to enforce the given constraints, and
+	// returns the resulting hardware characteristics.
+	UpdateContainerLimits(instance.Id, constraints.Value) (*instance.HardwareCharacteristics, error)
+}
+
 // Initialiser is responsible for performing the steps required to initialise
 // a host machine so it can run containers.
 type Initialiser interface {
@@ -21,6 +21,9 @@ var (
 	PreferFastLXC           = preferFastLXC
 	InitProcessCgroupFile   = &initProcessCgroupFile
 	RuntimeGOOS             = &runtimeGOOS
+	HostCPUCount            = &hostCPUCount
+	SwapAccountingEnabled   = &swapAccountingEnabled
+	SetCgroupValue          = &setCgroupValue
 )
 
 func GetCreateWithCloneValue(mgr container.Manager) bool {
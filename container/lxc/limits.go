@@ -0,0 +1,155 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxc
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/version"
+)
+
+const (
+	// cpuSharesPerCore is the cgroup cpu.shares value that
+	// corresponds to the full use of one core.
+	cpuSharesPerCore = 1024
+
+	// memswLimitFile only exists when the kernel accounts
+	// for the swap used by cgroups.
+	memswLimitFile = "/sys/fs/cgroup/memory/memory.memsw.limit_in_bytes"
+)
+
+var (
+	// hostCPUCount returns the number of cpus the host has.
+	hostCPUCount = runtime.NumCPU
+
+	// swapAccountingEnabled reports whether the host's kernel accounts
+	// for the swap used by cgroups. Without it, lxc cannot set the
+	// memory.memsw limits and the container fails to start.
+	swapAccountingEnabled = func() bool {
+		_, err := os.Stat(memswLimitFile)
+		return err == nil
+	}
+
+	// setCgroupValue changes a cgroup setting of a running container.
+	setCgroupValue = func(name, key, value string) error {
+		out, err := exec.Command("lxc-cgroup", "-n", name, key, value).CombinedOutput()
+		if err != nil {
+			return errors.Annotatef(err, "lxc-cgroup failed: %s", strings.TrimSpace(string(out)))
+		}
+		return nil
+	}
+)
+
+// cgroupLimit is a cgroup setting that limits the resources
+// a container can use.
+type cgroupLimit struct {
+	// key is the name of the cgroup setting,
+	// such as "memory.limit_in_bytes".
+	key string
+
+	// value holds the enforced limit, and is empty
+	// if the resource is not limited.
+	value string
+
+	// unlimited is the value that removes the limit
+	// from a running container.
+	unlimited string
+}
+
+// cgroupLimits returns the cgroup settings that enforce the given
+// constraints, along with the hardware characteristics that a
+// container limited by them will have.
+//
+// Memory is limited to the mem constraint, and the container may
+// swap out as much again if the host accounts for swap usage. The
+// container's share of cpu time is taken from cpu-power if given,
+// or else from cpu-cores. Containers are not pinned to particular
+// cpus, so that busy containers spread over all the host's cpus
+// and only compete for cpu time in proportion to their shares.
+func cgroupLimits(cons constraints.Value) ([]cgroupLimit, *instance.HardwareCharacteristics) {
+	arch := version.Current.Arch
+	hardware := &instance.HardwareCharacteristics{
+		Arch: &arch,
+	}
+	memory := cgroupLimit{key: "memory.limit_in_bytes", unlimited: "-1"}
+	memsw := cgroupLimit{key: "memory.memsw.limit_in_bytes", unlimited: "-1"}
+	shares := cgroupLimit{key: "cpu.shares", unlimited: fmt.Sprint(cpuSharesPerCore)}
+
+	if cons.Mem != nil && *cons.Mem > 0 {
+		mem := *cons.Mem
+		memory.value = fmt.Sprintf("%dM", mem)
+		memsw.value = fmt.Sprintf("%dM", 2*mem)
+		hardware.Mem = &mem
+	}
+	if cons.CpuCores != nil && *cons.CpuCores > 0 {
+		cores := *cons.CpuCores
+		if hostCPUs := hostCPUCount(); cores > uint64(hostCPUs) {
+			logger.Warningf("cpu-cores constraint of %d exceeds the %d cpus of the host", cores, hostCPUs)
+			cores = uint64(hostCPUs)
+		}
+		shares.value = fmt.Sprint(cores * cpuSharesPerCore)
+		hardware.CpuCores = &cores
+	}
+	if cons.CpuPower != nil && *cons.CpuPower > 0 {
+		power := *cons.CpuPower
+		shares.value = fmt.Sprint(power * cpuSharesPerCore / 100)
+		hardware.CpuPower = &power
+	}
+	if !swapAccountingEnabled() {
+		return []cgroupLimit{memory, shares}, hardware
+	}
+	return []cgroupLimit{memory, memsw, shares}, hardware
+}
+
+// cgroupLimitsConfig returns the container config that sets the given
+// limits, in the form accepted by updateContainerConfig. Limits that
+// are not enforced have empty values, so they will be removed from
+// the config if found.
+func cgroupLimitsConfig(limits []cgroupLimit) string {
+	var lines []string
+	for _, limit := range limits {
+		lines = append(lines, fmt.Sprintf("lxc.cgroup.%s = %s", limit.key, limit.value))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// UpdateContainerLimits implements container.LimitsUpdater. The new
+// limits are saved in the container's config, and are also applied
+// at once if the container is running.
+func (manager *containerManager) UpdateContainerLimits(id instance.Id, cons constraints.Value) (*instance.HardwareCharacteristics, error) {
+	name := string(id)
+	limits, hardware := cgroupLimits(cons)
+	if err := updateContainerConfig(name, cgroupLimitsConfig(limits)); err != nil {
+		return nil, errors.Annotate(err, "failed to update resource limits")
+	}
+	if !LxcObjectFactory.New(name).IsRunning() {
+		return hardware, nil
+	}
+	if swapAccountingEnabled() {
+		// The memory limit cannot be raised above the memory and swap
+		// limit, so that is lifted first, and set again after it.
+		if err := setCgroupValue(name, "memory.memsw.limit_in_bytes", "-1"); err != nil {
+			return nil, errors.Annotatef(err, "failed to lift memory.memsw.limit_in_bytes of container %q", name)
+		}
+	}
+	for _, limit := range limits {
+		value := limit.value
+		if value == "" {
+			value = limit.unlimited
+		}
+		if err := setCgroupValue(name, limit.key, value); err != nil {
+			return nil, errors.Annotatef(err, "failed to set %s of container %q", limit.key, name)
+		}
+	}
+	logger.Tracef("updated resource limits of running container %q", name)
+	return hardware, nil
+}
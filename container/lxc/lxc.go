@@ -120,8 +120,11 @@ type containerManager struct {
 	imageURLGetter    container.ImageURLGetter
 }
 
-// containerManager implements container.Manager.
-var _ container.Manager = (*containerManager)(nil)
+// containerManager implements container.Manager and container.LimitsUpdater.
+var (
+	_ container.Manager       = (*containerManager)(nil)
+	_ container.LimitsUpdater = (*containerManager)(nil)
+)
 
 // NewContainerManager returns a manager object that can start and
 // stop lxc containers. The containers that are created are namespaced
@@ -331,6 +334,12 @@ func (manager *containerManager) CreateContainer(
 	if _, err := reorderNetworkConfig(configPath); err != nil {
 		return nil, nil, errors.Annotate(err, "failed to reorder network settings")
 	}
+	// Enforce the machine's constraints with cgroup limits.
+	limits, hardware := cgroupLimits(machineConfig.Constraints)
+	if err := updateContainerConfig(name, cgroupLimitsConfig(limits)); err != nil {
+		return nil, nil, errors.Annotate(err, "failed to set resource limits")
+	}
+	logger.Tracef("set resource limits in %q for container %q: %v", configPath, name, hardware)
 
 	// Start the lxc container with the appropriate settings for grabbing the
 	// console output and a log file.
@@ -361,10 +370,6 @@ func (manager *containerManager) CreateContainer(
 		return nil, nil, errors.Annotate(err, "container failed to start")
 	}
 
-	hardware := &instance.HardwareCharacteristics{
-		Arch: &version.Current.Arch,
-	}
-
 	return &lxcInstance{lxcContainer, name}, hardware, nil
 }
 
@@ -27,6 +27,7 @@ import (
 	"launchpad.net/golxc"
 
 	"github.com/juju/juju/agent"
+	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/container"
 	"github.com/juju/juju/container/lxc"
 	"github.com/juju/juju/container/lxc/mock"
@@ -934,6 +935,97 @@ lxc.mount.entry = /var/log/juju var/log/juju none defaults,bind 0 0
 	c.Assert(autostartLink, jc.DoesNotExist)
 }
 
+func (s *LxcSuite) createContainerWithConstraints(c *gc.C, manager container.Manager, cons string) (instance.Instance, *instance.HardwareCharacteristics) {
+	machineConfig, err := containertesting.MockMachineConfig("1/lxc/0")
+	c.Assert(err, jc.ErrorIsNil)
+	envConfig, err := config.New(config.NoDefaults, dummy.SampleConfig())
+	c.Assert(err, jc.ErrorIsNil)
+	machineConfig.Config = envConfig
+	machineConfig.Constraints = constraints.MustParse(cons)
+	network := container.BridgeNetworkConfig("nic42", nil)
+	inst, hardware, err := manager.CreateContainer(machineConfig, "quantal", network)
+	c.Assert(err, jc.ErrorIsNil)
+	return inst, hardware
+}
+
+func (s *LxcSuite) TestCreateContainerEnforcesConstraints(c *gc.C) {
+	s.PatchValue(lxc.HostCPUCount, func() int { return 4 })
+	s.PatchValue(lxc.SwapAccountingEnabled, func() bool { return true })
+	manager := s.makeManager(c, "test")
+	inst, hardware := s.createContainerWithConstraints(c, manager, "mem=2G cpu-cores=2")
+
+	config, err := ioutil.ReadFile(lxc.ContainerConfigFilename(string(inst.Id())))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(config), jc.Contains, `
+lxc.cgroup.memory.limit_in_bytes = 2048M
+lxc.cgroup.memory.memsw.limit_in_bytes = 4096M
+lxc.cgroup.cpu.shares = 2048
+`)
+	// Containers are not pinned to cpus, so they spread over the host.
+	c.Assert(string(config), gc.Not(jc.Contains), "lxc.cgroup.cpuset")
+	c.Assert(*hardware.Mem, gc.Equals, uint64(2048))
+	c.Assert(*hardware.CpuCores, gc.Equals, uint64(2))
+}
+
+func (s *LxcSuite) TestCreateContainerLimitsCpuPower(c *gc.C) {
+	s.PatchValue(lxc.HostCPUCount, func() int { return 4 })
+	manager := s.makeManager(c, "test")
+	inst, hardware := s.createContainerWithConstraints(c, manager, "cpu-cores=8 cpu-power=50")
+
+	config, err := ioutil.ReadFile(lxc.ContainerConfigFilename(string(inst.Id())))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(config), jc.Contains, "lxc.cgroup.cpu.shares = 512\n")
+	c.Assert(*hardware.CpuCores, gc.Equals, uint64(4))
+	c.Assert(*hardware.CpuPower, gc.Equals, uint64(50))
+	c.Assert(hardware.Mem, gc.IsNil)
+}
+
+func (s *LxcSuite) TestCreateContainerWithoutSwapAccounting(c *gc.C) {
+	s.PatchValue(lxc.SwapAccountingEnabled, func() bool { return false })
+	manager := s.makeManager(c, "test")
+	inst, hardware := s.createContainerWithConstraints(c, manager, "mem=2G")
+
+	config, err := ioutil.ReadFile(lxc.ContainerConfigFilename(string(inst.Id())))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(config), jc.Contains, "lxc.cgroup.memory.limit_in_bytes = 2048M\n")
+	c.Assert(string(config), gc.Not(jc.Contains), "lxc.cgroup.memory.memsw")
+	c.Assert(*hardware.Mem, gc.Equals, uint64(2048))
+}
+
+func (s *LxcSuite) TestUpdateContainerLimits(c *gc.C) {
+	s.PatchValue(lxc.SwapAccountingEnabled, func() bool { return true })
+	var set []string
+	s.PatchValue(lxc.SetCgroupValue, func(name, key, value string) error {
+		set = append(set, fmt.Sprintf("%s %s=%s", name, key, value))
+		return nil
+	})
+	manager := s.makeManager(c, "test")
+	inst, _ := s.createContainerWithConstraints(c, manager, "mem=2G cpu-cores=2")
+
+	updater, ok := manager.(container.LimitsUpdater)
+	c.Assert(ok, jc.IsTrue)
+	hardware, err := updater.UpdateContainerLimits(inst.Id(), constraints.MustParse("mem=1G"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(*hardware.Mem, gc.Equals, uint64(1024))
+	c.Assert(hardware.CpuCores, gc.IsNil)
+
+	config, err := ioutil.ReadFile(lxc.ContainerConfigFilename(string(inst.Id())))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(config), jc.Contains, `
+lxc.cgroup.memory.limit_in_bytes = 1024M
+lxc.cgroup.memory.memsw.limit_in_bytes = 2048M
+`)
+	c.Assert(string(config), gc.Not(jc.Contains), "lxc.cgroup.cpu")
+
+	// The container is running, so the limits are changed at once.
+	c.Assert(set, gc.DeepEquals, []string{
+		"test-machine-1-lxc-0 memory.memsw.limit_in_bytes=-1",
+		"test-machine-1-lxc-0 memory.limit_in_bytes=1024M",
+		"test-machine-1-lxc-0 memory.memsw.limit_in_bytes=2048M",
+		"test-machine-1-lxc-0 cpu.shares=1024",
+	})
+}
+
 func (s *LxcSuite) TestDestroyContainerRemovesAutostartLink(c *gc.C) {
 	manager := s.makeManager(c, "test")
 	instance := containertesting.CreateContainer(c, manager, "1/lxc/0")
@@ -0,0 +1,331 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+// DefaultSocketPath is where the local LXD daemon listens for
+// REST API requests.
+var DefaultSocketPath = "/var/lib/lxd/unix.socket"
+
+// DefaultImageServer is the simplestreams server from which the
+// images that containers are created from are imported, when the
+// daemon has not cached them already.
+var DefaultImageServer = "https://cloud-images.ubuntu.com/releases"
+
+// apiVersion prefixes all the paths of the LXD REST API.
+const apiVersion = "/1.0"
+
+// Container states reported by the LXD daemon.
+const (
+	StatusRunning = "Running"
+	StatusStopped = "Stopped"
+)
+
+// Client talks to an LXD daemon through its REST API.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewClient returns a Client that sends its requests to the LXD
+// daemon at baseURL using the given http.Client.
+func NewClient(baseURL string, httpClient *http.Client) *Client {
+	return &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		httpClient: httpClient,
+	}
+}
+
+// NewUnixSocketClient returns a Client that talks to the LXD daemon
+// listening on the given unix socket.
+func NewUnixSocketClient(socketPath string) *Client {
+	transport := &http.Transport{
+		Dial: func(_, _ string) (net.Conn, error) {
+			return net.Dial("unix", socketPath)
+		},
+	}
+	// The host is ignored, as every connection uses the socket.
+	return NewClient("http://lxd", &http.Client{Transport: transport})
+}
+
+// LocalClient returns a Client for the LXD daemon on this machine.
+// It is a variable so tests can substitute a fake daemon.
+var LocalClient = func() *Client {
+	return NewUnixSocketClient(DefaultSocketPath)
+}
+
+// ContainerSpec holds the details needed to create a container.
+type ContainerSpec struct {
+	// Name is the name of the container.
+	Name string
+
+	// Image is the alias of the cached image the container is
+	// created from.
+	Image string
+
+	// Config holds LXD configuration keys for the container,
+	// such as resource limits and cloud-init user data.
+	Config map[string]string
+
+	// Devices holds the devices, such as network interfaces,
+	// given to the container, keyed by device name.
+	Devices map[string]map[string]string
+}
+
+// response is the envelope of every LXD REST API response.
+type response struct {
+	Type       string          `json:"type"`
+	Status     string          `json:"status"`
+	StatusCode int             `json:"status_code"`
+	Operation  string          `json:"operation"`
+	Error      string          `json:"error"`
+	ErrorCode  int             `json:"error_code"`
+	Metadata   json.RawMessage `json:"metadata"`
+}
+
+// operation describes the outcome of a background operation.
+type operation struct {
+	Status     string          `json:"status"`
+	StatusCode int             `json:"status_code"`
+	Err        string          `json:"err"`
+	Metadata   json.RawMessage `json:"metadata"`
+}
+
+type containerSource struct {
+	Type  string `json:"type"`
+	Alias string `json:"alias"`
+}
+
+type imageSource struct {
+	Type     string `json:"type"`
+	Mode     string `json:"mode"`
+	Server   string `json:"server"`
+	Protocol string `json:"protocol"`
+	Alias    string `json:"alias"`
+}
+
+type importImageRequest struct {
+	Source imageSource `json:"source"`
+}
+
+type importImageResult struct {
+	Fingerprint string `json:"fingerprint"`
+}
+
+type createAliasRequest struct {
+	Name   string `json:"name"`
+	Target string `json:"target"`
+}
+
+type createContainerRequest struct {
+	Name     string                       `json:"name"`
+	Source   containerSource              `json:"source"`
+	Config   map[string]string            `json:"config,omitempty"`
+	Devices  map[string]map[string]string `json:"devices,omitempty"`
+	Profiles []string                     `json:"profiles"`
+}
+
+type containerStateRequest struct {
+	Action  string `json:"action"`
+	Timeout int    `json:"timeout"`
+	Force   bool   `json:"force"`
+}
+
+type containerState struct {
+	Status     string `json:"status"`
+	StatusCode int    `json:"status_code"`
+}
+
+// HasImage reports whether an image with the given alias
+// is cached by the daemon.
+func (c *Client) HasImage(alias string) (bool, error) {
+	err := c.call("GET", "/images/aliases/"+alias, nil, nil)
+	if errors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, errors.Annotatef(err, "cannot get image %q", alias)
+	}
+	return true, nil
+}
+
+// ImportImage has the daemon download the image with the given
+// remote alias from the simplestreams server at the given URL,
+// and caches it under the given local alias.
+func (c *Client) ImportImage(server, remoteAlias, alias string) error {
+	req := importImageRequest{
+		Source: imageSource{
+			Type:     "image",
+			Mode:     "pull",
+			Server:   server,
+			Protocol: "simplestreams",
+			Alias:    remoteAlias,
+		},
+	}
+	var result importImageResult
+	if err := c.call("POST", "/images", req, &result); err != nil {
+		return errors.Annotatef(err, "cannot import image %q from %s", remoteAlias, server)
+	}
+	if result.Fingerprint == "" {
+		return errors.Errorf("cannot import image %q from %s: no fingerprint returned", remoteAlias, server)
+	}
+	aliasReq := createAliasRequest{
+		Name:   alias,
+		Target: result.Fingerprint,
+	}
+	if err := c.call("POST", "/images/aliases", aliasReq, nil); err != nil {
+		return errors.Annotatef(err, "cannot create image alias %q", alias)
+	}
+	return nil
+}
+
+// CreateContainer creates, but does not start, a container
+// as described by spec.
+func (c *Client) CreateContainer(spec ContainerSpec) error {
+	req := createContainerRequest{
+		Name: spec.Name,
+		Source: containerSource{
+			Type:  "image",
+			Alias: spec.Image,
+		},
+		Config:   spec.Config,
+		Devices:  spec.Devices,
+		Profiles: []string{"default"},
+	}
+	if err := c.call("POST", "/containers", req, nil); err != nil {
+		return errors.Annotatef(err, "cannot create container %q", spec.Name)
+	}
+	return nil
+}
+
+// StartContainer starts the named container.
+func (c *Client) StartContainer(name string) error {
+	return c.setState(name, "start", false)
+}
+
+// StopContainer stops the named container, killing it if it
+// does not shut down in time.
+func (c *Client) StopContainer(name string) error {
+	return c.setState(name, "stop", true)
+}
+
+func (c *Client) setState(name, action string, force bool) error {
+	req := containerStateRequest{
+		Action:  action,
+		Timeout: 30,
+		Force:   force,
+	}
+	if err := c.call("PUT", "/containers/"+name+"/state", req, nil); err != nil {
+		return errors.Annotatef(err, "cannot %s container %q", action, name)
+	}
+	return nil
+}
+
+// DeleteContainer removes the named container, which must be stopped.
+func (c *Client) DeleteContainer(name string) error {
+	if err := c.call("DELETE", "/containers/"+name, nil, nil); err != nil {
+		return errors.Annotatef(err, "cannot delete container %q", name)
+	}
+	return nil
+}
+
+// ListContainers returns the names of all the daemon's containers.
+func (c *Client) ListContainers() ([]string, error) {
+	var urls []string
+	if err := c.call("GET", "/containers", nil, &urls); err != nil {
+		return nil, errors.Annotate(err, "cannot list containers")
+	}
+	names := make([]string, len(urls))
+	for i, url := range urls {
+		names[i] = url[strings.LastIndex(url, "/")+1:]
+	}
+	return names, nil
+}
+
+// ContainerStatus returns the current state of the named
+// container, such as StatusRunning.
+func (c *Client) ContainerStatus(name string) (string, error) {
+	var state containerState
+	if err := c.call("GET", "/containers/"+name+"/state", nil, &state); err != nil {
+		return "", errors.Annotatef(err, "cannot get state of container %q", name)
+	}
+	return state.Status, nil
+}
+
+// call sends a request to the daemon, waiting for any background
+// operation it starts to complete, and unmarshals the metadata of
+// the response, or of the operation, into result, if result is
+// not nil.
+func (c *Client) call(method, path string, body, result interface{}) error {
+	resp, err := c.send(method, apiVersion+path, body)
+	if err != nil {
+		return err
+	}
+	if resp.Type == "async" {
+		if resp, err = c.send("GET", resp.Operation+"/wait", nil); err != nil {
+			return err
+		}
+		var op operation
+		if err := json.Unmarshal(resp.Metadata, &op); err != nil {
+			return errors.Annotate(err, "cannot decode operation")
+		}
+		if op.Status != "Success" {
+			return errors.Errorf("operation %s: %s", strings.ToLower(op.Status), op.Err)
+		}
+		if result != nil && len(op.Metadata) > 0 {
+			if err := json.Unmarshal(op.Metadata, result); err != nil {
+				return errors.Annotate(err, "cannot decode operation metadata")
+			}
+		}
+		return nil
+	}
+	if result != nil {
+		if err := json.Unmarshal(resp.Metadata, result); err != nil {
+			return errors.Annotate(err, "cannot decode response")
+		}
+	}
+	return nil
+}
+
+func (c *Client) send(method, path string, body interface{}) (*response, error) {
+	var data []byte
+	if body != nil {
+		var err error
+		if data, err = json.Marshal(body); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	req, err := http.NewRequest(method, c.baseURL+path, bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	httpResp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot connect to LXD")
+	}
+	defer httpResp.Body.Close()
+	var resp response
+	if err := json.NewDecoder(httpResp.Body).Decode(&resp); err != nil {
+		return nil, errors.Annotatef(err, "cannot decode %s %s response", method, path)
+	}
+	if resp.Type == "error" {
+		if resp.ErrorCode == http.StatusNotFound {
+			return nil, errors.NotFoundf(strings.TrimPrefix(path, apiVersion+"/"))
+		}
+		return nil, fmt.Errorf("lxd: %s", resp.Error)
+	}
+	return &resp, nil
+}
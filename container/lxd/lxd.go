@@ -0,0 +1,218 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxd
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/container"
+	"github.com/juju/juju/environs/cloudinit"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/version"
+)
+
+var logger = loggo.GetLogger("juju.container.lxd")
+
+// DefaultLxdBridge is the bridge that containers are connected to
+// when no other bridge is configured; LXD shares it with LXC.
+const DefaultLxdBridge = "lxcbr0"
+
+// IsLXDSupported reports whether the LXD daemon is installed
+// on this machine. It is a variable to allow us to override
+// behaviour in the tests.
+var IsLXDSupported = func() bool {
+	_, err := exec.LookPath("lxd")
+	return err == nil
+}
+
+// ImageAlias returns the alias of the cached image that
+// containers running the given series are created from.
+// The image is imported from DefaultImageServer if the
+// daemon has not cached it already.
+func ImageAlias(series string) string {
+	return "ubuntu-" + series
+}
+
+// NewContainerManager returns a manager object that can start and stop
+// containers through the local LXD daemon. The containers that are
+// created are namespaced by the name parameter.
+func NewContainerManager(conf container.ManagerConfig) (container.Manager, error) {
+	name := conf.PopValue(container.ConfigName)
+	if name == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+	conf.WarnAboutUnused()
+	return &containerManager{name: name, client: LocalClient()}, nil
+}
+
+// containerManager handles all of the business logic at the juju specific
+// level, leaving the daemon to look after the containers themselves.
+type containerManager struct {
+	name   string
+	client *Client
+}
+
+var _ container.Manager = (*containerManager)(nil)
+
+func (manager *containerManager) CreateContainer(
+	machineConfig *cloudinit.MachineConfig,
+	series string,
+	network *container.NetworkConfig,
+) (instance.Instance, *instance.HardwareCharacteristics, error) {
+
+	name := names.NewMachineTag(machineConfig.MachineId).String()
+	if manager.name != "" {
+		name = fmt.Sprintf("%s-%s", manager.name, name)
+	}
+
+	image := ImageAlias(series)
+	cached, err := manager.client.HasImage(image)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	if !cached {
+		// The daemon is only ever asked for the images of the series
+		// juju runs, so they are imported the first time they are needed.
+		logger.Infof("importing %s image %q from %s", series, image, DefaultImageServer)
+		if err := manager.client.ImportImage(DefaultImageServer, series, image); err != nil {
+			return nil, nil, errors.Annotate(err, "lxd container creation failed")
+		}
+	}
+
+	logger.Tracef("create the cloud-init user data")
+	userData, err := container.CloudInitUserData(machineConfig)
+	if err != nil {
+		return nil, nil, errors.Annotate(err, "failed to create user data")
+	}
+	config := limitsConfig(machineConfig.Constraints)
+	config["user.user-data"] = string(userData)
+
+	logger.Tracef("create the container, constraints: %v", machineConfig.Constraints)
+	spec := ContainerSpec{
+		Name:    name,
+		Image:   image,
+		Config:  config,
+		Devices: networkDevices(network),
+	}
+	if err := manager.client.CreateContainer(spec); err != nil {
+		return nil, nil, errors.Annotate(err, "lxd container creation failed")
+	}
+	if err := manager.client.StartContainer(name); err != nil {
+		if err := manager.client.DeleteContainer(name); err != nil {
+			logger.Errorf("cannot remove container after failing to start it: %v", err)
+		}
+		return nil, nil, errors.Annotate(err, "lxd container creation failed")
+	}
+	logger.Tracef("lxd container created")
+	return &lxdInstance{name, manager.client}, hardware(machineConfig.Constraints), nil
+}
+
+func (manager *containerManager) IsInitialized() bool {
+	return IsLXDSupported()
+}
+
+func (manager *containerManager) DestroyContainer(id instance.Id) error {
+	name := string(id)
+	status, err := manager.client.ContainerStatus(name)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if status != StatusStopped {
+		if err := manager.client.StopContainer(name); err != nil {
+			logger.Errorf("failed to stop lxd container: %v", err)
+			return err
+		}
+	}
+	return manager.client.DeleteContainer(name)
+}
+
+func (manager *containerManager) ListContainers() (result []instance.Instance, err error) {
+	containers, err := manager.client.ListContainers()
+	if err != nil {
+		logger.Errorf("failed getting all instances: %v", err)
+		return nil, err
+	}
+	managerPrefix := fmt.Sprintf("%s-", manager.name)
+	for _, name := range containers {
+		// Filter out those not starting with our name.
+		if !strings.HasPrefix(name, managerPrefix) {
+			continue
+		}
+		status, err := manager.client.ContainerStatus(name)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if status == StatusRunning {
+			result = append(result, &lxdInstance{name, manager.client})
+		}
+	}
+	return result, nil
+}
+
+// limitsConfig returns the LXD configuration that limits a container
+// to the resources given by the constraints. Constraints that LXD
+// cannot apply cause a message to be logged.
+func limitsConfig(cons constraints.Value) map[string]string {
+	config := make(map[string]string)
+	if cons.CpuCores != nil {
+		config["limits.cpu"] = fmt.Sprint(*cons.CpuCores)
+	}
+	if cons.Mem != nil {
+		config["limits.memory"] = fmt.Sprintf("%dMB", *cons.Mem)
+	}
+	if cons.Arch != nil {
+		logger.Infof("arch constraint of %q being ignored as not supported", *cons.Arch)
+	}
+	if cons.Container != nil {
+		logger.Infof("container constraint of %q being ignored as not supported", *cons.Container)
+	}
+	if cons.CpuPower != nil {
+		logger.Infof("cpu-power constraint of %v being ignored as not supported", *cons.CpuPower)
+	}
+	if cons.RootDisk != nil {
+		logger.Infof("root-disk constraint of %v being ignored as not supported", *cons.RootDisk)
+	}
+	if cons.Tags != nil {
+		logger.Infof("tags constraint of %q being ignored as not supported", strings.Join(*cons.Tags, ","))
+	}
+	return config
+}
+
+// hardware returns the characteristics of a container
+// started with the given constraints.
+func hardware(cons constraints.Value) *instance.HardwareCharacteristics {
+	arch := version.Current.Arch
+	return &instance.HardwareCharacteristics{
+		Arch:     &arch,
+		Mem:      cons.Mem,
+		CpuCores: cons.CpuCores,
+	}
+}
+
+// networkDevices returns the LXD devices that connect a container
+// to the given network, or nil if the daemon's default profile
+// should be used.
+func networkDevices(network *container.NetworkConfig) map[string]map[string]string {
+	if network == nil || network.Device == "" {
+		return nil
+	}
+	nicType := "bridged"
+	if network.NetworkType == container.PhysicalNetwork {
+		nicType = "physical"
+	}
+	return map[string]map[string]string{
+		"eth0": {
+			"type":    "nic",
+			"nictype": nicType,
+			"parent":  network.Device,
+		},
+	}
+}
@@ -0,0 +1,175 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxd_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/container"
+	"github.com/juju/juju/container/lxd"
+	"github.com/juju/juju/container/lxd/mock"
+	lxdtesting "github.com/juju/juju/container/lxd/testing"
+	containertesting "github.com/juju/juju/container/testing"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/provider/dummy"
+)
+
+type LXDSuite struct {
+	lxdtesting.TestSuite
+	manager container.Manager
+}
+
+var _ = gc.Suite(&LXDSuite{})
+
+func (s *LXDSuite) SetUpTest(c *gc.C) {
+	s.TestSuite.SetUpTest(c)
+	var err error
+	s.manager, err = lxd.NewContainerManager(container.ManagerConfig{container.ConfigName: "test"})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (*LXDSuite) TestManagerNameNeeded(c *gc.C) {
+	manager, err := lxd.NewContainerManager(container.ManagerConfig{container.ConfigName: ""})
+	c.Assert(err, gc.ErrorMatches, "name is required")
+	c.Assert(manager, gc.IsNil)
+}
+
+func (s *LXDSuite) TestListInitiallyEmpty(c *gc.C) {
+	containers, err := s.manager.ListContainers()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(containers, gc.HasLen, 0)
+}
+
+func (s *LXDSuite) createContainer(c *gc.C, name string, start bool) {
+	client := s.Server.Client()
+	err := client.CreateContainer(lxd.ContainerSpec{
+		Name:  name,
+		Image: lxd.ImageAlias("quantal"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	if start {
+		c.Assert(client.StartContainer(name), jc.ErrorIsNil)
+	}
+}
+
+func (s *LXDSuite) TestListMatchesManagerName(c *gc.C) {
+	s.createContainer(c, "test-match1", true)
+	s.createContainer(c, "test-match2", true)
+	s.createContainer(c, "testNoMatch", true)
+	s.createContainer(c, "other", true)
+	containers, err := s.manager.ListContainers()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(containers, gc.HasLen, 2)
+	expectedIds := []instance.Id{"test-match1", "test-match2"}
+	ids := []instance.Id{containers[0].Id(), containers[1].Id()}
+	c.Assert(ids, jc.SameContents, expectedIds)
+}
+
+func (s *LXDSuite) TestListMatchesRunningContainers(c *gc.C) {
+	s.createContainer(c, "test-running", true)
+	s.createContainer(c, "test-stopped", false)
+	containers, err := s.manager.ListContainers()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(containers, gc.HasLen, 1)
+	c.Assert(string(containers[0].Id()), gc.Equals, "test-running")
+}
+
+func (s *LXDSuite) TestCreateContainer(c *gc.C) {
+	inst := containertesting.CreateContainer(c, s.manager, "1/lxd/0")
+	c.Assert(inst.Id(), gc.Equals, instance.Id("test-machine-1-lxd-0"))
+
+	ctr, ok := s.Server.Container("test-machine-1-lxd-0")
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(ctr.Image, gc.Equals, "ubuntu-quantal")
+	c.Assert(ctr.Status, gc.Equals, lxd.StatusRunning)
+	c.Assert(ctr.Config["user.user-data"], jc.HasPrefix, "#cloud-config\n")
+	c.Assert(ctr.Devices, jc.DeepEquals, map[string]map[string]string{
+		"eth0": {
+			"type":    "nic",
+			"nictype": "bridged",
+			"parent":  "nic42",
+		},
+	})
+}
+
+func (s *LXDSuite) TestCreateContainerAppliesConstraints(c *gc.C) {
+	machineConfig, err := containertesting.MockMachineConfig("1/lxd/0")
+	c.Assert(err, jc.ErrorIsNil)
+	envConfig, err := config.New(config.NoDefaults, dummy.SampleConfig())
+	c.Assert(err, jc.ErrorIsNil)
+	machineConfig.Config = envConfig
+	machineConfig.Constraints = constraints.MustParse("mem=512M cpu-cores=2")
+
+	inst, hardware, err := s.manager.CreateContainer(machineConfig, "quantal", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(*hardware.Mem, gc.Equals, uint64(512))
+	c.Assert(*hardware.CpuCores, gc.Equals, uint64(2))
+
+	ctr, ok := s.Server.Container(string(inst.Id()))
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(ctr.Config["limits.memory"], gc.Equals, "512MB")
+	c.Assert(ctr.Config["limits.cpu"], gc.Equals, "2")
+	c.Assert(ctr.Devices, gc.HasLen, 0)
+}
+
+func (s *LXDSuite) TestCreateContainerImportsImage(c *gc.C) {
+	s.Server.AddRemoteImage("precise")
+	machineConfig, err := containertesting.MockMachineConfig("1/lxd/0")
+	c.Assert(err, jc.ErrorIsNil)
+	inst, _, err := s.manager.CreateContainer(machineConfig, "precise", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	ctr, ok := s.Server.Container(string(inst.Id()))
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(ctr.Image, gc.Equals, "ubuntu-precise")
+	c.Assert(s.Server.Imports(), jc.DeepEquals, []mock.Import{{
+		Server: lxd.DefaultImageServer,
+		Alias:  "precise",
+	}})
+
+	// The image is only imported once.
+	machineConfig, err = containertesting.MockMachineConfig("1/lxd/1")
+	c.Assert(err, jc.ErrorIsNil)
+	_, _, err = s.manager.CreateContainer(machineConfig, "precise", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.Server.Imports(), gc.HasLen, 1)
+}
+
+func (s *LXDSuite) TestCreateContainerUsesCachedImage(c *gc.C) {
+	machineConfig, err := containertesting.MockMachineConfig("1/lxd/0")
+	c.Assert(err, jc.ErrorIsNil)
+	_, _, err = s.manager.CreateContainer(machineConfig, "quantal", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.Server.Imports(), gc.HasLen, 0)
+}
+
+func (s *LXDSuite) TestCreateContainerImageNotAvailable(c *gc.C) {
+	machineConfig, err := containertesting.MockMachineConfig("1/lxd/0")
+	c.Assert(err, jc.ErrorIsNil)
+	_, _, err = s.manager.CreateContainer(machineConfig, "precise", nil)
+	c.Assert(err, gc.ErrorMatches, `lxd container creation failed: cannot import image "precise" from .*: operation failure: image "precise" not found on .*`)
+	containers, err := s.manager.ListContainers()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(containers, gc.HasLen, 0)
+}
+
+func (s *LXDSuite) TestDestroyContainer(c *gc.C) {
+	inst := containertesting.CreateContainer(c, s.manager, "1/lxd/0")
+
+	err := s.manager.DestroyContainer(inst.Id())
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, ok := s.Server.Container(string(inst.Id()))
+	c.Assert(ok, jc.IsFalse)
+}
+
+func (s *LXDSuite) TestInstanceStatusIsLive(c *gc.C) {
+	inst := containertesting.CreateContainer(c, s.manager, "1/lxd/0")
+	c.Assert(inst.Status(), gc.Equals, "running")
+
+	s.Server.SetStatus(string(inst.Id()), lxd.StatusStopped)
+	c.Assert(inst.Status(), gc.Equals, "stopped")
+}
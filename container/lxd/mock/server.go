@@ -0,0 +1,289 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package mock
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	"github.com/juju/juju/container/lxd"
+)
+
+// This file provides a fake LXD daemon, serving enough of the
+// REST API for the lxd package to manage containers with.
+
+// Container records a container created on the fake daemon.
+type Container struct {
+	Name    string
+	Image   string
+	Config  map[string]string
+	Devices map[string]map[string]string
+	Status  string
+}
+
+// Import records an image imported by the fake daemon.
+type Import struct {
+	Server string
+	Alias  string
+}
+
+// Server is a fake LXD daemon listening on a local port.
+type Server struct {
+	*httptest.Server
+
+	mu           sync.Mutex
+	images       map[string]bool
+	remoteImages map[string]bool
+	fingerprints map[string]bool
+	imports      []Import
+	containers   map[string]*Container
+	operations   map[string]operationResult
+}
+
+// operationResult holds the outcome of a completed operation.
+type operationResult struct {
+	err      error
+	metadata interface{}
+}
+
+// NewServer starts and returns a new fake LXD daemon, which must
+// be closed when no longer required.
+func NewServer() *Server {
+	srv := &Server{
+		images:       make(map[string]bool),
+		remoteImages: make(map[string]bool),
+		fingerprints: make(map[string]bool),
+		containers:   make(map[string]*Container),
+		operations:   make(map[string]operationResult),
+	}
+	srv.Server = httptest.NewServer(http.HandlerFunc(srv.serveHTTP))
+	return srv
+}
+
+// Client returns a client that talks to the fake daemon.
+func (srv *Server) Client() *lxd.Client {
+	return lxd.NewClient(srv.URL, http.DefaultClient)
+}
+
+// AddImage adds a cached image with the given alias.
+func (srv *Server) AddImage(alias string) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	srv.images[alias] = true
+}
+
+// AddRemoteImage makes an image with the given alias available
+// for the fake daemon to import from any image server.
+func (srv *Server) AddRemoteImage(alias string) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	srv.remoteImages[alias] = true
+}
+
+// Imports returns the images the fake daemon has imported.
+func (srv *Server) Imports() []Import {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	return append([]Import(nil), srv.imports...)
+}
+
+// Container returns a copy of the named container,
+// and whether it exists.
+func (srv *Server) Container(name string) (Container, bool) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	ctr, ok := srv.containers[name]
+	if !ok {
+		return Container{}, false
+	}
+	return *ctr, true
+}
+
+// SetStatus changes the status of the named container,
+// as if it had changed outside of juju's control.
+func (srv *Server) SetStatus(name, status string) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	srv.containers[name].Status = status
+}
+
+type request struct {
+	Name   string `json:"name"`
+	Target string `json:"target"`
+	Source struct {
+		Server string `json:"server"`
+		Alias  string `json:"alias"`
+	} `json:"source"`
+	Config  map[string]string            `json:"config"`
+	Devices map[string]map[string]string `json:"devices"`
+	Action  string                       `json:"action"`
+}
+
+func (srv *Server) serveHTTP(w http.ResponseWriter, req *http.Request) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	var body request
+	if req.Body != nil && req.ContentLength != 0 {
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	path := strings.Split(strings.TrimPrefix(req.URL.Path, "/1.0/"), "/")
+	switch {
+	case req.Method == "GET" && len(path) == 3 && path[0] == "images" && path[1] == "aliases":
+		if !srv.images[path[2]] {
+			writeError(w, http.StatusNotFound, "not found")
+			return
+		}
+		writeSync(w, map[string]string{"target": "fingerprint-" + path[2]})
+	case req.Method == "POST" && len(path) == 1 && path[0] == "images":
+		fingerprint, err := srv.importImage(body)
+		srv.writeAsyncResult(w, err, map[string]string{"fingerprint": fingerprint})
+	case req.Method == "POST" && len(path) == 2 && path[0] == "images" && path[1] == "aliases":
+		if !srv.fingerprints[body.Target] {
+			writeError(w, http.StatusNotFound, "not found")
+			return
+		}
+		srv.images[body.Name] = true
+		writeSync(w, map[string]string{})
+	case req.Method == "GET" && len(path) == 1 && path[0] == "containers":
+		urls := []string{}
+		for name := range srv.containers {
+			urls = append(urls, "/1.0/containers/"+name)
+		}
+		writeSync(w, urls)
+	case req.Method == "POST" && len(path) == 1 && path[0] == "containers":
+		srv.writeAsync(w, srv.createContainer(body))
+	case req.Method == "DELETE" && len(path) == 2 && path[0] == "containers":
+		srv.writeAsync(w, srv.deleteContainer(path[1]))
+	case req.Method == "GET" && len(path) == 3 && path[0] == "containers" && path[2] == "state":
+		ctr, ok := srv.containers[path[1]]
+		if !ok {
+			writeError(w, http.StatusNotFound, "not found")
+			return
+		}
+		writeSync(w, map[string]interface{}{"status": ctr.Status})
+	case req.Method == "PUT" && len(path) == 3 && path[0] == "containers" && path[2] == "state":
+		srv.writeAsync(w, srv.changeState(path[1], body.Action))
+	case req.Method == "GET" && len(path) == 3 && path[0] == "operations" && path[2] == "wait":
+		result, ok := srv.operations[path[1]]
+		if !ok {
+			writeError(w, http.StatusNotFound, "not found")
+			return
+		}
+		op := map[string]interface{}{"status": "Success", "status_code": 200, "metadata": result.metadata}
+		if result.err != nil {
+			op = map[string]interface{}{"status": "Failure", "status_code": 400, "err": result.err.Error()}
+		}
+		writeSync(w, op)
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+func (srv *Server) importImage(body request) (string, error) {
+	if !srv.remoteImages[body.Source.Alias] {
+		return "", fmt.Errorf("image %q not found on %s", body.Source.Alias, body.Source.Server)
+	}
+	srv.imports = append(srv.imports, Import{
+		Server: body.Source.Server,
+		Alias:  body.Source.Alias,
+	})
+	fingerprint := "fingerprint-" + body.Source.Alias
+	srv.fingerprints[fingerprint] = true
+	return fingerprint, nil
+}
+
+func (srv *Server) createContainer(body request) error {
+	if _, ok := srv.containers[body.Name]; ok {
+		return fmt.Errorf("container %q already exists", body.Name)
+	}
+	if !srv.images[body.Source.Alias] {
+		return fmt.Errorf("image %q not found", body.Source.Alias)
+	}
+	srv.containers[body.Name] = &Container{
+		Name:    body.Name,
+		Image:   body.Source.Alias,
+		Config:  body.Config,
+		Devices: body.Devices,
+		Status:  lxd.StatusStopped,
+	}
+	return nil
+}
+
+func (srv *Server) deleteContainer(name string) error {
+	ctr, ok := srv.containers[name]
+	if !ok {
+		return fmt.Errorf("container %q not found", name)
+	}
+	if ctr.Status != lxd.StatusStopped {
+		return fmt.Errorf("container %q is running", name)
+	}
+	delete(srv.containers, name)
+	return nil
+}
+
+func (srv *Server) changeState(name, action string) error {
+	ctr, ok := srv.containers[name]
+	if !ok {
+		return fmt.Errorf("container %q not found", name)
+	}
+	switch action {
+	case "start":
+		ctr.Status = lxd.StatusRunning
+	case "stop":
+		ctr.Status = lxd.StatusStopped
+	default:
+		return fmt.Errorf("unknown action %q", action)
+	}
+	return nil
+}
+
+// writeAsync records the outcome of an operation, which has already
+// completed, and reports the operation to the client.
+func (srv *Server) writeAsync(w http.ResponseWriter, err error) {
+	srv.writeAsyncResult(w, err, nil)
+}
+
+// writeAsyncResult is like writeAsync, but also records
+// the metadata of the operation.
+func (srv *Server) writeAsyncResult(w http.ResponseWriter, err error, metadata interface{}) {
+	id := fmt.Sprintf("op-%d", len(srv.operations))
+	srv.operations[id] = operationResult{err, metadata}
+	writeJSON(w, http.StatusAccepted, map[string]interface{}{
+		"type":        "async",
+		"status":      "OK",
+		"status_code": 100,
+		"operation":   "/1.0/operations/" + id,
+	})
+}
+
+func writeSync(w http.ResponseWriter, metadata interface{}) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"type":        "sync",
+		"status":      "Success",
+		"status_code": 200,
+		"metadata":    metadata,
+	})
+}
+
+func writeError(w http.ResponseWriter, code int, message string) {
+	writeJSON(w, code, map[string]interface{}{
+		"type":       "error",
+		"error":      message,
+		"error_code": code,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,36 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Functions defined in this file should *ONLY* be used for testing.  These
+// functions are exported for testing purposes only, and shouldn't be called
+// from code that isn't in a test file.
+
+package testing
+
+import (
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/container/lxd"
+	"github.com/juju/juju/container/lxd/mock"
+	"github.com/juju/juju/testing"
+)
+
+// TestSuite replaces the LXD daemon that the manager uses with a
+// fake daemon, which has an image cached for the quantal series
+// used by the container testing helpers.
+type TestSuite struct {
+	testing.BaseSuite
+	Server *mock.Server
+}
+
+func (s *TestSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.Server = mock.NewServer()
+	s.Server.AddImage(lxd.ImageAlias("quantal"))
+	s.PatchValue(&lxd.LocalClient, s.Server.Client)
+}
+
+func (s *TestSuite) TearDownTest(c *gc.C) {
+	s.Server.Close()
+	s.BaseSuite.TearDownTest(c)
+}
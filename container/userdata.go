@@ -21,7 +21,7 @@ var (
 // and writes the serialized form out to a cloud-init file in the directory
 // specified.
 func WriteUserData(machineConfig *cloudinit.MachineConfig, directory string) (string, error) {
-	userData, err := cloudInitUserData(machineConfig)
+	userData, err := CloudInitUserData(machineConfig)
 	if err != nil {
 		logger.Errorf("failed to create user data: %v", err)
 		return "", err
@@ -40,7 +40,9 @@ func WriteCloudInitFile(directory string, userData []byte) (string, error) {
 	return userDataFilename, nil
 }
 
-func cloudInitUserData(machineConfig *cloudinit.MachineConfig) ([]byte, error) {
+// CloudInitUserData returns the serialized cloud-init user data
+// for the specified machine config.
+func CloudInitUserData(machineConfig *cloudinit.MachineConfig) ([]byte, error) {
 	cloudConfig := coreCloudinit.New()
 	udata, err := cloudinit.NewUserdataConfig(machineConfig, cloudConfig)
 	if err != nil {
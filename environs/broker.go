@@ -47,6 +47,10 @@ type StartInstanceParams struct {
 	// NetworkInfo is an optional list of network interface details,
 	// necessary to configure on the instance.
 	NetworkInfo []network.InterfaceInfo
+
+	// InstanceTags is a set of tags to set on the instance,
+	// if the provider supports tagging instances.
+	InstanceTags map[string]string
 }
 
 // StartInstanceResult holds the result of an
@@ -88,3 +92,12 @@ type InstanceBroker interface {
 	// AllInstances returns all instances currently known to the broker.
 	AllInstances() ([]instance.Instance, error)
 }
+
+// InstanceTagger is an interface that may be implemented by an
+// Environ whose provider supports tagging instances.
+type InstanceTagger interface {
+	// TagInstance sets the given tags on the instance with the
+	// given id. Tags with the same keys are replaced, and other
+	// existing tags are left alone.
+	TagInstance(id instance.Id, tags map[string]string) error
+}
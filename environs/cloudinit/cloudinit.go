@@ -13,7 +13,6 @@ import (
 	"github.com/juju/errors"
 	"github.com/juju/names"
 	"github.com/juju/utils"
-	"github.com/juju/utils/apt"
 	"github.com/juju/utils/proxy"
 	goyaml "gopkg.in/yaml.v1"
 
@@ -218,12 +217,48 @@ func AddAptCommands(
 
 	// Write out the apt proxy settings
 	if (proxySettings != proxy.Settings{}) {
-		filename := apt.ConfFile
-		c.AddBootCmd(fmt.Sprintf(
-			`[ -f %s ] || (printf '%%s\n' %s > %s)`,
-			filename,
-			shquote(apt.ProxyContent(proxySettings)),
-			filename))
+		aptGet := &cloudinit.AptPackageManager{}
+		c.AddBootCmd(aptGet.SetProxy(proxySettings))
+	}
+}
+
+// AddYumCommands updates the cloudinit.Config instance with the
+// commands needed to install juju's packages with yum, refreshing
+// and upgrading the installed packages first if requested, and
+// configures yum's proxy if one is set.
+func AddYumCommands(
+	proxySettings proxy.Settings,
+	c *cloudinit.Config,
+	addUpdateScripts bool,
+	addUpgradeScripts bool,
+) {
+	// Check preconditions
+	if c == nil {
+		panic("c is nil")
+	}
+	yum := &cloudinit.YumPackageManager{}
+
+	// Write out the yum proxy settings before yum is first run.
+	if (proxySettings != proxy.Settings{}) {
+		if cmd := yum.SetProxy(proxySettings); cmd != "" {
+			c.AddBootCmd(cmd)
+		}
+	}
+
+	// Bring packages up-to-date.
+	if addUpdateScripts {
+		c.AddRunCmd(cloudinit.LogProgressCmd("Updating package list"))
+		c.AddRunCmd(yum.Update())
+	}
+	if addUpgradeScripts {
+		c.AddRunCmd(cloudinit.LogProgressCmd("Upgrading packages"))
+		c.AddRunCmd(yum.Upgrade())
+	}
+
+	// If we're not doing an update, adding these packages is
+	// meaningless.
+	if addUpdateScripts {
+		c.AddRunCmd(yum.Install("curl", "bridge-utils", "rsyslog-gnutls"))
 	}
 }
 
@@ -57,8 +57,8 @@ func NewUserdataConfig(cfg *MachineConfig, c *cloudinit.Config) (UserdataConfig,
 	}
 
 	switch operatingSystem {
-	case version.Ubuntu:
-		return newUbuntuConfig(cfg, c)
+	case version.Ubuntu, version.CentOS:
+		return newUnixConfig(cfg, c)
 	case version.Windows:
 		return newWindowsConfig(cfg, c)
 	default:
@@ -11,6 +11,7 @@ import (
 
 	"github.com/juju/names"
 	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils/proxy"
 	gc "gopkg.in/check.v1"
 	goyaml "gopkg.in/yaml.v1"
 
@@ -379,6 +380,48 @@ printf '%s\\n' '.*' > '/var/lib/juju/agents/machine-2-lxc-1/agent\.conf'
 ln -s 1\.2\.3-quantal-amd64 '/var/lib/juju/tools/machine-2-lxc-1'
 cat >> /etc/init/jujud-machine-2-lxc-1\.conf << 'EOF'\\ndescription "juju machine-2-lxc-1 agent"\\nauthor "Juju Team <juju@lists\.ubuntu\.com>"\\nstart on runlevel \[2345\]\\nstop on runlevel \[!2345\]\\nrespawn\\nnormal exit 0\\n\\nlimit nofile 20000 20000\\n\\nscript\\n\\n\\n  # Ensure log files are properly protected\\n  touch /var/log/juju/machine-2-lxc-1\.log\\n  chown syslog:syslog /var/log/juju/machine-2-lxc-1\.log\\n  chmod 0600 /var/log/juju/machine-2-lxc-1\.log\\n\\n  exec /var/lib/juju/tools/machine-2-lxc-1/jujud machine --data-dir '/var/lib/juju' --machine-id 2/lxc/1 --debug >> /var/log/juju/machine-2-lxc-1\.log 2>&1\\nend script\\nEOF\\n
 start jujud-machine-2-lxc-1
+`,
+	}, {
+		// vivid uses systemd rather than upstart.
+		cfg: cloudinit.MachineConfig{
+			MachineId:          "5",
+			AuthorizedKeys:     "sshkey1",
+			AgentEnvironment:   map[string]string{agent.ProviderType: "dummy"},
+			DataDir:            dataDir,
+			LogDir:             jujuLogDir,
+			Jobs:               normalMachineJobs,
+			CloudInitOutputLog: cloudInitOutputLog,
+			Bootstrap:          false,
+			Tools:              newSimpleTools("1.2.3-vivid-amd64"),
+			Series:             "vivid",
+			MachineNonce:       "FAKE_NONCE",
+			MongoInfo: &mongo.MongoInfo{
+				Tag:      names.NewMachineTag("5"),
+				Password: "arble",
+				Info: mongo.Info{
+					Addrs:  []string{"state-addr.testing.invalid:12345"},
+					CACert: "CA CERT\n" + testing.CACert,
+				},
+			},
+			APIInfo: &api.Info{
+				Addrs:      []string{"state-addr.testing.invalid:54321"},
+				Tag:        names.NewMachineTag("5"),
+				Password:   "bletch",
+				CACert:     "CA CERT\n" + testing.CACert,
+				EnvironTag: testing.EnvironmentTag,
+			},
+			MachineAgentServiceName: "jujud-machine-5",
+			EnableOSRefreshUpdate:   true,
+		},
+		inexactMatch: true,
+		expectScripts: `
+ln -s 1\.2\.3-vivid-amd64 '/var/lib/juju/tools/machine-5'
+cat > /etc/systemd/system/jujud-machine-5-exec-start\.sh << 'EOF'\\n#!/usr/bin/env bash\\n.*\\nexec /var/lib/juju/tools/machine-5/jujud machine --data-dir '/var/lib/juju' --machine-id 5 --debug >> /var/log/juju/machine-5\.log 2>&1\\nEOF\\n
+chmod 0755 /etc/systemd/system/jujud-machine-5-exec-start\.sh
+cat > /etc/systemd/system/jujud-machine-5\.service << 'EOF'\\n\[Unit\]\\nDescription=juju machine-5 agent\\n.*\\nLimitNOFILE=20000\\nExecStart=/etc/systemd/system/jujud-machine-5-exec-start\.sh\\nRestart=on-failure\\n.*EOF\\n
+systemctl daemon-reload
+systemctl enable /etc/systemd/system/jujud-machine-5\.service
+systemctl start jujud-machine-5\.service
 `,
 	}, {
 		// hostname verification disabled.
@@ -646,6 +689,82 @@ func (*cloudinitSuite) TestCloudInitConfigureUsesGivenConfig(c *gc.C) {
 	c.Check(runCmd[0], gc.Equals, script)
 }
 
+func (*cloudinitSuite) TestCloudInitCentOS(c *gc.C) {
+	mcfg := cloudinit.MachineConfig{
+		MachineId:          "6",
+		AuthorizedKeys:     "sshkey1",
+		AgentEnvironment:   map[string]string{agent.ProviderType: "dummy"},
+		DataDir:            dataDir,
+		LogDir:             jujuLogDir,
+		Jobs:               normalMachineJobs,
+		CloudInitOutputLog: cloudInitOutputLog,
+		Bootstrap:          false,
+		Tools:              newSimpleTools("1.2.3-centos7-amd64"),
+		Series:             "centos7",
+		MachineNonce:       "FAKE_NONCE",
+		MongoInfo: &mongo.MongoInfo{
+			Tag:      names.NewMachineTag("6"),
+			Password: "arble",
+			Info: mongo.Info{
+				Addrs:  []string{"state-addr.testing.invalid:12345"},
+				CACert: "CA CERT\n" + testing.CACert,
+			},
+		},
+		APIInfo: &api.Info{
+			Addrs:      []string{"state-addr.testing.invalid:54321"},
+			Tag:        names.NewMachineTag("6"),
+			Password:   "bletch",
+			CACert:     "CA CERT\n" + testing.CACert,
+			EnvironTag: testing.EnvironmentTag,
+		},
+		AptProxySettings:        proxy.Settings{Http: "http://user@10.0.0.1"},
+		MachineAgentServiceName: "jujud-machine-6",
+		EnableOSRefreshUpdate:   true,
+	}
+	cloudcfg := coreCloudinit.New()
+	udata, err := cloudinit.NewUserdataConfig(&mcfg, cloudcfg)
+	c.Assert(err, jc.ErrorIsNil)
+	err = udata.Configure()
+	c.Assert(err, jc.ErrorIsNil)
+	data, err := udata.Render()
+	c.Assert(err, jc.ErrorIsNil)
+
+	configKeyValues := make(map[interface{}]interface{})
+	err = goyaml.Unmarshal(data, &configKeyValues)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Packages are managed with yum, so none of the apt
+	// options understood by cloud-init may be rendered.
+	c.Check(configKeyValues["apt_get_wrapper"], gc.IsNil)
+	c.Check(configKeyValues["apt_update"], gc.IsNil)
+	c.Check(configKeyValues["apt_upgrade"], gc.IsNil)
+	c.Check(configKeyValues["packages"], gc.IsNil)
+
+	scripts := getScripts(configKeyValues)
+	assertScriptMatch(c, scripts, `
+grep -q '\^proxy=' /etc/yum\.conf \|\| \(printf '%s\\n' 'proxy=http://user@10\.0\.0\.1' >> /etc/yum\.conf\)
+yum --assumeyes makecache
+yum --assumeyes install curl bridge-utils rsyslog-gnutls
+ln -s 1\.2\.3-centos7-amd64 '/var/lib/juju/tools/machine-6'
+systemctl enable /etc/systemd/system/jujud-machine-6\.service
+systemctl start jujud-machine-6\.service
+`, false)
+	for _, script := range scripts {
+		c.Check(script, gc.Not(jc.Contains), "chown syslog")
+	}
+}
+
+func (*cloudinitSuite) TestCloudInitCentOSBootstrap(c *gc.C) {
+	mcfg := minimalMachineConfig()
+	mcfg.Config = minimalConfig(c)
+	mcfg.Tools = newSimpleTools("1.2.3-centos7-amd64")
+	mcfg.Series = "centos7"
+	udata, err := cloudinit.NewUserdataConfig(&mcfg, coreCloudinit.New())
+	c.Assert(err, jc.ErrorIsNil)
+	err = udata.Configure()
+	c.Assert(err, gc.ErrorMatches, `cannot bootstrap a state server on series "centos7"`)
+}
+
 func getScripts(configKeyValue map[interface{}]interface{}) []string {
 	var scripts []string
 	if bootcmds, ok := configKeyValue["bootcmd"]; ok {
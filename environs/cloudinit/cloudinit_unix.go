@@ -22,7 +22,10 @@ import (
 	"github.com/juju/juju/cloudinit"
 	"github.com/juju/juju/environs/imagemetadata"
 	"github.com/juju/juju/juju/osenv"
+	"github.com/juju/juju/service"
+	"github.com/juju/juju/service/systemd"
 	"github.com/juju/juju/service/upstart"
+	"github.com/juju/juju/version"
 )
 
 const (
@@ -52,24 +55,32 @@ for n in $(seq {{.ToolsDownloadAttempts}}); do
 done`
 )
 
-type ubuntuConfigure struct {
+// unixConfigure configures the Linux distributions supported
+// by juju; it implements UserdataConfig.
+type unixConfigure struct {
 	mcfg     *MachineConfig
 	conf     *cloudinit.Config
 	renderer cloudinit.Renderer
+	os       version.OSType
 }
 
-func (w *ubuntuConfigure) init() error {
+func (w *unixConfigure) init() error {
+	operatingSystem, err := version.GetOSFromSeries(w.mcfg.Series)
+	if err != nil {
+		return err
+	}
 	renderer, err := cloudinit.NewRenderer(w.mcfg.Series)
 	if err != nil {
 		return err
 	}
+	w.os = operatingSystem
 	w.renderer = renderer
 	return nil
 }
 
 // Configure updates the provided cloudinit.Config with
 // configuration to initialize a Juju machine agent.
-func (w *ubuntuConfigure) Configure() error {
+func (w *unixConfigure) Configure() error {
 	if err := w.ConfigureBasic(); err != nil {
 		return err
 	}
@@ -88,7 +99,7 @@ func (w *ubuntuConfigure) Configure() error {
 // Doing it later brings the benefit of feedback in the face of errors,
 // but adds to the running time of initialisation due to lack of activity
 // between image bringup and start of agent installation.
-func (w *ubuntuConfigure) ConfigureBasic() error {
+func (w *unixConfigure) ConfigureBasic() error {
 	w.conf.AddScripts(
 		"set -xe", // ensure we run all the scripts or abort.
 	)
@@ -108,10 +119,14 @@ func (w *ubuntuConfigure) ConfigureBasic() error {
 
 // ConfigureJuju updates the provided cloudinit.Config with configuration
 // to initialise a Juju machine agent.
-func (w *ubuntuConfigure) ConfigureJuju() error {
+func (w *unixConfigure) ConfigureJuju() error {
 	if err := verifyConfig(w.mcfg); err != nil {
 		return err
 	}
+	// State servers install mongo from the Ubuntu archives.
+	if w.mcfg.Bootstrap && w.os != version.Ubuntu {
+		return errors.Errorf("cannot bootstrap a state server on series %q", w.mcfg.Series)
+	}
 
 	// Initialise progress reporting. We need to do separately for runcmd
 	// and (possibly, below) for bootcmd, as they may be run in different
@@ -129,13 +144,23 @@ func (w *ubuntuConfigure) ConfigureJuju() error {
 		w.conf.AddBootCmd(cloudinit.LogProgressCmd("Logging to %s on remote host", w.mcfg.CloudInitOutputLog))
 	}
 
-	AddAptCommands(
-		w.mcfg.AptProxySettings,
-		w.mcfg.AptMirror,
-		w.conf,
-		w.mcfg.EnableOSRefreshUpdate,
-		w.mcfg.EnableOSUpgrade,
-	)
+	switch w.os {
+	case version.CentOS:
+		AddYumCommands(
+			w.mcfg.AptProxySettings,
+			w.conf,
+			w.mcfg.EnableOSRefreshUpdate,
+			w.mcfg.EnableOSUpgrade,
+		)
+	default:
+		AddAptCommands(
+			w.mcfg.AptProxySettings,
+			w.mcfg.AptMirror,
+			w.conf,
+			w.mcfg.EnableOSRefreshUpdate,
+			w.mcfg.EnableOSUpgrade,
+		)
+	}
 
 	// Write out the normal proxy settings so that the settings are
 	// sourced by bash, and ssh through that.
@@ -164,8 +189,11 @@ func (w *ubuntuConfigure) ConfigureJuju() error {
 		// We only try to change ownership if there is an ubuntu user defined.
 		fmt.Sprintf("(id ubuntu &> /dev/null) && chown ubuntu:ubuntu %s", lockDir),
 		fmt.Sprintf("mkdir -p %s", w.mcfg.LogDir),
-		fmt.Sprintf("chown syslog:adm %s", w.mcfg.LogDir),
 	)
+	// There is no syslog user on CentOS; rsyslog runs as root there.
+	if w.os != version.CentOS {
+		w.conf.AddScripts(fmt.Sprintf("chown syslog:adm %s", w.mcfg.LogDir))
+	}
 
 	w.conf.AddScripts(
 		"bin="+shquote(w.mcfg.jujuTools()),
@@ -308,7 +336,7 @@ func toolsDownloadCommand(curlCommand string, urls []string) string {
 	return buf.String()
 }
 
-func (w *ubuntuConfigure) addMachineAgentToBoot(tag string) error {
+func (w *unixConfigure) addMachineAgentToBoot(tag string) error {
 	// Make the agent run via a symbolic link to the actual tools
 	// directory, so it can upgrade itself without needing to change
 	// the init script.
@@ -317,23 +345,34 @@ func (w *ubuntuConfigure) addMachineAgentToBoot(tag string) error {
 	w.conf.AddScripts(fmt.Sprintf("ln -s %v %s", w.mcfg.Tools.Version, shquote(toolsDir)))
 
 	name := w.mcfg.MachineAgentServiceName
-	conf := upstart.MachineAgentUpstartService(
+	svc := upstart.MachineAgentUpstartService(
 		name, toolsDir, w.mcfg.DataDir, w.mcfg.LogDir, tag, w.mcfg.MachineId, osenv.FeatureFlags())
-	cmds, err := conf.InstallCommands()
+	initSystem, err := service.InitSystemForSeries(w.mcfg.Series)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var cmds []string
+	if initSystem == service.InitSystemSystemd {
+		conf := svc.Conf
+		conf.InitDir = ""
+		cmds, err = systemd.NewService(name, conf).InstallCommands()
+	} else {
+		cmds, err = svc.InstallCommands()
+	}
 	if err != nil {
-		return errors.Annotatef(err, "cannot make cloud-init upstart script for the %s agent", tag)
+		return errors.Annotatef(err, "cannot make cloud-init %s script for the %s agent", initSystem, tag)
 	}
 	w.conf.AddRunCmd(cloudinit.LogProgressCmd("Starting Juju machine agent (%s)", name))
 	w.conf.AddScripts(cmds...)
 	return nil
 }
 
-func (w *ubuntuConfigure) Render() ([]byte, error) {
+func (w *unixConfigure) Render() ([]byte, error) {
 	return w.renderer.Render(w.conf)
 }
 
-func newUbuntuConfig(mcfg *MachineConfig, conf *cloudinit.Config) (*ubuntuConfigure, error) {
-	cfg := &ubuntuConfigure{
+func newUnixConfig(mcfg *MachineConfig, conf *cloudinit.Config) (*unixConfigure, error) {
+	cfg := &unixConfigure{
 		mcfg: mcfg,
 		conf: conf,
 	}
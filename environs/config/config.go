@@ -6,6 +6,7 @@ package config
 import (
 	"fmt"
 	"io/ioutil"
+	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -21,6 +22,8 @@ import (
 	"gopkg.in/juju/charm.v4"
 
 	"github.com/juju/juju/cert"
+	"github.com/juju/juju/environs/tags"
+	"github.com/juju/juju/identity"
 	"github.com/juju/juju/juju/osenv"
 	"github.com/juju/juju/version"
 )
@@ -88,6 +91,24 @@ const (
 	// Only prevent all-changes from running
 	// if user specifically requests it. Otherwise, let them run.
 	DefaultPreventAllChanges = false
+
+	// DefaultProvisionerConcurrency is the number of machines the
+	// provisioner starts at the same time, unless configured otherwise.
+	DefaultProvisionerConcurrency = 16
+
+	// DefaultProvisionerRetryCount is the number of times the
+	// provisioner retries starting an instance after a retryable
+	// failure, unless configured otherwise.
+	DefaultProvisionerRetryCount = 3
+
+	// DefaultProvisionerRetryDelay is the time, in seconds, the
+	// provisioner waits before first retrying to start an instance.
+	DefaultProvisionerRetryDelay = 10
+
+	// DefaultHookTimeout is the time, in seconds, a charm hook may
+	// run before the uniter kills it. Zero means hooks are never
+	// killed.
+	DefaultHookTimeout = 0
 )
 
 // TODO(katco-): Please grow this over time.
@@ -101,6 +122,18 @@ const (
 	// ProvisionerHarvestModeKey stores the key for this setting.
 	ProvisionerHarvestModeKey = "provisioner-harvest-mode"
 
+	// ProvisionerConcurrencyKey stores the key for this setting.
+	ProvisionerConcurrencyKey = "provisioner-concurrency"
+
+	// ProvisionerRetryCountKey stores the key for this setting.
+	ProvisionerRetryCountKey = "provisioner-retry-count"
+
+	// ProvisionerRetryDelayKey stores the key for this setting.
+	ProvisionerRetryDelayKey = "provisioner-retry-delay"
+
+	// HookTimeoutKey stores the key for this setting.
+	HookTimeoutKey = "hook-timeout"
+
 	// AgentStreamKey stores the key for this setting.
 	AgentStreamKey = "agent-stream"
 
@@ -146,6 +179,17 @@ const (
 	// PreventAllChangesKey stores the value for this setting
 	PreventAllChangesKey = BlockKeyPrefix + "all-changes"
 
+	// IdentityURLKey stores the key for this setting.
+	IdentityURLKey = "identity-url"
+
+	// IdentityPublicKeyKey stores the key for this setting.
+	IdentityPublicKeyKey = "identity-public-key"
+
+	// ResourceTagsKey is an optional space-separated string of k=v
+	// pairs, defining the tags for resources that providers create
+	// for the environment.
+	ResourceTagsKey = "resource-tags"
+
 	//
 	// Deprecated Settings Attributes
 	//
@@ -570,6 +614,39 @@ func Validate(cfg, old *Config) error {
 		}
 	}
 
+	// Ensure that the provisioner concurrency is usable.
+	if v, ok := cfg.defined[ProvisionerConcurrencyKey].(int); ok && v < 1 {
+		return fmt.Errorf("%s must be at least 1, not %d", ProvisionerConcurrencyKey, v)
+	}
+	for _, key := range []string{ProvisionerRetryCountKey, ProvisionerRetryDelayKey, HookTimeoutKey} {
+		if v, ok := cfg.defined[key].(int); ok && v < 0 {
+			return fmt.Errorf("%s must not be negative, not %d", key, v)
+		}
+	}
+
+	// Ensure that the resource tags, if set, are valid.
+	if _, err := parseResourceTags(cfg.asString(ResourceTagsKey)); err != nil {
+		return errors.Annotatef(err, "invalid %s in environment configuration", ResourceTagsKey)
+	}
+
+	// Ensure that the identity service settings, if set, are
+	// valid, and that neither is set without the other.
+	identityURL, urlOK := cfg.IdentityURL()
+	identityKey, keyOK := cfg.IdentityPublicKey()
+	if urlOK {
+		if u, err := url.Parse(identityURL); err != nil || u.Scheme == "" || u.Host == "" {
+			return errors.Errorf("invalid identity-url in environment configuration: %q", identityURL)
+		}
+	}
+	if keyOK {
+		if _, err := identity.ParsePublicKey(identityKey); err != nil {
+			return errors.Annotate(err, "invalid identity-public-key in environment configuration")
+		}
+	}
+	if urlOK != keyOK {
+		return errors.New("identity-url and identity-public-key must be set together")
+	}
+
 	// Check the immutable config values.  These can't change
 	if old != nil {
 		for _, attr := range immutableAttributes {
@@ -871,6 +948,21 @@ func (c *Config) AptMirror() string {
 	return c.asString("apt-mirror")
 }
 
+// IdentityURL returns the URL of the external identity service
+// trusted to authenticate remote users, and whether it has been set.
+func (c *Config) IdentityURL() (string, bool) {
+	u, ok := c.defined[IdentityURLKey].(string)
+	return u, ok && u != ""
+}
+
+// IdentityPublicKey returns the base64-encoded public key of the
+// external identity service trusted to authenticate remote users,
+// and whether it has been set.
+func (c *Config) IdentityPublicKey() (string, bool) {
+	key, ok := c.defined[IdentityPublicKeyKey].(string)
+	return key, ok && key != ""
+}
+
 // BootstrapSSHOpts returns the SSH timeout and retry delays used
 // during bootstrap.
 func (c *Config) BootstrapSSHOpts() SSHTimeoutOpts {
@@ -1022,6 +1114,70 @@ func (c *Config) ProvisionerHarvestMode() HarvestMode {
 	}
 }
 
+// ProvisionerConcurrency reports the maximum number of machines
+// the provisioner will start at the same time.
+func (c *Config) ProvisionerConcurrency() int {
+	if v, ok := c.defined[ProvisionerConcurrencyKey].(int); ok {
+		return v
+	}
+	return DefaultProvisionerConcurrency
+}
+
+// ProvisionerRetryCount reports the number of times the provisioner
+// retries starting an instance after a retryable failure.
+func (c *Config) ProvisionerRetryCount() int {
+	if v, ok := c.defined[ProvisionerRetryCountKey].(int); ok {
+		return v
+	}
+	return DefaultProvisionerRetryCount
+}
+
+// ProvisionerRetryDelay reports how long the provisioner waits before
+// first retrying to start an instance. The delay doubles with each
+// further retry.
+func (c *Config) ProvisionerRetryDelay() time.Duration {
+	if v, ok := c.defined[ProvisionerRetryDelayKey].(int); ok {
+		return time.Duration(v) * time.Second
+	}
+	return time.Duration(DefaultProvisionerRetryDelay) * time.Second
+}
+
+// HookTimeout reports how long a charm hook may run before the uniter
+// kills it and puts the unit in an error state. Zero means hooks may
+// run for as long as they like. Services may override it.
+func (c *Config) HookTimeout() time.Duration {
+	if v, ok := c.defined[HookTimeoutKey].(int); ok {
+		return time.Duration(v) * time.Second
+	}
+	return time.Duration(DefaultHookTimeout) * time.Second
+}
+
+// ResourceTags returns the user-defined tags that providers set
+// on the resources, such as instances, that they create.
+func (c *Config) ResourceTags() map[string]string {
+	// The tags have already been validated.
+	result, _ := parseResourceTags(c.asString(ResourceTagsKey))
+	return result
+}
+
+// parseResourceTags parses a space-separated list of key=value pairs
+// into a map. Keys must not be empty, nor start with the prefix that
+// juju reserves for its own tags.
+func parseResourceTags(s string) (map[string]string, error) {
+	result := make(map[string]string)
+	for _, field := range strings.Fields(s) {
+		parts := strings.SplitN(field, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, errors.Errorf("expected key=value, got %q", field)
+		}
+		if strings.HasPrefix(parts[0], tags.JujuTagPrefix) {
+			return nil, errors.Errorf("tag %q uses reserved prefix %q", parts[0], tags.JujuTagPrefix)
+		}
+		result[parts[0]] = parts[1]
+	}
+	return result, nil
+}
+
 // ImageStream returns the simplestreams stream
 // used to identify which image ids to search
 // when starting an instance.
@@ -1138,6 +1294,10 @@ var fields = schema.Fields{
 	"logging-config":             schema.String(),
 	"charm-store-auth":           schema.String(),
 	ProvisionerHarvestModeKey:    schema.String(),
+	ProvisionerConcurrencyKey:    schema.ForceInt(),
+	ProvisionerRetryCountKey:     schema.ForceInt(),
+	ProvisionerRetryDelayKey:     schema.ForceInt(),
+	HookTimeoutKey:               schema.ForceInt(),
 	HttpProxyKey:                 schema.String(),
 	HttpsProxyKey:                schema.String(),
 	FtpProxyKey:                  schema.String(),
@@ -1161,6 +1321,9 @@ var fields = schema.Fields{
 	PreventDestroyEnvironmentKey: schema.Bool(),
 	PreventRemoveObjectKey:       schema.Bool(),
 	PreventAllChangesKey:         schema.Bool(),
+	IdentityURLKey:               schema.String(),
+	IdentityPublicKeyKey:         schema.String(),
+	ResourceTagsKey:              schema.String(),
 
 	// Deprecated fields, retain for backwards compatibility.
 	ToolsMetadataURLKey:    schema.String(),
@@ -1186,6 +1349,11 @@ var alwaysOptional = schema.Defaults{
 	"ca-private-key-path":        schema.Omit,
 	"logging-config":             schema.Omit,
 	ProvisionerHarvestModeKey:    schema.Omit,
+	ProvisionerConcurrencyKey:    schema.Omit,
+	ProvisionerRetryCountKey:     schema.Omit,
+	ProvisionerRetryDelayKey:     schema.Omit,
+	HookTimeoutKey:               schema.Omit,
+	ResourceTagsKey:              schema.Omit,
 	"bootstrap-timeout":          schema.Omit,
 	"bootstrap-retry-delay":      schema.Omit,
 	"bootstrap-addresses-delay":  schema.Omit,
@@ -1205,6 +1373,8 @@ var alwaysOptional = schema.Defaults{
 	PreventDestroyEnvironmentKey: DefaultPreventDestroyEnvironment,
 	PreventRemoveObjectKey:       DefaultPreventRemoveObject,
 	PreventAllChangesKey:         DefaultPreventAllChanges,
+	IdentityURLKey:               schema.Omit,
+	IdentityPublicKeyKey:         schema.Omit,
 
 	// Deprecated fields, retain for backwards compatibility.
 	ToolsMetadataURLKey:    "",
@@ -616,6 +616,84 @@ var configTests = []configTest{
 			"provisioner-harvest-mode": "yes please",
 		},
 		err: `unknown harvesting method: yes please`,
+	}, {
+		about:       "provisioner-concurrency",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type": "my-type",
+			"name": "my-name",
+			"provisioner-concurrency": 4,
+		},
+	}, {
+		about:       "provisioner-concurrency too small",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type": "my-type",
+			"name": "my-name",
+			"provisioner-concurrency": 0,
+		},
+		err: `provisioner-concurrency must be at least 1, not 0`,
+	}, {
+		about:       "provisioner retry policy",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type": "my-type",
+			"name": "my-name",
+			"provisioner-retry-count": 5,
+			"provisioner-retry-delay": 30,
+		},
+	}, {
+		about:       "provisioner-retry-count negative",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type": "my-type",
+			"name": "my-name",
+			"provisioner-retry-count": -1,
+		},
+		err: `provisioner-retry-count must not be negative, not -1`,
+	}, {
+		about:       "hook timeout",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":         "my-type",
+			"name":         "my-name",
+			"hook-timeout": 600,
+		},
+	}, {
+		about:       "hook-timeout negative",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":         "my-type",
+			"name":         "my-name",
+			"hook-timeout": -1,
+		},
+		err: `hook-timeout must not be negative, not -1`,
+	}, {
+		about:       "resource tags",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":          "my-type",
+			"name":          "my-name",
+			"resource-tags": "owner=finance cost-centre=42",
+		},
+	}, {
+		about:       "resource tags without value",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":          "my-type",
+			"name":          "my-name",
+			"resource-tags": "owner",
+		},
+		err: `invalid resource-tags in environment configuration: expected key=value, got "owner"`,
+	}, {
+		about:       "resource tags with reserved prefix",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":          "my-type",
+			"name":          "my-name",
+			"resource-tags": "juju-env-uuid=foo",
+		},
+		err: `invalid resource-tags in environment configuration: tag "juju-env-uuid" uses reserved prefix "juju-"`,
 	}, {
 		about:       "default image stream",
 		useDefaults: config.UseDefaults,
@@ -890,9 +968,58 @@ var configTests = []configTest{
 			"name":       "my-name",
 			"apt-mirror": "http://my.archive.ubuntu.com",
 		},
+	}, {
+		about:       "Explicit identity service",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                "my-type",
+			"name":                "my-name",
+			"identity-url":        "https://sso.invalid",
+			"identity-public-key": identityPublicKey,
+		},
+	}, {
+		about:       "Invalid identity-url",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                "my-type",
+			"name":                "my-name",
+			"identity-url":        "sso.invalid",
+			"identity-public-key": identityPublicKey,
+		},
+		err: `invalid identity-url in environment configuration: "sso.invalid"`,
+	}, {
+		about:       "Invalid identity-public-key",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                "my-type",
+			"name":                "my-name",
+			"identity-url":        "https://sso.invalid",
+			"identity-public-key": "AAAA",
+		},
+		err: "invalid identity-public-key in environment configuration: public key has 3 bytes, expected 32",
+	}, {
+		about:       "identity-url without identity-public-key",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":         "my-type",
+			"name":         "my-name",
+			"identity-url": "https://sso.invalid",
+		},
+		err: "identity-url and identity-public-key must be set together",
+	}, {
+		about:       "identity-public-key without identity-url",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                "my-type",
+			"name":                "my-name",
+			"identity-public-key": identityPublicKey,
+		},
+		err: "identity-url and identity-public-key must be set together",
 	},
 }
 
+var identityPublicKey = "rA5bVijysUv046pqr5AK0bVlY/b0cAFK8gCiDhdGHy4="
+
 // authTokenConfigTest returns a config test that checks
 // that a configuration with the given auth token
 // will pass or fail, depending on the value of ok.
@@ -1187,6 +1314,22 @@ func (test configTest) check(c *gc.C, home *gitjujutesting.FakeHome) {
 		c.Assert(cfg.SSLHostnameVerification(), gc.Equals, v)
 	}
 
+	identityURL, identityURLPresent := cfg.IdentityURL()
+	if v, ok := test.attrs["identity-url"]; ok {
+		c.Assert(identityURLPresent, jc.IsTrue)
+		c.Assert(identityURL, gc.Equals, v)
+	} else {
+		c.Assert(identityURLPresent, jc.IsFalse)
+	}
+
+	identityKey, identityKeyPresent := cfg.IdentityPublicKey()
+	if v, ok := test.attrs["identity-public-key"]; ok {
+		c.Assert(identityKeyPresent, jc.IsTrue)
+		c.Assert(identityKey, gc.Equals, v)
+	} else {
+		c.Assert(identityKeyPresent, jc.IsFalse)
+	}
+
 	if v, ok := test.attrs["provisioner-harvest-mode"]; ok {
 		hvstMeth, err := config.ParseHarvestMode(v.(string))
 		c.Assert(err, jc.ErrorIsNil)
@@ -1194,6 +1337,36 @@ func (test configTest) check(c *gc.C, home *gitjujutesting.FakeHome) {
 	} else {
 		c.Assert(cfg.ProvisionerHarvestMode(), gc.Equals, config.HarvestDestroyed)
 	}
+	if v, ok := test.attrs["provisioner-concurrency"]; ok {
+		c.Assert(cfg.ProvisionerConcurrency(), gc.Equals, v)
+	} else {
+		c.Assert(cfg.ProvisionerConcurrency(), gc.Equals, config.DefaultProvisionerConcurrency)
+	}
+	if v, ok := test.attrs["provisioner-retry-count"]; ok {
+		c.Assert(cfg.ProvisionerRetryCount(), gc.Equals, v)
+	} else {
+		c.Assert(cfg.ProvisionerRetryCount(), gc.Equals, config.DefaultProvisionerRetryCount)
+	}
+	test.assertDuration(
+		c,
+		"provisioner-retry-delay",
+		cfg.ProvisionerRetryDelay(),
+		config.DefaultProvisionerRetryDelay,
+	)
+	test.assertDuration(
+		c,
+		"hook-timeout",
+		cfg.HookTimeout(),
+		config.DefaultHookTimeout,
+	)
+	if _, ok := test.attrs["resource-tags"]; ok {
+		c.Assert(cfg.ResourceTags(), jc.DeepEquals, map[string]string{
+			"owner":       "finance",
+			"cost-centre": "42",
+		})
+	} else {
+		c.Assert(cfg.ResourceTags(), gc.HasLen, 0)
+	}
 	sshOpts := cfg.BootstrapSSHOpts()
 	test.assertDuration(
 		c,
@@ -0,0 +1,61 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package tags defines the tags that juju sets on the resources,
+// such as instances, that providers create for an environment.
+package tags
+
+import (
+	"strings"
+)
+
+// JujuTagPrefix is the prefix of the tags that juju sets itself.
+// Users may not define tags with this prefix.
+const JujuTagPrefix = "juju-"
+
+const (
+	// JujuEnv is the tag that holds the UUID of the environment
+	// that a resource belongs to.
+	JujuEnv = JujuTagPrefix + "env-uuid"
+
+	// JujuMachine is the tag that holds the id of the machine
+	// that an instance was started for.
+	JujuMachine = JujuTagPrefix + "machine-id"
+
+	// JujuStateServer is the tag that is set to "true" on
+	// instances that run a state server.
+	JujuStateServer = JujuTagPrefix + "is-state"
+
+	// JujuUnitsDeployed is the tag that holds the space-separated
+	// names of the principal units assigned to a machine.
+	JujuUnitsDeployed = JujuTagPrefix + "units-deployed"
+)
+
+// ResourceTags returns the tags that are set on every resource of
+// the environment with the given UUID: the user-defined tags, and
+// the environment's UUID.
+func ResourceTags(envUUID string, userTags map[string]string) map[string]string {
+	tags := make(map[string]string)
+	for key, value := range userTags {
+		tags[key] = value
+	}
+	tags[JujuEnv] = envUUID
+	return tags
+}
+
+// InstanceTags returns the tags that are set on the instance of
+// the machine with the given id, in addition to the resource tags.
+func InstanceTags(resourceTags map[string]string, machineId string, isStateServer bool, units []string) map[string]string {
+	tags := make(map[string]string)
+	for key, value := range resourceTags {
+		tags[key] = value
+	}
+	tags[JujuMachine] = machineId
+	if isStateServer {
+		tags[JujuStateServer] = "true"
+	}
+	if len(units) > 0 {
+		tags[JujuUnitsDeployed] = strings.Join(units, " ")
+	}
+	return tags
+}
@@ -0,0 +1,46 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package tags_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs/tags"
+	"github.com/juju/juju/testing"
+)
+
+type tagsSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&tagsSuite{})
+
+func (*tagsSuite) TestResourceTags(c *gc.C) {
+	userTags := map[string]string{"owner": "finance"}
+	resourceTags := tags.ResourceTags("env-uuid", userTags)
+	c.Assert(resourceTags, jc.DeepEquals, map[string]string{
+		"owner":         "finance",
+		"juju-env-uuid": "env-uuid",
+	})
+	// The user's tags are left alone.
+	c.Assert(userTags, jc.DeepEquals, map[string]string{"owner": "finance"})
+}
+
+func (*tagsSuite) TestInstanceTags(c *gc.C) {
+	resourceTags := tags.ResourceTags("env-uuid", nil)
+	instanceTags := tags.InstanceTags(resourceTags, "0", true, []string{"wordpress/0", "mysql/0"})
+	c.Assert(instanceTags, jc.DeepEquals, map[string]string{
+		"juju-env-uuid":       "env-uuid",
+		"juju-machine-id":     "0",
+		"juju-is-state":       "true",
+		"juju-units-deployed": "wordpress/0 mysql/0",
+	})
+
+	instanceTags = tags.InstanceTags(resourceTags, "1/lxc/0", false, nil)
+	c.Assert(instanceTags, jc.DeepEquals, map[string]string{
+		"juju-env-uuid":   "env-uuid",
+		"juju-machine-id": "1/lxc/0",
+	})
+}
@@ -0,0 +1,366 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// The identity package implements the credentials that let users
+// authenticated by an external identity service log in to juju.
+//
+// The credentials are macaroons. Juju issues a macaroon holding a third
+// party caveat that only the identity service can discharge; the user
+// has the identity service discharge it, which it does by declaring the
+// name and groups of the user it has authenticated, and then logs in
+// with the macaroon and its discharge. The user therefore needs no
+// password stored in juju itself.
+package identity
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"golang.org/x/crypto/nacl/box"
+	"gopkg.in/macaroon.v1"
+)
+
+const (
+	// Location holds the location of the macaroons issued by juju.
+	Location = "juju"
+
+	// AuthenticatedUserCondition holds the condition of the third
+	// party caveat that the identity service discharges once it has
+	// authenticated the user.
+	AuthenticatedUserCondition = "is-authenticated-user"
+)
+
+// The names of the first party caveats understood by Verify.
+const (
+	environmentCaveat = "environment-uuid"
+	timeBeforeCaveat  = "time-before"
+	declaredCaveat    = "declared"
+)
+
+// Identity holds the identity of a user, as declared
+// by the identity service that authenticated them.
+type Identity struct {
+	// User holds the name of the user, qualified with the
+	// domain of the identity service, for example "bob@sso".
+	User string
+
+	// Groups holds the names of the groups the user belongs to.
+	Groups []string
+}
+
+// PublicKey is the public key of an identity service, used to
+// encrypt the ids of the caveats addressed to the service.
+type PublicKey [32]byte
+
+// String returns the key in the form accepted by ParsePublicKey.
+func (k *PublicKey) String() string {
+	return base64.StdEncoding.EncodeToString(k[:])
+}
+
+// ParsePublicKey parses a public key in the form
+// returned by PublicKey.String.
+func ParsePublicKey(s string) (*PublicKey, error) {
+	data, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot decode public key")
+	}
+	var key PublicKey
+	if len(data) != len(key) {
+		return nil, errors.Errorf("public key has %d bytes, expected %d", len(data), len(key))
+	}
+	copy(key[:], data)
+	return &key, nil
+}
+
+// KeyPair holds the public and private keys of an identity service.
+type KeyPair struct {
+	Public  PublicKey
+	Private [32]byte
+}
+
+// NewKeyPair returns a new key pair for an identity service.
+func NewKeyPair() (*KeyPair, error) {
+	public, private, err := box.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot generate key pair")
+	}
+	return &KeyPair{
+		Public:  PublicKey(*public),
+		Private: *private,
+	}, nil
+}
+
+// caveatIdRecord holds the id of a third party caveat. It holds the
+// caveat's root key and condition, encrypted so that only the third
+// party can read them.
+type caveatIdRecord struct {
+	// PublicKey holds the public key of the key pair
+	// used to encrypt the caveat.
+	PublicKey []byte
+
+	// Nonce holds the nonce used to encrypt the caveat.
+	Nonce []byte
+
+	// Id holds the encrypted caveatInfo.
+	Id []byte
+}
+
+// caveatInfo holds the contents of the id of a third party caveat.
+type caveatInfo struct {
+	RootKey   []byte
+	Condition string
+}
+
+// encodeCaveatId returns the id of a third party caveat with the given
+// root key and condition, that can only be read with the private key
+// corresponding to the given public key.
+func encodeCaveatId(key *PublicKey, rootKey []byte, condition string) (string, error) {
+	plain, err := json.Marshal(&caveatInfo{
+		RootKey:   rootKey,
+		Condition: condition,
+	})
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	public, private, err := box.GenerateKey(rand.Reader)
+	if err != nil {
+		return "", errors.Annotate(err, "cannot generate key pair")
+	}
+	var nonce [24]byte
+	if _, err := rand.Read(nonce[:]); err != nil {
+		return "", errors.Annotate(err, "cannot generate nonce")
+	}
+	peer := [32]byte(*key)
+	data, err := json.Marshal(&caveatIdRecord{
+		PublicKey: public[:],
+		Nonce:     nonce[:],
+		Id:        box.Seal(nil, plain, &nonce, &peer, private),
+	})
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return base64.URLEncoding.EncodeToString(data), nil
+}
+
+// decodeCaveatId returns the root key and condition held in the id of
+// a third party caveat addressed to the owner of the given key pair.
+func decodeCaveatId(key *KeyPair, id string) ([]byte, string, error) {
+	data, err := base64.URLEncoding.DecodeString(id)
+	if err != nil {
+		return nil, "", errors.Annotate(err, "malformed caveat id")
+	}
+	var record caveatIdRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, "", errors.Annotate(err, "malformed caveat id")
+	}
+	var public [32]byte
+	var nonce [24]byte
+	if len(record.PublicKey) != len(public) || len(record.Nonce) != len(nonce) {
+		return nil, "", errors.New("malformed caveat id")
+	}
+	copy(public[:], record.PublicKey)
+	copy(nonce[:], record.Nonce)
+	plain, ok := box.Open(nil, record.Id, &nonce, &public, &key.Private)
+	if !ok {
+		return nil, "", errors.New("caveat id not encrypted for this identity service")
+	}
+	var info caveatInfo
+	if err := json.Unmarshal(plain, &info); err != nil {
+		return nil, "", errors.Annotate(err, "malformed caveat id")
+	}
+	return info.RootKey, info.Condition, nil
+}
+
+// NewMacaroon returns a macaroon, signed with the given root key, for
+// logging in to the environment with the given UUID. It cannot be used
+// until the identity service at the given location, with the given
+// public key, has discharged its third party caveat.
+func NewMacaroon(rootKey []byte, envUUID, serviceLocation string, serviceKey *PublicKey) (*macaroon.Macaroon, error) {
+	id, err := randomBytes(16)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	m, err := macaroon.New(rootKey, hex.EncodeToString(id), Location)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := m.AddFirstPartyCaveat(environmentCaveat + " " + envUUID); err != nil {
+		return nil, errors.Trace(err)
+	}
+	caveatKey, err := randomBytes(24)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	caveatId, err := encodeCaveatId(serviceKey, caveatKey, AuthenticatedUserCondition)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := m.AddThirdPartyCaveat(caveatKey, caveatId, serviceLocation); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return m, nil
+}
+
+// Discharge returns a macaroon that discharges the third party caveat
+// with the given id, which must be addressed to the identity service
+// with the given key pair. The discharge declares the given identity of
+// the user, and expires at the given time.
+func Discharge(key *KeyPair, caveatId string, id *Identity, expires time.Time) (*macaroon.Macaroon, error) {
+	rootKey, condition, err := decodeCaveatId(key, caveatId)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if condition != AuthenticatedUserCondition {
+		return nil, errors.Errorf("caveat %q not recognised", condition)
+	}
+	m, err := macaroon.New(rootKey, caveatId, "")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	caveats := []string{
+		declaredCaveat + " user " + id.User,
+		declaredCaveat + " groups " + strings.Join(id.Groups, " "),
+		timeBeforeCaveat + " " + expires.UTC().Format(time.RFC3339Nano),
+	}
+	for _, caveat := range caveats {
+		if err := m.AddFirstPartyCaveat(caveat); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	return m, nil
+}
+
+// ThirdPartyCaveat returns the location and id of the third
+// party caveat of a macaroon returned by NewMacaroon.
+func ThirdPartyCaveat(m *macaroon.Macaroon) (location, id string, err error) {
+	for _, caveat := range m.Caveats() {
+		if caveat.Location != "" {
+			return caveat.Location, caveat.Id, nil
+		}
+	}
+	return "", "", errors.New("macaroon has no third party caveat")
+}
+
+// EncodeCredentials returns the given macaroon and its discharge
+// as credentials for logging in to juju. The discharge is bound to
+// the macaroon, so it cannot be used with any other.
+func EncodeCredentials(m, discharge *macaroon.Macaroon) (string, error) {
+	discharge = discharge.Clone()
+	discharge.Bind(m.Signature())
+	data, err := json.Marshal([]*macaroon.Macaroon{m, discharge})
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return string(data), nil
+}
+
+// Verify checks that the given credentials, as returned by
+// EncodeCredentials, hold a macaroon signed with the given root key
+// for logging in to the environment with the given UUID, along with
+// its unexpired discharge, and returns the identity declared by the
+// identity service that discharged it.
+func Verify(rootKey []byte, envUUID, credentials string) (*Identity, error) {
+	var ms []*macaroon.Macaroon
+	if err := json.Unmarshal([]byte(credentials), &ms); err != nil {
+		return nil, errors.Annotate(err, "malformed credentials")
+	}
+	if len(ms) == 0 {
+		return nil, errors.New("malformed credentials: no macaroons")
+	}
+	now := time.Now()
+	declared := make(map[string]string)
+	check := func(caveat string) error {
+		name, arg := splitCaveat(caveat)
+		switch name {
+		case environmentCaveat:
+			if arg != envUUID {
+				return errors.Errorf("macaroon is for environment %q", arg)
+			}
+		case timeBeforeCaveat:
+			t, err := time.Parse(time.RFC3339Nano, arg)
+			if err != nil {
+				return errors.Annotate(err, "malformed time-before caveat")
+			}
+			if !now.Before(t) {
+				return errors.Errorf("macaroon expired at %v", t)
+			}
+		case declaredCaveat:
+			// A value may be declared more than once, but never
+			// differently, so that whoever holds the discharge
+			// cannot add caveats declaring another identity.
+			key, value := splitCaveat(arg)
+			if old, ok := declared[key]; ok && old != value {
+				return errors.Errorf("%s declared as both %q and %q", key, old, value)
+			}
+			declared[key] = value
+		default:
+			return errors.Errorf("caveat %q not satisfied", caveat)
+		}
+		return nil
+	}
+	if err := ms[0].Verify(rootKey, check, ms[1:]); err != nil {
+		return nil, errors.Annotate(err, "invalid credentials")
+	}
+	user := declared["user"]
+	if user == "" {
+		return nil, errors.New("invalid credentials: no user declared")
+	}
+	return &Identity{
+		User:   user,
+		Groups: strings.Fields(declared["groups"]),
+	}, nil
+}
+
+// ObtainDischarge asks the identity service at the given location to
+// discharge the third party caveat with the given id, after checking
+// the password of the named user. The user is named as the identity
+// service knows them, without its domain.
+func ObtainDischarge(location, caveatId, user, password string) (*macaroon.Macaroon, error) {
+	resp, err := http.PostForm(strings.TrimSuffix(location, "/")+"/discharge", url.Values{
+		"id":       {caveatId},
+		"user":     {user},
+		"password": {password},
+	})
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot contact identity service")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		var result struct {
+			Error string
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil || result.Error == "" {
+			return nil, errors.Errorf("identity service failed: %s", resp.Status)
+		}
+		return nil, errors.Errorf("identity service refused discharge: %s", result.Error)
+	}
+	var m macaroon.Macaroon
+	if err := json.NewDecoder(resp.Body).Decode(&m); err != nil {
+		return nil, errors.Annotate(err, "cannot decode discharge")
+	}
+	return &m, nil
+}
+
+// splitCaveat splits a caveat into its name and argument.
+func splitCaveat(caveat string) (name, arg string) {
+	if i := strings.Index(caveat, " "); i >= 0 {
+		return caveat[:i], caveat[i+1:]
+	}
+	return caveat, ""
+}
+
+func randomBytes(n int) ([]byte, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, fmt.Errorf("cannot generate random bytes: %v", err)
+	}
+	return buf, nil
+}
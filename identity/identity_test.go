@@ -0,0 +1,178 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package identity_test
+
+import (
+	stdtesting "testing"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/macaroon.v1"
+
+	"github.com/juju/juju/identity"
+	"github.com/juju/juju/testing"
+)
+
+func TestPackage(t *stdtesting.T) {
+	gc.TestingT(t)
+}
+
+type identitySuite struct {
+	testing.BaseSuite
+	rootKey []byte
+	key     *identity.KeyPair
+}
+
+var _ = gc.Suite(&identitySuite{})
+
+const (
+	testEnvUUID  = "deadbeef-0bad-400d-8000-4b1d0d06f00d"
+	testLocation = "https://sso.invalid"
+)
+
+var testIdentity = identity.Identity{
+	User:   "bob@sso",
+	Groups: []string{"devops", "dba"},
+}
+
+func (s *identitySuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.rootKey = []byte("0123456789abcdef0123456789abcdef")
+	var err error
+	s.key, err = identity.NewKeyPair()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+// newMacaroon returns a new macaroon for the test environment,
+// along with the id of its third party caveat.
+func (s *identitySuite) newMacaroon(c *gc.C) (*macaroon.Macaroon, string) {
+	m, err := identity.NewMacaroon(s.rootKey, testEnvUUID, testLocation, &s.key.Public)
+	c.Assert(err, jc.ErrorIsNil)
+	location, caveatId, err := identity.ThirdPartyCaveat(m)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(location, gc.Equals, testLocation)
+	return m, caveatId
+}
+
+// credentials returns credentials for the test environment, discharged
+// with the given identity and expiry time.
+func (s *identitySuite) credentials(c *gc.C, id *identity.Identity, expires time.Time) string {
+	m, caveatId := s.newMacaroon(c)
+	d, err := identity.Discharge(s.key, caveatId, id, expires)
+	c.Assert(err, jc.ErrorIsNil)
+	credentials, err := identity.EncodeCredentials(m, d)
+	c.Assert(err, jc.ErrorIsNil)
+	return credentials
+}
+
+func (s *identitySuite) TestVerify(c *gc.C) {
+	credentials := s.credentials(c, &testIdentity, time.Now().Add(time.Hour))
+	id, err := identity.Verify(s.rootKey, testEnvUUID, credentials)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(id, jc.DeepEquals, &testIdentity)
+}
+
+func (s *identitySuite) TestVerifyNoGroups(c *gc.C) {
+	credentials := s.credentials(c, &identity.Identity{User: "bob@sso"}, time.Now().Add(time.Hour))
+	id, err := identity.Verify(s.rootKey, testEnvUUID, credentials)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(id.User, gc.Equals, "bob@sso")
+	c.Assert(id.Groups, gc.HasLen, 0)
+}
+
+func (s *identitySuite) TestVerifyWrongEnvironment(c *gc.C) {
+	credentials := s.credentials(c, &testIdentity, time.Now().Add(time.Hour))
+	_, err := identity.Verify(s.rootKey, "some-other-uuid", credentials)
+	c.Assert(err, gc.ErrorMatches, `invalid credentials: .*macaroon is for environment "`+testEnvUUID+`"`)
+}
+
+func (s *identitySuite) TestVerifyExpired(c *gc.C) {
+	credentials := s.credentials(c, &testIdentity, time.Now().Add(-time.Minute))
+	_, err := identity.Verify(s.rootKey, testEnvUUID, credentials)
+	c.Assert(err, gc.ErrorMatches, "invalid credentials: .*macaroon expired at .*")
+}
+
+func (s *identitySuite) TestVerifyWrongRootKey(c *gc.C) {
+	credentials := s.credentials(c, &testIdentity, time.Now().Add(time.Hour))
+	_, err := identity.Verify([]byte("another root key"), testEnvUUID, credentials)
+	c.Assert(err, gc.ErrorMatches, "invalid credentials: .*")
+}
+
+func (s *identitySuite) TestVerifyWithoutDischarge(c *gc.C) {
+	m, _ := s.newMacaroon(c)
+	data, err := m.MarshalJSON()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = identity.Verify(s.rootKey, testEnvUUID, "["+string(data)+"]")
+	c.Assert(err, gc.ErrorMatches, "invalid credentials: .*")
+}
+
+func (s *identitySuite) TestVerifyConflictingDeclarations(c *gc.C) {
+	// Whoever holds a discharge can add caveats to it, but
+	// cannot use them to declare themselves someone else.
+	m, caveatId := s.newMacaroon(c)
+	d, err := identity.Discharge(s.key, caveatId, &testIdentity, time.Now().Add(time.Hour))
+	c.Assert(err, jc.ErrorIsNil)
+	err = d.AddFirstPartyCaveat("declared user alice@sso")
+	c.Assert(err, jc.ErrorIsNil)
+	credentials, err := identity.EncodeCredentials(m, d)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = identity.Verify(s.rootKey, testEnvUUID, credentials)
+	c.Assert(err, gc.ErrorMatches, `invalid credentials: .*user declared as both "bob@sso" and "alice@sso"`)
+}
+
+func (s *identitySuite) TestVerifyMalformed(c *gc.C) {
+	for i, test := range []struct {
+		credentials string
+		err         string
+	}{{
+		credentials: "",
+		err:         "malformed credentials: .*",
+	}, {
+		credentials: "not json",
+		err:         "malformed credentials: .*",
+	}, {
+		credentials: "[]",
+		err:         "malformed credentials: no macaroons",
+	}} {
+		c.Logf("test %d: %q", i, test.credentials)
+		_, err := identity.Verify(s.rootKey, testEnvUUID, test.credentials)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *identitySuite) TestDischargeWrongKey(c *gc.C) {
+	_, caveatId := s.newMacaroon(c)
+	otherKey, err := identity.NewKeyPair()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = identity.Discharge(otherKey, caveatId, &testIdentity, time.Now().Add(time.Hour))
+	c.Assert(err, gc.ErrorMatches, "caveat id not encrypted for this identity service")
+}
+
+func (s *identitySuite) TestDischargeMalformedCaveatId(c *gc.C) {
+	_, err := identity.Discharge(s.key, "!!!", &testIdentity, time.Now().Add(time.Hour))
+	c.Assert(err, gc.ErrorMatches, "malformed caveat id: .*")
+}
+
+func (s *identitySuite) TestThirdPartyCaveatMissing(c *gc.C) {
+	m, err := macaroon.New(s.rootKey, "id", "juju")
+	c.Assert(err, jc.ErrorIsNil)
+	_, _, err = identity.ThirdPartyCaveat(m)
+	c.Assert(err, gc.ErrorMatches, "macaroon has no third party caveat")
+}
+
+func (s *identitySuite) TestParsePublicKey(c *gc.C) {
+	key, err := identity.ParsePublicKey(s.key.Public.String())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(*key, gc.Equals, s.key.Public)
+}
+
+func (s *identitySuite) TestParsePublicKeyErrors(c *gc.C) {
+	_, err := identity.ParsePublicKey("!!!")
+	c.Assert(err, gc.ErrorMatches, "cannot decode public key: .*")
+
+	_, err = identity.ParsePublicKey("AAAA")
+	c.Assert(err, gc.ErrorMatches, "public key has 3 bytes, expected 32")
+}
@@ -0,0 +1,146 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// The testing package provides a stand-in for an external identity
+// service, for use in tests of juju's handling of remote users.
+package testing
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/macaroon.v1"
+
+	"github.com/juju/juju/identity"
+)
+
+// DefaultExpiry holds the lifetime of the discharges
+// issued by a Service unless changed with SetExpiry.
+const DefaultExpiry = time.Hour
+
+// Service is a local stand-in for an external identity service. It
+// holds a set of users with passwords and group memberships, and
+// discharges the third party caveats of juju's macaroons by declaring
+// the identity of one of them. Users are named within the service's
+// domain, so that the user "bob" of a service with the domain "sso" is
+// known to juju as "bob@sso".
+//
+// A Service also implements http.Handler, in the way expected by
+// identity.ObtainDischarge.
+type Service struct {
+	domain string
+	key    *identity.KeyPair
+
+	mu     sync.Mutex
+	users  map[string]user
+	expiry time.Duration
+}
+
+type user struct {
+	password string
+	groups   []string
+}
+
+// NewService returns a new identity service, with a newly
+// generated key pair, that names its users within the given domain.
+func NewService(domain string) (*Service, error) {
+	key, err := identity.NewKeyPair()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &Service{
+		domain: domain,
+		key:    key,
+		users:  make(map[string]user),
+		expiry: DefaultExpiry,
+	}, nil
+}
+
+// PublicKey returns the service's public key, in the form
+// needed for the identity-public-key environment setting.
+func (s *Service) PublicKey() string {
+	return s.key.Public.String()
+}
+
+// AddUser adds a user to the service with the given
+// password and membership of the given groups.
+func (s *Service) AddUser(name, password string, groups ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.users[name] = user{
+		password: password,
+		groups:   groups,
+	}
+}
+
+// SetExpiry sets the lifetime of subsequently issued discharges.
+// A negative lifetime produces discharges that have already expired.
+func (s *Service) SetExpiry(expiry time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.expiry = expiry
+}
+
+// UserName returns the name by which juju knows
+// the service user with the given name.
+func (s *Service) UserName(name string) string {
+	return name + "@" + s.domain
+}
+
+// Discharge discharges the third party caveat with the given id,
+// declaring the identity of the named user. The user's password
+// is not checked.
+func (s *Service) Discharge(name, caveatId string) (*macaroon.Macaroon, error) {
+	s.mu.Lock()
+	u, ok := s.users[name]
+	expiry := s.expiry
+	s.mu.Unlock()
+	if !ok {
+		return nil, errors.NotFoundf("user %q", name)
+	}
+	return identity.Discharge(s.key, caveatId, &identity.Identity{
+		User:   s.UserName(name),
+		Groups: u.groups,
+	}, time.Now().Add(expiry))
+}
+
+// ServeHTTP implements http.Handler. A POST to /discharge with "id",
+// "user" and "password" form values discharges the caveat with that id
+// for that user, returning the discharge macaroon as JSON.
+func (s *Service) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.URL.Path != "/discharge" {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	if req.Method != "POST" {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Sprintf("unsupported method: %q", req.Method))
+		return
+	}
+	name := req.FormValue("user")
+	s.mu.Lock()
+	u, ok := s.users[name]
+	s.mu.Unlock()
+	if !ok || u.password != req.FormValue("password") {
+		writeError(w, http.StatusUnauthorized, "invalid user name or password")
+		return
+	}
+	m, err := s.Discharge(name, req.FormValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(m)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(struct {
+		Error string
+	}{message})
+}
@@ -0,0 +1,102 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package testing_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	stdtesting "testing"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/macaroon.v1"
+
+	"github.com/juju/juju/identity"
+	identitytesting "github.com/juju/juju/identity/testing"
+	coretesting "github.com/juju/juju/testing"
+)
+
+func TestPackage(t *stdtesting.T) {
+	gc.TestingT(t)
+}
+
+type serviceSuite struct {
+	coretesting.BaseSuite
+	svc     *identitytesting.Service
+	server  *httptest.Server
+	rootKey []byte
+}
+
+var _ = gc.Suite(&serviceSuite{})
+
+func (s *serviceSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	var err error
+	s.svc, err = identitytesting.NewService("sso")
+	c.Assert(err, jc.ErrorIsNil)
+	s.svc.AddUser("bob", "secret", "devops")
+	s.server = httptest.NewServer(s.svc)
+	s.AddCleanup(func(*gc.C) { s.server.Close() })
+	s.rootKey = []byte("0123456789abcdef0123456789abcdef")
+}
+
+// newMacaroon returns a macaroon for the environment "some-uuid"
+// whose third party caveat is addressed to the test service.
+func (s *serviceSuite) newMacaroon(c *gc.C) *macaroon.Macaroon {
+	key, err := identity.ParsePublicKey(s.svc.PublicKey())
+	c.Assert(err, jc.ErrorIsNil)
+	m, err := identity.NewMacaroon(s.rootKey, "some-uuid", s.server.URL, key)
+	c.Assert(err, jc.ErrorIsNil)
+	return m
+}
+
+func (s *serviceSuite) TestObtainDischarge(c *gc.C) {
+	m := s.newMacaroon(c)
+	location, caveatId, err := identity.ThirdPartyCaveat(m)
+	c.Assert(err, jc.ErrorIsNil)
+	d, err := identity.ObtainDischarge(location, caveatId, "bob", "secret")
+	c.Assert(err, jc.ErrorIsNil)
+
+	credentials, err := identity.EncodeCredentials(m, d)
+	c.Assert(err, jc.ErrorIsNil)
+	id, err := identity.Verify(s.rootKey, "some-uuid", credentials)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(id, jc.DeepEquals, &identity.Identity{
+		User:   "bob@sso",
+		Groups: []string{"devops"},
+	})
+}
+
+func (s *serviceSuite) TestObtainDischargeBadPassword(c *gc.C) {
+	_, caveatId, err := identity.ThirdPartyCaveat(s.newMacaroon(c))
+	c.Assert(err, jc.ErrorIsNil)
+	for _, test := range []struct {
+		user, password string
+	}{
+		{"bob", "wrong"},
+		{"alice", "secret"},
+	} {
+		_, err := identity.ObtainDischarge(s.server.URL, caveatId, test.user, test.password)
+		c.Check(err, gc.ErrorMatches, "identity service refused discharge: invalid user name or password")
+	}
+}
+
+func (s *serviceSuite) TestServeHTTPErrors(c *gc.C) {
+	resp, err := http.Get(s.server.URL + "/discharge")
+	c.Assert(err, jc.ErrorIsNil)
+	resp.Body.Close()
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusMethodNotAllowed)
+
+	resp, err = http.Post(s.server.URL+"/other", "text/plain", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	resp.Body.Close()
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusNotFound)
+}
+
+func (s *serviceSuite) TestDischargeUnknownUser(c *gc.C) {
+	_, caveatId, err := identity.ThirdPartyCaveat(s.newMacaroon(c))
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.svc.Discharge("alice", caveatId)
+	c.Assert(err, gc.ErrorMatches, `user "alice" not found`)
+}
@@ -13,12 +13,14 @@ const (
 	NONE = ContainerType("none")
 	LXC  = ContainerType("lxc")
 	KVM  = ContainerType("kvm")
+	LXD  = ContainerType("lxd")
 )
 
 // ContainerTypes is used to validate add-machine arguments.
 var ContainerTypes []ContainerType = []ContainerType{
 	LXC,
 	KVM,
+	LXD,
 }
 
 // ParseContainerTypeOrNone converts the specified string into a supported
@@ -87,6 +87,34 @@ func IsRetryableCreationError(err error) bool {
 	return ok
 }
 
+// ZoneConstrainedError reports that an instance could not be created
+// because the availability zones it was attempted in lack capacity for
+// it. Creating the instance in another zone, or again once capacity
+// becomes available, may succeed. Providers returning this error must
+// accept a "zone=<name>" placement directive.
+type ZoneConstrainedError struct {
+	// Zones holds the names of the availability zones
+	// in which the instance could not be created.
+	Zones   []string
+	message string
+}
+
+// Error returns the error message.
+func (e *ZoneConstrainedError) Error() string { return e.message }
+
+// NewZoneConstrainedError returns a new ZoneConstrainedError for
+// the given availability zones.
+func NewZoneConstrainedError(zones []string, errorMessage string) *ZoneConstrainedError {
+	return &ZoneConstrainedError{zones, errorMessage}
+}
+
+// IsZoneConstrainedError returns true if the given error is
+// a ZoneConstrainedError.
+func IsZoneConstrainedError(err error) bool {
+	_, ok := err.(*ZoneConstrainedError)
+	return ok
+}
+
 func (hc HardwareCharacteristics) String() string {
 	var strs []string
 	if hc.Arch != nil {
@@ -13,8 +13,8 @@ import (
 
 	"gopkg.in/mgo.v2"
 
+	"github.com/juju/juju/service"
 	"github.com/juju/juju/service/common"
-	"github.com/juju/juju/service/upstart"
 )
 
 // AdminUser is the name of the user that is initially created in mongo.
@@ -79,8 +79,8 @@ func EnsureAdminUser(p EnsureAdminUserParams) (added bool, err error) {
 	// Login failed, so we need to add the user.
 	// Stop mongo, so we can start it in --noauth mode.
 	mongoServiceName := ServiceName(p.Namespace)
-	mongoService := upstart.NewService(mongoServiceName, common.Conf{})
-	if err := upstartServiceStop(mongoService); err != nil {
+	mongoService := service.NewService(mongoServiceName, common.Conf{})
+	if err := serviceStop(mongoService); err != nil {
 		return false, fmt.Errorf("failed to stop %v: %v", mongoServiceName, err)
 	}
 
@@ -107,7 +107,7 @@ func EnsureAdminUser(p EnsureAdminUserParams) (added bool, err error) {
 	}
 	logger.Infof("added %q to admin database", p.User)
 
-	// Restart mongo using upstart.
+	// Restart mongo using the init system.
 	if err := processSignal(cmd.Process, syscall.SIGTERM); err != nil {
 		return false, fmt.Errorf("cannot kill mongod: %v", err)
 	}
@@ -116,7 +116,7 @@ func EnsureAdminUser(p EnsureAdminUserParams) (added bool, err error) {
 			return false, fmt.Errorf("mongod did not cleanly terminate: %v", err)
 		}
 	}
-	if err := upstartServiceStart(mongoService); err != nil {
+	if err := serviceStart(mongoService); err != nil {
 		return false, err
 	}
 	return true, nil
@@ -16,7 +16,7 @@ import (
 	"gopkg.in/mgo.v2/bson"
 
 	"github.com/juju/juju/mongo"
-	"github.com/juju/juju/service/upstart"
+	"github.com/juju/juju/service"
 	coretesting "github.com/juju/juju/testing"
 )
 
@@ -32,14 +32,14 @@ func (s *adminSuite) SetUpTest(c *gc.C) {
 	s.BaseSuite.SetUpTest(c)
 	s.serviceStarts = 0
 	s.serviceStops = 0
-	s.PatchValue(mongo.UpstartConfInstall, func(conf *upstart.Service) error {
+	s.PatchValue(mongo.ServiceInstall, func(svc service.Service) error {
 		return nil
 	})
-	s.PatchValue(mongo.UpstartServiceStart, func(svc *upstart.Service) error {
+	s.PatchValue(mongo.ServiceStart, func(svc service.Service) error {
 		s.serviceStarts++
 		return nil
 	})
-	s.PatchValue(mongo.UpstartServiceStop, func(svc *upstart.Service) error {
+	s.PatchValue(mongo.ServiceStop, func(svc service.Service) error {
 		s.serviceStops++
 		return nil
 	})
@@ -12,13 +12,13 @@ var (
 	SharedSecretPath = sharedSecretPath
 	SSLKeyPath       = sslKeyPath
 
-	UpstartConfInstall          = &upstartConfInstall
-	UpstartService              = upstartService
-	UpstartServiceExists        = &upstartServiceExists
-	UpstartServiceRunning       = &upstartServiceRunning
-	UpstartServiceStopAndRemove = &upstartServiceStopAndRemove
-	UpstartServiceStop          = &upstartServiceStop
-	UpstartServiceStart         = &upstartServiceStart
+	ServiceInstall       = &serviceInstall
+	ServiceConf          = serviceConf
+	ServiceExists        = &serviceExists
+	ServiceRunning       = &serviceRunning
+	ServiceStopAndRemove = &serviceStopAndRemove
+	ServiceStop          = &serviceStop
+	ServiceStart         = &serviceStart
 
 	HostWordSize   = &hostWordSize
 	RuntimeGOOS    = &runtimeGOOS
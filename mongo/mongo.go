@@ -22,8 +22,8 @@ import (
 
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/replicaset"
+	"github.com/juju/juju/service"
 	"github.com/juju/juju/service/common"
-	"github.com/juju/juju/service/upstart"
 	"github.com/juju/juju/version"
 )
 
@@ -49,12 +49,12 @@ var (
 	// JujuMongodPath holds the default path to the juju-specific mongod.
 	JujuMongodPath = "/usr/lib/juju/bin/mongod"
 
-	upstartConfInstall          = (*upstart.Service).Install
-	upstartServiceExists        = (*upstart.Service).Exists
-	upstartServiceRunning       = (*upstart.Service).Running
-	upstartServiceStopAndRemove = (*upstart.Service).StopAndRemove
-	upstartServiceStop          = (*upstart.Service).Stop
-	upstartServiceStart         = (*upstart.Service).Start
+	serviceInstall       = service.Service.Install
+	serviceExists        = service.Service.Exists
+	serviceRunning       = service.Service.Running
+	serviceStopAndRemove = service.Service.StopAndRemove
+	serviceStop          = service.Service.Stop
+	serviceStart         = service.Service.Start
 
 	// This is NUMACTL package name for apt-get
 	numaCtlPkg = "numactl"
@@ -154,8 +154,8 @@ func Path() (string, error) {
 
 // RemoveService removes the mongoDB init service from this machine.
 func RemoveService(namespace string) error {
-	svc := upstart.NewService(ServiceName(namespace), common.Conf{})
-	return upstartServiceStopAndRemove(svc)
+	svc := service.NewService(ServiceName(namespace), common.Conf{})
+	return serviceStopAndRemove(svc)
 }
 
 // EnsureServerParams is a parameter struct for EnsureServer.
@@ -236,14 +236,12 @@ func EnsureServer(args EnsureServerParams) error {
 	}
 	logVersion(mongoPath)
 
-	svc, err := upstartService(args.Namespace, args.DataDir, dbDir, mongoPath, args.StatePort, oplogSizeMB, args.SetNumaControlPolicy)
-	if err != nil {
-		return err
-	}
-	if upstartServiceExists(svc) {
+	conf := serviceConf(args.DataDir, dbDir, mongoPath, args.StatePort, oplogSizeMB, args.SetNumaControlPolicy)
+	svc := service.NewService(ServiceName(args.Namespace), conf)
+	if serviceExists(svc) {
 		logger.Debugf("mongo exists as expected")
-		if !upstartServiceRunning(svc) {
-			return upstartServiceStart(svc)
+		if !serviceRunning(svc) {
+			return serviceStart(svc)
 		}
 		return nil
 	}
@@ -273,7 +271,7 @@ func EnsureServer(args EnsureServerParams) error {
 		}
 	}
 
-	if err := upstartServiceStop(svc); err != nil {
+	if err := serviceStop(svc); err != nil {
 		return fmt.Errorf("failed to stop mongo: %v", err)
 	}
 	if err := makeJournalDirs(dbDir); err != nil {
@@ -282,7 +280,7 @@ func EnsureServer(args EnsureServerParams) error {
 	if err := preallocOplog(dbDir, oplogSizeMB); err != nil {
 		return fmt.Errorf("error creating oplog files: %v", err)
 	}
-	return upstartConfInstall(svc)
+	return serviceInstall(svc)
 }
 
 // ServiceName returns the name of the init service config for mongo using
@@ -326,10 +324,8 @@ func sharedSecretPath(dataDir string) string {
 	return filepath.Join(dataDir, SharedSecretFile)
 }
 
-// upstartService returns the upstart config for the mongo state service.
-// It also returns the path to the mongod executable that the upstart config
-// will be using.
-func upstartService(namespace, dataDir, dbDir, mongoPath string, port, oplogSizeMB int, wantNumaCtl bool) (*upstart.Service, error) {
+// serviceConf returns the init service config for the mongo state service.
+func serviceConf(dataDir, dbDir, mongoPath string, port, oplogSizeMB int, wantNumaCtl bool) common.Conf {
 	mongoCmd := mongoPath + " --auth" +
 		" --dbpath=" + utils.ShQuote(dbDir) +
 		" --sslOnNormalPorts" +
@@ -358,8 +354,7 @@ func upstartService(namespace, dataDir, dbDir, mongoPath string, port, oplogSize
 		ExtraScript: extraScript,
 		Cmd:         mongoCmd,
 	}
-	svc := upstart.NewService(ServiceName(namespace), conf)
-	return svc, nil
+	return conf
 }
 
 func aptGetInstallMongod(numaCtl bool) error {
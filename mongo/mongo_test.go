@@ -21,7 +21,9 @@ import (
 
 	"github.com/juju/juju/mongo"
 	"github.com/juju/juju/network"
+	"github.com/juju/juju/service"
 	"github.com/juju/juju/service/common"
+	"github.com/juju/juju/service/systemd"
 	"github.com/juju/juju/service/upstart"
 	coretesting "github.com/juju/juju/testing"
 	"github.com/juju/juju/version"
@@ -95,12 +97,16 @@ func (s *MongoSuite) SetUpTest(c *gc.C) {
 	s.mongodConfigPath = filepath.Join(testPath, "mongodConfig")
 	s.PatchValue(mongo.MongoConfigPath, s.mongodConfigPath)
 
-	s.PatchValue(mongo.UpstartConfInstall, func(conf *upstart.Service) error {
-		s.installed = append(s.installed, *conf)
+	// Make sure the tests don't depend on the init system of the host.
+	s.PatchValue(&service.DiscoverInitSystem, func() string {
+		return service.InitSystemUpstart
+	})
+	s.PatchValue(mongo.ServiceInstall, func(svc service.Service) error {
+		s.installed = append(s.installed, *svc.(*upstart.Service))
 		return s.installError
 	})
-	s.PatchValue(mongo.UpstartServiceStopAndRemove, func(svc *upstart.Service) error {
-		s.removed = append(s.removed, *svc)
+	s.PatchValue(mongo.ServiceStopAndRemove, func(svc service.Service) error {
+		s.removed = append(s.removed, *svc.(*upstart.Service))
 		return s.removeError
 	})
 	// Clear out the values that are set by the above patched functions.
@@ -181,13 +187,13 @@ func (s *MongoSuite) TestEnsureServerServerExistsAndRunning(c *gc.C) {
 
 	mockShellCommand(c, &s.CleanupSuite, "apt-get")
 
-	s.PatchValue(mongo.UpstartServiceExists, func(svc *upstart.Service) bool {
+	s.PatchValue(mongo.ServiceExists, func(svc service.Service) bool {
 		return true
 	})
-	s.PatchValue(mongo.UpstartServiceRunning, func(svc *upstart.Service) bool {
+	s.PatchValue(mongo.ServiceRunning, func(svc service.Service) bool {
 		return true
 	})
-	s.PatchValue(mongo.UpstartServiceStart, func(svc *upstart.Service) error {
+	s.PatchValue(mongo.ServiceStart, func(svc service.Service) error {
 		return fmt.Errorf("shouldn't be called")
 	})
 
@@ -202,14 +208,14 @@ func (s *MongoSuite) TestEnsureServerServerExistsNotRunningIsStarted(c *gc.C) {
 
 	mockShellCommand(c, &s.CleanupSuite, "apt-get")
 
-	s.PatchValue(mongo.UpstartServiceExists, func(svc *upstart.Service) bool {
+	s.PatchValue(mongo.ServiceExists, func(svc service.Service) bool {
 		return true
 	})
-	s.PatchValue(mongo.UpstartServiceRunning, func(svc *upstart.Service) bool {
+	s.PatchValue(mongo.ServiceRunning, func(svc service.Service) bool {
 		return false
 	})
 	var started bool
-	s.PatchValue(mongo.UpstartServiceStart, func(svc *upstart.Service) error {
+	s.PatchValue(mongo.ServiceStart, func(svc service.Service) error {
 		started = true
 		return nil
 	})
@@ -226,13 +232,13 @@ func (s *MongoSuite) TestEnsureServerServerExistsNotRunningStartError(c *gc.C) {
 
 	mockShellCommand(c, &s.CleanupSuite, "apt-get")
 
-	s.PatchValue(mongo.UpstartServiceExists, func(svc *upstart.Service) bool {
+	s.PatchValue(mongo.ServiceExists, func(svc service.Service) bool {
 		return true
 	})
-	s.PatchValue(mongo.UpstartServiceRunning, func(svc *upstart.Service) bool {
+	s.PatchValue(mongo.ServiceRunning, func(svc service.Service) bool {
 		return false
 	})
-	s.PatchValue(mongo.UpstartServiceStart, func(svc *upstart.Service) error {
+	s.PatchValue(mongo.ServiceStart, func(svc service.Service) error {
 		return fmt.Errorf("won't start")
 	})
 
@@ -279,6 +285,27 @@ func (s *MongoSuite) testEnsureServerNumaCtl(c *gc.C, setNumaPolicy bool) string
 	return dataDir
 }
 
+func (s *MongoSuite) TestEnsureServerSystemd(c *gc.C) {
+	s.PatchValue(&service.DiscoverInitSystem, func() string {
+		return service.InitSystemSystemd
+	})
+	var installed []service.Service
+	s.PatchValue(mongo.ServiceInstall, func(svc service.Service) error {
+		installed = append(installed, svc)
+		return nil
+	})
+	mockShellCommand(c, &s.CleanupSuite, "apt-get")
+
+	err := mongo.EnsureServer(makeEnsureServerParams(c.MkDir(), "namespace"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(installed, gc.HasLen, 1)
+	svc, ok := installed[0].(*systemd.Service)
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(svc.Name, gc.Equals, "juju-db-namespace")
+	c.Assert(svc.Conf.InitDir, gc.Equals, systemd.InitDir)
+	c.Assert(svc.Conf.Desc, gc.Equals, "juju state database")
+}
+
 func (s *MongoSuite) TestInstallMongod(c *gc.C) {
 	type installs struct {
 		series string
@@ -325,13 +352,13 @@ func (s *MongoSuite) TestInstallMongodServiceExists(c *gc.C) {
 	dataDir := c.MkDir()
 	namespace := "namespace"
 
-	s.PatchValue(mongo.UpstartServiceExists, func(svc *upstart.Service) bool {
+	s.PatchValue(mongo.ServiceExists, func(svc service.Service) bool {
 		return true
 	})
-	s.PatchValue(mongo.UpstartServiceRunning, func(svc *upstart.Service) bool {
+	s.PatchValue(mongo.ServiceRunning, func(svc service.Service) bool {
 		return true
 	})
-	s.PatchValue(mongo.UpstartServiceStart, func(svc *upstart.Service) error {
+	s.PatchValue(mongo.ServiceStart, func(svc service.Service) error {
 		return fmt.Errorf("shouldn't be called")
 	})
 
@@ -344,36 +371,32 @@ func (s *MongoSuite) TestInstallMongodServiceExists(c *gc.C) {
 	c.Assert(cmds, gc.HasLen, 1)
 }
 
-func (s *MongoSuite) TestUpstartServiceWithReplSet(c *gc.C) {
+func (s *MongoSuite) TestServiceConfWithReplSet(c *gc.C) {
 	dataDir := c.MkDir()
 
-	svc, err := mongo.UpstartService("", dataDir, dataDir, mongo.JujuMongodPath, 1234, 1024, false)
-	c.Assert(err, jc.ErrorIsNil)
-	c.Assert(strings.Contains(svc.Conf.Cmd, "--replSet"), jc.IsTrue)
+	conf := mongo.ServiceConf(dataDir, dataDir, mongo.JujuMongodPath, 1234, 1024, false)
+	c.Assert(strings.Contains(conf.Cmd, "--replSet"), jc.IsTrue)
 }
 
-func (s *MongoSuite) TestUpstartServiceWithNumCtl(c *gc.C) {
+func (s *MongoSuite) TestServiceConfWithNumCtl(c *gc.C) {
 	dataDir := c.MkDir()
 
-	svc, err := mongo.UpstartService("", dataDir, dataDir, mongo.JujuMongodPath, 1234, 1024, true)
-	c.Assert(err, jc.ErrorIsNil)
-	c.Assert(svc.Conf.ExtraScript, gc.Not(gc.Matches), "")
+	conf := mongo.ServiceConf(dataDir, dataDir, mongo.JujuMongodPath, 1234, 1024, true)
+	c.Assert(conf.ExtraScript, gc.Not(gc.Matches), "")
 }
 
-func (s *MongoSuite) TestUpstartServiceIPv6(c *gc.C) {
+func (s *MongoSuite) TestServiceConfIPv6(c *gc.C) {
 	dataDir := c.MkDir()
 
-	svc, err := mongo.UpstartService("", dataDir, dataDir, mongo.JujuMongodPath, 1234, 1024, false)
-	c.Assert(err, jc.ErrorIsNil)
-	c.Assert(strings.Contains(svc.Conf.Cmd, "--ipv6"), jc.IsTrue)
+	conf := mongo.ServiceConf(dataDir, dataDir, mongo.JujuMongodPath, 1234, 1024, false)
+	c.Assert(strings.Contains(conf.Cmd, "--ipv6"), jc.IsTrue)
 }
 
-func (s *MongoSuite) TestUpstartServiceWithJournal(c *gc.C) {
+func (s *MongoSuite) TestServiceConfWithJournal(c *gc.C) {
 	dataDir := c.MkDir()
 
-	svc, err := mongo.UpstartService("", dataDir, dataDir, mongo.JujuMongodPath, 1234, 1024, false)
-	c.Assert(err, jc.ErrorIsNil)
-	journalPresent := strings.Contains(svc.Conf.Cmd, " --journal ") || strings.HasSuffix(svc.Conf.Cmd, " --journal")
+	conf := mongo.ServiceConf(dataDir, dataDir, mongo.JujuMongodPath, 1234, 1024, false)
+	journalPresent := strings.Contains(conf.Cmd, " --journal ") || strings.HasSuffix(conf.Cmd, " --journal")
 	c.Assert(journalPresent, jc.IsTrue)
 }
 
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"net/http"
 	"regexp"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -24,6 +25,7 @@ import (
 	"github.com/juju/juju/environs/instances"
 	"github.com/juju/juju/environs/simplestreams"
 	"github.com/juju/juju/environs/storage"
+	"github.com/juju/juju/environs/tags"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/provider/common"
@@ -474,7 +476,7 @@ func (env *azureEnviron) SetConfig(cfg *config.Config) error {
 // name it chooses (based on the given prefix), but recognizes that the name
 // may not be available.  If the name is not available, it does not treat that
 // as an error but just returns nil.
-func attemptCreateService(azure *gwacl.ManagementAPI, prefix, affinityGroupName, label string) (*gwacl.CreateHostedService, error) {
+func attemptCreateService(azure *gwacl.ManagementAPI, prefix, affinityGroupName, label string, properties []gwacl.ExtendedProperty) (*gwacl.CreateHostedService, error) {
 	var err error
 	name := gwacl.MakeRandomHostedServiceName(prefix)
 	err = azure.CheckHostedServiceNameAvailability(name)
@@ -487,6 +489,7 @@ func attemptCreateService(azure *gwacl.ManagementAPI, prefix, affinityGroupName,
 	}
 	req := gwacl.NewCreateHostedServiceWithLocation(name, label, "")
 	req.AffinityGroup = affinityGroupName
+	req.ExtendedProperties = properties
 	err = azure.AddHostedService(req)
 	if err != nil {
 		return nil, err
@@ -494,13 +497,13 @@ func attemptCreateService(azure *gwacl.ManagementAPI, prefix, affinityGroupName,
 	return req, nil
 }
 
-// newHostedService creates a hosted service.  It will make up a unique name,
-// starting with the given prefix.
-func newHostedService(azure *gwacl.ManagementAPI, prefix, affinityGroupName, label string) (*gwacl.HostedService, error) {
+// newHostedService creates a hosted service, with the given extended
+// properties.  It will make up a unique name, starting with the given prefix.
+func newHostedService(azure *gwacl.ManagementAPI, prefix, affinityGroupName, label string, properties []gwacl.ExtendedProperty) (*gwacl.HostedService, error) {
 	var err error
 	var createdService *gwacl.CreateHostedService
 	for tries := 10; tries > 0 && err == nil && createdService == nil; tries-- {
-		createdService, err = attemptCreateService(azure, prefix, affinityGroupName, label)
+		createdService, err = attemptCreateService(azure, prefix, affinityGroupName, label, properties)
 	}
 	if err != nil {
 		return nil, errors.Annotate(err, "could not create hosted service")
@@ -511,6 +514,26 @@ func newHostedService(azure *gwacl.ManagementAPI, prefix, affinityGroupName, lab
 	return azure.GetHostedServiceProperties(createdService.ServiceName, true)
 }
 
+// extendedProperties returns the given tags as the extended properties
+// of a cloud service, sorted by name. Azure only allows letters, digits
+// and underscores in property names, so dashes in the tag keys are
+// replaced with underscores.
+func extendedProperties(resourceTags map[string]string) []gwacl.ExtendedProperty {
+	names := make([]string, 0, len(resourceTags))
+	for key := range resourceTags {
+		names = append(names, key)
+	}
+	sort.Strings(names)
+	properties := make([]gwacl.ExtendedProperty, len(names))
+	for i, key := range names {
+		properties[i] = gwacl.ExtendedProperty{
+			Name:  strings.Replace(key, "-", "_", -1),
+			Value: resourceTags[key],
+		}
+	}
+	return properties
+}
+
 // SupportedArchitectures is specified on the EnvironCapability interface.
 func (env *azureEnviron) SupportedArchitectures() ([]string, error) {
 	env.archMutex.Lock()
@@ -646,7 +669,12 @@ func (env *azureEnviron) createInstance(azure *gwacl.ManagementAPI, role *gwacl.
 		if stateServer {
 			label = stateServerLabel
 		}
-		service, err = newHostedService(azure, env.getEnvPrefix(), env.getAffinityGroupName(), label)
+		// Cloud services may hold the instances of several machines,
+		// so they are tagged only with the environment's resource tags.
+		cfg := env.Config()
+		envUUID, _ := cfg.UUID()
+		properties := extendedProperties(tags.ResourceTags(envUUID, cfg.ResourceTags()))
+		service, err = newHostedService(azure, env.getEnvPrefix(), env.getAffinityGroupName(), label, properties)
 	}
 	if err != nil {
 		return nil, err
@@ -610,7 +610,7 @@ func (*environSuite) TestAttemptCreateServiceCreatesService(c *gc.C) {
 	azure, err := gwacl.NewManagementAPI("subscription", "", "West US")
 	c.Assert(err, jc.ErrorIsNil)
 
-	service, err := attemptCreateService(azure, prefix, affinityGroup, "")
+	service, err := attemptCreateService(azure, prefix, affinityGroup, "", nil)
 	c.Assert(err, jc.ErrorIsNil)
 
 	c.Assert(*requests, gc.HasLen, 2)
@@ -622,6 +622,30 @@ func (*environSuite) TestAttemptCreateServiceCreatesService(c *gc.C) {
 	c.Check(service.Location, gc.Equals, "")
 }
 
+func (*environSuite) TestAttemptCreateServiceSetsExtendedProperties(c *gc.C) {
+	responses := []gwacl.DispatcherResponse{
+		gwacl.NewDispatcherResponse(makeAvailabilityResponse(c), http.StatusOK, nil),
+		gwacl.NewDispatcherResponse(nil, http.StatusOK, nil),
+	}
+	requests := gwacl.PatchManagementAPIResponses(responses)
+	azure, err := gwacl.NewManagementAPI("subscription", "", "West US")
+	c.Assert(err, jc.ErrorIsNil)
+
+	properties := extendedProperties(map[string]string{
+		"juju-env-uuid": "env-uuid",
+		"owner":         "finance",
+	})
+	_, err = attemptCreateService(azure, "service", "affinity-group", "", properties)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(*requests, gc.HasLen, 2)
+	body := parseCreateServiceRequest(c, (*requests)[1])
+	c.Check(body.ExtendedProperties, jc.DeepEquals, []gwacl.ExtendedProperty{
+		{Name: "juju_env_uuid", Value: "env-uuid"},
+		{Name: "owner", Value: "finance"},
+	})
+}
+
 func (*environSuite) TestAttemptCreateServiceReturnsNilIfNameNotUnique(c *gc.C) {
 	responses := []gwacl.DispatcherResponse{
 		gwacl.NewDispatcherResponse(makeNonAvailabilityResponse(c), http.StatusOK, nil),
@@ -630,7 +654,7 @@ func (*environSuite) TestAttemptCreateServiceReturnsNilIfNameNotUnique(c *gc.C)
 	azure, err := gwacl.NewManagementAPI("subscription", "", "West US")
 	c.Assert(err, jc.ErrorIsNil)
 
-	service, err := attemptCreateService(azure, "service", "affinity-group", "")
+	service, err := attemptCreateService(azure, "service", "affinity-group", "", nil)
 	c.Check(err, jc.ErrorIsNil)
 	c.Check(service, gc.IsNil)
 }
@@ -644,7 +668,7 @@ func (*environSuite) TestAttemptCreateServicePropagatesOtherFailure(c *gc.C) {
 	azure, err := gwacl.NewManagementAPI("subscription", "", "West US")
 	c.Assert(err, jc.ErrorIsNil)
 
-	_, err = attemptCreateService(azure, "service", "affinity-group", "")
+	_, err = attemptCreateService(azure, "service", "affinity-group", "", nil)
 	c.Assert(err, gc.NotNil)
 	c.Check(err, gc.ErrorMatches, ".*Not Found.*")
 }
@@ -665,7 +689,7 @@ func (*environSuite) TestNewHostedServiceCreatesService(c *gc.C) {
 	azure, err := gwacl.NewManagementAPI("subscription", "", "West US")
 	c.Assert(err, jc.ErrorIsNil)
 
-	service, err := newHostedService(azure, prefix, affinityGroup, "")
+	service, err := newHostedService(azure, prefix, affinityGroup, "", nil)
 	c.Assert(err, jc.ErrorIsNil)
 
 	c.Assert(*requests, gc.HasLen, 3)
@@ -698,7 +722,7 @@ func (*environSuite) TestNewHostedServiceRetriesIfNotUnique(c *gc.C) {
 	azure, err := gwacl.NewManagementAPI("subscription", "", "West US")
 	c.Assert(err, jc.ErrorIsNil)
 
-	service, err := newHostedService(azure, "service", "affinity-group", "")
+	service, err := newHostedService(azure, "service", "affinity-group", "", nil)
 	c.Check(err, jc.ErrorIsNil)
 
 	c.Assert(*requests, gc.HasLen, 5)
@@ -736,7 +760,7 @@ func (*environSuite) TestNewHostedServiceFailsIfUnableToFindUniqueName(c *gc.C)
 	azure, err := gwacl.NewManagementAPI("subscription", "", "West US")
 	c.Assert(err, jc.ErrorIsNil)
 
-	_, err = newHostedService(azure, "service", "affinity-group", "")
+	_, err = newHostedService(azure, "service", "affinity-group", "", nil)
 	c.Assert(err, gc.NotNil)
 	c.Check(err, gc.ErrorMatches, "could not come up with a unique hosted service name.*")
 }
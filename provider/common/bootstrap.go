@@ -24,6 +24,7 @@ import (
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/cloudinit"
 	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/environs/tags"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/network"
 	coretools "github.com/juju/juju/tools"
@@ -93,12 +94,19 @@ func BootstrapInstance(ctx environs.BootstrapContext, env environs.Environ, args
 	}
 	maybeSetBridge(machineConfig)
 
+	envUUID, _ := env.Config().UUID()
+	instanceTags := tags.InstanceTags(
+		tags.ResourceTags(envUUID, env.Config().ResourceTags()),
+		machineConfig.MachineId, true, nil,
+	)
+
 	fmt.Fprintln(ctx.GetStderr(), "Launching instance")
 	result, err := env.StartInstance(environs.StartInstanceParams{
 		Constraints:   args.Constraints,
 		Tools:         availableTools,
 		MachineConfig: machineConfig,
 		Placement:     args.Placement,
+		InstanceTags:  instanceTags,
 	})
 	if err != nil {
 		return nil, "", nil, errors.Annotate(err, "cannot start bootstrap instance")
@@ -194,6 +194,7 @@ type OpStartInstance struct {
 	APIInfo          *api.Info
 	Secret           string
 	AgentEnvironment map[string]string
+	InstanceTags     map[string]string
 }
 
 type OpStopInstances struct {
@@ -939,6 +940,7 @@ func (e *environ) StartInstance(args environs.StartInstanceParams) (*environs.St
 		Info:             args.MachineConfig.MongoInfo,
 		APIInfo:          args.MachineConfig.APIInfo,
 		AgentEnvironment: args.MachineConfig.AgentEnvironment,
+		InstanceTags:     args.InstanceTags,
 		Secret:           e.ecfg().secret(),
 	}
 	return &environs.StartInstanceResult{
@@ -57,6 +57,12 @@ amazon:
     #
     # enable-os-upgrade: true
 
+    # resource-tags is a space-separated list of key=value tags to set
+    # on the instances started for this environment, in addition to
+    # the juju- tags that juju sets itself.
+    #
+    # resource-tags: owner=finance cost-centre=42
+
 `
 
 var configFields = schema.Fields{
@@ -75,6 +75,7 @@ var _ environs.Environ = (*environ)(nil)
 var _ simplestreams.HasRegion = (*environ)(nil)
 var _ state.Prechecker = (*environ)(nil)
 var _ state.InstanceDistributor = (*environ)(nil)
+var _ environs.InstanceTagger = (*environ)(nil)
 
 type defaultVpc struct {
 	hasDefaultVpc bool
@@ -306,6 +307,10 @@ type ec2Placement struct {
 }
 
 func (e *environ) parsePlacement(placement string) (*ec2Placement, error) {
+	if placement == "spot" || strings.HasPrefix(placement, "spot=") {
+		// The EC2 client library cannot request spot instances.
+		return nil, errors.NotSupportedf("spot instances")
+	}
 	pos := strings.IndexRune(placement, '=')
 	if pos == -1 {
 		return nil, fmt.Errorf("unknown placement directive: %v", placement)
@@ -487,6 +492,7 @@ func (e *environ) StartInstance(args environs.StartInstanceParams) (*environs.St
 	}
 	rootDiskSize := uint64(blockDeviceMappings[0].VolumeSize) * 1024
 
+	var constrainedZones []string
 	for _, availZone := range availabilityZones {
 		instResp, err = runInstances(e.ec2(), &ec2.RunInstances{
 			AvailZone:           availZone,
@@ -500,11 +506,16 @@ func (e *environ) StartInstance(args environs.StartInstanceParams) (*environs.St
 		})
 		if isZoneConstrainedError(err) {
 			logger.Infof("%q is constrained, trying another availability zone", availZone)
+			constrainedZones = append(constrainedZones, availZone)
 		} else {
 			break
 		}
 	}
 	if err != nil {
+		if isZoneConstrainedError(err) {
+			// Let the caller decide whether to try other zones.
+			err = instance.NewZoneConstrainedError(constrainedZones, err.Error())
+		}
 		return nil, errors.Annotate(err, "cannot run instances")
 	}
 	if len(instResp.Instances) != 1 {
@@ -517,9 +528,17 @@ func (e *environ) StartInstance(args environs.StartInstanceParams) (*environs.St
 	}
 	logger.Infof("started instance %q in %q", inst.Id(), inst.Instance.AvailZone)
 
+	// Tagging is best-effort: the instance is running, and failing
+	// here would leave it orphaned.
+	if len(args.InstanceTags) > 0 {
+		if err := tagResources(e.ec2(), args.InstanceTags, string(inst.Id())); err != nil {
+			logger.Warningf("could not tag instance %q: %v", inst.Id(), err)
+		}
+	}
+
 	// TODO(axw) extract volume ID, store in BlockDevice.ProviderId field,
-	// and tag all resources (instances and volumes). We can't do this until
-	// goamz's BlockDeviceMapping structure is updated to include VolumeId.
+	// and tag volumes. We can't do this until goamz's BlockDeviceMapping
+	// structure is updated to include VolumeId.
 
 	if multiwatcher.AnyJobNeedsState(args.MachineConfig.Jobs...) {
 		if err := common.AddStateInstance(e.Storage(), inst.Id()); err != nil {
@@ -558,6 +577,32 @@ func _runInstances(e *ec2.EC2, ri *ec2.RunInstances) (resp *ec2.RunInstancesResp
 	return resp, err
 }
 
+var tagResources = _tagResources
+
+// tagResources calls ec2.CreateTags, tagging each of the given
+// resources with the given tags. Newly created resources may not
+// yet be visible to CreateTags, so the call is retried for a fixed
+// number of attempts while it fails with a "not found" error.
+func _tagResources(e *ec2.EC2, tags map[string]string, resourceIds ...string) error {
+	ec2Tags := make([]ec2.Tag, 0, len(tags))
+	for key, value := range tags {
+		ec2Tags = append(ec2Tags, ec2.Tag{Key: key, Value: value})
+	}
+	var err error
+	for a := shortAttempt.Start(); a.Next(); {
+		_, err = e.CreateTags(resourceIds, ec2Tags)
+		if err == nil || !strings.HasSuffix(ec2ErrCode(err), ".NotFound") {
+			break
+		}
+	}
+	return err
+}
+
+// TagInstance implements environs.InstanceTagger.
+func (e *environ) TagInstance(id instance.Id, tags map[string]string) error {
+	return errors.Annotatef(tagResources(e.ec2(), tags, string(id)), "tagging instance %q", id)
+}
+
 func (e *environ) StopInstances(ids ...instance.Id) error {
 	if err := e.terminateInstances(ids); err != nil {
 		return errors.Trace(err)
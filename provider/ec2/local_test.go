@@ -491,6 +491,64 @@ var azNoDefaultSubnetErr = &amzec2.Error{
 	Message: "No default subnet for availability zone: ''us-east-1e''.",
 }
 
+func (t *localServerSuite) TestStartInstanceTagsInstance(c *gc.C) {
+	env := t.Prepare(c)
+	err := bootstrap.Bootstrap(envtesting.BootstrapContext(c), env, bootstrap.BootstrapParams{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	var tagged []string
+	var tags map[string]string
+	t.PatchValue(ec2.TagResources, func(_ *amzec2.EC2, tagsArg map[string]string, ids ...string) error {
+		tagged = append(tagged, ids...)
+		tags = tagsArg
+		return nil
+	})
+	instanceTags := map[string]string{"juju-machine-id": "1", "owner": "finance"}
+	result, err := testing.StartInstanceWithParams(env, "1", environs.StartInstanceParams{
+		InstanceTags: instanceTags,
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(tagged, gc.DeepEquals, []string{string(result.Instance.Id())})
+	c.Assert(tags, jc.DeepEquals, instanceTags)
+}
+
+func (t *localServerSuite) TestStartInstanceTagFailureIgnored(c *gc.C) {
+	env := t.Prepare(c)
+	err := bootstrap.Bootstrap(envtesting.BootstrapContext(c), env, bootstrap.BootstrapParams{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	t.PatchValue(ec2.TagResources, func(*amzec2.EC2, map[string]string, ...string) error {
+		return fmt.Errorf("no tags for you")
+	})
+	result, err := testing.StartInstanceWithParams(env, "1", environs.StartInstanceParams{
+		InstanceTags: map[string]string{"owner": "finance"},
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Instance, gc.NotNil)
+}
+
+func (t *localServerSuite) TestInstancesTerminatedByCloud(c *gc.C) {
+	env := t.Prepare(c)
+	err := bootstrap.Bootstrap(envtesting.BootstrapContext(c), env, bootstrap.BootstrapParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	inst1, _ := testing.AssertStartInstance(c, env, "1")
+	inst2, _ := testing.AssertStartInstance(c, env, "2")
+
+	// Terminate an instance behind juju's back, as the
+	// cloud does when it reclaims capacity.
+	_, err = ec2.EnvironEC2(env).TerminateInstances([]string{string(inst1.Id())})
+	c.Assert(err, jc.ErrorIsNil)
+
+	insts, err := env.Instances([]instance.Id{inst1.Id(), inst2.Id()})
+	c.Assert(err, gc.Equals, environs.ErrPartialInstances)
+	c.Assert(insts[0], gc.IsNil)
+	c.Assert(insts[1].Id(), gc.Equals, inst2.Id())
+
+	insts, err = env.Instances([]instance.Id{inst1.Id()})
+	c.Assert(err, gc.Equals, environs.ErrNoInstances)
+	c.Assert(insts, gc.HasLen, 0)
+}
+
 func (t *localServerSuite) TestStartInstanceAvailZoneAllConstrained(c *gc.C) {
 	t.testStartInstanceAvailZoneAllConstrained(c, azConstrainedErr)
 }
@@ -527,6 +585,9 @@ func (t *localServerSuite) testStartInstanceAvailZoneAllConstrained(c *gc.C, run
 		runInstancesError.Code,
 	))
 	c.Assert(azArgs, gc.DeepEquals, []string{"az1", "az2"})
+	zoneErr, ok := errors.Cause(err).(*instance.ZoneConstrainedError)
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(zoneErr.Zones, gc.DeepEquals, []string{"az1", "az2"})
 }
 
 func (t *localServerSuite) TestStartInstanceAvailZoneOneConstrained(c *gc.C) {
@@ -675,6 +736,15 @@ func (t *localServerSuite) TestPrecheckInstanceAvailZoneUnknown(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `invalid availability zone "test-unknown"`)
 }
 
+func (t *localServerSuite) TestPrecheckInstanceSpotNotSupported(c *gc.C) {
+	env := t.Prepare(c)
+	for _, placement := range []string{"spot", "spot=0.05"} {
+		err := env.PrecheckInstance(coretesting.FakeDefaultSeries, constraints.Value{}, placement)
+		c.Check(err, gc.ErrorMatches, "spot instances not supported")
+		c.Check(err, jc.Satisfies, errors.IsNotSupported)
+	}
+}
+
 func (t *localServerSuite) TestValidateImageMetadata(c *gc.C) {
 	env := t.Prepare(c)
 	params, err := env.(simplestreams.MetadataValidator).MetadataLookupParams("test")
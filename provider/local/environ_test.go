@@ -31,6 +31,7 @@ import (
 	"github.com/juju/juju/juju/osenv"
 	"github.com/juju/juju/mongo"
 	"github.com/juju/juju/provider/local"
+	"github.com/juju/juju/service"
 	"github.com/juju/juju/service/common"
 	"github.com/juju/juju/service/upstart"
 	"github.com/juju/juju/state/multiwatcher"
@@ -270,6 +271,9 @@ func (s *localJujuTestSuite) makeFakeUpstartScripts(c *gc.C, env environs.Enviro
 ) (mongoService *upstart.Service, machineAgent *upstart.Service) {
 	upstartDir := c.MkDir()
 	s.PatchValue(&upstart.InitDir, upstartDir)
+	s.PatchValue(&service.DiscoverInitSystem, func() string {
+		return service.InitSystemUpstart
+	})
 	s.MakeTool(c, "start", `echo "some-service start/running, process 123"`)
 
 	namespace := env.Config().AllAttrs()["namespace"].(string)
@@ -1055,6 +1055,10 @@ func (environ *maasEnviron) newCloudinitConfig(hostname, primaryIface, series st
 		cloudcfg.AddScripts(
 			runCmd,
 		)
+	case version.CentOS:
+		// The bridge is set up with ifupdown, which CentOS
+		// does not have, so the node keeps its own networking.
+		cloudcfg.AddScripts("set -xe", runCmd)
 	case version.Ubuntu:
 		cloudcfg.SetAptUpdate(true)
 		if on, set := environ.Config().DisableNetworkManagement(); on && set {
@@ -226,6 +226,16 @@ func (*environSuite) TestNewCloudinitConfig(c *gc.C) {
 	c.Assert(cloudcfg.RunCmds(), jc.DeepEquals, expectedCloudinitConfig)
 }
 
+func (*environSuite) TestNewCloudinitConfigCentOS(c *gc.C) {
+	cfg := getSimpleTestConfig(c, nil)
+	env, err := maas.NewEnviron(cfg)
+	c.Assert(err, jc.ErrorIsNil)
+	cloudcfg, err := maas.NewCloudinitConfig(env, "testing.invalid", "eth0", "centos7")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cloudcfg.AptUpdate(), jc.IsFalse)
+	c.Assert(cloudcfg.RunCmds(), jc.DeepEquals, expectedCloudinitConfigWithoutNetworking)
+}
+
 func (*environSuite) TestNewCloudinitConfigWithDisabledNetworkManagement(c *gc.C) {
 	attrs := coretesting.Attrs{
 		"disable-network-management": true,
@@ -1035,6 +1035,9 @@ func (e *environ) StartInstance(args environs.StartInstanceParams) (*environs.St
 	for i, g := range groups {
 		groupNames[i] = nova.SecurityGroupName{g.Name}
 	}
+	// TODO set args.InstanceTags as server metadata, and implement
+	// environs.InstanceTagger, once the goose revision we depend on
+	// supports RunServerOpts.Metadata and SetServerMetadata.
 	var server *nova.Entity
 	for _, availZone := range availabilityZones {
 		var opts = nova.RunServerOpts{
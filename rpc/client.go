@@ -4,6 +4,8 @@
 package rpc
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
 	"strings"
 )
@@ -17,12 +19,19 @@ type Call struct {
 	Response interface{}
 	Error    error
 	Done     chan *Call
+
+	// TraceId holds the trace id sent with the request. If it is
+	// empty when the call is made, the connection's trace id is
+	// used, or if that is not set, a new trace id is generated so
+	// the request can still be found in the server's logs.
+	TraceId string
 }
 
 // RequestError represents an error returned from an RPC request.
 type RequestError struct {
 	Message string
 	Code    string
+	Info    map[string]interface{}
 }
 
 func (e *RequestError) Error() string {
@@ -37,48 +46,129 @@ func (e *RequestError) ErrorCode() string {
 	return e.Code
 }
 
+func (e *RequestError) ErrorInfo() map[string]interface{} {
+	return e.Info
+}
+
+// NewTraceId returns a new random identifier suitable for
+// passing to Conn.SetTraceId or for use as the TraceId of a Call.
+func NewTraceId() string {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		// The trace id is purely informational, so
+		// there's no need to fail if we can't make one.
+		logger.Warningf("cannot generate trace id: %v", err)
+		return ""
+	}
+	return hex.EncodeToString(buf)
+}
+
 func (conn *Conn) send(call *Call) {
 	conn.sending.Lock()
 	defer conn.sending.Unlock()
 
-	// Register this call.
+	hdrs, bodies, ok := conn.register([]*Call{call})
+	if !ok {
+		return
+	}
+	if err := conn.codec.WriteMessage(hdrs[0], bodies[0]); err != nil {
+		conn.abandon(hdrs, err)
+	}
+}
+
+// sendBatch sends all the given calls in a single message if the
+// codec supports it, or one after another if not.
+func (conn *Conn) sendBatch(calls []*Call) {
+	batcher, ok := conn.codec.(BatchWriter)
+	if !ok {
+		for _, call := range calls {
+			conn.send(call)
+		}
+		return
+	}
+	conn.sending.Lock()
+	defer conn.sending.Unlock()
+
+	hdrs, bodies, ok := conn.register(calls)
+	if !ok {
+		return
+	}
+	if err := batcher.WriteBatch(hdrs, bodies); err != nil {
+		conn.abandon(hdrs, err)
+	}
+}
+
+// register allocates request ids for the given calls and adds them
+// to the set of pending calls. It returns the headers and bodies of
+// the requests to send. If the connection is shutting down, the
+// calls are completed with ErrShutdown and register returns false.
+// The caller must hold conn.sending.
+func (conn *Conn) register(calls []*Call) ([]*Header, []interface{}, bool) {
+	conn.mutex.Lock()
+	traceId := conn.traceId
+	conn.mutex.Unlock()
+	for _, call := range calls {
+		if call.TraceId == "" {
+			call.TraceId = traceId
+		}
+		if call.TraceId == "" {
+			call.TraceId = NewTraceId()
+		}
+	}
 	conn.mutex.Lock()
 	if conn.dead == nil {
 		panic("rpc: call made when connection not started")
 	}
 	if conn.closing || conn.shutdown {
-		call.Error = ErrShutdown
 		conn.mutex.Unlock()
-		call.done()
-		return
+		for _, call := range calls {
+			call.Error = ErrShutdown
+			call.done()
+		}
+		return nil, nil, false
+	}
+	hdrs := make([]*Header, len(calls))
+	bodies := make([]interface{}, len(calls))
+	for i, call := range calls {
+		conn.reqId++
+		conn.clientPending[conn.reqId] = call
+		hdrs[i] = &Header{
+			RequestId: conn.reqId,
+			Request:   call.Request,
+			TraceId:   call.TraceId,
+		}
+		bodies[i] = call.Params
+		if bodies[i] == nil {
+			bodies[i] = struct{}{}
+		}
 	}
-	conn.reqId++
-	reqId := conn.reqId
-	conn.clientPending[reqId] = call
 	conn.mutex.Unlock()
 
-	// Encode and send the request.
-	hdr := &Header{
-		RequestId: reqId,
-		Request:   call.Request,
-	}
-	params := call.Params
-	if params == nil {
-		params = struct{}{}
-	}
 	if conn.notifier != nil {
-		conn.notifier.ClientRequest(hdr, params)
+		for i, hdr := range hdrs {
+			conn.notifier.ClientRequest(hdr, bodies[i])
+		}
 	}
-	if err := conn.codec.WriteMessage(hdr, params); err != nil {
-		conn.mutex.Lock()
-		call = conn.clientPending[reqId]
-		delete(conn.clientPending, reqId)
-		conn.mutex.Unlock()
-		if call != nil {
-			call.Error = err
-			call.done()
+	return hdrs, bodies, true
+}
+
+// abandon completes any of the calls with the given request headers
+// that are still pending with the given error. It is used when the
+// requests could not be written.
+func (conn *Conn) abandon(hdrs []*Header, err error) {
+	var calls []*Call
+	conn.mutex.Lock()
+	for _, hdr := range hdrs {
+		if call := conn.clientPending[hdr.RequestId]; call != nil {
+			delete(conn.clientPending, hdr.RequestId)
+			calls = append(calls, call)
 		}
 	}
+	conn.mutex.Unlock()
+	for _, call := range calls {
+		call.Error = err
+		call.done()
+	}
 }
 
 func (conn *Conn) handleResponse(hdr *Header) error {
@@ -111,6 +201,7 @@ func (conn *Conn) handleResponse(hdr *Header) error {
 		call.Error = &RequestError{
 			Message: hdr.Error,
 			Code:    hdr.ErrorCode,
+			Info:    hdr.ErrorInfo,
 		}
 		err = conn.readBody(nil, false)
 		if conn.notifier != nil {
@@ -174,3 +265,23 @@ func (conn *Conn) Go(req Request, args, response interface{}, done chan *Call) *
 	conn.send(call)
 	return call
 }
+
+// GoBatch invokes all the given calls asynchronously. If the codec
+// implements BatchWriter, the requests are sent together in a single
+// message; otherwise they are sent one after another. Each call's
+// Done channel is signalled independently as its reply arrives, so
+// calls may complete in any order. Calls with a nil Done channel are
+// given a new one; as for Go, a non-nil Done channel must be buffered.
+func (conn *Conn) GoBatch(calls []*Call) {
+	if len(calls) == 0 {
+		return
+	}
+	for _, call := range calls {
+		if call.Done == nil {
+			call.Done = make(chan *Call, 1)
+		} else if cap(call.Done) == 0 {
+			panic("github.com/juju/juju/rpc: done channel is unbuffered")
+		}
+	}
+	conn.sendBatch(calls)
+}
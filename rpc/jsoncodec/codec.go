@@ -2,6 +2,7 @@
 package jsoncodec
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -29,7 +30,13 @@ type JSONConn interface {
 type Codec struct {
 	// msg holds the message that's just been read by ReadHeader, so
 	// that the body can be read by ReadBody.
-	msg         inMsg
+	msg inMsg
+
+	// pending holds messages that have been received but not yet
+	// read by ReadHeader. It is only non-empty after receiving
+	// a batch of messages.
+	pending []inMsg
+
 	conn        JSONConn
 	logMessages int32
 	mu          sync.Mutex
@@ -70,20 +77,24 @@ type inMsg struct {
 	Params    json.RawMessage
 	Error     string
 	ErrorCode string
+	ErrorInfo map[string]interface{}
 	Response  json.RawMessage
+	TraceId   string
 }
 
 // outMsg holds an outgoing message.
 type outMsg struct {
 	RequestId uint64
-	Type      string      `json:",omitempty"`
-	Version   int         `json:",omitempty"`
-	Id        string      `json:",omitempty"`
-	Request   string      `json:",omitempty"`
-	Params    interface{} `json:",omitempty"`
-	Error     string      `json:",omitempty"`
-	ErrorCode string      `json:",omitempty"`
-	Response  interface{} `json:",omitempty"`
+	Type      string                 `json:",omitempty"`
+	Version   int                    `json:",omitempty"`
+	Id        string                 `json:",omitempty"`
+	Request   string                 `json:",omitempty"`
+	Params    interface{}            `json:",omitempty"`
+	Error     string                 `json:",omitempty"`
+	ErrorCode string                 `json:",omitempty"`
+	ErrorInfo map[string]interface{} `json:",omitempty"`
+	Response  interface{}            `json:",omitempty"`
+	TraceId   string                 `json:",omitempty"`
 }
 
 func (c *Codec) Close() error {
@@ -101,27 +112,13 @@ func (c *Codec) isClosing() bool {
 
 func (c *Codec) ReadHeader(hdr *rpc.Header) error {
 	c.msg = inMsg{} // avoid any potential cross-message contamination.
-	var err error
-	if c.isLogging() {
-		var m json.RawMessage
-		err = c.conn.Receive(&m)
-		if err == nil {
-			logger.Tracef("<- %s", m)
-			err = json.Unmarshal(m, &c.msg)
-		} else {
-			logger.Tracef("<- error: %v (closing %v)", err, c.isClosing())
-		}
-	} else {
-		err = c.conn.Receive(&c.msg)
-	}
-	if err != nil {
-		// If we've closed the connection, we may get a spurious error,
-		// so ignore it.
-		if c.isClosing() || err == io.EOF {
-			return io.EOF
+	if len(c.pending) == 0 {
+		if err := c.receive(); err != nil {
+			return err
 		}
-		return fmt.Errorf("error receiving message: %v", err)
 	}
+	c.msg, c.pending[0] = c.pending[0], inMsg{}
+	c.pending = c.pending[1:]
 	hdr.RequestId = c.msg.RequestId
 	hdr.Request = rpc.Request{
 		Type:    c.msg.Type,
@@ -131,9 +128,51 @@ func (c *Codec) ReadHeader(hdr *rpc.Header) error {
 	}
 	hdr.Error = c.msg.Error
 	hdr.ErrorCode = c.msg.ErrorCode
+	hdr.ErrorInfo = c.msg.ErrorInfo
+	hdr.TraceId = c.msg.TraceId
 	return nil
 }
 
+// receive reads messages from the connection until at least one
+// message is pending. A single frame holds either one message or,
+// if it was written by WriteBatch, a JSON array of messages.
+func (c *Codec) receive() error {
+	for len(c.pending) == 0 {
+		var m json.RawMessage
+		err := c.conn.Receive(&m)
+		if err == nil {
+			if c.isLogging() {
+				logger.Tracef("<- %s", m)
+			}
+			if isBatch(m) {
+				err = json.Unmarshal(m, &c.pending)
+			} else {
+				c.pending = make([]inMsg, 1)
+				err = json.Unmarshal(m, &c.pending[0])
+			}
+		} else if c.isLogging() {
+			logger.Tracef("<- error: %v (closing %v)", err, c.isClosing())
+		}
+		if err != nil {
+			c.pending = nil
+			// If we've closed the connection, we may get a spurious error,
+			// so ignore it.
+			if c.isClosing() || err == io.EOF {
+				return io.EOF
+			}
+			return fmt.Errorf("error receiving message: %v", err)
+		}
+	}
+	return nil
+}
+
+// isBatch reports whether the given JSON
+// data holds an array of messages.
+func isBatch(m json.RawMessage) bool {
+	m = bytes.TrimLeft(m, " \t\r\n")
+	return len(m) > 0 && m[0] == '['
+}
+
 func (c *Codec) ReadBody(body interface{}, isRequest bool) error {
 	if body == nil {
 		return nil
@@ -181,6 +220,24 @@ func (c *Codec) WriteMessage(hdr *rpc.Header, body interface{}) error {
 	return c.conn.Send(&m)
 }
 
+// WriteBatch implements rpc.BatchWriter by sending
+// all the messages together in a single JSON array.
+func (c *Codec) WriteBatch(hdrs []*rpc.Header, bodies []interface{}) error {
+	msgs := make([]outMsg, len(hdrs))
+	for i, hdr := range hdrs {
+		msgs[i].init(hdr, bodies[i])
+	}
+	if c.isLogging() {
+		data, err := json.Marshal(msgs)
+		if err != nil {
+			logger.Tracef("-> marshal error: %v", err)
+			return err
+		}
+		logger.Tracef("-> %s", data)
+	}
+	return c.conn.Send(msgs)
+}
+
 // init fills out the receiving outMsg with information from the given
 // header and body.
 func (m *outMsg) init(hdr *rpc.Header, body interface{}) {
@@ -191,6 +248,8 @@ func (m *outMsg) init(hdr *rpc.Header, body interface{}) {
 	m.Request = hdr.Request.Action
 	m.Error = hdr.Error
 	m.ErrorCode = hdr.ErrorCode
+	m.ErrorInfo = hdr.ErrorInfo
+	m.TraceId = hdr.TraceId
 	if hdr.IsRequest() {
 		m.Params = body
 	} else {
@@ -6,6 +6,7 @@ import (
 	"io"
 	"reflect"
 	"regexp"
+	"strings"
 	stdtesting "testing"
 
 	"github.com/juju/loggo"
@@ -54,6 +55,15 @@ var readTests = []struct {
 		ErrorCode: "a code",
 	},
 	expectBody: new(map[string]interface{}),
+}, {
+	msg: `{"RequestId": 2, "Error": "an error", "ErrorCode": "a code", "ErrorInfo": {"RetryAfter": 5}}`,
+	expectHdr: rpc.Header{
+		RequestId: 2,
+		Error:     "an error",
+		ErrorCode: "a code",
+		ErrorInfo: map[string]interface{}{"RetryAfter": 5.0},
+	},
+	expectBody: new(map[string]interface{}),
 }, {
 	msg: `{"RequestId": 3, "Response": {"X": "result"}}`,
 	expectHdr: rpc.Header{
@@ -72,6 +82,17 @@ var readTests = []struct {
 		},
 	},
 	expectBody: &value{X: "param"},
+}, {
+	msg: `{"RequestId": 5, "Type": "foo", "Request": "frob", "TraceId": "abc123", "Params": {"X": "param"}}`,
+	expectHdr: rpc.Header{
+		RequestId: 5,
+		Request: rpc.Request{
+			Type:   "foo",
+			Action: "frob",
+		},
+		TraceId: "abc123",
+	},
+	expectBody: &value{X: "param"},
 }}
 
 func (*suite) TestRead(c *gc.C) {
@@ -97,6 +118,59 @@ func (*suite) TestRead(c *gc.C) {
 	}
 }
 
+func (*suite) TestReadBatch(c *gc.C) {
+	codec := jsoncodec.New(&testConn{
+		readMsgs: []string{
+			`[{"RequestId": 1, "Type": "foo", "Request": "frob", "Params": {"X": "one"}},` +
+				`{"RequestId": 2, "Type": "bar", "Version": 1, "Request": "frob", "Params": {"X": "two"}}]`,
+			` []`,
+			`{"RequestId": 3, "Response": {"X": "three"}}`,
+		},
+	})
+	for i, expect := range []struct {
+		hdr  rpc.Header
+		body value
+	}{{
+		hdr: rpc.Header{
+			RequestId: 1,
+			Request:   rpc.Request{Type: "foo", Action: "frob"},
+		},
+		body: value{X: "one"},
+	}, {
+		hdr: rpc.Header{
+			RequestId: 2,
+			Request:   rpc.Request{Type: "bar", Version: 1, Action: "frob"},
+		},
+		body: value{X: "two"},
+	}, {
+		// The empty batch is skipped.
+		hdr:  rpc.Header{RequestId: 3},
+		body: value{X: "three"},
+	}} {
+		c.Logf("message %d", i)
+		var hdr rpc.Header
+		err := codec.ReadHeader(&hdr)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(hdr, gc.DeepEquals, expect.hdr)
+		var body value
+		err = codec.ReadBody(&body, hdr.IsRequest())
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(body, gc.Equals, expect.body)
+	}
+	var hdr rpc.Header
+	err := codec.ReadHeader(&hdr)
+	c.Assert(err, gc.Equals, io.EOF)
+}
+
+func (*suite) TestReadBadBatch(c *gc.C) {
+	codec := jsoncodec.New(&testConn{
+		readMsgs: []string{`[{"RequestId": 1}, "foo"]`},
+	})
+	var hdr rpc.Header
+	err := codec.ReadHeader(&hdr)
+	c.Assert(err, gc.ErrorMatches, "error receiving message: json: cannot unmarshal string into .*")
+}
+
 func (*suite) TestReadHeaderLogsRequests(c *gc.C) {
 	codecLogger := loggo.GetLogger("juju.rpc.jsoncodec")
 	defer codecLogger.SetLogLevel(codecLogger.LogLevel())
@@ -237,6 +311,14 @@ var writeTests = []struct {
 		ErrorCode: "a code",
 	},
 	expect: `{"RequestId": 2, "Error": "an error", "ErrorCode": "a code"}`,
+}, {
+	hdr: &rpc.Header{
+		RequestId: 2,
+		Error:     "an error",
+		ErrorCode: "a code",
+		ErrorInfo: map[string]interface{}{"RetryAfter": 5},
+	},
+	expect: `{"RequestId": 2, "Error": "an error", "ErrorCode": "a code", "ErrorInfo": {"RetryAfter": 5}}`,
 }, {
 	hdr: &rpc.Header{
 		RequestId: 3,
@@ -255,6 +337,13 @@ var writeTests = []struct {
 	},
 	body:   &value{X: "param"},
 	expect: `{"RequestId": 4, "Type": "foo", "Version": 2, "Request": "frob", "Params": {"X": "param"}}`,
+}, {
+	hdr: &rpc.Header{
+		RequestId: 5,
+		TraceId:   "abc123",
+	},
+	body:   &value{X: "result"},
+	expect: `{"RequestId": 5, "TraceId": "abc123", "Response": {"X": "result"}}`,
 }}
 
 func (*suite) TestWrite(c *gc.C) {
@@ -270,6 +359,51 @@ func (*suite) TestWrite(c *gc.C) {
 	}
 }
 
+func (*suite) TestWriteBatch(c *gc.C) {
+	var conn testConn
+	codec := jsoncodec.New(&conn)
+	var hdrs []*rpc.Header
+	var bodies []interface{}
+	var expect []string
+	for _, test := range writeTests {
+		hdrs = append(hdrs, test.hdr)
+		bodies = append(bodies, test.body)
+		expect = append(expect, test.expect)
+	}
+	err := codec.WriteBatch(hdrs, bodies)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(conn.writeMsgs, gc.HasLen, 1)
+	assertJSONEqual(c, conn.writeMsgs[0], "["+strings.Join(expect, ",")+"]")
+}
+
+func (*suite) TestWriteBatchReadBack(c *gc.C) {
+	var conn testConn
+	codec := jsoncodec.New(&conn)
+	err := codec.WriteBatch([]*rpc.Header{{
+		RequestId: 1,
+		Request:   rpc.Request{Type: "foo", Action: "frob"},
+	}, {
+		RequestId: 2,
+		Request:   rpc.Request{Type: "foo", Action: "frob"},
+	}}, []interface{}{
+		&value{X: "one"},
+		&value{X: "two"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	codec = jsoncodec.New(&testConn{readMsgs: conn.writeMsgs})
+	for i, x := range []string{"one", "two"} {
+		var hdr rpc.Header
+		err := codec.ReadHeader(&hdr)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(hdr.RequestId, gc.Equals, uint64(i+1))
+		var body value
+		err = codec.ReadBody(&body, true)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(body.X, gc.Equals, x)
+	}
+}
+
 var dumpRequestTests = []struct {
 	hdr    rpc.Header
 	body   interface{}
@@ -488,9 +488,9 @@ func (root *Root) testCall(c *gc.C, p testCallParams) {
 
 	root.assertCallMade(c, p)
 
-	requestId := root.assertClientNotified(c, p, &r)
+	requestId, traceId := root.assertClientNotified(c, p, &r)
 
-	root.assertServerNotified(c, p, requestId)
+	root.assertServerNotified(c, p, requestId, traceId)
 }
 
 func (root *Root) assertCallMade(c *gc.C, p testCallParams) {
@@ -508,8 +508,8 @@ func (root *Root) assertCallMade(c *gc.C, p testCallParams) {
 // assertClientNotified asserts that the right client notifications
 // were made for the given test call parameters. The value of r
 // holds the result parameter passed to the call.
-// It returns the request id.
-func (root *Root) assertClientNotified(c *gc.C, p testCallParams, r interface{}) uint64 {
+// It returns the request id and trace id.
+func (root *Root) assertClientNotified(c *gc.C, p testCallParams, r interface{}) (uint64, string) {
 	c.Assert(p.clientNotifier.serverRequests, gc.HasLen, 0)
 	c.Assert(p.clientNotifier.serverReplies, gc.HasLen, 0)
 
@@ -517,7 +517,11 @@ func (root *Root) assertClientNotified(c *gc.C, p testCallParams, r interface{})
 	c.Assert(p.clientNotifier.clientRequests, gc.HasLen, 1)
 	clientReq := p.clientNotifier.clientRequests[0]
 	requestId := clientReq.hdr.RequestId
-	clientReq.hdr.RequestId = 0 // Ignore the exact value of the request id to start with.
+	traceId := clientReq.hdr.TraceId
+	c.Assert(traceId, gc.Matches, "[0-9a-f]{16}")
+	// Ignore the exact values of the request and trace ids to start with.
+	clientReq.hdr.RequestId = 0
+	clientReq.hdr.TraceId = ""
 	c.Assert(clientReq.hdr, gc.DeepEquals, rpc.Header{
 		Request: p.request(),
 	})
@@ -536,19 +540,21 @@ func (root *Root) assertClientNotified(c *gc.C, p testCallParams, r interface{})
 		c.Assert(clientReply.hdr, gc.DeepEquals, rpc.Header{
 			RequestId: requestId,
 			Error:     p.errorMessage(),
+			TraceId:   traceId,
 		})
 	} else {
 		c.Assert(clientReply.hdr, gc.DeepEquals, rpc.Header{
 			RequestId: requestId,
+			TraceId:   traceId,
 		})
 	}
-	return requestId
+	return requestId, traceId
 }
 
 // assertServerNotified asserts that the right server notifications
 // were made for the given test call parameters. The id of the request
-// is held in requestId.
-func (root *Root) assertServerNotified(c *gc.C, p testCallParams, requestId uint64) {
+// and its trace id are held in requestId and traceId.
+func (root *Root) assertServerNotified(c *gc.C, p testCallParams, requestId uint64, traceId string) {
 	// Check that the right server notifications were made.
 	c.Assert(p.serverNotifier.clientRequests, gc.HasLen, 0)
 	c.Assert(p.serverNotifier.clientReplies, gc.HasLen, 0)
@@ -559,6 +565,7 @@ func (root *Root) assertServerNotified(c *gc.C, p testCallParams, requestId uint
 	c.Assert(serverReq.hdr, gc.DeepEquals, rpc.Header{
 		RequestId: requestId,
 		Request:   p.request(),
+		TraceId:   traceId,
 	})
 	if p.narg > 0 {
 		c.Assert(serverReq.body, gc.Equals, stringVal{"arg"})
@@ -576,13 +583,15 @@ func (root *Root) assertServerNotified(c *gc.C, p testCallParams, requestId uint
 		c.Assert(serverReply.body, gc.Equals, stringVal{p.request().Action + " ret"})
 	}
 	if p.retErr && p.testErr {
-		c.Assert(serverReply.hdr, gc.Equals, rpc.Header{
+		c.Assert(serverReply.hdr, gc.DeepEquals, rpc.Header{
 			RequestId: requestId,
 			Error:     p.errorMessage(),
+			TraceId:   traceId,
 		})
 	} else {
-		c.Assert(serverReply.hdr, gc.Equals, rpc.Header{
+		c.Assert(serverReply.hdr, gc.DeepEquals, rpc.Header{
 			RequestId: requestId,
+			TraceId:   traceId,
 		})
 	}
 }
@@ -749,6 +758,91 @@ func (*rpcSuite) TestConcurrentCalls(c *gc.C) {
 	chanRead(c, done2, "method 2 done")
 }
 
+func (*rpcSuite) TestGoBatch(c *gc.C) {
+	start1 := make(chan string)
+	start2 := make(chan string)
+	ready1 := make(chan struct{})
+	ready2 := make(chan struct{})
+
+	root := &Root{
+		delayed: map[string]*DelayedMethods{
+			"1": {ready: ready1, done: start1},
+			"2": {ready: ready2, done: start2},
+		},
+		errorInst: &ErrorMethods{&codedError{"message", "code"}},
+	}
+	client, srvDone, _, serverNotifier := newRPCClientServer(c, root, nil, false)
+	defer closeClient(c, client, srvDone)
+
+	var r1, r2 stringVal
+	calls := []*rpc.Call{{
+		Request:  rpc.Request{"DelayedMethods", 0, "1", "Delay"},
+		Response: &r1,
+	}, {
+		Request:  rpc.Request{"DelayedMethods", 0, "2", "Delay"},
+		Response: &r2,
+	}, {
+		Request: rpc.Request{"ErrorMethods", 0, "", "Call"},
+	}}
+	client.GoBatch(calls)
+
+	// Check that both delayed calls are running concurrently.
+	chanRead(c, ready1, "method 1 ready")
+	chanRead(c, ready2, "method 2 ready")
+
+	// The error reply doesn't wait for the others.
+	call := chanReadCall(c, calls[2].Done, "error call done")
+	c.Assert(call.Error, gc.ErrorMatches, "request error: message \\(code\\)")
+
+	// Replies are delivered as they arrive, regardless of
+	// the order the calls were made in.
+	start2 <- "return 2"
+	call = chanReadCall(c, calls[1].Done, "method 2 done")
+	c.Assert(call.Error, jc.ErrorIsNil)
+	c.Assert(r2.Val, gc.Equals, "return 2")
+	select {
+	case <-calls[0].Done:
+		c.Fatalf("method 1 completed early")
+	default:
+	}
+	start1 <- "return 1"
+	call = chanReadCall(c, calls[0].Done, "method 1 done")
+	c.Assert(call.Error, jc.ErrorIsNil)
+	c.Assert(r1.Val, gc.Equals, "return 1")
+
+	serverNotifier.mu.Lock()
+	defer serverNotifier.mu.Unlock()
+	c.Assert(serverNotifier.serverRequests, gc.HasLen, 3)
+	for i, req := range serverNotifier.serverRequests {
+		c.Check(req.hdr.Request, gc.Equals, calls[i].Request)
+	}
+}
+
+func (*rpcSuite) TestGoBatchUnbufferedDoneChannel(c *gc.C) {
+	client, srvDone, _, _ := newRPCClientServer(c, SimpleRoot(), nil, false)
+	defer closeClient(c, client, srvDone)
+	calls := []*rpc.Call{{
+		Request: rpc.Request{"SimpleMethods", 0, "a99", "Call0r0"},
+		Done:    make(chan *rpc.Call),
+	}}
+	c.Assert(func() { client.GoBatch(calls) }, gc.PanicMatches, ".*done channel is unbuffered")
+}
+
+func (*rpcSuite) TestGoBatchAfterClose(c *gc.C) {
+	client, srvDone, _, _ := newRPCClientServer(c, SimpleRoot(), nil, false)
+	closeClient(c, client, srvDone)
+	calls := []*rpc.Call{{
+		Request: rpc.Request{"SimpleMethods", 0, "a99", "Call0r0"},
+	}, {
+		Request: rpc.Request{"SimpleMethods", 0, "a99", "Call0r1"},
+	}}
+	client.GoBatch(calls)
+	for i, call := range calls {
+		call = chanReadCall(c, call.Done, fmt.Sprintf("call %d done", i))
+		c.Assert(call.Error, gc.Equals, rpc.ErrShutdown)
+	}
+}
+
 type codedError struct {
 	m    string
 	code string
@@ -773,6 +867,106 @@ func (*rpcSuite) TestErrorCode(c *gc.C) {
 	c.Assert(err.(rpc.ErrorCoder).ErrorCode(), gc.Equals, "code")
 }
 
+type infoError struct {
+	codedError
+	info map[string]interface{}
+}
+
+func (e *infoError) ErrorInfo() map[string]interface{} {
+	return e.info
+}
+
+func (*rpcSuite) TestErrorInfo(c *gc.C) {
+	root := &Root{
+		errorInst: &ErrorMethods{&infoError{
+			codedError{"message", "code"},
+			map[string]interface{}{"delay": 3},
+		}},
+	}
+	client, srvDone, _, _ := newRPCClientServer(c, root, nil, false)
+	defer closeClient(c, client, srvDone)
+	err := client.Call(rpc.Request{"ErrorMethods", 0, "", "Call"}, nil, nil)
+	c.Assert(err, gc.ErrorMatches, `request error: message \(code\)`)
+	c.Assert(err.(rpc.ErrorInfoer).ErrorInfo(), jc.DeepEquals, map[string]interface{}{"delay": 3.0})
+}
+
+func (*rpcSuite) TestTraceId(c *gc.C) {
+	root := SimpleRoot()
+	client, srvDone, _, serverNotifier := newRPCClientServer(c, root, nil, false)
+	defer closeClient(c, client, srvDone)
+
+	req := rpc.Request{"SimpleMethods", 0, "a99", "Call0r0"}
+	calls := []*rpc.Call{
+		{Request: req, TraceId: "deadbeef"},
+		{Request: req},
+		{Request: req},
+	}
+	client.GoBatch(calls)
+	for i, call := range calls {
+		call = chanReadCall(c, call.Done, fmt.Sprintf("call %d done", i))
+		c.Assert(call.Error, jc.ErrorIsNil)
+	}
+	// A trace id given by the caller is used; each
+	// other request is given a new one of its own.
+	c.Assert(calls[0].TraceId, gc.Equals, "deadbeef")
+	c.Assert(calls[1].TraceId, gc.Matches, "[0-9a-f]{16}")
+	c.Assert(calls[2].TraceId, gc.Matches, "[0-9a-f]{16}")
+	c.Assert(calls[1].TraceId, gc.Not(gc.Equals), calls[2].TraceId)
+
+	serverNotifier.mu.Lock()
+	defer serverNotifier.mu.Unlock()
+	c.Assert(serverNotifier.serverRequests, gc.HasLen, 3)
+	c.Assert(serverNotifier.serverReplies, gc.HasLen, 3)
+	requestTraceIds := make(map[uint64]string)
+	for i, call := range calls {
+		hdr := serverNotifier.serverRequests[i].hdr
+		c.Check(hdr.TraceId, gc.Equals, call.TraceId)
+		requestTraceIds[hdr.RequestId] = hdr.TraceId
+	}
+	for _, reply := range serverNotifier.serverReplies {
+		c.Check(reply.hdr.TraceId, gc.Equals, requestTraceIds[reply.hdr.RequestId])
+	}
+}
+
+func (*rpcSuite) TestConnTraceId(c *gc.C) {
+	root := SimpleRoot()
+	client, srvDone, _, serverNotifier := newRPCClientServer(c, root, nil, false)
+	defer closeClient(c, client, srvDone)
+
+	client.SetTraceId("cafebabe")
+	c.Assert(client.TraceId(), gc.Equals, "cafebabe")
+	req := rpc.Request{"SimpleMethods", 0, "a99", "Call0r0"}
+	calls := []*rpc.Call{
+		{Request: req, TraceId: "deadbeef"},
+		{Request: req},
+		{Request: req},
+	}
+	client.GoBatch(calls)
+	for i, call := range calls {
+		call = chanReadCall(c, call.Done, fmt.Sprintf("call %d done", i))
+		c.Assert(call.Error, jc.ErrorIsNil)
+	}
+	// A trace id given by the caller is used; the
+	// other requests share the connection's trace id.
+	c.Assert(calls[0].TraceId, gc.Equals, "deadbeef")
+	c.Assert(calls[1].TraceId, gc.Equals, "cafebabe")
+	c.Assert(calls[2].TraceId, gc.Equals, "cafebabe")
+
+	serverNotifier.mu.Lock()
+	defer serverNotifier.mu.Unlock()
+	c.Assert(serverNotifier.serverRequests, gc.HasLen, 3)
+	for i, call := range calls {
+		c.Check(serverNotifier.serverRequests[i].hdr.TraceId, gc.Equals, call.TraceId)
+	}
+}
+
+func (*rpcSuite) TestNewTraceId(c *gc.C) {
+	id0 := rpc.NewTraceId()
+	id1 := rpc.NewTraceId()
+	c.Assert(id0, gc.Matches, "[0-9a-f]{16}")
+	c.Assert(id0, gc.Not(gc.Equals), id1)
+}
+
 func (*rpcSuite) TestTransformErrors(c *gc.C) {
 	root := &Root{
 		errorInst: &ErrorMethods{&codedError{"message", "code"}},
@@ -957,10 +1151,12 @@ func testBadCall(
 	c.Assert(clientNotifier.clientRequests, gc.HasLen, 1)
 	clientReq := clientNotifier.clientRequests[0]
 	requestId := clientReq.hdr.RequestId
+	traceId := clientReq.hdr.TraceId
 	c.Assert(clientReq, gc.DeepEquals, requestEvent{
 		hdr: rpc.Header{
 			RequestId: requestId,
 			Request:   req,
+			TraceId:   traceId,
 		},
 		body: struct{}{},
 	})
@@ -973,6 +1169,7 @@ func testBadCall(
 			RequestId: requestId,
 			Error:     expectedErr,
 			ErrorCode: expectedErrCode,
+			TraceId:   traceId,
 		},
 	})
 
@@ -991,6 +1188,7 @@ func testBadCall(
 		hdr: rpc.Header{
 			RequestId: requestId,
 			Request:   req,
+			TraceId:   traceId,
 		},
 		body: expectBody,
 	})
@@ -1003,6 +1201,7 @@ func testBadCall(
 			RequestId: requestId,
 			Error:     expectedErr,
 			ErrorCode: expectedErrCode,
+			TraceId:   traceId,
 		},
 		req:  req,
 		body: struct{}{},
@@ -1177,6 +1376,16 @@ func chanReadError(c *gc.C, ch <-chan error, what string) error {
 	panic("unreachable")
 }
 
+func chanReadCall(c *gc.C, ch <-chan *rpc.Call, what string) *rpc.Call {
+	select {
+	case call := <-ch:
+		return call
+	case <-time.After(3 * time.Second):
+		c.Fatalf("timeout on channel read %s", what)
+	}
+	panic("unreachable")
+}
+
 // newRPCClientServer starts an RPC server serving a connection from a
 // single client.  When the server has finished serving the connection,
 // it sends a value on the returned channel.
@@ -1256,6 +1465,19 @@ func (c *testCodec) WriteMessage(hdr *rpc.Header, x interface{}) error {
 	return c.Codec.WriteMessage(hdr, x)
 }
 
+func (c *testCodec) WriteBatch(hdrs []*rpc.Header, bodies []interface{}) error {
+	for i, hdr := range hdrs {
+		if reflect.ValueOf(bodies[i]).Kind() != reflect.Struct {
+			panic(fmt.Errorf("WriteBatch bad param; want struct got %T (%#v)", bodies[i], bodies[i]))
+		}
+		if c.role != roleBoth && hdr.IsRequest() != (c.role == roleClient) {
+			panic(fmt.Errorf("codec role %v; header wrong type %#v", c.role, hdr))
+		}
+		logger.Infof("send batched header: %#v; body: %#v", hdr, bodies[i])
+	}
+	return c.Codec.(rpc.BatchWriter).WriteBatch(hdrs, bodies)
+}
+
 func (c *testCodec) ReadHeader(hdr *rpc.Header) error {
 	err := c.Codec.ReadHeader(hdr)
 	if err != nil {
@@ -0,0 +1,165 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// The rpcmetrics package records call counts, error counts and
+// latency histograms for the requests served by an rpc.Conn.
+package rpcmetrics
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/juju/juju/rpc"
+)
+
+// LatencyBuckets holds the upper bounds of the buckets used
+// to build latency histograms. A request is counted in the
+// first bucket whose bound is greater than or equal to the
+// time spent serving it; requests slower than the last bound
+// are counted in an extra overflow bucket.
+var LatencyBuckets = []time.Duration{
+	time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	5 * time.Second,
+	10 * time.Second,
+}
+
+// Method identifies a single method on a versioned facade.
+type Method struct {
+	Facade  string
+	Version int
+	Method  string
+}
+
+// UnknownMethod is the Method under which calls of methods
+// that could not be found are recorded.
+var UnknownMethod = Method{Facade: "<unknown>"}
+
+// MethodStats holds the statistics recorded for a single method.
+type MethodStats struct {
+	Method
+
+	// Calls holds the number of completed calls.
+	Calls int64
+
+	// Errors holds the number of calls that returned an error.
+	Errors int64
+
+	// TotalTime holds the sum of the time spent serving all calls.
+	TotalTime time.Duration
+
+	// MaxTime holds the time spent serving the slowest call.
+	MaxTime time.Duration
+
+	// Buckets holds the latency histogram. It has one entry
+	// for each entry in LatencyBuckets, followed by the
+	// overflow bucket.
+	Buckets []int64
+}
+
+// Recorder accumulates statistics about RPC requests.
+// It is safe to call its methods concurrently.
+type Recorder struct {
+	mu      sync.Mutex
+	methods map[Method]*MethodStats
+}
+
+// NewRecorder returns a new Recorder with no recorded calls.
+func NewRecorder() *Recorder {
+	return &Recorder{
+		methods: make(map[Method]*MethodStats),
+	}
+}
+
+// Record records a completed call of the given request that
+// took timeSpent to serve. If failed is true, the call is also
+// counted as an error. The request must be of a method known
+// to the server; use RecordUnknown for any other request.
+func (r *Recorder) Record(req rpc.Request, timeSpent time.Duration, failed bool) {
+	r.record(Method{
+		Facade:  req.Type,
+		Version: req.Version,
+		Method:  req.Action,
+	}, timeSpent, failed)
+}
+
+// RecordUnknown records a failed call of a method that could not be
+// found, which took timeSpent to serve. All such calls are recorded
+// together under UnknownMethod, so that clients cannot make the
+// recorded statistics grow without bound.
+func (r *Recorder) RecordUnknown(timeSpent time.Duration) {
+	r.record(UnknownMethod, timeSpent, true)
+}
+
+func (r *Recorder) record(key Method, timeSpent time.Duration, failed bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stats, ok := r.methods[key]
+	if !ok {
+		stats = &MethodStats{
+			Method:  key,
+			Buckets: make([]int64, len(LatencyBuckets)+1),
+		}
+		r.methods[key] = stats
+	}
+	stats.Calls++
+	if failed {
+		stats.Errors++
+	}
+	stats.TotalTime += timeSpent
+	if timeSpent > stats.MaxTime {
+		stats.MaxTime = timeSpent
+	}
+	stats.Buckets[bucketIndex(timeSpent)]++
+}
+
+// bucketIndex returns the index of the histogram
+// bucket that counts requests taking d to serve.
+func bucketIndex(d time.Duration) int {
+	return sort.Search(len(LatencyBuckets), func(i int) bool {
+		return LatencyBuckets[i] >= d
+	})
+}
+
+// Stats returns a copy of the statistics recorded so far,
+// ordered by facade, version and method.
+func (r *Recorder) Stats() []MethodStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	result := make([]MethodStats, 0, len(r.methods))
+	for _, stats := range r.methods {
+		s := *stats
+		s.Buckets = append([]int64(nil), stats.Buckets...)
+		result = append(result, s)
+	}
+	sort.Sort(byMethod(result))
+	return result
+}
+
+// Reset discards all recorded statistics.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.methods = make(map[Method]*MethodStats)
+}
+
+type byMethod []MethodStats
+
+func (s byMethod) Len() int      { return len(s) }
+func (s byMethod) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s byMethod) Less(i, j int) bool {
+	a, b := s[i].Method, s[j].Method
+	if a.Facade != b.Facade {
+		return a.Facade < b.Facade
+	}
+	if a.Version != b.Version {
+		return a.Version < b.Version
+	}
+	return a.Method < b.Method
+}
@@ -0,0 +1,92 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package rpcmetrics_test
+
+import (
+	stdtesting "testing"
+	"time"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/rpc"
+	"github.com/juju/juju/rpc/rpcmetrics"
+	"github.com/juju/juju/testing"
+)
+
+func TestPackage(t *stdtesting.T) {
+	gc.TestingT(t)
+}
+
+type recorderSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&recorderSuite{})
+
+func (*recorderSuite) TestRecord(c *gc.C) {
+	r := rpcmetrics.NewRecorder()
+	req := rpc.Request{Type: "Client", Version: 0, Action: "FullStatus"}
+	r.Record(req, 3*time.Millisecond, false)
+	r.Record(req, 200*time.Millisecond, true)
+	r.Record(req, time.Minute, false)
+
+	stats := r.Stats()
+	c.Assert(stats, gc.HasLen, 1)
+	s := stats[0]
+	c.Check(s.Method, gc.Equals, rpcmetrics.Method{"Client", 0, "FullStatus"})
+	c.Check(s.Calls, gc.Equals, int64(3))
+	c.Check(s.Errors, gc.Equals, int64(1))
+	c.Check(s.TotalTime, gc.Equals, time.Minute+203*time.Millisecond)
+	c.Check(s.MaxTime, gc.Equals, time.Minute)
+	c.Check(s.Buckets, gc.DeepEquals, []int64{0, 1, 0, 0, 0, 1, 0, 0, 0, 1})
+}
+
+func (*recorderSuite) TestRecordUnknown(c *gc.C) {
+	r := rpcmetrics.NewRecorder()
+	r.RecordUnknown(time.Millisecond)
+	r.RecordUnknown(time.Second)
+
+	stats := r.Stats()
+	c.Assert(stats, gc.HasLen, 1)
+	s := stats[0]
+	c.Check(s.Method, gc.Equals, rpcmetrics.UnknownMethod)
+	c.Check(s.Calls, gc.Equals, int64(2))
+	c.Check(s.Errors, gc.Equals, int64(2))
+	c.Check(s.Buckets, gc.DeepEquals, []int64{1, 0, 0, 0, 0, 0, 1, 0, 0, 0})
+}
+
+func (*recorderSuite) TestStatsOrdered(c *gc.C) {
+	r := rpcmetrics.NewRecorder()
+	r.Record(rpc.Request{Type: "Uniter", Version: 2, Action: "Life"}, 0, false)
+	r.Record(rpc.Request{Type: "Client", Version: 0, Action: "Status"}, 0, false)
+	r.Record(rpc.Request{Type: "Uniter", Version: 1, Action: "Life"}, 0, false)
+	r.Record(rpc.Request{Type: "Client", Version: 0, Action: "FullStatus"}, 0, false)
+
+	var methods []rpcmetrics.Method
+	for _, s := range r.Stats() {
+		methods = append(methods, s.Method)
+	}
+	c.Assert(methods, gc.DeepEquals, []rpcmetrics.Method{
+		{"Client", 0, "FullStatus"},
+		{"Client", 0, "Status"},
+		{"Uniter", 1, "Life"},
+		{"Uniter", 2, "Life"},
+	})
+}
+
+func (*recorderSuite) TestStatsIsCopy(c *gc.C) {
+	r := rpcmetrics.NewRecorder()
+	req := rpc.Request{Type: "Client", Action: "Status"}
+	r.Record(req, 0, false)
+	stats := r.Stats()
+	stats[0].Buckets[0] = 99
+	c.Assert(r.Stats()[0].Buckets[0], gc.Equals, int64(1))
+}
+
+func (*recorderSuite) TestReset(c *gc.C) {
+	r := rpcmetrics.NewRecorder()
+	r.Record(rpc.Request{Type: "Client", Action: "Status"}, 0, false)
+	r.Reset()
+	c.Assert(r.Stats(), gc.HasLen, 0)
+}
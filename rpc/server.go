@@ -45,6 +45,18 @@ type Codec interface {
 	Close() error
 }
 
+// BatchWriter may be implemented by a Codec that is able to write
+// several messages at once. Messages written together must be read
+// by the other side's ReadHeader and ReadBody in the order given,
+// exactly as if they had been written by successive calls to
+// WriteMessage.
+type BatchWriter interface {
+	// WriteBatch writes a message for each of the given headers,
+	// with the corresponding body. The same concurrency rules
+	// apply as for Codec.WriteMessage.
+	WriteBatch(hdrs []*Header, bodies []interface{}) error
+}
+
 // Header is a header written before every RPC call.  Since RPC requests
 // can be initiated from either side, the header may represent a request
 // from the other side or a response to an outstanding request.
@@ -62,6 +74,15 @@ type Header struct {
 
 	// ErrorCode holds the code of the error, if any.
 	ErrorCode string
+
+	// ErrorInfo holds structured details of the error, if any.
+	ErrorInfo map[string]interface{}
+
+	// TraceId holds an identifier that correlates the requests
+	// made on behalf of a single client operation, so they can be
+	// found in the logs of both ends of the connection. A reply
+	// carries the TraceId of the request it answers.
+	TraceId string
 }
 
 // Request represents an RPC to be performed, absent its parameters.
@@ -127,6 +148,10 @@ type Conn struct {
 	// reqId holds the latest client request id.
 	reqId uint64
 
+	// traceId holds the trace id sent with client requests
+	// that are not given one of their own.
+	traceId string
+
 	// clientPending holds all pending client requests.
 	clientPending map[uint64]*Call
 
@@ -283,6 +308,23 @@ func (conn *Conn) serve(methodFinder MethodFinder, root interface{}, transformEr
 	conn.transformErrors = transformErrors
 }
 
+// SetTraceId sets the trace id that will be sent with all subsequent
+// client requests made on the connection that are not given a trace
+// id of their own. If it is empty, each such request is sent with a
+// new trace id.
+func (conn *Conn) SetTraceId(traceId string) {
+	conn.mutex.Lock()
+	defer conn.mutex.Unlock()
+	conn.traceId = traceId
+}
+
+// TraceId returns the trace id set with SetTraceId.
+func (conn *Conn) TraceId() string {
+	conn.mutex.Lock()
+	defer conn.mutex.Unlock()
+	return conn.traceId
+}
+
 // noopTransform is used when transformErrors is not supplied to Serve.
 func noopTransform(err error) error {
 	return err
@@ -357,6 +399,13 @@ type ErrorCoder interface {
 	ErrorCode() string
 }
 
+// ErrorInfoer represents an error that carries structured details
+// for the client, such as how long to wait before retrying. The
+// details are encoded along with the error message.
+type ErrorInfoer interface {
+	ErrorInfo() map[string]interface{}
+}
+
 // MethodFinder represents a type that can be used to lookup a Method and place
 // calls on that method.
 type MethodFinder interface {
@@ -493,12 +542,16 @@ func (conn *Conn) writeErrorResponse(reqHdr *Header, err error, startTime time.T
 	defer conn.sending.Unlock()
 	hdr := &Header{
 		RequestId: reqHdr.RequestId,
+		TraceId:   reqHdr.TraceId,
 	}
 	if err, ok := err.(ErrorCoder); ok {
 		hdr.ErrorCode = err.ErrorCode()
 	} else {
 		hdr.ErrorCode = ""
 	}
+	if err, ok := err.(ErrorInfoer); ok {
+		hdr.ErrorInfo = err.ErrorInfo()
+	}
 	hdr.Error = err.Error()
 	if conn.notifier != nil {
 		conn.notifier.ServerReply(reqHdr.Request, hdr, struct{}{}, time.Since(startTime))
@@ -555,6 +608,7 @@ func (conn *Conn) runRequest(req boundRequest, arg reflect.Value, startTime time
 	} else {
 		hdr := &Header{
 			RequestId: req.hdr.RequestId,
+			TraceId:   req.hdr.TraceId,
 		}
 		var rvi interface{}
 		if rv.IsValid() {
@@ -583,3 +637,7 @@ func (e *serverError) Error() string {
 func (e *serverError) ErrorCode() string {
 	return e.Code
 }
+
+func (e *serverError) ErrorInfo() map[string]interface{} {
+	return e.Info
+}
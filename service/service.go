@@ -3,20 +3,49 @@ package service
 import (
 	"fmt"
 	"io/ioutil"
+	"os"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/juju/utils/exec"
 
 	"github.com/juju/juju/service/common"
+	"github.com/juju/juju/service/systemd"
 	"github.com/juju/juju/service/upstart"
 	"github.com/juju/juju/service/windows"
 	"github.com/juju/juju/version"
 )
 
 var _ Service = (*upstart.Service)(nil)
+var _ Service = (*systemd.Service)(nil)
 var _ Service = (*windows.Service)(nil)
 
+// These are the names of the init systems that juju knows how to
+// manage services with.
+const (
+	InitSystemUpstart = "upstart"
+	InitSystemSystemd = "systemd"
+	InitSystemWindows = "windows"
+)
+
+// systemdRunDir exists only when the host was booted with systemd;
+// this is the same check made by sd_booted(3).
+var systemdRunDir = "/run/systemd/system"
+
+// DiscoverInitSystem returns the name of the init system managing
+// services on the current host. It is a variable so that tests can
+// avoid depending on the init system of the machine they run on.
+var DiscoverInitSystem = func() string {
+	if version.Current.OS == version.Windows {
+		return InitSystemWindows
+	}
+	if fi, err := os.Stat(systemdRunDir); err == nil && fi.IsDir() {
+		return InitSystemSystemd
+	}
+	return InitSystemUpstart
+}
+
 // Service represents a service running on the current system
 type Service interface {
 	// Installed will return a boolean value that denotes
@@ -43,13 +72,47 @@ type Service interface {
 	UpdateConfig(conf common.Conf)
 }
 
+// systemdUbuntuVersion is the first Ubuntu release that uses systemd.
+const systemdUbuntuVersion = 15.04
+
+// InitSystemForSeries returns the name of the init system used by
+// machines running the given series. It is used when configuring
+// services for a machine that is not the current host.
+func InitSystemForSeries(series string) (string, error) {
+	osType, err := version.GetOSFromSeries(series)
+	if err != nil {
+		return "", err
+	}
+	switch osType {
+	case version.Windows:
+		return InitSystemWindows, nil
+	case version.CentOS:
+		return InitSystemSystemd, nil
+	case version.Ubuntu:
+		seriesVersion, err := version.SeriesVersion(series)
+		if err != nil {
+			return "", err
+		}
+		v, err := strconv.ParseFloat(seriesVersion, 64)
+		if err != nil {
+			return "", fmt.Errorf("invalid version %q for series %q", seriesVersion, series)
+		}
+		if v >= systemdUbuntuVersion {
+			return InitSystemSystemd, nil
+		}
+	}
+	return InitSystemUpstart, nil
+}
+
 // NewService returns an interface to a service apropriate
 // for the current system
 func NewService(name string, conf common.Conf) Service {
-	switch version.Current.OS {
-	case version.Windows:
+	switch DiscoverInitSystem() {
+	case InitSystemWindows:
 		svc := windows.NewService(name, conf)
 		return svc
+	case InitSystemSystemd:
+		return systemd.NewService(name, conf)
 	default:
 		return upstart.NewService(name, conf)
 	}
@@ -85,14 +148,21 @@ func upstartListServices(initDir string) ([]string, error) {
 	return services, nil
 }
 
-// ListServices lists all installed services on the running system
+// ListServices lists all installed services on the running system.
+// If initDir is empty, the init system's default directory is used.
 func ListServices(initDir string) ([]string, error) {
-	switch version.Current.OS {
-	case version.Ubuntu:
-		return upstartListServices(initDir)
-	case version.Windows:
+	switch DiscoverInitSystem() {
+	case InitSystemWindows:
 		return windowsListServices()
+	case InitSystemSystemd:
+		if initDir == "" {
+			initDir = systemd.InitDir
+		}
+		return systemd.ListServices(initDir)
 	default:
+		if initDir == "" {
+			initDir = upstart.InitDir
+		}
 		return upstartListServices(initDir)
 	}
 }
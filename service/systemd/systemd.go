@@ -0,0 +1,392 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package systemd
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+
+	"github.com/juju/juju/service/common"
+)
+
+// InitDir holds the default directory in which unit files are written.
+var InitDir = "/etc/systemd/system"
+
+var InstallStartRetryAttempts = utils.AttemptStrategy{
+	Total: 1 * time.Second,
+	Delay: 250 * time.Millisecond,
+}
+
+// limitDirectives maps the ulimit names used in common.Conf.Limit
+// onto the equivalent systemd resource limit directives.
+var limitDirectives = map[string]string{
+	"as":         "LimitAS",
+	"core":       "LimitCORE",
+	"cpu":        "LimitCPU",
+	"data":       "LimitDATA",
+	"fsize":      "LimitFSIZE",
+	"memlock":    "LimitMEMLOCK",
+	"msgqueue":   "LimitMSGQUEUE",
+	"nice":       "LimitNICE",
+	"nofile":     "LimitNOFILE",
+	"nproc":      "LimitNPROC",
+	"rss":        "LimitRSS",
+	"rtprio":     "LimitRTPRIO",
+	"sigpending": "LimitSIGPENDING",
+	"stack":      "LimitSTACK",
+}
+
+// Service provides visibility into and control over a systemd service.
+type Service struct {
+	Name string
+	Conf common.Conf
+}
+
+func NewService(name string, conf common.Conf) *Service {
+	if conf.InitDir == "" {
+		conf.InitDir = InitDir
+	}
+	return &Service{Name: name, Conf: conf}
+}
+
+// unitName returns the name by which systemd knows the service.
+func (s *Service) unitName() string {
+	return s.Name + ".service"
+}
+
+// confPath returns the path to the service's unit file.
+func (s *Service) confPath() string {
+	return path.Join(s.Conf.InitDir, s.unitName())
+}
+
+// scriptPath returns the path to the script run by the service when
+// its command needs a shell to run in.
+func (s *Service) scriptPath() string {
+	return path.Join(s.Conf.InitDir, s.Name+"-exec-start.sh")
+}
+
+// needsScript reports whether the service's command must be wrapped in
+// a shell script; systemd runs ExecStart directly, without a shell, so
+// extra script lines and output redirection cannot go in the unit file.
+func (s *Service) needsScript() bool {
+	return s.Conf.ExtraScript != "" || s.Conf.Out != ""
+}
+
+func (s *Service) UpdateConfig(conf common.Conf) {
+	s.Conf = conf
+}
+
+// validate returns an error if the service is not adequately defined.
+func (s *Service) validate() error {
+	if s.Name == "" {
+		return errors.New("missing Name")
+	}
+	if s.Conf.InitDir == "" {
+		return errors.New("missing InitDir")
+	}
+	if s.Conf.Desc == "" {
+		return errors.New("missing Desc")
+	}
+	if s.Conf.Cmd == "" {
+		return errors.New("missing Cmd")
+	}
+	for name := range s.Conf.Limit {
+		if _, ok := limitDirectives[name]; !ok {
+			return errors.Errorf("unsupported limit %q", name)
+		}
+	}
+	return nil
+}
+
+// unitParams holds the values used to render a unit file.
+type unitParams struct {
+	Desc      string
+	Env       []string
+	Limit     []string
+	ExecStart string
+}
+
+// render returns the unit file and, if the service needs one, the start
+// script for the service.
+func (s *Service) render() (unit, script []byte, err error) {
+	if err := s.validate(); err != nil {
+		return nil, nil, err
+	}
+	params := unitParams{
+		Desc:      s.Conf.Desc,
+		ExecStart: s.Conf.Cmd,
+	}
+	for k, v := range s.Conf.Env {
+		params.Env = append(params.Env, fmt.Sprintf("%q", k+"="+v))
+	}
+	sort.Strings(params.Env)
+	for k, v := range s.Conf.Limit {
+		// Upstart limits hold the soft and hard values; systemd
+		// sets both from a single value, so use the hard limit.
+		fields := strings.Fields(v)
+		if len(fields) == 0 {
+			return nil, nil, errors.Errorf("missing value for limit %q", k)
+		}
+		params.Limit = append(params.Limit, limitDirectives[k]+"="+fields[len(fields)-1])
+	}
+	sort.Strings(params.Limit)
+	if s.needsScript() {
+		params.ExecStart = s.scriptPath()
+		var buf bytes.Buffer
+		if err := scriptT.Execute(&buf, s.Conf); err != nil {
+			return nil, nil, err
+		}
+		script = buf.Bytes()
+	}
+	var buf bytes.Buffer
+	if err := unitT.Execute(&buf, params); err != nil {
+		return nil, nil, err
+	}
+	return buf.Bytes(), script, nil
+}
+
+// Installed returns whether the service's unit file exists in the
+// init directory.
+func (s *Service) Installed() bool {
+	_, err := os.Stat(s.confPath())
+	return err == nil
+}
+
+// Exists returns whether the service's unit file exists in the
+// init directory with the same content that this Service would have
+// if installed.
+func (s *Service) Exists() bool {
+	// In any error case, we just say it doesn't exist with this configuration.
+	// Subsequent calls into the Service will give the caller more useful errors.
+	_, same, _, _, err := s.existsAndSame()
+	if err != nil {
+		return false
+	}
+	return same
+}
+
+func (s *Service) existsAndSame() (exists, same bool, unit, script []byte, err error) {
+	unit, script, err = s.render()
+	if err != nil {
+		return false, false, nil, nil, errors.Trace(err)
+	}
+	current, err := ioutil.ReadFile(s.confPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			// no existing unit file
+			return false, false, unit, script, nil
+		}
+		return false, false, nil, nil, errors.Trace(err)
+	}
+	if !bytes.Equal(current, unit) {
+		return true, false, unit, script, nil
+	}
+	if script != nil {
+		currentScript, err := ioutil.ReadFile(s.scriptPath())
+		if err != nil && !os.IsNotExist(err) {
+			return false, false, nil, nil, errors.Trace(err)
+		}
+		return true, bytes.Equal(currentScript, script), unit, script, nil
+	}
+	return true, true, unit, script, nil
+}
+
+// Running returns true if the Service appears to be running.
+func (s *Service) Running() bool {
+	out, err := exec.Command("systemctl", "is-active", s.unitName()).CombinedOutput()
+	if err != nil {
+		return false
+	}
+	return string(bytes.TrimSpace(out)) == "active"
+}
+
+// Start starts the service.
+func (s *Service) Start() error {
+	if s.Running() {
+		return nil
+	}
+	err := runCommand("systemctl", "start", s.unitName())
+	if err != nil {
+		// Double check to see if we were started before our command ran.
+		if s.Running() {
+			return nil
+		}
+	}
+	return err
+}
+
+func runCommand(args ...string) error {
+	out, err := exec.Command(args[0], args[1:]...).CombinedOutput()
+	if err == nil {
+		return nil
+	}
+	out = bytes.TrimSpace(out)
+	if len(out) > 0 {
+		return fmt.Errorf("exec %q: %v (%s)", args, err, out)
+	}
+	return fmt.Errorf("exec %q: %v", args, err)
+}
+
+// Stop stops the service.
+func (s *Service) Stop() error {
+	if !s.Running() {
+		return nil
+	}
+	return runCommand("systemctl", "stop", s.unitName())
+}
+
+// StopAndRemove stops the service and then deletes the service's
+// unit file from the init directory.
+func (s *Service) StopAndRemove() error {
+	if !s.Installed() {
+		return nil
+	}
+	if err := s.Stop(); err != nil {
+		return err
+	}
+	return s.Remove()
+}
+
+// Remove disables the service and deletes its unit file from the init
+// directory.
+func (s *Service) Remove() error {
+	if !s.Installed() {
+		return nil
+	}
+	if err := runCommand("systemctl", "disable", s.unitName()); err != nil {
+		return err
+	}
+	if err := os.Remove(s.confPath()); err != nil {
+		return err
+	}
+	if err := os.Remove(s.scriptPath()); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return runCommand("systemctl", "daemon-reload")
+}
+
+// Install installs and starts the service.
+func (s *Service) Install() error {
+	exists, same, unit, script, err := s.existsAndSame()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if same {
+		return nil
+	}
+	if exists {
+		if err := s.StopAndRemove(); err != nil {
+			return errors.Annotate(err, "systemd: could not remove installed service")
+		}
+	}
+	if script != nil {
+		if err := ioutil.WriteFile(s.scriptPath(), script, 0755); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if err := ioutil.WriteFile(s.confPath(), unit, 0644); err != nil {
+		return errors.Trace(err)
+	}
+	if err := runCommand("systemctl", "daemon-reload"); err != nil {
+		return errors.Trace(err)
+	}
+	// Enabling by path links the unit into systemd's search path when
+	// the init directory is not one that systemd already knows about.
+	if err := runCommand("systemctl", "enable", s.confPath()); err != nil {
+		return errors.Trace(err)
+	}
+	for attempt := InstallStartRetryAttempts.Start(); attempt.Next(); {
+		if err = s.Start(); err == nil {
+			break
+		}
+	}
+	return err
+}
+
+// InstallCommands returns shell commands to install and start the service.
+func (s *Service) InstallCommands() ([]string, error) {
+	unit, script, err := s.render()
+	if err != nil {
+		return nil, err
+	}
+	var cmds []string
+	if script != nil {
+		cmds = append(cmds,
+			fmt.Sprintf("cat > %s << 'EOF'\n%sEOF\n", s.scriptPath(), script),
+			"chmod 0755 "+s.scriptPath(),
+		)
+	}
+	return append(cmds,
+		fmt.Sprintf("cat > %s << 'EOF'\n%sEOF\n", s.confPath(), unit),
+		"systemctl daemon-reload",
+		"systemctl enable "+s.confPath(),
+		"systemctl start "+s.unitName(),
+	), nil
+}
+
+var servicesRe = regexp.MustCompile(`^([a-zA-Z0-9-_:]+)\.service$`)
+
+// ListServices returns the names of the services whose unit files
+// are in the given directory.
+func ListServices(initDir string) ([]string, error) {
+	var services []string
+	fis, err := ioutil.ReadDir(initDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, fi := range fis {
+		if groups := servicesRe.FindStringSubmatch(fi.Name()); len(groups) > 0 {
+			services = append(services, groups[1])
+		}
+	}
+	return services, nil
+}
+
+// BUG: %q quoting of environment values does not necessarily match
+// systemd's quoting rules; this may become an issue in the future.
+var unitT = template.Must(template.New("").Parse(`
+[Unit]
+Description={{.Desc}}
+After=syslog.target
+After=network.target
+After=systemd-user-sessions.service
+
+[Service]
+{{range .Env}}Environment={{.}}
+{{end}}{{range .Limit}}{{.}}
+{{end}}ExecStart={{.ExecStart}}
+Restart=on-failure
+TimeoutSec=300
+
+[Install]
+WantedBy=multi-user.target
+`[1:]))
+
+var scriptT = template.Must(template.New("").Parse(`
+#!/usr/bin/env bash
+{{if .ExtraScript}}
+{{.ExtraScript}}
+{{end}}{{if .Out}}
+# Ensure log files are properly protected. The syslog user, which
+# reads the logs for forwarding on Ubuntu, does not exist everywhere.
+touch {{.Out}}
+if id -u syslog >/dev/null 2>&1; then
+    chown syslog:syslog {{.Out}}
+fi
+chmod 0600 {{.Out}}
+{{end}}
+exec {{.Cmd}}{{if .Out}} >> {{.Out}} 2>&1{{end}}
+`[1:]))
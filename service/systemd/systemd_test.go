@@ -0,0 +1,369 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package systemd_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/service/common"
+	"github.com/juju/juju/service/systemd"
+	coretesting "github.com/juju/juju/testing"
+)
+
+func Test(t *testing.T) { gc.TestingT(t) }
+
+type SystemdSuite struct {
+	coretesting.BaseSuite
+	testPath string
+	service  *systemd.Service
+	initDir  string
+}
+
+var _ = gc.Suite(&SystemdSuite{})
+
+func (s *SystemdSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.testPath = c.MkDir()
+	s.initDir = c.MkDir()
+	s.PatchEnvPathPrepend(s.testPath)
+	s.PatchValue(&systemd.InstallStartRetryAttempts, utils.AttemptStrategy{})
+	s.PatchValue(&systemd.InitDir, s.initDir)
+	s.service = systemd.NewService(
+		"some-service",
+		common.Conf{
+			Desc: "some service",
+			Cmd:  "some command",
+		},
+	)
+	s.StoppedStatus(c)
+}
+
+// systemctl holds the shell snippets run by the fake systemctl
+// tool for each of the subcommands used by the systemd package.
+type systemctl struct {
+	isActive string
+	start    string
+	stop     string
+}
+
+// MakeSystemctl writes a fake systemctl tool which records its
+// arguments in a log file and runs the given snippets.
+func (s *SystemdSuite) MakeSystemctl(c *gc.C, tool systemctl) {
+	orDefault := func(script, def string) string {
+		if script == "" {
+			return def
+		}
+		return script
+	}
+	script := fmt.Sprintf(`#!/bin/bash --norc
+echo "$@" >> %s
+case "$1" in
+is-active) %s ;;
+start) %s ;;
+stop) %s ;;
+esac
+`,
+		s.logPath(),
+		orDefault(tool.isActive, `echo inactive; exit 3`),
+		orDefault(tool.start, "exit 0"),
+		orDefault(tool.stop, "exit 0"),
+	)
+	path := filepath.Join(s.testPath, "systemctl")
+	err := ioutil.WriteFile(path, []byte(script), 0755)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *SystemdSuite) logPath() string {
+	return filepath.Join(s.testPath, "systemctl.log")
+}
+
+// calls returns the systemctl invocations made so far, one per line.
+func (s *SystemdSuite) calls(c *gc.C) string {
+	data, err := ioutil.ReadFile(s.logPath())
+	if os.IsNotExist(err) {
+		return ""
+	}
+	c.Assert(err, jc.ErrorIsNil)
+	return string(data)
+}
+
+func (s *SystemdSuite) StoppedStatus(c *gc.C) {
+	s.MakeSystemctl(c, systemctl{})
+}
+
+func (s *SystemdSuite) RunningStatus(c *gc.C) {
+	s.MakeSystemctl(c, systemctl{isActive: "echo active"})
+}
+
+func (s *SystemdSuite) TestInitDir(c *gc.C) {
+	svc := systemd.NewService("blah", common.Conf{})
+	c.Assert(svc.Conf.InitDir, gc.Equals, s.initDir)
+}
+
+func (s *SystemdSuite) goodInstall(c *gc.C) {
+	err := s.service.Install()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *SystemdSuite) TestInstalled(c *gc.C) {
+	c.Assert(s.service.Installed(), jc.IsFalse)
+	s.goodInstall(c)
+	c.Assert(s.service.Installed(), jc.IsTrue)
+}
+
+func (s *SystemdSuite) TestExists(c *gc.C) {
+	c.Assert(s.service.Exists(), jc.IsFalse)
+	s.goodInstall(c)
+	c.Assert(s.service.Exists(), jc.IsTrue)
+}
+
+func (s *SystemdSuite) TestExistsNonEmpty(c *gc.C) {
+	s.goodInstall(c)
+	s.service.Conf.Cmd = "something else"
+	c.Assert(s.service.Exists(), jc.IsFalse)
+}
+
+func (s *SystemdSuite) TestExistsScriptChanged(c *gc.C) {
+	s.service.Conf.Out = "/some/output/path"
+	s.goodInstall(c)
+	c.Assert(s.service.Exists(), jc.IsTrue)
+	s.service.Conf.Out = "/some/other/path"
+	c.Assert(s.service.Exists(), jc.IsFalse)
+}
+
+func (s *SystemdSuite) TestRunning(c *gc.C) {
+	s.MakeSystemctl(c, systemctl{isActive: "exit 1"})
+	c.Assert(s.service.Running(), jc.IsFalse)
+	s.MakeSystemctl(c, systemctl{isActive: `echo "GIBBERISH NONSENSE"`})
+	c.Assert(s.service.Running(), jc.IsFalse)
+	s.RunningStatus(c)
+	c.Assert(s.service.Running(), jc.IsTrue)
+}
+
+func (s *SystemdSuite) TestStart(c *gc.C) {
+	s.MakeSystemctl(c, systemctl{isActive: "echo active", start: "exit 99"})
+	c.Assert(s.service.Start(), gc.IsNil)
+	s.MakeSystemctl(c, systemctl{start: "exit 99"})
+	c.Assert(s.service.Start(), gc.ErrorMatches, ".*exit status 99.*")
+	s.StoppedStatus(c)
+	c.Assert(s.service.Start(), gc.IsNil)
+	c.Assert(s.calls(c), jc.Contains, "start some-service.service")
+}
+
+func (s *SystemdSuite) TestStop(c *gc.C) {
+	s.MakeSystemctl(c, systemctl{stop: "exit 99"})
+	c.Assert(s.service.Stop(), gc.IsNil)
+	s.MakeSystemctl(c, systemctl{isActive: "echo active", stop: "exit 99"})
+	c.Assert(s.service.Stop(), gc.ErrorMatches, ".*exit status 99.*")
+	s.RunningStatus(c)
+	c.Assert(s.service.Stop(), gc.IsNil)
+	c.Assert(s.calls(c), jc.Contains, "stop some-service.service")
+}
+
+func (s *SystemdSuite) TestRemoveMissing(c *gc.C) {
+	c.Assert(s.service.StopAndRemove(), gc.IsNil)
+	c.Assert(s.calls(c), gc.Equals, "")
+}
+
+func (s *SystemdSuite) TestRemoveStopped(c *gc.C) {
+	s.service.Conf.Out = "/some/output/path"
+	s.goodInstall(c)
+	c.Assert(s.service.StopAndRemove(), gc.IsNil)
+	_, err := os.Stat(filepath.Join(s.initDir, "some-service.service"))
+	c.Assert(err, jc.Satisfies, os.IsNotExist)
+	_, err = os.Stat(filepath.Join(s.initDir, "some-service-exec-start.sh"))
+	c.Assert(err, jc.Satisfies, os.IsNotExist)
+	c.Assert(s.calls(c), jc.HasSuffix, "disable some-service.service\ndaemon-reload\n")
+}
+
+func (s *SystemdSuite) TestStopAndRemove(c *gc.C) {
+	s.goodInstall(c)
+	s.MakeSystemctl(c, systemctl{isActive: "echo active", stop: "exit 99"})
+
+	// StopAndRemove will fail, as it calls stop.
+	c.Assert(s.service.StopAndRemove(), gc.ErrorMatches, ".*exit status 99.*")
+	_, err := os.Stat(filepath.Join(s.initDir, "some-service.service"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Plain old Remove will succeed.
+	c.Assert(s.service.Remove(), gc.IsNil)
+	_, err = os.Stat(filepath.Join(s.initDir, "some-service.service"))
+	c.Assert(err, jc.Satisfies, os.IsNotExist)
+}
+
+func (s *SystemdSuite) TestInstallErrors(c *gc.C) {
+	conf := common.Conf{}
+	check := func(msg string) {
+		c.Assert(s.service.Install(), gc.ErrorMatches, msg)
+		_, err := s.service.InstallCommands()
+		c.Assert(err, gc.ErrorMatches, msg)
+	}
+	s.service.Conf = conf
+	s.service.Name = ""
+	check("missing Name")
+	s.service.Name = "some-service"
+	check("missing InitDir")
+	s.service.Conf.InitDir = c.MkDir()
+	check("missing Desc")
+	s.service.Conf.Desc = "this is a systemd service"
+	check("missing Cmd")
+	s.service.Conf.Cmd = "do something"
+	s.service.Conf.Limit = map[string]string{"bogus": "1 1"}
+	check(`unsupported limit "bogus"`)
+}
+
+const expectUnitStart = `[Unit]
+Description=this is a systemd service
+After=syslog.target
+After=network.target
+After=systemd-user-sessions.service
+
+[Service]
+`
+
+const expectUnitEnd = `Restart=on-failure
+TimeoutSec=300
+
+[Install]
+WantedBy=multi-user.target
+`
+
+func (s *SystemdSuite) dummyConf(c *gc.C) common.Conf {
+	return common.Conf{
+		Desc:    "this is a systemd service",
+		Cmd:     "do something",
+		InitDir: s.initDir,
+	}
+}
+
+func (s *SystemdSuite) assertInstall(c *gc.C, conf common.Conf, expectService, expectScript string) {
+	expectUnit := expectUnitStart + expectService + expectUnitEnd
+	unitPath := filepath.Join(conf.InitDir, "some-service.service")
+	scriptPath := filepath.Join(conf.InitDir, "some-service-exec-start.sh")
+
+	s.service.Conf = conf
+	cmds, err := s.service.InstallCommands()
+	c.Assert(err, jc.ErrorIsNil)
+	var expectCmds []string
+	if expectScript != "" {
+		expectCmds = append(expectCmds,
+			"cat > "+scriptPath+" << 'EOF'\n"+expectScript+"EOF\n",
+			"chmod 0755 "+scriptPath,
+		)
+	}
+	expectCmds = append(expectCmds,
+		"cat > "+unitPath+" << 'EOF'\n"+expectUnit+"EOF\n",
+		"systemctl daemon-reload",
+		"systemctl enable "+unitPath,
+		"systemctl start some-service.service",
+	)
+	c.Assert(cmds, jc.DeepEquals, expectCmds)
+
+	s.MakeSystemctl(c, systemctl{start: "exit 99"})
+	err = s.service.Install()
+	c.Assert(err, gc.ErrorMatches, ".*exit status 99.*")
+	s.StoppedStatus(c)
+	err = s.service.Install()
+	c.Assert(err, jc.ErrorIsNil)
+	content, err := ioutil.ReadFile(unitPath)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(content), gc.Equals, expectUnit)
+	if expectScript == "" {
+		_, err := os.Stat(scriptPath)
+		c.Assert(err, jc.Satisfies, os.IsNotExist)
+		return
+	}
+	content, err = ioutil.ReadFile(scriptPath)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(content), gc.Equals, expectScript)
+}
+
+func (s *SystemdSuite) TestInstallSimple(c *gc.C) {
+	conf := s.dummyConf(c)
+	s.assertInstall(c, conf, "ExecStart=do something\n", "")
+	c.Assert(s.calls(c), jc.Contains, "enable "+filepath.Join(s.initDir, "some-service.service"))
+}
+
+func (s *SystemdSuite) TestInstallExtraScript(c *gc.C) {
+	conf := s.dummyConf(c)
+	conf.ExtraScript = "extra lines of script"
+	scriptPath := filepath.Join(s.initDir, "some-service-exec-start.sh")
+	s.assertInstall(c, conf, "ExecStart="+scriptPath+"\n", `#!/usr/bin/env bash
+
+extra lines of script
+
+exec do something
+`)
+}
+
+func (s *SystemdSuite) TestInstallOutput(c *gc.C) {
+	conf := s.dummyConf(c)
+	conf.Out = "/some/output/path"
+	scriptPath := filepath.Join(s.initDir, "some-service-exec-start.sh")
+	s.assertInstall(c, conf, "ExecStart="+scriptPath+"\n", `#!/usr/bin/env bash
+
+# Ensure log files are properly protected. The syslog user, which
+# reads the logs for forwarding on Ubuntu, does not exist everywhere.
+touch /some/output/path
+if id -u syslog >/dev/null 2>&1; then
+    chown syslog:syslog /some/output/path
+fi
+chmod 0600 /some/output/path
+
+exec do something >> /some/output/path 2>&1
+`)
+}
+
+func (s *SystemdSuite) TestInstallEnv(c *gc.C) {
+	conf := s.dummyConf(c)
+	conf.Env = map[string]string{"FOO": "bar baz", "QUX": "ping pong"}
+	s.assertInstall(c, conf, `Environment="FOO=bar baz"
+Environment="QUX=ping pong"
+ExecStart=do something
+`, "")
+}
+
+func (s *SystemdSuite) TestInstallLimit(c *gc.C) {
+	conf := s.dummyConf(c)
+	conf.Limit = map[string]string{"nofile": "65000 65000", "nproc": "20000 20000"}
+	s.assertInstall(c, conf, `LimitNOFILE=65000
+LimitNPROC=20000
+ExecStart=do something
+`, "")
+}
+
+func (s *SystemdSuite) TestInstallAlreadyRunning(c *gc.C) {
+	s.RunningStatus(c)
+	s.goodInstall(c)
+	c.Assert(s.calls(c), gc.Not(jc.Contains), "start some-service.service")
+	c.Assert(s.service, jc.Satisfies, (*systemd.Service).Running)
+}
+
+func (s *SystemdSuite) TestInstallReplacesChangedService(c *gc.C) {
+	s.goodInstall(c)
+	s.service.Conf.Cmd = "something else"
+	s.goodInstall(c)
+	content, err := ioutil.ReadFile(filepath.Join(s.initDir, "some-service.service"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(content), jc.Contains, "ExecStart=something else\n")
+	c.Assert(s.calls(c), jc.Contains, "disable some-service.service")
+}
+
+func (s *SystemdSuite) TestListServices(c *gc.C) {
+	for _, name := range []string{"jujud-unit-foo-0.service", "juju-db.service", "not-a-unit.conf"} {
+		err := ioutil.WriteFile(filepath.Join(s.initDir, name), nil, 0644)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	services, err := systemd.ListServices(s.initDir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(services, jc.SameContents, []string{"jujud-unit-foo-0", "juju-db"})
+}
@@ -7,6 +7,7 @@ import (
 	"encoding/hex"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/names"
@@ -15,6 +16,7 @@ import (
 	"github.com/juju/utils"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/actions"
 	"github.com/juju/juju/state"
 	statetesting "github.com/juju/juju/state/testing"
 	"github.com/juju/juju/testing"
@@ -465,6 +467,64 @@ func (s *ActionSuite) TestUnitWatchActionNotifications(c *gc.C) {
 	wc.AssertNoChange()
 }
 
+func (s *ActionSuite) TestAddPredefinedActionToActionlessUnit(c *gc.C) {
+	payload := actions.JujuRunParams("hostname", time.Minute)
+	action, err := s.actionlessUnit.AddAction(actions.JujuRunActionName, payload)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(action.Name(), gc.Equals, actions.JujuRunActionName)
+	c.Assert(action.Receiver(), gc.Equals, s.actionlessUnit.Name())
+
+	_, err = s.actionlessUnit.AddAction(actions.JujuRunActionName, nil)
+	c.Assert(err, gc.ErrorMatches, "JSON validation failed: .*")
+}
+
+func (s *ActionSuite) TestMachineAddAction(c *gc.C) {
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+
+	payload := actions.JujuRunParams("hostname", time.Minute)
+	action, err := machine.AddAction(actions.JujuRunActionName, payload)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(action.Receiver(), gc.Equals, machine.Id())
+
+	pending, err := machine.PendingActions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pending, gc.HasLen, 1)
+	c.Assert(pending[0].Id(), gc.Equals, action.Id())
+
+	_, err = machine.AddAction("snapshot", nil)
+	c.Assert(err, gc.ErrorMatches, `cannot add action "snapshot" to a machine; only predefined actions allowed`)
+}
+
+func (s *ActionSuite) TestMachineWatchActionNotifications(c *gc.C) {
+	machine0, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	machine1, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	payload := actions.JujuRunParams("hostname", time.Minute)
+
+	fa1, err := machine0.AddAction(actions.JujuRunActionName, payload)
+	c.Assert(err, jc.ErrorIsNil)
+
+	w := machine0.WatchActionNotifications()
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewStringsWatcherC(c, s.State, w)
+	wc.AssertChange(expectActionIds(fa1)...)
+	wc.AssertNoChange()
+
+	// Actions queued for other receivers are not reported.
+	_, err = machine1.AddAction(actions.JujuRunActionName, payload)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.unit.AddAction("snapshot", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertNoChange()
+
+	fa2, err := machine0.AddAction(actions.JujuRunActionName, payload)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertChange(expectActionIds(fa2)...)
+	wc.AssertNoChange()
+}
+
 func (s *ActionSuite) TestMergeIds(c *gc.C) {
 	var tests = []struct {
 		changes  string
@@ -323,6 +323,9 @@ func (st *State) addMachineInsideMachineOps(template MachineTemplate, parentId s
 	if !parent.supportsContainerType(containerType) {
 		return nil, nil, errors.Errorf("machine %s cannot host %s containers", parentId, containerType)
 	}
+	if parent.InMaintenance() {
+		return nil, nil, errors.Errorf("machine %s is in maintenance", parentId)
+	}
 	newId, err := st.newContainerId(parentId, containerType)
 	if err != nil {
 		return nil, nil, err
@@ -334,6 +337,12 @@ func (st *State) addMachineInsideMachineOps(template MachineTemplate, parentId s
 		return nil, nil, errors.Trace(err)
 	}
 	prereqOps = append(prereqOps,
+		// Ensure the host machine is not put into maintenance.
+		txn.Op{
+			C:      machinesC,
+			Id:     st.docID(parentId),
+			Assert: notInMaintenanceDoc,
+		},
 		// Update containers record for host machine.
 		st.addChildToContainerRefOp(parentId, mdoc.Id),
 		// Create a containers reference document for the container itself.
@@ -290,6 +290,45 @@ func (s *AssignSuite) TestAssignMachineWhenDying(c *gc.C) {
 	testWhenDying(c, machine, expect, expect, assignTest)
 }
 
+func (s *AssignSuite) TestAssignMachineInMaintenance(c *gc.C) {
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.SetMaintenance(true)
+	c.Assert(err, jc.ErrorIsNil)
+
+	unit, err := s.wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AssignToMachine(machine)
+	c.Assert(err, gc.ErrorMatches, `cannot assign unit "wordpress/0" to machine 0: machine is in maintenance`)
+
+	err = machine.SetMaintenance(false)
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AssignToMachine(machine)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *AssignSuite) TestAddContainerToMachineInMaintenance(c *gc.C) {
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.SetMaintenance(true)
+	c.Assert(err, jc.ErrorIsNil)
+
+	template := state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+	}
+	_, err = s.State.AddMachineInsideMachine(template, machine.Id(), instance.LXC)
+	c.Assert(err, gc.ErrorMatches, "cannot add a new machine: machine 0 is in maintenance")
+
+	unit, err := s.wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AssignToNewMachineOrContainer()
+	c.Assert(err, jc.ErrorIsNil)
+	assigned, err := unit.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(assigned, gc.Not(gc.Equals), machine.Id())
+}
+
 func (s *AssignSuite) TestAssignMachinePrincipalsChange(c *gc.C) {
 	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
 	c.Assert(err, jc.ErrorIsNil)
@@ -808,6 +847,15 @@ func (s *assignCleanSuite) TestAssignToMachineNoneAvailable(c *gc.C) {
 	c.Assert(m, gc.IsNil)
 	c.Assert(err, gc.ErrorMatches, eligibleMachinesInUse)
 
+	// Add a machine in maintenance and check that it is not chosen.
+	m, err = s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = m.SetMaintenance(true)
+	c.Assert(err, jc.ErrorIsNil)
+	m, err = s.assignUnit(unit)
+	c.Assert(m, gc.IsNil)
+	c.Assert(err, gc.ErrorMatches, eligibleMachinesInUse)
+
 	// Add a dying machine and check that it is not chosen.
 	m, err = s.State.AddMachine("quantal", state.JobHostUnits)
 	c.Assert(err, jc.ErrorIsNil)
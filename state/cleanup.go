@@ -218,7 +218,7 @@ func (st *State) cleanupRemovedUnit(unitId string) error {
 			return err
 		}
 	}
-	return nil
+	return st.removeHookHistory(unitId)
 }
 
 // cleanupForceDestroyedMachine systematically destroys and removes all entities
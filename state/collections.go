@@ -67,6 +67,7 @@ var multiEnvCollections = set.NewStrings(
 	cleanupsC,
 	constraintsC,
 	containerRefsC,
+	hookHistoryC,
 	instanceDataC,
 	machinesC,
 	meterStatusC,
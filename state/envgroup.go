@@ -0,0 +1,174 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// EnvironmentGroup represents access to an environment granted to
+// all the members of a group defined by an external identity
+// service. There should be no more than one EnvironmentGroup per
+// group.
+type EnvironmentGroup struct {
+	st  *State
+	doc envGroupDoc
+}
+
+type envGroupDoc struct {
+	ID          string    `bson:"_id"`
+	EnvUUID     string    `bson:"envuuid"`
+	GroupName   string    `bson:"group"`
+	CreatedBy   string    `bson:"createdby"`
+	DateCreated time.Time `bson:"datecreated"`
+	Access      string    `bson:"access"`
+}
+
+var validGroupName = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9.+_-]*$`)
+
+// IsValidGroupName returns whether the given
+// name is a valid name for an external group.
+func IsValidGroupName(name string) bool {
+	return validGroupName.MatchString(name)
+}
+
+// ID returns the ID of the environment group.
+func (g *EnvironmentGroup) ID() string {
+	return g.doc.ID
+}
+
+// EnvironmentTag returns the environment tag of the environment group.
+func (g *EnvironmentGroup) EnvironmentTag() names.EnvironTag {
+	return names.NewEnvironTag(g.doc.EnvUUID)
+}
+
+// GroupName returns the name of the group.
+func (g *EnvironmentGroup) GroupName() string {
+	return g.doc.GroupName
+}
+
+// CreatedBy returns the user who granted the group access.
+func (g *EnvironmentGroup) CreatedBy() string {
+	return g.doc.CreatedBy
+}
+
+// DateCreated returns the date the group was granted access.
+func (g *EnvironmentGroup) DateCreated() time.Time {
+	return g.doc.DateCreated
+}
+
+// Access returns the level of access to the environment
+// granted to the members of the group.
+func (g *EnvironmentGroup) Access() EnvironmentAccess {
+	return EnvironmentAccess(g.doc.Access)
+}
+
+// envGroupID returns the document id of the environment group with
+// the following format uuid:group.
+func envGroupID(envuuid, group string) string {
+	return fmt.Sprintf("%s:%s", envuuid, group)
+}
+
+// EnvironmentGroup returns the environment group with the given name.
+func (st *State) EnvironmentGroup(group string) (*EnvironmentGroup, error) {
+	envGroups, closer := st.getCollection(envGroupsC)
+	defer closer()
+
+	envGroup := &EnvironmentGroup{st: st}
+	err := envGroups.FindId(envGroupID(st.EnvironUUID(), group)).One(&envGroup.doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("environment group %q", group)
+	}
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return envGroup, nil
+}
+
+// EnvironmentGroups returns all the groups that
+// have been granted access to the environment.
+func (st *State) EnvironmentGroups() ([]*EnvironmentGroup, error) {
+	envGroups, closer := st.getCollection(envGroupsC)
+	defer closer()
+
+	var docs []envGroupDoc
+	err := envGroups.Find(bson.D{{"envuuid", st.EnvironUUID()}}).Sort("group").All(&docs)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	result := make([]*EnvironmentGroup, len(docs))
+	for i, doc := range docs {
+		result[i] = &EnvironmentGroup{st: st, doc: doc}
+	}
+	return result, nil
+}
+
+// AddEnvironmentGroup grants the given level of access to the
+// environment to all members of the named external group.
+func (st *State) AddEnvironmentGroup(group string, createdBy names.UserTag, access EnvironmentAccess) (*EnvironmentGroup, error) {
+	if !IsValidGroupName(group) {
+		return nil, errors.Errorf("invalid group name %q", group)
+	}
+	if err := access.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	// Ensure local createdBy user exists.
+	if createdBy.IsLocal() {
+		if _, err := st.User(createdBy); err != nil {
+			return nil, errors.Annotate(err, fmt.Sprintf("createdBy user %q does not exist locally", createdBy.Name()))
+		}
+	}
+
+	envuuid := st.EnvironUUID()
+	id := envGroupID(envuuid, group)
+	doc := &envGroupDoc{
+		ID:          id,
+		EnvUUID:     envuuid,
+		GroupName:   group,
+		CreatedBy:   createdBy.Username(),
+		DateCreated: nowToTheSecond(),
+		Access:      string(access),
+	}
+	ops := []txn.Op{{
+		C:      envGroupsC,
+		Id:     id,
+		Assert: txn.DocMissing,
+		Insert: doc,
+	}}
+	err := st.runTransaction(ops)
+	if err == txn.ErrAborted {
+		err = errors.AlreadyExistsf("environment group %q", group)
+	}
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &EnvironmentGroup{st: st, doc: *doc}, nil
+}
+
+// RemoveEnvironmentGroup revokes the access to the environment
+// granted to the named external group.
+func (st *State) RemoveEnvironmentGroup(group string) error {
+	ops := []txn.Op{{
+		C:      envGroupsC,
+		Id:     envGroupID(st.EnvironUUID(), group),
+		Assert: txn.DocExists,
+		Remove: true,
+	}}
+	err := st.runTransaction(ops)
+	if err == txn.ErrAborted {
+		err = errors.NewNotFound(err, fmt.Sprintf("environment group %q does not exist", group))
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
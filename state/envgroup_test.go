@@ -0,0 +1,107 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing/factory"
+)
+
+type EnvGroupSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&EnvGroupSuite{})
+
+func (s *EnvGroupSuite) TestAddEnvironmentGroup(c *gc.C) {
+	now := state.NowToTheSecond()
+	createdBy := s.factory.MakeUser(c, &factory.UserParams{Name: "createdby"})
+	envGroup, err := s.State.AddEnvironmentGroup("devops", createdBy.UserTag(), state.EnvironWriteAccess)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(envGroup.ID(), gc.Equals, fmt.Sprintf("%s:devops", s.envTag.Id()))
+	c.Assert(envGroup.EnvironmentTag(), gc.Equals, s.envTag)
+	c.Assert(envGroup.GroupName(), gc.Equals, "devops")
+	c.Assert(envGroup.CreatedBy(), gc.Equals, "createdby@local")
+	c.Assert(envGroup.Access(), gc.Equals, state.EnvironWriteAccess)
+	c.Assert(envGroup.DateCreated().Equal(now) || envGroup.DateCreated().After(now), jc.IsTrue)
+
+	envGroup, err = s.State.EnvironmentGroup("devops")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(envGroup.ID(), gc.Equals, fmt.Sprintf("%s:devops", s.envTag.Id()))
+	c.Assert(envGroup.GroupName(), gc.Equals, "devops")
+	c.Assert(envGroup.CreatedBy(), gc.Equals, "createdby@local")
+	c.Assert(envGroup.Access(), gc.Equals, state.EnvironWriteAccess)
+}
+
+func (s *EnvGroupSuite) TestAddEnvironmentGroupInvalidAccess(c *gc.C) {
+	createdBy := s.factory.MakeUser(c, nil)
+	_, err := s.State.AddEnvironmentGroup("devops", createdBy.UserTag(), "")
+	c.Assert(err, gc.ErrorMatches, `invalid environment access ""`)
+}
+
+func (s *EnvGroupSuite) TestAddEnvironmentGroupTwice(c *gc.C) {
+	createdBy := s.factory.MakeUser(c, &factory.UserParams{Name: "createdby"})
+	_, err := s.State.AddEnvironmentGroup("devops", createdBy.UserTag(), state.EnvironWriteAccess)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddEnvironmentGroup("devops", createdBy.UserTag(), state.EnvironWriteAccess)
+	c.Assert(err, gc.ErrorMatches, `environment group "devops" already exists`)
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+}
+
+func (s *EnvGroupSuite) TestAddEnvironmentGroupInvalidName(c *gc.C) {
+	createdBy := s.factory.MakeUser(c, &factory.UserParams{Name: "createdby"})
+	_, err := s.State.AddEnvironmentGroup("-devops", createdBy.UserTag(), state.EnvironWriteAccess)
+	c.Assert(err, gc.ErrorMatches, `invalid group name "-devops"`)
+}
+
+func (s *EnvGroupSuite) TestAddEnvironmentGroupNoCreatedByUserFails(c *gc.C) {
+	_, err := s.State.AddEnvironmentGroup("devops", names.NewLocalUserTag("nobody"), state.EnvironWriteAccess)
+	c.Assert(err, gc.ErrorMatches, `createdBy user "nobody" does not exist locally: user "nobody" not found`)
+}
+
+func (s *EnvGroupSuite) TestEnvironmentGroupNotFound(c *gc.C) {
+	_, err := s.State.EnvironmentGroup("devops")
+	c.Assert(err, gc.ErrorMatches, `environment group "devops" not found`)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *EnvGroupSuite) TestEnvironmentGroups(c *gc.C) {
+	createdBy := s.factory.MakeUser(c, &factory.UserParams{Name: "createdby"})
+	for _, group := range []string{"qa", "devops", "dba"} {
+		_, err := s.State.AddEnvironmentGroup(group, createdBy.UserTag(), state.EnvironWriteAccess)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	envGroups, err := s.State.EnvironmentGroups()
+	c.Assert(err, jc.ErrorIsNil)
+	var groups []string
+	for _, envGroup := range envGroups {
+		groups = append(groups, envGroup.GroupName())
+	}
+	c.Assert(groups, jc.DeepEquals, []string{"dba", "devops", "qa"})
+}
+
+func (s *EnvGroupSuite) TestRemoveEnvironmentGroup(c *gc.C) {
+	createdBy := s.factory.MakeUser(c, &factory.UserParams{Name: "createdby"})
+	_, err := s.State.AddEnvironmentGroup("devops", createdBy.UserTag(), state.EnvironWriteAccess)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.RemoveEnvironmentGroup("devops")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.EnvironmentGroup("devops")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *EnvGroupSuite) TestRemoveEnvironmentGroupNotFound(c *gc.C) {
+	err := s.State.RemoveEnvironmentGroup("devops")
+	c.Assert(err, gc.ErrorMatches, `environment group "devops" does not exist`)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
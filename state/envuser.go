@@ -32,6 +32,47 @@ type envUserDoc struct {
 	CreatedBy      string     `bson:"createdby"`
 	DateCreated    time.Time  `bson:"datecreated"`
 	LastConnection *time.Time `bson:"lastconnection"`
+	Access         string     `bson:"access,omitempty"`
+}
+
+// EnvironmentAccess defines the level of access
+// a user or group has to an environment.
+type EnvironmentAccess string
+
+const (
+	// EnvironReadAccess allows the environment to be inspected,
+	// for example with status, but not changed.
+	EnvironReadAccess EnvironmentAccess = "read"
+
+	// EnvironWriteAccess allows services, units, machines and
+	// relations in the environment to be added and removed.
+	EnvironWriteAccess EnvironmentAccess = "write"
+
+	// EnvironAdminAccess additionally allows the environment's
+	// configuration to be changed, the environment to be shared
+	// with others, and the environment to be destroyed.
+	EnvironAdminAccess EnvironmentAccess = "admin"
+)
+
+var environAccessLevels = map[EnvironmentAccess]int{
+	EnvironReadAccess:  1,
+	EnvironWriteAccess: 2,
+	EnvironAdminAccess: 3,
+}
+
+// Validate returns an error if the access level is not known.
+func (a EnvironmentAccess) Validate() error {
+	if _, ok := environAccessLevels[a]; !ok {
+		return errors.Errorf("invalid environment access %q", string(a))
+	}
+	return nil
+}
+
+// Includes returns whether the access level a
+// allows everything allowed by the access level b.
+func (a EnvironmentAccess) Includes(b EnvironmentAccess) bool {
+	level, ok := environAccessLevels[a]
+	return ok && level >= environAccessLevels[b]
 }
 
 // ID returns the ID of the environment user.
@@ -74,6 +115,35 @@ func (e *EnvironmentUser) LastConnection() *time.Time {
 	return e.doc.LastConnection
 }
 
+// Access returns the level of access the environment user has to the
+// environment. Users that were given access to the environment before
+// access levels were introduced have admin access.
+func (e *EnvironmentUser) Access() EnvironmentAccess {
+	if e.doc.Access == "" {
+		return EnvironAdminAccess
+	}
+	return EnvironmentAccess(e.doc.Access)
+}
+
+// SetAccess changes the level of access the environment
+// user has to the environment.
+func (e *EnvironmentUser) SetAccess(access EnvironmentAccess) error {
+	if err := access.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	ops := []txn.Op{{
+		C:      envUsersC,
+		Id:     e.ID(),
+		Assert: txn.DocExists,
+		Update: bson.D{{"$set", bson.D{{"access", string(access)}}}},
+	}}
+	if err := e.st.runTransaction(ops); err != nil {
+		return errors.Annotatef(err, "cannot set access for envuser %q", e.ID())
+	}
+	e.doc.Access = string(access)
+	return nil
+}
+
 // UpdateLastConnection updates the last connection time of the environment user.
 func (e *EnvironmentUser) UpdateLastConnection() error {
 	timestamp := nowToTheSecond()
@@ -111,8 +181,12 @@ func (st *State) EnvironmentUser(user names.UserTag) (*EnvironmentUser, error) {
 	return envUser, nil
 }
 
-// AddEnvironmentUser adds a new user to the database.
-func (st *State) AddEnvironmentUser(user, createdBy names.UserTag) (*EnvironmentUser, error) {
+// AddEnvironmentUser adds a new user to the database, with
+// the given level of access to the environment.
+func (st *State) AddEnvironmentUser(user, createdBy names.UserTag, access EnvironmentAccess) (*EnvironmentUser, error) {
+	if err := access.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
 	var displayName string
 	// Ensure local user exists in state before adding them as an environment user.
 	if user.IsLocal() {
@@ -131,7 +205,7 @@ func (st *State) AddEnvironmentUser(user, createdBy names.UserTag) (*Environment
 	}
 
 	envuuid := st.EnvironUUID()
-	op, doc := createEnvUserOpAndDoc(envuuid, user, createdBy, displayName)
+	op, doc := createEnvUserOpAndDoc(envuuid, user, createdBy, displayName, access)
 	err := st.runTransaction([]txn.Op{op})
 	if err == txn.ErrAborted {
 		err = errors.New("env user already exists")
@@ -142,7 +216,7 @@ func (st *State) AddEnvironmentUser(user, createdBy names.UserTag) (*Environment
 	return &EnvironmentUser{st: st, doc: *doc}, nil
 }
 
-func createEnvUserOpAndDoc(envuuid string, user, createdBy names.UserTag, displayName string) (txn.Op, *envUserDoc) {
+func createEnvUserOpAndDoc(envuuid string, user, createdBy names.UserTag, displayName string, access EnvironmentAccess) (txn.Op, *envUserDoc) {
 	username := user.Username()
 	creatorname := createdBy.Username()
 	id := envUserID(envuuid, username)
@@ -153,6 +227,7 @@ func createEnvUserOpAndDoc(envuuid string, user, createdBy names.UserTag, displa
 		DisplayName: displayName,
 		CreatedBy:   creatorname,
 		DateCreated: nowToTheSecond(),
+		Access:      string(access),
 	}
 	op := txn.Op{
 		C:      envUsersC,
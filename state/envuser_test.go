@@ -28,7 +28,7 @@ func (s *EnvUserSuite) TestAddEnvironmentUser(c *gc.C) {
 	now := state.NowToTheSecond()
 	user := s.factory.MakeUser(c, &factory.UserParams{Name: "validusername", NoEnvUser: true})
 	createdBy := s.factory.MakeUser(c, &factory.UserParams{Name: "createdby"})
-	envUser, err := s.State.AddEnvironmentUser(user.UserTag(), createdBy.UserTag())
+	envUser, err := s.State.AddEnvironmentUser(user.UserTag(), createdBy.UserTag(), state.EnvironReadAccess)
 	c.Assert(err, jc.ErrorIsNil)
 
 	c.Assert(envUser.ID(), gc.Equals, fmt.Sprintf("%s:validusername@local", s.envTag.Id()))
@@ -48,17 +48,71 @@ func (s *EnvUserSuite) TestAddEnvironmentUser(c *gc.C) {
 	c.Assert(envUser.CreatedBy(), gc.Equals, "createdby@local")
 	c.Assert(envUser.DateCreated().Equal(now) || envUser.DateCreated().After(now), jc.IsTrue)
 	c.Assert(envUser.LastConnection(), gc.IsNil)
+	c.Assert(envUser.Access(), gc.Equals, state.EnvironReadAccess)
+}
+
+func (s *EnvUserSuite) TestAddEnvironmentUserInvalidAccess(c *gc.C) {
+	user := s.factory.MakeUser(c, &factory.UserParams{NoEnvUser: true})
+	createdBy := s.factory.MakeUser(c, nil)
+	_, err := s.State.AddEnvironmentUser(user.UserTag(), createdBy.UserTag(), "superuser")
+	c.Assert(err, gc.ErrorMatches, `invalid environment access "superuser"`)
+}
+
+func (s *EnvUserSuite) TestSetAccess(c *gc.C) {
+	envUser := s.factory.MakeEnvUser(c, &factory.EnvUserParams{Access: state.EnvironWriteAccess})
+	c.Assert(envUser.Access(), gc.Equals, state.EnvironWriteAccess)
+
+	err := envUser.SetAccess(state.EnvironReadAccess)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(envUser.Access(), gc.Equals, state.EnvironReadAccess)
+
+	envUser, err = s.State.EnvironmentUser(envUser.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(envUser.Access(), gc.Equals, state.EnvironReadAccess)
+
+	err = envUser.SetAccess("superuser")
+	c.Assert(err, gc.ErrorMatches, `invalid environment access "superuser"`)
+}
+
+func (s *EnvUserSuite) TestEnvironmentOwnerHasAdminAccess(c *gc.C) {
+	env, err := s.State.Environment()
+	c.Assert(err, jc.ErrorIsNil)
+	envUser, err := s.State.EnvironmentUser(env.Owner())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(envUser.Access(), gc.Equals, state.EnvironAdminAccess)
+}
+
+func (s *EnvUserSuite) TestEnvironmentAccessIncludes(c *gc.C) {
+	for i, test := range []struct {
+		access   state.EnvironmentAccess
+		required state.EnvironmentAccess
+		expect   bool
+	}{
+		{state.EnvironReadAccess, state.EnvironReadAccess, true},
+		{state.EnvironReadAccess, state.EnvironWriteAccess, false},
+		{state.EnvironReadAccess, state.EnvironAdminAccess, false},
+		{state.EnvironWriteAccess, state.EnvironReadAccess, true},
+		{state.EnvironWriteAccess, state.EnvironWriteAccess, true},
+		{state.EnvironWriteAccess, state.EnvironAdminAccess, false},
+		{state.EnvironAdminAccess, state.EnvironReadAccess, true},
+		{state.EnvironAdminAccess, state.EnvironAdminAccess, true},
+		{"", state.EnvironReadAccess, false},
+		{"superuser", state.EnvironReadAccess, false},
+	} {
+		c.Logf("test %d: %q includes %q", i, test.access, test.required)
+		c.Check(test.access.Includes(test.required), gc.Equals, test.expect)
+	}
 }
 
 func (s *EnvUserSuite) TestAddEnvironmentNoUserFails(c *gc.C) {
 	createdBy := s.factory.MakeUser(c, &factory.UserParams{Name: "createdby"})
-	_, err := s.State.AddEnvironmentUser(names.NewLocalUserTag("validusername"), createdBy.UserTag())
+	_, err := s.State.AddEnvironmentUser(names.NewLocalUserTag("validusername"), createdBy.UserTag(), state.EnvironAdminAccess)
 	c.Assert(err, gc.ErrorMatches, `user "validusername" does not exist locally: user "validusername" not found`)
 }
 
 func (s *EnvUserSuite) TestAddEnvironmentNoCreatedByUserFails(c *gc.C) {
 	user := s.factory.MakeUser(c, &factory.UserParams{Name: "validusername"})
-	_, err := s.State.AddEnvironmentUser(user.UserTag(), names.NewLocalUserTag("createdby"))
+	_, err := s.State.AddEnvironmentUser(user.UserTag(), names.NewLocalUserTag("createdby"), state.EnvironAdminAccess)
 	c.Assert(err, gc.ErrorMatches, `createdBy user "createdby" does not exist locally: user "createdby" not found`)
 }
 
@@ -154,7 +208,7 @@ func (s *EnvUserSuite) newEnvWithUser(c *gc.C, name string, user names.UserTag)
 	newEnv, err := envState.Environment()
 	c.Assert(err, jc.ErrorIsNil)
 
-	_, err = envState.AddEnvironmentUser(user, newEnv.Owner())
+	_, err = envState.AddEnvironmentUser(user, newEnv.Owner(), state.EnvironAdminAccess)
 	c.Assert(err, jc.ErrorIsNil)
 	return newEnv
 }
@@ -43,6 +43,7 @@ var (
 	CurrentUpgradeId              = currentUpgradeId
 	NowToTheSecond                = nowToTheSecond
 	PickAddress                   = &pickAddress
+	HookHistoryLimit              = &hookHistoryLimit
 	CreateMachineBlockDeviceOps   = createMachineBlockDeviceOps
 	SetProvisionedBlockDeviceInfo = setProvisionedBlockDeviceInfo
 )
@@ -0,0 +1,177 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// hookHistoryLimit is the number of hook records kept for each unit;
+// older records are discarded as new ones are added.
+var hookHistoryLimit = 100
+
+// HookRecord describes a single hook, action or set of commands run
+// by a unit agent.
+type HookRecord struct {
+	// Kind is what ran: "hook", "action" or "commands".
+	Kind string
+
+	// Hook is the name of the hook or action, as seen by the charm, or
+	// "juju-run" for commands.
+	Hook string
+
+	// Relation is the charm-visible identifier of the relation the
+	// hook ran for (for example "db:2"), if any.
+	Relation string
+
+	// RemoteUnit is the name of the remote unit the hook ran for, if any.
+	RemoteUnit string
+
+	// Started is the time at which the hook started running.
+	Started time.Time
+
+	// Duration is how long the hook ran for.
+	Duration time.Duration
+
+	// Result is "ok" if the hook, action or commands succeeded, and
+	// otherwise describes how they failed.
+	Result string
+}
+
+// hookHistoryDoc records a single hook, action or set of commands run by
+// a unit. Each unit's records are numbered from 1 in the order they were
+// added.
+type hookHistoryDoc struct {
+	DocID      string        `bson:"_id"`
+	EnvUUID    string        `bson:"env-uuid"`
+	Unit       string        `bson:"unit"`
+	Seq        int           `bson:"seq"`
+	Kind       string        `bson:"kind"`
+	Hook       string        `bson:"hook"`
+	Relation   string        `bson:"relation,omitempty"`
+	RemoteUnit string        `bson:"remoteunit,omitempty"`
+	Started    time.Time     `bson:"started"`
+	Duration   time.Duration `bson:"duration"`
+	Result     string        `bson:"result"`
+}
+
+func hookRecordId(unitName string, seq int) string {
+	return fmt.Sprintf("%s#%d", unitName, seq)
+}
+
+func hookHistorySequence(unitName string) string {
+	return "hookhistory-" + unitName
+}
+
+// removeHookHistorySequenceOp returns the operation needed to remove the
+// sequence used to number the named unit's hook records.
+func removeHookHistorySequenceOp(st *State, unitName string) txn.Op {
+	return txn.Op{
+		C:      sequenceC,
+		Id:     st.docID(hookHistorySequence(unitName)),
+		Remove: true,
+	}
+}
+
+// AddHookRecord adds record to the unit's hook history, discarding the
+// unit's oldest record if the history is full.
+func (u *Unit) AddHookRecord(record HookRecord) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot add hook record for unit %q", u)
+	switch record.Kind {
+	case "hook", "action", "commands":
+	default:
+		return errors.Errorf("invalid record kind %q", record.Kind)
+	}
+	if record.Hook == "" {
+		return errors.New("missing hook name")
+	}
+	seq, err := u.st.sequence(hookHistorySequence(u.doc.Name))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	doc := &hookHistoryDoc{
+		DocID:      u.st.docID(hookRecordId(u.doc.Name, seq)),
+		EnvUUID:    u.st.EnvironUUID(),
+		Unit:       u.doc.Name,
+		Seq:        seq,
+		Kind:       record.Kind,
+		Hook:       record.Hook,
+		Relation:   record.Relation,
+		RemoteUnit: record.RemoteUnit,
+		Started:    record.Started,
+		Duration:   record.Duration,
+		Result:     record.Result,
+	}
+	ops := []txn.Op{{
+		C:      unitsC,
+		Id:     u.doc.DocID,
+		Assert: txn.DocExists,
+	}, {
+		C:      hookHistoryC,
+		Id:     doc.DocID,
+		Assert: txn.DocMissing,
+		Insert: doc,
+	}}
+	if oldest := seq - hookHistoryLimit; oldest > 0 {
+		ops = append(ops, txn.Op{
+			C:      hookHistoryC,
+			Id:     u.st.docID(hookRecordId(u.doc.Name, oldest)),
+			Remove: true,
+		})
+	}
+	if err := u.st.runTransaction(ops); err == txn.ErrAborted {
+		// The unit's sequence was removed along with it, and has
+		// just been recreated; don't leave it behind.
+		sequenceId := u.st.docID(hookHistorySequence(u.doc.Name))
+		err := u.st.db.C(sequenceC).RemoveId(sequenceId)
+		if err != nil && err != mgo.ErrNotFound {
+			return errors.Trace(err)
+		}
+		return errors.NotFoundf("unit")
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+// HookHistory returns the hooks, actions and commands recorded for the
+// unit, most recent first.
+func (u *Unit) HookHistory() ([]HookRecord, error) {
+	hookHistory, closer := u.st.getCollection(hookHistoryC)
+	defer closer()
+
+	var docs []hookHistoryDoc
+	err := hookHistory.Find(bson.D{{"unit", u.doc.Name}}).Sort("-seq").Limit(hookHistoryLimit).All(&docs)
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get hook history for unit %q", u)
+	}
+	records := make([]HookRecord, len(docs))
+	for i, doc := range docs {
+		records[i] = HookRecord{
+			Kind:       doc.Kind,
+			Hook:       doc.Hook,
+			Relation:   doc.Relation,
+			RemoteUnit: doc.RemoteUnit,
+			Started:    doc.Started,
+			Duration:   doc.Duration,
+			Result:     doc.Result,
+		}
+	}
+	return records, nil
+}
+
+// removeHookHistory removes all hook records for the named unit.
+func (st *State) removeHookHistory(unitName string) error {
+	hookHistory, closer := st.getCollection(hookHistoryC)
+	defer closer()
+
+	_, err := hookHistory.RemoveAll(bson.D{{"unit", unitName}})
+	return errors.Annotatef(err, "cannot remove hook history for unit %q", unitName)
+}
@@ -0,0 +1,155 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"fmt"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing/factory"
+)
+
+type HookHistorySuite struct {
+	ConnSuite
+	unit *state.Unit
+}
+
+var _ = gc.Suite(&HookHistorySuite{})
+
+func (s *HookHistorySuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.unit = factory.NewFactory(s.State).MakeUnit(c, nil)
+}
+
+func (s *HookHistorySuite) addRecords(c *gc.C, hooks ...string) []state.HookRecord {
+	started := time.Date(2015, 4, 1, 12, 0, 0, 0, time.UTC)
+	records := make([]state.HookRecord, len(hooks))
+	for i, hook := range hooks {
+		records[i] = state.HookRecord{
+			Kind:     "hook",
+			Hook:     hook,
+			Started:  started.Add(time.Duration(i) * time.Minute),
+			Duration: time.Duration(i+1) * time.Second,
+			Result:   "ok",
+		}
+		err := s.unit.AddHookRecord(records[i])
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	return records
+}
+
+func (s *HookHistorySuite) assertHistory(c *gc.C, expect []state.HookRecord) {
+	records, err := s.unit.HookHistory()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(records, gc.HasLen, len(expect))
+	for i, record := range records {
+		// Records are returned most recent first.
+		expected := expect[len(expect)-1-i]
+		c.Check(record.Started.Equal(expected.Started), jc.IsTrue)
+		record.Started = expected.Started
+		c.Check(record, jc.DeepEquals, expected)
+	}
+}
+
+func (s *HookHistorySuite) TestEmptyHistory(c *gc.C) {
+	records, err := s.unit.HookHistory()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(records, gc.HasLen, 0)
+}
+
+func (s *HookHistorySuite) TestAddHookRecord(c *gc.C) {
+	records := s.addRecords(c, "install", "config-changed", "start")
+	relationHook := state.HookRecord{
+		Kind:       "hook",
+		Hook:       "db-relation-changed",
+		Relation:   "db:0",
+		RemoteUnit: "mysql/0",
+		Started:    time.Date(2015, 4, 1, 13, 0, 0, 0, time.UTC),
+		Duration:   500 * time.Millisecond,
+		Result:     "exit status 1",
+	}
+	err := s.unit.AddHookRecord(relationHook)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertHistory(c, append(records, relationHook))
+}
+
+func (s *HookHistorySuite) TestAddActionAndCommandsRecords(c *gc.C) {
+	records := []state.HookRecord{{
+		Kind:     "action",
+		Hook:     "snapshot",
+		Started:  time.Date(2015, 4, 1, 12, 0, 0, 0, time.UTC),
+		Duration: 2 * time.Second,
+		Result:   "backup volume missing",
+	}, {
+		Kind:       "commands",
+		Hook:       "juju-run",
+		Relation:   "db:0",
+		RemoteUnit: "mysql/0",
+		Started:    time.Date(2015, 4, 1, 12, 1, 0, 0, time.UTC),
+		Duration:   time.Second,
+		Result:     "ok",
+	}}
+	for _, record := range records {
+		err := s.unit.AddHookRecord(record)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	s.assertHistory(c, records)
+}
+
+func (s *HookHistorySuite) TestAddHookRecordMissingHook(c *gc.C) {
+	err := s.unit.AddHookRecord(state.HookRecord{Kind: "hook", Result: "ok"})
+	c.Assert(err, gc.ErrorMatches, `cannot add hook record for unit ".*": missing hook name`)
+}
+
+func (s *HookHistorySuite) TestAddHookRecordInvalidKind(c *gc.C) {
+	err := s.unit.AddHookRecord(state.HookRecord{Kind: "relation", Hook: "install", Result: "ok"})
+	c.Assert(err, gc.ErrorMatches, `cannot add hook record for unit ".*": invalid record kind "relation"`)
+	err = s.unit.AddHookRecord(state.HookRecord{Hook: "install", Result: "ok"})
+	c.Assert(err, gc.ErrorMatches, `cannot add hook record for unit ".*": invalid record kind ""`)
+}
+
+func (s *HookHistorySuite) TestHistoryIsCapped(c *gc.C) {
+	s.PatchValue(state.HookHistoryLimit, 3)
+	var hooks []string
+	for i := 0; i < 5; i++ {
+		hooks = append(hooks, fmt.Sprintf("hook-%d", i))
+	}
+	records := s.addRecords(c, hooks...)
+	s.assertHistory(c, records[2:])
+}
+
+func (s *HookHistorySuite) TestAddHookRecordRemovedUnit(c *gc.C) {
+	err := s.unit.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.AddHookRecord(state.HookRecord{Kind: "hook", Hook: "stop", Result: "ok"})
+	c.Assert(err, gc.ErrorMatches, `cannot add hook record for unit ".*": unit not found`)
+	s.assertNoSequence(c)
+}
+
+func (s *HookHistorySuite) assertNoSequence(c *gc.C) {
+	sequences, closer := state.GetRawCollection(s.State, "sequence")
+	defer closer()
+	docID := state.DocID(s.State, "hookhistory-"+s.unit.Name())
+	count, err := sequences.FindId(docID).Count()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(count, gc.Equals, 0)
+}
+
+func (s *HookHistorySuite) TestHistoryRemovedWithUnit(c *gc.C) {
+	s.addRecords(c, "install", "start")
+	err := s.unit.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertNoSequence(c)
+	err = s.State.Cleanup()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertHistory(c, nil)
+}
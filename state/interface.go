@@ -205,6 +205,7 @@ type ActionReceiver interface {
 
 var (
 	_ ActionReceiver = (*Unit)(nil)
+	_ ActionReceiver = (*Machine)(nil)
 	// TODO(jcw4) - use when Actions can be queued for Services.
 	//_ ActionReceiver = (*Service)(nil)
 )
@@ -0,0 +1,58 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"crypto/rand"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+const macaroonRootKeyKey = "macaroonRootKey"
+
+// macaroonRootKeyDoc holds the key used by the state
+// servers to sign the macaroons they issue.
+type macaroonRootKeyDoc struct {
+	RootKey []byte `bson:"rootkey"`
+}
+
+// MacaroonRootKey returns the key with which the state servers sign
+// the macaroons that let externally authenticated users log in. The
+// key is generated the first time it is asked for, and shared by all
+// the state servers from then on.
+func (st *State) MacaroonRootKey() ([]byte, error) {
+	stateServers, closer := st.getCollection(stateServersC)
+	defer closer()
+
+	var doc macaroonRootKeyDoc
+	err := stateServers.Find(bson.D{{"_id", macaroonRootKeyKey}}).One(&doc)
+	if err == nil {
+		return doc.RootKey, nil
+	}
+	if err != mgo.ErrNotFound {
+		return nil, errors.Annotate(err, "cannot get macaroon root key")
+	}
+	doc.RootKey = make([]byte, 24)
+	if _, err := rand.Read(doc.RootKey); err != nil {
+		return nil, errors.Annotate(err, "cannot generate macaroon root key")
+	}
+	ops := []txn.Op{{
+		C:      stateServersC,
+		Id:     macaroonRootKeyKey,
+		Assert: txn.DocMissing,
+		Insert: &doc,
+	}}
+	err = st.runTransaction(ops)
+	if err == txn.ErrAborted {
+		// Another state server stored its key first.
+		err = stateServers.Find(bson.D{{"_id", macaroonRootKeyKey}}).One(&doc)
+	}
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot set macaroon root key")
+	}
+	return doc.RootKey, nil
+}
@@ -0,0 +1,26 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+)
+
+type MacaroonSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&MacaroonSuite{})
+
+func (s *MacaroonSuite) TestMacaroonRootKey(c *gc.C) {
+	rootKey, err := s.State.MacaroonRootKey()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rootKey, gc.HasLen, 24)
+
+	// The key is only generated once.
+	again, err := s.State.MacaroonRootKey()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(again, jc.DeepEquals, rootKey)
+}
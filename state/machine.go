@@ -18,6 +18,7 @@ import (
 	"gopkg.in/mgo.v2/bson"
 	"gopkg.in/mgo.v2/txn"
 
+	"github.com/juju/juju/actions"
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/mongo"
@@ -122,8 +123,14 @@ type machineDoc struct {
 	// Placement is the placement directive that should be used when provisioning
 	// an instance for the machine.
 	Placement string `bson:",omitempty"`
+	// Maintenance is true while the machine is in maintenance mode,
+	// during which no new units or containers are placed on it.
+	Maintenance bool `bson:",omitempty"`
 }
 
+// notInMaintenanceDoc asserts that a machine is not in maintenance.
+var notInMaintenanceDoc = bson.D{{"maintenance", bson.D{{"$ne", true}}}}
+
 func newMachine(st *State, doc *machineDoc) *Machine {
 	machine := &Machine{
 		st:  st,
@@ -175,6 +182,10 @@ type instanceData struct {
 	CpuPower   *uint64     `bson:"cpupower,omitempty"`
 	Tags       *[]string   `bson:"tags,omitempty"`
 	AvailZone  *string     `bson:"availzone,omitempty"`
+
+	// InstanceTags holds the tags that the provider has set
+	// on the instance.
+	InstanceTags map[string]string `bson:"instancetags,omitempty"`
 }
 
 func hardwareCharacteristics(instData instanceData) *instance.HardwareCharacteristics {
@@ -266,6 +277,29 @@ func (m *Machine) SetHasVote(hasVote bool) error {
 	return nil
 }
 
+// InMaintenance reports whether the machine is in maintenance mode.
+// New units and containers are not placed on a machine in maintenance.
+func (m *Machine) InMaintenance() bool {
+	return m.doc.Maintenance
+}
+
+// SetMaintenance puts the machine into maintenance mode, or takes it
+// out again. Units and containers already on the machine are not
+// affected.
+func (m *Machine) SetMaintenance(maintenance bool) error {
+	ops := []txn.Op{{
+		C:      machinesC,
+		Id:     m.doc.DocID,
+		Assert: notDeadDoc,
+		Update: bson.D{{"$set", bson.D{{"maintenance", maintenance}}}},
+	}}
+	if err := m.st.runTransaction(ops); err != nil {
+		return fmt.Errorf("cannot set maintenance mode of machine %v: %v", m, onAbort(err, ErrDead))
+	}
+	m.doc.Maintenance = maintenance
+	return nil
+}
+
 // IsManager returns true if the machine has JobManageEnviron.
 func (m *Machine) IsManager() bool {
 	return hasJob(m.doc.Jobs, JobManageEnviron)
@@ -448,6 +482,12 @@ func IsHasAssignedUnitsError(err error) bool {
 	return ok
 }
 
+// Principals returns the names of the principal units
+// assigned to the machine.
+func (m *Machine) Principals() []string {
+	return m.doc.Principals
+}
+
 // Containers returns the container ids belonging to a parent machine.
 // TODO(wallyworld): move this method to a service
 func (m *Machine) Containers() ([]string, error) {
@@ -792,6 +832,91 @@ func (m *Machine) SetInstanceStatus(status string) (err error) {
 	return errors.NotProvisionedf("machine %v", m.Id())
 }
 
+// InstanceTags returns the tags that the provider has set on the
+// machine's instance, or a NotProvisionedError if the instance is
+// not yet provisioned. Instances of providers that do not support
+// tagging have no tags.
+func (m *Machine) InstanceTags() (map[string]string, error) {
+	instData, err := getInstanceData(m.st, m.Id())
+	if errors.IsNotFound(err) {
+		err = errors.NotProvisionedf("machine %v", m.Id())
+	}
+	if err != nil {
+		return nil, err
+	}
+	return instData.InstanceTags, nil
+}
+
+// SetInstanceTags records the tags that the provider has set on
+// the machine's instance.
+func (m *Machine) SetInstanceTags(tags map[string]string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set instance tags for machine %q", m)
+
+	ops := []txn.Op{
+		{
+			C:      instanceDataC,
+			Id:     m.doc.DocID,
+			Assert: txn.DocExists,
+			Update: bson.D{{"$set", bson.D{{"instancetags", tags}}}},
+		},
+	}
+
+	if err = m.st.runTransaction(ops); err == nil {
+		return nil
+	} else if err != txn.ErrAborted {
+		return err
+	}
+	return errors.NotProvisionedf("machine %v", m.Id())
+}
+
+// SetInstanceLost records that the machine's instance, which must have
+// the given id, no longer exists in the provider; for example, because
+// the cloud terminated it. The machine's instance data and nonce are
+// cleared and its status is set to a transient error, so that the
+// provisioner will start a replacement instance for it when it next
+// retries failed machines. State server machines cannot be replaced
+// this way.
+func (m *Machine) SetInstanceLost(id instance.Id) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set instance of machine %q lost", m)
+
+	if m.IsManager() {
+		return errors.Errorf("machine is a state server")
+	}
+	doc, err := newMachineStatusDoc(
+		StatusError,
+		fmt.Sprintf("instance %q no longer exists in the provider", id),
+		map[string]interface{}{"transient": true},
+		false,
+	)
+	if err != nil {
+		return err
+	}
+	ops := []txn.Op{{
+		C:      machinesC,
+		Id:     m.doc.DocID,
+		Assert: isAliveDoc,
+		Update: bson.D{{"$set", bson.D{{"nonce", ""}}}},
+	}, {
+		C:      instanceDataC,
+		Id:     m.doc.DocID,
+		Assert: bson.D{{"instanceid", id}},
+		Remove: true,
+	},
+		updateStatusOp(m.st, m.globalKey(), doc.statusDoc),
+	}
+	if err = m.st.runTransaction(ops); err == nil {
+		m.doc.Nonce = ""
+		return nil
+	} else if err != txn.ErrAborted {
+		return err
+	} else if alive, err := isAlive(m.st, machinesC, m.doc.DocID); err != nil {
+		return err
+	} else if !alive {
+		return errNotAlive
+	}
+	return errors.Errorf("instance %q is not the machine's instance", id)
+}
+
 // AvailabilityZone returns the provier-specific instance availability
 // zone in which the machine was provisioned.
 func (m *Machine) AvailabilityZone() (string, error) {
@@ -1183,7 +1308,8 @@ func (m *Machine) Constraints() (constraints.Value, error) {
 
 // SetConstraints sets the exact constraints to apply when provisioning an
 // instance for the machine. It will fail if the machine is Dead, or if it
-// is already provisioned.
+// is already provisioned and is not a container; the resource limits of
+// a provisioned container are updated to enforce its new constraints.
 func (m *Machine) SetConstraints(cons constraints.Value) (err error) {
 	defer errors.DeferredAnnotatef(&err, "cannot set constraints")
 	unsupported, err := m.st.validateConstraints(cons)
@@ -1193,15 +1319,6 @@ func (m *Machine) SetConstraints(cons constraints.Value) (err error) {
 	} else if err != nil {
 		return err
 	}
-	notSetYet := bson.D{{"nonce", ""}}
-	ops := []txn.Op{
-		{
-			C:      machinesC,
-			Id:     m.doc.DocID,
-			Assert: append(isAliveDoc, notSetYet...),
-		},
-		setConstraintsOp(m.st, m.globalKey(), cons),
-	}
 	// make multiple attempts to push the ErrExcessiveContention case out of the
 	// realm of plausibility: it implies local state indicating unprovisioned,
 	// and remote state indicating provisioned (reasonable); but which changes
@@ -1216,12 +1333,24 @@ func (m *Machine) SetConstraints(cons constraints.Value) (err error) {
 		if m.doc.Life != Alive {
 			return nil, errNotAlive
 		}
-		if _, err := m.InstanceId(); err == nil {
-			return nil, fmt.Errorf("machine is already provisioned")
-		} else if !errors.IsNotProvisioned(err) {
-			return nil, err
+		assert := isAliveDoc
+		if m.ContainerType() == "" {
+			if _, err := m.InstanceId(); err == nil {
+				return nil, fmt.Errorf("machine is already provisioned")
+			} else if !errors.IsNotProvisioned(err) {
+				return nil, err
+			}
+			notSetYet := bson.D{{"nonce", ""}}
+			assert = append(isAliveDoc, notSetYet...)
 		}
-		return ops, nil
+		return []txn.Op{
+			{
+				C:      machinesC,
+				Id:     m.doc.DocID,
+				Assert: assert,
+			},
+			setConstraintsOp(m.st, m.globalKey(), cons),
+		}, nil
 	}
 	return m.st.run(buildTxn)
 }
@@ -1380,3 +1509,50 @@ func (m *Machine) BlockDevices() ([]BlockDevice, error) {
 	}
 	return result, nil
 }
+
+// AddAction queues the predefined action with the given name and
+// payload on the machine. Machines have no charm, so only the actions
+// juju provides itself can be run on them.
+func (m *Machine) AddAction(name string, payload map[string]interface{}) (*Action, error) {
+	spec, ok := actions.PredefinedActionsSpec[name]
+	if !ok {
+		return nil, errors.Errorf("cannot add action %q to a machine; only predefined actions allowed", name)
+	}
+	if _, err := spec.ValidateParams(payload); err != nil {
+		return nil, err
+	}
+	return m.st.EnqueueAction(m.Tag(), name, payload)
+}
+
+// CancelAction removes a pending Action from the queue for this
+// ActionReceiver and marks it as cancelled.
+func (m *Machine) CancelAction(action *Action) (*Action, error) {
+	return action.Finish(ActionResults{Status: ActionCancelled})
+}
+
+// WatchActionNotifications starts and returns a StringsWatcher that
+// notifies when actions with Id prefixes matching this Machine are added
+func (m *Machine) WatchActionNotifications() StringsWatcher {
+	return m.st.watchEnqueuedActionsFilteredBy(m)
+}
+
+// Actions returns a list of actions pending or completed for this machine.
+func (m *Machine) Actions() ([]*Action, error) {
+	return m.st.matchingActions(m)
+}
+
+// CompletedActions returns a list of actions that have finished for
+// this machine.
+func (m *Machine) CompletedActions() ([]*Action, error) {
+	return m.st.matchingActionsCompleted(m)
+}
+
+// PendingActions returns a list of actions pending for this machine.
+func (m *Machine) PendingActions() ([]*Action, error) {
+	return m.st.matchingActionsPending(m)
+}
+
+// RunningActions returns a list of actions running on this machine.
+func (m *Machine) RunningActions() ([]*Action, error) {
+	return m.st.matchingActionsRunning(m)
+}
@@ -394,6 +394,32 @@ func (s *MachineSuite) TestHasVote(c *gc.C) {
 	c.Assert(s.machine.HasVote(), jc.IsFalse)
 }
 
+func (s *MachineSuite) TestSetMaintenance(c *gc.C) {
+	c.Assert(s.machine.InMaintenance(), jc.IsFalse)
+
+	err := s.machine.SetMaintenance(true)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.machine.InMaintenance(), jc.IsTrue)
+
+	m, err := s.State.Machine(s.machine.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m.InMaintenance(), jc.IsTrue)
+
+	err = m.SetMaintenance(false)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m.InMaintenance(), jc.IsFalse)
+	err = s.machine.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.machine.InMaintenance(), jc.IsFalse)
+}
+
+func (s *MachineSuite) TestSetMaintenanceWhenDead(c *gc.C) {
+	err := s.machine.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.SetMaintenance(true)
+	c.Assert(err, gc.ErrorMatches, `cannot set maintenance mode of machine .*: not found or dead`)
+}
+
 func (s *MachineSuite) TestCannotDestroyMachineWithVote(c *gc.C) {
 	err := s.machine.SetHasVote(true)
 	c.Assert(err, jc.ErrorIsNil)
@@ -990,6 +1016,84 @@ func (s *MachineSuite) TestNotProvisionedMachineInstanceStatus(c *gc.C) {
 	c.Assert(err, jc.Satisfies, errors.IsNotProvisioned)
 }
 
+func (s *MachineSuite) TestMachineSetInstanceTags(c *gc.C) {
+	err := s.machine.SetProvisioned("umbrella/0", "fake_nonce", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	tags, err := s.machine.InstanceTags()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(tags, gc.HasLen, 0)
+
+	err = s.machine.SetInstanceTags(map[string]string{"juju-machine-id": "1"})
+	c.Assert(err, jc.ErrorIsNil)
+	tags, err = s.machine.InstanceTags()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(tags, jc.DeepEquals, map[string]string{"juju-machine-id": "1"})
+}
+
+func (s *MachineSuite) TestNotProvisionedMachineSetInstanceTags(c *gc.C) {
+	err := s.machine.SetInstanceTags(map[string]string{"juju-machine-id": "1"})
+	c.Assert(err, gc.ErrorMatches, ".* not provisioned")
+	_, err = s.machine.InstanceTags()
+	c.Assert(err, jc.Satisfies, errors.IsNotProvisioned)
+}
+
+func (s *MachineSuite) TestSetInstanceLost(c *gc.C) {
+	err := s.machine.SetProvisioned("umbrella/0", "fake_nonce", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.machine.SetInstanceLost("umbrella/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.machine.CheckProvisioned("fake_nonce"), jc.IsFalse)
+	_, err = s.machine.InstanceId()
+	c.Assert(err, jc.Satisfies, errors.IsNotProvisioned)
+	status, info, data, err := s.machine.Status()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status, gc.Equals, state.StatusError)
+	c.Assert(info, gc.Equals, `instance "umbrella/0" no longer exists in the provider`)
+	c.Assert(data, jc.DeepEquals, map[string]interface{}{"transient": true})
+
+	// The machine can be provisioned again.
+	err = s.machine.SetProvisioned("umbrella/1", "another_nonce", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.machine.CheckProvisioned("another_nonce"), jc.IsTrue)
+}
+
+func (s *MachineSuite) TestSetInstanceLostWrongInstance(c *gc.C) {
+	err := s.machine.SetProvisioned("umbrella/0", "fake_nonce", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.machine.SetInstanceLost("umbrella/1")
+	c.Assert(err, gc.ErrorMatches, `cannot set instance of machine "1" lost: instance "umbrella/1" is not the machine's instance`)
+	id, err := s.machine.InstanceId()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(id, gc.Equals, instance.Id("umbrella/0"))
+}
+
+func (s *MachineSuite) TestSetInstanceLostNotProvisioned(c *gc.C) {
+	err := s.machine.SetInstanceLost("umbrella/0")
+	c.Assert(err, gc.ErrorMatches, `cannot set instance of machine "1" lost: instance "umbrella/0" is not the machine's instance`)
+}
+
+func (s *MachineSuite) TestSetInstanceLostNotAlive(c *gc.C) {
+	err := s.machine.SetProvisioned("umbrella/0", "fake_nonce", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.machine.SetInstanceLost("umbrella/0")
+	c.Assert(err, gc.ErrorMatches, `cannot set instance of machine "1" lost: not found or not alive`)
+}
+
+func (s *MachineSuite) TestSetInstanceLostStateServer(c *gc.C) {
+	err := s.machine0.SetProvisioned("umbrella/0", "fake_nonce", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.machine0.SetInstanceLost("umbrella/0")
+	c.Assert(err, gc.ErrorMatches, `cannot set instance of machine "0" lost: machine is a state server`)
+}
+
 func (s *MachineSuite) TestMachineRefresh(c *gc.C) {
 	m0, err := s.State.AddMachine("quantal", state.JobHostUnits)
 	c.Assert(err, jc.ErrorIsNil)
@@ -1453,6 +1557,51 @@ func (s *MachineSuite) TestSetConstraints(c *gc.C) {
 	c.Assert(mcons, gc.DeepEquals, cons1)
 }
 
+func (s *MachineSuite) TestSetConstraintsOnProvisionedContainer(c *gc.C) {
+	container, err := s.State.AddMachineInsideMachine(state.MachineTemplate{
+		Series:      "quantal",
+		Jobs:        []state.MachineJob{state.JobHostUnits},
+		Constraints: constraints.MustParse("mem=1G"),
+	}, s.machine.Id(), instance.LXC)
+	c.Assert(err, jc.ErrorIsNil)
+	err = container.SetProvisioned("juju-machine-1-lxc-0", "fake_nonce", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The constraints of a container can still be changed, as its
+	// resource limits are updated to enforce them.
+	cons := constraints.MustParse("mem=2G")
+	err = container.SetConstraints(cons)
+	c.Assert(err, jc.ErrorIsNil)
+	mcons, err := container.Constraints()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(mcons, gc.DeepEquals, cons)
+}
+
+func (s *MachineSuite) TestWatchConstraints(c *gc.C) {
+	w := s.machine.WatchConstraints()
+	defer testing.AssertStop(c, w)
+
+	// Initial event.
+	wc := testing.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	// Make one change (to a separate instance), check one event.
+	machine, err := s.State.Machine(s.machine.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.SetConstraints(constraints.MustParse("mem=4G"))
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	// Changes to the machine document do not trigger events.
+	err = machine.SetPassword("arble-farble-dying-yarble")
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertNoChange()
+
+	// Stop, check closed.
+	testing.AssertStop(c, w)
+	wc.AssertClosed()
+}
+
 func (s *MachineSuite) TestSetAmbiguousConstraints(c *gc.C) {
 	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
 	c.Assert(err, jc.ErrorIsNil)
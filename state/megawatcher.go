@@ -319,6 +319,9 @@ func (s *backingStatus) updated(st *State, store *multiwatcherStore, id interfac
 		newInfo.StatusInfo = s.StatusInfo
 		newInfo.StatusData = s.StatusData
 		info0 = &newInfo
+	case *multiwatcher.ServiceInfo:
+		// Service status is not yet reported by the watcher.
+		return nil
 	default:
 		panic(fmt.Errorf("status for unexpected entity with id %q; type %T", id, info))
 	}
@@ -134,7 +134,7 @@ func (st *State) envSetupOps(cfg *config.Config, serverUUID string, owner names.
 	if serverUUID == "" {
 		serverUUID = uuid
 	}
-	envUserOp, _ := createEnvUserOpAndDoc(uuid, owner, owner, owner.Name())
+	envUserOp, _ := createEnvUserOpAndDoc(uuid, owner, owner, owner.Name(), EnvironAdminAccess)
 	ops := []txn.Op{
 		createConstraintsOp(st, environGlobalKey, constraints.Value{}),
 		createSettingsOp(st, environGlobalKey, cfg.AllAttrs()),
@@ -169,6 +169,7 @@ var indexes = []struct {
 	{subnetsC, []string{"providerid"}, true, true},
 	{ipaddressesC, []string{"state"}, false, false},
 	{ipaddressesC, []string{"subnetid"}, false, false},
+	{hookHistoryC, []string{"env-uuid", "unit", "seq"}, false, false},
 }
 
 // The capped collection used for transaction logs defaults to 10MB.
@@ -279,6 +280,9 @@ func (st *State) CACert() string {
 }
 
 func (st *State) Close() (err error) {
+	if st.parent != nil {
+		return st.parent.Close()
+	}
 	defer errors.DeferredAnnotatef(&err, "closing state failed")
 	err1 := st.watcher.Stop()
 	err2 := st.pwatcher.Stop()
@@ -0,0 +1,227 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"sort"
+
+	"github.com/juju/errors"
+	jujutxn "github.com/juju/txn"
+	"gopkg.in/juju/charm.v4"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// rolloutDoc records the progress of a rolling charm upgrade. It is
+// held in the service document while the upgrade is in progress, so
+// that units watching the service see each new batch as it is allowed
+// to upgrade.
+type rolloutDoc struct {
+	// FromCharmURL holds the charm URL the service was using when the
+	// rollout started. Units not yet allowed to upgrade stay on it.
+	FromCharmURL *charm.URL `bson:"fromcharmurl"`
+	BatchSize    int        `bson:"batchsize"`
+	WaitHealthy  bool       `bson:"waithealthy"`
+
+	// Upgrading holds the names of the units allowed to upgrade so far.
+	Upgrading []string `bson:"upgrading"`
+	Paused    bool     `bson:"paused"`
+	Revno     int      `bson:"revno"`
+}
+
+// admits returns whether the rollout allows the unit to upgrade to the
+// service charm with the given URL. Units already running that charm,
+// or not yet running any, are always allowed.
+func (r *rolloutDoc) admits(u *Unit, curl *charm.URL) bool {
+	if unitURL, ok := u.CharmURL(); !ok || *unitURL == *curl {
+		return true
+	}
+	for _, name := range r.Upgrading {
+		if name == u.Name() {
+			return true
+		}
+	}
+	return false
+}
+
+// RolloutStatus describes the progress of a rolling charm upgrade.
+type RolloutStatus struct {
+	// BatchSize holds the number of units allowed to upgrade at once.
+	BatchSize int
+
+	// WaitHealthy holds whether upgraded units must become idle before
+	// the next batch is allowed to upgrade.
+	WaitHealthy bool
+
+	// Upgrading holds the names of the units allowed to upgrade so far.
+	Upgrading []string
+
+	// Paused holds whether the rollout is paused because a unit allowed
+	// to upgrade is in an error state.
+	Paused bool
+}
+
+// Rollout returns the progress of the service's rolling charm upgrade,
+// and whether one is in progress.
+func (s *Service) Rollout() (RolloutStatus, bool) {
+	r := s.doc.Rollout
+	if r == nil {
+		return RolloutStatus{}, false
+	}
+	return RolloutStatus{
+		BatchSize:   r.BatchSize,
+		WaitHealthy: r.WaitHealthy,
+		Upgrading:   append([]string(nil), r.Upgrading...),
+		Paused:      r.Paused,
+	}, true
+}
+
+// SetCharmRollout changes the charm for the service as SetCharm does,
+// but only allows batchSize of its existing units to upgrade at first.
+// Further batches are allowed to upgrade by AdvanceRollout once all the
+// units allowed so far have upgraded and, if waitHealthy is true, have
+// become idle.
+func (s *Service) SetCharmRollout(ch *Charm, force bool, batchSize int, waitHealthy bool) error {
+	if batchSize < 1 {
+		return errors.Errorf("cannot start rolling upgrade for service %q: batch size must be positive", s)
+	}
+	return s.setCharm(ch, force, batchSize, waitHealthy)
+}
+
+// newRollout returns a rollout document for a rolling upgrade of the
+// service's units, which allows the first batch of them to upgrade.
+func (s *Service) newRollout(batchSize int, waitHealthy bool) (*rolloutDoc, error) {
+	units, err := s.AllUnits()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var names []string
+	for _, u := range units {
+		if u.Life() == Alive {
+			names = append(names, u.Name())
+		}
+	}
+	sort.Strings(names)
+	if len(names) > batchSize {
+		names = names[:batchSize]
+	}
+	return &rolloutDoc{
+		FromCharmURL: s.doc.CharmURL,
+		BatchSize:    batchSize,
+		WaitHealthy:  waitHealthy,
+		Upgrading:    names,
+	}, nil
+}
+
+// setRolloutOp returns the operation that records the given rollout in
+// the service document, or removes any rollout if it is nil.
+func setRolloutOp(st *State, serviceName string, rollout *rolloutDoc) txn.Op {
+	update := bson.D{{"$unset", bson.D{{"rollout", nil}}}}
+	if rollout != nil {
+		update = bson.D{{"$set", bson.D{{"rollout", rollout}}}}
+	}
+	return txn.Op{
+		C:      servicesC,
+		Id:     st.docID(serviceName),
+		Update: update,
+	}
+}
+
+// AdvanceRollout allows the next batch of the service's units to upgrade
+// once every unit allowed to upgrade so far has done so (and become idle,
+// if the rollout requires it). The rollout is paused while any of those
+// units is in an error state, and it finishes once every unit has been
+// allowed to upgrade. It does nothing if no rollout is in progress.
+func (s *Service) AdvanceRollout() error {
+	svc := &Service{st: s.st, doc: s.doc}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := svc.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		r := svc.doc.Rollout
+		if r == nil {
+			return nil, jujutxn.ErrNoOperations
+		}
+		units, err := svc.AllUnits()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ready, paused := true, false
+		var waiting []string
+		for _, u := range units {
+			if u.Life() != Alive {
+				continue
+			}
+			if !r.admits(u, svc.doc.CharmURL) {
+				waiting = append(waiting, u.Name())
+				continue
+			}
+			status, info, _, err := u.Status()
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			unitURL, _ := u.CharmURL()
+			switch {
+			case status == StatusError:
+				paused = true
+			case unitURL == nil || *unitURL != *svc.doc.CharmURL:
+				ready = false
+			case r.WaitHealthy && (status != StatusActive || info != ""):
+				ready = false
+			}
+		}
+		var update bson.D
+		switch {
+		case paused || !ready:
+			if paused == r.Paused {
+				return nil, jujutxn.ErrNoOperations
+			}
+			update = bson.D{
+				{"$set", bson.D{{"rollout.paused", paused}}},
+				{"$inc", bson.D{{"rollout.revno", 1}}},
+			}
+		case len(waiting) == 0:
+			update = bson.D{{"$unset", bson.D{{"rollout", nil}}}}
+		default:
+			sort.Strings(waiting)
+			if len(waiting) > r.BatchSize {
+				waiting = waiting[:r.BatchSize]
+			}
+			upgrading := append(append([]string(nil), r.Upgrading...), waiting...)
+			update = bson.D{
+				{"$set", bson.D{{"rollout.upgrading", upgrading}, {"rollout.paused", false}}},
+				{"$inc", bson.D{{"rollout.revno", 1}}},
+			}
+		}
+		return []txn.Op{{
+			C:      servicesC,
+			Id:     svc.doc.DocID,
+			Assert: bson.D{{"charmurl", svc.doc.CharmURL}, {"rollout.revno", r.Revno}},
+			Update: update,
+		}}, nil
+	}
+	if err := s.st.run(buildTxn); err != nil {
+		return errors.Annotatef(err, "cannot advance rolling upgrade for service %q", s)
+	}
+	return nil
+}
+
+// UpgradeCharmURL returns the URL of the charm the unit should be
+// running, and whether it should upgrade to that charm even if it is
+// in an error state. This is the service's charm unless a rolling
+// upgrade is in progress and has not yet allowed the unit to upgrade,
+// in which case it is the charm the service used before the upgrade.
+func (u *Unit) UpgradeCharmURL() (*charm.URL, bool, error) {
+	svc, err := u.Service()
+	if err != nil {
+		return nil, false, errors.Trace(err)
+	}
+	curl, force := svc.CharmURL()
+	if r := svc.doc.Rollout; r != nil && !r.admits(u, curl) {
+		return r.FromCharmURL, false, nil
+	}
+	return curl, force, nil
+}
@@ -0,0 +1,190 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+)
+
+type RolloutSuite struct {
+	ConnSuite
+	charm    *state.Charm
+	newCharm *state.Charm
+	mysql    *state.Service
+	units    []*state.Unit
+}
+
+var _ = gc.Suite(&RolloutSuite{})
+
+func (s *RolloutSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.charm = s.AddTestingCharm(c, "mysql")
+	s.newCharm = s.AddMetaCharm(c, "mysql", metaBase, 2)
+	s.mysql = s.AddTestingService(c, "mysql", s.charm)
+	s.units = nil
+	for i := 0; i < 3; i++ {
+		unit, err := s.mysql.AddUnit()
+		c.Assert(err, jc.ErrorIsNil)
+		err = unit.SetCharmURL(s.charm.URL())
+		c.Assert(err, jc.ErrorIsNil)
+		err = unit.SetStatus(state.StatusActive, "", nil)
+		c.Assert(err, jc.ErrorIsNil)
+		s.units = append(s.units, unit)
+	}
+}
+
+func (s *RolloutSuite) assertRollout(c *gc.C, expect *state.RolloutStatus) {
+	err := s.mysql.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	rollout, ok := s.mysql.Rollout()
+	if expect == nil {
+		c.Assert(ok, jc.IsFalse)
+		return
+	}
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(rollout, jc.DeepEquals, *expect)
+}
+
+func (s *RolloutSuite) assertUpgradeCharmURL(c *gc.C, unit *state.Unit, expect *state.Charm) {
+	curl, _, err := unit.UpgradeCharmURL()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(curl, gc.DeepEquals, expect.URL())
+}
+
+func (s *RolloutSuite) upgradeUnit(c *gc.C, unit *state.Unit) {
+	err := unit.SetCharmURL(s.newCharm.URL())
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *RolloutSuite) TestSetCharmRolloutInvalidBatchSize(c *gc.C) {
+	err := s.mysql.SetCharmRollout(s.newCharm, false, 0, false)
+	c.Assert(err, gc.ErrorMatches, `cannot start rolling upgrade for service "mysql": batch size must be positive`)
+	s.assertRollout(c, nil)
+}
+
+func (s *RolloutSuite) TestSetCharmRollout(c *gc.C) {
+	err := s.mysql.SetCharmRollout(s.newCharm, true, 2, false)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertRollout(c, &state.RolloutStatus{
+		BatchSize: 2,
+		Upgrading: []string{"mysql/0", "mysql/1"},
+	})
+	curl, force := s.mysql.CharmURL()
+	c.Assert(curl, gc.DeepEquals, s.newCharm.URL())
+	c.Assert(force, jc.IsTrue)
+
+	s.assertUpgradeCharmURL(c, s.units[0], s.newCharm)
+	s.assertUpgradeCharmURL(c, s.units[1], s.newCharm)
+	curl, force, err = s.units[2].UpgradeCharmURL()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(curl, gc.DeepEquals, s.charm.URL())
+	c.Assert(force, jc.IsFalse)
+
+	// Units added during the rollout use the new charm.
+	unit, err := s.mysql.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertUpgradeCharmURL(c, unit, s.newCharm)
+}
+
+func (s *RolloutSuite) TestAdvanceRollout(c *gc.C) {
+	err := s.mysql.SetCharmRollout(s.newCharm, false, 2, false)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Nothing happens until the first batch has upgraded.
+	s.upgradeUnit(c, s.units[0])
+	err = s.mysql.AdvanceRollout()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertRollout(c, &state.RolloutStatus{
+		BatchSize: 2,
+		Upgrading: []string{"mysql/0", "mysql/1"},
+	})
+	s.assertUpgradeCharmURL(c, s.units[2], s.charm)
+
+	s.upgradeUnit(c, s.units[1])
+	err = s.mysql.AdvanceRollout()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertRollout(c, &state.RolloutStatus{
+		BatchSize: 2,
+		Upgrading: []string{"mysql/0", "mysql/1", "mysql/2"},
+	})
+	s.assertUpgradeCharmURL(c, s.units[2], s.newCharm)
+
+	// The rollout finishes once every unit has upgraded.
+	s.upgradeUnit(c, s.units[2])
+	err = s.mysql.AdvanceRollout()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertRollout(c, nil)
+
+	// Advancing without a rollout does nothing.
+	err = s.mysql.AdvanceRollout()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *RolloutSuite) TestAdvanceRolloutPausesOnError(c *gc.C) {
+	err := s.mysql.SetCharmRollout(s.newCharm, false, 1, false)
+	c.Assert(err, jc.ErrorIsNil)
+	s.upgradeUnit(c, s.units[0])
+	err = s.units[0].SetStatus(state.StatusError, "hook failed", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.mysql.AdvanceRollout()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertRollout(c, &state.RolloutStatus{
+		BatchSize: 1,
+		Upgrading: []string{"mysql/0"},
+		Paused:    true,
+	})
+	s.assertUpgradeCharmURL(c, s.units[1], s.charm)
+
+	// Once the error is resolved the rollout carries on.
+	err = s.units[0].SetStatus(state.StatusActive, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysql.AdvanceRollout()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertRollout(c, &state.RolloutStatus{
+		BatchSize: 1,
+		Upgrading: []string{"mysql/0", "mysql/1"},
+	})
+}
+
+func (s *RolloutSuite) TestAdvanceRolloutWaitHealthy(c *gc.C) {
+	err := s.mysql.SetCharmRollout(s.newCharm, false, 2, true)
+	c.Assert(err, jc.ErrorIsNil)
+	s.upgradeUnit(c, s.units[0])
+	s.upgradeUnit(c, s.units[1])
+	err = s.units[1].SetStatus(state.StatusActive, "running upgrade-charm hook", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.mysql.AdvanceRollout()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertRollout(c, &state.RolloutStatus{
+		BatchSize:   2,
+		WaitHealthy: true,
+		Upgrading:   []string{"mysql/0", "mysql/1"},
+	})
+
+	err = s.units[1].SetStatus(state.StatusActive, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysql.AdvanceRollout()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertRollout(c, &state.RolloutStatus{
+		BatchSize:   2,
+		WaitHealthy: true,
+		Upgrading:   []string{"mysql/0", "mysql/1", "mysql/2"},
+	})
+}
+
+func (s *RolloutSuite) TestSetCharmAbandonsRollout(c *gc.C) {
+	err := s.mysql.SetCharmRollout(s.newCharm, false, 1, false)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysql.RollbackCharm(false)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertRollout(c, nil)
+	for _, unit := range s.units {
+		s.assertUpgradeCharmURL(c, unit, s.charm)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/names"
@@ -46,6 +47,20 @@ type serviceDoc struct {
 	OwnerTag          string     `bson:"ownertag"`
 	TxnRevno          int64      `bson:"txn-revno"`
 	MetricCredentials []byte     `bson:"metric-credentials"`
+
+	// HookTimeout, if set, overrides the environment's hook-timeout
+	// setting for the service's units.
+	HookTimeout time.Duration `bson:"hooktimeout,omitempty"`
+
+	// PreviousCharmURL holds the charm URL the service was using
+	// before its most recent charm change, so that the change can
+	// be rolled back. The service holds a reference to its settings
+	// for that charm until its charm next changes.
+	PreviousCharmURL *charm.URL `bson:"previouscharmurl,omitempty"`
+
+	// Rollout holds the progress of a rolling charm upgrade, if one
+	// is in progress.
+	Rollout *rolloutDoc `bson:"rollout,omitempty"`
 }
 
 func newService(st *State, doc *serviceDoc) *Service {
@@ -229,10 +244,69 @@ func (s *Service) removeOps(asserts bson.D) []txn.Op {
 		removeConstraintsOp(s.st, s.globalKey()),
 		annotationRemoveOp(s.st, s.globalKey()),
 		removeLeadershipSettingsOp(s.Tag().Id()),
+		removeStatusOp(s.st, s.globalKey()),
+	}
+	if prevURL := s.doc.PreviousCharmURL; prevURL != nil {
+		// Release the settings kept for the previous charm; with
+		// no units left, nothing else refers to them.
+		prevDocID := s.st.docID(serviceSettingsKey(s.doc.Name, prevURL))
+		ops = append(ops, txn.Op{
+			C:      settingsrefsC,
+			Id:     prevDocID,
+			Remove: true,
+		}, txn.Op{
+			C:      settingsC,
+			Id:     prevDocID,
+			Remove: true,
+		})
 	}
 	return ops
 }
 
+// Status returns the status of the service, as last reported by the
+// charm of its leader unit.
+func (s *Service) Status() (status Status, info string, data map[string]interface{}, err error) {
+	doc, err := getWorkloadStatus(s.st, s.globalKey())
+	if err != nil {
+		return "", "", nil, err
+	}
+	return doc.Status, doc.StatusInfo, doc.StatusData, nil
+}
+
+// SetStatus sets the status of the service. Only the charm of the
+// service's leader unit is expected to do so; that is enforced by the
+// API server, not here.
+func (s *Service) SetStatus(status Status, info string, data map[string]interface{}) error {
+	doc, err := newWorkloadStatusDoc(status, info, data)
+	if err != nil {
+		return errors.Annotatef(err, "cannot set status of service %q", s)
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := s.Refresh(); errors.IsNotFound(err) {
+				return nil, errNotAlive
+			} else if err != nil {
+				return nil, err
+			} else if s.Life() != Alive {
+				return nil, errNotAlive
+			}
+		}
+		statusOp, err := setWorkloadStatusOp(s.st, s.globalKey(), doc.statusDoc)
+		if err != nil {
+			return nil, err
+		}
+		return []txn.Op{{
+			C:      servicesC,
+			Id:     s.doc.DocID,
+			Assert: isAliveDoc,
+		}, statusOp}, nil
+	}
+	if err := s.st.run(buildTxn); err != nil {
+		return errors.Annotatef(err, "cannot set status of service %q", s)
+	}
+	return nil
+}
+
 // IsExposed returns whether this service is exposed. The explicitly open
 // ports (with open-port) for exposed services may be accessed from machines
 // outside of the local deployment network. See SetExposed and ClearExposed.
@@ -266,6 +340,37 @@ func (s *Service) setExposed(exposed bool) (err error) {
 	return nil
 }
 
+// HookTimeout returns how long the service's charm hooks may run before
+// they are killed, overriding the environment's hook-timeout setting.
+// Zero means that the environment setting applies.
+func (s *Service) HookTimeout() time.Duration {
+	return s.doc.HookTimeout
+}
+
+// SetHookTimeout overrides the environment's hook-timeout setting for
+// the service's units. Setting a zero timeout removes the override.
+func (s *Service) SetHookTimeout(timeout time.Duration) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set hook timeout for service %q to %v", s, timeout)
+	if timeout < 0 {
+		return errors.New("timeout must not be negative")
+	}
+	update := bson.D{{"$set", bson.D{{"hooktimeout", timeout}}}}
+	if timeout == 0 {
+		update = bson.D{{"$unset", bson.D{{"hooktimeout", nil}}}}
+	}
+	ops := []txn.Op{{
+		C:      servicesC,
+		Id:     s.doc.DocID,
+		Assert: isAliveDoc,
+		Update: update,
+	}}
+	if err := s.st.runTransaction(ops); err != nil {
+		return onAbort(err, errNotAlive)
+	}
+	s.doc.HookTimeout = timeout
+	return nil
+}
+
 // Charm returns the service's charm and whether units should upgrade to that
 // charm even if they are in an error state.
 func (s *Service) Charm() (ch *Charm, force bool, err error) {
@@ -288,6 +393,13 @@ func (s *Service) CharmURL() (curl *charm.URL, force bool) {
 	return s.doc.CharmURL, s.doc.ForceCharm
 }
 
+// PreviousCharmURL returns the URL of the charm the service was using
+// before its most recent charm change, or nil if the service's charm
+// has never been changed.
+func (s *Service) PreviousCharmURL() *charm.URL {
+	return s.doc.PreviousCharmURL
+}
+
 // Endpoints returns the service's currently available relation endpoints.
 func (s *Service) Endpoints() (eps []Endpoint, err error) {
 	ch, _, err := s.Charm()
@@ -375,69 +487,63 @@ func (s *Service) checkRelationsOps(ch *Charm, relations []*Relation) ([]txn.Op,
 // changeCharmOps returns the operations necessary to set a service's
 // charm URL to a new value.
 func (s *Service) changeCharmOps(ch *Charm, force bool) ([]txn.Op, error) {
-	// Build the new service config from what can be used of the old one.
-	var newSettings charm.Settings
-	oldSettings, err := readSettings(s.st, s.settingsKey())
-	if err == nil {
-		// Filter the old settings through to get the new settings.
-		newSettings = ch.Config().FilterSettings(oldSettings.Map())
-	} else if errors.IsNotFound(err) {
-		// No old settings, start with empty new settings.
-		newSettings = make(charm.Settings)
-	} else {
-		return nil, errors.Trace(err)
+	// The service keeps a reference to the settings of its previous
+	// charm until its charm next changes, so that returning to that
+	// charm restores the settings it had.
+	prevURL := s.doc.PreviousCharmURL
+	newKey := serviceSettingsKey(s.doc.Name, ch.URL())
+	var prevSettings *Settings
+	if prevURL != nil && prevURL.String() == ch.URL().String() {
+		var err error
+		prevSettings, err = readSettings(s.st, newKey)
+		if err != nil && !errors.IsNotFound(err) {
+			return nil, errors.Trace(err)
+		}
 	}
+	rollback := prevSettings != nil
 
-	// Create or replace service settings.
-	var settingsOp txn.Op
-	newKey := serviceSettingsKey(s.doc.Name, ch.URL())
-	if _, err := readSettings(s.st, newKey); errors.IsNotFound(err) {
-		// No settings for this key yet, create it.
-		settingsOp = createSettingsOp(s.st, newKey, newSettings)
-	} else if err != nil {
-		return nil, errors.Trace(err)
+	var ops []txn.Op
+	if rollback {
+		// The previous charm's settings are restored as they were
+		// read, and the service's reference to them becomes its
+		// reference to its current charm's settings.
+		ops = append(ops, prevSettings.assertUnchangedOp())
 	} else {
-		// Settings exist, just replace them with the new ones.
-		settingsOp, _, err = replaceSettingsOp(s.st, newKey, newSettings)
+		settingsOps, err := s.newCharmSettingsOps(ch, newKey)
 		if err != nil {
 			return nil, errors.Trace(err)
 		}
+		ops = append(ops, settingsOps...)
 	}
 
-	// Add or create a reference to the new settings doc.
-	incOp, err := settingsIncRefOp(s.st, s.doc.Name, ch.URL(), true)
-	if err != nil {
-		return nil, errors.Trace(err)
-	}
+	// Drop the reference to the previous charm's settings, unless the
+	// service is returning to that charm. The reference to the current
+	// charm's settings is kept, as it becomes the previous charm.
 	var decOps []txn.Op
-	// Drop the reference to the old settings doc (if they exist).
-	if oldSettings != nil {
-		decOps, err = settingsDecRefOps(s.st, s.doc.Name, s.doc.CharmURL) // current charm
-		if err != nil {
+	if prevURL != nil && !rollback {
+		var err error
+		decOps, err = settingsDecRefOps(s.st, s.doc.Name, prevURL)
+		if errors.IsNotFound(err) {
+			decOps = nil
+		} else if err != nil {
 			return nil, errors.Trace(err)
 		}
 	}
 
-	// Build the transaction.
-	var ops []txn.Op
 	differentCharm := bson.D{{"charmurl", bson.D{{"$ne", ch.URL()}}}}
-	if oldSettings != nil {
-		// Old settings shouldn't change (when they exist).
-		ops = append(ops, oldSettings.assertUnchangedOp())
-	}
-	ops = append(ops, []txn.Op{
-		// Create or replace new settings.
-		settingsOp,
-		// Increment the ref count.
-		incOp,
-		// Update the charm URL and force flag (if relevant).
-		{
-			C:      servicesC,
-			Id:     s.doc.DocID,
-			Assert: append(notDeadDoc, differentCharm...),
-			Update: bson.D{{"$set", bson.D{{"charmurl", ch.URL()}, {"forcecharm", force}}}},
-		},
-	}...)
+	samePrevious := bson.D{{"previouscharmurl", prevURL}}
+	ops = append(ops, txn.Op{
+		// Update the charm URL and force flag (if relevant), and
+		// remember the current charm URL so it can be rolled back to.
+		C:      servicesC,
+		Id:     s.doc.DocID,
+		Assert: append(append(notDeadDoc, differentCharm...), samePrevious...),
+		Update: bson.D{{"$set", bson.D{
+			{"charmurl", ch.URL()},
+			{"forcecharm", force},
+			{"previouscharmurl", s.doc.CharmURL},
+		}}},
+	})
 	// Add any extra peer relations that need creation.
 	newPeers := s.extraPeerRelations(ch.Meta())
 	peerOps, err := s.st.addPeerRelationsOps(s.doc.Name, newPeers)
@@ -472,10 +578,64 @@ func (s *Service) changeCharmOps(ch *Charm, force bool) ([]txn.Op, error) {
 	return append(ops, decOps...), nil
 }
 
+// newCharmSettingsOps returns the operations that create or replace
+// the settings of the service for the new charm ch, stored under key,
+// from the settings for its current charm, and take a reference to
+// them.
+func (s *Service) newCharmSettingsOps(ch *Charm, key string) ([]txn.Op, error) {
+	// Build the new service config from what can be used of the old one.
+	var newSettings charm.Settings
+	oldSettings, err := readSettings(s.st, s.settingsKey())
+	if err == nil {
+		// Filter the old settings through to get the new settings.
+		newSettings = ch.Config().FilterSettings(oldSettings.Map())
+	} else if errors.IsNotFound(err) {
+		// No old settings, start with empty new settings.
+		newSettings = make(charm.Settings)
+	} else {
+		return nil, errors.Trace(err)
+	}
+
+	// Create or replace service settings.
+	var settingsOp txn.Op
+	if _, err := readSettings(s.st, key); errors.IsNotFound(err) {
+		// No settings for this key yet, create it.
+		settingsOp = createSettingsOp(s.st, key, newSettings)
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	} else {
+		// Settings exist, just replace them with the new ones.
+		settingsOp, _, err = replaceSettingsOp(s.st, key, newSettings)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+
+	// Add or create a reference to the new settings doc.
+	incOp, err := settingsIncRefOp(s.st, s.doc.Name, ch.URL(), true)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var ops []txn.Op
+	if oldSettings != nil {
+		// Old settings shouldn't change (when they exist).
+		ops = append(ops, oldSettings.assertUnchangedOp())
+	}
+	return append(ops, settingsOp, incOp), nil
+}
+
 // SetCharm changes the charm for the service. New units will be started with
 // this charm, and existing units will be upgraded to use it. If force is true,
-// units will be upgraded even if they are in an error state.
+// units will be upgraded even if they are in an error state. Any rolling
+// upgrade in progress for the service is abandoned.
 func (s *Service) SetCharm(ch *Charm, force bool) error {
+	return s.setCharm(ch, force, 0, false)
+}
+
+// setCharm changes the charm for the service. If batchSize is positive,
+// existing units are upgraded in a rolling fashion, batchSize units at a
+// time; see SetCharmRollout.
+func (s *Service) setCharm(ch *Charm, force bool, batchSize int, waitHealthy bool) error {
 	if ch.Meta().Subordinate != s.doc.Subordinate {
 		return errors.Errorf("cannot change a service's subordinacy")
 	}
@@ -486,6 +646,8 @@ func (s *Service) SetCharm(ch *Charm, force bool) error {
 	services, closer := s.st.getCollection(servicesC)
 	defer closer()
 
+	changed := false
+	var rollout *rolloutDoc
 	buildTxn := func(attempt int) ([]txn.Op, error) {
 		if attempt > 0 {
 			// NOTE: We're explicitly allowing SetCharm to succeed
@@ -513,23 +675,53 @@ func (s *Service) SetCharm(ch *Charm, force bool) error {
 				Assert: append(notDeadDoc, sameCharm...),
 				Update: bson.D{{"$set", bson.D{{"forcecharm", force}}}},
 			}}
+			changed = false
 		} else {
 			// Change the charm URL.
 			ops, err = s.changeCharmOps(ch, force)
 			if err != nil {
 				return nil, errors.Trace(err)
 			}
+			// Start a rolling upgrade, or abandon any in progress.
+			rollout = nil
+			if batchSize > 0 {
+				if rollout, err = s.newRollout(batchSize, waitHealthy); err != nil {
+					return nil, errors.Trace(err)
+				}
+			}
+			ops = append(ops, setRolloutOp(s.st, s.doc.Name, rollout))
+			changed = true
 		}
 		return ops, nil
 	}
 	err := s.st.run(buildTxn)
 	if err == nil {
+		if changed {
+			s.doc.PreviousCharmURL = s.doc.CharmURL
+			s.doc.Rollout = rollout
+		}
 		s.doc.CharmURL = ch.URL()
 		s.doc.ForceCharm = force
 	}
 	return err
 }
 
+// RollbackCharm changes the charm for the service back to the one it
+// was using before its most recent charm change, and restores the
+// settings it had then. Units are upgraded to the previous charm just
+// as they would be by SetCharm; rolling back twice returns the service
+// to the charm it started with.
+func (s *Service) RollbackCharm(force bool) error {
+	if s.doc.PreviousCharmURL == nil {
+		return errors.Errorf("cannot roll back charm for service %q: no previous charm", s)
+	}
+	ch, err := s.st.Charm(s.doc.PreviousCharmURL)
+	if err != nil {
+		return errors.Annotatef(err, "cannot roll back charm for service %q", s)
+	}
+	return s.SetCharm(ch, force)
+}
+
 // String returns the service name.
 func (s *Service) String() string {
 	return s.doc.Name
@@ -614,6 +806,10 @@ func (s *Service) addUnitOps(principalName string, asserts bson.D) (string, []tx
 			Insert: udoc,
 		},
 		createStatusOp(s.st, globalKey, sdoc),
+		createStatusOp(s.st, unitWorkloadGlobalKey(name), statusDoc{
+			Status:  StatusUnknown,
+			EnvUUID: s.st.EnvironUUID(),
+		}),
 		createMeterStatusOp(s.st, globalKey, &meterStatusDoc{Code: MeterNotSet}),
 		{
 			C:      servicesC,
@@ -727,7 +923,9 @@ func (s *Service) removeUnitOps(u *Unit, asserts bson.D) ([]txn.Op, error) {
 	},
 		removeConstraintsOp(s.st, u.globalKey()),
 		removeStatusOp(s.st, u.globalKey()),
+		removeStatusOp(s.st, u.workloadGlobalKey()),
 		removeMeterStatusOp(s.st, u.globalKey()),
+		removeHookHistorySequenceOp(s.st, u.doc.Name),
 		annotationRemoveOp(s.st, u.globalKey()),
 		s.st.newCleanupOp(cleanupRemovedUnit, u.doc.Name),
 	)
@@ -1003,8 +1201,10 @@ func settingsDecRefOps(st *State, serviceName string, curl *charm.URL) ([]txn.Op
 // settingsRefsDoc holds the number of units and services using the
 // settings document identified by the document's id. Every time a
 // service upgrades its charm the settings doc ref count for the new
-// charm url is incremented, and the old settings is ref count is
-// decremented. When a unit upgrades to the new charm, the old service
+// charm url is incremented, and the ref count of the settings for the
+// charm it used before the old one is decremented; the service keeps
+// its reference to the old charm's settings so that the upgrade can be
+// rolled back. When a unit upgrades to the new charm, the old service
 // settings ref count is decremented and the ref count of the new
 // charm settings is incremented. The last unit upgrading to the new
 // charm is responsible for deleting the old charm's settings doc.
@@ -6,6 +6,7 @@ package state_test
 import (
 	"fmt"
 	"sort"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
@@ -314,6 +315,96 @@ func (s *ServiceSuite) TestSetCharmConfig(c *gc.C) {
 	}
 }
 
+func (s *ServiceSuite) TestRollbackCharm(c *gc.C) {
+	c.Assert(s.mysql.PreviousCharmURL(), gc.IsNil)
+	err := s.mysql.RollbackCharm(false)
+	c.Assert(err, gc.ErrorMatches, `cannot roll back charm for service "mysql": no previous charm`)
+
+	sch := s.AddMetaCharm(c, "mysql", metaBase, 2)
+	err = s.mysql.SetCharm(sch, false)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mysql.PreviousCharmURL(), gc.DeepEquals, s.charm.URL())
+
+	// Setting the same charm again leaves the previous charm alone.
+	err = s.mysql.SetCharm(sch, true)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mysql.PreviousCharmURL(), gc.DeepEquals, s.charm.URL())
+
+	err = s.mysql.RollbackCharm(true)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysql.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	url, force := s.mysql.CharmURL()
+	c.Assert(url, gc.DeepEquals, s.charm.URL())
+	c.Assert(force, jc.IsTrue)
+	c.Assert(s.mysql.PreviousCharmURL(), gc.DeepEquals, sch.URL())
+
+	// Rolling back again returns to the newer charm.
+	err = s.mysql.RollbackCharm(false)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysql.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	url, _ = s.mysql.CharmURL()
+	c.Assert(url, gc.DeepEquals, sch.URL())
+	c.Assert(s.mysql.PreviousCharmURL(), gc.DeepEquals, s.charm.URL())
+}
+
+func (s *ServiceSuite) TestRollbackCharmConfig(c *gc.C) {
+	oldCh := s.AddConfigCharm(c, "wordpress", stringConfig, 1)
+	newCh := s.AddConfigCharm(c, "wordpress", newStringConfig, 2)
+	svc := s.AddTestingService(c, "wordpress", oldCh)
+	err := svc.UpdateConfigSettings(charm.Settings{"key": "foo"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = svc.SetCharm(newCh, false)
+	c.Assert(err, jc.ErrorIsNil)
+	err = svc.UpdateConfigSettings(charm.Settings{"key": "changed", "other": "bar"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The settings the service had before the upgrade are restored,
+	// and the previous charm's config schema applies once more.
+	err = svc.RollbackCharm(false)
+	c.Assert(err, jc.ErrorIsNil)
+	settings, err := svc.ConfigSettings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, gc.DeepEquals, charm.Settings{"key": "foo"})
+	err = svc.UpdateConfigSettings(charm.Settings{"other": "baz"})
+	c.Assert(err, gc.ErrorMatches, `unknown option "other"`)
+
+	// Rolling back again restores the upgraded charm's settings.
+	err = svc.RollbackCharm(false)
+	c.Assert(err, jc.ErrorIsNil)
+	settings, err = svc.ConfigSettings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, gc.DeepEquals, charm.Settings{"key": "changed", "other": "bar"})
+}
+
+func (s *ServiceSuite) TestSetCharmReleasesPreviousSettings(c *gc.C) {
+	ch1 := s.AddConfigCharm(c, "wordpress", stringConfig, 1)
+	ch2 := s.AddConfigCharm(c, "wordpress", stringConfig, 2)
+	ch3 := s.AddConfigCharm(c, "wordpress", stringConfig, 3)
+	svc := s.AddTestingService(c, "wordpress", ch1)
+
+	// The settings for the previous charm are kept until the
+	// service's charm next changes.
+	err := svc.SetCharm(ch2, false)
+	c.Assert(err, jc.ErrorIsNil)
+	assertSettingsRef(c, s.State, "wordpress", ch1, 1)
+	assertSettingsRef(c, s.State, "wordpress", ch2, 1)
+
+	err = svc.SetCharm(ch3, false)
+	c.Assert(err, jc.ErrorIsNil)
+	assertNoSettingsRef(c, s.State, "wordpress", ch1)
+	assertSettingsRef(c, s.State, "wordpress", ch2, 1)
+	assertSettingsRef(c, s.State, "wordpress", ch3, 1)
+
+	// Both are released when the service is removed.
+	err = svc.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	assertNoSettingsRef(c, s.State, "wordpress", ch2)
+	assertNoSettingsRef(c, s.State, "wordpress", ch3)
+}
+
 func (s *ServiceSuite) TestSetCharmWithDyingService(c *gc.C) {
 	sch := s.AddMetaCharm(c, "mysql", metaBase, 2)
 
@@ -460,7 +551,9 @@ func (s *ServiceSuite) TestSetCharmRetriesWhenBothOldAndNewSettingsChanged(c *gc
 				// Add two units, which will keep the refcount of oldCh
 				// and newCh settings greater than 0, while the service's
 				// charm URLs change between oldCh and newCh. Ensure
-				// refcounts change as expected.
+				// refcounts change as expected; the service holds
+				// references to the settings of both its current and
+				// its previous charm.
 				unit1, err := s.mysql.AddUnit()
 				c.Assert(err, jc.ErrorIsNil)
 				unit2, err := s.mysql.AddUnit()
@@ -479,11 +572,11 @@ func (s *ServiceSuite) TestSetCharmRetriesWhenBothOldAndNewSettingsChanged(c *gc
 				c.Assert(err, jc.ErrorIsNil)
 				err = s.mysql.SetCharm(oldCh, false)
 				c.Assert(err, jc.ErrorIsNil)
-				assertSettingsRef(c, s.State, "mysql", newCh, 1)
+				assertSettingsRef(c, s.State, "mysql", newCh, 2)
 				assertSettingsRef(c, s.State, "mysql", oldCh, 1)
 				err = unit2.SetCharmURL(oldCh.URL())
 				c.Assert(err, jc.ErrorIsNil)
-				assertSettingsRef(c, s.State, "mysql", newCh, 1)
+				assertSettingsRef(c, s.State, "mysql", newCh, 2)
 				assertSettingsRef(c, s.State, "mysql", oldCh, 2)
 				err = s.mysql.UpdateConfigSettings(charm.Settings{"key": "value2"})
 				c.Assert(err, jc.ErrorIsNil)
@@ -496,7 +589,7 @@ func (s *ServiceSuite) TestSetCharmRetriesWhenBothOldAndNewSettingsChanged(c *gc
 				c.Assert(err, jc.ErrorIsNil)
 				c.Assert(force, jc.IsFalse)
 				c.Assert(currentCh.URL(), jc.DeepEquals, oldCh.URL())
-				assertSettingsRef(c, s.State, "mysql", newCh, 1)
+				assertSettingsRef(c, s.State, "mysql", newCh, 2)
 				assertSettingsRef(c, s.State, "mysql", oldCh, 2)
 			},
 		},
@@ -508,12 +601,12 @@ func (s *ServiceSuite) TestSetCharmRetriesWhenBothOldAndNewSettingsChanged(c *gc
 				err := s.mysql.SetCharm(newCh, false)
 				c.Assert(err, jc.ErrorIsNil)
 				assertSettingsRef(c, s.State, "mysql", newCh, 2)
-				assertSettingsRef(c, s.State, "mysql", oldCh, 1)
+				assertSettingsRef(c, s.State, "mysql", oldCh, 2)
 				err = s.mysql.UpdateConfigSettings(charm.Settings{"key": "value3"})
 				c.Assert(err, jc.ErrorIsNil)
 				err = s.mysql.SetCharm(oldCh, false)
 				c.Assert(err, jc.ErrorIsNil)
-				assertSettingsRef(c, s.State, "mysql", newCh, 1)
+				assertSettingsRef(c, s.State, "mysql", newCh, 2)
 				assertSettingsRef(c, s.State, "mysql", oldCh, 2)
 				err = s.mysql.UpdateConfigSettings(charm.Settings{"key": "value4"})
 				c.Assert(err, jc.ErrorIsNil)
@@ -526,7 +619,7 @@ func (s *ServiceSuite) TestSetCharmRetriesWhenBothOldAndNewSettingsChanged(c *gc
 				c.Assert(err, jc.ErrorIsNil)
 				c.Assert(force, jc.IsFalse)
 				c.Assert(currentCh.URL(), jc.DeepEquals, oldCh.URL())
-				assertSettingsRef(c, s.State, "mysql", newCh, 1)
+				assertSettingsRef(c, s.State, "mysql", newCh, 2)
 				assertSettingsRef(c, s.State, "mysql", oldCh, 2)
 			},
 		},
@@ -541,7 +634,7 @@ func (s *ServiceSuite) TestSetCharmRetriesWhenBothOldAndNewSettingsChanged(c *gc
 				c.Assert(force, jc.IsTrue)
 				c.Assert(currentCh.URL(), jc.DeepEquals, newCh.URL())
 				assertSettingsRef(c, s.State, "mysql", newCh, 2)
-				assertSettingsRef(c, s.State, "mysql", oldCh, 1)
+				assertSettingsRef(c, s.State, "mysql", oldCh, 2)
 			},
 		},
 	).Check()
@@ -661,20 +754,20 @@ func (s *ServiceSuite) TestSettingsRefCountWorks(c *gc.C) {
 	assertSettingsRef(c, s.State, svcName, oldCh, 1)
 	assertNoSettingsRef(c, s.State, svcName, newCh)
 
-	// Changing from oldCh to newCh causes the refcount of oldCh's
-	// settings to be decremented, while newCh's settings is
-	// incremented. Consequently, because oldCh's refcount is 0, the
-	// settings doc will be removed.
+	// Changing from oldCh to newCh increments the refcount of newCh's
+	// settings. The service keeps its reference to oldCh's settings,
+	// so that the change can be rolled back.
 	err = svc.SetCharm(newCh, false)
 	c.Assert(err, jc.ErrorIsNil)
-	assertNoSettingsRef(c, s.State, svcName, oldCh)
+	assertSettingsRef(c, s.State, svcName, oldCh, 1)
 	assertSettingsRef(c, s.State, svcName, newCh, 1)
 
-	// The same but newCh swapped with oldCh.
+	// Changing back to oldCh takes over the service's reference to
+	// its settings, and keeps the one to newCh's settings instead.
 	err = svc.SetCharm(oldCh, false)
 	c.Assert(err, jc.ErrorIsNil)
 	assertSettingsRef(c, s.State, svcName, oldCh, 1)
-	assertNoSettingsRef(c, s.State, svcName, newCh)
+	assertSettingsRef(c, s.State, svcName, newCh, 1)
 
 	// Adding a unit without a charm URL set does not affect the
 	// refcount.
@@ -683,7 +776,7 @@ func (s *ServiceSuite) TestSettingsRefCountWorks(c *gc.C) {
 	curl, ok := u.CharmURL()
 	c.Assert(ok, jc.IsFalse)
 	assertSettingsRef(c, s.State, svcName, oldCh, 1)
-	assertNoSettingsRef(c, s.State, svcName, newCh)
+	assertSettingsRef(c, s.State, svcName, newCh, 1)
 
 	// Setting oldCh as the units charm URL increments oldCh, which is
 	// used by svc as well, hence 2.
@@ -693,19 +786,19 @@ func (s *ServiceSuite) TestSettingsRefCountWorks(c *gc.C) {
 	c.Assert(ok, jc.IsTrue)
 	c.Assert(curl, gc.DeepEquals, oldCh.URL())
 	assertSettingsRef(c, s.State, svcName, oldCh, 2)
-	assertNoSettingsRef(c, s.State, svcName, newCh)
+	assertSettingsRef(c, s.State, svcName, newCh, 1)
 
 	// A dead unit does not decrement the refcount.
 	err = u.EnsureDead()
 	c.Assert(err, jc.ErrorIsNil)
 	assertSettingsRef(c, s.State, svcName, oldCh, 2)
-	assertNoSettingsRef(c, s.State, svcName, newCh)
+	assertSettingsRef(c, s.State, svcName, newCh, 1)
 
 	// Once the unit is removed, refcount is decremented.
 	err = u.Remove()
 	c.Assert(err, jc.ErrorIsNil)
 	assertSettingsRef(c, s.State, svcName, oldCh, 1)
-	assertNoSettingsRef(c, s.State, svcName, newCh)
+	assertSettingsRef(c, s.State, svcName, newCh, 1)
 
 	// Finally, after the service is destroyed and removed (since the
 	// last unit's gone), the refcount is again decremented.
@@ -970,6 +1063,65 @@ func (s *ServiceSuite) TestServiceRefresh(c *gc.C) {
 	c.Assert(err, jc.Satisfies, errors.IsNotFound)
 }
 
+func (s *ServiceSuite) TestGetSetStatus(c *gc.C) {
+	status, info, data, err := s.mysql.Status()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status, gc.Equals, state.StatusUnknown)
+	c.Assert(info, gc.Equals, "")
+	c.Assert(data, gc.HasLen, 0)
+
+	err = s.mysql.SetStatus(state.StatusUnknown, "", nil)
+	c.Assert(err, gc.ErrorMatches, `cannot set status of service "mysql": cannot set status "unknown"`)
+	err = s.mysql.SetStatus(state.Status("vliegkat"), "", nil)
+	c.Assert(err, gc.ErrorMatches, `cannot set status of service "mysql": cannot set invalid status "vliegkat"`)
+
+	err = s.mysql.SetStatus(state.StatusWaiting, "waiting for peers", map[string]interface{}{
+		"peers": 2,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	status, info, data, err = s.mysql.Status()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status, gc.Equals, state.StatusWaiting)
+	c.Assert(info, gc.Equals, "waiting for peers")
+	c.Assert(data, gc.DeepEquals, map[string]interface{}{
+		"peers": 2,
+	})
+}
+
+func (s *ServiceSuite) TestSetStatusWhenNotAlive(c *gc.C) {
+	_, err := s.mysql.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysql.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysql.SetStatus(state.StatusRunning, "", nil)
+	c.Assert(err, gc.ErrorMatches, `cannot set status of service "mysql": not found or not alive`)
+}
+
+func (s *ServiceSuite) TestHookTimeout(c *gc.C) {
+	c.Assert(s.mysql.HookTimeout(), gc.Equals, time.Duration(0))
+
+	err := s.mysql.SetHookTimeout(10 * time.Minute)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mysql.HookTimeout(), gc.Equals, 10*time.Minute)
+	err = s.mysql.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mysql.HookTimeout(), gc.Equals, 10*time.Minute)
+
+	err = s.mysql.SetHookTimeout(0)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysql.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mysql.HookTimeout(), gc.Equals, time.Duration(0))
+
+	err = s.mysql.SetHookTimeout(-time.Second)
+	c.Assert(err, gc.ErrorMatches, `cannot set hook timeout for service "mysql" to -1s: timeout must not be negative`)
+
+	err = s.mysql.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysql.SetHookTimeout(time.Minute)
+	c.Assert(err, gc.ErrorMatches, notAliveErr)
+}
+
 func (s *ServiceSuite) TestServiceExposed(c *gc.C) {
 	// Check that querying for the exposed flag works correctly.
 	c.Assert(s.mysql.IsExposed(), jc.IsFalse)
@@ -69,6 +69,7 @@ const (
 
 	usersC              = "users"
 	envUsersC           = "envusers"
+	envGroupsC          = "envgroups"
 	presenceC           = "presence"
 	cleanupsC           = "cleanups"
 	annotationsC        = "annotations"
@@ -91,6 +92,9 @@ const (
 	// meterStatusC is the collection used to store meter status information.
 	meterStatusC = "meterStatus"
 
+	// hookHistoryC is the collection used to record the hooks run by units.
+	hookHistoryC = "hookhistory"
+
 	// toolsmetadataC is the collection used to store tools metadata.
 	toolsmetadataC = "toolsmetadata"
 
@@ -124,6 +128,14 @@ type State struct {
 	mu         sync.Mutex
 	allManager *storeManager
 	environTag names.EnvironTag
+
+	// parent is set when the State was returned by WithTraceId,
+	// and holds the State that owns the shared watchers.
+	parent *State
+
+	// traceId returns the trace id logged with each transaction,
+	// and is nil when transactions are not traced.
+	traceId func() string
 }
 
 // StateServingInfo holds information needed by a state server.
@@ -151,6 +163,30 @@ func (st *State) ForEnviron(env names.EnvironTag) (*State, error) {
 	return newState, nil
 }
 
+// WithTraceId returns a State for the same environment as st, sharing
+// its database connection and watchers, that logs each transaction it
+// runs along with the trace id returned by traceId. The trace id
+// identifies the client operation that caused the transaction.
+// Closing the returned State closes st.
+func (st *State) WithTraceId(traceId func() string) *State {
+	parent := st
+	if st.parent != nil {
+		parent = st.parent
+	}
+	return &State{
+		LeasePersistor:    st.LeasePersistor,
+		transactionRunner: st.transactionRunner,
+		mongoInfo:         st.mongoInfo,
+		policy:            st.policy,
+		db:                st.db,
+		watcher:           st.watcher,
+		pwatcher:          st.pwatcher,
+		environTag:        st.environTag,
+		parent:            parent,
+		traceId:           traceId,
+	}
+}
+
 // EnvironTag() returns the environment tag for the environment controlled by
 // this state instance.
 func (st *State) EnvironTag() names.EnvironTag {
@@ -194,6 +230,9 @@ func (st *State) MongoSession() *mgo.Session {
 type closeFunc func()
 
 func (st *State) Watch() *Multiwatcher {
+	if st.parent != nil {
+		return st.parent.Watch()
+	}
 	st.mu.Lock()
 	if st.allManager == nil {
 		st.allManager = newStoreManager(newAllWatcherStateBacking(st))
@@ -1166,6 +1205,10 @@ func (st *State) AddService(
 		createStorageConstraintsOp(svc.globalKey(), storage),
 		createSettingsOp(st, svc.settingsKey(), nil),
 		addLeadershipSettingsOp(svc.Tag().Id()),
+		createStatusOp(st, svc.globalKey(), statusDoc{
+			Status:  StatusUnknown,
+			EnvUUID: env.UUID(),
+		}),
 		{
 			C:      settingsrefsC,
 			Id:     st.docID(svc.settingsKey()),
@@ -195,6 +195,48 @@ func (s *StateSuite) TestPing(c *gc.C) {
 	c.Assert(s.State.Ping(), gc.NotNil)
 }
 
+func (s *StateSuite) TestWithTraceId(c *gc.C) {
+	logger := loggo.GetLogger("juju.state")
+	defer logger.SetLogLevel(logger.LogLevel())
+	logger.SetLogLevel(loggo.DEBUG)
+	var tw loggo.TestWriter
+	c.Assert(loggo.RegisterWriter("trace-tester", &tw, loggo.DEBUG), gc.IsNil)
+	defer loggo.RemoveWriter("trace-tester")
+
+	traceId := "deadbeef"
+	st := s.State.WithTraceId(func() string { return traceId })
+	c.Assert(st.EnvironUUID(), gc.Equals, s.State.EnvironUUID())
+	_, err := st.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	traceId = "cafebabe"
+	err = st.UpdateEnvironConfig(map[string]interface{}{"default-series": "trusty"}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	var traces []string
+	for _, entry := range tw.Log() {
+		if strings.HasPrefix(entry.Message, "trace ") {
+			traces = append(traces, entry.Message)
+		}
+	}
+	c.Assert(traces, gc.HasLen, 2)
+	c.Check(traces[0], gc.Matches, `trace deadbeef: running transaction on .*machines\[.*`)
+	c.Check(traces[1], gc.Matches, `trace cafebabe: running transaction on settings\[.*`)
+
+	m, err := s.State.Machine("0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(m.Series(), gc.Equals, "quantal")
+}
+
+func (s *StateSuite) TestWithTraceIdClose(c *gc.C) {
+	other, err := s.State.ForEnviron(s.State.EnvironTag())
+	c.Assert(err, jc.ErrorIsNil)
+	st := other.WithTraceId(func() string { return "deadbeef" })
+
+	// Closing the traced state closes the one it was made from.
+	c.Assert(st.Close(), jc.ErrorIsNil)
+	c.Assert(func() { other.Ping() }, gc.PanicMatches, "Session already closed")
+}
+
 func (s *StateSuite) TestIsNotFound(c *gc.C) {
 	err1 := fmt.Errorf("unrelated error")
 	err2 := errors.NotFoundf("foo")
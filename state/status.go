@@ -92,6 +92,9 @@ const (
 	// The unit believes it is correctly offering all the services it has
 	// been asked to offer.
 	StatusRunning Status = "running"
+
+	// The charm has not yet reported the state of its software.
+	StatusUnknown Status = "unknown"
 )
 
 // ValidAgentStatus returns true if status has a known value for an agent.
@@ -258,12 +261,94 @@ func (doc *unitAgentStatusDoc) validateSet() error {
 			return errors.Errorf("cannot set status %q without info", doc.Status)
 		}
 	}
-	if doc.StatusData != nil && doc.Status != StatusError {
+	if doc.StatusData != nil && doc.Status != StatusError && !isRunningHookData(doc.StatusData) {
 		return errors.Errorf("cannot set status data when status is %q", doc.Status)
 	}
 	return nil
 }
 
+// isRunningHookData returns true if data holds nothing but the hook name
+// and start time that unit agents record while a hook is running.
+func isRunningHookData(data map[string]interface{}) bool {
+	if len(data) != 2 {
+		return false
+	}
+	_, hasHook := data["hook"]
+	_, hasStarted := data["hook-started"]
+	return hasHook && hasStarted
+}
+
+type workloadStatusDoc struct {
+	statusDoc
+}
+
+// newWorkloadStatusDoc creates a new workloadStatusDoc with the given status and other data.
+func newWorkloadStatusDoc(status Status, info string, data map[string]interface{}) (*workloadStatusDoc, error) {
+	doc := &workloadStatusDoc{statusDoc{
+		Status:     status,
+		StatusInfo: info,
+		StatusData: data,
+	}}
+	if err := doc.validateSet(); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// workloadStatusValid returns true if status has a known value for
+// the software run by a service or unit.
+func workloadStatusValid(status Status) bool {
+	switch status {
+	case
+		StatusBusy,
+		StatusWaiting,
+		StatusBlocked,
+		StatusRunning,
+		StatusUnknown:
+		return true
+	default:
+		return false
+	}
+}
+
+// validateSet returns an error if the workloadStatusDoc does not represent
+// a sane SetWorkloadStatus operation.
+func (doc *workloadStatusDoc) validateSet() error {
+	if !workloadStatusValid(doc.Status) {
+		return errors.Errorf("cannot set invalid status %q", doc.Status)
+	}
+	if doc.Status == StatusUnknown {
+		return errors.Errorf("cannot set status %q", doc.Status)
+	}
+	return nil
+}
+
+// getWorkloadStatus returns the workload status document associated
+// with the given globalKey. Services and units created before workload
+// status was recorded have no such document, and are reported as
+// having an unknown status.
+func getWorkloadStatus(st *State, globalKey string) (statusDoc, error) {
+	doc, err := getStatus(st, globalKey)
+	if errors.IsNotFound(err) {
+		return statusDoc{Status: StatusUnknown}, nil
+	}
+	return doc, err
+}
+
+// setWorkloadStatusOp returns the operation needed to set the given
+// workload status document associated with the given globalKey,
+// creating the document if it does not exist yet.
+func setWorkloadStatusOp(st *State, globalKey string, doc statusDoc) (txn.Op, error) {
+	_, err := getStatus(st, globalKey)
+	if errors.IsNotFound(err) {
+		doc.EnvUUID = st.EnvironUUID()
+		return createStatusOp(st, globalKey, doc), nil
+	} else if err != nil {
+		return txn.Op{}, err
+	}
+	return updateStatusOp(st, globalKey, doc), nil
+}
+
 // getStatus retrieves the status document associated with the given
 // globalKey and copies it to outStatusDoc, which needs to be created
 // by the caller before.
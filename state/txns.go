@@ -6,7 +6,9 @@ package state
 import (
 	"fmt"
 	"reflect"
+	"strings"
 
+	"github.com/juju/loggo"
 	jujutxn "github.com/juju/txn"
 	"github.com/juju/utils/set"
 	"gopkg.in/mgo.v2"
@@ -51,6 +53,7 @@ func (st *State) txnRunnerNoEnvAliveAssert(session *mgo.Session) jujutxn.Runner
 func (st *State) runTransactionNoEnvAliveAssert(ops []txn.Op) error {
 	session := st.db.Session.Copy()
 	defer session.Close()
+	st.traceTransaction(ops)
 	return st.txnRunnerNoEnvAliveAssert(session).RunTransaction(ops)
 }
 
@@ -58,6 +61,7 @@ func (st *State) runTransactionNoEnvAliveAssert(ops []txn.Op) error {
 func (st *State) runTransaction(ops []txn.Op) error {
 	session := st.db.Session.Copy()
 	defer session.Close()
+	st.traceTransaction(ops)
 	return st.txnRunner(session).RunTransaction(ops)
 }
 
@@ -65,9 +69,38 @@ func (st *State) runTransaction(ops []txn.Op) error {
 func (st *State) run(transactions jujutxn.TransactionSource) error {
 	session := st.db.Session.Copy()
 	defer session.Close()
+	if st.traceId != nil {
+		source := transactions
+		transactions = func(attempt int) ([]txn.Op, error) {
+			ops, err := source(attempt)
+			if err == nil {
+				st.traceTransaction(ops)
+			}
+			return ops, err
+		}
+	}
 	return st.txnRunner(session).Run(transactions)
 }
 
+// traceTransaction logs the documents changed by a transaction
+// along with the trace id of the client operation that caused it,
+// so that the operation can be followed from the API server into
+// state.
+func (st *State) traceTransaction(ops []txn.Op) {
+	if st.traceId == nil || logger.EffectiveLogLevel() > loggo.DEBUG {
+		return
+	}
+	traceId := st.traceId()
+	if traceId == "" {
+		return
+	}
+	docs := make([]string, len(ops))
+	for i, op := range ops {
+		docs[i] = fmt.Sprintf("%s[%v]", op.C, op.Id)
+	}
+	logger.Debugf("trace %s: running transaction on %s", traceId, strings.Join(docs, ", "))
+}
+
 // ResumeTransactions resumes all pending transactions.
 func (st *State) ResumeTransactions() error {
 	session := st.db.Session.Copy()
@@ -18,6 +18,7 @@ import (
 	"gopkg.in/mgo.v2/bson"
 	"gopkg.in/mgo.v2/txn"
 
+	"github.com/juju/juju/actions"
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/network"
@@ -160,6 +161,18 @@ func (u *Unit) globalKey() string {
 	return unitGlobalKey(u.doc.Name)
 }
 
+// unitWorkloadGlobalKey returns the global database key for the
+// workload status of the named unit.
+func unitWorkloadGlobalKey(name string) string {
+	return unitGlobalKey(name) + "#charm"
+}
+
+// workloadGlobalKey returns the global database key for the unit's
+// workload status.
+func (u *Unit) workloadGlobalKey() string {
+	return unitWorkloadGlobalKey(u.doc.Name)
+}
+
 // Life returns whether the unit is Alive, Dying or Dead.
 func (u *Unit) Life() Life {
 	return u.doc.Life
@@ -783,6 +796,49 @@ func (u *Unit) SetStatus(status Status, info string, data map[string]interface{}
 	return nil
 }
 
+// WorkloadStatus returns the status of the software run by the unit,
+// as last reported by its charm.
+func (u *Unit) WorkloadStatus() (status Status, info string, data map[string]interface{}, err error) {
+	doc, err := getWorkloadStatus(u.st, u.workloadGlobalKey())
+	if err != nil {
+		return "", "", nil, err
+	}
+	return doc.Status, doc.StatusInfo, doc.StatusData, nil
+}
+
+// SetWorkloadStatus sets the status of the software run by the unit.
+// The optional values allow to pass additional helpful status data.
+func (u *Unit) SetWorkloadStatus(status Status, info string, data map[string]interface{}) error {
+	doc, err := newWorkloadStatusDoc(status, info, data)
+	if err != nil {
+		return errors.Annotatef(err, "cannot set workload status of unit %q", u)
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := u.Refresh(); errors.IsNotFound(err) {
+				return nil, ErrDead
+			} else if err != nil {
+				return nil, err
+			} else if u.Life() == Dead {
+				return nil, ErrDead
+			}
+		}
+		statusOp, err := setWorkloadStatusOp(u.st, u.workloadGlobalKey(), doc.statusDoc)
+		if err != nil {
+			return nil, err
+		}
+		return []txn.Op{{
+			C:      unitsC,
+			Id:     u.doc.DocID,
+			Assert: notDeadDoc,
+		}, statusOp}, nil
+	}
+	if err := u.st.run(buildTxn); err != nil {
+		return errors.Annotatef(err, "cannot set workload status of unit %q", u)
+	}
+	return nil
+}
+
 // OpenPorts opens the given port range and protocol for the unit, if
 // it does not conflict with another already opened range on the
 // unit's assigned machine.
@@ -1047,6 +1103,7 @@ var (
 	unitNotAliveErr    = stderrors.New("unit is not alive")
 	alreadyAssignedErr = stderrors.New("unit is already assigned to a machine")
 	inUseErr           = stderrors.New("machine is not unused")
+	maintenanceErr     = stderrors.New("machine is in maintenance")
 )
 
 // assignToMachine is the internal version of AssignToMachine,
@@ -1056,6 +1113,7 @@ var (
 // - unitNotAliveErr when the unit is not alive.
 // - alreadyAssignedErr when the unit has already been assigned
 // - inUseErr when the machine already has a unit assigned (if unused is true)
+// - maintenanceErr when the machine is in maintenance.
 func (u *Unit) assignToMachine(m *Machine, unused bool) (err error) {
 	if u.doc.Series != m.doc.Series {
 		return fmt.Errorf("series does not match")
@@ -1079,6 +1137,9 @@ func (u *Unit) assignToMachine(m *Machine, unused bool) (err error) {
 	if !canHost {
 		return fmt.Errorf("machine %q cannot host units", m)
 	}
+	if m.doc.Maintenance {
+		return maintenanceErr
+	}
 	// assignToMachine implies assignment to an existing machine,
 	// which is only permitted if unit placement is supported.
 	if err := u.st.supportsUnitPlacement(); err != nil {
@@ -1090,7 +1151,7 @@ func (u *Unit) assignToMachine(m *Machine, unused bool) (err error) {
 			{{"machineid", m.Id()}},
 		}},
 	}...)
-	massert := isAliveDoc
+	massert := append(isAliveDoc, notInMaintenanceDoc...)
 	if unused {
 		massert = append(massert, bson.D{{"clean", bson.D{{"$ne", false}}}}...)
 	}
@@ -1127,6 +1188,8 @@ func (u *Unit) assignToMachine(m *Machine, unused bool) (err error) {
 		return unitNotAliveErr
 	case m0.Life() != Alive:
 		return machineNotAliveErr
+	case m0.InMaintenance():
+		return maintenanceErr
 	case u0.doc.MachineId != "" || !unused:
 		return alreadyAssignedErr
 	}
@@ -1430,6 +1493,7 @@ func (u *Unit) findCleanMachineQuery(requireEmpty bool, cons *constraints.Value)
 		{"series", u.doc.Series},
 		{"jobs", []MachineJob{JobHostUnits}},
 		{"clean", true},
+		{"maintenance", bson.D{{"$ne", true}}},
 		{"machineid", bson.D{{"$nin", machinesWithContainers}}},
 	}
 	// Add the container filter term if necessary.
@@ -1587,7 +1651,7 @@ func (u *Unit) assignToCleanMaybeEmptyMachine(requireEmpty bool) (m *Machine, er
 		if err == nil {
 			return m, nil
 		}
-		if err != inUseErr && err != machineNotAliveErr {
+		if err != inUseErr && err != machineNotAliveErr && err != maintenanceErr {
 			assignContextf(&err, u, context)
 			return nil, err
 		}
@@ -1631,6 +1695,12 @@ func (u *Unit) AddAction(name string, payload map[string]interface{}) (*Action,
 	if len(name) == 0 {
 		return nil, errors.New("no action name given")
 	}
+	if spec, ok := actions.PredefinedActionsSpec[name]; ok {
+		if _, err := spec.ValidateParams(payload); err != nil {
+			return nil, err
+		}
+		return u.st.EnqueueAction(u.Tag(), name, payload)
+	}
 	specs, err := u.ActionSpecs()
 	if err != nil {
 		return nil, err
@@ -657,6 +657,27 @@ func (s *UnitSuite) TestGetSetStatusDataChange(c *gc.C) {
 		"3rd-key": true,
 	})
 
+	// Only running hooks may be described with status data when the
+	// unit agent is not in error.
+	err = s.unit.SetStatus(state.StatusActive, "", map[string]interface{}{
+		"hook": "test-hook",
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot set status data when status is "active"`)
+	err = s.unit.SetStatus(state.StatusActive, "running test-hook hook", map[string]interface{}{
+		"hook":         "test-hook",
+		"hook-started": "2015-06-01T12:00:00Z",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	status, info, data, err = s.unit.Status()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status, gc.Equals, state.StatusActive)
+	c.Assert(info, gc.Equals, "running test-hook hook")
+	c.Assert(data, gc.DeepEquals, map[string]interface{}{
+		"hook":         "test-hook",
+		"hook-started": "2015-06-01T12:00:00Z",
+	})
+
 	// Set status data to nil, so an empty map will be returned.
 	err = s.unit.SetStatus(state.StatusActive, "", nil)
 	c.Assert(err, jc.ErrorIsNil)
@@ -668,6 +689,48 @@ func (s *UnitSuite) TestGetSetStatusDataChange(c *gc.C) {
 	c.Assert(data, gc.HasLen, 0)
 }
 
+func (s *UnitSuite) TestGetSetWorkloadStatus(c *gc.C) {
+	status, info, data, err := s.unit.WorkloadStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status, gc.Equals, state.StatusUnknown)
+	c.Assert(info, gc.Equals, "")
+	c.Assert(data, gc.HasLen, 0)
+
+	err = s.unit.SetWorkloadStatus(state.StatusUnknown, "", nil)
+	c.Assert(err, gc.ErrorMatches, `cannot set workload status of unit "wordpress/0": cannot set status "unknown"`)
+	err = s.unit.SetWorkloadStatus(state.StatusActive, "", nil)
+	c.Assert(err, gc.ErrorMatches, `cannot set workload status of unit "wordpress/0": cannot set invalid status "active"`)
+
+	err = s.unit.SetWorkloadStatus(state.StatusBlocked, "waiting for database", map[string]interface{}{
+		"foo": "bar",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	status, info, data, err = s.unit.WorkloadStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status, gc.Equals, state.StatusBlocked)
+	c.Assert(info, gc.Equals, "waiting for database")
+	c.Assert(data, gc.DeepEquals, map[string]interface{}{
+		"foo": "bar",
+	})
+
+	// The agent status is not affected.
+	status, _, _, err = s.unit.Status()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status, gc.Equals, state.StatusAllocating)
+}
+
+func (s *UnitSuite) TestSetWorkloadStatusWhenDead(c *gc.C) {
+	err := s.unit.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.SetWorkloadStatus(state.StatusRunning, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.unit.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.SetWorkloadStatus(state.StatusRunning, "", nil)
+	c.Assert(err, gc.ErrorMatches, `cannot set workload status of unit "wordpress/0": not found or dead`)
+}
+
 func (s *UnitSuite) TestSetCharmURLSuccess(c *gc.C) {
 	preventUnitDestroyRemove(c, s.unit)
 	curl, ok := s.unit.CharmURL()
@@ -1659,6 +1722,31 @@ func (s *UnitSuite) TestWatchUnit(c *gc.C) {
 	testing.NewNotifyWatcherC(c, s.State, w).AssertOneChange()
 }
 
+func (s *UnitSuite) TestWatchStatus(c *gc.C) {
+	w := s.unit.WatchStatus()
+	defer testing.AssertStop(c, w)
+
+	// Initial event.
+	wc := testing.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	// Make one change (to a separate instance), check one event.
+	unit, err := s.State.Unit(s.unit.Name())
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.SetStatus(state.StatusActive, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	// Changes to the unit document do not trigger events.
+	err = unit.SetPassword("arble-farble-dying-yarble")
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertNoChange()
+
+	// Stop, check closed.
+	testing.AssertStop(c, w)
+	wc.AssertClosed()
+}
+
 func (s *UnitSuite) TestUnitAgentTools(c *gc.C) {
 	preventUnitDestroyRemove(c, s.unit)
 	testAgentTools(c, s.unit, `unit "wordpress/0"`)
@@ -1206,6 +1206,12 @@ func (st *State) WatchStateServerInfo() NotifyWatcher {
 	return newEntityWatcher(st, stateServersC, environGlobalKey)
 }
 
+// WatchConstraints returns a watcher for observing changes to a
+// machine's constraints.
+func (m *Machine) WatchConstraints() NotifyWatcher {
+	return newEntityWatcher(m.st, constraintsC, m.st.docID(m.globalKey()))
+}
+
 // Watch returns a watcher for observing changes to a machine.
 func (m *Machine) Watch() NotifyWatcher {
 	return newEntityWatcher(m.st, machinesC, m.doc.DocID)
@@ -1271,6 +1277,12 @@ func (u *Unit) WatchMeterStatus() NotifyWatcher {
 	return newEntityWatcher(u.st, meterStatusC, u.st.docID(u.globalKey()))
 }
 
+// WatchStatus returns a watcher observing changes to the unit's agent
+// status.
+func (u *Unit) WatchStatus() NotifyWatcher {
+	return newEntityWatcher(u.st, statusesC, u.st.docID(u.globalKey()))
+}
+
 func newEntityWatcher(st *State, collName string, key interface{}) NotifyWatcher {
 	w := &entityWatcher{
 		commonWatcher: commonWatcher{st: st},
@@ -49,6 +49,7 @@ type EnvUserParams struct {
 	User        string
 	DisplayName string
 	CreatedBy   names.Tag
+	Access      state.EnvironmentAccess
 }
 
 // CharmParams defines the parameters for creating a charm.
@@ -150,7 +151,7 @@ func (factory *Factory) MakeUser(c *gc.C, params *UserParams) *state.User {
 		params.Name, params.DisplayName, params.Password, creatorUserTag.Name())
 	c.Assert(err, jc.ErrorIsNil)
 	if !params.NoEnvUser {
-		_, err := factory.st.AddEnvironmentUser(user.UserTag(), names.NewUserTag(user.CreatedBy()))
+		_, err := factory.st.AddEnvironmentUser(user.UserTag(), names.NewUserTag(user.CreatedBy()), state.EnvironAdminAccess)
 		c.Assert(err, jc.ErrorIsNil)
 	}
 	if params.Disabled {
@@ -176,8 +177,11 @@ func (factory *Factory) MakeEnvUser(c *gc.C, params *EnvUserParams) *state.Envir
 		user := factory.MakeUser(c, nil)
 		params.CreatedBy = user.UserTag()
 	}
+	if params.Access == "" {
+		params.Access = state.EnvironAdminAccess
+	}
 	createdByUserTag := params.CreatedBy.(names.UserTag)
-	envUser, err := factory.st.AddEnvironmentUser(names.NewUserTag(params.User), createdByUserTag)
+	envUser, err := factory.st.AddEnvironmentUser(names.NewUserTag(params.User), createdByUserTag, params.Access)
 	c.Assert(err, jc.ErrorIsNil)
 	return envUser
 }
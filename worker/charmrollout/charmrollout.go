@@ -0,0 +1,261 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package charmrollout
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"launchpad.net/tomb"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/watcher"
+	"github.com/juju/juju/worker"
+)
+
+var logger = loggo.GetLogger("juju.worker.charmrollout")
+
+// charmRollout watches the services in state, and advances the rolling
+// charm upgrade of each one as its units upgrade.
+type charmRollout struct {
+	tomb      tomb.Tomb
+	st        *state.State
+	serviceds map[string]*serviceData
+}
+
+// NewCharmRollout returns a worker that advances the rolling charm
+// upgrades of all services whenever their units change.
+func NewCharmRollout(st *state.State) worker.Worker {
+	cr := &charmRollout{
+		st:        st,
+		serviceds: make(map[string]*serviceData),
+	}
+	go func() {
+		defer cr.tomb.Done()
+		cr.tomb.Kill(cr.loop())
+	}()
+	return cr
+}
+
+func (cr *charmRollout) loop() error {
+	w := cr.st.WatchServices()
+	defer watcher.Stop(w, &cr.tomb)
+	defer cr.stopServices()
+	for {
+		select {
+		case <-cr.tomb.Dying():
+			return tomb.ErrDying
+		case names, ok := <-w.Changes():
+			if !ok {
+				return watcher.EnsureErr(w)
+			}
+			for _, name := range names {
+				if err := cr.serviceChanged(name); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+// serviceChanged starts watching the named service, or stops watching
+// it once it is dead or removed.
+func (cr *charmRollout) serviceChanged(name string) error {
+	service, err := cr.st.Service(name)
+	if errors.IsNotFound(err) || err == nil && service.Life() == state.Dead {
+		if sd, ok := cr.serviceds[name]; ok {
+			delete(cr.serviceds, name)
+			return sd.Stop()
+		}
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if _, ok := cr.serviceds[name]; ok {
+		return nil
+	}
+	sd := &serviceData{
+		cr:          cr,
+		service:     service,
+		unitds:      make(map[string]*unitData),
+		unitChanged: make(chan struct{}),
+	}
+	cr.serviceds[name] = sd
+	go sd.watchLoop()
+	return nil
+}
+
+func (cr *charmRollout) stopServices() {
+	for name, sd := range cr.serviceds {
+		watcher.Stop(sd, &cr.tomb)
+		delete(cr.serviceds, name)
+	}
+}
+
+// Kill is part of the worker.Worker interface.
+func (cr *charmRollout) Kill() {
+	cr.tomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (cr *charmRollout) Wait() error {
+	return cr.tomb.Wait()
+}
+
+// serviceData holds service details, and watches the service's units
+// while it has a rolling upgrade in progress.
+type serviceData struct {
+	tomb        tomb.Tomb
+	cr          *charmRollout
+	service     *state.Service
+	unitds      map[string]*unitData
+	unitChanged chan struct{}
+}
+
+// watchLoop advances the service's rolling upgrade whenever the service,
+// or any of its units while the upgrade is in progress, changes.
+func (sd *serviceData) watchLoop() {
+	defer sd.tomb.Done()
+	defer sd.stopUnits()
+	w := sd.service.Watch()
+	defer watcher.Stop(w, &sd.tomb)
+	var unitsw state.StringsWatcher
+	var unitsChanges <-chan []string
+	defer func() {
+		if unitsw != nil {
+			watcher.Stop(unitsw, &sd.tomb)
+		}
+	}()
+	for {
+		select {
+		case <-sd.tomb.Dying():
+			return
+		case _, ok := <-w.Changes():
+			if !ok {
+				sd.cr.tomb.Kill(watcher.EnsureErr(w))
+				return
+			}
+			if err := sd.service.Refresh(); err != nil {
+				if !errors.IsNotFound(err) {
+					sd.cr.tomb.Kill(err)
+				}
+				return
+			}
+			_, rolling := sd.service.Rollout()
+			switch {
+			case rolling && unitsw == nil:
+				logger.Debugf("watching units of service %q for rolling upgrade", sd.service.Name())
+				unitsw = sd.service.WatchUnits()
+				unitsChanges = unitsw.Changes()
+			case !rolling && unitsw != nil:
+				if err := unitsw.Stop(); err != nil {
+					sd.cr.tomb.Kill(err)
+					return
+				}
+				unitsw, unitsChanges = nil, nil
+				sd.stopUnits()
+			}
+		case names, ok := <-unitsChanges:
+			if !ok {
+				sd.cr.tomb.Kill(watcher.EnsureErr(unitsw))
+				return
+			}
+			if err := sd.unitsChanged(names); err != nil {
+				sd.cr.tomb.Kill(err)
+				return
+			}
+		case <-sd.unitChanged:
+		}
+		if err := sd.service.AdvanceRollout(); err != nil && !errors.IsNotFound(errors.Cause(err)) {
+			sd.cr.tomb.Kill(err)
+			return
+		}
+	}
+}
+
+// unitsChanged starts watching the named units of the service, or stops
+// watching them once they are dead or removed.
+func (sd *serviceData) unitsChanged(names []string) error {
+	for _, name := range names {
+		unit, err := sd.cr.st.Unit(name)
+		if errors.IsNotFound(err) || err == nil && unit.Life() == state.Dead {
+			if ud, ok := sd.unitds[name]; ok {
+				delete(sd.unitds, name)
+				if err := ud.Stop(); err != nil {
+					return err
+				}
+			}
+			continue
+		} else if err != nil {
+			return err
+		}
+		if _, ok := sd.unitds[name]; ok {
+			continue
+		}
+		ud := &unitData{
+			serviced: sd,
+			unit:     unit,
+		}
+		sd.unitds[name] = ud
+		go ud.watchLoop()
+	}
+	return nil
+}
+
+func (sd *serviceData) stopUnits() {
+	for name, ud := range sd.unitds {
+		watcher.Stop(ud, &sd.cr.tomb)
+		delete(sd.unitds, name)
+	}
+}
+
+// Stop stops the service watching.
+func (sd *serviceData) Stop() error {
+	sd.tomb.Kill(nil)
+	return sd.tomb.Wait()
+}
+
+// unitData holds unit details, and watches the unit's charm and status.
+type unitData struct {
+	tomb     tomb.Tomb
+	serviced *serviceData
+	unit     *state.Unit
+}
+
+// watchLoop notifies the unit's service whenever the unit document or
+// the unit's agent status changes. The unit document records its charm
+// URL, but its status is held separately.
+func (ud *unitData) watchLoop() {
+	defer ud.tomb.Done()
+	w := ud.unit.Watch()
+	defer watcher.Stop(w, &ud.tomb)
+	statusw := ud.unit.WatchStatus()
+	defer watcher.Stop(statusw, &ud.tomb)
+	for {
+		select {
+		case <-ud.tomb.Dying():
+			return
+		case _, ok := <-w.Changes():
+			if !ok {
+				ud.serviced.cr.tomb.Kill(watcher.EnsureErr(w))
+				return
+			}
+		case _, ok := <-statusw.Changes():
+			if !ok {
+				ud.serviced.cr.tomb.Kill(watcher.EnsureErr(statusw))
+				return
+			}
+		}
+		select {
+		case ud.serviced.unitChanged <- struct{}{}:
+		case <-ud.tomb.Dying():
+			return
+		}
+	}
+}
+
+// Stop stops the unit watching.
+func (ud *unitData) Stop() error {
+	ud.tomb.Kill(nil)
+	return ud.tomb.Wait()
+}
@@ -0,0 +1,124 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package charmrollout_test
+
+import (
+	stdtesting "testing"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/testing/factory"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/charmrollout"
+)
+
+func TestPackage(t *stdtesting.T) {
+	coretesting.MgoTestPackage(t)
+}
+
+type CharmRolloutSuite struct {
+	testing.JujuConnSuite
+	charm    *state.Charm
+	newCharm *state.Charm
+	mysql    *state.Service
+	units    []*state.Unit
+}
+
+var _ = gc.Suite(&CharmRolloutSuite{})
+
+func (s *CharmRolloutSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	s.charm = s.AddTestingCharm(c, "mysql")
+	s.newCharm = s.Factory.MakeCharm(c, &factory.CharmParams{Name: "mysql"})
+	s.mysql = s.AddTestingService(c, "mysql", s.charm)
+	s.units = nil
+	for i := 0; i < 2; i++ {
+		unit, err := s.mysql.AddUnit()
+		c.Assert(err, jc.ErrorIsNil)
+		err = unit.SetCharmURL(s.charm.URL())
+		c.Assert(err, jc.ErrorIsNil)
+		err = unit.SetStatus(state.StatusActive, "", nil)
+		c.Assert(err, jc.ErrorIsNil)
+		s.units = append(s.units, unit)
+	}
+}
+
+// waitUpgrading waits for the mysql rollout to allow the given units to
+// upgrade, or to finish if none are given.
+func (s *CharmRolloutSuite) waitUpgrading(c *gc.C, names ...string) {
+	timeout := time.After(coretesting.LongWait)
+	for {
+		s.State.StartSync()
+		select {
+		case <-time.After(coretesting.ShortWait):
+			err := s.mysql.Refresh()
+			c.Assert(err, jc.ErrorIsNil)
+			rollout, ok := s.mysql.Rollout()
+			if len(names) == 0 && !ok {
+				return
+			}
+			if ok && len(rollout.Upgrading) == len(names) {
+				c.Assert(rollout.Upgrading, jc.DeepEquals, names)
+				return
+			}
+		case <-timeout:
+			c.Fatalf("timed out waiting for rollout to advance")
+		}
+	}
+}
+
+func (s *CharmRolloutSuite) upgradeUnit(c *gc.C, unit *state.Unit) {
+	err := unit.SetCharmURL(s.newCharm.URL())
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *CharmRolloutSuite) TestRunStop(c *gc.C) {
+	w := charmrollout.NewCharmRollout(s.State)
+	c.Assert(worker.Stop(w), jc.ErrorIsNil)
+}
+
+func (s *CharmRolloutSuite) TestAdvancesOnUnitUpgrade(c *gc.C) {
+	w := charmrollout.NewCharmRollout(s.State)
+	defer func() { c.Assert(worker.Stop(w), jc.ErrorIsNil) }()
+
+	err := s.mysql.SetCharmRollout(s.newCharm, false, 1, false)
+	c.Assert(err, jc.ErrorIsNil)
+	s.waitUpgrading(c, "mysql/0")
+
+	s.upgradeUnit(c, s.units[0])
+	s.waitUpgrading(c, "mysql/0", "mysql/1")
+
+	s.upgradeUnit(c, s.units[1])
+	s.waitUpgrading(c)
+}
+
+func (s *CharmRolloutSuite) TestAdvancesOnUnitStatus(c *gc.C) {
+	err := s.mysql.SetCharmRollout(s.newCharm, false, 1, true)
+	c.Assert(err, jc.ErrorIsNil)
+	s.upgradeUnit(c, s.units[0])
+	err = s.units[0].SetStatus(state.StatusActive, "running upgrade-charm hook", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	w := charmrollout.NewCharmRollout(s.State)
+	defer func() { c.Assert(worker.Stop(w), jc.ErrorIsNil) }()
+
+	// The unit has upgraded but is not yet idle, so the next batch
+	// has to wait.
+	s.State.StartSync()
+	time.Sleep(coretesting.ShortWait)
+	err = s.mysql.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	rollout, ok := s.mysql.Rollout()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(rollout.Upgrading, jc.DeepEquals, []string{"mysql/0"})
+
+	err = s.units[0].SetStatus(state.StatusActive, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.waitUpgrading(c, "mysql/0", "mysql/1")
+}
@@ -23,9 +23,10 @@ import (
 	"github.com/juju/juju/version"
 )
 
-// InitDir is the default upstart init directory.
+// InitDir is the directory in which unit agent services are installed.
+// If empty, the default directory of the host's init system is used.
 // This is a var so it can be overridden by tests.
-var InitDir = "/etc/init"
+var InitDir = ""
 
 // APICalls defines the interface to the API that the simple context needs.
 type APICalls interface {
@@ -18,6 +18,7 @@ import (
 
 	"github.com/juju/juju/agent"
 	"github.com/juju/juju/agent/tools"
+	"github.com/juju/juju/service"
 	"github.com/juju/juju/state/multiwatcher"
 	"github.com/juju/juju/testing"
 	coretools "github.com/juju/juju/tools"
@@ -136,6 +137,8 @@ type SimpleToolsFixture struct {
 	initDir  string
 	origPath string
 	binDir   string
+
+	origDiscoverInitSystem func() string
 }
 
 var fakeJujud = "#!/bin/bash --norc\n# fake-jujud\nexit 0\n"
@@ -156,6 +159,8 @@ func (fix *SimpleToolsFixture) SetUp(c *gc.C, dataDir string) {
 	c.Assert(err, jc.ErrorIsNil)
 	err = ioutil.WriteFile(toolsPath, data, 0644)
 	c.Assert(err, jc.ErrorIsNil)
+	fix.origDiscoverInitSystem = service.DiscoverInitSystem
+	service.DiscoverInitSystem = func() string { return service.InitSystemUpstart }
 	fix.binDir = c.MkDir()
 	fix.origPath = os.Getenv("PATH")
 	os.Setenv("PATH", fix.binDir+":"+fix.origPath)
@@ -168,6 +173,7 @@ func (fix *SimpleToolsFixture) SetUp(c *gc.C, dataDir string) {
 
 func (fix *SimpleToolsFixture) TearDown(c *gc.C) {
 	os.Setenv("PATH", fix.origPath)
+	service.DiscoverInitSystem = fix.origDiscoverInitSystem
 }
 
 func (fix *SimpleToolsFixture) makeBin(c *gc.C, name, script string) {
@@ -81,6 +81,14 @@ func (a *aggregator) loop() error {
 				ids[i] = req.instId
 			}
 			insts, err := a.environ.Instances(ids)
+			if err == environs.ErrNoInstances && len(ids) == 1 {
+				// The only instance asked for does not exist.
+				// When several instances are asked for, the
+				// provider reporting none of them may just mean
+				// that its API is not consistent yet, so that is
+				// not taken to mean that they have all gone.
+				insts, err = make([]instance.Instance, 1), nil
+			}
 			for i, req := range reqs {
 				var reply instanceInfoReply
 				if err != nil && err != environs.ErrPartialInstances {
@@ -209,6 +209,42 @@ func (s *aggregateSuite) TestPartialErrResponse(c *gc.C) {
 	c.Assert(err, jc.Satisfies, errors.IsNotFound)
 }
 
+func (s *aggregateSuite) TestNoInstancesErrResponse(c *gc.C) {
+	testGetter := new(testInstanceGetter)
+	testGetter.err = environs.ErrNoInstances
+
+	aggregator := newAggregator(testGetter)
+	_, err := aggregator.instanceInfo("foo")
+
+	c.Assert(err, gc.ErrorMatches, "instance foo not found")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *aggregateSuite) TestNoInstancesErrResponseForSeveral(c *gc.C) {
+	s.PatchValue(&gatherTime, 30*time.Millisecond)
+	testGetter := new(testInstanceGetter)
+	testGetter.err = environs.ErrNoInstances
+
+	aggregator := newAggregator(testGetter)
+	_, err := aggregator.instanceInfo("foo")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	// When none of several instances are found, they are not
+	// reported as not found.
+	var wg sync.WaitGroup
+	checkInfo := func(id instance.Id) {
+		_, err := aggregator.instanceInfo(id)
+		c.Check(err, gc.Equals, environs.ErrNoInstances)
+		wg.Done()
+	}
+	wg.Add(2)
+	go checkInfo("foo2")
+	go checkInfo("foo3")
+	wg.Wait()
+
+	c.Assert(testGetter.ids, gc.HasLen, 2)
+}
+
 func (s *aggregateSuite) TestAddressesError(c *gc.C) {
 	testGetter := new(testInstanceGetter)
 	instance1 := testGetter.newTestInstance("foo", "foobar", []string{"127.0.0.1", "192.168.1.1"})
@@ -58,6 +58,178 @@ func (s *machineSuite) TestSetsInstanceInfoInitially(c *gc.C) {
 	c.Assert(m.instStatus, gc.Equals, "running")
 }
 
+func (s *machineSuite) TestSetsInstanceLostWhenInstanceMissing(c *gc.C) {
+	s.PatchValue(&ShortPoll, 1*time.Millisecond)
+	s.PatchValue(&LongPoll, coretesting.LongWait)
+	s.PatchValue(&LostInstanceTimeout, 10*time.Millisecond)
+	count := int32(0)
+	getInstanceInfo := func(id instance.Id) (instanceInfo, error) {
+		c.Check(id, gc.Equals, instance.Id("i1234"))
+		atomic.AddInt32(&count, 1)
+		return instanceInfo{}, errors.NotFoundf("instance %v", id)
+	}
+	context := &testMachineContext{
+		getInstanceInfo: getInstanceInfo,
+		dyingc:          make(chan struct{}),
+	}
+	m := &testMachine{
+		id:         "99",
+		instanceId: "i1234",
+		instStatus: "running",
+		refresh:    func() error { return nil },
+		life:       state.Alive,
+	}
+	died := make(chan machine)
+
+	go runMachine(context, m, nil, died)
+	time.Sleep(coretesting.ShortWait)
+
+	killMachineLoop(c, m, context.dyingc, died)
+	c.Assert(context.killAllErr, gc.Equals, nil)
+	c.Assert(m.lostInstanceId, gc.Equals, instance.Id("i1234"))
+	// The instance is only lost once it has been missing for a while.
+	c.Assert(count > 1, jc.IsTrue)
+}
+
+func (s *machineSuite) TestInstanceBrieflyMissingNotLost(c *gc.C) {
+	s.PatchValue(&ShortPoll, 1*time.Millisecond)
+	s.PatchValue(&LongPoll, coretesting.LongWait)
+	s.PatchValue(&LostInstanceTimeout, coretesting.LongWait)
+	count := int32(0)
+	getInstanceInfo := func(id instance.Id) (instanceInfo, error) {
+		c.Check(id, gc.Equals, instance.Id("i1234"))
+		if atomic.AddInt32(&count, 1)%2 == 0 {
+			return instanceInfo{status: "running"}, nil
+		}
+		return instanceInfo{}, errors.NotFoundf("instance %v", id)
+	}
+	context := &testMachineContext{
+		getInstanceInfo: getInstanceInfo,
+		dyingc:          make(chan struct{}),
+	}
+	m := &testMachine{
+		id:         "99",
+		instanceId: "i1234",
+		instStatus: "running",
+		refresh:    func() error { return nil },
+		life:       state.Alive,
+	}
+	died := make(chan machine)
+
+	go runMachine(context, m, nil, died)
+	time.Sleep(coretesting.ShortWait)
+
+	killMachineLoop(c, m, context.dyingc, died)
+	c.Assert(context.killAllErr, gc.Equals, nil)
+	c.Assert(m.lostInstanceId, gc.Equals, instance.Id(""))
+	c.Assert(atomic.LoadInt32(&count) > 1, jc.IsTrue)
+}
+
+func (s *machineSuite) TestNeverSeenInstanceNotLost(c *gc.C) {
+	s.PatchValue(&ShortPoll, 1*time.Millisecond)
+	s.PatchValue(&LongPoll, coretesting.LongWait)
+	context := &testMachineContext{
+		getInstanceInfo: instanceInfoGetter(c, "i1234", nil, "", errors.NotFoundf("instance i1234")),
+		dyingc:          make(chan struct{}),
+	}
+	m := &testMachine{
+		id:         "99",
+		instanceId: "i1234",
+		refresh:    func() error { return nil },
+		life:       state.Alive,
+	}
+	died := make(chan machine)
+
+	go runMachine(context, m, nil, died)
+	time.Sleep(coretesting.ShortWait)
+
+	killMachineLoop(c, m, context.dyingc, died)
+	c.Assert(context.killAllErr, gc.Equals, nil)
+	c.Assert(m.lostInstanceId, gc.Equals, instance.Id(""))
+}
+
+func (s *machineSuite) TestUpdatesInstanceTags(c *gc.C) {
+	type tagCall struct {
+		id   instance.Id
+		tags map[string]string
+	}
+	calls := make(chan tagCall)
+	context := &testMachineContext{
+		getInstanceInfo: instanceInfoGetter(c, "i1234", testAddrs, "running", nil),
+		tagInstanceFunc: func(id instance.Id, tags map[string]string) error {
+			calls <- tagCall{id, tags}
+			return nil
+		},
+		dyingc: make(chan struct{}),
+	}
+	m := &testMachine{
+		id:          "99",
+		instanceId:  "i1234",
+		refresh:     func() error { return nil },
+		life:        state.Alive,
+		principals:  []string{"wordpress/0"},
+		principalsc: make(chan []string),
+	}
+	died := make(chan machine)
+	s.PatchValue(&ShortPoll, coretesting.ShortWait/10)
+	s.PatchValue(&LongPoll, coretesting.ShortWait/10)
+
+	go runMachine(context, m, nil, died)
+	nextCall := func() tagCall {
+		select {
+		case call := <-calls:
+			return call
+		case <-time.After(coretesting.LongWait):
+			c.Fatalf("timed out waiting for instance to be tagged")
+		}
+		panic("unreachable")
+	}
+	expectTags := map[string]string{
+		"juju-env-uuid":       "env-uuid",
+		"juju-machine-id":     "99",
+		"juju-units-deployed": "wordpress/0",
+	}
+	c.Assert(nextCall(), jc.DeepEquals, tagCall{"i1234", expectTags})
+
+	// The tags are set again when the machine's units change, and
+	// the tag for units that are no longer deployed is cleared.
+	m.setPrincipals()
+	m.principalsc <- nil
+	expectTags["juju-units-deployed"] = ""
+	c.Assert(nextCall(), jc.DeepEquals, tagCall{"i1234", expectTags})
+
+	killMachineLoop(c, m, context.dyingc, died)
+	c.Assert(context.killAllErr, gc.Equals, nil)
+	tags, err := m.InstanceTags()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(tags, jc.DeepEquals, expectTags)
+}
+
+func (s *machineSuite) TestInstanceTagsNotSupported(c *gc.C) {
+	context := &testMachineContext{
+		getInstanceInfo: instanceInfoGetter(c, "i1234", testAddrs, "running", nil),
+		dyingc:          make(chan struct{}),
+	}
+	m := &testMachine{
+		id:         "99",
+		instanceId: "i1234",
+		refresh:    func() error { return nil },
+		life:       state.Alive,
+	}
+	died := make(chan machine)
+	s.PatchValue(&ShortPoll, coretesting.ShortWait/10)
+	s.PatchValue(&LongPoll, coretesting.ShortWait/10)
+
+	go runMachine(context, m, nil, died)
+	time.Sleep(coretesting.ShortWait)
+
+	killMachineLoop(c, m, context.dyingc, died)
+	c.Assert(context.killAllErr, gc.Equals, nil)
+	tags, err := m.InstanceTags()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(tags, gc.HasLen, 0)
+}
+
 func (s *machineSuite) TestShortPollIntervalWhenNoAddress(c *gc.C) {
 	s.PatchValue(&ShortPoll, 1*time.Millisecond)
 	s.PatchValue(&LongPoll, coretesting.LongWait)
@@ -302,6 +474,7 @@ func instanceInfoGetter(
 type testMachineContext struct {
 	killAllErr      error
 	getInstanceInfo func(instance.Id) (instanceInfo, error)
+	tagInstanceFunc func(instance.Id, map[string]string) error
 	dyingc          chan struct{}
 }
 
@@ -316,6 +489,17 @@ func (context *testMachineContext) instanceInfo(id instance.Id) (instanceInfo, e
 	return context.getInstanceInfo(id)
 }
 
+func (context *testMachineContext) resourceTags() map[string]string {
+	return map[string]string{"juju-env-uuid": "env-uuid"}
+}
+
+func (context *testMachineContext) tagInstance(id instance.Id, tags map[string]string) error {
+	if context.tagInstanceFunc == nil {
+		return errors.NotSupportedf("tagging instances")
+	}
+	return context.tagInstanceFunc(id, tags)
+}
+
 func (context *testMachineContext) dying() <-chan struct{} {
 	return context.dyingc
 }
@@ -333,6 +517,10 @@ type testMachine struct {
 	life            state.Life
 	addresses       []network.Address
 	setAddressCount int
+	lostInstanceId  instance.Id
+	principals      []string
+	instanceTags    map[string]string
+	principalsc     chan []string
 }
 
 func (m *testMachine) Id() string {
@@ -349,6 +537,8 @@ func (m *testMachine) Addresses() []network.Address {
 }
 
 func (m *testMachine) InstanceId() (instance.Id, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	if m.instanceId == "" {
 		return "", errors.NotProvisionedf("machine %v", m.Id())
 	}
@@ -381,6 +571,15 @@ func (m *testMachine) SetInstanceStatus(status string) error {
 	return nil
 }
 
+func (m *testMachine) SetInstanceLost(id instance.Id) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.lostInstanceId = id
+	m.instanceId = ""
+	m.instStatus = ""
+	return nil
+}
+
 func (m *testMachine) SetAddresses(addrs ...network.Address) error {
 	if m.setAddressesErr != nil {
 		return m.setAddressesErr
@@ -392,6 +591,39 @@ func (m *testMachine) SetAddresses(addrs ...network.Address) error {
 	return nil
 }
 
+func (m *testMachine) IsManager() bool {
+	return false
+}
+
+func (m *testMachine) Principals() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.principals
+}
+
+func (m *testMachine) setPrincipals(principals ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.principals = principals
+}
+
+func (m *testMachine) InstanceTags() (map[string]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.instanceTags, nil
+}
+
+func (m *testMachine) SetInstanceTags(tags map[string]string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.instanceTags = tags
+	return nil
+}
+
+func (m *testMachine) WatchPrincipalUnits() state.StringsWatcher {
+	return &testStringsWatcher{changes: m.principalsc}
+}
+
 func (m *testMachine) String() string {
 	return m.id
 }
@@ -411,3 +643,27 @@ func (m *testMachine) setLife(life state.Life) {
 	defer m.mu.Unlock()
 	m.life = life
 }
+
+// testStringsWatcher is a state.StringsWatcher that sends the
+// changes it is given.
+type testStringsWatcher struct {
+	changes chan []string
+}
+
+func (w *testStringsWatcher) Changes() <-chan []string {
+	return w.changes
+}
+
+func (w *testStringsWatcher) Stop() error {
+	return nil
+}
+
+func (w *testStringsWatcher) Kill() {}
+
+func (w *testStringsWatcher) Wait() error {
+	return nil
+}
+
+func (w *testStringsWatcher) Err() error {
+	return nil
+}
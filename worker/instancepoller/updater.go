@@ -5,11 +5,13 @@ package instancepoller
 
 import (
 	"fmt"
+	"reflect"
 	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 
+	"github.com/juju/juju/environs/tags"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
@@ -31,6 +33,16 @@ var (
 	LongPoll         = 15 * time.Minute
 )
 
+// LostInstanceTimeout holds how long a machine's instance must have
+// been missing from the provider before the instance is considered
+// lost (for example, because the cloud terminated it) and the machine
+// is marked for reprovisioning. Providers' APIs may briefly fail to
+// report instances that still exist, so the instance must be missing
+// from every poll made over this period. Instances that have never
+// been seen by the poller are not considered lost, because newly
+// started instances may not yet be visible to the provider's API.
+var LostInstanceTimeout = 10 * time.Minute
+
 type machine interface {
 	Id() string
 	InstanceId() (instance.Id, error)
@@ -38,11 +50,17 @@ type machine interface {
 	SetAddresses(...network.Address) error
 	InstanceStatus() (string, error)
 	SetInstanceStatus(status string) error
+	SetInstanceLost(id instance.Id) error
 	String() string
 	Refresh() error
 	Life() state.Life
 	Status() (status state.Status, info string, data map[string]interface{}, err error)
 	IsManual() (bool, error)
+	IsManager() bool
+	Principals() []string
+	InstanceTags() (map[string]string, error)
+	SetInstanceTags(tags map[string]string) error
+	WatchPrincipalUnits() state.StringsWatcher
 }
 
 type instanceInfo struct {
@@ -53,6 +71,8 @@ type instanceInfo struct {
 type machineContext interface {
 	killAll(err error)
 	instanceInfo(id instance.Id) (instanceInfo, error)
+	resourceTags() map[string]string
+	tagInstance(id instance.Id, tags map[string]string) error
 	dying() <-chan struct{}
 }
 
@@ -171,15 +191,39 @@ func runMachine(context machineContext, m machine, changed <-chan struct{}, died
 	}
 }
 
-func machineLoop(context machineContext, m machine, changed <-chan struct{}) error {
+func machineLoop(context machineContext, m machine, changed <-chan struct{}) (err error) {
+	// The instance's tags include the machine's principal units,
+	// so they are updated whenever units are assigned to it.
+	principals := m.WatchPrincipalUnits()
+	defer func() {
+		if stopErr := principals.Stop(); stopErr != nil && err == nil {
+			err = stopErr
+		}
+	}()
 	// Use a short poll interval when initially waiting for
 	// a machine's address and machine agent to start, and a long one when it already
 	// has an address and the machine agent is started.
 	pollInterval := ShortPoll
 	pollInstance := true
+	var missingSince time.Time
+	canTag := true
 	for {
 		if pollInstance {
 			instInfo, err := pollInstanceInfo(context, m)
+			if errors.IsNotFound(err) {
+				// The instance has gone away; keep polling to
+				// confirm it before replacing it.
+				if missingSince.IsZero() {
+					missingSince = time.Now()
+					pollInterval = ShortPoll
+				} else if time.Since(missingSince) >= LostInstanceTimeout {
+					setInstanceLost(m)
+					missingSince = time.Time{}
+				}
+				err = nil
+			} else {
+				missingSince = time.Time{}
+			}
 			if err != nil && !errors.IsNotProvisioned(err) {
 				// If the provider doesn't implement Addresses/Status now,
 				// it never will until we're upgraded, so don't bother
@@ -209,12 +253,29 @@ func machineLoop(context machineContext, m machine, changed <-chan struct{}) err
 				pollInterval = time.Duration(float64(pollInterval) * ShortPollBackoff)
 			}
 			pollInstance = false
+			if canTag {
+				if canTag, err = updateInstanceTags(context, m); err != nil {
+					return err
+				}
+			}
 		}
 		select {
 		case <-time.After(pollInterval):
 			pollInstance = true
 		case <-context.dying():
 			return nil
+		case _, ok := <-principals.Changes():
+			if !ok {
+				return watcher.EnsureErr(principals)
+			}
+			if err := m.Refresh(); err != nil {
+				return err
+			}
+			if canTag {
+				if canTag, err = updateInstanceTags(context, m); err != nil {
+					return err
+				}
+			}
 		case <-changed:
 			if err := m.Refresh(); err != nil {
 				return err
@@ -226,6 +287,50 @@ func machineLoop(context machineContext, m machine, changed <-chan struct{}) err
 	}
 }
 
+// updateInstanceTags sets the tags that the machine's instance should
+// have on the instance, if they differ from those that the provider
+// has already set, and records them on the machine. It returns false
+// if the provider does not support tagging instances. Failures to tag
+// the instance are logged, and the tags are set again when the
+// instance is next polled.
+func updateInstanceTags(context machineContext, m machine) (bool, error) {
+	instId, err := m.InstanceId()
+	if errors.IsNotProvisioned(err) {
+		return true, nil
+	} else if err != nil {
+		return false, fmt.Errorf("cannot get machine's instance id: %v", err)
+	}
+	current, err := m.InstanceTags()
+	if errors.IsNotProvisioned(err) {
+		return true, nil
+	} else if err != nil {
+		return false, fmt.Errorf("cannot get machine's instance tags: %v", err)
+	}
+	wanted := tags.InstanceTags(context.resourceTags(), m.Id(), m.IsManager(), m.Principals())
+	// Providers cannot remove tags from instances, so tags that are
+	// no longer wanted, such as those for units that have been
+	// removed from the machine, are cleared instead.
+	for key := range current {
+		if _, ok := wanted[key]; !ok {
+			wanted[key] = ""
+		}
+	}
+	if reflect.DeepEqual(current, wanted) {
+		return true, nil
+	}
+	if err := context.tagInstance(instId, wanted); errors.IsNotSupported(err) {
+		return false, nil
+	} else if err != nil {
+		logger.Warningf("cannot tag instance %q of machine %q: %v", instId, m.Id(), err)
+		return true, nil
+	}
+	logger.Infof("machine %q instance tags changed to %v", m.Id(), wanted)
+	if err := m.SetInstanceTags(wanted); err != nil {
+		logger.Errorf("cannot set instance tags on %q: %v", m, err)
+	}
+	return true, nil
+}
+
 // pollInstanceInfo checks the current provider addresses and status
 // for the given machine's instance, and sets them on the machine if they've changed.
 func pollInstanceInfo(context machineContext, m machine) (instInfo instanceInfo, err error) {
@@ -244,6 +349,12 @@ func pollInstanceInfo(context machineContext, m machine) (instInfo instanceInfo,
 			return instInfo, err
 		}
 		logger.Warningf("cannot get instance info for instance %q: %v", instId, err)
+		if errors.IsNotFound(err) {
+			// Only an instance that we have seen before can be lost.
+			if currentInstStatus, _ := m.InstanceStatus(); currentInstStatus != "" {
+				return instanceInfo{}, err
+			}
+		}
 		return instInfo, nil
 	}
 	currentInstStatus, err := m.InstanceStatus()
@@ -269,6 +380,20 @@ func pollInstanceInfo(context machineContext, m machine) (instInfo instanceInfo,
 	return instInfo, err
 }
 
+// setInstanceLost marks the given machine's instance as lost, so
+// that the provisioner will start a new instance for the machine.
+func setInstanceLost(m machine) {
+	instId, err := m.InstanceId()
+	if err != nil {
+		logger.Warningf("cannot get instance id for machine %v: %v", m.Id(), err)
+		return
+	}
+	logger.Warningf("machine %q instance %q no longer exists in the provider", m.Id(), instId)
+	if err := m.SetInstanceLost(instId); err != nil {
+		logger.Errorf("cannot replace instance %q of machine %q: %v", instId, m.Id(), err)
+	}
+}
+
 func addressesEqual(a0, a1 []network.Address) bool {
 	if len(a0) != len(a1) {
 		logger.Tracef("address lists have different lengths %d != %d for %v != %v",
@@ -4,8 +4,12 @@
 package instancepoller
 
 import (
+	"github.com/juju/errors"
 	"launchpad.net/tomb"
 
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/tags"
+	"github.com/juju/juju/instance"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/worker"
 )
@@ -21,6 +25,8 @@ type updaterWorker struct {
 // NewWorker returns a worker that keeps track of
 // the machines in the state and polls their instance
 // addresses and status periodically to keep them up to date.
+// It also keeps the tags of the instances up to date, if the
+// provider supports tagging them.
 func NewWorker(st *state.State) worker.Worker {
 	u := &updaterWorker{
 		st: st,
@@ -72,3 +78,15 @@ func (u *updaterWorker) dying() <-chan struct{} {
 func (u *updaterWorker) killAll(err error) {
 	u.tomb.Kill(err)
 }
+
+func (u *updaterWorker) resourceTags() map[string]string {
+	return tags.ResourceTags(u.st.EnvironUUID(), u.observer.Environ().Config().ResourceTags())
+}
+
+func (u *updaterWorker) tagInstance(id instance.Id, instanceTags map[string]string) error {
+	tagger, ok := u.observer.Environ().(environs.InstanceTagger)
+	if !ok {
+		return errors.NotSupportedf("tagging instances")
+	}
+	return tagger.TagInstance(id, instanceTags)
+}
@@ -0,0 +1,161 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package machineactions defines a worker that runs the actions queued
+// for the machine running it. Only the predefined juju-run action is
+// supported, which "juju run" uses to run commands on machines without
+// connecting to them over SSH.
+package machineactions
+
+import (
+	"bytes"
+	"fmt"
+	osexec "os/exec"
+	"path"
+	"syscall"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/names"
+	"github.com/juju/utils/exec"
+	"github.com/juju/utils/fslock"
+
+	"github.com/juju/juju/actions"
+	"github.com/juju/juju/api/machineactions"
+	"github.com/juju/juju/api/watcher"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/version"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/proxyupdater"
+)
+
+var logger = loggo.GetLogger("juju.worker.machineactions")
+
+// Facade defines the capabilities of the API used by the worker.
+type Facade interface {
+	WatchActionNotifications() (watcher.StringsWatcher, error)
+	Action(tag names.ActionTag) (*machineactions.Action, error)
+	ActionBegin(tag names.ActionTag) error
+	ActionFinish(tag names.ActionTag, status string, results map[string]interface{}, message string) error
+}
+
+// NewWorker returns a worker that runs the actions queued for the
+// machine accessed through the facade. The commands of each action are
+// run while holding the hook execution lock, so that they do not
+// interfere with the hooks of any units on the machine.
+func NewWorker(facade Facade, lock *fslock.Lock) worker.Worker {
+	return worker.NewStringsWorker(&handler{facade, lock})
+}
+
+type handler struct {
+	facade Facade
+	lock   *fslock.Lock
+}
+
+// SetUp is part of the worker.StringsWatchHandler interface.
+func (h *handler) SetUp() (watcher.StringsWatcher, error) {
+	return h.facade.WatchActionNotifications()
+}
+
+// TearDown is part of the worker.StringsWatchHandler interface.
+func (h *handler) TearDown() error {
+	return nil
+}
+
+// Handle is part of the worker.StringsWatchHandler interface.
+func (h *handler) Handle(actionIds []string) error {
+	for _, actionId := range actionIds {
+		if !names.IsValidAction(actionId) {
+			return errors.Errorf("got invalid action id %q", actionId)
+		}
+		if err := h.runAction(names.NewActionTag(actionId)); err != nil {
+			return errors.Annotatef(err, "cannot run action %q", actionId)
+		}
+	}
+	return nil
+}
+
+func (h *handler) runAction(tag names.ActionTag) error {
+	action, err := h.facade.Action(tag)
+	if params.IsCodeActionNotAvailable(err) {
+		// The action has already been run or cancelled.
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	if action.Name() != actions.JujuRunActionName {
+		message := fmt.Sprintf("action %q not supported on machines", action.Name())
+		return h.facade.ActionFinish(tag, params.ActionFailed, nil, message)
+	}
+	commands, timeout, err := actions.ParseJujuRunParams(action.Params())
+	if err != nil {
+		return h.facade.ActionFinish(tag, params.ActionFailed, nil, err.Error())
+	}
+	if err := h.facade.ActionBegin(tag); err != nil {
+		return errors.Trace(err)
+	}
+	logger.Debugf("running action %q", tag.Id())
+	response, err := h.runCommands(commands, timeout)
+	if err != nil {
+		return h.facade.ActionFinish(tag, params.ActionFailed, nil, err.Error())
+	}
+	results := make(map[string]interface{})
+	for key, value := range actions.JujuRunResults(response) {
+		results[key] = value
+	}
+	return h.facade.ActionFinish(tag, params.ActionCompleted, results, "")
+}
+
+// runCommands runs the commands, killing them if they are still running
+// after timeout. A zero timeout means the commands are never killed.
+func (h *handler) runCommands(commands string, timeout time.Duration) (*exec.ExecResponse, error) {
+	if err := h.lock.Lock("running machine action"); err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer h.lock.Unlock()
+
+	if version.Current.OS == version.Ubuntu {
+		// Make the proxy settings for the environment available to
+		// the commands, as they are for hooks.
+		proxyFile := path.Join(proxyupdater.ProxyDirectory, proxyupdater.ProxyFile)
+		commands = fmt.Sprintf("[ -f %q ] && . %q\n%s", proxyFile, proxyFile, commands)
+	}
+	cmd := newCommand(commands)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Start(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	timedOut := make(chan struct{})
+	if timeout > 0 {
+		// Kill the whole process group, so that processes started
+		// by the commands do not keep running, or keep their output
+		// open, after the timeout.
+		timer := time.AfterFunc(timeout, func() {
+			close(timedOut)
+			if err := killProcessGroup(cmd.Process); err != nil {
+				logger.Warningf("cannot kill timed out commands: %v", err)
+			}
+		})
+		defer timer.Stop()
+	}
+	err := cmd.Wait()
+	select {
+	case <-timedOut:
+		return nil, errors.Errorf("command timed out after %v", timeout)
+	default:
+	}
+	response := &exec.ExecResponse{
+		Stdout: stdout.Bytes(),
+		Stderr: stderr.Bytes(),
+	}
+	if exitErr, ok := err.(*osexec.ExitError); ok {
+		if status, ok := exitErr.Sys().(syscall.WaitStatus); ok {
+			response.Code = status.ExitStatus()
+			err = nil
+		}
+	}
+	return response, errors.Trace(err)
+}
@@ -0,0 +1,200 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machineactions_test
+
+import (
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils/fslock"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/actions"
+	"github.com/juju/juju/api/machineactions"
+	"github.com/juju/juju/api/watcher"
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+	machineactionsworker "github.com/juju/juju/worker/machineactions"
+)
+
+type WorkerSuite struct {
+	coretesting.BaseSuite
+	lock *fslock.Lock
+}
+
+var _ = gc.Suite(&WorkerSuite{})
+
+const (
+	actionId1 = "11111111-1111-1111-1111-111111111111"
+	actionId2 = "22222222-2222-2222-2222-222222222222"
+	actionId3 = "33333333-3333-3333-3333-333333333333"
+)
+
+func (s *WorkerSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	var err error
+	s.lock, err = fslock.NewLock(c.MkDir(), "hook-execution")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *WorkerSuite) runWorker(c *gc.C, facade *mockFacade, ids ...string) {
+	w := machineactionsworker.NewWorker(facade, s.lock)
+	defer func() {
+		w.Kill()
+		c.Check(w.Wait(), jc.ErrorIsNil)
+	}()
+	select {
+	case facade.changes <- ids:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out sending changes")
+	}
+	for _ = range ids {
+		select {
+		case <-facade.finished:
+		case <-time.After(coretesting.LongWait):
+			c.Fatalf("timed out waiting for actions to finish")
+		}
+	}
+}
+
+func (s *WorkerSuite) TestRunsJujuRunActions(c *gc.C) {
+	facade := newMockFacade(map[string]*machineactions.Action{
+		actionId1: machineactions.NewAction(actions.JujuRunActionName, actions.JujuRunParams("echo hello; echo oops >&2; exit 3", 0)),
+		actionId2: machineactions.NewAction(actions.JujuRunActionName, actions.JujuRunParams("sleep 1", time.Millisecond)),
+	})
+	s.runWorker(c, facade, actionId1, actionId2)
+
+	c.Assert(facade.begun, jc.DeepEquals, []string{actionId1, actionId2})
+	c.Assert(facade.results[actionId1], jc.DeepEquals, finishedAction{
+		status: params.ActionCompleted,
+		results: map[string]interface{}{
+			"Code":   "3",
+			"Stdout": "hello\n",
+			"Stderr": "oops\n",
+		},
+	})
+	c.Assert(facade.results[actionId2].status, gc.Equals, params.ActionFailed)
+	c.Assert(facade.results[actionId2].message, gc.Equals, "command timed out after 1ms")
+}
+
+func (s *WorkerSuite) TestTimeoutKillsStartedProcesses(c *gc.C) {
+	// The background sleep keeps the output of the commands open, so
+	// the action only finishes in time if it is killed too.
+	facade := newMockFacade(map[string]*machineactions.Action{
+		actionId1: machineactions.NewAction(actions.JujuRunActionName, actions.JujuRunParams("sleep 60 & wait", 10*time.Millisecond)),
+	})
+	s.runWorker(c, facade, actionId1)
+
+	c.Assert(facade.results[actionId1].status, gc.Equals, params.ActionFailed)
+	c.Assert(facade.results[actionId1].message, gc.Equals, "command timed out after 10ms")
+}
+
+func (s *WorkerSuite) TestRejectsOtherActions(c *gc.C) {
+	facade := newMockFacade(map[string]*machineactions.Action{
+		actionId1: machineactions.NewAction("snapshot", nil),
+		actionId2: machineactions.NewAction(actions.JujuRunActionName, map[string]interface{}{"timeout": 10}),
+	})
+	s.runWorker(c, facade, actionId1, actionId2)
+
+	c.Assert(facade.begun, gc.HasLen, 0)
+	c.Assert(facade.results, jc.DeepEquals, map[string]finishedAction{
+		actionId1: {
+			status:  params.ActionFailed,
+			message: `action "snapshot" not supported on machines`,
+		},
+		actionId2: {
+			status:  params.ActionFailed,
+			message: "command not specified",
+		},
+	})
+}
+
+func (s *WorkerSuite) TestSkipsUnavailableActions(c *gc.C) {
+	facade := newMockFacade(map[string]*machineactions.Action{
+		actionId1: machineactions.NewAction(actions.JujuRunActionName, actions.JujuRunParams("true", 0)),
+	})
+	w := machineactionsworker.NewWorker(facade, s.lock)
+	defer func() {
+		w.Kill()
+		c.Check(w.Wait(), jc.ErrorIsNil)
+	}()
+	// actionId3 is not known to the facade, so it is reported as no
+	// longer available and must not stop the worker.
+	facade.changes <- []string{actionId3, actionId1}
+	select {
+	case <-facade.finished:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for action to finish")
+	}
+	c.Assert(facade.begun, jc.DeepEquals, []string{actionId1})
+}
+
+type finishedAction struct {
+	status  string
+	results map[string]interface{}
+	message string
+}
+
+type mockFacade struct {
+	mu       sync.Mutex
+	changes  chan []string
+	finished chan struct{}
+	actions  map[string]*machineactions.Action
+	begun    []string
+	results  map[string]finishedAction
+}
+
+func newMockFacade(actions map[string]*machineactions.Action) *mockFacade {
+	return &mockFacade{
+		changes:  make(chan []string),
+		finished: make(chan struct{}, len(actions)),
+		actions:  actions,
+		results:  make(map[string]finishedAction),
+	}
+}
+
+func (m *mockFacade) WatchActionNotifications() (watcher.StringsWatcher, error) {
+	return m, nil
+}
+
+func (m *mockFacade) Changes() <-chan []string {
+	return m.changes
+}
+
+func (m *mockFacade) Stop() error {
+	return nil
+}
+
+func (m *mockFacade) Err() error {
+	return nil
+}
+
+func (m *mockFacade) Action(tag names.ActionTag) (*machineactions.Action, error) {
+	action, ok := m.actions[tag.Id()]
+	if !ok {
+		return nil, &params.Error{Code: params.CodeActionNotAvailable, Message: "action no longer available"}
+	}
+	return action, nil
+}
+
+func (m *mockFacade) ActionBegin(tag names.ActionTag) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.begun = append(m.begun, tag.Id())
+	return nil
+}
+
+func (m *mockFacade) ActionFinish(tag names.ActionTag, status string, results map[string]interface{}, message string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.results[tag.Id()]; ok {
+		return errors.Errorf("action %q finished twice", tag.Id())
+	}
+	m.results[tag.Id()] = finishedAction{status, results, message}
+	m.finished <- struct{}{}
+	return nil
+}
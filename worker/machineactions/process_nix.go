@@ -0,0 +1,28 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// +build !windows
+
+package machineactions
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+)
+
+// newCommand returns a command that runs commands with bash, in a new
+// process group so that it can be killed along with any processes it
+// starts.
+func newCommand(commands string) *exec.Cmd {
+	cmd := exec.Command("/bin/bash", "-s")
+	cmd.Stdin = strings.NewReader(commands)
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	return cmd
+}
+
+// killProcessGroup kills the process group led by p.
+func killProcessGroup(p *os.Process) error {
+	return syscall.Kill(-p.Pid, syscall.SIGKILL)
+}
@@ -0,0 +1,22 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machineactions
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// newCommand returns a command that runs commands with powershell.
+func newCommand(commands string) *exec.Cmd {
+	cmd := exec.Command("powershell.exe", "-noprofile", "-noninteractive", "-command", "$input|iex")
+	cmd.Stdin = strings.NewReader(commands)
+	return cmd
+}
+
+// killProcessGroup kills p. Windows has no process groups to kill.
+func killProcessGroup(p *os.Process) error {
+	return p.Kill()
+}
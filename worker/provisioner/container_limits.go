@@ -0,0 +1,212 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provisioner
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/tomb"
+
+	apiprovisioner "github.com/juju/juju/api/provisioner"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/state/watcher"
+)
+
+// limitsUpdater is implemented by container brokers that can change
+// the resource limits of the containers they have started.
+type limitsUpdater interface {
+	// UpdateInstanceLimits changes the resource limits of the given
+	// container to enforce the given constraints, and returns the
+	// resulting hardware characteristics.
+	UpdateInstanceLimits(instance.Id, constraints.Value) (*instance.HardwareCharacteristics, error)
+}
+
+// containerLimits watches the containers of one type on a host machine,
+// and updates the resource limits of each provisioned container whenever
+// its constraints change.
+type containerLimits struct {
+	tomb          tomb.Tomb
+	st            *apiprovisioner.State
+	host          *apiprovisioner.Machine
+	containerType instance.ContainerType
+	updater       limitsUpdater
+	containerds   map[string]*containerData
+}
+
+// newContainerLimits returns a worker that updates the resource limits
+// of the containers of the given type on host when their constraints
+// change.
+func newContainerLimits(
+	st *apiprovisioner.State,
+	host *apiprovisioner.Machine,
+	containerType instance.ContainerType,
+	updater limitsUpdater,
+) *containerLimits {
+	cl := &containerLimits{
+		st:            st,
+		host:          host,
+		containerType: containerType,
+		updater:       updater,
+		containerds:   make(map[string]*containerData),
+	}
+	go func() {
+		defer cl.tomb.Done()
+		cl.tomb.Kill(cl.loop())
+	}()
+	return cl
+}
+
+func (cl *containerLimits) loop() error {
+	w, err := cl.host.WatchContainers(cl.containerType)
+	if err != nil {
+		return err
+	}
+	defer watcher.Stop(w, &cl.tomb)
+	defer cl.stopContainers()
+	for {
+		select {
+		case <-cl.tomb.Dying():
+			return tomb.ErrDying
+		case ids, ok := <-w.Changes():
+			if !ok {
+				return watcher.EnsureErr(w)
+			}
+			for _, id := range ids {
+				if err := cl.containerChanged(id); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+// containerChanged starts watching the constraints of the container
+// with the given id, or stops watching them once it is dead or removed.
+func (cl *containerLimits) containerChanged(id string) error {
+	machine, err := cl.st.Machine(names.NewMachineTag(id))
+	if params.IsCodeNotFoundOrCodeUnauthorized(err) || err == nil && machine.Life() == params.Dead {
+		if cd, ok := cl.containerds[id]; ok {
+			delete(cl.containerds, id)
+			return cd.Stop()
+		}
+		return nil
+	} else if err != nil {
+		return errors.Annotatef(err, "failed to get container %v", id)
+	}
+	if _, ok := cl.containerds[id]; ok {
+		return nil
+	}
+	cd := &containerData{
+		cl:      cl,
+		machine: machine,
+	}
+	cl.containerds[id] = cd
+	go cd.watchLoop()
+	return nil
+}
+
+func (cl *containerLimits) stopContainers() {
+	for id, cd := range cl.containerds {
+		watcher.Stop(cd, &cl.tomb)
+		delete(cl.containerds, id)
+	}
+}
+
+// Kill implements worker.Worker.Kill.
+func (cl *containerLimits) Kill() {
+	cl.tomb.Kill(nil)
+}
+
+// Wait implements worker.Worker.Wait.
+func (cl *containerLimits) Wait() error {
+	return cl.tomb.Wait()
+}
+
+// Stop stops the worker and returns any error it encountered.
+func (cl *containerLimits) Stop() error {
+	cl.Kill()
+	return cl.Wait()
+}
+
+// Dying returns a channel that is closed when the worker is stopping.
+func (cl *containerLimits) Dying() <-chan struct{} {
+	return cl.tomb.Dying()
+}
+
+// Err returns the reason why the worker has stopped, or
+// tomb.ErrStillAlive when it is still alive.
+func (cl *containerLimits) Err() error {
+	return cl.tomb.Err()
+}
+
+// containerData holds container details, and watches the container's
+// constraints.
+type containerData struct {
+	tomb    tomb.Tomb
+	cl      *containerLimits
+	machine *apiprovisioner.Machine
+}
+
+// watchLoop updates the resource limits of the container to enforce its
+// constraints, whenever they change. The limits are also reapplied when
+// watching starts, in case the constraints changed while the host's
+// agent was not running.
+func (cd *containerData) watchLoop() {
+	defer cd.tomb.Done()
+	w, err := cd.machine.WatchConstraints()
+	if err != nil {
+		cd.cl.tomb.Kill(err)
+		return
+	}
+	defer watcher.Stop(w, &cd.tomb)
+	for {
+		select {
+		case <-cd.tomb.Dying():
+			return
+		case _, ok := <-w.Changes():
+			if !ok {
+				cd.cl.tomb.Kill(watcher.EnsureErr(w))
+				return
+			}
+			if err := cd.updateLimits(); err != nil {
+				cd.cl.tomb.Kill(err)
+				return
+			}
+		}
+	}
+}
+
+// updateLimits changes the resource limits of the container to enforce
+// its current constraints. A failure to change them is only logged, so
+// that it does not stop the limits of other containers being updated.
+func (cd *containerData) updateLimits() error {
+	instId, err := cd.machine.InstanceId()
+	if params.IsCodeNotProvisioned(err) || params.IsCodeNotFoundOrCodeUnauthorized(err) {
+		// The limits are set when the container is created.
+		return nil
+	} else if err != nil {
+		return errors.Annotatef(err, "failed to get instance id of container %v", cd.machine)
+	}
+	cons, err := cd.machine.Constraints()
+	if params.IsCodeNotFoundOrCodeUnauthorized(err) {
+		return nil
+	} else if err != nil {
+		return errors.Annotatef(err, "failed to get constraints of container %v", cd.machine)
+	}
+	hardware, err := cd.cl.updater.UpdateInstanceLimits(instId, cons)
+	if err != nil {
+		logger.Errorf("cannot update resource limits of container %v: %v", cd.machine, err)
+		return nil
+	}
+	logger.Infof("updated resource limits of container %v to %v", cd.machine, hardware)
+	return nil
+}
+
+// Stop stops the container watching.
+func (cd *containerData) Stop() error {
+	cd.tomb.Kill(nil)
+	return cd.tomb.Wait()
+}
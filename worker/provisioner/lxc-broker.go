@@ -10,6 +10,7 @@ import (
 
 	"github.com/juju/juju/agent"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/container"
 	"github.com/juju/juju/container/lxc"
 	"github.com/juju/juju/environs"
@@ -18,7 +19,10 @@ import (
 
 var lxcLogger = loggo.GetLogger("juju.provisioner.lxc")
 
-var _ environs.InstanceBroker = (*lxcBroker)(nil)
+var (
+	_ environs.InstanceBroker = (*lxcBroker)(nil)
+	_ limitsUpdater           = (*lxcBroker)(nil)
+)
 
 type APICalls interface {
 	ContainerConfig() (params.ContainerConfig, error)
@@ -67,6 +71,8 @@ func (broker *lxcBroker) StartInstance(args environs.StartInstanceParams) (*envi
 	series := args.Tools.OneSeries()
 	args.MachineConfig.MachineContainerType = instance.LXC
 	args.MachineConfig.Tools = args.Tools[0]
+	// The container manager enforces the constraints as resource limits.
+	args.MachineConfig.Constraints = args.Constraints
 
 	config, err := broker.api.ContainerConfig()
 	if err != nil {
@@ -118,3 +124,12 @@ func (broker *lxcBroker) StopInstances(ids ...instance.Id) error {
 func (broker *lxcBroker) AllInstances() (result []instance.Instance, err error) {
 	return broker.manager.ListContainers()
 }
+
+// UpdateInstanceLimits implements limitsUpdater.
+func (broker *lxcBroker) UpdateInstanceLimits(id instance.Id, cons constraints.Value) (*instance.HardwareCharacteristics, error) {
+	updater, ok := broker.manager.(container.LimitsUpdater)
+	if !ok {
+		return nil, errors.New("updating lxc container limits is not supported")
+	}
+	return updater.UpdateContainerLimits(id, cons)
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/juju/errors"
@@ -87,12 +88,15 @@ func (s *lxcBrokerSuite) SetUpTest(c *gc.C) {
 }
 
 func (s *lxcBrokerSuite) startInstance(c *gc.C, machineId string) instance.Instance {
+	return s.startInstanceWithConstraints(c, machineId, constraints.Value{}).Instance
+}
+
+func (s *lxcBrokerSuite) startInstanceWithConstraints(c *gc.C, machineId string, cons constraints.Value) *environs.StartInstanceResult {
 	machineNonce := "fake-nonce"
 	stateInfo := jujutesting.FakeStateInfo(machineId)
 	apiInfo := jujutesting.FakeAPIInfo(machineId)
 	machineConfig, err := environs.NewMachineConfig(machineId, machineNonce, "released", "quantal", true, nil, stateInfo, apiInfo)
 	c.Assert(err, jc.ErrorIsNil)
-	cons := constraints.Value{}
 	possibleTools := coretools.List{&coretools.Tools{
 		Version: version.MustParseBinary("2.3.4-quantal-amd64"),
 		URL:     "http://tools.testing.invalid/2.3.4-quantal-amd64.tgz",
@@ -103,7 +107,7 @@ func (s *lxcBrokerSuite) startInstance(c *gc.C, machineId string) instance.Insta
 		MachineConfig: machineConfig,
 	})
 	c.Assert(err, jc.ErrorIsNil)
-	return result.Instance
+	return result
 }
 
 func (s *lxcBrokerSuite) TestStartInstance(c *gc.C) {
@@ -133,6 +137,14 @@ func (s *lxcBrokerSuite) TestStartInstanceWithBridgeEnviron(c *gc.C) {
 	c.Assert(string(lxcConfContents), jc.Contains, "lxc.network.link = br0")
 }
 
+func (s *lxcBrokerSuite) TestStartInstanceEnforcesConstraints(c *gc.C) {
+	result := s.startInstanceWithConstraints(c, "1/lxc/0", constraints.MustParse("mem=512M"))
+	c.Assert(*result.Hardware.Mem, gc.Equals, uint64(512))
+	config, err := ioutil.ReadFile(filepath.Join(s.LxcDir, string(result.Instance.Id()), "config"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(config), jc.Contains, "lxc.cgroup.memory.limit_in_bytes = 512M\n")
+}
+
 func (s *lxcBrokerSuite) TestStopInstance(c *gc.C) {
 	lxc0 := s.startInstance(c, "1/lxc/0")
 	lxc1 := s.startInstance(c, "1/lxc/1")
@@ -295,6 +307,28 @@ func (s *lxcProvisionerSuite) TestContainerStartedAndStopped(c *gc.C) {
 	s.waitRemoved(c, container)
 }
 
+func (s *lxcProvisionerSuite) TestContainerLimitsUpdated(c *gc.C) {
+	p := s.newLxcProvisioner(c)
+	defer stop(c, p)
+
+	container := s.addContainer(c)
+	instId := s.expectStarted(c, container)
+
+	// The container's limits are updated when its constraints change.
+	err := container.SetConstraints(constraints.MustParse("mem=2G"))
+	c.Assert(err, jc.ErrorIsNil)
+	configPath := filepath.Join(s.LxcDir, instId, "config")
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		s.State.StartSync()
+		config, err := ioutil.ReadFile(configPath)
+		c.Assert(err, jc.ErrorIsNil)
+		if strings.Contains(string(config), "lxc.cgroup.memory.limit_in_bytes = 2048M\n") {
+			return
+		}
+	}
+	c.Fatalf("resource limits of container %v not updated", container)
+}
+
 type fakeAPI struct{}
 
 func (*fakeAPI) ContainerConfig() (params.ContainerConfig, error) {
@@ -0,0 +1,127 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provisioner_test
+
+import (
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/container"
+	"github.com/juju/juju/container/lxd"
+	lxdtesting "github.com/juju/juju/container/lxd/testing"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/instance"
+	instancetest "github.com/juju/juju/instance/testing"
+	jujutesting "github.com/juju/juju/juju/testing"
+	coretesting "github.com/juju/juju/testing"
+	coretools "github.com/juju/juju/tools"
+	"github.com/juju/juju/version"
+	"github.com/juju/juju/worker/provisioner"
+)
+
+type lxdBrokerSuite struct {
+	lxdtesting.TestSuite
+	broker      environs.InstanceBroker
+	agentConfig agent.Config
+}
+
+var _ = gc.Suite(&lxdBrokerSuite{})
+
+func (s *lxdBrokerSuite) SetUpTest(c *gc.C) {
+	s.TestSuite.SetUpTest(c)
+	var err error
+	s.agentConfig, err = agent.NewAgentConfig(
+		agent.AgentConfigParams{
+			DataDir:           "/not/used/here",
+			Tag:               names.NewUnitTag("ubuntu/1"),
+			UpgradedToVersion: version.Current.Number,
+			Password:          "dummy-secret",
+			Nonce:             "nonce",
+			APIAddresses:      []string{"10.0.0.1:1234"},
+			CACert:            coretesting.CACert,
+			Environment:       coretesting.EnvironmentTag,
+		})
+	c.Assert(err, jc.ErrorIsNil)
+	managerConfig := container.ManagerConfig{container.ConfigName: "juju"}
+	s.broker, err = provisioner.NewLxdBroker(&fakeAPI{}, s.agentConfig, managerConfig)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *lxdBrokerSuite) startInstance(c *gc.C, machineId string) instance.Instance {
+	return s.startInstanceWithConstraints(c, machineId, constraints.Value{}).Instance
+}
+
+func (s *lxdBrokerSuite) startInstanceWithConstraints(c *gc.C, machineId string, cons constraints.Value) *environs.StartInstanceResult {
+	machineNonce := "fake-nonce"
+	stateInfo := jujutesting.FakeStateInfo(machineId)
+	apiInfo := jujutesting.FakeAPIInfo(machineId)
+	machineConfig, err := environs.NewMachineConfig(machineId, machineNonce, "released", "quantal", true, nil, stateInfo, apiInfo)
+	c.Assert(err, jc.ErrorIsNil)
+	possibleTools := coretools.List{&coretools.Tools{
+		Version: version.MustParseBinary("2.3.4-quantal-amd64"),
+		URL:     "http://tools.testing.invalid/2.3.4-quantal-amd64.tgz",
+	}}
+	result, err := s.broker.StartInstance(environs.StartInstanceParams{
+		Constraints:   cons,
+		Tools:         possibleTools,
+		MachineConfig: machineConfig,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return result
+}
+
+func (s *lxdBrokerSuite) TestStartInstance(c *gc.C) {
+	lxd0 := s.startInstance(c, "1/lxd/0")
+	c.Assert(lxd0.Id(), gc.Equals, instance.Id("juju-machine-1-lxd-0"))
+
+	ctr, ok := s.Server.Container("juju-machine-1-lxd-0")
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(ctr.Status, gc.Equals, lxd.StatusRunning)
+	c.Assert(ctr.Devices["eth0"]["parent"], gc.Equals, lxd.DefaultLxdBridge)
+}
+
+func (s *lxdBrokerSuite) TestStartInstanceAppliesConstraints(c *gc.C) {
+	result := s.startInstanceWithConstraints(c, "1/lxd/0", constraints.MustParse("mem=512M"))
+	c.Assert(*result.Hardware.Mem, gc.Equals, uint64(512))
+
+	ctr, ok := s.Server.Container(string(result.Instance.Id()))
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(ctr.Config["limits.memory"], gc.Equals, "512MB")
+}
+
+func (s *lxdBrokerSuite) TestStopInstance(c *gc.C) {
+	lxd0 := s.startInstance(c, "1/lxd/0")
+	lxd1 := s.startInstance(c, "1/lxd/1")
+	lxd2 := s.startInstance(c, "1/lxd/2")
+
+	err := s.broker.StopInstances(lxd0.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertInstances(c, lxd1, lxd2)
+	_, ok := s.Server.Container(string(lxd0.Id()))
+	c.Assert(ok, jc.IsFalse)
+
+	err = s.broker.StopInstances(lxd1.Id(), lxd2.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertInstances(c)
+}
+
+func (s *lxdBrokerSuite) TestAllInstances(c *gc.C) {
+	lxd0 := s.startInstance(c, "1/lxd/0")
+	lxd1 := s.startInstance(c, "1/lxd/1")
+	s.assertInstances(c, lxd0, lxd1)
+
+	err := s.broker.StopInstances(lxd1.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	lxd2 := s.startInstance(c, "1/lxd/2")
+	s.assertInstances(c, lxd0, lxd2)
+}
+
+func (s *lxdBrokerSuite) assertInstances(c *gc.C, inst ...instance.Instance) {
+	results, err := s.broker.AllInstances()
+	c.Assert(err, jc.ErrorIsNil)
+	instancetest.MatchInstances(c, results, inst...)
+}
@@ -106,8 +106,9 @@ var getToolsFinder = func(st *apiprovisioner.State) ToolsFinder {
 	return st
 }
 
-// getStartTask creates a new worker for the provisioner,
-func (p *provisioner) getStartTask(harvestMode config.HarvestMode) (ProvisionerTask, error) {
+// getStartTask creates a new worker for the provisioner, which will
+// start at most concurrency machines at the same time.
+func (p *provisioner) getStartTask(harvestMode config.HarvestMode, concurrency int) (ProvisionerTask, error) {
 	auth, err := authentication.NewAPIAuthenticator(p.st)
 	if err != nil {
 		return nil, err
@@ -148,10 +149,21 @@ func (p *provisioner) getStartTask(harvestMode config.HarvestMode) (ProvisionerT
 		auth,
 		envCfg.ImageStream(),
 		secureServerConnection,
+		concurrency,
+		newRetryStrategy(envCfg),
 	)
 	return task, nil
 }
 
+// newRetryStrategy returns the strategy for retrying the starting
+// of instances held in the given environment configuration.
+func newRetryStrategy(cfg *config.Config) RetryStrategy {
+	return RetryStrategy{
+		Count: cfg.ProvisionerRetryCount(),
+		Delay: cfg.ProvisionerRetryDelay(),
+	}
+}
+
 // NewEnvironProvisioner returns a new Provisioner for an environment.
 // When new machines are added to the state, it allocates instances
 // from the environment and allocates them to the new machines.
@@ -186,8 +198,11 @@ func (p *environProvisioner) loop() error {
 	}
 	p.broker = p.environ
 
-	harvestMode := p.environ.Config().ProvisionerHarvestMode()
-	task, err := p.getStartTask(harvestMode)
+	cfg := p.environ.Config()
+	task, err := p.getStartTask(
+		cfg.ProvisionerHarvestMode(),
+		cfg.ProvisionerConcurrency(),
+	)
 	if err != nil {
 		return err
 	}
@@ -214,6 +229,8 @@ func (p *environProvisioner) loop() error {
 				logger.Errorf("loaded invalid environment configuration: %v", err)
 			}
 			task.SetHarvestMode(environConfig.ProvisionerHarvestMode())
+			task.SetConcurrency(environConfig.ProvisionerConcurrency())
+			task.SetRetryStrategy(newRetryStrategy(environConfig))
 		}
 	}
 }
@@ -260,12 +277,34 @@ func NewContainerProvisioner(containerType instance.ContainerType, st *apiprovis
 }
 
 func (p *containerProvisioner) loop() error {
-	task, err := p.getStartTask(config.HarvestDestroyed)
+	environWatcher, err := p.st.WatchForEnvironConfigChanges()
+	if err != nil {
+		return err
+	}
+	defer watcher.Stop(environWatcher, &p.tomb)
+
+	// Containers on a host are started one at a time, as
+	// they share the host's resources and container templates.
+	task, err := p.getStartTask(config.HarvestDestroyed, 1)
 	if err != nil {
 		return err
 	}
 	defer watcher.Stop(task, &p.tomb)
 
+	// Brokers that can change the resource limits of their containers
+	// update them when the containers' constraints change.
+	var limits *containerLimits
+	var limitsDying <-chan struct{}
+	if updater, ok := p.broker.(limitsUpdater); ok {
+		machine, err := p.getMachine()
+		if err != nil {
+			return err
+		}
+		limits = newContainerLimits(p.st, machine, p.containerType, updater)
+		defer watcher.Stop(limits, &p.tomb)
+		limitsDying = limits.Dying()
+	}
+
 	for {
 		select {
 		case <-p.tomb.Dying():
@@ -274,6 +313,20 @@ func (p *containerProvisioner) loop() error {
 			err := task.Err()
 			logger.Errorf("%s provisioner died: %v", p.containerType, err)
 			return err
+		case <-limitsDying:
+			err := limits.Err()
+			logger.Errorf("%s container limits updater died: %v", p.containerType, err)
+			return err
+		case _, ok := <-environWatcher.Changes():
+			if !ok {
+				return watcher.EnsureErr(environWatcher)
+			}
+			environConfig, err := p.st.EnvironConfig()
+			if err != nil {
+				logger.Errorf("cannot load environment configuration: %v", err)
+				return err
+			}
+			task.SetRetryStrategy(newRetryStrategy(environConfig))
 		}
 	}
 }
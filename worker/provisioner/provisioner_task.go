@@ -5,6 +5,7 @@ package provisioner
 
 import (
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/juju/errors"
@@ -23,6 +24,7 @@ import (
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/network"
+	"github.com/juju/juju/provider/common"
 	"github.com/juju/juju/state/watcher"
 	coretools "github.com/juju/juju/tools"
 	"github.com/juju/juju/version"
@@ -39,6 +41,14 @@ type ProvisionerTask interface {
 	// should harvest machines. See config.HarvestMode for
 	// documentation of behavior.
 	SetHarvestMode(mode config.HarvestMode)
+
+	// SetConcurrency sets the maximum number of machines
+	// the provisioner task starts at the same time.
+	SetConcurrency(concurrency int)
+
+	// SetRetryStrategy sets how the provisioner task retries
+	// starting instances after transient failures.
+	SetRetryStrategy(strategy RetryStrategy)
 }
 
 type MachineGetter interface {
@@ -54,6 +64,17 @@ type ToolsFinder interface {
 	FindTools(version version.Number, series string, arch *string) (coretools.List, error)
 }
 
+// RetryStrategy defines how the provisioner retries starting an
+// instance after a retryable failure.
+type RetryStrategy struct {
+	// Count is the number of times starting an instance is retried.
+	Count int
+
+	// Delay is the time to wait before the first retry;
+	// it doubles with each further retry.
+	Delay time.Duration
+}
+
 var _ MachineGetter = (*apiprovisioner.State)(nil)
 var _ ToolsFinder = (*apiprovisioner.State)(nil)
 
@@ -68,6 +89,8 @@ func NewProvisionerTask(
 	auth authentication.AuthenticationProvider,
 	imageStream string,
 	secureServerConnection bool,
+	concurrency int,
+	retryStrategy RetryStrategy,
 ) ProvisionerTask {
 	task := &provisionerTask{
 		machineTag:             machineTag,
@@ -82,6 +105,9 @@ func NewProvisionerTask(
 		machines:               make(map[string]*apiprovisioner.Machine),
 		imageStream:            imageStream,
 		secureServerConnection: secureServerConnection,
+		concurrency:            concurrency,
+		retryStrategy:          retryStrategy,
+		startFailures:          make(map[string]*startFailures),
 	}
 	go func() {
 		defer task.tomb.Done()
@@ -103,6 +129,15 @@ type provisionerTask struct {
 	secureServerConnection bool
 	harvestMode            config.HarvestMode
 	harvestModeChan        chan config.HarvestMode
+	// mu guards concurrency, retryStrategy and startFailures,
+	// which may change while machines are being started.
+	mu sync.Mutex
+	// concurrency holds the maximum number of machines
+	// that will be started at the same time.
+	concurrency   int
+	retryStrategy RetryStrategy
+	// machine id -> failed attempts to start the machine's instance
+	startFailures map[string]*startFailures
 	// instance id -> instance
 	instances map[instance.Id]instance.Instance
 	// machine id -> machine
@@ -132,6 +167,17 @@ func (task *provisionerTask) Err() error {
 	return task.tomb.Err()
 }
 
+// startFailures records the failed attempts to start an instance
+// for a machine, while starting it may yet be retried.
+type startFailures struct {
+	machine  *apiprovisioner.Machine
+	attempts []interface{}
+	retries  int
+	// retryAt holds the time at which starting the instance
+	// is retried; it is zero when no retry is scheduled.
+	retryAt time.Time
+}
+
 func (task *provisionerTask) loop() error {
 	logger.Infof("Starting up provisioner task %s", task.machineTag)
 	defer watcher.Stop(task.machineWatcher, &task.tomb)
@@ -148,6 +194,10 @@ func (task *provisionerTask) loop() error {
 		retryChan = task.retryWatcher.Changes()
 	}
 
+	// Starting an instance that failed transiently is retried when
+	// the retry timer fires, rather than while other machines wait.
+	var retryTimer <-chan time.Time
+
 	// When the watcher is started, it will have the initial changes be all
 	// the machines that are relevant. Also, since this is available straight
 	// away, we know there will be some changes right off the bat.
@@ -185,7 +235,12 @@ func (task *provisionerTask) loop() error {
 			if err := task.processMachinesWithTransientErrors(); err != nil {
 				return errors.Annotate(err, "failed to process machines with transient errors")
 			}
+		case <-retryTimer:
+			if err := task.retryStartMachines(); err != nil {
+				return errors.Annotate(err, "failed to retry starting machines")
+			}
 		}
+		retryTimer = task.nextRetry()
 	}
 }
 
@@ -197,6 +252,89 @@ func (task *provisionerTask) SetHarvestMode(mode config.HarvestMode) {
 	}
 }
 
+// SetConcurrency implements ProvisionerTask.SetConcurrency().
+func (task *provisionerTask) SetConcurrency(concurrency int) {
+	task.mu.Lock()
+	defer task.mu.Unlock()
+	if concurrency != task.concurrency {
+		logger.Infof("provisioner concurrency changed to %d", concurrency)
+		task.concurrency = concurrency
+	}
+}
+
+// SetRetryStrategy implements ProvisionerTask.SetRetryStrategy().
+func (task *provisionerTask) SetRetryStrategy(strategy RetryStrategy) {
+	task.mu.Lock()
+	defer task.mu.Unlock()
+	if strategy != task.retryStrategy {
+		logger.Infof("provisioner retry strategy changed to %+v", strategy)
+		task.retryStrategy = strategy
+	}
+}
+
+// nextRetry returns a channel which receives a value when the next
+// scheduled retry is due, or nil if no retry is scheduled.
+func (task *provisionerTask) nextRetry() <-chan time.Time {
+	task.mu.Lock()
+	defer task.mu.Unlock()
+	var next time.Time
+	for _, failures := range task.startFailures {
+		if failures.retryAt.IsZero() {
+			continue
+		}
+		if next.IsZero() || failures.retryAt.Before(next) {
+			next = failures.retryAt
+		}
+	}
+	if next.IsZero() {
+		return nil
+	}
+	return time.After(next.Sub(time.Now()))
+}
+
+// retryStartMachines starts instances again for the machines
+// whose scheduled retry is due.
+func (task *provisionerTask) retryStartMachines() error {
+	now := time.Now()
+	var due []*apiprovisioner.Machine
+	task.mu.Lock()
+	for _, failures := range task.startFailures {
+		if !failures.retryAt.IsZero() && !failures.retryAt.After(now) {
+			failures.retryAt = time.Time{}
+			due = append(due, failures.machine)
+		}
+	}
+	task.mu.Unlock()
+	var pending []*apiprovisioner.Machine
+	for _, machine := range due {
+		if err := machine.SetStatus(params.StatusPending, "", nil); err != nil {
+			logger.Errorf("cannot reset status of machine %q: %v", machine, err)
+			task.forgetStartFailures(machine.Id())
+			continue
+		}
+		pending = append(pending, machine)
+	}
+	return task.startMachines(pending)
+}
+
+// retryScheduled reports whether a retry of starting an instance
+// for the machine with the given id is scheduled.
+func (task *provisionerTask) retryScheduled(id string) bool {
+	task.mu.Lock()
+	defer task.mu.Unlock()
+	failures, ok := task.startFailures[id]
+	return ok && !failures.retryAt.IsZero()
+}
+
+// forgetStartFailures forgets the failed attempts to start an
+// instance for the machine with the given id, cancelling any
+// scheduled retry.
+func (task *provisionerTask) forgetStartFailures(id string) {
+	task.mu.Lock()
+	defer task.mu.Unlock()
+	delete(task.startFailures, id)
+}
+
 func (task *provisionerTask) processMachinesWithTransientErrors() error {
 	machines, statusResults, err := task.machineGetter.MachinesWithTransientErrors()
 	if err != nil {
@@ -210,6 +348,10 @@ func (task *provisionerTask) processMachinesWithTransientErrors() error {
 			continue
 		}
 		machine := machines[i]
+		if task.retryScheduled(machine.Id()) {
+			// The provisioner task retries this machine itself.
+			continue
+		}
 		if err := machine.SetStatus(params.StatusPending, "", nil); err != nil {
 			logger.Errorf("cannot reset status of machine %q: %v", status.Id, err)
 			continue
@@ -282,6 +424,7 @@ func (task *provisionerTask) processMachines(ids []string) error {
 			logger.Errorf("failed to remove dead machine %q", machine)
 		}
 		delete(task.machines, machine.Id())
+		task.forgetStartFailures(machine.Id())
 	}
 
 	// Start an instance for the pending ones
@@ -487,50 +630,109 @@ func constructStartInstanceParams(
 		Placement:         provisioningInfo.Placement,
 		DistributionGroup: machine.DistributionGroup,
 		Volumes:           provisioningInfo.Volumes,
+		InstanceTags:      provisioningInfo.Tags,
 	}
 }
 
+// startMachines starts instances for the given machines, with at most
+// task.concurrency instances being started at any one time. A failure
+// to start one machine is recorded in that machine's status and does
+// not prevent the others from being started.
 func (task *provisionerTask) startMachines(machines []*apiprovisioner.Machine) error {
+	task.mu.Lock()
+	concurrency := task.concurrency
+	task.mu.Unlock()
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	var wg sync.WaitGroup
+	errs := make(chan error, len(machines))
+	semaphore := make(chan struct{}, concurrency)
+	dying := false
 	for _, m := range machines {
-
-		pInfo, err := task.blockUntilProvisioned(m.ProvisioningInfo)
-		if err != nil {
-			return err
+		select {
+		case semaphore <- struct{}{}:
+		case <-task.tomb.Dying():
+			dying = true
 		}
-
-		machineCfg, err := task.constructMachineConfig(m, task.auth, pInfo)
+		if dying {
+			break
+		}
+		wg.Add(1)
+		go func(m *apiprovisioner.Machine) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			errs <- task.provisionMachine(m)
+		}(m)
+	}
+	wg.Wait()
+	close(errs)
+	if dying {
+		return tomb.ErrDying
+	}
+	for err := range errs {
 		if err != nil {
 			return err
 		}
+	}
+	return nil
+}
 
-		assocProvInfoAndMachCfg(pInfo, machineCfg)
+// provisionMachine gathers everything needed to start an instance
+// for the given machine, and starts it. Errors that only concern
+// the machine are reported through its status; the returned error
+// is non-nil only if the provisioner task cannot continue.
+func (task *provisionerTask) provisionMachine(m *apiprovisioner.Machine) error {
+	pInfo, err := task.blockUntilProvisioned(m.ProvisioningInfo)
+	if err == tomb.ErrDying {
+		return err
+	} else if err != nil {
+		return task.setErrorStatus("cannot get provisioning info for machine %q: %v", m, err)
+	}
 
-		possibleTools, err := task.toolsFinder.FindTools(
-			version.Current.Number,
-			pInfo.Series,
-			pInfo.Constraints.Arch,
-		)
-		if err != nil {
-			return task.setErrorStatus("cannot find tools for machine %q: %v", m, err)
-		}
+	machineCfg, err := task.constructMachineConfig(m, task.auth, pInfo)
+	if err != nil {
+		return task.setErrorStatus("cannot create machine config for machine %q: %v", m, err)
+	}
 
-		startInstanceParams := constructStartInstanceParams(
-			m,
-			machineCfg,
-			pInfo,
-			possibleTools,
-		)
+	assocProvInfoAndMachCfg(pInfo, machineCfg)
 
-		if err := task.startMachine(m, pInfo, startInstanceParams); err != nil {
-			return errors.Annotatef(err, "cannot start machine %v", m)
-		}
+	possibleTools, err := task.toolsFinder.FindTools(
+		version.Current.Number,
+		pInfo.Series,
+		pInfo.Constraints.Arch,
+	)
+	if err != nil {
+		return task.setErrorStatus("cannot find tools for machine %q: %v", m, err)
+	}
+
+	startInstanceParams := constructStartInstanceParams(
+		m,
+		machineCfg,
+		pInfo,
+		possibleTools,
+	)
+
+	if err := task.startMachine(m, pInfo, startInstanceParams); err == tomb.ErrDying {
+		return err
+	} else if err != nil {
+		return errors.Annotatef(err, "cannot start machine %v", m)
 	}
 	return nil
 }
 
 func (task *provisionerTask) setErrorStatus(message string, machine *apiprovisioner.Machine, err error) error {
+	return task.setErrorStatusWithData(message, machine, err, nil)
+}
+
+func (task *provisionerTask) setErrorStatusWithData(
+	message string,
+	machine *apiprovisioner.Machine,
+	err error,
+	data map[string]interface{},
+) error {
 	logger.Errorf(message, machine, err)
-	if err1 := machine.SetStatus(params.StatusError, err.Error(), nil); err1 != nil {
+	if err1 := machine.SetStatus(params.StatusError, err.Error(), data); err1 != nil {
 		// Something is wrong with this machine, better report it back.
 		return errors.Annotatef(err1, "cannot set error status for machine %q", machine)
 	}
@@ -571,21 +773,13 @@ func (task *provisionerTask) startMachine(
 	startInstanceParams environs.StartInstanceParams,
 ) error {
 
-	result, err := task.broker.StartInstance(startInstanceParams)
+	result, err := task.startInstance(machine, provisioningInfo, startInstanceParams)
 	if err != nil {
-		// If this is a retryable error, we retry once
-		if instance.IsRetryableCreationError(errors.Cause(err)) {
-			logger.Infof("retryable error received on start instance - retrying instance creation")
-			result, err = task.broker.StartInstance(startInstanceParams)
-			if err != nil {
-				return task.setErrorStatus("cannot start instance for machine after a retry %q: %v", machine, err)
-			}
-		} else {
-			// Set the state to error, so the machine will be skipped next
-			// time until the error is resolved, but don't return an
-			// error; just keep going with the other machines.
-			return task.setErrorStatus("cannot start instance for machine %q: %v", machine, err)
-		}
+		return err
+	}
+	if result == nil {
+		// The machine's error status has been set.
+		return nil
 	}
 
 	inst := result.Instance
@@ -615,6 +809,135 @@ func (task *provisionerTask) startMachine(
 	return nil
 }
 
+// startInstance asks the broker to start an instance for the given
+// machine. If the broker supports availability zones and the machine
+// was not placed explicitly, a failure due to a lack of capacity in
+// the zones tried is retried straight away in another zone. Other
+// transient failures, and capacity failures once no zone is left to
+// try, are retried later according to the task's retry strategy. If
+// the instance is not started, the machine's error status is set,
+// recording every attempt made, and a nil result is returned.
+func (task *provisionerTask) startInstance(
+	machine *apiprovisioner.Machine,
+	provisioningInfo *params.ProvisioningInfo,
+	startInstanceParams environs.StartInstanceParams,
+) (*environs.StartInstanceResult, error) {
+	zonedEnviron, _ := task.broker.(common.ZonedEnviron)
+	triedZones := set.NewStrings()
+	for {
+		result, err := task.broker.StartInstance(startInstanceParams)
+		if err == nil {
+			task.forgetStartFailures(machine.Id())
+			return result, nil
+		}
+		cause := errors.Cause(err)
+		zoneErr, zoneConstrained := cause.(*instance.ZoneConstrainedError)
+		transient := zoneConstrained || instance.IsRetryableCreationError(cause)
+		attempt := map[string]interface{}{
+			"error":     err.Error(),
+			"transient": transient,
+		}
+		if startInstanceParams.Placement != "" {
+			attempt["placement"] = startInstanceParams.Placement
+		}
+		if zoneConstrained {
+			attempt["zones"] = zoneErr.Zones
+		}
+
+		attempts := task.recordStartAttempt(machine, attempt)
+
+		if zoneConstrained && zonedEnviron != nil && provisioningInfo.Placement == "" {
+			triedZones = triedZones.Union(set.NewStrings(zoneErr.Zones...))
+			zone, err := nextAvailabilityZone(zonedEnviron, &startInstanceParams, triedZones)
+			if err != nil {
+				logger.Warningf("cannot determine next availability zone for machine %q: %v", machine, err)
+			} else if zone != "" {
+				logger.Infof("availability zones %v are constrained, trying %q for machine %q", zoneErr.Zones, zone, machine)
+				startInstanceParams.Placement = "zone=" + zone
+				continue
+			}
+		}
+
+		retrying := false
+		if transient {
+			var delay time.Duration
+			if delay, retrying = task.scheduleRetry(machine.Id()); retrying {
+				logger.Infof("transient error starting instance for machine %q, retrying in %v: %v", machine, delay, err)
+			}
+		}
+		if !retrying {
+			task.forgetStartFailures(machine.Id())
+		}
+		// Set the state to error, so the machine will be skipped next
+		// time until it is retried or the error is resolved, but don't
+		// return an error; just keep going with the other machines.
+		data := map[string]interface{}{
+			"attempts":  attempts,
+			"transient": retrying,
+		}
+		return nil, task.setErrorStatusWithData("cannot start instance for machine %q: %v", machine, err, data)
+	}
+}
+
+// recordStartAttempt records a failed attempt to start an instance
+// for the machine, and returns every attempt made since the machine
+// was last started or given up on.
+func (task *provisionerTask) recordStartAttempt(machine *apiprovisioner.Machine, attempt map[string]interface{}) []interface{} {
+	task.mu.Lock()
+	defer task.mu.Unlock()
+	failures, ok := task.startFailures[machine.Id()]
+	if !ok {
+		failures = &startFailures{machine: machine}
+		task.startFailures[machine.Id()] = failures
+	}
+	failures.attempts = append(failures.attempts, attempt)
+	return append([]interface{}(nil), failures.attempts...)
+}
+
+// scheduleRetry schedules a retry of starting an instance for the
+// machine with the given id, if the task's retry strategy allows
+// one, and returns the delay before the retry. The delay doubles
+// with each retry of the same machine.
+func (task *provisionerTask) scheduleRetry(id string) (time.Duration, bool) {
+	task.mu.Lock()
+	defer task.mu.Unlock()
+	failures, ok := task.startFailures[id]
+	if !ok || failures.retries >= task.retryStrategy.Count {
+		return 0, false
+	}
+	delay := task.retryStrategy.Delay << uint(failures.retries)
+	failures.retries++
+	failures.retryAt = time.Now().Add(delay)
+	return delay, true
+}
+
+// nextAvailabilityZone returns the least populated availability zone
+// for the instance distribution group that is not in exclude, or ""
+// if there is no such zone.
+func nextAvailabilityZone(
+	env common.ZonedEnviron,
+	startInstanceParams *environs.StartInstanceParams,
+	exclude set.Strings,
+) (string, error) {
+	var group []instance.Id
+	if startInstanceParams.DistributionGroup != nil {
+		var err error
+		if group, err = startInstanceParams.DistributionGroup(); err != nil {
+			return "", err
+		}
+	}
+	zoneInstances, err := common.AvailabilityZoneAllocations(env, group)
+	if err != nil {
+		return "", err
+	}
+	for _, zone := range zoneInstances {
+		if !exclude.Contains(zone.ZoneName) {
+			return zone.ZoneName, nil
+		}
+	}
+	return "", nil
+}
+
 type provisioningInfo struct {
 	Constraints   constraints.Value
 	Series        string
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/juju/errors"
@@ -27,12 +28,14 @@ import (
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/environs/filestorage"
 	"github.com/juju/juju/environs/imagemetadata"
+	"github.com/juju/juju/environs/tags"
 	envtesting "github.com/juju/juju/environs/testing"
 	"github.com/juju/juju/environs/tools"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/mongo"
 	"github.com/juju/juju/network"
+	"github.com/juju/juju/provider/common"
 	"github.com/juju/juju/provider/dummy"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/multiwatcher"
@@ -84,6 +87,12 @@ func (s *CommonProvisionerSuite) SetUpTest(c *gc.C) {
 	dummy.Listen(op)
 	s.op = op
 
+	// Retry starting instances without delay.
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{
+		"provisioner-retry-delay": 0,
+	}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
 	cfg, err := s.State.EnvironConfig()
 	c.Assert(err, jc.ErrorIsNil)
 	s.cfg = cfg
@@ -220,6 +229,8 @@ func (s *CommonProvisionerSuite) checkStartInstanceCustom(
 					jobs = append(jobs, job.ToParams())
 				}
 				c.Assert(o.Jobs, jc.SameContents, jobs)
+				c.Assert(o.InstanceTags[tags.JujuEnv], gc.Equals, s.State.EnvironUUID())
+				c.Assert(o.InstanceTags[tags.JujuMachine], gc.Equals, m.Id())
 
 				if checkPossibleTools != nil {
 					for _, t := range o.PossibleTools {
@@ -649,6 +660,41 @@ func (s *ProvisionerSuite) TestProvisionerSucceedStartInstanceWithInjectedWrappe
 	s.checkStartInstanceNoSecureConnection(c, m)
 }
 
+func (s *ProvisionerSuite) TestProvisionerRecordsStartInstanceAttempts(c *gc.C) {
+	// Fail every attempt to start the instance.
+	attempts := config.DefaultProvisionerRetryCount + 1
+	errorInjectionChannel := make(chan error, attempts)
+	for i := 1; i <= attempts; i++ {
+		errorInjectionChannel <- instance.NewRetryableCreationError(fmt.Sprintf("attempt %d failed", i))
+	}
+
+	p := s.newEnvironProvisioner(c)
+	defer stop(c, p)
+
+	cleanup := dummy.PatchTransientErrorInjectionChannel(errorInjectionChannel)
+	defer cleanup()
+
+	m, err := s.addMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkNoOperations(c)
+
+	t0 := time.Now()
+	for time.Since(t0) < coretesting.LongWait {
+		status, info, data, err := m.Status()
+		c.Assert(err, jc.ErrorIsNil)
+		if status == state.StatusPending || data["transient"] == true {
+			// The instance is still being started, or will be retried.
+			time.Sleep(coretesting.ShortWait)
+			continue
+		}
+		c.Assert(status, gc.Equals, state.StatusError)
+		c.Assert(info, gc.Equals, fmt.Sprintf("attempt %d failed", attempts))
+		c.Assert(data["attempts"], gc.HasLen, attempts)
+		break
+	}
+	c.Assert(errorInjectionChannel, gc.HasLen, 0)
+}
+
 func (s *ProvisionerSuite) TestProvisionerFailStartInstanceWithInjectedNonRetryableCreationError(c *gc.C) {
 	// create the error injection channel
 	errorInjectionChannel := make(chan error, 1)
@@ -1052,6 +1098,24 @@ func (s *ProvisionerSuite) newProvisionerTask(
 	machineGetter provisioner.MachineGetter,
 	toolsFinder provisioner.ToolsFinder,
 ) provisioner.ProvisionerTask {
+	return s.newProvisionerTaskWithConcurrency(
+		c,
+		harvestingMethod,
+		broker,
+		machineGetter,
+		toolsFinder,
+		config.DefaultProvisionerConcurrency,
+	)
+}
+
+func (s *ProvisionerSuite) newProvisionerTaskWithConcurrency(
+	c *gc.C,
+	harvestingMethod config.HarvestMode,
+	broker environs.InstanceBroker,
+	machineGetter provisioner.MachineGetter,
+	toolsFinder provisioner.ToolsFinder,
+	concurrency int,
+) provisioner.ProvisionerTask {
 
 	machineWatcher, err := s.provisioner.WatchEnvironMachines()
 	c.Assert(err, jc.ErrorIsNil)
@@ -1071,6 +1135,8 @@ func (s *ProvisionerSuite) newProvisionerTask(
 		auth,
 		imagemetadata.ReleasedStream,
 		true,
+		concurrency,
+		provisioner.RetryStrategy{Count: config.DefaultProvisionerRetryCount},
 	)
 }
 
@@ -1234,11 +1300,16 @@ func (s *ProvisionerSuite) TestProvisionerObservesMachineJobs(c *gc.C) {
 
 type mockBroker struct {
 	environs.Environ
+	mu         sync.Mutex
 	retryCount map[string]int
 	ids        []string
 }
 
 func (b *mockBroker) StartInstance(args environs.StartInstanceParams) (*environs.StartInstanceResult, error) {
+	// Machines may be started concurrently; hold the lock for the
+	// whole call so that ids records the order instances started in.
+	b.mu.Lock()
+	defer b.mu.Unlock()
 	// All machines except machines 3, 4 are provisioned successfully the first time.
 	// Machines 3 is provisioned after some attempts have been made.
 	// Machine 4 is never provisioned.
@@ -1254,6 +1325,192 @@ func (b *mockBroker) StartInstance(args environs.StartInstanceParams) (*environs
 	return nil, fmt.Errorf("error: some error")
 }
 
+func (s *ProvisionerSuite) TestProvisionerStartsMachinesConcurrently(c *gc.C) {
+	broker := &blockingBroker{
+		Environ: s.Environ,
+		started: make(chan string),
+		release: make(chan struct{}),
+	}
+	expectIds := set.NewStrings()
+	for i := 0; i < 3; i++ {
+		m, err := s.addMachine()
+		c.Assert(err, jc.ErrorIsNil)
+		expectIds.Add(m.Id())
+	}
+	task := s.newProvisionerTaskWithConcurrency(c, config.HarvestDestroyed, broker, s.provisioner, mockToolsFinder{}, 2)
+	defer stop(c, task)
+	s.BackingState.StartSync()
+
+	// Two machines are started at the same time...
+	startedIds := set.NewStrings()
+	for i := 0; i < 2; i++ {
+		select {
+		case id := <-broker.started:
+			startedIds.Add(id)
+		case <-time.After(coretesting.LongWait):
+			c.Fatalf("timed out waiting for machines to start concurrently")
+		}
+	}
+	// ...but not the third, until one of the others has finished.
+	select {
+	case id := <-broker.started:
+		c.Fatalf("machine %s started beyond the concurrency limit", id)
+	case <-time.After(coretesting.ShortWait):
+	}
+	close(broker.release)
+	select {
+	case id := <-broker.started:
+		startedIds.Add(id)
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for the last machine to start")
+	}
+	c.Assert(startedIds.SortedValues(), jc.DeepEquals, expectIds.SortedValues())
+
+	for i := 0; i < 3; i++ {
+		select {
+		case o := <-s.op:
+			start, ok := o.(dummy.OpStartInstance)
+			c.Assert(ok, jc.IsTrue, gc.Commentf("unexpected operation %#v", o))
+			expectIds.Remove(start.MachineId)
+		case <-time.After(coretesting.LongWait):
+			c.Fatalf("provisioner did not start all instances")
+		}
+	}
+	c.Assert(expectIds.IsEmpty(), jc.IsTrue)
+}
+
+func (s *ProvisionerSuite) TestProvisionerRetriesZoneConstrainedStartInstance(c *gc.C) {
+	broker := &constrainedBroker{Environ: s.Environ}
+	task := s.newProvisionerTask(c, config.HarvestDestroyed, broker, s.provisioner, mockToolsFinder{})
+	defer stop(c, task)
+
+	m, err := s.addMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkStartInstance(c, m)
+	c.Assert(broker.startCount(), gc.Equals, 2)
+}
+
+func (s *ProvisionerSuite) TestProvisionerRetryDoesNotBlockOtherMachines(c *gc.C) {
+	broker := &constrainedBroker{Environ: s.Environ}
+	task := s.newProvisionerTask(c, config.HarvestDestroyed, broker, s.provisioner, mockToolsFinder{})
+	defer stop(c, task)
+	task.SetRetryStrategy(provisioner.RetryStrategy{Count: 1, Delay: time.Hour})
+
+	// The first machine fails to start, and is retried much later...
+	m1, err := s.addMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkNoOperations(c)
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		status, _, data, err := m1.Status()
+		c.Assert(err, jc.ErrorIsNil)
+		if status == state.StatusPending && a.HasNext() {
+			continue
+		}
+		c.Assert(status, gc.Equals, state.StatusError)
+		c.Assert(data["transient"], jc.IsTrue)
+		break
+	}
+
+	// ...but that does not hold up starting other machines.
+	m2, err := s.addMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkStartInstance(c, m2)
+	c.Assert(broker.startCount(), gc.Equals, 2)
+}
+
+func (s *ProvisionerSuite) TestProvisionerFailsOverToAnotherAvailabilityZone(c *gc.C) {
+	broker := &zonedBroker{Environ: s.Environ}
+	task := s.newProvisionerTask(c, config.HarvestDestroyed, broker, s.provisioner, mockToolsFinder{})
+	defer stop(c, task)
+
+	m, err := s.addMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkStartInstance(c, m)
+	c.Assert(broker.startPlacements(), jc.DeepEquals, []string{"", "zone=az2"})
+}
+
+// zonedBroker is a broker with two availability zones, az1 and az2,
+// which reports that az1 lacks the capacity to start instances.
+type zonedBroker struct {
+	environs.Environ
+	mu         sync.Mutex
+	placements []string
+}
+
+func (b *zonedBroker) AvailabilityZones() ([]common.AvailabilityZone, error) {
+	return []common.AvailabilityZone{mockZone("az1"), mockZone("az2")}, nil
+}
+
+func (b *zonedBroker) InstanceAvailabilityZoneNames(ids []instance.Id) ([]string, error) {
+	return make([]string, len(ids)), nil
+}
+
+func (b *zonedBroker) StartInstance(args environs.StartInstanceParams) (*environs.StartInstanceResult, error) {
+	b.mu.Lock()
+	b.placements = append(b.placements, args.Placement)
+	b.mu.Unlock()
+	if args.Placement != "zone=az2" {
+		return nil, instance.NewZoneConstrainedError([]string{"az1"}, "az1 is out of capacity")
+	}
+	return b.Environ.StartInstance(args)
+}
+
+func (b *zonedBroker) startPlacements() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]string(nil), b.placements...)
+}
+
+type mockZone string
+
+func (z mockZone) Name() string {
+	return string(z)
+}
+
+func (z mockZone) Available() bool {
+	return true
+}
+
+// constrainedBroker is a broker which reports that every availability
+// zone lacks the capacity to start an instance the first time it is
+// asked to start one.
+type constrainedBroker struct {
+	environs.Environ
+	mu     sync.Mutex
+	starts int
+}
+
+func (b *constrainedBroker) StartInstance(args environs.StartInstanceParams) (*environs.StartInstanceResult, error) {
+	b.mu.Lock()
+	b.starts++
+	starts := b.starts
+	b.mu.Unlock()
+	if starts == 1 {
+		return nil, instance.NewZoneConstrainedError([]string{"az1", "az2"}, "out of capacity")
+	}
+	return b.Environ.StartInstance(args)
+}
+
+func (b *constrainedBroker) startCount() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.starts
+}
+
+// blockingBroker reports each machine it is asked to start, and
+// does not start any of them until release is closed.
+type blockingBroker struct {
+	environs.Environ
+	started chan string
+	release chan struct{}
+}
+
+func (b *blockingBroker) StartInstance(args environs.StartInstanceParams) (*environs.StartInstanceResult, error) {
+	b.started <- args.MachineConfig.MachineId
+	<-b.release
+	return b.Environ.StartInstance(args)
+}
+
 type mockToolsFinder struct {
 }
 
@@ -5,6 +5,7 @@ package filter
 
 import (
 	"sort"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
@@ -16,6 +17,7 @@ import (
 	"github.com/juju/juju/api/uniter"
 	apiwatcher "github.com/juju/juju/api/watcher"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/leadership"
 	"github.com/juju/juju/state/watcher"
 	"github.com/juju/juju/worker"
 	"github.com/juju/juju/worker/uniter/hook"
@@ -23,12 +25,18 @@ import (
 
 var filterLogger = loggo.GetLogger("juju.worker.uniter.filter")
 
+// leadershipRetryDelay is how long the filter waits before claiming
+// leadership again when it cannot wait for the current leader's claim
+// to be released.
+var leadershipRetryDelay = 30 * time.Second
+
 // filter collects unit, service, and service config information from separate
 // state watchers, and presents it as events on channels designed specifically
 // for the convenience of the uniter.
 type filter struct {
-	st   *uniter.State
-	tomb tomb.Tomb
+	st                *uniter.State
+	leadershipManager leadership.LeadershipManager
+	tomb              tomb.Tomb
 
 	// outUnitDying is closed when the unit's life becomes Dying.
 	outUnitDying chan struct{}
@@ -37,18 +45,22 @@ type filter struct {
 	// The out* chans, when set to the corresponding out*On chan (rather than
 	// nil) indicate that an event of the appropriate type is ready to send
 	// to the client.
-	outConfig        chan struct{}
-	outConfigOn      chan struct{}
-	outAction        chan *hook.Info
-	outActionOn      chan *hook.Info
-	outUpgrade       chan *charm.URL
-	outUpgradeOn     chan *charm.URL
-	outResolved      chan params.ResolvedMode
-	outResolvedOn    chan params.ResolvedMode
-	outRelations     chan []int
-	outRelationsOn   chan []int
-	outMeterStatus   chan struct{}
-	outMeterStatusOn chan struct{}
+	outConfig           chan struct{}
+	outConfigOn         chan struct{}
+	outAction           chan *hook.Info
+	outActionOn         chan *hook.Info
+	outUpgrade          chan *charm.URL
+	outUpgradeOn        chan *charm.URL
+	outResolved         chan params.ResolvedMode
+	outResolvedOn       chan params.ResolvedMode
+	outRelations        chan []int
+	outRelationsOn      chan []int
+	outMeterStatus      chan struct{}
+	outMeterStatusOn    chan struct{}
+	outLeaderElected    chan struct{}
+	outLeaderElectedOn  chan struct{}
+	outLeaderSettings   chan struct{}
+	outLeaderSettingsOn chan struct{}
 	// The want* chans are used to indicate that the filter should send
 	// events if it has them available.
 	wantForcedUpgrade chan bool
@@ -98,33 +110,42 @@ type filter struct {
 	// meterStatusCode and meterStatusInfo reflect the meter status values of the unit.
 	meterStatusCode string
 	meterStatusInfo string
+
+	// isLeader holds whether the unit's last leadership claim succeeded.
+	isLeader bool
 }
 
 // NewFilter returns a filter that handles state changes pertaining to the
-// supplied unit.
-func NewFilter(st *uniter.State, unitTag names.UnitTag) (Filter, error) {
+// supplied unit. The filter holds and renews the unit's claim to the
+// leadership of its service, using the supplied leadership manager.
+func NewFilter(st *uniter.State, unitTag names.UnitTag, leadershipManager leadership.LeadershipManager) (Filter, error) {
 	f := &filter{
-		st:                st,
-		outUnitDying:      make(chan struct{}),
-		outConfig:         make(chan struct{}),
-		outConfigOn:       make(chan struct{}),
-		outAction:         make(chan *hook.Info),
-		outActionOn:       make(chan *hook.Info),
-		outUpgrade:        make(chan *charm.URL),
-		outUpgradeOn:      make(chan *charm.URL),
-		outResolved:       make(chan params.ResolvedMode),
-		outResolvedOn:     make(chan params.ResolvedMode),
-		outRelations:      make(chan []int),
-		outRelationsOn:    make(chan []int),
-		outMeterStatus:    make(chan struct{}),
-		outMeterStatusOn:  make(chan struct{}),
-		wantForcedUpgrade: make(chan bool),
-		wantResolved:      make(chan struct{}),
-		discardConfig:     make(chan struct{}),
-		setCharm:          make(chan *charm.URL),
-		didSetCharm:       make(chan struct{}),
-		clearResolved:     make(chan struct{}),
-		didClearResolved:  make(chan struct{}),
+		st:                  st,
+		leadershipManager:   leadershipManager,
+		outUnitDying:        make(chan struct{}),
+		outConfig:           make(chan struct{}),
+		outConfigOn:         make(chan struct{}),
+		outAction:           make(chan *hook.Info),
+		outActionOn:         make(chan *hook.Info),
+		outUpgrade:          make(chan *charm.URL),
+		outUpgradeOn:        make(chan *charm.URL),
+		outResolved:         make(chan params.ResolvedMode),
+		outResolvedOn:       make(chan params.ResolvedMode),
+		outRelations:        make(chan []int),
+		outRelationsOn:      make(chan []int),
+		outMeterStatus:      make(chan struct{}),
+		outMeterStatusOn:    make(chan struct{}),
+		outLeaderElected:    make(chan struct{}),
+		outLeaderElectedOn:  make(chan struct{}),
+		outLeaderSettings:   make(chan struct{}),
+		outLeaderSettingsOn: make(chan struct{}),
+		wantForcedUpgrade:   make(chan bool),
+		wantResolved:        make(chan struct{}),
+		discardConfig:       make(chan struct{}),
+		setCharm:            make(chan *charm.URL),
+		didSetCharm:         make(chan struct{}),
+		clearResolved:       make(chan struct{}),
+		didClearResolved:    make(chan struct{}),
 	}
 	go func() {
 		defer f.tomb.Done()
@@ -174,6 +195,18 @@ func (f *filter) MeterStatusEvents() <-chan struct{} {
 	return f.outMeterStatusOn
 }
 
+// LeaderElectedEvents returns a channel that will receive a signal whenever
+// the unit becomes the leader of its service.
+func (f *filter) LeaderElectedEvents() <-chan struct{} {
+	return f.outLeaderElectedOn
+}
+
+// LeaderSettingsEvents returns a channel that will receive a signal whenever
+// the service's leader settings change while the unit is not the leader.
+func (f *filter) LeaderSettingsEvents() <-chan struct{} {
+	return f.outLeaderSettingsOn
+}
+
 // ConfigEvents returns a channel that will receive a signal whenever the service's
 // configuration changes, or when an event is explicitly requested.
 func (f *filter) ConfigEvents() <-chan struct{} {
@@ -293,6 +326,11 @@ func (f *filter) loop(unitTag names.UnitTag) (err error) {
 	if err = f.serviceChanged(); err != nil {
 		return err
 	}
+	leadershipReleased := make(chan struct{})
+	leadershipClaim, err := f.claimLeadership(leadershipReleased)
+	if err != nil {
+		return err
+	}
 	unitw, err := f.unit.Watch()
 	if err != nil {
 		return err
@@ -341,6 +379,18 @@ func (f *filter) loop(unitTag names.UnitTag) (err error) {
 		return err
 	}
 	defer watcher.Stop(addressesw, &f.tomb)
+	// Leader settings can only be watched through version 2 of the
+	// uniter facade and later.
+	var leaderSettingsw apiwatcher.NotifyWatcher
+	var leaderSettingsChanges <-chan struct{}
+	if f.st.LeadershipSettings != nil {
+		leaderSettingsw, err = f.st.LeadershipSettings.WatchLeadershipSettings(f.service.Name())
+		if err != nil {
+			return err
+		}
+		leaderSettingsChanges = leaderSettingsw.Changes()
+	}
+	defer f.maybeStopWatcher(leaderSettingsw)
 
 	// Config events cannot be meaningfully discarded until one is available;
 	// once we receive the initial config and address changes, we unblock
@@ -433,6 +483,26 @@ func (f *filter) loop(unitTag names.UnitTag) (err error) {
 				}
 			}
 			f.relationsChanged(ids)
+		case _, ok = <-leaderSettingsChanges:
+			filterLogger.Debugf("got leader settings change")
+			if !ok {
+				return watcher.EnsureErr(leaderSettingsw)
+			}
+			if !f.isLeader {
+				f.outLeaderSettings = f.outLeaderSettingsOn
+			}
+
+		// Renew or retry the leadership claim.
+		case <-leadershipClaim:
+			filterLogger.Debugf("renewing leadership claim")
+			if leadershipClaim, err = f.claimLeadership(leadershipReleased); err != nil {
+				return err
+			}
+		case <-leadershipReleased:
+			filterLogger.Debugf("leadership released, claiming leadership")
+			if leadershipClaim, err = f.claimLeadership(leadershipReleased); err != nil {
+				return err
+			}
 
 		// Send events on active out chans.
 		case f.outUpgrade <- f.upgrade:
@@ -454,6 +524,12 @@ func (f *filter) loop(unitTag names.UnitTag) (err error) {
 		case f.outMeterStatus <- nothing:
 			filterLogger.Debugf("sent meter status change event")
 			f.outMeterStatus = nil
+		case f.outLeaderElected <- nothing:
+			filterLogger.Debugf("sent leader elected event")
+			f.outLeaderElected = nil
+		case f.outLeaderSettings <- nothing:
+			filterLogger.Debugf("sent leader settings changed event")
+			f.outLeaderSettings = nil
 
 		// Handle explicit requests.
 		case curl := <-f.setCharm:
@@ -542,6 +618,62 @@ func (f *filter) meterStatusChanged() error {
 	return nil
 }
 
+// claimLeadership claims the leadership of the unit's service, and
+// returns a channel that will signal when the claim should be renewed.
+// If the claim is denied, the returned channel is nil, and released
+// will be signalled when the current leader's claim is released.
+func (f *filter) claimLeadership(released chan<- struct{}) (<-chan time.Time, error) {
+	serviceName := f.service.Name()
+	duration, err := f.leadershipManager.ClaimLeadership(serviceName, f.unit.Name())
+	if errors.Cause(err) == leadership.LeadershipClaimDeniedErr {
+		if f.isLeader {
+			filterLogger.Infof("unit is no longer leader of service %q", serviceName)
+		}
+		f.isLeader = false
+		f.outLeaderElected = nil
+		go f.waitLeadershipReleased(serviceName, released)
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "cannot claim leadership of service %q", serviceName)
+	}
+	if !f.isLeader {
+		filterLogger.Infof("unit is now leader of service %q", serviceName)
+		f.isLeader = true
+		f.outLeaderElected = f.outLeaderElectedOn
+		f.outLeaderSettings = nil
+	}
+	return time.After(duration / 2), nil
+}
+
+// waitLeadershipReleased signals released when the leadership of the
+// named service is released, or when leadershipRetryDelay has passed
+// if that cannot be determined. It gives up when the filter is stopped.
+func (f *filter) waitLeadershipReleased(serviceName string, released chan<- struct{}) {
+	// BlockUntilLeadershipReleased cannot be interrupted; it returns
+	// when the API connection is closed, and nothing waits for it then.
+	blocked := make(chan error, 1)
+	go func() {
+		blocked <- f.leadershipManager.BlockUntilLeadershipReleased(serviceName)
+	}()
+	select {
+	case <-f.tomb.Dying():
+		return
+	case err := <-blocked:
+		if err != nil {
+			filterLogger.Warningf("cannot wait for leadership release: %v", err)
+			select {
+			case <-f.tomb.Dying():
+				return
+			case <-time.After(leadershipRetryDelay):
+			}
+		}
+	}
+	select {
+	case <-f.tomb.Dying():
+	case released <- nothing:
+	}
+}
+
 // unitChanged responds to changes in the unit.
 func (f *filter) unitChanged() error {
 	if err := f.unit.Refresh(); err != nil {
@@ -576,7 +708,9 @@ func (f *filter) serviceChanged() error {
 	if err := f.service.Refresh(); err != nil {
 		return err
 	}
-	url, force, err := f.service.CharmURL()
+	// A rolling charm upgrade may keep the unit on an older charm
+	// than the service's for a while, so ask what this unit should run.
+	url, force, err := f.unit.UpgradeCharmURL()
 	if err != nil {
 		return err
 	}
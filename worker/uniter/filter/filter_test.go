@@ -5,6 +5,7 @@ package filter_test
 
 import (
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/juju/names"
@@ -34,14 +35,16 @@ type FilterSuite struct {
 	wpcharm    *state.Charm
 	machine    *state.Machine
 
-	st     *api.State
-	uniter *apiuniter.State
+	st         *api.State
+	uniter     *apiuniter.State
+	leadership *fakeLeadership
 }
 
 var _ = gc.Suite(&FilterSuite{})
 
 func (s *FilterSuite) SetUpTest(c *gc.C) {
 	s.JujuConnSuite.SetUpTest(c)
+	s.leadership = &fakeLeadership{released: make(chan struct{})}
 	s.wpcharm = s.AddTestingCharm(c, "wordpress")
 	s.wordpress = s.AddTestingService(c, "wordpress", s.wpcharm)
 	var err error
@@ -58,6 +61,11 @@ func (s *FilterSuite) SetUpTest(c *gc.C) {
 	s.APILogin(c, s.unit)
 }
 
+func (s *FilterSuite) TearDownTest(c *gc.C) {
+	close(s.leadership.released)
+	s.JujuConnSuite.TearDownTest(c)
+}
+
 func (s *FilterSuite) APILogin(c *gc.C, unit *state.Unit) {
 	password, err := utils.RandomPassword()
 	c.Assert(err, jc.ErrorIsNil)
@@ -70,7 +78,7 @@ func (s *FilterSuite) APILogin(c *gc.C, unit *state.Unit) {
 }
 
 func (s *FilterSuite) TestUnitDeath(c *gc.C) {
-	f, err := filter.NewFilter(s.uniter, s.unit.Tag().(names.UnitTag))
+	f, err := filter.NewFilter(s.uniter, s.unit.Tag().(names.UnitTag), s.leadership)
 	c.Assert(err, jc.ErrorIsNil)
 	defer f.Stop() // no AssertStop, we test for an error below
 	asserter := coretesting.NotifyAsserterC{
@@ -104,7 +112,7 @@ func (s *FilterSuite) TestUnitDeath(c *gc.C) {
 }
 
 func (s *FilterSuite) TestUnitRemoval(c *gc.C) {
-	f, err := filter.NewFilter(s.uniter, s.unit.Tag().(names.UnitTag))
+	f, err := filter.NewFilter(s.uniter, s.unit.Tag().(names.UnitTag), s.leadership)
 	c.Assert(err, jc.ErrorIsNil)
 	defer f.Stop() // no AssertStop, we test for an error below
 
@@ -130,7 +138,7 @@ func (s *FilterSuite) assertAgentTerminates(c *gc.C, f filter.Filter) {
 }
 
 func (s *FilterSuite) TestServiceDeath(c *gc.C) {
-	f, err := filter.NewFilter(s.uniter, s.unit.Tag().(names.UnitTag))
+	f, err := filter.NewFilter(s.uniter, s.unit.Tag().(names.UnitTag), s.leadership)
 	c.Assert(err, jc.ErrorIsNil)
 	defer statetesting.AssertStop(c, f)
 	dyingAsserter := coretesting.NotifyAsserterC{
@@ -165,7 +173,7 @@ loop:
 }
 
 func (s *FilterSuite) TestResolvedEvents(c *gc.C) {
-	f, err := filter.NewFilter(s.uniter, s.unit.Tag().(names.UnitTag))
+	f, err := filter.NewFilter(s.uniter, s.unit.Tag().(names.UnitTag), s.leadership)
 	c.Assert(err, jc.ErrorIsNil)
 	defer statetesting.AssertStop(c, f)
 
@@ -227,7 +235,7 @@ func (s *FilterSuite) TestCharmUpgradeEvents(c *gc.C) {
 
 	s.APILogin(c, unit)
 
-	f, err := filter.NewFilter(s.uniter, unit.Tag().(names.UnitTag))
+	f, err := filter.NewFilter(s.uniter, unit.Tag().(names.UnitTag), s.leadership)
 	c.Assert(err, jc.ErrorIsNil)
 	defer statetesting.AssertStop(c, f)
 
@@ -292,8 +300,56 @@ func (s *FilterSuite) TestCharmUpgradeEvents(c *gc.C) {
 	assertNoChange()
 }
 
+func (s *FilterSuite) TestCharmUpgradeEventsRollout(c *gc.C) {
+	oldCharm := s.AddTestingCharm(c, "upgrade1")
+	svc := s.AddTestingService(c, "upgradetest", oldCharm)
+	var units []*state.Unit
+	for i := 0; i < 2; i++ {
+		unit, err := svc.AddUnit()
+		c.Assert(err, jc.ErrorIsNil)
+		err = unit.AssignToNewMachine()
+		c.Assert(err, jc.ErrorIsNil)
+		err = unit.SetCharmURL(oldCharm.URL())
+		c.Assert(err, jc.ErrorIsNil)
+		units = append(units, unit)
+	}
+
+	// Watch the second unit, which is not in the first batch.
+	s.APILogin(c, units[1])
+	f, err := filter.NewFilter(s.uniter, units[1].Tag().(names.UnitTag), s.leadership)
+	c.Assert(err, jc.ErrorIsNil)
+	defer statetesting.AssertStop(c, f)
+	err = f.SetCharm(oldCharm.URL())
+	c.Assert(err, jc.ErrorIsNil)
+	f.WantUpgradeEvent(false)
+
+	newCharm := s.AddTestingCharm(c, "upgrade2")
+	err = svc.SetCharmRollout(newCharm, false, 1, false)
+	c.Assert(err, jc.ErrorIsNil)
+	s.BackingState.StartSync()
+	select {
+	case sch := <-f.UpgradeEvents():
+		c.Fatalf("unexpected %#v", sch)
+	case <-time.After(coretesting.ShortWait):
+	}
+
+	// Once the first batch has upgraded, the rollout moves on to the
+	// watched unit.
+	err = units[0].SetCharmURL(newCharm.URL())
+	c.Assert(err, jc.ErrorIsNil)
+	err = svc.AdvanceRollout()
+	c.Assert(err, jc.ErrorIsNil)
+	s.BackingState.StartSync()
+	select {
+	case upgradeCharm := <-f.UpgradeEvents():
+		c.Assert(upgradeCharm, gc.DeepEquals, newCharm.URL())
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out")
+	}
+}
+
 func (s *FilterSuite) TestConfigEvents(c *gc.C) {
-	f, err := filter.NewFilter(s.uniter, s.unit.Tag().(names.UnitTag))
+	f, err := filter.NewFilter(s.uniter, s.unit.Tag().(names.UnitTag), s.leadership)
 	c.Assert(err, jc.ErrorIsNil)
 	defer statetesting.AssertStop(c, f)
 
@@ -357,7 +413,7 @@ func (s *FilterSuite) TestConfigEvents(c *gc.C) {
 
 	// Check that a filter's initial event works with DiscardConfigEvent
 	// as expected.
-	f, err = filter.NewFilter(s.uniter, s.unit.Tag().(names.UnitTag))
+	f, err = filter.NewFilter(s.uniter, s.unit.Tag().(names.UnitTag), s.leadership)
 	c.Assert(err, jc.ErrorIsNil)
 	defer statetesting.AssertStop(c, f)
 	s.BackingState.StartSync()
@@ -371,7 +427,7 @@ func (s *FilterSuite) TestConfigEvents(c *gc.C) {
 }
 
 func (s *FilterSuite) TestInitialAddressEventIgnored(c *gc.C) {
-	f, err := filter.NewFilter(s.uniter, s.unit.Tag().(names.UnitTag))
+	f, err := filter.NewFilter(s.uniter, s.unit.Tag().(names.UnitTag), s.leadership)
 	c.Assert(err, jc.ErrorIsNil)
 	defer statetesting.AssertStop(c, f)
 
@@ -410,7 +466,7 @@ func (s *FilterSuite) TestInitialAddressEventIgnored(c *gc.C) {
 }
 
 func (s *FilterSuite) TestConfigAndAddressEvents(c *gc.C) {
-	f, err := filter.NewFilter(s.uniter, s.unit.Tag().(names.UnitTag))
+	f, err := filter.NewFilter(s.uniter, s.unit.Tag().(names.UnitTag), s.leadership)
 	c.Assert(err, jc.ErrorIsNil)
 	defer statetesting.AssertStop(c, f)
 
@@ -448,7 +504,7 @@ func (s *FilterSuite) TestConfigAndAddressEvents(c *gc.C) {
 }
 
 func (s *FilterSuite) TestConfigAndAddressEventsDiscarded(c *gc.C) {
-	f, err := filter.NewFilter(s.uniter, s.unit.Tag().(names.UnitTag))
+	f, err := filter.NewFilter(s.uniter, s.unit.Tag().(names.UnitTag), s.leadership)
 	c.Assert(err, jc.ErrorIsNil)
 	defer statetesting.AssertStop(c, f)
 
@@ -516,7 +572,7 @@ func getAddAction(s *FilterSuite, c *gc.C) func(name string) string {
 }
 
 func (s *FilterSuite) TestActionEvents(c *gc.C) {
-	f, err := filter.NewFilter(s.uniter, s.unit.Tag().(names.UnitTag))
+	f, err := filter.NewFilter(s.uniter, s.unit.Tag().(names.UnitTag), s.leadership)
 	c.Assert(err, jc.ErrorIsNil)
 	defer statetesting.AssertStop(c, f)
 
@@ -550,7 +606,7 @@ func (s *FilterSuite) TestPreexistingActions(c *gc.C) {
 	testId := addAction("snapshot")
 
 	// Now create the Filter and see whether the Action comes in as expected.
-	f, err := filter.NewFilter(s.uniter, s.unit.Tag().(names.UnitTag))
+	f, err := filter.NewFilter(s.uniter, s.unit.Tag().(names.UnitTag), s.leadership)
 	c.Assert(err, jc.ErrorIsNil)
 	defer statetesting.AssertStop(c, f)
 
@@ -564,7 +620,7 @@ func (s *FilterSuite) TestPreexistingActions(c *gc.C) {
 }
 
 func (s *FilterSuite) TestCharmErrorEvents(c *gc.C) {
-	f, err := filter.NewFilter(s.uniter, s.unit.Tag().(names.UnitTag))
+	f, err := filter.NewFilter(s.uniter, s.unit.Tag().(names.UnitTag), s.leadership)
 	c.Assert(err, jc.ErrorIsNil)
 	defer f.Stop() // no AssertStop, we test for an error below
 
@@ -584,7 +640,7 @@ func (s *FilterSuite) TestCharmErrorEvents(c *gc.C) {
 	s.assertFilterDies(c, f)
 
 	// Filter died after the error, so restart it.
-	f, err = filter.NewFilter(s.uniter, s.unit.Tag().(names.UnitTag))
+	f, err = filter.NewFilter(s.uniter, s.unit.Tag().(names.UnitTag), s.leadership)
 	c.Assert(err, jc.ErrorIsNil)
 	defer f.Stop() // no AssertStop, we test for an error below
 
@@ -596,7 +652,7 @@ func (s *FilterSuite) TestCharmErrorEvents(c *gc.C) {
 }
 
 func (s *FilterSuite) TestRelationsEvents(c *gc.C) {
-	f, err := filter.NewFilter(s.uniter, s.unit.Tag().(names.UnitTag))
+	f, err := filter.NewFilter(s.uniter, s.unit.Tag().(names.UnitTag), s.leadership)
 	c.Assert(err, jc.ErrorIsNil)
 	defer statetesting.AssertStop(c, f)
 
@@ -646,7 +702,7 @@ func (s *FilterSuite) TestRelationsEvents(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 
 	// Start a new filter, check initial event.
-	f, err = filter.NewFilter(s.uniter, s.unit.Tag().(names.UnitTag))
+	f, err = filter.NewFilter(s.uniter, s.unit.Tag().(names.UnitTag), s.leadership)
 	c.Assert(err, jc.ErrorIsNil)
 	defer statetesting.AssertStop(c, f)
 	assertChange([]int{0, 2})
@@ -673,7 +729,7 @@ func (s *FilterSuite) addRelation(c *gc.C) *state.Relation {
 }
 
 func (s *FilterSuite) TestMeterStatusEvents(c *gc.C) {
-	f, err := filter.NewFilter(s.uniter, s.unit.Tag().(names.UnitTag))
+	f, err := filter.NewFilter(s.uniter, s.unit.Tag().(names.UnitTag), s.leadership)
 	c.Assert(err, jc.ErrorIsNil)
 	defer statetesting.AssertStop(c, f)
 	assertNoChange := func() {
@@ -709,3 +765,99 @@ func (s *FilterSuite) TestMeterStatusEvents(c *gc.C) {
 	}
 	assertChange()
 }
+
+func (s *FilterSuite) TestLeaderElectedEvents(c *gc.C) {
+	f, err := filter.NewFilter(s.uniter, s.unit.Tag().(names.UnitTag), s.leadership)
+	c.Assert(err, jc.ErrorIsNil)
+	defer statetesting.AssertStop(c, f)
+	electedAsserter := coretesting.NotifyAsserterC{
+		C:       c,
+		Precond: func() { s.BackingState.StartSync() },
+		Chan:    f.LeaderElectedEvents(),
+	}
+	settingsAsserter := coretesting.NotifyAsserterC{
+		C:       c,
+		Precond: func() { s.BackingState.StartSync() },
+		Chan:    f.LeaderSettingsEvents(),
+	}
+
+	// The unit is not leader, so it sees the initial leader settings.
+	electedAsserter.AssertNoReceive()
+	settingsAsserter.AssertOneReceive()
+
+	// Release the leadership to the unit; it is elected once.
+	s.leadership.elect()
+	electedAsserter.AssertOneReceive()
+
+	// The leader does not see its own leader settings changes.
+	s.setLeaderSettings(c, "foo", "bar")
+	settingsAsserter.AssertNoReceive()
+}
+
+func (s *FilterSuite) TestLeaderSettingsEvents(c *gc.C) {
+	f, err := filter.NewFilter(s.uniter, s.unit.Tag().(names.UnitTag), s.leadership)
+	c.Assert(err, jc.ErrorIsNil)
+	defer statetesting.AssertStop(c, f)
+	settingsAsserter := coretesting.NotifyAsserterC{
+		C:       c,
+		Precond: func() { s.BackingState.StartSync() },
+		Chan:    f.LeaderSettingsEvents(),
+	}
+	settingsAsserter.AssertOneReceive()
+
+	s.setLeaderSettings(c, "foo", "bar")
+	settingsAsserter.AssertOneReceive()
+
+	// Make sure bundled events arrive properly.
+	for i := 0; i < 5; i++ {
+		s.setLeaderSettings(c, "foo", fmt.Sprintf("baz%d", i))
+	}
+	settingsAsserter.AssertOneReceive()
+}
+
+func (s *FilterSuite) setLeaderSettings(c *gc.C, key, value string) {
+	settings, err := s.State.ReadLeadershipSettings(s.wordpress.Name())
+	c.Assert(err, jc.ErrorIsNil)
+	settings.Set(key, value)
+	_, err = settings.Write()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+// fakeLeadership is a leadership.LeadershipManager whose claims are
+// denied until elect is called.
+type fakeLeadership struct {
+	mu       sync.Mutex
+	leader   bool
+	released chan struct{}
+}
+
+func (l *fakeLeadership) ClaimLeadership(serviceId, unitId string) (time.Duration, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.leader {
+		return 0, leadership.LeadershipClaimDeniedErr
+	}
+	return time.Minute, nil
+}
+
+func (l *fakeLeadership) ReleaseLeadership(serviceId, unitId string) error {
+	return nil
+}
+
+func (l *fakeLeadership) BlockUntilLeadershipReleased(serviceId string) error {
+	<-l.released
+	return nil
+}
+
+// elect makes subsequent claims succeed, and notifies the waiting
+// filter that leadership has been released.
+func (l *fakeLeadership) elect() {
+	l.mu.Lock()
+	l.leader = true
+	l.mu.Unlock()
+	select {
+	case l.released <- struct{}{}:
+	case <-time.After(coretesting.LongWait):
+		panic("timed out waiting for leadership release to be noticed")
+	}
+}
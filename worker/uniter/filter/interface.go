@@ -42,6 +42,14 @@ type Filter interface {
 	// meter status changes.
 	MeterStatusEvents() <-chan struct{}
 
+	// LeaderElectedEvents returns a channel that will receive a signal whenever
+	// the unit becomes the leader of its service.
+	LeaderElectedEvents() <-chan struct{}
+
+	// LeaderSettingsEvents returns a channel that will receive a signal whenever
+	// the service's leader settings change while the unit is not the leader.
+	LeaderSettingsEvents() <-chan struct{}
+
 	// ConfigEvents returns a channel that will receive a signal whenever the service's
 	// configuration changes, or when an event is explicitly requested.
 	ConfigEvents() <-chan struct{}
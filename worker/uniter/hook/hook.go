@@ -14,6 +14,18 @@ import (
 	"gopkg.in/juju/charm.v4/hooks"
 )
 
+// The charm package does not yet know about the leadership hooks, so
+// they are defined here until it does.
+const (
+	// LeaderElected is run when the unit becomes leader of its service.
+	LeaderElected hooks.Kind = "leader-elected"
+
+	// LeaderSettingsChanged is run when the leader of the unit's service
+	// changes the service's leader settings. It is not run on the leader
+	// itself.
+	LeaderSettingsChanged hooks.Kind = "leader-settings-changed"
+)
+
 // Info holds details required to execute a hook. Not all fields are
 // relevant to all Kind values.
 type Info struct {
@@ -49,6 +61,8 @@ func (hi Info) Validate() error {
 		fallthrough
 	case hooks.Install, hooks.Start, hooks.ConfigChanged, hooks.UpgradeCharm, hooks.Stop, hooks.RelationBroken, hooks.CollectMetrics, hooks.MeterStatusChanged:
 		return nil
+	case LeaderElected, LeaderSettingsChanged:
+		return nil
 	case hooks.Action:
 		if !names.IsValidAction(hi.ActionId) {
 			return fmt.Errorf("action id %q cannot be parsed as an action tag", hi.ActionId)
@@ -55,6 +55,8 @@ var validateTests = []struct {
 	{hook.Info{Kind: hooks.RelationBroken}, ""},
 	{hook.Info{Kind: hooks.StorageAttached}, ""},
 	{hook.Info{Kind: hooks.StorageDetached}, ""},
+	{hook.Info{Kind: hook.LeaderElected}, ""},
+	{hook.Info{Kind: hook.LeaderSettingsChanged}, ""},
 }
 
 func (s *InfoSuite) TestValidate(c *gc.C) {
@@ -72,6 +72,13 @@ func ModeContinue(u *Uniter) (next Mode, err error) {
 		// TODO(fwereade): we *should* handle interrupted actions, and make sure
 		// they're marked as failed, but that's not for now.
 		logger.Infof("found incomplete action %q; ignoring", opState.ActionId)
+		if opState.HookPending {
+			logger.Infof("returning to failed %q hook", opState.Hook.Kind)
+			if err := u.skipAction(*opState.ActionId); err != nil {
+				return nil, err
+			}
+			return ModeHookError, nil
+		}
 		logger.Infof("recommitting prior %q hook", opState.Hook.Kind)
 		if err := u.skipHook(*opState.Hook); err != nil {
 			return nil, err
@@ -244,6 +251,10 @@ func modeAbideAliveLoop(u *Uniter) (Mode, error) {
 			hi = hook.Info{Kind: hooks.MeterStatusChanged}
 		case <-u.f.ConfigEvents():
 			hi = hook.Info{Kind: hooks.ConfigChanged}
+		case <-u.f.LeaderElectedEvents():
+			hi = hook.Info{Kind: hook.LeaderElected}
+		case <-u.f.LeaderSettingsEvents():
+			hi = hook.Info{Kind: hook.LeaderSettingsChanged}
 		case info := <-u.f.ActionEvents():
 			hi = hook.Info{Kind: info.Kind, ActionId: info.ActionId}
 		case hi = <-u.relations.Hooks():
@@ -297,6 +308,7 @@ func modeAbideDyingLoop(u *Uniter) (next Mode, err error) {
 
 // ModeHookError is responsible for watching and responding to:
 // * user resolution of hook errors
+// * action requests
 // * forced charm upgrade requests
 func ModeHookError(u *Uniter) (next Mode, err error) {
 	defer modeContext("ModeHookError", &err)()
@@ -320,10 +332,13 @@ func ModeHookError(u *Uniter) (next Mode, err error) {
 		hookName = fmt.Sprintf("%s-%s", relationName, hookInfo.Kind)
 	}
 	statusData["hook"] = hookName
-	statusMessage := fmt.Sprintf("hook failed: %q", hookName)
 	u.f.WantResolvedEvent()
 	u.f.WantUpgradeEvent(true)
 	for {
+		statusMessage := fmt.Sprintf("hook failed: %q", hookName)
+		if u.operationState().HookTimedOut {
+			statusMessage = fmt.Sprintf("hook timed out: %q", hookName)
+		}
 		if err = u.unit.SetStatus(params.StatusError, statusMessage, statusData); err != nil {
 			return nil, err
 		}
@@ -348,6 +363,13 @@ func ModeHookError(u *Uniter) (next Mode, err error) {
 				return nil, err
 			}
 			return ModeContinue, nil
+		case info := <-u.f.ActionEvents():
+			// Actions may be run while the hook error awaits
+			// resolution; once complete, the uniter returns to
+			// the failed hook's Pending state.
+			if err := u.runAction(info.ActionId); err != nil {
+				return nil, err
+			}
 		case curl := <-u.f.UpgradeEvents():
 			return ModeUpgrading(curl), nil
 		}
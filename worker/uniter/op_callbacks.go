@@ -5,9 +5,11 @@ package uniter
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/names"
+	utilexec "github.com/juju/utils/exec"
 	corecharm "gopkg.in/juju/charm.v4"
 	"gopkg.in/juju/charm.v4/hooks"
 	"launchpad.net/tomb"
@@ -47,8 +49,6 @@ func (opc *operationCallbacks) AcquireExecutionLock(message string) (func(), err
 // PrepareHook is part of the operation.Callbacks interface.
 func (opc *operationCallbacks) PrepareHook(hi hook.Info) (string, error) {
 	name := string(hi.Kind)
-	status := params.StatusActive
-
 	switch {
 	case hi.Kind.IsRelation():
 		var err error
@@ -56,32 +56,42 @@ func (opc *operationCallbacks) PrepareHook(hi hook.Info) (string, error) {
 		if err != nil {
 			return "", err
 		}
-	case hi.Kind == hooks.Stop:
-		status = params.StatusStopping
 	case hi.Kind == hooks.ConfigChanged:
 		opc.u.f.DiscardConfigEvent()
-		fallthrough
-	default:
-		if !opc.u.operationState().Started {
-			status = params.StatusInstalling
-		}
 	}
-	err := opc.u.unit.SetStatus(status, "", nil)
+	err := opc.u.unit.SetStatus(opc.hookStatus(hi), "", nil)
 	if err != nil {
 		return "", err
 	}
 	return name, nil
 }
 
+// hookStatus returns the agent status to report while the supplied hook
+// is being prepared, run, or committed.
+func (opc *operationCallbacks) hookStatus(hi hook.Info) params.Status {
+	switch {
+	case hi.Kind.IsRelation():
+		return params.StatusActive
+	case hi.Kind == hooks.Stop:
+		return params.StatusStopping
+	case !opc.u.operationState().Started:
+		return params.StatusInstalling
+	}
+	return params.StatusActive
+}
+
 // CommitHook is part of the operation.Callbacks interface.
 func (opc *operationCallbacks) CommitHook(hi hook.Info) error {
 	if hi.Kind.IsRelation() {
-		return opc.u.relations.CommitHook(hi)
+		if err := opc.u.relations.CommitHook(hi); err != nil {
+			return err
+		}
 	}
 	if hi.Kind == hooks.ConfigChanged {
 		opc.u.ranConfigChanged = true
 	}
-	return nil
+	// The hook is no longer running.
+	return opc.u.unit.SetStatus(opc.hookStatus(hi), "", nil)
 }
 
 func notifyHook(hook string, ctx runner.Context, method func(string)) {
@@ -95,6 +105,81 @@ func notifyHook(hook string, ctx runner.Context, method func(string)) {
 	method(hook)
 }
 
+// NotifyHookStarted is part of the operation.Callbacks interface. It records
+// the running hook, and when it started, in the unit's status, so that
+// hooks that take a long time to complete can be spotted.
+func (opc *operationCallbacks) NotifyHookStarted(hook string, ctx runner.Context) {
+	hi := opc.u.operationState().Hook
+	if hi == nil {
+		return
+	}
+	info := fmt.Sprintf("running %s hook", hook)
+	data := map[string]interface{}{
+		"hook":         hook,
+		"hook-started": time.Now().UTC().Format(time.RFC3339),
+	}
+	if err := opc.u.unit.SetStatus(opc.hookStatus(*hi), info, data); err != nil {
+		logger.Errorf("cannot record start of %q hook: %v", hook, err)
+	}
+}
+
+// RecordHook is part of the operation.Callbacks interface.
+func (opc *operationCallbacks) RecordHook(hook string, ctx runner.Context, started time.Time, hookErr error) {
+	opc.addHookRecord(params.HookRecordHook, hook, ctx, started, hookErr)
+}
+
+// RecordAction is part of the operation.Callbacks interface.
+func (opc *operationCallbacks) RecordAction(action string, ctx runner.Context, started time.Time) {
+	var actionErr error
+	actionData, err := ctx.ActionData()
+	if err != nil {
+		actionErr = err
+	} else if actionData.ActionFailed {
+		message := actionData.ResultsMessage
+		if message == "" {
+			message = "action failed"
+		}
+		actionErr = errors.New(message)
+	}
+	opc.addHookRecord(params.HookRecordAction, action, ctx, started, actionErr)
+}
+
+// RecordCommands is part of the operation.Callbacks interface.
+func (opc *operationCallbacks) RecordCommands(ctx runner.Context, started time.Time, response *utilexec.ExecResponse, runErr error) {
+	if runErr == nil && response != nil && response.Code != 0 {
+		runErr = fmt.Errorf("exit status %d", response.Code)
+	}
+	opc.addHookRecord(params.HookRecordCommands, "juju-run", ctx, started, runErr)
+}
+
+// addHookRecord adds a record of the named hook, action or commands, and
+// of how they failed if runErr is not nil, to the unit's hook history.
+func (opc *operationCallbacks) addHookRecord(kind, name string, ctx runner.Context, started time.Time, runErr error) {
+	record := params.HookRecord{
+		Kind:     kind,
+		Hook:     name,
+		Started:  started.UTC(),
+		Duration: time.Since(started),
+		Result:   "ok",
+	}
+	if r, ok := ctx.HookRelation(); ok {
+		record.Relation = r.FakeId()
+		record.RemoteUnit, _ = ctx.RemoteUnitName()
+	}
+	switch errors.Cause(runErr) {
+	case nil, runner.ErrReboot, runner.ErrRequeueAndReboot:
+		// A reboot request is not a failure.
+	default:
+		record.Result = runErr.Error()
+	}
+	err := opc.u.unit.AddHookRecord(record)
+	if errors.IsNotImplemented(err) {
+		logger.Debugf("not recording %s %q: %v", kind, name, err)
+	} else if err != nil {
+		logger.Errorf("cannot record %s %q: %v", kind, name, err)
+	}
+}
+
 // NotifyHookCompleted is part of the operation.Callbacks interface.
 func (opc *operationCallbacks) NotifyHookCompleted(hook string, ctx runner.Context) {
 	if opc.u.observer != nil {
@@ -4,6 +4,8 @@
 package operation
 
 import (
+	"time"
+
 	"github.com/juju/loggo"
 	utilexec "github.com/juju/utils/exec"
 	corecharm "gopkg.in/juju/charm.v4"
@@ -130,9 +132,17 @@ type Callbacks interface {
 
 	// NotifyHook* exist so that we can defer worrying about how to untangle the
 	// callbacks inserted for uniter_test. They're only used by RunHook operations.
+	NotifyHookStarted(string, runner.Context)
 	NotifyHookCompleted(string, runner.Context)
 	NotifyHookFailed(string, runner.Context)
 
+	// Record* add a record of a hook, action or set of commands that ran,
+	// and of its result, to the unit's hook history. They're only used by
+	// RunHook, RunAction and RunCommands operations respectively.
+	RecordHook(name string, ctx runner.Context, started time.Time, err error)
+	RecordAction(name string, ctx runner.Context, started time.Time)
+	RecordCommands(ctx runner.Context, started time.Time, response *utilexec.ExecResponse, err error)
+
 	// InitializeMetricsCollector ensures that the collect-metrics hook timer is
 	// up to date given the current deployed charm. It's only used in deploy
 	// operations.
@@ -5,6 +5,7 @@ package operation
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/juju/errors"
 
@@ -28,7 +29,7 @@ func (ra *runAction) String() string {
 
 // Prepare ensures that the action is valid and can be executed. If not, it
 // will return ErrSkipExecute. It preserves any hook recorded in the supplied
-// state.
+// state, and whether that hook failed and is awaiting resolution.
 // Prepare is part of the Operation interface.
 func (ra *runAction) Prepare(state State) (*State, error) {
 	rnr, err := ra.runnerFactory.NewActionRunner(ra.actionId)
@@ -49,11 +50,14 @@ func (ra *runAction) Prepare(state State) (*State, error) {
 	}
 	ra.name = actionData.ActionName
 	ra.runner = rnr
+	hookPending := isHookPending(state)
 	return stateChange{
-		Kind:     RunAction,
-		Step:     Pending,
-		ActionId: &ra.actionId,
-		Hook:     state.Hook,
+		Kind:         RunAction,
+		Step:         Pending,
+		ActionId:     &ra.actionId,
+		Hook:         state.Hook,
+		HookPending:  hookPending,
+		HookTimedOut: hookPending && state.HookTimedOut,
 	}.apply(state), nil
 }
 
@@ -67,26 +71,47 @@ func (ra *runAction) Execute(state State) (*State, error) {
 	}
 	defer unlock()
 
+	started := time.Now()
 	err = ra.runner.RunAction(ra.name)
 	if err != nil {
 		// This indicates an actual error -- an action merely failing should
 		// be handled inside the Runner, and returned as nil.
 		return nil, errors.Annotatef(err, "running action %q", ra.name)
 	}
+	ra.callbacks.RecordAction(ra.name, ra.runner.Context(), started)
 	return stateChange{
-		Kind:     RunAction,
-		Step:     Done,
-		ActionId: &ra.actionId,
-		Hook:     state.Hook,
+		Kind:         RunAction,
+		Step:         Done,
+		ActionId:     &ra.actionId,
+		Hook:         state.Hook,
+		HookPending:  state.HookPending,
+		HookTimedOut: state.HookTimedOut,
 	}.apply(state), nil
 }
 
-// Commit preserves the recorded hook, and returns a neutral state.
+// Commit preserves the recorded hook, and returns a neutral state; or, if
+// the action was run while the recorded hook awaited resolution of its
+// failure, returns to that hook's Pending state.
 // Commit is part of the Operation interface.
 func (ra *runAction) Commit(state State) (*State, error) {
+	if isHookPending(state) {
+		return stateChange{
+			Kind:         RunHook,
+			Step:         Pending,
+			Hook:         state.Hook,
+			HookTimedOut: state.HookTimedOut,
+		}.apply(state), nil
+	}
 	return stateChange{
 		Kind: Continue,
 		Step: Pending,
 		Hook: state.Hook,
 	}.apply(state), nil
 }
+
+// isHookPending returns whether the supplied state records a failed hook
+// that awaits resolution, either directly or from within an action run
+// while it did.
+func isHookPending(state State) bool {
+	return state.HookPending || state.Kind == RunHook && state.Step == Pending
+}
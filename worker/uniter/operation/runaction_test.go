@@ -148,6 +148,7 @@ func (s *RunActionSuite) TestExecuteRunError(c *gc.C) {
 	runnerFactory := NewRunActionRunnerFactory(errors.New("snargle"))
 	callbacks := &RunActionCallbacks{
 		MockAcquireExecutionLock: &MockAcquireExecutionLock{},
+		MockRecordAction:         &MockRecordAction{},
 	}
 	factory := operation.NewFactory(nil, runnerFactory, callbacks, nil)
 	op, err := factory.NewAction(someActionId)
@@ -159,6 +160,7 @@ func (s *RunActionSuite) TestExecuteRunError(c *gc.C) {
 	newState, err = op.Execute(operation.State{})
 	c.Assert(newState, gc.IsNil)
 	c.Assert(err, gc.ErrorMatches, `running action "some-action-name": snargle`)
+	c.Assert(callbacks.MockRecordAction.gotName, gc.IsNil)
 	c.Assert(*callbacks.MockAcquireExecutionLock.gotMessage, gc.Equals, "running action some-action-name")
 	c.Assert(callbacks.MockAcquireExecutionLock.didUnlock, jc.IsTrue)
 	c.Assert(*runnerFactory.MockNewActionRunner.runner.MockRunAction.gotName, gc.Equals, "some-action-name")
@@ -187,6 +189,24 @@ func (s *RunActionSuite) TestExecuteSuccess(c *gc.C) {
 			Started:            true,
 			CollectMetricsTime: 1234567,
 		},
+	}, {
+		description: "records failed hook",
+		before: operation.State{
+			Kind:         operation.RunHook,
+			Step:         operation.Pending,
+			Hook:         &hook.Info{Kind: hooks.ConfigChanged},
+			HookTimedOut: true,
+			Started:      true,
+		},
+		after: operation.State{
+			Kind:         operation.RunAction,
+			Step:         operation.Done,
+			ActionId:     &someActionId,
+			Hook:         &hook.Info{Kind: hooks.ConfigChanged},
+			HookPending:  true,
+			HookTimedOut: true,
+			Started:      true,
+		},
 	}}
 
 	for i, test := range stateChangeTests {
@@ -194,6 +214,7 @@ func (s *RunActionSuite) TestExecuteSuccess(c *gc.C) {
 		runnerFactory := NewRunActionRunnerFactory(nil)
 		callbacks := &RunActionCallbacks{
 			MockAcquireExecutionLock: &MockAcquireExecutionLock{},
+			MockRecordAction:         &MockRecordAction{},
 		}
 		factory := operation.NewFactory(nil, runnerFactory, callbacks, nil)
 		op, err := factory.NewAction(someActionId)
@@ -209,6 +230,8 @@ func (s *RunActionSuite) TestExecuteSuccess(c *gc.C) {
 		c.Assert(*callbacks.MockAcquireExecutionLock.gotMessage, gc.Equals, "running action some-action-name")
 		c.Assert(callbacks.MockAcquireExecutionLock.didUnlock, jc.IsTrue)
 		c.Assert(*runnerFactory.MockNewActionRunner.runner.MockRunAction.gotName, gc.Equals, "some-action-name")
+		c.Assert(*callbacks.MockRecordAction.gotName, gc.Equals, "some-action-name")
+		c.Assert(*callbacks.MockRecordAction.gotContext, gc.Equals, runnerFactory.MockNewActionRunner.runner.context)
 	}
 }
 
@@ -233,6 +256,24 @@ func (s *RunActionSuite) TestCommit(c *gc.C) {
 			Started:            true,
 			CollectMetricsTime: 1234567,
 		},
+	}, {
+		description: "returns to failed hook",
+		before: operation.State{
+			Kind:         operation.RunAction,
+			Step:         operation.Done,
+			ActionId:     &someActionId,
+			Hook:         &hook.Info{Kind: hooks.ConfigChanged},
+			HookPending:  true,
+			HookTimedOut: true,
+			Started:      true,
+		},
+		after: operation.State{
+			Kind:         operation.RunHook,
+			Step:         operation.Pending,
+			Hook:         &hook.Info{Kind: hooks.ConfigChanged},
+			HookTimedOut: true,
+			Started:      true,
+		},
 	}}
 
 	for i, test := range stateChangeTests {
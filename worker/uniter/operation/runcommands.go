@@ -5,6 +5,7 @@ package operation
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/juju/juju/worker/uniter/runner"
 )
@@ -57,7 +58,9 @@ func (rc *runCommands) Execute(state State) (*State, error) {
 	}
 	defer unlock()
 
+	started := time.Now()
 	response, err := rc.runner.RunCommands(rc.args.Commands)
+	rc.callbacks.RecordCommands(rc.runner.Context(), started, response, err)
 	switch err {
 	case runner.ErrRequeueAndReboot:
 		logger.Warningf("cannot requeue external commands")
@@ -85,6 +85,7 @@ func (s *RunCommandsSuite) TestExecuteRebootErrors(c *gc.C) {
 		)
 		callbacks := &RunCommandsCallbacks{
 			MockAcquireExecutionLock: &MockAcquireExecutionLock{},
+			MockRecordCommands:       &MockRecordCommands{},
 		}
 		factory := operation.NewFactory(nil, runnerFactory, callbacks, nil)
 		sendResponse := &MockSendResponse{}
@@ -101,6 +102,8 @@ func (s *RunCommandsSuite) TestExecuteRebootErrors(c *gc.C) {
 		c.Assert(*runnerFactory.MockNewCommandRunner.runner.MockRunCommands.gotCommands, gc.Equals, "do something")
 		c.Assert(*sendResponse.gotResponse, gc.DeepEquals, &utilexec.ExecResponse{Code: 101})
 		c.Assert(*sendResponse.gotErr, gc.Equals, operation.ErrNeedsReboot)
+		c.Assert(*callbacks.MockRecordCommands.gotResponse, gc.DeepEquals, &utilexec.ExecResponse{Code: 101})
+		c.Assert(*callbacks.MockRecordCommands.gotErr, gc.Equals, sendErr)
 	}
 }
 
@@ -110,6 +113,7 @@ func (s *RunCommandsSuite) TestExecuteOtherError(c *gc.C) {
 	)
 	callbacks := &RunCommandsCallbacks{
 		MockAcquireExecutionLock: &MockAcquireExecutionLock{},
+		MockRecordCommands:       &MockRecordCommands{},
 	}
 	factory := operation.NewFactory(nil, runnerFactory, callbacks, nil)
 	sendResponse := &MockSendResponse{}
@@ -126,6 +130,8 @@ func (s *RunCommandsSuite) TestExecuteOtherError(c *gc.C) {
 	c.Assert(*runnerFactory.MockNewCommandRunner.runner.MockRunCommands.gotCommands, gc.Equals, "do something")
 	c.Assert(*sendResponse.gotResponse, gc.IsNil)
 	c.Assert(*sendResponse.gotErr, gc.ErrorMatches, "sneh")
+	c.Assert(*callbacks.MockRecordCommands.gotResponse, gc.IsNil)
+	c.Assert(*callbacks.MockRecordCommands.gotErr, gc.ErrorMatches, "sneh")
 }
 
 func (s *RunCommandsSuite) TestExecuteSuccess(c *gc.C) {
@@ -134,6 +140,7 @@ func (s *RunCommandsSuite) TestExecuteSuccess(c *gc.C) {
 	)
 	callbacks := &RunCommandsCallbacks{
 		MockAcquireExecutionLock: &MockAcquireExecutionLock{},
+		MockRecordCommands:       &MockRecordCommands{},
 	}
 	factory := operation.NewFactory(nil, runnerFactory, callbacks, nil)
 	sendResponse := &MockSendResponse{}
@@ -150,6 +157,9 @@ func (s *RunCommandsSuite) TestExecuteSuccess(c *gc.C) {
 	c.Assert(*runnerFactory.MockNewCommandRunner.runner.MockRunCommands.gotCommands, gc.Equals, "do something")
 	c.Assert(*sendResponse.gotResponse, gc.DeepEquals, &utilexec.ExecResponse{Code: 222})
 	c.Assert(*sendResponse.gotErr, jc.ErrorIsNil)
+	c.Assert(*callbacks.MockRecordCommands.gotContext, gc.Equals, runnerFactory.MockNewCommandRunner.runner.context)
+	c.Assert(*callbacks.MockRecordCommands.gotResponse, gc.DeepEquals, &utilexec.ExecResponse{Code: 222})
+	c.Assert(*callbacks.MockRecordCommands.gotErr, jc.ErrorIsNil)
 }
 
 func (s *RunCommandsSuite) TestCommit(c *gc.C) {
@@ -70,8 +70,13 @@ func (rh *runHook) Execute(state State) (*State, error) {
 	ranHook := true
 	step := Done
 
+	started := time.Now()
+	rh.callbacks.NotifyHookStarted(rh.name, rh.runner.Context())
 	err = rh.runner.RunHook(rh.name)
 	cause := errors.Cause(err)
+	if !runner.IsMissingHookError(cause) {
+		rh.callbacks.RecordHook(rh.name, rh.runner.Context(), started, err)
+	}
 	switch {
 	case runner.IsMissingHookError(cause):
 		ranHook = false
@@ -82,6 +87,15 @@ func (rh *runHook) Execute(state State) (*State, error) {
 	case cause == runner.ErrReboot:
 		err = ErrNeedsReboot
 	case err == nil:
+	case runner.IsHookTimedOutError(cause):
+		logger.Errorf("hook %q timed out: %v", rh.name, err)
+		rh.callbacks.NotifyHookFailed(rh.name, rh.runner.Context())
+		return stateChange{
+			Kind:         RunHook,
+			Step:         Pending,
+			Hook:         &rh.info,
+			HookTimedOut: true,
+		}.apply(state), ErrHookFailed
 	default:
 		logger.Errorf("hook %q failed: %v", rh.name, err)
 		rh.callbacks.NotifyHookFailed(rh.name, rh.runner.Context())
@@ -192,8 +192,10 @@ func (s *RunHookSuite) getExecuteRunnerTest(c *gc.C, newHook newHook, runErr err
 	callbacks := &ExecuteHookCallbacks{
 		PrepareHookCallbacks:     NewPrepareHookCallbacks(),
 		MockAcquireExecutionLock: &MockAcquireExecutionLock{},
+		MockNotifyHookStarted:    &MockNotify{},
 		MockNotifyHookCompleted:  &MockNotify{},
 		MockNotifyHookFailed:     &MockNotify{},
+		MockRecordHook:           &MockRecordHook{},
 	}
 	factory := operation.NewFactory(nil, runnerFactory, callbacks, nil)
 	op, err := newHook(factory, hook.Info{Kind: hooks.ConfigChanged})
@@ -219,6 +221,7 @@ func (s *RunHookSuite) testExecuteMissingHookError(c *gc.C, newHook newHook) {
 	c.Assert(*runnerFactory.MockNewHookRunner.runner.MockRunHook.gotName, gc.Equals, "some-hook-name")
 	c.Assert(callbacks.MockNotifyHookCompleted.gotName, gc.IsNil)
 	c.Assert(callbacks.MockNotifyHookFailed.gotName, gc.IsNil)
+	c.Assert(callbacks.MockRecordHook.gotName, gc.IsNil)
 }
 
 func (s *RunHookSuite) TestExecuteMissingHookError_Run(c *gc.C) {
@@ -302,6 +305,9 @@ func (s *RunHookSuite) testExecuteOtherError(c *gc.C, newHook newHook) {
 	c.Assert(*callbacks.MockNotifyHookFailed.gotName, gc.Equals, "some-hook-name")
 	c.Assert(*callbacks.MockNotifyHookFailed.gotContext, gc.Equals, runnerFactory.MockNewHookRunner.runner.context)
 	c.Assert(callbacks.MockNotifyHookCompleted.gotName, gc.IsNil)
+	c.Assert(*callbacks.MockRecordHook.gotName, gc.Equals, "some-hook-name")
+	c.Assert(*callbacks.MockRecordHook.gotContext, gc.Equals, runnerFactory.MockNewHookRunner.runner.context)
+	c.Assert(callbacks.MockRecordHook.gotErr, gc.Equals, runErr)
 }
 
 func (s *RunHookSuite) TestExecuteOtherError_Run(c *gc.C) {
@@ -312,10 +318,41 @@ func (s *RunHookSuite) TestExecuteOtherError_Retry(c *gc.C) {
 	s.testExecuteOtherError(c, (operation.Factory).NewRetryHook)
 }
 
+func (s *RunHookSuite) testExecuteTimedOutError(c *gc.C, newHook newHook) {
+	runErr := runner.NewHookTimedOutError("some-hook-name", time.Minute)
+	op, callbacks, runnerFactory := s.getExecuteRunnerTest(c, newHook, runErr)
+	_, err := op.Prepare(operation.State{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	newState, err := op.Execute(operation.State{})
+	c.Assert(err, gc.Equals, operation.ErrHookFailed)
+	c.Assert(newState, gc.DeepEquals, &operation.State{
+		Kind:         operation.RunHook,
+		Step:         operation.Pending,
+		Hook:         &hook.Info{Kind: hooks.ConfigChanged},
+		HookTimedOut: true,
+	})
+	c.Assert(*callbacks.MockAcquireExecutionLock.gotMessage, gc.Equals, "running hook some-hook-name")
+	c.Assert(callbacks.MockAcquireExecutionLock.didUnlock, jc.IsTrue)
+	c.Assert(*runnerFactory.MockNewHookRunner.runner.MockRunHook.gotName, gc.Equals, "some-hook-name")
+	c.Assert(*callbacks.MockNotifyHookStarted.gotName, gc.Equals, "some-hook-name")
+	c.Assert(*callbacks.MockNotifyHookFailed.gotName, gc.Equals, "some-hook-name")
+	c.Assert(*callbacks.MockNotifyHookFailed.gotContext, gc.Equals, runnerFactory.MockNewHookRunner.runner.context)
+	c.Assert(callbacks.MockNotifyHookCompleted.gotName, gc.IsNil)
+}
+
+func (s *RunHookSuite) TestExecuteTimedOutError_Run(c *gc.C) {
+	s.testExecuteTimedOutError(c, (operation.Factory).NewRunHook)
+}
+
+func (s *RunHookSuite) TestExecuteTimedOutError_Retry(c *gc.C) {
+	s.testExecuteTimedOutError(c, (operation.Factory).NewRetryHook)
+}
+
 func (s *RunHookSuite) testExecuteSuccess(
 	c *gc.C, newHook newHook, before, after operation.State,
 ) {
-	op, _, _ := s.getExecuteRunnerTest(c, newHook, nil)
+	op, callbacks, _ := s.getExecuteRunnerTest(c, newHook, nil)
 	midState, err := op.Prepare(before)
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(midState, gc.NotNil)
@@ -323,6 +360,8 @@ func (s *RunHookSuite) testExecuteSuccess(
 	newState, err := op.Execute(*midState)
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(newState, gc.DeepEquals, &after)
+	c.Assert(*callbacks.MockRecordHook.gotName, gc.Equals, "some-hook-name")
+	c.Assert(callbacks.MockRecordHook.gotErr, jc.ErrorIsNil)
 }
 
 func (s *RunHookSuite) TestExecuteSuccess_BlankSlate(c *gc.C) {
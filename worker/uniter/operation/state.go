@@ -80,6 +80,16 @@ type State struct {
 	// operation, and is otherwise blank.
 	CharmURL *charm.URL `yaml:"charm,omitempty"`
 
+	// HookTimedOut indicates that the RunHook operation failed because the
+	// hook was killed after running for longer than the configured timeout.
+	HookTimedOut bool `yaml:"hook-timed-out,omitempty"`
+
+	// HookPending indicates that the RunAction operation was started while
+	// the uniter was awaiting resolution of the failed hook held in Hook,
+	// and that the uniter should return to that hook's Pending state once
+	// the action is complete.
+	HookPending bool `yaml:"hook-pending,omitempty"`
+
 	// CollectMetricsTime records the time the collect metrics hook was last run.
 	// It's set to nil if the hook was not run at all. Recording time as int64
 	// because the yaml encoder cannot encode the time.Time struct.
@@ -92,6 +102,12 @@ func (st State) validate() (err error) {
 	hasHook := st.Hook != nil
 	hasActionId := st.ActionId != nil
 	hasCharm := st.CharmURL != nil
+	if st.HookPending && st.Kind != RunAction {
+		return errors.New("unexpected pending hook")
+	}
+	if st.HookTimedOut && st.Kind != RunHook && !st.HookPending {
+		return errors.New("unexpected hook timeout")
+	}
 	switch st.Kind {
 	case Install:
 		if hasHook {
@@ -145,11 +161,13 @@ func (st State) CollectedMetricsAt() time.Time {
 
 // stateChange is useful for a variety of Operation implementations.
 type stateChange struct {
-	Kind     Kind
-	Step     Step
-	Hook     *hook.Info
-	ActionId *string
-	CharmURL *charm.URL
+	Kind         Kind
+	Step         Step
+	Hook         *hook.Info
+	ActionId     *string
+	CharmURL     *charm.URL
+	HookTimedOut bool
+	HookPending  bool
 }
 
 func (change stateChange) apply(state State) *State {
@@ -158,6 +176,8 @@ func (change stateChange) apply(state State) *State {
 	state.Hook = change.Hook
 	state.ActionId = change.ActionId
 	state.CharmURL = change.CharmURL
+	state.HookTimedOut = change.HookTimedOut
+	state.HookPending = change.HookPending
 	return &state
 }
 
@@ -94,6 +94,23 @@ var stateTests = []struct {
 			Hook:     &hook.Info{Kind: hooks.Install},
 			ActionId: &someActionId,
 		},
+	}, {
+		st: operation.State{
+			Kind:         operation.RunAction,
+			Step:         operation.Pending,
+			Hook:         &hook.Info{Kind: hooks.Install},
+			ActionId:     &someActionId,
+			HookPending:  true,
+			HookTimedOut: true,
+		},
+	}, {
+		st: operation.State{
+			Kind:        operation.Continue,
+			Step:        operation.Pending,
+			Hook:        &hook.Info{Kind: hooks.Install},
+			HookPending: true,
+		},
+		err: `unexpected pending hook`,
 	},
 	// RunHook operation.
 	{
@@ -157,6 +174,13 @@ var stateTests = []struct {
 			},
 		},
 		err: `action id "foo" cannot be parsed as an action tag`,
+	}, {
+		st: operation.State{
+			Kind:         operation.RunHook,
+			Step:         operation.Pending,
+			Hook:         &hook.Info{Kind: hooks.ConfigChanged},
+			HookTimedOut: true,
+		},
 	},
 	// Upgrade operation.
 	{
@@ -165,6 +189,14 @@ var stateTests = []struct {
 			Step: operation.Pending,
 		},
 		err: `missing charm URL`,
+	}, {
+		st: operation.State{
+			Kind:         operation.Upgrade,
+			Step:         operation.Pending,
+			CharmURL:     stcurl,
+			HookTimedOut: true,
+		},
+		err: `unexpected hook timeout`,
 	}, {
 		st: operation.State{
 			Kind:     operation.Upgrade,
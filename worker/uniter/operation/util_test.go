@@ -4,6 +4,8 @@
 package operation_test
 
 import (
+	"time"
+
 	"github.com/juju/errors"
 	utilexec "github.com/juju/utils/exec"
 	corecharm "gopkg.in/juju/charm.v4"
@@ -136,10 +138,21 @@ func (mock *MockAcquireExecutionLock) Call(message string) (func(), error) {
 	return func() { mock.didUnlock = true }, nil
 }
 
+type MockRecordAction struct {
+	gotName    *string
+	gotContext *runner.Context
+}
+
+func (mock *MockRecordAction) Call(actionName string, ctx runner.Context) {
+	mock.gotName = &actionName
+	mock.gotContext = &ctx
+}
+
 type RunActionCallbacks struct {
 	operation.Callbacks
 	*MockFailAction
 	*MockAcquireExecutionLock
+	*MockRecordAction
 }
 
 func (cb *RunActionCallbacks) FailAction(actionId, message string) error {
@@ -150,15 +163,36 @@ func (cb *RunActionCallbacks) AcquireExecutionLock(message string) (func(), erro
 	return cb.MockAcquireExecutionLock.Call(message)
 }
 
+func (cb *RunActionCallbacks) RecordAction(actionName string, ctx runner.Context, started time.Time) {
+	cb.MockRecordAction.Call(actionName, ctx)
+}
+
+type MockRecordCommands struct {
+	gotContext  *runner.Context
+	gotResponse **utilexec.ExecResponse
+	gotErr      *error
+}
+
+func (mock *MockRecordCommands) Call(ctx runner.Context, response *utilexec.ExecResponse, err error) {
+	mock.gotContext = &ctx
+	mock.gotResponse = &response
+	mock.gotErr = &err
+}
+
 type RunCommandsCallbacks struct {
 	operation.Callbacks
 	*MockAcquireExecutionLock
+	*MockRecordCommands
 }
 
 func (cb *RunCommandsCallbacks) AcquireExecutionLock(message string) (func(), error) {
 	return cb.MockAcquireExecutionLock.Call(message)
 }
 
+func (cb *RunCommandsCallbacks) RecordCommands(ctx runner.Context, started time.Time, response *utilexec.ExecResponse, err error) {
+	cb.MockRecordCommands.Call(ctx, response, err)
+}
+
 type MockPrepareHook struct {
 	gotHook *hook.Info
 	name    string
@@ -194,17 +228,35 @@ func (mock *MockNotify) Call(hookName string, ctx runner.Context) {
 	mock.gotContext = &ctx
 }
 
+type MockRecordHook struct {
+	gotName    *string
+	gotContext *runner.Context
+	gotErr     error
+}
+
+func (mock *MockRecordHook) Call(hookName string, ctx runner.Context, err error) {
+	mock.gotName = &hookName
+	mock.gotContext = &ctx
+	mock.gotErr = err
+}
+
 type ExecuteHookCallbacks struct {
 	*PrepareHookCallbacks
 	*MockAcquireExecutionLock
+	MockNotifyHookStarted   *MockNotify
 	MockNotifyHookCompleted *MockNotify
 	MockNotifyHookFailed    *MockNotify
+	MockRecordHook          *MockRecordHook
 }
 
 func (cb *ExecuteHookCallbacks) AcquireExecutionLock(message string) (func(), error) {
 	return cb.MockAcquireExecutionLock.Call(message)
 }
 
+func (cb *ExecuteHookCallbacks) NotifyHookStarted(hookName string, ctx runner.Context) {
+	cb.MockNotifyHookStarted.Call(hookName, ctx)
+}
+
 func (cb *ExecuteHookCallbacks) NotifyHookCompleted(hookName string, ctx runner.Context) {
 	cb.MockNotifyHookCompleted.Call(hookName, ctx)
 }
@@ -213,6 +265,10 @@ func (cb *ExecuteHookCallbacks) NotifyHookFailed(hookName string, ctx runner.Con
 	cb.MockNotifyHookFailed.Call(hookName, ctx)
 }
 
+func (cb *ExecuteHookCallbacks) RecordHook(hookName string, ctx runner.Context, started time.Time, err error) {
+	cb.MockRecordHook.Call(hookName, ctx, err)
+}
+
 type MockCommitHook struct {
 	gotHook *hook.Info
 	err     error
@@ -396,6 +452,7 @@ func NewRunCommandsRunnerFactory(runResponse *utilexec.ExecResponse, runErr erro
 		MockNewCommandRunner: &MockNewCommandRunner{
 			runner: &MockRunner{
 				MockRunCommands: &MockRunCommands{response: runResponse, err: runErr},
+				context:         &MockContext{},
 			},
 		},
 	}
@@ -91,6 +91,10 @@ type StateDir struct {
 	// to be synchronized with the true state so long as no concurrent
 	// changes are made to the directory.
 	state State
+
+	// settings holds, for each member unit, the settings last recorded
+	// by WriteSettings.
+	settings map[string]map[string]string
 }
 
 // State returns the current state of the relation.
@@ -98,6 +102,21 @@ func (d *StateDir) State() *State {
 	return d.state.copy()
 }
 
+// Settings returns the settings of the named member unit as recorded when
+// its last "relation-changed" hook was committed, and whether any were
+// recorded.
+func (d *StateDir) Settings(unitName string) (map[string]string, bool) {
+	settings, found := d.settings[unitName]
+	if !found {
+		return nil, false
+	}
+	copy := make(map[string]string, len(settings))
+	for k, v := range settings {
+		copy[k] = v
+	}
+	return copy, true
+}
+
 // ReadStateDir loads a StateDir from the subdirectory of dirPath named
 // for the supplied RelationId. If the directory does not exist, no error
 // is returned,
@@ -105,6 +124,7 @@ func ReadStateDir(dirPath string, relationId int) (d *StateDir, err error) {
 	d = &StateDir{
 		filepath.Join(dirPath, strconv.Itoa(relationId)),
 		State{relationId, map[string]int64{}, ""},
+		map[string]map[string]string{},
 	}
 	defer errors.DeferredAnnotatef(&err, "cannot load relation state from %q", d.path)
 	if _, err := os.Stat(d.path); os.IsNotExist(err) {
@@ -138,6 +158,9 @@ func ReadStateDir(dirPath string, relationId int) (d *StateDir, err error) {
 			return nil, fmt.Errorf(`invalid unit file %q: "changed-version" not set`, name)
 		}
 		d.state.Members[unitName] = *info.ChangeVersion
+		if info.Settings != nil {
+			d.settings[unitName] = info.Settings
+		}
 		if info.ChangedPending {
 			if d.state.ChangedPending != "" {
 				return nil, fmt.Errorf("%q and %q both have pending changed hooks", d.state.ChangedPending, unitName)
@@ -188,7 +211,24 @@ func (d *StateDir) Ensure() error {
 // It must be called after the respective hook was executed successfully.
 // Write doesn't validate hi but guarantees that successive writes of
 // the same hi are idempotent.
-func (d *StateDir) Write(hi hook.Info) (err error) {
+func (d *StateDir) Write(hi hook.Info) error {
+	return d.write(hi, d.settings[hi.RemoteUnit])
+}
+
+// WriteSettings acts like Write for a "relation-changed" hook, and also
+// records the settings of the remote unit that were visible to the hook,
+// so that they may be compared with the unit's settings in later hooks.
+func (d *StateDir) WriteSettings(hi hook.Info, settings map[string]string) error {
+	if hi.Kind != hooks.RelationChanged {
+		return errors.Errorf("cannot record settings for %q hook", hi.Kind)
+	}
+	if settings == nil {
+		settings = map[string]string{}
+	}
+	return d.write(hi, settings)
+}
+
+func (d *StateDir) write(hi hook.Info, settings map[string]string) (err error) {
 	defer errors.DeferredAnnotatef(&err, "failed to write %q hook info for %q on state directory", hi.Kind, hi.RemoteUnit)
 	if hi.Kind == hooks.RelationBroken {
 		return d.Remove()
@@ -201,14 +241,18 @@ func (d *StateDir) Write(hi hook.Info) (err error) {
 		}
 		// If atomic delete succeeded, update own state.
 		delete(d.state.Members, hi.RemoteUnit)
+		delete(d.settings, hi.RemoteUnit)
 		return nil
 	}
-	di := diskInfo{&hi.ChangeVersion, hi.Kind == hooks.RelationJoined}
+	di := diskInfo{&hi.ChangeVersion, hi.Kind == hooks.RelationJoined, settings}
 	if err := utils.WriteYaml(path, &di); err != nil {
 		return err
 	}
 	// If write was successful, update own state.
 	d.state.Members[hi.RemoteUnit] = hi.ChangeVersion
+	if settings != nil {
+		d.settings[hi.RemoteUnit] = settings
+	}
 	if hi.Kind == hooks.RelationJoined {
 		d.state.ChangedPending = hi.RemoteUnit
 	} else {
@@ -224,11 +268,13 @@ func (d *StateDir) Remove() error {
 	}
 	// If atomic delete succeeded, update own state.
 	d.state.Members = nil
+	d.settings = nil
 	return nil
 }
 
 // diskInfo defines the relation unit data serialization.
 type diskInfo struct {
-	ChangeVersion  *int64 `yaml:"change-version"`
-	ChangedPending bool   `yaml:"changed-pending,omitempty"`
+	ChangeVersion  *int64            `yaml:"change-version"`
+	ChangedPending bool              `yaml:"changed-pending,omitempty"`
+	Settings       map[string]string `yaml:"settings,omitempty"`
 }
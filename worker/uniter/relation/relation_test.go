@@ -243,6 +243,50 @@ func (s *StateDirSuite) TestWrite(c *gc.C) {
 	}
 }
 
+func (s *StateDirSuite) TestWriteSettings(c *gc.C) {
+	basedir := c.MkDir()
+	setUpDir(c, basedir, "123", map[string]string{
+		"foo-1": "change-version: 0\n",
+		"foo-2": "change-version: 0\n",
+	})
+	dir, err := relation.ReadStateDir(basedir, 123)
+	c.Assert(err, jc.ErrorIsNil)
+	_, found := dir.Settings("foo/1")
+	c.Assert(found, jc.IsFalse)
+
+	changed := hook.Info{Kind: hooks.RelationChanged, RelationId: 123, RemoteUnit: "foo/1", ChangeVersion: 1}
+	err = dir.WriteSettings(changed, map[string]string{"a": "1", "b": "2"})
+	c.Assert(err, jc.ErrorIsNil)
+	settings, found := dir.Settings("foo/1")
+	c.Assert(found, jc.IsTrue)
+	c.Assert(settings, gc.DeepEquals, map[string]string{"a": "1", "b": "2"})
+
+	// Settings survive writes that don't record any, and are read back
+	// from disk.
+	changed.ChangeVersion = 2
+	err = dir.Write(changed)
+	c.Assert(err, jc.ErrorIsNil)
+	dir, err = relation.ReadStateDir(basedir, 123)
+	c.Assert(err, jc.ErrorIsNil)
+	assertState(c, dir, basedir, 123, msi{"foo/1": 2, "foo/2": 0}, "", false)
+	settings, found = dir.Settings("foo/1")
+	c.Assert(found, jc.IsTrue)
+	c.Assert(settings, gc.DeepEquals, map[string]string{"a": "1", "b": "2"})
+	_, found = dir.Settings("foo/2")
+	c.Assert(found, jc.IsFalse)
+
+	// Settings are forgotten when the unit departs.
+	err = dir.Write(hook.Info{Kind: hooks.RelationDeparted, RelationId: 123, RemoteUnit: "foo/1"})
+	c.Assert(err, jc.ErrorIsNil)
+	_, found = dir.Settings("foo/1")
+	c.Assert(found, jc.IsFalse)
+
+	// Only relation-changed hooks record settings.
+	joined := hook.Info{Kind: hooks.RelationJoined, RelationId: 123, RemoteUnit: "foo/3"}
+	err = dir.WriteSettings(joined, map[string]string{"a": "1"})
+	c.Assert(err, gc.ErrorMatches, `cannot record settings for "relation-joined" hook`)
+}
+
 func (s *StateDirSuite) TestRemove(c *gc.C) {
 	basedir := c.MkDir()
 	dir, err := relation.ReadStateDir(basedir, 1)
@@ -9,6 +9,7 @@ import (
 	"gopkg.in/juju/charm.v4/hooks"
 
 	apiuniter "github.com/juju/juju/api/uniter"
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/worker/uniter/hook"
 	"github.com/juju/juju/worker/uniter/relation"
 	"github.com/juju/juju/worker/uniter/runner"
@@ -21,6 +22,17 @@ type Relationer struct {
 	queue relation.HookQueue
 	hooks chan<- hook.Info
 	dying bool
+
+	// prepared holds the remote unit settings read when preparing a
+	// relation-changed hook, so they can be recorded when it's committed.
+	prepared *preparedSettings
+}
+
+// preparedSettings holds the settings of a remote unit as they were
+// when the hook described by info was prepared.
+type preparedSettings struct {
+	info     hook.Info
+	settings params.Settings
 }
 
 // NewRelationer creates a new Relationer. The unit will not join the
@@ -37,10 +49,18 @@ func NewRelationer(ru *apiuniter.RelationUnit, dir *relation.StateDir, hooks cha
 func (r *Relationer) ContextInfo() *runner.RelationInfo {
 	members := r.dir.State().Members
 	memberNames := make([]string, 0, len(members))
+	previousSettings := map[string]params.Settings{}
 	for memberName := range members {
 		memberNames = append(memberNames, memberName)
+		if settings, found := r.dir.Settings(memberName); found {
+			previousSettings[memberName] = settings
+		}
+	}
+	return &runner.RelationInfo{
+		RelationUnit:     r.ru,
+		MemberNames:      memberNames,
+		PreviousSettings: previousSettings,
 	}
-	return &runner.RelationInfo{r.ru, memberNames}
 }
 
 // IsImplicit returns whether the local relation endpoint is implicit. Implicit
@@ -138,6 +158,18 @@ func (r *Relationer) PrepareHook(hi hook.Info) (hookName string, err error) {
 	if err = r.dir.State().Validate(hi); err != nil {
 		return
 	}
+	r.prepared = nil
+	if hi.Kind == hooks.RelationChanged {
+		// The hook may see settings newer than these, but never older,
+		// so comparing them with the remote unit's settings in later
+		// hooks will never miss a change.
+		settings, err := r.ru.ReadSettings(hi.RemoteUnit)
+		if err == nil {
+			r.prepared = &preparedSettings{hi, settings}
+		} else if !params.IsCodeNotFound(err) {
+			return "", err
+		}
+	}
 	name := r.ru.Endpoint().Name
 	return fmt.Sprintf("%s-%s", name, hi.Kind), nil
 }
@@ -150,5 +182,10 @@ func (r *Relationer) CommitHook(hi hook.Info) error {
 	if hi.Kind == hooks.RelationBroken {
 		return r.die()
 	}
+	prepared := r.prepared
+	r.prepared = nil
+	if prepared != nil && prepared.info == hi {
+		return r.dir.WriteSettings(hi, prepared.settings)
+	}
 	return r.dir.Write(hi)
 }
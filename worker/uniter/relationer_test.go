@@ -18,6 +18,7 @@ import (
 
 	"github.com/juju/juju/api"
 	apiuniter "github.com/juju/juju/api/uniter"
+	"github.com/juju/juju/apiserver/params"
 	jujutesting "github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
@@ -323,6 +324,38 @@ func (s *RelationerSuite) TestPrepareCommitHooks(c *gc.C) {
 	assertMembers(map[string]int64{"u/1": 7, "u/2": 3})
 }
 
+func (s *RelationerSuite) TestCommitHookRecordsSettings(c *gc.C) {
+	ru1, _ := s.AddRelationUnit(c, "u/1")
+	err := ru1.EnterScope(map[string]interface{}{"foo": "bar"})
+	c.Assert(err, jc.ErrorIsNil)
+	r := uniter.NewRelationer(s.apiRelUnit, s.dir, s.hooks)
+	err = r.Join()
+	c.Assert(err, jc.ErrorIsNil)
+
+	joined := hook.Info{Kind: hooks.RelationJoined, RemoteUnit: "u/1"}
+	_, err = r.PrepareHook(joined)
+	c.Assert(err, jc.ErrorIsNil)
+	err = r.CommitHook(joined)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(r.ContextInfo().PreviousSettings, gc.HasLen, 0)
+
+	// The settings recorded are those read when the hook was prepared,
+	// even if they change while the hook runs.
+	changed := hook.Info{Kind: hooks.RelationChanged, RemoteUnit: "u/1", ChangeVersion: 1}
+	_, err = r.PrepareHook(changed)
+	c.Assert(err, jc.ErrorIsNil)
+	node, err := ru1.Settings()
+	c.Assert(err, jc.ErrorIsNil)
+	node.Set("foo", "baz")
+	_, err = node.Write()
+	c.Assert(err, jc.ErrorIsNil)
+	err = r.CommitHook(changed)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(r.ContextInfo().PreviousSettings, jc.DeepEquals, map[string]params.Settings{
+		"u/1": {"foo": "bar"},
+	})
+}
+
 func (s *RelationerSuite) TestSetDying(c *gc.C) {
 	ru1, _ := s.AddRelationUnit(c, "u/1")
 	settings := map[string]interface{}{"unit": "settings"}
@@ -18,6 +18,7 @@ import (
 
 	"github.com/juju/juju/api/uniter"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/leadership"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/storage"
 	"github.com/juju/juju/worker/uniter/runner/jujuc"
@@ -43,6 +44,13 @@ type HookContext struct {
 	// not fully there yet.
 	state *uniter.State
 
+	// leadership is used to claim the leadership of the unit's service.
+	leadership leadership.LeadershipManager
+
+	// leaderSettings is the cached value of the service's leader
+	// settings, read from state when they are first asked for.
+	leaderSettings map[string]string
+
 	// privateAddress is the cached value of the unit's private
 	// address.
 	privateAddress string
@@ -125,6 +133,10 @@ type HookContext struct {
 	// like a juju-run command or a hook
 	process *os.Process
 
+	// hookTimeout is how long a hook may run before it is killed; it
+	// is not enforced if zero.
+	hookTimeout time.Duration
+
 	// rebootPriority tells us when the hook wants to reboot. If rebootPriority is jujuc.RebootNow
 	// the hook will be killed and requeued
 	rebootPriority jujuc.RebootPriority
@@ -134,6 +146,16 @@ type HookContext struct {
 
 	// storageId is the id of the storage instance associated with the running hook.
 	storageId string
+
+	// unitStatus is the workload status of the unit, read from
+	// state when it is first asked for.
+	unitStatus *jujuc.StatusInfo
+
+	// pendingUnitStatus and pendingServiceStatus hold the workload
+	// status of the unit and of its service, to be set when the
+	// current hook is committed.
+	pendingUnitStatus    *jujuc.StatusInfo
+	pendingServiceStatus *jujuc.StatusInfo
 }
 
 func (ctx *HookContext) RequestReboot(priority jujuc.RebootPriority) error {
@@ -175,6 +197,10 @@ func (ctx *HookContext) SetProcess(process *os.Process) {
 	ctx.process = process
 }
 
+func (ctx *HookContext) HookTimeout() time.Duration {
+	return ctx.hookTimeout
+}
+
 func (ctx *HookContext) Id() string {
 	return ctx.id
 }
@@ -208,6 +234,96 @@ func (ctx *HookContext) StorageInstance(storageId string) (*storage.StorageInsta
 	return nil, false
 }
 
+func (ctx *HookContext) UnitStatus() (*jujuc.StatusInfo, error) {
+	if ctx.pendingUnitStatus != nil {
+		return ctx.pendingUnitStatus, nil
+	}
+	if ctx.unitStatus == nil {
+		result, err := ctx.unit.WorkloadStatus()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ctx.unitStatus = &jujuc.StatusInfo{
+			Status: string(result.Status),
+			Info:   result.Info,
+			Data:   result.Data,
+		}
+	}
+	return ctx.unitStatus, nil
+}
+
+func (ctx *HookContext) SetUnitStatus(status jujuc.StatusInfo) error {
+	ctx.pendingUnitStatus = &status
+	return nil
+}
+
+// SetServiceStatus records the status of the unit's service, to be
+// set when the context is flushed. Leadership is checked now, so that
+// a charm that is not the leader learns of it when it runs status-set.
+func (ctx *HookContext) SetServiceStatus(status jujuc.StatusInfo) error {
+	isLeader, err := ctx.IsLeader()
+	if err != nil {
+		return errors.Annotate(err, "cannot determine leadership")
+	}
+	if !isLeader {
+		return errors.Errorf("unit %q is not leader", ctx.unitName)
+	}
+	ctx.pendingServiceStatus = &status
+	return nil
+}
+
+// IsLeader returns whether the unit is the leader of its service. Asking
+// renews the unit's leadership claim, so that a positive answer remains
+// true for at least the duration of the claim.
+func (ctx *HookContext) IsLeader() (bool, error) {
+	_, err := ctx.leadership.ClaimLeadership(ctx.unit.ServiceName(), ctx.unitName)
+	if errors.Cause(err) == leadership.LeadershipClaimDeniedErr {
+		return false, nil
+	} else if err != nil {
+		return false, errors.Trace(err)
+	}
+	return true, nil
+}
+
+func (ctx *HookContext) LeaderSettings() (map[string]string, error) {
+	if ctx.state.LeadershipSettings == nil {
+		return nil, errors.NotImplementedf("leader settings")
+	}
+	if ctx.leaderSettings == nil {
+		settings, err := ctx.state.LeadershipSettings.Read(ctx.unit.ServiceName())
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ctx.leaderSettings = make(map[string]string)
+		for key, value := range settings {
+			ctx.leaderSettings[key] = value
+		}
+	}
+	result := make(map[string]string)
+	for key, value := range ctx.leaderSettings {
+		result[key] = value
+	}
+	return result, nil
+}
+
+// WriteLeaderSettings merges the supplied settings into the service's
+// leader settings; keys with empty values are deleted. Unlike most
+// changes made by a hook, leader settings are written immediately.
+func (ctx *HookContext) WriteLeaderSettings(settings map[string]string) error {
+	if ctx.state.LeadershipSettings == nil {
+		return errors.NotImplementedf("leader settings")
+	}
+	err := ctx.state.LeadershipSettings.Merge(ctx.unit.ServiceName(), settings)
+	if params.IsCodeUnauthorized(err) {
+		return errors.Errorf("unit %q is not leader", ctx.unitName)
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	// Read the settings afresh when they are next asked for.
+	ctx.leaderSettings = nil
+	return nil
+}
+
 func (ctx *HookContext) OpenPorts(protocol string, fromPort, toPort int) error {
 	return tryOpenPorts(
 		protocol, fromPort, toPort,
@@ -465,6 +581,32 @@ func (ctx *HookContext) FlushContext(process string, ctxErr error) (err error) {
 			}
 		}
 	}
+
+	if writeChanges && ctx.pendingUnitStatus != nil {
+		status := ctx.pendingUnitStatus
+		if e := ctx.unit.SetWorkloadStatus(params.Status(status.Status), status.Info, status.Data); e != nil {
+			e = errors.Annotate(e, "cannot set workload status")
+			logger.Errorf("%v", e)
+			if ctxErr == nil {
+				ctxErr = e
+			}
+		}
+	}
+
+	if writeChanges && ctx.pendingServiceStatus != nil {
+		status := ctx.pendingServiceStatus
+		service, e := ctx.unit.Service()
+		if e == nil {
+			e = service.SetStatus(params.Status(status.Status), status.Info, status.Data)
+		}
+		if e != nil {
+			e = errors.Annotate(e, "cannot set service status")
+			logger.Errorf("%v", e)
+			if ctxErr == nil {
+				ctxErr = e
+			}
+		}
+	}
 	if ctxErr != nil {
 		return ctxErr
 	}
@@ -512,6 +654,10 @@ func (ctx *HookContext) finalizeAction(err, unhandledErr error) error {
 			message = fmt.Sprintf("action not implemented on unit %q", ctx.unitName)
 		}
 		status = params.ActionFailed
+		// Keep the failure visible to the uniter, which records it
+		// in the unit's hook history.
+		ctx.actionData.ActionFailed = true
+		ctx.actionData.ResultsMessage = message
 	}
 
 	callErr := ctx.state.ActionFinish(tag, status, results, message)
@@ -7,6 +7,7 @@ import (
 	"os"
 	"syscall"
 
+	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils"
 	gc "gopkg.in/check.v1"
@@ -114,6 +115,70 @@ func (s *InterfaceSuite) TestConfigCaching(c *gc.C) {
 	c.Assert(settings, gc.DeepEquals, charm.Settings{"blog-title": "My Title"})
 }
 
+func (s *InterfaceSuite) TestSetServiceStatusChecksLeadership(c *gc.C) {
+	ctx := s.GetContext(c, -1, "").(*runner.HookContext)
+	status := jujuc.StatusInfo{Status: "blocked", Info: "need a database"}
+
+	runner.SetLeadershipManager(ctx, &leadershipStub{})
+	err := ctx.SetServiceStatus(status)
+	c.Assert(err, gc.ErrorMatches, `unit "u/0" is not leader`)
+
+	runner.SetLeadershipManager(ctx, &leadershipStub{err: errors.New("boom")})
+	err = ctx.SetServiceStatus(status)
+	c.Assert(err, gc.ErrorMatches, "cannot determine leadership: boom")
+
+	runner.SetLeadershipManager(ctx, &leadershipStub{leader: true})
+	err = ctx.SetServiceStatus(status)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *InterfaceSuite) TestIsLeader(c *gc.C) {
+	ctx := s.GetContext(c, -1, "").(*runner.HookContext)
+	for i, t := range []struct {
+		stub   *leadershipStub
+		leader bool
+		err    string
+	}{
+		{&leadershipStub{leader: true}, true, ""},
+		{&leadershipStub{}, false, ""},
+		{&leadershipStub{err: errors.New("boom")}, false, "boom"},
+	} {
+		c.Logf("test %d", i)
+		runner.SetLeadershipManager(ctx, t.stub)
+		leader, err := ctx.IsLeader()
+		if t.err == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.ErrorMatches, t.err)
+		}
+		c.Check(leader, gc.Equals, t.leader)
+	}
+}
+
+func (s *InterfaceSuite) TestLeaderSettingsCaching(c *gc.C) {
+	s.setLeaderSettings(c, map[string]interface{}{"foo": "bar"})
+	ctx := s.GetContext(c, -1, "")
+	settings, err := ctx.LeaderSettings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, gc.DeepEquals, map[string]string{"foo": "bar"})
+
+	// Change remote settings.
+	s.setLeaderSettings(c, map[string]interface{}{"foo": "baz"})
+
+	// Local view is not changed.
+	settings, err = ctx.LeaderSettings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, gc.DeepEquals, map[string]string{"foo": "bar"})
+}
+
+func (s *InterfaceSuite) setLeaderSettings(c *gc.C, values map[string]interface{}) {
+	settings, err := s.State.ReadLeadershipSettings(s.service.Name())
+	c.Assert(err, jc.ErrorIsNil)
+	settings.Update(values)
+	_, err = settings.Write()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 // TestNonActionCallsToActionMethodsFail does exactly what its name says:
 // it simply makes sure that Action-related calls to HookContexts with a nil
 // actionData member error out correctly.
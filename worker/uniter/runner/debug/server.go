@@ -33,9 +33,12 @@ var waitClientExit = func(s *ServerSession) {
 	exec.Command("flock", path, "-c", "true").Run()
 }
 
-// RunHook "runs" the hook with the specified name via debug-hooks.
-func (s *ServerSession) RunHook(hookName, charmDir string, env []string) error {
-	env = append(env, "JUJU_HOOK_NAME="+hookName)
+// RunHook "runs" the hook or action with the specified name via
+// debug-hooks. If hookPath is not empty, it holds the path of the
+// charm's implementation of the hook, which may be run from within
+// the session with "run-hook".
+func (s *ServerSession) RunHook(hookName, hookPath, charmDir string, env []string) error {
+	env = append(env, "JUJU_HOOK_NAME="+hookName, "JUJU_DEBUG_HOOK_PATH="+hookPath)
 	cmd := exec.Command("/bin/bash", "-s")
 	cmd.Env = env
 	cmd.Dir = charmDir
@@ -95,6 +98,8 @@ export | grep -v $FILTER > $JUJU_DEBUG/env.sh
 cat > $JUJU_DEBUG/welcome.msg <<END
 This is a Juju debug-hooks tmux session. Remember:
 1. You need to execute hooks manually if you want them to run for trapped events.
+   Run 'run-hook' to execute the charm's own hook or action for this event, as
+   often as you like; it runs with the same environment as Juju would use.
 2. When you are finished with an event, you can run 'exit' to close the current window and allow Juju to continue running.
 
 More help and info is available in the online documentation:
@@ -105,6 +110,15 @@ END
 cat > $JUJU_DEBUG/init.sh <<END
 #!/bin/bash
 cat $JUJU_DEBUG/welcome.msg
+
+# Run the charm's implementation of the trapped hook or action.
+run-hook() {
+    if [ -z "\$JUJU_DEBUG_HOOK_PATH" ]; then
+        echo "\$JUJU_HOOK_NAME is not implemented by the charm" >&2
+        return 1
+    fi
+    "\$JUJU_DEBUG_HOOK_PATH" "\$@"
+}
 END
 chmod +x $JUJU_DEBUG/init.sh
 
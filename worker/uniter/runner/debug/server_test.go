@@ -113,7 +113,7 @@ func (s *DebugHooksServerSuite) TestRunHookExceptional(c *gc.C) {
 	s.PatchValue(&waitClientExit, func(*ServerSession) {
 		flockAcquired <- struct{}{}
 	})
-	err = session.RunHook("myhook", s.tmpdir, os.Environ())
+	err = session.RunHook("myhook", "", s.tmpdir, os.Environ())
 	c.Assert(err, gc.ErrorMatches, "signal: [kK]illed")
 	waitForFlock()
 
@@ -129,7 +129,7 @@ func (s *DebugHooksServerSuite) TestRunHookExceptional(c *gc.C) {
 		flockAcquired <- struct{}{}
 	})
 	go func() { ch <- true }() // asynchronously release the flock
-	err = session.RunHook("myhook", s.tmpdir, os.Environ())
+	err = session.RunHook("myhook", "", s.tmpdir, os.Environ())
 	waitForFlock()
 	c.Assert(clientExited, jc.IsTrue)
 	c.Assert(err, gc.ErrorMatches, "signal: [kK]illed")
@@ -143,6 +143,7 @@ func (s *DebugHooksServerSuite) TestRunHook(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 
 	const hookName = "myhook"
+	hookPath := filepath.Join(s.tmpdir, "hooks", hookName)
 
 	// Run the hook in debug mode with the exit flock held,
 	// and also create the .pid file. We'll populate it with
@@ -152,7 +153,7 @@ func (s *DebugHooksServerSuite) TestRunHook(c *gc.C) {
 	c.Assert(cmd.Start(), gc.IsNil)
 	ch := make(chan error)
 	go func() {
-		ch <- session.RunHook(hookName, s.tmpdir, os.Environ())
+		ch <- session.RunHook(hookName, hookPath, s.tmpdir, os.Environ())
 	}()
 
 	// Wait until either we find the debug dir, or the flock is released.
@@ -192,6 +193,9 @@ func (s *DebugHooksServerSuite) TestRunHook(c *gc.C) {
 
 	envsh := filepath.Join(s.tmpdir, debugdir.Name(), "env.sh")
 	s.verifyEnvshFile(c, envsh, hookName)
+	c.Assert(s.readFile(c, envsh), jc.Contains, fmt.Sprintf("JUJU_DEBUG_HOOK_PATH=%q", hookPath))
+	initsh := filepath.Join(s.tmpdir, debugdir.Name(), "init.sh")
+	c.Assert(s.readFile(c, initsh), jc.Contains, "run-hook() {")
 
 	hookpid := filepath.Join(s.tmpdir, debugdir.Name(), "hook.pid")
 	err = ioutil.WriteFile(hookpid, []byte("not a pid"), 0777)
@@ -204,10 +208,14 @@ func (s *DebugHooksServerSuite) TestRunHook(c *gc.C) {
 	cmd.Process.Kill() // kill flock
 }
 
-func (s *DebugHooksServerSuite) verifyEnvshFile(c *gc.C, envshPath string, hookName string) {
-	data, err := ioutil.ReadFile(envshPath)
+func (s *DebugHooksServerSuite) readFile(c *gc.C, path string) string {
+	data, err := ioutil.ReadFile(path)
 	c.Assert(err, jc.ErrorIsNil)
-	contents := string(data)
+	return string(data)
+}
+
+func (s *DebugHooksServerSuite) verifyEnvshFile(c *gc.C, envshPath string, hookName string) {
+	contents := s.readFile(c, envshPath)
 	c.Assert(contents, jc.Contains, fmt.Sprintf("JUJU_UNIT_NAME=%q", s.ctx.Unit))
 	c.Assert(contents, jc.Contains, fmt.Sprintf("JUJU_HOOK_NAME=%q", hookName))
 	c.Assert(contents, jc.Contains, fmt.Sprintf(`PS1="%s:%s %% "`, s.ctx.Unit, hookName))
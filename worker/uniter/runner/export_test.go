@@ -6,11 +6,14 @@ package runner
 import (
 	"github.com/juju/errors"
 	"github.com/juju/names"
+	utilexec "github.com/juju/utils/exec"
 	"github.com/juju/utils/proxy"
 	"gopkg.in/juju/charm.v4"
 
 	"github.com/juju/juju/api/uniter"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/leadership"
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
 )
 
 var (
@@ -21,6 +24,10 @@ var (
 	ValidatePortRange = validatePortRange
 	TryOpenPorts      = tryOpenPorts
 	TryClosePorts     = tryClosePorts
+	SetProcessGroup   = setProcessGroup
+	WaitHook          = waitHook
+
+	DebugSessionPollInterval = &debugSessionPollInterval
 )
 
 func RunnerPaths(rnr Runner) Paths {
@@ -66,6 +73,10 @@ func ContextMachineTag(ctx Context) names.MachineTag {
 	return hctx.assignedMachineTag
 }
 
+func SetLeadershipManager(ctx *HookContext, leadershipManager leadership.LeadershipManager) {
+	ctx.leadership = leadershipManager
+}
+
 func GetStubActionContext(in map[string]interface{}) *HookContext {
 	return &HookContext{
 		actionData: &ActionData{
@@ -180,3 +191,7 @@ func SetEnvironmentHookContextRelation(
 		},
 	}
 }
+
+func ObserveHookTool(ctx *OfflineContext, req jujuc.Request, resp *utilexec.ExecResponse) {
+	ctx.observeHookTool(req, resp)
+}
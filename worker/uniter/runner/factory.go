@@ -13,8 +13,10 @@ import (
 	"gopkg.in/juju/charm.v4"
 	"gopkg.in/juju/charm.v4/hooks"
 
+	"github.com/juju/juju/actions"
 	"github.com/juju/juju/api/uniter"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/leadership"
 	"github.com/juju/juju/worker/uniter/hook"
 )
 
@@ -51,10 +53,11 @@ type Factory interface {
 type RelationsFunc func() map[int]*RelationInfo
 
 // NewFactory returns a Factory capable of creating execution contexts backed
-// by the supplied unit's supplied API connection.
+// by the supplied unit's supplied API connection and leadership manager.
 func NewFactory(
 	state *uniter.State,
 	unitTag names.UnitTag,
+	leadershipManager leadership.LeadershipManager,
 	getRelationInfos RelationsFunc,
 	paths Paths,
 ) (
@@ -83,6 +86,7 @@ func NewFactory(
 	return &factory{
 		unit:             unit,
 		state:            state,
+		leadership:       leadershipManager,
 		paths:            paths,
 		envUUID:          environment.UUID(),
 		envName:          environment.Name(),
@@ -96,8 +100,9 @@ func NewFactory(
 
 type factory struct {
 	// API connection fields; unit should be deprecated, but isn't yet.
-	unit  *uniter.Unit
-	state *uniter.State
+	unit       *uniter.Unit
+	state      *uniter.State
+	leadership leadership.LeadershipManager
 
 	// Fields that shouldn't change in a factory's lifetime.
 	paths      Paths
@@ -137,45 +142,54 @@ func (f *factory) NewHookRunner(hookInfo hook.Info) (Runner, error) {
 		return nil, errors.Trace(err)
 	}
 
-	ctx, err := f.coreContext()
-	if err != nil {
-		return nil, errors.Trace(err)
-	}
-
-	hookName := string(hookInfo.Kind)
-	if hookInfo.Kind.IsRelation() {
-		ctx.relationId = hookInfo.RelationId
-		ctx.remoteUnitName = hookInfo.RemoteUnit
-		relation, found := ctx.relations[hookInfo.RelationId]
-		if !found {
-			return nil, errors.Errorf("unknown relation id: %v", hookInfo.RelationId)
-		}
-		if hookInfo.Kind == hooks.RelationDeparted {
-			relation.cache.RemoveMember(hookInfo.RemoteUnit)
-		} else if hookInfo.RemoteUnit != "" {
-			// Clear remote settings cache for changing remote unit.
-			relation.cache.InvalidateMember(hookInfo.RemoteUnit)
-		}
-		hookName = fmt.Sprintf("%s-%s", relation.Name(), hookInfo.Kind)
-	}
-	if hookInfo.Kind.IsStorage() {
-		ctx.storageId = hookInfo.StorageId
-		if err := f.updateStorage(ctx); err != nil {
+	// A hook that is run again in a debug-hooks session gets a fresh
+	// context, made the same way.
+	return f.newRunner(func() (*HookContext, error) {
+		ctx, err := f.coreContext()
+		if err != nil {
 			return nil, errors.Trace(err)
 		}
-	}
-	// Metrics are only sent from the collect-metrics hook.
-	if hookInfo.Kind == hooks.CollectMetrics {
-		ctx.canAddMetrics = true
-		ch, err := f.getCharm()
-		if err != nil {
+		// State servers that can't tell us how long hooks may run will
+		// let them run for as long as they need.
+		ctx.hookTimeout, err = f.unit.HookTimeout()
+		if err != nil && !errors.IsNotImplemented(err) {
 			return nil, errors.Trace(err)
 		}
-		ctx.definedMetrics = ch.Metrics()
-	}
-	ctx.id = f.newId(hookName)
-	runner := NewRunner(ctx, f.paths)
-	return runner, nil
+
+		hookName := string(hookInfo.Kind)
+		if hookInfo.Kind.IsRelation() {
+			ctx.relationId = hookInfo.RelationId
+			ctx.remoteUnitName = hookInfo.RemoteUnit
+			relation, found := ctx.relations[hookInfo.RelationId]
+			if !found {
+				return nil, errors.Errorf("unknown relation id: %v", hookInfo.RelationId)
+			}
+			if hookInfo.Kind == hooks.RelationDeparted {
+				relation.cache.RemoveMember(hookInfo.RemoteUnit)
+			} else if hookInfo.RemoteUnit != "" {
+				// Clear remote settings cache for changing remote unit.
+				relation.cache.InvalidateMember(hookInfo.RemoteUnit)
+			}
+			hookName = fmt.Sprintf("%s-%s", relation.Name(), hookInfo.Kind)
+		}
+		if hookInfo.Kind.IsStorage() {
+			ctx.storageId = hookInfo.StorageId
+			if err := f.updateStorage(ctx); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		// Metrics are only sent from the collect-metrics hook.
+		if hookInfo.Kind == hooks.CollectMetrics {
+			ctx.canAddMetrics = true
+			ch, err := f.getCharm()
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			ctx.definedMetrics = ch.Metrics()
+		}
+		ctx.id = f.newId(hookName)
+		return ctx, nil
+	})
 }
 
 // NewActionRunner exists to satisfy the Factory interface.
@@ -205,23 +219,48 @@ func (f *factory) NewActionRunner(actionId string) (Runner, error) {
 	}
 
 	name := action.Name()
-	spec, ok := ch.Actions().ActionSpecs[name]
+	spec, ok := actions.PredefinedActionsSpec[name]
 	if !ok {
-		return nil, &badActionError{name, "not defined"}
+		spec, ok = ch.Actions().ActionSpecs[name]
+		if !ok {
+			return nil, &badActionError{name, "not defined"}
+		}
 	}
 	params := action.Params()
 	if _, err := spec.ValidateParams(params); err != nil {
 		return nil, &badActionError{name, err.Error()}
 	}
 
-	ctx, err := f.coreContext()
+	return f.newRunner(func() (*HookContext, error) {
+		ctx, err := f.coreContext()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ctx.actionData = newActionData(name, &tag, params)
+		ctx.id = f.newId(name)
+		return ctx, nil
+	})
+}
+
+// newRunner returns a Runner backed by the context returned by
+// newContext, which is also used to make a fresh context when the
+// runner runs a hook or action again in a debug-hooks session.
+func (f *factory) newRunner(newContext func() (*HookContext, error)) (Runner, error) {
+	ctx, err := newContext()
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	ctx.actionData = newActionData(name, &tag, params)
-	ctx.id = f.newId(name)
-	runner := NewRunner(ctx, f.paths)
-	return runner, nil
+	return &runner{
+		context: ctx,
+		paths:   f.paths,
+		newContext: func() (Context, error) {
+			ctx, err := newContext()
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			return ctx, nil
+		},
+	}, nil
 }
 
 // newId returns a probably-unique identifier for a new context, containing the
@@ -235,6 +274,7 @@ func (f *factory) coreContext() (*HookContext, error) {
 	ctx := &HookContext{
 		unit:               f.unit,
 		state:              f.state,
+		leadership:         f.leadership,
 		uuid:               f.envUUID,
 		envName:            f.envName,
 		unitName:           f.unit.Name(),
@@ -277,7 +317,9 @@ func (f *factory) getContextRelations() map[int]*ContextRelation {
 			cache = NewRelationCache(relationUnit.ReadSettings, memberNames)
 		}
 		relationCaches[id] = cache
-		contextRelations[id] = NewContextRelation(relationUnit, cache)
+		contextRelation := NewContextRelation(relationUnit, cache)
+		contextRelation.previousSettings = info.PreviousSettings
+		contextRelations[id] = contextRelation
 	}
 	f.relationCaches = relationCaches
 	return contextRelations
@@ -16,6 +16,7 @@ import (
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/charm.v4/hooks"
 
+	"github.com/juju/juju/actions"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/juju/osenv"
 	"github.com/juju/juju/state"
@@ -30,6 +31,7 @@ type FactorySuite struct {
 	paths      RealPaths
 	factory    runner.Factory
 	membership map[int][]string
+	previous   map[int]map[string]params.Settings
 }
 
 var _ = gc.Suite(&FactorySuite{})
@@ -40,9 +42,11 @@ func (s *FactorySuite) SetUpTest(c *gc.C) {
 	s.HookContextSuite.SetUpTest(c)
 	s.paths = NewRealPaths(c)
 	s.membership = map[int][]string{}
+	s.previous = map[int]map[string]params.Settings{}
 	factory, err := runner.NewFactory(
 		s.uniter,
 		s.unit.Tag().(names.UnitTag),
+		&leadershipStub{},
 		s.getRelationInfos,
 		s.paths,
 	)
@@ -61,8 +65,9 @@ func (s *FactorySuite) getRelationInfos() map[int]*runner.RelationInfo {
 	info := map[int]*runner.RelationInfo{}
 	for relId, relUnit := range s.apiRelunits {
 		info[relId] = &runner.RelationInfo{
-			RelationUnit: relUnit,
-			MemberNames:  s.membership[relId],
+			RelationUnit:     relUnit,
+			MemberNames:      s.membership[relId],
+			PreviousSettings: s.previous[relId],
 		}
 	}
 	return info
@@ -286,6 +291,7 @@ func (s *FactorySuite) TestNewHookRunnerWithStorage(c *gc.C) {
 	factory, err := runner.NewFactory(
 		uniter,
 		unit.Tag().(names.UnitTag),
+		&leadershipStub{},
 		s.getRelationInfos,
 		s.paths,
 	)
@@ -413,6 +419,24 @@ func (s *FactorySuite) TestNewHookRunnerRelationChangedUpdatesRelationContextAnd
 	c.Assert(member, jc.IsTrue)
 }
 
+func (s *FactorySuite) TestNewHookRunnerRelationChangedPreviousSettings(c *gc.C) {
+	s.membership[1] = []string{"r/0", "r/4"}
+	s.previous[1] = map[string]params.Settings{"r/4": {"baz": "qux"}}
+
+	rnr, err := s.factory.NewHookRunner(hook.Info{
+		Kind:       hooks.RelationChanged,
+		RelationId: 1,
+		RemoteUnit: "r/4",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	rel := s.AssertRelationContext(c, rnr.Context(), 1, "r/4")
+	previous, found := rel.ReadPreviousSettings("r/4")
+	c.Assert(found, jc.IsTrue)
+	c.Assert(previous, jc.DeepEquals, params.Settings{"baz": "qux"})
+	_, found = rel.ReadPreviousSettings("r/0")
+	c.Assert(found, jc.IsFalse)
+}
+
 func (s *FactorySuite) TestNewHookRunnerRelationDepartedUpdatesRelationContextAndCaches(c *gc.C) {
 	// Update member settings to have actual values, so we can check that
 	// the depart for r/0 leaves r/4's cache alone (while discarding r/0's).
@@ -546,6 +570,23 @@ func (s *FactorySuite) TestNewActionRunner(c *gc.C) {
 	})
 }
 
+func (s *FactorySuite) TestNewActionRunnerPredefinedAction(c *gc.C) {
+	s.SetCharm(c, "dummy")
+	payload := actions.JujuRunParams("hostname", time.Minute)
+	action, err := s.State.EnqueueAction(s.unit.Tag(), actions.JujuRunActionName, payload)
+	c.Assert(err, jc.ErrorIsNil)
+	rnr, err := s.factory.NewActionRunner(action.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	s.AssertPaths(c, rnr)
+	data, err := rnr.Context().ActionData()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(data.ActionName, gc.Equals, actions.JujuRunActionName)
+	commands, timeout, err := actions.ParseJujuRunParams(data.ActionParams)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(commands, gc.Equals, "hostname")
+	c.Assert(timeout, gc.Equals, time.Minute)
+}
+
 func (s *FactorySuite) TestNewActionRunnerBadName(c *gc.C) {
 	s.SetCharm(c, "dummy")
 	action, err := s.State.EnqueueAction(s.unit.Tag(), "no-such-action", nil)
@@ -12,6 +12,8 @@ import (
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/network"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
 )
 
 type FlushContextSuite struct {
@@ -87,6 +89,54 @@ func (s *FlushContextSuite) TestRunHookRelationFlushingSuccess(c *gc.C) {
 	})
 }
 
+func (s *FlushContextSuite) TestRunHookWorkloadStatusFlushingSuccess(c *gc.C) {
+	uuid, err := utils.NewUUID()
+	c.Assert(err, jc.ErrorIsNil)
+	ctx := s.getHookContext(c, uuid.String(), -1, "", noProxies)
+
+	status, err := ctx.UnitStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status.Status, gc.Equals, "unknown")
+
+	err = ctx.SetUnitStatus(jujuc.StatusInfo{
+		Status: "blocked",
+		Info:   "need a database",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	status, err = ctx.UnitStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status.Status, gc.Equals, "blocked")
+
+	// The status is not written to state until the context is flushed.
+	stateStatus, _, _, err := s.unit.WorkloadStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(stateStatus, gc.Equals, state.StatusUnknown)
+
+	err = ctx.FlushContext("some badge", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	stateStatus, info, _, err := s.unit.WorkloadStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(stateStatus, gc.Equals, state.StatusBlocked)
+	c.Assert(info, gc.Equals, "need a database")
+}
+
+func (s *FlushContextSuite) TestRunHookNoWorkloadStatusOnFailure(c *gc.C) {
+	uuid, err := utils.NewUUID()
+	c.Assert(err, jc.ErrorIsNil)
+	ctx := s.getHookContext(c, uuid.String(), -1, "", noProxies)
+
+	err = ctx.SetUnitStatus(jujuc.StatusInfo{Status: "running"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = ctx.FlushContext("some badge", errors.New("blam pow"))
+	c.Assert(err, gc.ErrorMatches, "blam pow")
+
+	stateStatus, _, _, err := s.unit.WorkloadStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(stateStatus, gc.Equals, state.StatusUnknown)
+}
+
 func (s *FlushContextSuite) TestRunHookMetricSendingSuccess(c *gc.C) {
 	uuid, err := utils.NewUUID()
 	c.Assert(err, jc.ErrorIsNil)
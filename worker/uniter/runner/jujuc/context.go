@@ -106,6 +106,41 @@ type Context interface {
 	// HookStorageInstance returns the storage instance associated
 	// the executing hook.
 	HookStorageInstance() (*storage.StorageInstance, bool)
+
+	// UnitStatus returns the status of the software run by the
+	// executing unit, as last reported by its charm.
+	UnitStatus() (*StatusInfo, error)
+
+	// SetUnitStatus records the status of the software run by the
+	// executing unit, to be set when the hook completes.
+	SetUnitStatus(StatusInfo) error
+
+	// SetServiceStatus records the status of the executing unit's
+	// service, to be set when the hook completes. Only the leader
+	// unit of the service is allowed to set it.
+	SetServiceStatus(StatusInfo) error
+
+	// IsLeader returns whether the executing unit is the leader of
+	// its service.
+	IsLeader() (bool, error)
+
+	// LeaderSettings returns the current leader settings of the
+	// executing unit's service.
+	LeaderSettings() (map[string]string, error)
+
+	// WriteLeaderSettings merges the supplied settings into the leader
+	// settings of the executing unit's service; keys with empty values
+	// are deleted. Only the leader unit of the service is allowed to
+	// write them.
+	WriteLeaderSettings(map[string]string) error
+}
+
+// StatusInfo is a record of the status of the software run by a
+// unit or a service.
+type StatusInfo struct {
+	Status string
+	Info   string
+	Data   map[string]interface{}
 }
 
 // ContextRelation expresses the capabilities of a hook with respect to a relation.
@@ -132,6 +167,11 @@ type ContextRelation interface {
 
 	// ReadSettings returns the settings of any remote unit in the relation.
 	ReadSettings(unit string) (params.Settings, error)
+
+	// ReadPreviousSettings returns the settings of a remote unit as seen
+	// by the last relation-changed hook run for it, and whether any such
+	// hook has run.
+	ReadPreviousSettings(unit string) (params.Settings, bool)
 }
 
 // Settings is implemented by types that manipulate unit settings.
@@ -0,0 +1,53 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+)
+
+type isLeaderSuite struct {
+	ContextSuite
+}
+
+var _ = gc.Suite(&isLeaderSuite{})
+
+var isLeaderTests = []struct {
+	isLeader bool
+	args     []string
+	out      string
+}{
+	{true, []string{}, "True\n"},
+	{false, []string{}, "False\n"},
+	{true, []string{"--format", "json"}, "true\n"},
+	{false, []string{"--format", "yaml"}, "false\n"},
+}
+
+func (s *isLeaderSuite) TestOutputFormat(c *gc.C) {
+	for i, t := range isLeaderTests {
+		c.Logf("test %d: %#v", i, t.args)
+		hctx := s.GetHookContext(c, -1, "")
+		hctx.isLeader = t.isLeader
+		com, err := jujuc.NewCommand(hctx, cmdString("is-leader"))
+		c.Assert(err, jc.ErrorIsNil)
+		ctx := testing.Context(c)
+		code := cmd.Main(com, ctx, t.args)
+		c.Assert(code, gc.Equals, 0)
+		c.Assert(bufferString(ctx.Stderr), gc.Equals, "")
+		c.Assert(bufferString(ctx.Stdout), gc.Equals, t.out)
+	}
+}
+
+func (s *isLeaderSuite) TestUnknownArgs(c *gc.C) {
+	hctx := s.GetHookContext(c, -1, "")
+	com, err := jujuc.NewCommand(hctx, cmdString("is-leader"))
+	c.Assert(err, jc.ErrorIsNil)
+	err = testing.InitCommand(com, []string{"blah"})
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["blah"\]`)
+}
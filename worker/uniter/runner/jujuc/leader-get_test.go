@@ -0,0 +1,54 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+)
+
+type leaderGetSuite struct {
+	ContextSuite
+}
+
+var _ = gc.Suite(&leaderGetSuite{})
+
+var leaderGetTests = []struct {
+	args []string
+	out  string
+}{
+	{[]string{"foo"}, "bar\n"},
+	{[]string{"missing"}, ""},
+	{[]string{"foo", "--format", "json"}, `"bar"` + "\n"},
+	{[]string{}, "baz: qux\nfoo: bar\n"},
+	{[]string{"-"}, "baz: qux\nfoo: bar\n"},
+	{[]string{"--format", "json"}, `{"baz":"qux","foo":"bar"}` + "\n"},
+}
+
+func (s *leaderGetSuite) TestOutputFormat(c *gc.C) {
+	for i, t := range leaderGetTests {
+		c.Logf("test %d: %#v", i, t.args)
+		hctx := s.GetHookContext(c, -1, "")
+		hctx.leaderSettings = map[string]string{"foo": "bar", "baz": "qux"}
+		com, err := jujuc.NewCommand(hctx, cmdString("leader-get"))
+		c.Assert(err, jc.ErrorIsNil)
+		ctx := testing.Context(c)
+		code := cmd.Main(com, ctx, t.args)
+		c.Assert(code, gc.Equals, 0)
+		c.Assert(bufferString(ctx.Stderr), gc.Equals, "")
+		c.Assert(bufferString(ctx.Stdout), gc.Equals, t.out)
+	}
+}
+
+func (s *leaderGetSuite) TestUnknownArgs(c *gc.C) {
+	hctx := s.GetHookContext(c, -1, "")
+	com, err := jujuc.NewCommand(hctx, cmdString("leader-get"))
+	c.Assert(err, jc.ErrorIsNil)
+	err = testing.InitCommand(com, []string{"foo", "blah"})
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["blah"\]`)
+}
@@ -0,0 +1,47 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/utils/keyvalues"
+)
+
+// LeaderSetCommand implements the leader-set command.
+type LeaderSetCommand struct {
+	cmd.CommandBase
+	ctx      Context
+	settings map[string]string
+}
+
+// NewLeaderSetCommand makes a jujuc leader-set command.
+func NewLeaderSetCommand(ctx Context) cmd.Command {
+	return &LeaderSetCommand{ctx: ctx}
+}
+
+func (c *LeaderSetCommand) Info() *cmd.Info {
+	doc := `
+leader-set immediately writes the key/value pairs to the service's leadership
+settings; a key with an empty value is deleted. It will fail if the local unit
+is not the leader. Other units of the service will see the new settings in
+their next leader-settings-changed hook.
+`
+	return &cmd.Info{
+		Name:    "leader-set",
+		Args:    "<key>=<value> [...]",
+		Purpose: "write service leadership settings",
+		Doc:     doc,
+	}
+}
+
+func (c *LeaderSetCommand) Init(args []string) (err error) {
+	c.settings, err = keyvalues.Parse(args, true)
+	return
+}
+
+func (c *LeaderSetCommand) Run(_ *cmd.Context) error {
+	err := c.ctx.WriteLeaderSettings(c.settings)
+	return errors.Annotate(err, "cannot write leadership settings")
+}
@@ -0,0 +1,54 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+)
+
+type leaderSetSuite struct {
+	ContextSuite
+}
+
+var _ = gc.Suite(&leaderSetSuite{})
+
+func (s *leaderSetSuite) TestInitError(c *gc.C) {
+	hctx := s.GetHookContext(c, -1, "")
+	com, err := jujuc.NewCommand(hctx, cmdString("leader-set"))
+	c.Assert(err, jc.ErrorIsNil)
+	err = testing.InitCommand(com, []string{"foo"})
+	c.Assert(err, gc.ErrorMatches, `expected "key=value", got "foo"`)
+}
+
+func (s *leaderSetSuite) TestWriteSettings(c *gc.C) {
+	hctx := s.GetHookContext(c, -1, "")
+	hctx.isLeader = true
+	hctx.leaderSettings = map[string]string{"foo": "bar", "baz": "qux"}
+	com, err := jujuc.NewCommand(hctx, cmdString("leader-set"))
+	c.Assert(err, jc.ErrorIsNil)
+	ctx := testing.Context(c)
+	code := cmd.Main(com, ctx, []string{"foo=", "new=value"})
+	c.Assert(code, gc.Equals, 0)
+	c.Assert(bufferString(ctx.Stderr), gc.Equals, "")
+	c.Assert(hctx.leaderSettings, jc.DeepEquals, map[string]string{
+		"baz": "qux",
+		"new": "value",
+	})
+}
+
+func (s *leaderSetSuite) TestNotLeader(c *gc.C) {
+	hctx := s.GetHookContext(c, -1, "")
+	com, err := jujuc.NewCommand(hctx, cmdString("leader-set"))
+	c.Assert(err, jc.ErrorIsNil)
+	ctx := testing.Context(c)
+	code := cmd.Main(com, ctx, []string{"foo=bar"})
+	c.Assert(code, gc.Equals, 1)
+	c.Assert(bufferString(ctx.Stderr), gc.Equals, "error: cannot write leadership settings: not the leader\n")
+	c.Assert(hctx.leaderSettings, gc.HasLen, 0)
+}
@@ -19,6 +19,7 @@ type RelationGetCommand struct {
 	RelationId int
 	Key        string
 	UnitName   string
+	Changed    bool
 	out        cmd.Output
 }
 
@@ -31,6 +32,12 @@ func (c *RelationGetCommand) Info() *cmd.Info {
 	doc := `
 relation-get prints the value of a unit's relation setting, specified by key.
 If no key is given, or if the key is "-", all keys and values will be printed.
+
+With --changed, only settings whose values have changed since the last
+relation-changed hook for the remote unit are considered, and their previous
+values are printed instead; settings that were not previously set are printed
+with null values. If no relation-changed hook has yet run for the remote unit,
+all its settings are considered changed.
 `
 	if name, found := c.ctx.RemoteUnitName(); found {
 		args = "[<key> [<unit id>]]"
@@ -50,6 +57,7 @@ func (c *RelationGetCommand) SetFlags(f *gnuflag.FlagSet) {
 	c.out.AddFlags(f, "smart", cmd.DefaultFormatters)
 	f.Var(rV, "r", "specify a relation by id")
 	f.Var(rV, "relation", "")
+	f.BoolVar(&c.Changed, "changed", false, "print previous values of changed settings")
 }
 
 func (c *RelationGetCommand) Init(args []string) error {
@@ -73,6 +81,9 @@ func (c *RelationGetCommand) Init(args []string) error {
 	if c.UnitName == "" {
 		return fmt.Errorf("no unit id specified")
 	}
+	if c.Changed && c.UnitName == c.ctx.UnitName() {
+		return fmt.Errorf("--changed cannot be used with the local unit")
+	}
 	return cmd.CheckEmpty(args)
 }
 
@@ -95,6 +106,14 @@ func (c *RelationGetCommand) Run(ctx *cmd.Context) error {
 			return err
 		}
 	}
+	if c.Changed {
+		previous, _ := r.ReadPreviousSettings(c.UnitName)
+		changed := changedSettings(previous, settings)
+		if c.Key == "" {
+			return c.out.Write(ctx, changed)
+		}
+		return c.out.Write(ctx, changed[c.Key])
+	}
 	if c.Key == "" {
 		return c.out.Write(ctx, settings)
 	}
@@ -103,3 +122,23 @@ func (c *RelationGetCommand) Run(ctx *cmd.Context) error {
 	}
 	return c.out.Write(ctx, nil)
 }
+
+// changedSettings returns the previous values of the settings whose values
+// differ between previous and current. Settings missing from previous have
+// nil values.
+func changedSettings(previous, current params.Settings) map[string]interface{} {
+	changed := map[string]interface{}{}
+	for key, value := range current {
+		if oldValue, ok := previous[key]; !ok {
+			changed[key] = nil
+		} else if oldValue != value {
+			changed[key] = oldValue
+		}
+	}
+	for key, oldValue := range previous {
+		if _, ok := current[key]; !ok {
+			changed[key] = oldValue
+		}
+	}
+	return changed
+}
@@ -28,6 +28,10 @@ func (s *RelationGetSuite) SetUpTest(c *gc.C) {
 	s.rels[0].units["u/0"]["private-address"] = "foo: bar\n"
 	s.rels[1].units["m/0"] = Settings{"pew": "pew\npew\n"}
 	s.rels[1].units["u/1"] = Settings{"value": "12345"}
+	s.rels[1].units["c/0"] = Settings{"same": "1", "changed": "new", "added": "3"}
+	s.rels[1].previous = map[string]Settings{
+		"c/0": {"same": "1", "changed": "old", "removed": "4"},
+	}
 }
 
 var relationGetTests = []struct {
@@ -135,6 +139,32 @@ var relationGetTests = []struct {
 		relid:   1,
 		args:    []string{"missing", "u/1", "--format", "smart"},
 		out:     "",
+	}, {
+		summary: "changed keys with implicit member",
+		relid:   1,
+		unit:    "c/0",
+		args:    []string{"--changed"},
+		out:     "added: null\nchanged: old\nremoved: \"4\"",
+	}, {
+		summary: "changed key with explicit member",
+		relid:   1,
+		args:    []string{"--changed", "changed", "c/0"},
+		out:     "old",
+	}, {
+		summary: "unchanged key",
+		relid:   1,
+		args:    []string{"--changed", "same", "c/0"},
+	}, {
+		summary: "changed keys without previous settings",
+		relid:   1,
+		args:    []string{"--changed", "-", "u/1"},
+		out:     "value: null",
+	}, {
+		summary: "changed keys with explicit local",
+		relid:   0,
+		args:    []string{"--changed", "-", "u/0"},
+		code:    2,
+		out:     "--changed cannot be used with the local unit",
 	},
 }
 
@@ -218,6 +248,8 @@ usage: %s
 purpose: get relation settings
 
 options:
+--changed  (= false)
+    print previous values of changed settings
 --format  (= smart)
     specify output format (json|smart|yaml)
 -o, --output (= "")
@@ -227,6 +259,12 @@ options:
 
 relation-get prints the value of a unit's relation setting, specified by key.
 If no key is given, or if the key is "-", all keys and values will be printed.
+
+With --changed, only settings whose values have changed since the last
+relation-changed hook for the remote unit are considered, and their previous
+values are printed instead; settings that were not previously set are printed
+with null values. If no relation-changed hook has yet run for the remote unit,
+all its settings are considered changed.
 %s`[1:]
 
 var relationGetHelpTests = []struct {
@@ -44,6 +44,11 @@ var newCommands = map[string]func(Context) cmd.Command{
 	"owner-get" + cmdSuffix:     NewOwnerGetCommand,
 	"add-metric" + cmdSuffix:    NewAddMetricCommand,
 	"juju-reboot" + cmdSuffix:   NewJujuRebootCommand,
+	"status-get" + cmdSuffix:    NewStatusGetCommand,
+	"status-set" + cmdSuffix:    NewStatusSetCommand,
+	"is-leader" + cmdSuffix:     NewIsLeaderCommand,
+	"leader-get" + cmdSuffix:    NewLeaderGetCommand,
+	"leader-set" + cmdSuffix:    NewLeaderSetCommand,
 }
 
 var storageCommands = map[string]func(Context) cmd.Command{
@@ -89,10 +94,14 @@ type Request struct {
 // CmdGetter looks up a Command implementation connected to a particular Context.
 type CmdGetter func(contextId, cmdName string) (cmd.Command, error)
 
+// CmdObserver is told about each Command run by a Server, and its result.
+type CmdObserver func(req Request, resp *exec.ExecResponse)
+
 // Jujuc implements the jujuc command in the form required by net/rpc.
 type Jujuc struct {
-	mu     sync.Mutex
-	getCmd CmdGetter
+	mu      sync.Mutex
+	getCmd  CmdGetter
+	observe CmdObserver
 }
 
 // badReqErrorf returns an error indicating a bad Request.
@@ -127,6 +136,9 @@ func (j *Jujuc) Main(req Request, resp *exec.ExecResponse) error {
 	resp.Code = cmd.Main(c, ctx, req.Args)
 	resp.Stdout = stdout.Bytes()
 	resp.Stderr = stderr.Bytes()
+	if j.observe != nil {
+		j.observe(req, resp)
+	}
 	return nil
 }
 
@@ -145,8 +157,14 @@ type Server struct {
 // remote command invocations against an appropriate Context. It will not
 // actually do so until Run is called.
 func NewServer(getCmd CmdGetter, socketPath string) (*Server, error) {
+	return NewObservedServer(getCmd, nil, socketPath)
+}
+
+// NewObservedServer acts like NewServer, but the returned server also
+// calls observe, if not nil, after running each command.
+func NewObservedServer(getCmd CmdGetter, observe CmdObserver, socketPath string) (*Server, error) {
 	server := rpc.NewServer()
-	if err := server.Register(&Jujuc{getCmd: getCmd}); err != nil {
+	if err := server.Register(&Jujuc{getCmd: getCmd, observe: observe}); err != nil {
 		return nil, err
 	}
 	listener, err := sockets.Listen(socketPath)
@@ -77,6 +77,7 @@ type ServerSuite struct {
 	server   *jujuc.Server
 	sockPath string
 	err      chan error
+	observed []jujuc.Request
 }
 
 var _ = gc.Suite(&ServerSuite{})
@@ -84,7 +85,11 @@ var _ = gc.Suite(&ServerSuite{})
 func (s *ServerSuite) SetUpTest(c *gc.C) {
 	s.BaseSuite.SetUpTest(c)
 	s.sockPath = filepath.Join(c.MkDir(), "test.sock")
-	srv, err := jujuc.NewServer(factory, s.sockPath)
+	s.observed = nil
+	observe := func(req jujuc.Request, resp *exec.ExecResponse) {
+		s.observed = append(s.observed, req)
+	}
+	srv, err := jujuc.NewObservedServer(factory, observe, s.sockPath)
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(srv, gc.NotNil)
 	s.server = srv
@@ -122,6 +127,19 @@ func (s *ServerSuite) TestHappyPath(c *gc.C) {
 	c.Assert(string(content), gc.Equals, "something")
 }
 
+func (s *ServerSuite) TestObserver(c *gc.C) {
+	dir := c.MkDir()
+	req := jujuc.Request{"validCtx", dir, "remote", []string{"--value", "error"}}
+	resp, err := s.Call(c, req)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(resp.Code, gc.Equals, 1)
+	_, err = s.Call(c, jujuc.Request{"whatever", dir, "remote", nil})
+	c.Assert(err, gc.ErrorMatches, `bad request: unknown context "whatever"`)
+
+	// Only commands that were run are observed.
+	c.Assert(s.observed, jc.DeepEquals, []jujuc.Request{req})
+}
+
 func (s *ServerSuite) TestLocks(c *gc.C) {
 	var wg sync.WaitGroup
 	t0 := time.Now()
@@ -0,0 +1,64 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+)
+
+// StatusGetCommand implements the status-get command.
+type StatusGetCommand struct {
+	cmd.CommandBase
+	ctx         Context
+	includeData bool
+	out         cmd.Output
+}
+
+// NewStatusGetCommand makes a jujuc status-get command.
+func NewStatusGetCommand(ctx Context) cmd.Command {
+	return &StatusGetCommand{ctx: ctx}
+}
+
+func (c *StatusGetCommand) Info() *cmd.Info {
+	doc := `
+By default, only the status value is printed.
+If the --include-data flag is passed, the associated data are printed also.
+`
+	return &cmd.Info{
+		Name:    "status-get",
+		Args:    "[--include-data]",
+		Purpose: "print status information",
+		Doc:     doc,
+	}
+}
+
+func (c *StatusGetCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "smart", cmd.DefaultFormatters)
+	f.BoolVar(&c.includeData, "include-data", false, "print all status data")
+}
+
+func (c *StatusGetCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+func (c *StatusGetCommand) Run(ctx *cmd.Context) error {
+	unitStatus, err := c.ctx.UnitStatus()
+	if err != nil {
+		return errors.Annotate(err, "finding workload status")
+	}
+	if !c.includeData {
+		return c.out.Write(ctx, unitStatus.Status)
+	}
+	data := unitStatus.Data
+	if data == nil {
+		data = make(map[string]interface{})
+	}
+	return c.out.Write(ctx, map[string]interface{}{
+		"status":      unitStatus.Status,
+		"message":     unitStatus.Info,
+		"status-data": data,
+	})
+}
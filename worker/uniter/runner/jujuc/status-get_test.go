@@ -0,0 +1,103 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	"encoding/json"
+
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	goyaml "gopkg.in/yaml.v1"
+
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+)
+
+type statusGetSuite struct {
+	ContextSuite
+}
+
+var _ = gc.Suite(&statusGetSuite{})
+
+func (s *statusGetSuite) getHookContext(c *gc.C) *Context {
+	hctx := s.GetHookContext(c, -1, "")
+	err := hctx.SetUnitStatus(jujuc.StatusInfo{
+		Status: "blocked",
+		Info:   "need a database",
+		Data:   map[string]interface{}{"relation": "db"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return hctx
+}
+
+var statusGetTests = []struct {
+	args []string
+	out  string
+}{
+	{[]string{}, "blocked\n"},
+	{[]string{"--format", "yaml"}, "blocked\n"},
+	{[]string{"--format", "json"}, `"blocked"` + "\n"},
+}
+
+func (s *statusGetSuite) TestOutputFormat(c *gc.C) {
+	for i, t := range statusGetTests {
+		c.Logf("test %d: %#v", i, t.args)
+		hctx := s.getHookContext(c)
+		com, err := jujuc.NewCommand(hctx, cmdString("status-get"))
+		c.Assert(err, jc.ErrorIsNil)
+		ctx := testing.Context(c)
+		code := cmd.Main(com, ctx, t.args)
+		c.Assert(code, gc.Equals, 0)
+		c.Assert(bufferString(ctx.Stderr), gc.Equals, "")
+		c.Assert(bufferString(ctx.Stdout), gc.Equals, t.out)
+	}
+}
+
+var statusGetDataTests = []struct {
+	args   []string
+	format int
+	out    map[string]interface{}
+}{
+	{[]string{"--include-data", "--format", "yaml"}, formatYaml, map[string]interface{}{
+		"status":      "blocked",
+		"message":     "need a database",
+		"status-data": map[interface{}]interface{}{"relation": "db"},
+	}},
+	{[]string{"--include-data", "--format", "json"}, formatJson, map[string]interface{}{
+		"status":      "blocked",
+		"message":     "need a database",
+		"status-data": map[string]interface{}{"relation": "db"},
+	}},
+}
+
+func (s *statusGetSuite) TestOutputFormatIncludeData(c *gc.C) {
+	for i, t := range statusGetDataTests {
+		c.Logf("test %d: %#v", i, t.args)
+		hctx := s.getHookContext(c)
+		com, err := jujuc.NewCommand(hctx, cmdString("status-get"))
+		c.Assert(err, jc.ErrorIsNil)
+		ctx := testing.Context(c)
+		code := cmd.Main(com, ctx, t.args)
+		c.Assert(code, gc.Equals, 0)
+		c.Assert(bufferString(ctx.Stderr), gc.Equals, "")
+
+		out := map[string]interface{}{}
+		switch t.format {
+		case formatYaml:
+			c.Assert(goyaml.Unmarshal(bufferBytes(ctx.Stdout), &out), gc.IsNil)
+		case formatJson:
+			c.Assert(json.Unmarshal(bufferBytes(ctx.Stdout), &out), gc.IsNil)
+		}
+		c.Assert(out, gc.DeepEquals, t.out)
+	}
+}
+
+func (s *statusGetSuite) TestUnknownArgs(c *gc.C) {
+	hctx := s.GetHookContext(c, -1, "")
+	com, err := jujuc.NewCommand(hctx, cmdString("status-get"))
+	c.Assert(err, jc.ErrorIsNil)
+	err = testing.InitCommand(com, []string{"blah"})
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["blah"\]`)
+}
@@ -0,0 +1,86 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc
+
+import (
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+// StatusSetCommand implements the status-set command.
+type StatusSetCommand struct {
+	cmd.CommandBase
+	ctx     Context
+	status  string
+	message string
+	service bool
+}
+
+// NewStatusSetCommand makes a jujuc status-set command.
+func NewStatusSetCommand(ctx Context) cmd.Command {
+	return &StatusSetCommand{ctx: ctx}
+}
+
+func (c *StatusSetCommand) Info() *cmd.Info {
+	doc := `
+Sets the workload status of the charm. Message is optional.
+The status is recorded when the hook completes successfully.
+
+If --service is passed, the status of the service is set instead;
+only the leader unit of the service is allowed to do so.
+`
+	return &cmd.Info{
+		Name:    "status-set",
+		Args:    "<busy|waiting|blocked|running> [message]",
+		Purpose: "set status information",
+		Doc:     doc,
+	}
+}
+
+var validStatus = []params.Status{
+	params.StatusBusy,
+	params.StatusWaiting,
+	params.StatusBlocked,
+	params.StatusRunning,
+}
+
+func (c *StatusSetCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.BoolVar(&c.service, "service", false, "set this status for the service to which the unit belongs")
+}
+
+func (c *StatusSetCommand) Init(args []string) error {
+	if len(args) < 1 {
+		return errors.Errorf("invalid args, require <status> [message]")
+	}
+	valid := false
+	for _, s := range validStatus {
+		if string(s) == args[0] {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return errors.Errorf("invalid status %q, expected one of %v", args[0], validStatus)
+	}
+	c.status = args[0]
+	c.message = strings.Join(args[1:], " ")
+	return nil
+}
+
+func (c *StatusSetCommand) Run(ctx *cmd.Context) error {
+	statusInfo := StatusInfo{
+		Status: c.status,
+		Info:   c.message,
+	}
+	if c.service {
+		err := c.ctx.SetServiceStatus(statusInfo)
+		return errors.Annotate(err, "cannot set service status")
+	}
+	return c.ctx.SetUnitStatus(statusInfo)
+}
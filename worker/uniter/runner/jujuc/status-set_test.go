@@ -0,0 +1,100 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	"strings"
+
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+)
+
+type statusSetSuite struct {
+	ContextSuite
+}
+
+var _ = gc.Suite(&statusSetSuite{})
+
+var statusSetInitTests = []struct {
+	args []string
+	err  string
+}{
+	{[]string{"busy"}, ""},
+	{[]string{"waiting"}, ""},
+	{[]string{"blocked"}, ""},
+	{[]string{"running"}, ""},
+	{[]string{"running", "message"}, ""},
+	{[]string{}, `invalid args, require <status> \[message\]`},
+	{[]string{"error"}, `invalid status "error", expected one of \[busy waiting blocked running\]`},
+	{[]string{"unknown"}, `invalid status "unknown", expected one of \[busy waiting blocked running\]`},
+}
+
+func (s *statusSetSuite) TestStatusSetInit(c *gc.C) {
+	for i, t := range statusSetInitTests {
+		c.Logf("test %d: %#v", i, t.args)
+		hctx := s.GetHookContext(c, -1, "")
+		com, err := jujuc.NewCommand(hctx, cmdString("status-set"))
+		c.Assert(err, jc.ErrorIsNil)
+		err = testing.InitCommand(com, t.args)
+		if t.err == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.ErrorMatches, t.err)
+		}
+	}
+}
+
+func (s *statusSetSuite) TestStatus(c *gc.C) {
+	for i, args := range [][]string{
+		{"busy", "doing work"},
+		{"waiting", "waiting", "for", "mysql"},
+		{"blocked"},
+	} {
+		c.Logf("test %d: %#v", i, args)
+		hctx := s.GetHookContext(c, -1, "")
+		com, err := jujuc.NewCommand(hctx, cmdString("status-set"))
+		c.Assert(err, jc.ErrorIsNil)
+		ctx := testing.Context(c)
+		code := cmd.Main(com, ctx, args)
+		c.Assert(code, gc.Equals, 0)
+		c.Assert(bufferString(ctx.Stderr), gc.Equals, "")
+		c.Assert(bufferString(ctx.Stdout), gc.Equals, "")
+		status, err := hctx.UnitStatus()
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(status.Status, gc.Equals, args[0])
+		c.Assert(status.Info, gc.Equals, strings.Join(args[1:], " "))
+		c.Assert(hctx.serviceStatus, gc.DeepEquals, jujuc.StatusInfo{})
+	}
+}
+
+func (s *statusSetSuite) TestServiceStatus(c *gc.C) {
+	hctx := s.GetHookContext(c, -1, "")
+	hctx.isLeader = true
+	com, err := jujuc.NewCommand(hctx, cmdString("status-set"))
+	c.Assert(err, jc.ErrorIsNil)
+	ctx := testing.Context(c)
+	code := cmd.Main(com, ctx, []string{"--service", "blocked", "need", "a", "database"})
+	c.Assert(code, gc.Equals, 0)
+	c.Assert(bufferString(ctx.Stderr), gc.Equals, "")
+	c.Assert(hctx.serviceStatus, gc.DeepEquals, jujuc.StatusInfo{
+		Status: "blocked",
+		Info:   "need a database",
+	})
+	c.Assert(hctx.unitStatus, gc.DeepEquals, jujuc.StatusInfo{})
+}
+
+func (s *statusSetSuite) TestServiceStatusNotLeader(c *gc.C) {
+	hctx := s.GetHookContext(c, -1, "")
+	com, err := jujuc.NewCommand(hctx, cmdString("status-set"))
+	c.Assert(err, jc.ErrorIsNil)
+	ctx := testing.Context(c)
+	code := cmd.Main(com, ctx, []string{"--service", "blocked"})
+	c.Assert(code, gc.Equals, 1)
+	c.Assert(bufferString(ctx.Stderr), gc.Equals, "error: cannot set service status: not the leader\n")
+	c.Assert(hctx.serviceStatus, gc.DeepEquals, jujuc.StatusInfo{})
+}
@@ -88,6 +88,10 @@ type Context struct {
 	canAddMetrics  bool
 	rebootPriority jujuc.RebootPriority
 	shouldError    bool
+	unitStatus     jujuc.StatusInfo
+	serviceStatus  jujuc.StatusInfo
+	isLeader       bool
+	leaderSettings map[string]string
 }
 
 func (c *Context) AddMetric(key, value string, created time.Time) error {
@@ -211,10 +215,53 @@ func (c *Context) OwnerTag() string {
 	return "test-owner"
 }
 
+func (c *Context) UnitStatus() (*jujuc.StatusInfo, error) {
+	return &c.unitStatus, nil
+}
+
+func (c *Context) SetUnitStatus(status jujuc.StatusInfo) error {
+	c.unitStatus = status
+	return nil
+}
+
+func (c *Context) SetServiceStatus(status jujuc.StatusInfo) error {
+	if !c.isLeader {
+		return fmt.Errorf("not the leader")
+	}
+	c.serviceStatus = status
+	return nil
+}
+
+func (c *Context) IsLeader() (bool, error) {
+	return c.isLeader, nil
+}
+
+func (c *Context) LeaderSettings() (map[string]string, error) {
+	return c.leaderSettings, nil
+}
+
+func (c *Context) WriteLeaderSettings(settings map[string]string) error {
+	if !c.isLeader {
+		return fmt.Errorf("not the leader")
+	}
+	if c.leaderSettings == nil {
+		c.leaderSettings = make(map[string]string)
+	}
+	for key, value := range settings {
+		if value == "" {
+			delete(c.leaderSettings, key)
+		} else {
+			c.leaderSettings[key] = value
+		}
+	}
+	return nil
+}
+
 type ContextRelation struct {
-	id    int
-	name  string
-	units map[string]Settings
+	id       int
+	name     string
+	units    map[string]Settings
+	previous map[string]Settings
 }
 
 func (r *ContextRelation) Id() int {
@@ -250,6 +297,14 @@ func (r *ContextRelation) ReadSettings(name string) (params.Settings, error) {
 	return s.Map(), nil
 }
 
+func (r *ContextRelation) ReadPreviousSettings(name string) (params.Settings, bool) {
+	s, found := r.previous[name]
+	if !found {
+		return nil, false
+	}
+	return s.Map(), true
+}
+
 type Settings params.Settings
 
 func (s Settings) Get(k string) (interface{}, bool) {
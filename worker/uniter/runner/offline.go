@@ -0,0 +1,597 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package runner
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	utilexec "github.com/juju/utils/exec"
+	"gopkg.in/juju/charm.v4"
+	"gopkg.in/juju/charm.v4/hooks"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/storage"
+	"github.com/juju/juju/version"
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+)
+
+// OfflineFixture describes a unit, and its view of the environment, for
+// running hooks against an OfflineContext.
+type OfflineFixture struct {
+	Unit             string                 `yaml:"unit"`
+	Owner            string                 `yaml:"owner"`
+	PublicAddress    string                 `yaml:"public-address"`
+	PrivateAddress   string                 `yaml:"private-address"`
+	AvailabilityZone string                 `yaml:"availability-zone"`
+	Config           map[string]interface{} `yaml:"config"`
+	Leader           bool                   `yaml:"leader"`
+	LeaderSettings   map[string]string      `yaml:"leader-settings"`
+	OpenedPorts      []string               `yaml:"opened-ports"`
+	Relations        []OfflineRelation      `yaml:"relations"`
+}
+
+// OfflineRelation describes a relation the unit in an OfflineFixture
+// participates in.
+type OfflineRelation struct {
+	Id   int    `yaml:"id"`
+	Name string `yaml:"name"`
+
+	// Settings holds the unit's own settings in the relation.
+	Settings map[string]string `yaml:"settings"`
+
+	// Units holds the settings of each remote unit in the relation.
+	Units map[string]map[string]string `yaml:"units"`
+
+	// PreviousSettings holds the settings of remote units as seen by
+	// their last relation-changed hooks.
+	PreviousSettings map[string]map[string]string `yaml:"previous-settings"`
+}
+
+// OfflineReport describes what a hook did to an OfflineContext.
+type OfflineReport struct {
+	Hook      string            `yaml:"hook" json:"hook"`
+	HookTools []OfflineHookTool `yaml:"hook-tools,omitempty" json:"hook-tools,omitempty"`
+
+	// RelationSettings holds the unit's settings in each relation, by
+	// relation id, for which the hook changed them.
+	RelationSettings map[string]map[string]string `yaml:"relation-settings,omitempty" json:"relation-settings,omitempty"`
+
+	OpenedPorts    []string          `yaml:"opened-ports,omitempty" json:"opened-ports,omitempty"`
+	ClosedPorts    []string          `yaml:"closed-ports,omitempty" json:"closed-ports,omitempty"`
+	UnitStatus     *OfflineStatus    `yaml:"unit-status,omitempty" json:"unit-status,omitempty"`
+	ServiceStatus  *OfflineStatus    `yaml:"service-status,omitempty" json:"service-status,omitempty"`
+	LeaderSettings map[string]string `yaml:"leader-settings,omitempty" json:"leader-settings,omitempty"`
+	Metrics        map[string]string `yaml:"metrics,omitempty" json:"metrics,omitempty"`
+	Reboot         string            `yaml:"reboot,omitempty" json:"reboot,omitempty"`
+}
+
+// OfflineHookTool describes a hook tool run against an OfflineContext.
+type OfflineHookTool struct {
+	Command string   `yaml:"command" json:"command"`
+	Args    []string `yaml:"args,omitempty" json:"args,omitempty"`
+	Code    int      `yaml:"code" json:"code"`
+	Stdout  string   `yaml:"stdout,omitempty" json:"stdout,omitempty"`
+	Stderr  string   `yaml:"stderr,omitempty" json:"stderr,omitempty"`
+}
+
+// OfflineStatus describes a status set by a hook run against an
+// OfflineContext.
+type OfflineStatus struct {
+	Status string                 `yaml:"status" json:"status"`
+	Info   string                 `yaml:"info,omitempty" json:"info,omitempty"`
+	Data   map[string]interface{} `yaml:"data,omitempty" json:"data,omitempty"`
+}
+
+// OfflineContext implements Context without connecting to an environment,
+// so that charm hooks can be run locally against the unit and relations
+// described by an OfflineFixture. It records everything the hook does.
+type OfflineContext struct {
+	id               string
+	hookName         string
+	unitTag          names.UnitTag
+	ownerTag         names.UserTag
+	publicAddress    string
+	privateAddress   string
+	availabilityZone string
+	configSettings   charm.Settings
+	isLeader         bool
+	leaderSettings   map[string]string
+	leaderWritten    bool
+	machinePorts     map[network.PortRange]params.RelationUnit
+	pendingPorts     map[PortRange]PortRangeInfo
+	relations        map[int]*offlineRelation
+	relationId       int
+	remoteUnitName   string
+	canAddMetrics    bool
+	metrics          map[string]string
+	rebootPriority   jujuc.RebootPriority
+	unitStatus       *jujuc.StatusInfo
+	serviceStatus    *jujuc.StatusInfo
+	hookTools        []OfflineHookTool
+}
+
+// NewOfflineContext returns an OfflineContext for running the named hook
+// of the unit described by fixture, with the supplied service config.
+// For relation hooks, the relation is found by name if relationId is
+// negative, and the remote unit is inferred if remoteUnit is empty and
+// the relation has a single remote unit.
+func NewOfflineContext(
+	fixture OfflineFixture, config charm.Settings, hookName string, relationId int, remoteUnit string,
+) (*OfflineContext, error) {
+	if !names.IsValidUnit(fixture.Unit) {
+		return nil, errors.Errorf("invalid unit name %q", fixture.Unit)
+	}
+	owner := fixture.Owner
+	if owner == "" {
+		owner = "admin"
+	}
+	if !names.IsValidUser(owner) {
+		return nil, errors.Errorf("invalid owner %q", owner)
+	}
+	ctx := &OfflineContext{
+		id:               fmt.Sprintf("%s-%s-offline", fixture.Unit, hookName),
+		hookName:         hookName,
+		unitTag:          names.NewUnitTag(fixture.Unit),
+		ownerTag:         names.NewUserTag(owner),
+		publicAddress:    fixture.PublicAddress,
+		privateAddress:   fixture.PrivateAddress,
+		availabilityZone: fixture.AvailabilityZone,
+		configSettings:   config,
+		isLeader:         fixture.Leader,
+		leaderSettings:   map[string]string{},
+		machinePorts:     map[network.PortRange]params.RelationUnit{},
+		pendingPorts:     map[PortRange]PortRangeInfo{},
+		relations:        map[int]*offlineRelation{},
+		relationId:       -1,
+		canAddMetrics:    hookName == string(hooks.CollectMetrics),
+		metrics:          map[string]string{},
+	}
+	for key, value := range fixture.LeaderSettings {
+		ctx.leaderSettings[key] = value
+	}
+	for _, portRangeStr := range fixture.OpenedPorts {
+		portRange, err := network.ParsePortRange(portRangeStr)
+		if err != nil {
+			return nil, errors.Annotatef(err, "invalid opened port %q", portRangeStr)
+		}
+		ctx.machinePorts[*portRange] = params.RelationUnit{Unit: ctx.unitTag.String()}
+	}
+	for _, rel := range fixture.Relations {
+		if _, found := ctx.relations[rel.Id]; found {
+			return nil, errors.Errorf("duplicate relation id %d", rel.Id)
+		}
+		ctx.relations[rel.Id] = newOfflineRelation(rel)
+	}
+	if err := ctx.setHookRelation(relationId, remoteUnit); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return ctx, nil
+}
+
+// setHookRelation records the relation and remote unit of the context's
+// hook, inferring them from the hook name and fixture where needed.
+func (ctx *OfflineContext) setHookRelation(relationId int, remoteUnit string) error {
+	var relationName string
+	var kind hooks.Kind
+	for _, k := range hooks.RelationHooks() {
+		if suffix := "-" + string(k); strings.HasSuffix(ctx.hookName, suffix) {
+			relationName = strings.TrimSuffix(ctx.hookName, suffix)
+			kind = k
+			break
+		}
+	}
+	if relationName == "" {
+		if relationId >= 0 || remoteUnit != "" {
+			return errors.Errorf("%q is not a relation hook", ctx.hookName)
+		}
+		return nil
+	}
+	if relationId < 0 {
+		for id, rel := range ctx.relations {
+			if rel.name != relationName {
+				continue
+			}
+			if relationId >= 0 {
+				return errors.Errorf("cannot infer relation for %q: more than one %q relation", ctx.hookName, relationName)
+			}
+			relationId = id
+		}
+		if relationId < 0 {
+			return errors.Errorf("cannot infer relation for %q: no %q relation", ctx.hookName, relationName)
+		}
+	}
+	rel, found := ctx.relations[relationId]
+	if !found {
+		return errors.Errorf("unknown relation id %d", relationId)
+	}
+	if rel.name != relationName {
+		return errors.Errorf("relation %d is not a %q relation", relationId, relationName)
+	}
+	if kind == hooks.RelationBroken {
+		if remoteUnit != "" {
+			return errors.Errorf("%q hook has no remote unit", ctx.hookName)
+		}
+	} else if remoteUnit == "" {
+		unitNames := rel.UnitNames()
+		if len(unitNames) != 1 {
+			return errors.Errorf("cannot infer remote unit for %q: relation %d has %d remote units", ctx.hookName, relationId, len(unitNames))
+		}
+		remoteUnit = unitNames[0]
+	} else if _, found := rel.units[remoteUnit]; !found {
+		return errors.Errorf("unit %q is not in relation %d", remoteUnit, relationId)
+	}
+	ctx.relationId = relationId
+	ctx.remoteUnitName = remoteUnit
+	return nil
+}
+
+// Report returns a description of everything done by hooks run against
+// the context.
+func (ctx *OfflineContext) Report() OfflineReport {
+	report := OfflineReport{
+		Hook:      ctx.hookName,
+		HookTools: append([]OfflineHookTool(nil), ctx.hookTools...),
+	}
+	for _, rel := range ctx.relations {
+		if rel.settingsChanged() {
+			if report.RelationSettings == nil {
+				report.RelationSettings = map[string]map[string]string{}
+			}
+			report.RelationSettings[rel.FakeId()] = rel.settings.Map()
+		}
+	}
+	var opened, closed []network.PortRange
+	for rangeKey, rangeInfo := range ctx.pendingPorts {
+		if rangeInfo.ShouldOpen {
+			opened = append(opened, rangeKey.Ports)
+		} else {
+			closed = append(closed, rangeKey.Ports)
+		}
+	}
+	report.OpenedPorts = portRangeStrings(opened)
+	report.ClosedPorts = portRangeStrings(closed)
+	report.UnitStatus = offlineStatus(ctx.unitStatus)
+	report.ServiceStatus = offlineStatus(ctx.serviceStatus)
+	if ctx.leaderWritten {
+		report.LeaderSettings = ctx.leaderSettings
+	}
+	if len(ctx.metrics) > 0 {
+		report.Metrics = ctx.metrics
+	}
+	switch ctx.rebootPriority {
+	case jujuc.RebootAfterHook:
+		report.Reboot = "after-hook"
+	case jujuc.RebootNow:
+		report.Reboot = "now"
+	}
+	return report
+}
+
+func portRangeStrings(portRanges []network.PortRange) []string {
+	network.SortPortRanges(portRanges)
+	var result []string
+	for _, portRange := range portRanges {
+		result = append(result, portRange.String())
+	}
+	return result
+}
+
+func offlineStatus(status *jujuc.StatusInfo) *OfflineStatus {
+	if status == nil {
+		return nil
+	}
+	return &OfflineStatus{
+		Status: status.Status,
+		Info:   status.Info,
+		Data:   status.Data,
+	}
+}
+
+// observeHookTool is part of the hookToolObserver interface.
+func (ctx *OfflineContext) observeHookTool(req jujuc.Request, resp *utilexec.ExecResponse) {
+	ctx.hookTools = append(ctx.hookTools, OfflineHookTool{
+		Command: req.CommandName,
+		Args:    req.Args,
+		Code:    resp.Code,
+		Stdout:  string(resp.Stdout),
+		Stderr:  string(resp.Stderr),
+	})
+}
+
+func (ctx *OfflineContext) Id() string {
+	return ctx.id
+}
+
+func (ctx *OfflineContext) UnitName() string {
+	return ctx.unitTag.Id()
+}
+
+func (ctx *OfflineContext) PublicAddress() (string, bool) {
+	return ctx.publicAddress, ctx.publicAddress != ""
+}
+
+func (ctx *OfflineContext) PrivateAddress() (string, bool) {
+	return ctx.privateAddress, ctx.privateAddress != ""
+}
+
+func (ctx *OfflineContext) AvailabilityZone() (string, bool) {
+	return ctx.availabilityZone, ctx.availabilityZone != ""
+}
+
+func (ctx *OfflineContext) OpenPorts(protocol string, fromPort, toPort int) error {
+	return tryOpenPorts(protocol, fromPort, toPort, ctx.unitTag, ctx.machinePorts, ctx.pendingPorts)
+}
+
+func (ctx *OfflineContext) ClosePorts(protocol string, fromPort, toPort int) error {
+	return tryClosePorts(protocol, fromPort, toPort, ctx.unitTag, ctx.machinePorts, ctx.pendingPorts)
+}
+
+func (ctx *OfflineContext) OpenedPorts() []network.PortRange {
+	var unitRanges []network.PortRange
+	for portRange := range ctx.machinePorts {
+		unitRanges = append(unitRanges, portRange)
+	}
+	network.SortPortRanges(unitRanges)
+	return unitRanges
+}
+
+func (ctx *OfflineContext) ConfigSettings() (charm.Settings, error) {
+	result := charm.Settings{}
+	for name, value := range ctx.configSettings {
+		result[name] = value
+	}
+	return result, nil
+}
+
+func (ctx *OfflineContext) ActionParams() (map[string]interface{}, error) {
+	return nil, errors.New("not running an action")
+}
+
+func (ctx *OfflineContext) UpdateActionResults(keys []string, value string) error {
+	return errors.New("not running an action")
+}
+
+func (ctx *OfflineContext) SetActionMessage(message string) error {
+	return errors.New("not running an action")
+}
+
+func (ctx *OfflineContext) SetActionFailed() error {
+	return errors.New("not running an action")
+}
+
+func (ctx *OfflineContext) ActionData() (*ActionData, error) {
+	return nil, errors.New("not running an action")
+}
+
+func (ctx *OfflineContext) HookRelation() (jujuc.ContextRelation, bool) {
+	return ctx.Relation(ctx.relationId)
+}
+
+func (ctx *OfflineContext) RemoteUnitName() (string, bool) {
+	return ctx.remoteUnitName, ctx.remoteUnitName != ""
+}
+
+func (ctx *OfflineContext) Relation(id int) (jujuc.ContextRelation, bool) {
+	r, found := ctx.relations[id]
+	if !found {
+		return nil, false
+	}
+	return r, true
+}
+
+func (ctx *OfflineContext) RelationIds() []int {
+	ids := []int{}
+	for id := range ctx.relations {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func (ctx *OfflineContext) OwnerTag() string {
+	return ctx.ownerTag.String()
+}
+
+func (ctx *OfflineContext) AddMetric(key, value string, created time.Time) error {
+	if !ctx.canAddMetrics {
+		return errors.New("metrics disabled")
+	}
+	ctx.metrics[key] = value
+	return nil
+}
+
+func (ctx *OfflineContext) RequestReboot(priority jujuc.RebootPriority) error {
+	ctx.rebootPriority = priority
+	return nil
+}
+
+func (ctx *OfflineContext) StorageInstance(storageId string) (*storage.StorageInstance, bool) {
+	return nil, false
+}
+
+func (ctx *OfflineContext) HookStorageInstance() (*storage.StorageInstance, bool) {
+	return nil, false
+}
+
+func (ctx *OfflineContext) UnitStatus() (*jujuc.StatusInfo, error) {
+	if ctx.unitStatus != nil {
+		return ctx.unitStatus, nil
+	}
+	return &jujuc.StatusInfo{Status: string(params.StatusUnknown)}, nil
+}
+
+func (ctx *OfflineContext) SetUnitStatus(status jujuc.StatusInfo) error {
+	ctx.unitStatus = &status
+	return nil
+}
+
+func (ctx *OfflineContext) SetServiceStatus(status jujuc.StatusInfo) error {
+	if !ctx.isLeader {
+		return errors.Errorf("unit %q is not leader", ctx.UnitName())
+	}
+	ctx.serviceStatus = &status
+	return nil
+}
+
+func (ctx *OfflineContext) IsLeader() (bool, error) {
+	return ctx.isLeader, nil
+}
+
+func (ctx *OfflineContext) LeaderSettings() (map[string]string, error) {
+	result := make(map[string]string)
+	for key, value := range ctx.leaderSettings {
+		result[key] = value
+	}
+	return result, nil
+}
+
+func (ctx *OfflineContext) WriteLeaderSettings(settings map[string]string) error {
+	if !ctx.isLeader {
+		return errors.Errorf("unit %q is not leader", ctx.UnitName())
+	}
+	for key, value := range settings {
+		if value == "" {
+			delete(ctx.leaderSettings, key)
+		} else {
+			ctx.leaderSettings[key] = value
+		}
+	}
+	ctx.leaderWritten = true
+	return nil
+}
+
+func (ctx *OfflineContext) HookVars(paths Paths) []string {
+	vars := []string{
+		"CHARM_DIR=" + paths.GetCharmDir(), // legacy, embarrassing
+		"JUJU_CHARM_DIR=" + paths.GetCharmDir(),
+		"JUJU_CONTEXT_ID=" + ctx.id,
+		"JUJU_AGENT_SOCKET=" + paths.GetJujucSocket(),
+		"JUJU_UNIT_NAME=" + ctx.UnitName(),
+		"JUJU_AVAILABILITY_ZONE=" + ctx.availabilityZone,
+	}
+	if r, found := ctx.HookRelation(); found {
+		vars = append(vars,
+			"JUJU_RELATION="+r.Name(),
+			"JUJU_RELATION_ID="+r.FakeId(),
+			"JUJU_REMOTE_UNIT="+ctx.remoteUnitName,
+		)
+	}
+	if version.Current.OS == version.Windows {
+		return append(vars, osDependentEnvVars(paths)...)
+	}
+	// Offline contexts run on machines that units don't, so the hook
+	// tools are always added to the path.
+	return append(vars, appendPath(paths)...)
+}
+
+func (ctx *OfflineContext) SetProcess(process *os.Process) {}
+
+func (ctx *OfflineContext) HookTimeout() time.Duration {
+	return 0
+}
+
+func (ctx *OfflineContext) FlushContext(badge string, failure error) error {
+	return failure
+}
+
+// offlineRelation implements jujuc.ContextRelation for OfflineContext.
+type offlineRelation struct {
+	id       int
+	name     string
+	settings offlineSettings
+	initial  params.Settings
+	units    map[string]params.Settings
+	previous map[string]params.Settings
+}
+
+func newOfflineRelation(rel OfflineRelation) *offlineRelation {
+	r := &offlineRelation{
+		id:       rel.Id,
+		name:     rel.Name,
+		settings: offlineSettings{},
+		units:    map[string]params.Settings{},
+		previous: map[string]params.Settings{},
+	}
+	for key, value := range rel.Settings {
+		r.settings[key] = value
+	}
+	r.initial = r.settings.Map()
+	for unitName, settings := range rel.Units {
+		r.units[unitName] = params.Settings(settings)
+	}
+	for unitName, settings := range rel.PreviousSettings {
+		r.previous[unitName] = params.Settings(settings)
+	}
+	return r
+}
+
+// settingsChanged returns whether the unit's own settings in the relation
+// have changed since the relation was created.
+func (r *offlineRelation) settingsChanged() bool {
+	return !reflect.DeepEqual(r.initial, r.settings.Map())
+}
+
+func (r *offlineRelation) Id() int {
+	return r.id
+}
+
+func (r *offlineRelation) Name() string {
+	return r.name
+}
+
+func (r *offlineRelation) FakeId() string {
+	return fmt.Sprintf("%s:%d", r.name, r.id)
+}
+
+func (r *offlineRelation) Settings() (jujuc.Settings, error) {
+	return r.settings, nil
+}
+
+func (r *offlineRelation) UnitNames() []string {
+	var unitNames []string
+	for unitName := range r.units {
+		unitNames = append(unitNames, unitName)
+	}
+	sort.Strings(unitNames)
+	return unitNames
+}
+
+func (r *offlineRelation) ReadSettings(unit string) (params.Settings, error) {
+	settings, found := r.units[unit]
+	if !found {
+		return nil, errors.NotFoundf("settings")
+	}
+	return settings, nil
+}
+
+func (r *offlineRelation) ReadPreviousSettings(unit string) (params.Settings, bool) {
+	settings, found := r.previous[unit]
+	return settings, found
+}
+
+// offlineSettings implements jujuc.Settings for offlineRelation.
+type offlineSettings params.Settings
+
+func (s offlineSettings) Map() params.Settings {
+	result := params.Settings{}
+	for key, value := range s {
+		result[key] = value
+	}
+	return result
+}
+
+func (s offlineSettings) Set(key, value string) {
+	s[key] = value
+}
+
+func (s offlineSettings) Delete(key string) {
+	delete(s, key)
+}
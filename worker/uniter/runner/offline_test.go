@@ -0,0 +1,213 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package runner_test
+
+import (
+	"time"
+
+	envtesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	utilexec "github.com/juju/utils/exec"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v4"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/worker/uniter/runner"
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+)
+
+type OfflineContextSuite struct {
+	envtesting.IsolationSuite
+	fixture runner.OfflineFixture
+}
+
+var _ = gc.Suite(&OfflineContextSuite{})
+
+func (s *OfflineContextSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.fixture = runner.OfflineFixture{
+		Unit:           "wordpress/0",
+		PublicAddress:  "wordpress-0.example.com",
+		PrivateAddress: "10.0.0.1",
+		LeaderSettings: map[string]string{"password": "sekrit"},
+		OpenedPorts:    []string{"80/tcp"},
+		Relations: []runner.OfflineRelation{{
+			Id:       0,
+			Name:     "db",
+			Settings: map[string]string{"private-address": "10.0.0.1"},
+			Units: map[string]map[string]string{
+				"mysql/0": {"host": "10.0.0.2", "password": "new"},
+			},
+			PreviousSettings: map[string]map[string]string{
+				"mysql/0": {"host": "10.0.0.2", "password": "old"},
+			},
+		}, {
+			Id:   1,
+			Name: "cache",
+			Units: map[string]map[string]string{
+				"memcached/0": {},
+				"memcached/1": {},
+			},
+		}},
+	}
+}
+
+func (s *OfflineContextSuite) newContext(c *gc.C, hookName string) *runner.OfflineContext {
+	ctx, err := runner.NewOfflineContext(s.fixture, charm.Settings{"blog-title": "My Title"}, hookName, -1, "")
+	c.Assert(err, jc.ErrorIsNil)
+	return ctx
+}
+
+func (s *OfflineContextSuite) TestUnit(c *gc.C) {
+	ctx := s.newContext(c, "install")
+	c.Assert(ctx.UnitName(), gc.Equals, "wordpress/0")
+	c.Assert(ctx.OwnerTag(), gc.Equals, "user-admin")
+	addr, ok := ctx.PublicAddress()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(addr, gc.Equals, "wordpress-0.example.com")
+	_, ok = ctx.AvailabilityZone()
+	c.Assert(ok, jc.IsFalse)
+	config, err := ctx.ConfigSettings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(config, gc.DeepEquals, charm.Settings{"blog-title": "My Title"})
+	c.Assert(ctx.OpenedPorts(), jc.DeepEquals, []network.PortRange{{80, 80, "tcp"}})
+	_, found := ctx.HookRelation()
+	c.Assert(found, jc.IsFalse)
+}
+
+func (s *OfflineContextSuite) TestInvalidFixture(c *gc.C) {
+	s.fixture.Unit = "wordpress"
+	_, err := runner.NewOfflineContext(s.fixture, nil, "install", -1, "")
+	c.Assert(err, gc.ErrorMatches, `invalid unit name "wordpress"`)
+
+	s.fixture.Unit = "wordpress/0"
+	s.fixture.OpenedPorts = []string{"eighty"}
+	_, err = runner.NewOfflineContext(s.fixture, nil, "install", -1, "")
+	c.Assert(err, gc.ErrorMatches, `invalid opened port "eighty": .*`)
+}
+
+func (s *OfflineContextSuite) TestRelationHook(c *gc.C) {
+	ctx := s.newContext(c, "db-relation-changed")
+	rel, found := ctx.HookRelation()
+	c.Assert(found, jc.IsTrue)
+	c.Assert(rel.FakeId(), gc.Equals, "db:0")
+	remote, found := ctx.RemoteUnitName()
+	c.Assert(found, jc.IsTrue)
+	c.Assert(remote, gc.Equals, "mysql/0")
+
+	settings, err := rel.ReadSettings("mysql/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, gc.DeepEquals, params.Settings{"host": "10.0.0.2", "password": "new"})
+	previous, found := rel.ReadPreviousSettings("mysql/0")
+	c.Assert(found, jc.IsTrue)
+	c.Assert(previous, gc.DeepEquals, params.Settings{"host": "10.0.0.2", "password": "old"})
+
+	vars := ctx.HookVars(MockEnvPaths{})
+	c.Assert(vars, gc.HasLen, 10)
+	c.Assert(vars[9], jc.HasPrefix, "PATH=path-to-tools:")
+	c.Assert(vars[:9], jc.SameContents, []string{
+		"CHARM_DIR=path-to-charm",
+		"JUJU_CHARM_DIR=path-to-charm",
+		"JUJU_CONTEXT_ID=" + ctx.Id(),
+		"JUJU_AGENT_SOCKET=path-to-jujuc.socket",
+		"JUJU_UNIT_NAME=wordpress/0",
+		"JUJU_AVAILABILITY_ZONE=",
+		"JUJU_RELATION=db",
+		"JUJU_RELATION_ID=db:0",
+		"JUJU_REMOTE_UNIT=mysql/0",
+	})
+}
+
+func (s *OfflineContextSuite) TestRelationHookErrors(c *gc.C) {
+	for i, test := range []struct {
+		hookName   string
+		relationId int
+		remoteUnit string
+		err        string
+	}{{
+		hookName:   "install",
+		relationId: 0,
+		err:        `"install" is not a relation hook`,
+	}, {
+		hookName:   "website-relation-joined",
+		relationId: -1,
+		err:        `cannot infer relation for "website-relation-joined": no "website" relation`,
+	}, {
+		hookName:   "cache-relation-joined",
+		relationId: 0,
+		err:        `relation 0 is not a "cache" relation`,
+	}, {
+		hookName:   "cache-relation-joined",
+		relationId: -1,
+		err:        `cannot infer remote unit for "cache-relation-joined": relation 1 has 2 remote units`,
+	}, {
+		hookName:   "cache-relation-joined",
+		relationId: 1,
+		remoteUnit: "mysql/0",
+		err:        `unit "mysql/0" is not in relation 1`,
+	}, {
+		hookName:   "cache-relation-broken",
+		relationId: 1,
+		remoteUnit: "memcached/0",
+		err:        `"cache-relation-broken" hook has no remote unit`,
+	}} {
+		c.Logf("test %d: %s", i, test.hookName)
+		_, err := runner.NewOfflineContext(s.fixture, nil, test.hookName, test.relationId, test.remoteUnit)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *OfflineContextSuite) TestLeadership(c *gc.C) {
+	ctx := s.newContext(c, "leader-settings-changed")
+	err := ctx.WriteLeaderSettings(map[string]string{"password": "new"})
+	c.Assert(err, gc.ErrorMatches, `unit "wordpress/0" is not leader`)
+	err = ctx.SetServiceStatus(jujuc.StatusInfo{Status: "active"})
+	c.Assert(err, gc.ErrorMatches, `unit "wordpress/0" is not leader`)
+
+	s.fixture.Leader = true
+	ctx = s.newContext(c, "leader-elected")
+	err = ctx.WriteLeaderSettings(map[string]string{"password": "", "url": "http://example.com"})
+	c.Assert(err, jc.ErrorIsNil)
+	settings, err := ctx.LeaderSettings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, gc.DeepEquals, map[string]string{"url": "http://example.com"})
+}
+
+func (s *OfflineContextSuite) TestReport(c *gc.C) {
+	ctx := s.newContext(c, "db-relation-changed")
+	runner.ObserveHookTool(ctx, jujuc.Request{
+		CommandName: "relation-set",
+		Args:        []string{"database=wordpress"},
+	}, &utilexec.ExecResponse{})
+	rel, _ := ctx.HookRelation()
+	settings, err := rel.Settings()
+	c.Assert(err, jc.ErrorIsNil)
+	settings.Set("database", "wordpress")
+	cacheRel, _ := ctx.Relation(1)
+	cacheSettings, err := cacheRel.Settings()
+	c.Assert(err, jc.ErrorIsNil)
+	cacheSettings.Delete("missing")
+
+	c.Assert(ctx.OpenPorts("tcp", 443, 443), jc.ErrorIsNil)
+	c.Assert(ctx.ClosePorts("tcp", 80, 80), jc.ErrorIsNil)
+	c.Assert(ctx.SetUnitStatus(jujuc.StatusInfo{Status: "active", Info: "ready"}), jc.ErrorIsNil)
+	c.Assert(ctx.AddMetric("pings", "1", time.Now()), gc.ErrorMatches, "metrics disabled")
+	c.Assert(ctx.RequestReboot(jujuc.RebootAfterHook), jc.ErrorIsNil)
+
+	c.Assert(ctx.Report(), jc.DeepEquals, runner.OfflineReport{
+		Hook: "db-relation-changed",
+		HookTools: []runner.OfflineHookTool{{
+			Command: "relation-set",
+			Args:    []string{"database=wordpress"},
+		}},
+		RelationSettings: map[string]map[string]string{
+			"db:0": {"private-address": "10.0.0.1", "database": "wordpress"},
+		},
+		OpenedPorts: []string{"443/tcp"},
+		ClosedPorts: []string{"80/tcp"},
+		UnitStatus:  &runner.OfflineStatus{Status: "active", Info: "ready"},
+		Reboot:      "after-hook",
+	})
+}
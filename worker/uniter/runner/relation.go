@@ -14,6 +14,10 @@ import (
 type RelationInfo struct {
 	RelationUnit *uniter.RelationUnit
 	MemberNames  []string
+
+	// PreviousSettings holds, for each member, the settings that were
+	// seen by the last relation-changed hook run for it.
+	PreviousSettings map[string]params.Settings
 }
 
 // ContextRelation is the implementation of jujuc.ContextRelation.
@@ -27,6 +31,10 @@ type ContextRelation struct {
 
 	// cache holds remote unit membership and settings.
 	cache *RelationCache
+
+	// previousSettings holds the settings of remote units as seen by
+	// their last relation-changed hooks.
+	previousSettings map[string]params.Settings
 }
 
 // NewContextRelation creates a new context for the given relation unit.
@@ -60,6 +68,11 @@ func (ctx *ContextRelation) ReadSettings(unit string) (settings params.Settings,
 	return ctx.cache.Settings(unit)
 }
 
+func (ctx *ContextRelation) ReadPreviousSettings(unit string) (params.Settings, bool) {
+	settings, found := ctx.previousSettings[unit]
+	return settings, found
+}
+
 func (ctx *ContextRelation) Settings() (jujuc.Settings, error) {
 	if ctx.settings == nil {
 		node, err := ctx.ru.Settings()
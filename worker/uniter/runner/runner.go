@@ -8,12 +8,14 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"time"
 
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	utilexec "github.com/juju/utils/exec"
 
+	"github.com/juju/juju/actions"
 	"github.com/juju/juju/version"
 	"github.com/juju/juju/worker/uniter/runner/debug"
 	"github.com/juju/juju/worker/uniter/runner/jujuc"
@@ -42,6 +44,7 @@ type Context interface {
 	HookVars(paths Paths) []string
 	ActionData() (*ActionData, error)
 	SetProcess(process *os.Process)
+	HookTimeout() time.Duration
 	FlushContext(badge string, failure error) error
 }
 
@@ -62,15 +65,29 @@ type Paths interface {
 	GetJujucSocket() string
 }
 
+// hookToolObserver is implemented by contexts that record the hook tools
+// run against them.
+type hookToolObserver interface {
+	observeHookTool(req jujuc.Request, resp *utilexec.ExecResponse)
+}
+
 // NewRunner returns a Runner backed by the supplied context and paths.
 func NewRunner(context Context, paths Paths) Runner {
-	return &runner{context, paths}
+	return &runner{context: context, paths: paths}
 }
 
+// debugSessionPollInterval is how often a running hook checks whether
+// a debug-hooks session has been started for it.
+var debugSessionPollInterval = time.Second
+
 // runner implements Runner.
 type runner struct {
 	context Context
 	paths   Paths
+
+	// newContext, if not nil, returns a fresh context in which to run
+	// a hook or action again in a debug-hooks session.
+	newContext func() (Context, error)
 }
 
 func (runner *runner) Context() Context {
@@ -79,6 +96,14 @@ func (runner *runner) Context() Context {
 
 // RunCommands exists to satisfy the Runner interface.
 func (runner *runner) RunCommands(commands string) (*utilexec.ExecResponse, error) {
+	result, err := runner.runCommands(commands, 0)
+	return result, runner.context.FlushContext("run commands", err)
+}
+
+// runCommands executes the supplied script, killing it if it is still
+// running after timeout. A zero timeout means it is never killed. The
+// caller is responsible for flushing the context.
+func (runner *runner) runCommands(commands string, timeout time.Duration) (*utilexec.ExecResponse, error) {
 	srv, err := runner.startJujucServer()
 	if err != nil {
 		return nil, err
@@ -97,9 +122,23 @@ func (runner *runner) RunCommands(commands string) (*utilexec.ExecResponse, erro
 	}
 	runner.context.SetProcess(command.Process())
 
+	timedOut := make(chan struct{})
+	if timeout > 0 {
+		timer := time.AfterFunc(timeout, func() {
+			close(timedOut)
+			command.Process().Kill()
+		})
+		defer timer.Stop()
+	}
+
 	// Block and wait for process to finish
 	result, err := command.Wait()
-	return result, runner.context.FlushContext("run commands", err)
+	select {
+	case <-timedOut:
+		return nil, errors.Errorf("commands timed out after %v", timeout)
+	default:
+	}
+	return result, err
 }
 
 // RunAction exists to satisfy the Runner interface.
@@ -107,21 +146,98 @@ func (runner *runner) RunAction(actionName string) error {
 	if _, err := runner.context.ActionData(); err != nil {
 		return errors.Trace(err)
 	}
-	return runner.runCharmHookWithLocation(actionName, "actions")
+	if actionName == actions.JujuRunActionName {
+		return runner.runJujuRunAction()
+	}
+	return runner.runCharmHookWithLocation(actionName, "actions", 0)
+}
+
+// runJujuRunAction runs the commands of the predefined juju-run action,
+// and records their output and return code as the action's results.
+func (runner *runner) runJujuRunAction() error {
+	params, err := runner.context.ActionParams()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	commands, timeout, err := actions.ParseJujuRunParams(params)
+	if err == nil {
+		var result *utilexec.ExecResponse
+		result, err = runner.runCommands(commands, timeout)
+		if err == nil {
+			for key, value := range actions.JujuRunResults(result) {
+				if err := runner.context.UpdateActionResults([]string{key}, value); err != nil {
+					return errors.Trace(err)
+				}
+			}
+		}
+	}
+	return runner.context.FlushContext(actions.JujuRunActionName, err)
 }
 
 // RunHook exists to satisfy the Runner interface.
 func (runner *runner) RunHook(hookName string) error {
-	return runner.runCharmHookWithLocation(hookName, "hooks")
+	return runner.runCharmHookWithLocation(hookName, "hooks", runner.context.HookTimeout())
 }
 
-func (runner *runner) runCharmHookWithLocation(hookName, charmLocation string) error {
+// runCharmHookWithLocation runs the named hook or action found in
+// charmLocation; if timeout is positive, the hook is killed if it runs
+// for longer than that. Hooks run via debug-hooks are never timed out.
+//
+// If a debug-hooks session for the hook is started while it is
+// running, or is found after it fails, the hook is stopped and run
+// again in the session, in a fresh context: changes that the original
+// run made to its context are discarded.
+func (runner *runner) runCharmHookWithLocation(hookName, charmLocation string, timeout time.Duration) error {
+	debugctx := debug.NewHooksContext(runner.context.UnitName())
+	findSession := func() *debug.ServerSession {
+		if session, _ := debugctx.FindSession(); session != nil && session.MatchHook(hookName) {
+			return session
+		}
+		return nil
+	}
+	if session := findSession(); session != nil {
+		logger.Infof("executing %s via debug-hooks", hookName)
+		return runner.context.FlushContext(hookName, runner.runDebugHook(session, hookName, charmLocation))
+	}
+	if runner.newContext == nil {
+		// The hook cannot be run again in a fresh context.
+		findSession = nil
+	}
+	session, err := runner.runCharmHook(hookName, charmLocation, timeout, findSession)
+	if err != nil && !IsMissingHookError(err) && session == nil && findSession != nil {
+		session = findSession()
+	}
+	if session == nil {
+		return runner.context.FlushContext(hookName, err)
+	}
+	logger.Infof("%s stopped (%v); executing it again via debug-hooks", hookName, err)
+	context, err := runner.newContext()
+	if err != nil {
+		return runner.context.FlushContext(hookName, errors.Annotatef(err, "cannot run %s again", hookName))
+	}
+	runner.context = context
+	return runner.context.FlushContext(hookName, runner.runDebugHook(session, hookName, charmLocation))
+}
+
+// runDebugHook runs the named hook or action found in charmLocation
+// via the supplied debug-hooks session.
+func (runner *runner) runDebugHook(session *debug.ServerSession, hookName string, charmLocation string) error {
 	srv, err := runner.startJujucServer()
 	if err != nil {
 		return err
 	}
 	defer srv.Close()
 
+	charmDir := runner.paths.GetCharmDir()
+	hookPath, err := searchHook(charmDir, filepath.Join(charmLocation, hookName))
+	if err != nil && !IsMissingHookError(err) {
+		return errors.Trace(err)
+	}
+	return session.RunHook(hookName, hookPath, charmDir, runner.hookEnv())
+}
+
+// hookEnv returns the environment in which hooks and actions are run.
+func (runner *runner) hookEnv() []string {
 	env := runner.context.HookVars(runner.paths)
 	if version.Current.OS == version.Windows {
 		// TODO(fwereade): somehow consolidate with utils/exec?
@@ -129,18 +245,22 @@ func (runner *runner) runCharmHookWithLocation(hookName, charmLocation string) e
 		// because that already has handling for windows environment requirements.
 		env = mergeEnvironment(env)
 	}
+	return env
+}
 
-	debugctx := debug.NewHooksContext(runner.context.UnitName())
-	if session, _ := debugctx.FindSession(); session != nil && session.MatchHook(hookName) {
-		logger.Infof("executing %s via debug-hooks", hookName)
-		err = session.RunHook(hookName, runner.paths.GetCharmDir(), env)
-	} else {
-		err = runner.runCharmHook(hookName, env, charmLocation)
+// runCharmHook runs the named hook or action found in charmLocation.
+// If findSession is not nil, it is called periodically while the hook
+// runs; if it returns a debug-hooks session, the hook is killed and the
+// session is returned, so that the hook can be run again in it.
+func (runner *runner) runCharmHook(
+	hookName, charmLocation string, timeout time.Duration, findSession func() *debug.ServerSession,
+) (*debug.ServerSession, error) {
+	srv, err := runner.startJujucServer()
+	if err != nil {
+		return nil, err
 	}
-	return runner.context.FlushContext(hookName, err)
-}
+	defer srv.Close()
 
-func (runner *runner) runCharmHook(hookName string, env []string, charmLocation string) error {
 	charmDir := runner.paths.GetCharmDir()
 	hook, err := searchHook(charmDir, filepath.Join(charmLocation, hookName))
 	if err != nil {
@@ -148,15 +268,16 @@ func (runner *runner) runCharmHook(hookName string, env []string, charmLocation
 			// Missing hook is perfectly valid, but worth mentioning.
 			logger.Infof("skipped %q hook (not implemented)", hookName)
 		}
-		return err
+		return nil, err
 	}
 	hookCmd := hookCommand(hook)
 	ps := exec.Command(hookCmd[0], hookCmd[1:]...)
-	ps.Env = env
+	ps.Env = runner.hookEnv()
 	ps.Dir = charmDir
+	setProcessGroup(ps)
 	outReader, outWriter, err := os.Pipe()
 	if err != nil {
-		return errors.Errorf("cannot make logging pipe: %v", err)
+		return nil, errors.Errorf("cannot make logging pipe: %v", err)
 	}
 	ps.Stdout = outWriter
 	ps.Stderr = outWriter
@@ -166,16 +287,60 @@ func (runner *runner) runCharmHook(hookName string, env []string, charmLocation
 		logger: runner.getLogger(hookName),
 	}
 	go hookLogger.run()
+	var session *debug.ServerSession
 	err = ps.Start()
 	outWriter.Close()
 	if err == nil {
 		// Record the *os.Process of the hook
 		runner.context.SetProcess(ps.Process)
-		// Block until execution finishes
-		err = ps.Wait()
+		var timer *time.Timer
+		if timeout > 0 {
+			// Kill the hook, and anything it started, if it doesn't
+			// complete in time.
+			timer = time.AfterFunc(timeout, func() {
+				logger.Errorf("%q hook still running after %v; killing it", hookName, timeout)
+				if err := killProcessGroup(ps.Process); err != nil {
+					logger.Errorf("cannot kill %q hook: %v", hookName, err)
+				}
+			})
+		}
+		// Block until execution finishes, or a debug-hooks session
+		// is started for the hook.
+		session, err = waitHook(ps, hookName, findSession)
+		if timer != nil && !timer.Stop() {
+			err = NewHookTimedOutError(hookName, timeout)
+		}
 	}
 	hookLogger.stop()
-	return errors.Trace(err)
+	return session, errors.Trace(err)
+}
+
+// waitHook waits for the hook process ps to finish. If findSession
+// is not nil and returns a debug-hooks session while the hook runs,
+// the hook, and anything it started, is killed and the session is
+// returned along with the error from the killed process.
+func waitHook(ps *exec.Cmd, hookName string, findSession func() *debug.ServerSession) (*debug.ServerSession, error) {
+	done := make(chan error, 1)
+	go func() {
+		done <- ps.Wait()
+	}()
+	if findSession == nil {
+		return nil, <-done
+	}
+	for {
+		select {
+		case err := <-done:
+			return nil, err
+		case <-time.After(debugSessionPollInterval):
+			if session := findSession(); session != nil {
+				logger.Infof("debug-hooks session started for running %q hook; killing it", hookName)
+				if err := killProcessGroup(ps.Process); err != nil {
+					logger.Errorf("cannot kill %q hook: %v", hookName, err)
+				}
+				return session, <-done
+			}
+		}
+	}
 }
 
 func (runner *runner) startJujucServer() (*jujuc.Server, error) {
@@ -186,7 +351,11 @@ func (runner *runner) startJujucServer() (*jujuc.Server, error) {
 		}
 		return jujuc.NewCommand(runner.context, cmdName)
 	}
-	srv, err := jujuc.NewServer(getCmd, runner.paths.GetJujucSocket())
+	var observe jujuc.CmdObserver
+	if observer, ok := runner.context.(hookToolObserver); ok {
+		observe = observer.observeHookTool
+	}
+	srv, err := jujuc.NewObservedServer(getCmd, observe, runner.paths.GetJujucSocket())
 	if err != nil {
 		return nil, err
 	}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 	"time"
@@ -17,7 +18,9 @@ import (
 	"github.com/juju/utils"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/actions"
 	"github.com/juju/juju/worker/uniter/runner"
+	"github.com/juju/juju/worker/uniter/runner/debug"
 )
 
 type RunCommandSuite struct {
@@ -145,6 +148,7 @@ func (s *RunHookSuite) TestRunHook(c *gc.C) {
 type MockContext struct {
 	runner.Context
 	actionData   *runner.ActionData
+	hookTimeout  time.Duration
 	expectPid    int
 	flushBadge   string
 	flushFailure error
@@ -166,10 +170,29 @@ func (ctx *MockContext) ActionData() (*runner.ActionData, error) {
 	return ctx.actionData, nil
 }
 
+func (ctx *MockContext) ActionParams() (map[string]interface{}, error) {
+	if ctx.actionData == nil {
+		return nil, errors.New("blam")
+	}
+	return ctx.actionData.ActionParams, nil
+}
+
+func (ctx *MockContext) UpdateActionResults(keys []string, value string) error {
+	if ctx.actionData == nil {
+		return errors.New("blam")
+	}
+	ctx.actionData.ResultsMap[strings.Join(keys, ".")] = value
+	return nil
+}
+
 func (ctx *MockContext) SetProcess(process *os.Process) {
 	ctx.expectPid = process.Pid
 }
 
+func (ctx *MockContext) HookTimeout() time.Duration {
+	return ctx.hookTimeout
+}
+
 func (ctx *MockContext) FlushContext(badge string, failure error) error {
 	ctx.flushBadge = badge
 	ctx.flushFailure = failure
@@ -231,6 +254,54 @@ func (s *RunMockContextSuite) TestRunHookFlushFailure(c *gc.C) {
 	s.assertRecordedPid(c, ctx.expectPid)
 }
 
+func (s *RunMockContextSuite) TestRunHookTimeout(c *gc.C) {
+	expectErr := errors.New("pew pew pew")
+	ctx := &MockContext{
+		flushResult: expectErr,
+		hookTimeout: 100 * time.Millisecond,
+	}
+	makeCharm(c, hookSpec{
+		dir:  "hooks",
+		name: "something-happened",
+		perm: 0700,
+		hang: true,
+	}, s.paths.charm)
+	actualErr := runner.NewRunner(ctx, s.paths).RunHook("something-happened")
+	c.Assert(actualErr, gc.Equals, expectErr)
+	c.Assert(ctx.flushBadge, gc.Equals, "something-happened")
+	c.Assert(ctx.flushFailure, gc.ErrorMatches, `hook "something-happened" timed out after 100ms`)
+	c.Assert(runner.IsHookTimedOutError(errors.Cause(ctx.flushFailure)), jc.IsTrue)
+}
+
+func (s *RunMockContextSuite) TestWaitHookKillsHookForDebugSession(c *gc.C) {
+	s.PatchValue(runner.DebugSessionPollInterval, 10*time.Millisecond)
+	makeCharm(c, hookSpec{
+		dir:  "hooks",
+		name: "something-happened",
+		perm: 0700,
+		hang: true,
+	}, s.paths.charm)
+	hookCmd := runner.HookCommand(filepath.Join(s.paths.charm, "hooks", "something-happened"))
+	ps := exec.Command(hookCmd[0], hookCmd[1:]...)
+	runner.SetProcessGroup(ps)
+	err := ps.Start()
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The hook is killed once a session is started for it.
+	expectSession := &debug.ServerSession{}
+	polls := 0
+	findSession := func() *debug.ServerSession {
+		if polls++; polls < 3 {
+			return nil
+		}
+		return expectSession
+	}
+	session, err := runner.WaitHook(ps, "something-happened", findSession)
+	c.Assert(session, gc.Equals, expectSession)
+	c.Assert(err, gc.NotNil)
+	c.Assert(polls, gc.Equals, 3)
+}
+
 func (s *RunMockContextSuite) TestRunActionFlushSuccess(c *gc.C) {
 	expectErr := errors.New("pew pew pew")
 	ctx := &MockContext{
@@ -268,6 +339,57 @@ func (s *RunMockContextSuite) TestRunActionFlushFailure(c *gc.C) {
 	s.assertRecordedPid(c, ctx.expectPid)
 }
 
+func (s *RunMockContextSuite) TestRunJujuRunAction(c *gc.C) {
+	expectErr := errors.New("pew pew pew")
+	ctx := &MockContext{
+		flushResult: expectErr,
+		actionData: &runner.ActionData{
+			ActionName:   actions.JujuRunActionName,
+			ActionParams: actions.JujuRunParams("echo $$ > pid; echo hello; exit 3", 0),
+			ResultsMap:   map[string]interface{}{},
+		},
+	}
+	actualErr := runner.NewRunner(ctx, s.paths).RunAction(actions.JujuRunActionName)
+	c.Assert(actualErr, gc.Equals, expectErr)
+	c.Assert(ctx.flushBadge, gc.Equals, actions.JujuRunActionName)
+	c.Assert(ctx.flushFailure, gc.IsNil)
+	c.Assert(ctx.actionData.ResultsMap, jc.DeepEquals, map[string]interface{}{
+		"Code":   "3",
+		"Stdout": "hello\n",
+		"Stderr": "",
+	})
+	s.assertRecordedPid(c, ctx.expectPid)
+}
+
+func (s *RunMockContextSuite) TestRunJujuRunActionTimeout(c *gc.C) {
+	ctx := &MockContext{
+		actionData: &runner.ActionData{
+			ActionName:   actions.JujuRunActionName,
+			ActionParams: actions.JujuRunParams("echo $$ > pid; sleep 1", 10*time.Millisecond),
+			ResultsMap:   map[string]interface{}{},
+		},
+	}
+	err := runner.NewRunner(ctx, s.paths).RunAction(actions.JujuRunActionName)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ctx.flushBadge, gc.Equals, actions.JujuRunActionName)
+	c.Assert(ctx.flushFailure, gc.ErrorMatches, "commands timed out after 10ms")
+	c.Assert(ctx.actionData.ResultsMap, gc.HasLen, 0)
+}
+
+func (s *RunMockContextSuite) TestRunJujuRunActionBadParams(c *gc.C) {
+	ctx := &MockContext{
+		actionData: &runner.ActionData{
+			ActionName:   actions.JujuRunActionName,
+			ActionParams: map[string]interface{}{},
+			ResultsMap:   map[string]interface{}{},
+		},
+	}
+	err := runner.NewRunner(ctx, s.paths).RunAction(actions.JujuRunActionName)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ctx.flushBadge, gc.Equals, actions.JujuRunActionName)
+	c.Assert(ctx.flushFailure, gc.ErrorMatches, "command not specified")
+}
+
 func (s *RunMockContextSuite) TestRunCommandsFlushSuccess(c *gc.C) {
 	expectErr := errors.New("pew pew pew")
 	ctx := &MockContext{
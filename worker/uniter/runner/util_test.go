@@ -8,6 +8,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/juju/names"
 	jc "github.com/juju/testing/checkers"
@@ -20,6 +21,7 @@ import (
 	"github.com/juju/juju/api/uniter"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/leadership"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/worker/uniter/runner"
@@ -228,6 +230,31 @@ func (s *HookContextSuite) metricsDefinition(name string) *charm.Metrics {
 	return &charm.Metrics{Metrics: map[string]charm.Metric{name: {Type: charm.MetricTypeGauge, Description: "generated metric"}}}
 }
 
+// leadershipStub is a leadership.LeadershipManager whose claims succeed
+// if leader is set, and otherwise fail with err, or are denied.
+type leadershipStub struct {
+	leader bool
+	err    error
+}
+
+func (l *leadershipStub) ClaimLeadership(serviceId, unitId string) (time.Duration, error) {
+	switch {
+	case l.leader:
+		return time.Minute, nil
+	case l.err != nil:
+		return 0, l.err
+	}
+	return 0, leadership.LeadershipClaimDeniedErr
+}
+
+func (l *leadershipStub) ReleaseLeadership(serviceId, unitId string) error {
+	return nil
+}
+
+func (l *leadershipStub) BlockUntilLeadershipReleased(serviceId string) error {
+	return nil
+}
+
 // hookSpec supports makeCharm.
 type hookSpec struct {
 	// dir is the directory to create the hook in.
@@ -244,6 +271,8 @@ type hookSpec struct {
 	stderr string
 	// background holds a string to print in the background after 0.2s.
 	background string
+	// hang indicates that the hook should never complete.
+	hang bool
 }
 
 // makeCharm constructs a fake charm dir containing a single named hook
@@ -286,5 +315,8 @@ func makeCharm(c *gc.C, spec hookSpec, charmDir string) {
 		// expected.
 		printf("(sleep 0.2; echo %s; sleep 10) &", spec.background)
 	}
+	if spec.hang {
+		printf("sleep 1000")
+	}
 	printf("exit %d", spec.code)
 }
@@ -19,6 +19,7 @@ import (
 
 	"github.com/juju/juju/api/uniter"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/leadership"
 	"github.com/juju/juju/state/watcher"
 	"github.com/juju/juju/version"
 	"github.com/juju/juju/worker"
@@ -66,12 +67,13 @@ type deployerProxy struct {
 // delegated to Mode values, which are expected to react to events and direct
 // the uniter's responses to them.
 type Uniter struct {
-	tomb      tomb.Tomb
-	st        *uniter.State
-	paths     Paths
-	f         filter.Filter
-	unit      *uniter.Unit
-	relations Relations
+	tomb       tomb.Tomb
+	st         *uniter.State
+	paths      Paths
+	f          filter.Filter
+	unit       *uniter.Unit
+	relations  Relations
+	leadership leadership.LeadershipManager
 
 	deployer          *deployerProxy
 	operationFactory  operation.Factory
@@ -93,10 +95,18 @@ type Uniter struct {
 
 // NewUniter creates a new Uniter which will install, run, and upgrade
 // a charm on behalf of the unit with the given unitTag, by executing
-// hooks and operations provoked by changes in st.
-func NewUniter(st *uniter.State, unitTag names.UnitTag, dataDir string, hookLock *fslock.Lock) *Uniter {
+// hooks and operations provoked by changes in st. The unit's claim to
+// the leadership of its service is made through leadershipManager.
+func NewUniter(
+	st *uniter.State,
+	unitTag names.UnitTag,
+	leadershipManager leadership.LeadershipManager,
+	dataDir string,
+	hookLock *fslock.Lock,
+) *Uniter {
 	u := &Uniter{
 		st:               st,
+		leadership:       leadershipManager,
 		paths:            NewPaths(dataDir, unitTag),
 		hookLock:         hookLock,
 		collectMetricsAt: inactiveMetricsTimer,
@@ -119,7 +129,7 @@ func (u *Uniter) loop(unitTag names.UnitTag) (err error) {
 	logger.Infof("unit %q started", u.unit)
 
 	// Start filtering state change events for consumption by modes.
-	u.f, err = filter.NewFilter(u.st, unitTag)
+	u.f, err = filter.NewFilter(u.st, unitTag, u.leadership)
 	if err != nil {
 		return err
 	}
@@ -202,7 +212,7 @@ func (u *Uniter) init(unitTag names.UnitTag) (err error) {
 	}
 	u.deployer = &deployerProxy{deployer}
 	runnerFactory, err := runner.NewFactory(
-		u.st, unitTag, u.relations.GetInfo, u.paths,
+		u.st, unitTag, u.leadership, u.relations.GetInfo, u.paths,
 	)
 	if err != nil {
 		return err
@@ -350,6 +360,15 @@ func (u *Uniter) runAction(actionId string) (err error) {
 	return u.operationExecutor.Run(op)
 }
 
+// skipAction commits the action with the supplied id without running it.
+func (u *Uniter) skipAction(actionId string) (err error) {
+	op, err := u.operationFactory.NewAction(actionId)
+	if err != nil {
+		return err
+	}
+	return u.operationExecutor.Skip(op)
+}
+
 // runHook executes the supplied hook.Info in an appropriate hook context. If
 // the hook itself fails to execute, it returns errHookFailed.
 func (u *Uniter) runHook(hi hook.Info) (err error) {
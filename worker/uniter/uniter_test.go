@@ -9,6 +9,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"time"
 
 	jc "github.com/juju/testing/checkers"
 	ft "github.com/juju/testing/filetesting"
@@ -225,6 +226,99 @@ func (s *UniterSuite) TestUniterStartHook(c *gc.C) {
 	})
 }
 
+func (s *UniterSuite) TestUniterHookTimeout(c *gc.C) {
+	s.runUniterTests(c, []uniterTest{
+		ut(
+			"start hook times out and is retried",
+			createCharm{
+				customize: func(c *gc.C, ctx *context, path string) {
+					ctx.writeHangingHook(c, filepath.Join(path, "hooks", "start"))
+				},
+			},
+			serveCharm{},
+			ensureStateWorker{},
+			createServiceAndUnit{},
+			setHookTimeout{time.Second},
+			startUniter{},
+			waitAddresses{},
+			waitUnit{
+				status: params.StatusError,
+				info:   `hook timed out: "start"`,
+				data: map[string]interface{}{
+					"hook": "start",
+				},
+			},
+			waitHooks{"install", "config-changed", "fail-start"},
+			verifyWaiting{},
+
+			fixHook{"start"},
+			resolveError{state.ResolvedRetryHooks},
+			waitUnit{
+				status: params.StatusActive,
+			},
+			waitHooks{"start", "config-changed"},
+			verifyRunning{},
+		),
+	})
+}
+
+func (s *UniterSuite) TestUniterHookHistory(c *gc.C) {
+	s.runUniterTests(c, []uniterTest{
+		ut(
+			"hooks are recorded with their results",
+			startupError{"start"},
+			verifyHookHistory{
+				{"hook", "install", "ok"},
+				{"hook", "config-changed", "ok"},
+				{"hook", "start", "exit status 1"},
+			},
+			fixHook{"start"},
+			resolveError{state.ResolvedRetryHooks},
+			waitUnit{
+				status: params.StatusActive,
+			},
+			waitHooks{"start", "config-changed"},
+			verifyHookHistory{
+				{"hook", "install", "ok"},
+				{"hook", "config-changed", "ok"},
+				{"hook", "start", "exit status 1"},
+				{"hook", "start", "ok"},
+				{"hook", "config-changed", "ok"},
+			},
+		), ut(
+			"actions are recorded with their results",
+			createCharm{
+				customize: func(c *gc.C, ctx *context, path string) {
+					ctx.writeAction(c, path, "action-log-fail")
+					ctx.writeActionsYaml(c, path, "action-log-fail")
+				},
+			},
+			serveCharm{},
+			ensureStateWorker{},
+			createServiceAndUnit{},
+			startUniter{},
+			waitAddresses{},
+			waitUnit{status: params.StatusActive},
+			waitHooks{"install", "config-changed", "start"},
+			addAction{"action-log-fail", nil},
+			waitActionResults{[]actionResult{{
+				name: "action-log-fail",
+				results: map[string]interface{}{
+					"foo": "still works",
+				},
+				message: "I'm afraid I can't let you do that, Dave.",
+				status:  params.ActionFailed,
+			}}},
+			waitLatestHookRecord{"action", "action-log-fail", "I'm afraid I can't let you do that, Dave."},
+		), ut(
+			"juju run commands are recorded with their results",
+			quickStart{},
+			runCommands{"true"},
+			waitLatestHookRecord{"commands", "juju-run", "ok"},
+		),
+	})
+}
+
 func (s *UniterSuite) TestUniterMultipleErrors(c *gc.C) {
 	s.runUniterTests(c, []uniterTest{
 		ut(
@@ -426,6 +520,25 @@ func (s *UniterSuite) TestUniterSteadyStateUpgrade(c *gc.C) {
 			waitHooks{"upgrade-charm", "config-changed"},
 			verifyCharm{revision: 1},
 			verifyRunning{},
+		), ut(
+			"steady state rollback",
+			quickStart{},
+			createCharm{revision: 1},
+			upgradeCharm{revision: 1},
+			waitUnit{
+				status: params.StatusActive,
+				charm:  1,
+			},
+			waitHooks{"upgrade-charm", "config-changed"},
+			verifyCharm{revision: 1},
+
+			rollbackCharm{},
+			waitUnit{
+				status: params.StatusActive,
+			},
+			waitHooks{"upgrade-charm", "config-changed"},
+			verifyCharm{},
+			verifyRunning{},
 		), ut(
 			"steady state forced upgrade (identical behaviour)",
 			quickStart{},
@@ -1223,6 +1336,24 @@ func (s *UniterSuite) TestUniterMeterStatusChanged(c *gc.C) {
 	})
 }
 
+func (s *UniterSuite) TestUniterLeaderElected(c *gc.C) {
+	s.runUniterTests(c, []uniterTest{
+		ut(
+			"leader-elected hook runs once the unit has started",
+			createCharm{
+				customize: func(c *gc.C, ctx *context, path string) {
+					ctx.writeHook(c, filepath.Join(path, "hooks", "leader-elected"), true)
+				},
+			},
+			serveCharm{},
+			createUniter{},
+			waitUnit{status: params.StatusActive},
+			waitHooks{"install", "config-changed", "start", "leader-elected"},
+			verifyCharm{},
+		),
+	})
+}
+
 func (s *UniterSuite) TestUniterCollectMetrics(c *gc.C) {
 	s.runUniterTests(c, []uniterTest{
 		ut(
@@ -167,6 +167,11 @@ touch i_have_risen
 juju-reboot --now
 `[1:]
 
+var hangingHook = `
+#!/bin/bash --norc
+sleep 1000
+`[1:]
+
 func (ctx *context) writeExplicitHook(c *gc.C, path string, contents string) {
 	err := ioutil.WriteFile(path, []byte(contents), 0755)
 	c.Assert(err, jc.ErrorIsNil)
@@ -181,6 +186,10 @@ func (ctx *context) writeHook(c *gc.C, path string, good bool) {
 	ctx.writeExplicitHook(c, path, content)
 }
 
+func (ctx *context) writeHangingHook(c *gc.C, path string) {
+	ctx.writeExplicitHook(c, path, hangingHook)
+}
+
 func (ctx *context) writeActions(c *gc.C, path string, names []string) {
 	for _, name := range names {
 		ctx.writeAction(c, path, name)
@@ -475,10 +484,26 @@ func (s startUniter) step(c *gc.C, ctx *context) {
 	locksDir := filepath.Join(ctx.dataDir, "locks")
 	lock, err := fslock.NewLock(locksDir, "uniter-hook-execution")
 	c.Assert(err, jc.ErrorIsNil)
-	ctx.uniter = uniter.NewUniter(ctx.api, tag, ctx.dataDir, lock)
+	ctx.uniter = uniter.NewUniter(ctx.api, tag, leadershipStub{}, ctx.dataDir, lock)
 	uniter.SetUniterObserver(ctx.uniter, ctx)
 }
 
+// leadershipStub is a leadership.LeadershipManager under which every
+// unit is always the leader of its service.
+type leadershipStub struct{}
+
+func (leadershipStub) ClaimLeadership(serviceId, unitId string) (time.Duration, error) {
+	return time.Minute, nil
+}
+
+func (leadershipStub) ReleaseLeadership(serviceId, unitId string) error {
+	return nil
+}
+
+func (leadershipStub) BlockUntilLeadershipReleased(serviceId string) error {
+	return nil
+}
+
 type waitUniterDead struct {
 	err string
 }
@@ -837,6 +862,63 @@ func (s fixHook) step(c *gc.C, ctx *context) {
 	ctx.writeHook(c, path, true)
 }
 
+type hookResult struct {
+	kind   string
+	hook   string
+	result string
+}
+
+func (expect hookResult) check(c *gc.C, record state.HookRecord) {
+	c.Check(record.Kind, gc.Equals, expect.kind)
+	c.Check(record.Hook, gc.Equals, expect.hook)
+	c.Check(record.Result, gc.Equals, expect.result)
+}
+
+// verifyHookHistory checks the oldest records in the unit's hook history.
+type verifyHookHistory []hookResult
+
+func (s verifyHookHistory) step(c *gc.C, ctx *context) {
+	records, err := ctx.unit.HookHistory()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(len(records) >= len(s), jc.IsTrue)
+	for i, expect := range s {
+		expect.check(c, records[len(records)-1-i])
+	}
+}
+
+// waitLatestHookRecord waits for the most recent record in the unit's hook
+// history to match.
+type waitLatestHookRecord hookResult
+
+func (s waitLatestHookRecord) step(c *gc.C, ctx *context) {
+	timeout := time.After(worstCase)
+	for {
+		records, err := ctx.unit.HookHistory()
+		c.Assert(err, jc.ErrorIsNil)
+		if len(records) > 0 {
+			latest := hookResult{records[0].Kind, records[0].Hook, records[0].Result}
+			if latest == hookResult(s) {
+				return
+			}
+			c.Logf("latest hook record: %#v", latest)
+		}
+		select {
+		case <-time.After(coretesting.ShortWait):
+		case <-timeout:
+			c.Fatalf("never recorded %#v", hookResult(s))
+		}
+	}
+}
+
+type setHookTimeout struct {
+	timeout time.Duration
+}
+
+func (s setHookTimeout) step(c *gc.C, ctx *context) {
+	err := ctx.svc.SetHookTimeout(s.timeout)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 type changeMeterStatus struct {
 	code string
 	info string
@@ -886,6 +968,17 @@ func (s upgradeCharm) step(c *gc.C, ctx *context) {
 	serveCharm{}.step(c, ctx)
 }
 
+type rollbackCharm struct {
+	forced bool
+}
+
+func (s rollbackCharm) step(c *gc.C, ctx *context) {
+	err := ctx.svc.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	err = ctx.svc.RollbackCharm(s.forced)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 type verifyCharm struct {
 	revision          int
 	attemptedRevision int